@@ -16,13 +16,20 @@ import (
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/state"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/workers"
 	"github.com/metorial/metorial/mcp-engine/pkg/aws"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
+	"github.com/metorial/metorial/mcp-engine/pkg/tracing"
 	sentryUtil "github.com/metorial/metorial/modules/sentry-util"
 )
 
 func main() {
+	logging.InitLoggingIfNeeded("mcp-engine-manager")
+
 	sentryUtil.InitSentryIfNeeded()
 	defer sentryUtil.ShutdownSentry()
 
+	tracing.InitTracingIfNeeded("mcp-engine-manager")
+	defer tracing.ShutdownTracing()
+
 	managerAddress, workerBrokerAddress, stateConfig, dsn, standaloneWorkers := getConfig()
 
 	db, error := db.NewDB(dsn)
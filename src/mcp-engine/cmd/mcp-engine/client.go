@@ -0,0 +1,443 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
+	mcpPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/mcp"
+	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
+	"github.com/metorial/metorial/mcp-engine/pkg/recording"
+	"github.com/metorial/metorial/modules/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const clientHelp = `Commands:
+  tools                      List the server's tools
+  prompts                    List the server's prompts
+  resources                  List the server's resources
+  templates                  List the server's resource templates
+  call <tool> [json-args]    Call a tool, e.g. call search {"query": "mcp"}
+  prompt <name> [json-args]  Get a prompt
+  read <uri>                 Read a resource
+  request <method> [json]    Send an arbitrary request
+  notify <method> [json]     Send an arbitrary notification
+  help                       Show this help
+  quit                       Leave the session (the session is kept)
+`
+
+type interactiveClient struct {
+	cli       *cli
+	sessionId string
+	recorder  *recording.Writer
+
+	nextId int
+
+	printMutex sync.Mutex
+}
+
+// connectSession creates a session from a CreateSessionRequest stored as
+// YAML, initializes it and then reads commands from stdin.
+func connectSession(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("sessions connect")
+	recordPath := fs.String("record", "", "Record the exchange to this file so it can be replayed")
+	sessionId := fs.String("session-id", "", "ID of the session to create (default: generated)")
+	path, err := parseWithId(fs, args)
+	if err != nil {
+		return err
+	}
+
+	req := &managerPb.CreateSessionRequest{}
+	if err := loadYamlProto(path, req); err != nil {
+		return err
+	}
+	if *sessionId != "" {
+		req.SessionId = *sessionId
+	}
+	if req.SessionId == "" {
+		req.SessionId = util.Must(uuid.NewV7()).String()
+	}
+
+	ic := &interactiveClient{cli: c, sessionId: req.SessionId}
+
+	if *recordPath != "" {
+		file, err := os.Create(*recordPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		ic.recorder = recording.NewWriter(file)
+		if err := ic.recorder.WriteSession(req); err != nil {
+			return err
+		}
+	}
+
+	if _, err := c.client.CreateSession(ctx, req); err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	ic.printf("* session %s created\n", req.SessionId)
+
+	go ic.tail(ctx)
+
+	if err := ic.initialize(ctx); err != nil {
+		return err
+	}
+
+	return ic.repl(ctx, os.Stdin)
+}
+
+func (ic *interactiveClient) initialize(ctx context.Context) error {
+	client := &mcp.MCPClient{
+		Info:         mcp.ParticipantInfo{Name: "mcp-engine-cli", Version: "1.0.0"},
+		Capabilities: mcp.Capabilities{},
+	}
+
+	init, err := client.ToInitMessage("")
+	if err != nil {
+		return err
+	}
+
+	if _, err := ic.send(ctx, init); err != nil {
+		return fmt.Errorf("failed to initialize session: %w", err)
+	}
+
+	initialized, err := mcp.ParseMCPMessage(util.Must(uuid.NewV7()).String(), `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	if err != nil {
+		return err
+	}
+
+	_, err = ic.send(ctx, initialized)
+	return err
+}
+
+func (ic *interactiveClient) repl(ctx context.Context, in io.Reader) error {
+	ic.printf("%s", clientHelp)
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+
+		scanner := bufio.NewScanner(in)
+		scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	for {
+		ic.printf("> ")
+
+		var line string
+		select {
+		case <-ctx.Done():
+			return nil
+		case l, ok := <-lines:
+			if !ok {
+				return nil
+			}
+			line = strings.TrimSpace(l)
+		}
+
+		if line == "" {
+			continue
+		}
+
+		name, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+
+		var err error
+		switch name {
+		case "quit", "exit":
+			return nil
+		case "help":
+			ic.printf("%s", clientHelp)
+		case "tools":
+			err = ic.list(ctx, "tools/list", "tools", "name")
+		case "prompts":
+			err = ic.list(ctx, "prompts/list", "prompts", "name")
+		case "resources":
+			err = ic.list(ctx, "resources/list", "resources", "uri")
+		case "templates":
+			err = ic.list(ctx, "resources/templates/list", "resourceTemplates", "uriTemplate")
+		case "call":
+			err = ic.withNamedArgs(ctx, "tools/call", rest)
+		case "prompt":
+			err = ic.withNamedArgs(ctx, "prompts/get", rest)
+		case "read":
+			_, err = ic.request(ctx, "resources/read", map[string]any{"uri": rest})
+		case "request":
+			err = ic.raw(ctx, rest, true)
+		case "notify":
+			err = ic.raw(ctx, rest, false)
+		default:
+			err = fmt.Errorf("unknown command %q, type help for a list of commands", name)
+		}
+
+		if err != nil {
+			ic.printf("! %v\n", err)
+		}
+	}
+}
+
+// withNamedArgs sends a request whose params are a name and an optional
+// JSON object of arguments, as used by tools/call and prompts/get.
+func (ic *interactiveClient) withNamedArgs(ctx context.Context, method, input string) error {
+	name, argsJson, _ := strings.Cut(input, " ")
+	if name == "" {
+		return fmt.Errorf("%s requires a name", method)
+	}
+
+	arguments, err := parseJsonObject(argsJson)
+	if err != nil {
+		return err
+	}
+
+	_, err = ic.request(ctx, method, map[string]any{"name": name, "arguments": arguments})
+	return err
+}
+
+func (ic *interactiveClient) raw(ctx context.Context, input string, isRequest bool) error {
+	method, paramsJson, _ := strings.Cut(input, " ")
+	if method == "" {
+		return fmt.Errorf("a method is required")
+	}
+
+	params, err := parseJsonObject(paramsJson)
+	if err != nil {
+		return err
+	}
+
+	if isRequest {
+		_, err = ic.request(ctx, method, params)
+		return err
+	}
+
+	data, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "method": method, "params": params})
+	if err != nil {
+		return err
+	}
+
+	msg, err := mcp.ParseMCPMessageFromBytes(util.Must(uuid.NewV7()).String(), data)
+	if err != nil {
+		return err
+	}
+
+	_, err = ic.send(ctx, msg)
+	return err
+}
+
+// list requests a list method and prints the key field and description of
+// every returned item instead of the full response.
+func (ic *interactiveClient) list(ctx context.Context, method, field, key string) error {
+	responses, err := ic.requestQuiet(ctx, method, map[string]any{})
+	if err != nil {
+		return err
+	}
+
+	for _, res := range responses {
+		var payload struct {
+			Result map[string]json.RawMessage `json:"result"`
+		}
+		var items []map[string]any
+		err := json.Unmarshal([]byte(res.GetMcpMessage().GetMessage()), &payload)
+		if err == nil && payload.Result != nil {
+			err = json.Unmarshal(payload.Result[field], &items)
+		}
+		if err != nil || payload.Result == nil {
+			ic.printResponse(&managerPb.McpConnectionStreamResponse{
+				Response: &managerPb.McpConnectionStreamResponse_McpMessage{McpMessage: res},
+			})
+			continue
+		}
+
+		if len(items) == 0 {
+			ic.printf("< no %s\n", field)
+		}
+
+		for _, item := range items {
+			description, _ := item["description"].(string)
+			ic.printf("< %v  %s\n", item[key], truncate(description, 100))
+		}
+	}
+
+	return nil
+}
+
+func (ic *interactiveClient) request(ctx context.Context, method string, params map[string]any) ([]*mcpPb.McpMessage, error) {
+	msg, err := ic.newRequest(method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return ic.send(ctx, msg)
+}
+
+func (ic *interactiveClient) requestQuiet(ctx context.Context, method string, params map[string]any) ([]*mcpPb.McpMessage, error) {
+	msg, err := ic.newRequest(method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return ic.sendWithOptions(ctx, msg, true)
+}
+
+func (ic *interactiveClient) newRequest(method string, params map[string]any) (*mcp.MCPMessage, error) {
+	ic.nextId++
+	return mcp.NewMCPRequestMessage(fmt.Sprintf("cli/%d", ic.nextId), method, params)
+}
+
+func (ic *interactiveClient) send(ctx context.Context, msg *mcp.MCPMessage) ([]*mcpPb.McpMessage, error) {
+	return ic.sendWithOptions(ctx, msg, false)
+}
+
+// sendWithOptions sends a message and waits for the manager to close the
+// response stream, which happens once all responses have arrived. MCP
+// message responses are returned, everything else is printed as it comes.
+func (ic *interactiveClient) sendWithOptions(ctx context.Context, msg *mcp.MCPMessage, quiet bool) ([]*mcpPb.McpMessage, error) {
+	raw := msg.ToPbRawMessage()
+	ic.printf("> %s\n", raw.Message)
+
+	if ic.recorder != nil {
+		if err := ic.recorder.WriteClientMessage(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	stream, err := ic.cli.client.SendMcpMessage(ctx, &managerPb.SendMcpMessageRequest{
+		SessionId:        ic.sessionId,
+		McpMessages:      []*mcpPb.McpMessageRaw{raw},
+		IncludeResponses: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*mcpPb.McpMessage, 0)
+
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return messages, nil
+			}
+
+			return messages, err
+		}
+
+		ic.record(res)
+
+		if message := res.GetMcpMessage(); message != nil {
+			messages = append(messages, message)
+			if quiet {
+				continue
+			}
+		}
+
+		ic.printResponse(res)
+	}
+}
+
+// tail streams the session live to show output, errors and session events
+// that are not part of a response, for example server logs.
+func (ic *interactiveClient) tail(ctx context.Context) {
+	stream, err := ic.cli.client.StreamMcpMessages(ctx, &managerPb.StreamMcpMessagesRequest{
+		SessionId: ic.sessionId,
+		OnlyMessageTypes: []mcpPb.McpMessageType{
+			mcpPb.McpMessageType_request,
+			mcpPb.McpMessageType_notification,
+		},
+	})
+	if err != nil {
+		ic.printf("! failed to stream session: %v\n", err)
+		return
+	}
+
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			if !errors.Is(err, io.EOF) && status.Code(err) != codes.Canceled && ctx.Err() == nil {
+				ic.printf("! session stream closed: %v\n", err)
+			}
+			return
+		}
+
+		// Responses to our own requests are printed by send
+		if res.GetMcpMessage() == nil {
+			ic.record(res)
+		}
+
+		ic.printResponse(res)
+	}
+}
+
+func (ic *interactiveClient) record(res *managerPb.McpConnectionStreamResponse) {
+	if ic.recorder == nil {
+		return
+	}
+
+	if err := ic.recorder.WriteResponse(res); err != nil {
+		ic.printf("! failed to record response: %v\n", err)
+	}
+}
+
+func (ic *interactiveClient) printResponse(res *managerPb.McpConnectionStreamResponse) {
+	switch r := res.Response.(type) {
+	case *managerPb.McpConnectionStreamResponse_McpMessage:
+		ic.printf("< %s\n", prettyJson(r.McpMessage.GetMcpMessage().GetMessage()))
+	case *managerPb.McpConnectionStreamResponse_McpError:
+		ic.printf("! %s: %s\n", r.McpError.ErrorCode.String(), r.McpError.ErrorMessage)
+	case *managerPb.McpConnectionStreamResponse_McpOutput:
+		for _, line := range r.McpOutput.Lines {
+			ic.printf("# [%s] %s\n", r.McpOutput.OutputType.String(), line)
+		}
+	case *managerPb.McpConnectionStreamResponse_SessionEvent:
+		ic.printf("* %s\n", sessionEventString(r.SessionEvent))
+	}
+}
+
+func (ic *interactiveClient) printf(format string, args ...any) {
+	ic.printMutex.Lock()
+	defer ic.printMutex.Unlock()
+
+	fmt.Fprintf(ic.cli.out.w, format, args...)
+}
+
+func parseJsonObject(input string) (map[string]any, error) {
+	res := map[string]any{}
+
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return res, nil
+	}
+
+	if err := json.Unmarshal([]byte(input), &res); err != nil {
+		return nil, fmt.Errorf("arguments must be a JSON object: %w", err)
+	}
+
+	return res, nil
+}
+
+func prettyJson(input string) string {
+	var value any
+	if err := json.Unmarshal([]byte(input), &value); err != nil {
+		return input
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return input
+	}
+
+	return string(data)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+func listWorkers(ctx context.Context, c *cli, args []string) error {
+	if err := parseWithoutArgs(newFlagSet("workers list"), args); err != nil {
+		return err
+	}
+
+	res, err := c.client.ListWorkers(ctx, &managerPb.ListWorkersRequest{})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(res.Workers))
+	for _, worker := range res.Workers {
+		rows = append(rows, []string{
+			worker.WorkerId,
+			worker.Address,
+			formatBool(worker.Healthy),
+			formatBool(worker.AcceptingRuns),
+		})
+	}
+
+	return c.out.list(res, []string{"ID", "ADDRESS", "HEALTHY", "ACCEPTING RUNS"}, rows)
+}
+
+func listManagers(ctx context.Context, c *cli, args []string) error {
+	if err := parseWithoutArgs(newFlagSet("managers list"), args); err != nil {
+		return err
+	}
+
+	res, err := c.client.ListManagers(ctx, &managerPb.ListManagersRequest{})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(res.Managers))
+	for _, manager := range res.Managers {
+		rows = append(rows, []string{manager.Id, manager.Address})
+	}
+
+	return c.out.list(res, []string{"ID", "ADDRESS"}, rows)
+}
+
+func listServers(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("servers list")
+	pagination := addPaginationFlags(fs)
+	if err := parseWithoutArgs(fs, args); err != nil {
+		return err
+	}
+
+	page, err := pagination.pagination()
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.ListServers(ctx, &managerPb.ListServersRequest{Pagination: page})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(res.Servers))
+	for _, server := range res.Servers {
+		rows = append(rows, serverRow(server))
+	}
+
+	return c.out.list(res, serverHeaders, rows)
+}
+
+func getServer(ctx context.Context, c *cli, args []string) error {
+	id, err := parseWithId(newFlagSet("servers get"), args)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.GetServer(ctx, &managerPb.GetServerRequest{ServerId: id})
+	if err != nil {
+		return err
+	}
+
+	return c.out.detail(res.Server)
+}
+
+func discoverServer(ctx context.Context, c *cli, args []string) error {
+	path, err := parseWithId(newFlagSet("servers discover"), args)
+	if err != nil {
+		return err
+	}
+
+	config, err := loadServerConfig(path)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.DiscoverServer(ctx, &managerPb.DiscoverRequest{ServerConfig: config})
+	if err != nil {
+		return err
+	}
+
+	return c.out.detail(res.Server)
+}
+
+// loadServerConfig reads a ServerConfig from a YAML (or JSON) file. The
+// document uses the protobuf JSON field names, for example:
+//
+//	remote_run_config_with_server:
+//	  server:
+//	    server_uri: https://example.com/sse
+//	    protocol: sse
+func loadServerConfig(path string) (*managerPb.ServerConfig, error) {
+	config := &managerPb.ServerConfig{}
+	if err := loadYamlProto(path, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func loadYamlProto(path string, msg proto.Message) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	jsonData, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to convert %s to JSON: %w", path, err)
+	}
+
+	if err := protojson.Unmarshal(jsonData, msg); err != nil {
+		return fmt.Errorf("invalid config in %s: %w", path, err)
+	}
+
+	return nil
+}
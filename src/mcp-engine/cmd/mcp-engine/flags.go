@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
+)
+
+type paginationFlags struct {
+	limit    int
+	afterId  string
+	beforeId string
+	order    string
+	after    time.Duration
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet(name, flag.ContinueOnError)
+}
+
+func addPaginationFlags(fs *flag.FlagSet) *paginationFlags {
+	p := &paginationFlags{}
+
+	fs.IntVar(&p.limit, "limit", 50, "Maximum number of items to return")
+	fs.StringVar(&p.afterId, "after-id", "", "Only return items after this ID")
+	fs.StringVar(&p.beforeId, "before-id", "", "Only return items before this ID")
+	fs.StringVar(&p.order, "order", "desc", "Sort order, either asc or desc")
+	fs.DurationVar(&p.after, "after", 0, "Only return items created within this duration, e.g. 1h")
+
+	return p
+}
+
+func (p *paginationFlags) pagination() (*managerPb.ListPagination, error) {
+	res := &managerPb.ListPagination{
+		AfterId:  p.afterId,
+		BeforeId: p.beforeId,
+		Limit:    int32(p.limit),
+	}
+
+	switch p.order {
+	case "asc":
+		res.Order = managerPb.ListPaginationOrder_list_cursor_order_asc
+	case "desc":
+		res.Order = managerPb.ListPaginationOrder_list_cursor_order_desc
+	default:
+		return nil, fmt.Errorf("unsupported order %q, expected asc or desc", p.order)
+	}
+
+	return res, nil
+}
+
+// afterMs returns the -after filter as a unix timestamp in milliseconds,
+// or nil if the flag is not set.
+func (p *paginationFlags) afterMs() *int64 {
+	if p.after <= 0 {
+		return nil
+	}
+
+	after := time.Now().Add(-p.after).UnixMilli()
+	return &after
+}
+
+// parseWithId parses the flags of a command that takes a single ID as its
+// only positional argument. Flags may appear before or after the ID.
+func parseWithId(fs *flag.FlagSet, args []string) (string, error) {
+	var positional []string
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return "", err
+		}
+
+		if fs.NArg() == 0 {
+			break
+		}
+
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(positional) != 1 {
+		return "", fmt.Errorf("%s expects exactly one ID argument", fs.Name())
+	}
+
+	return positional[0], nil
+}
+
+func parseWithoutArgs(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 0 {
+		return fmt.Errorf("%s does not take positional arguments", fs.Name())
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
+	grpc_util "github.com/metorial/metorial/mcp-engine/pkg/grpcUtil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type command struct {
+	usage       string
+	description string
+	run         func(ctx context.Context, c *cli, args []string) error
+}
+
+type cli struct {
+	client managerPb.McpManagerClient
+	out    *output
+}
+
+var commands = map[string]command{
+	"sessions list":     {"[-external-id id] [pagination]", "List sessions", listSessions},
+	"sessions get":      {"<session-id>", "Show a session", getSession},
+	"sessions server":   {"<session-id>", "Show the server a session is connected to", getSessionServer},
+	"sessions active":   {"[-since 15m]", "List recently active session IDs", listActiveSessions},
+	"sessions runs":     {"<session-id> [pagination]", "List the runs of a session", listRuns},
+	"sessions errors":   {"<session-id> [pagination]", "List the errors of a session", listSessionErrors},
+	"sessions events":   {"<session-id> [pagination]", "List the events of a session", listSessionEvents},
+	"sessions messages": {"<session-id> [pagination]", "List the messages of a session", listSessionMessages},
+	"sessions tail":     {"<session-id> [-types request,response] [-ids id,...] [-replay-after uuid]", "Stream MCP messages of a session live", tailSession},
+	"sessions discard":  {"<session-id>", "Discard a session and stop its runs", discardSession},
+	"sessions connect":  {"<session.yaml> [-record file] [-session-id id]", "Create a session from a CreateSessionRequest and use it interactively", connectSession},
+	"sessions replay":   {"<session-id> -config config.yaml [-image-tag tag] [-ignore path,...] [-keep]", "Replay a recorded session against a new session and diff the responses", replaySession},
+
+	"runs get":      {"<run-id>", "Show a run", getRun},
+	"runs active":   {"[-since 15m]", "List recently active run IDs", listActiveRuns},
+	"runs errors":   {"<run-id> [pagination]", "List the errors of a run", listRunErrors},
+	"runs events":   {"<run-id> [pagination]", "List the events of a run", listRunEvents},
+	"runs messages": {"<run-id> [pagination]", "List the messages of a run", listRunMessages},
+
+	"errors get":   {"<error-id>", "Show an error", getError},
+	"events get":   {"<event-id>", "Show an event", getEvent},
+	"messages get": {"<message-id>", "Show a message", getMessage},
+
+	"recordings replay": {"<recording.jsonl> [-image-tag tag] [-ignore path,...] [-keep]", "Replay a recording made with sessions connect -record", replayRecording},
+
+	"servers list":     {"[pagination]", "List servers", listServers},
+	"servers get":      {"<server-id>", "Show a server", getServer},
+	"servers discover": {"<config.yaml>", "Discover a server from a YAML server config", discoverServer},
+
+	"workers list":  {"", "List the workers known to the manager", listWorkers},
+	"managers list": {"", "List the managers in the cluster", listManagers},
+}
+
+func main() {
+	flag.Usage = printUsage
+
+	addressArg := flag.String("address", "", "Address of the MCP manager (default $MCP_ENGINE_MANAGER_ADDRESS or localhost:50050)")
+	outputArg := flag.String("output", "table", "Output format, either table or json")
+	flag.Parse()
+
+	address := *addressArg
+	if address == "" {
+		address = os.Getenv("MCP_ENGINE_MANAGER_ADDRESS")
+	}
+	if address == "" {
+		address = "localhost:50050"
+	}
+
+	format, err := parseFormat(*outputArg)
+	if err != nil {
+		fail(err)
+	}
+
+	args := flag.Args()
+	if len(args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[args[0]+" "+args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command: %s %s\n\n", args[0], args[1])
+		printUsage()
+		os.Exit(2)
+	}
+
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc_util.WithTracing())
+	if err != nil {
+		fail(fmt.Errorf("failed to connect to manager at %s: %w", address, err))
+	}
+	defer conn.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	c := &cli{
+		client: managerPb.NewMcpManagerClient(conn),
+		out:    newOutput(os.Stdout, format),
+	}
+
+	if err := cmd.run(ctx, c, args[2:]); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "error: %v\n", err)
+	os.Exit(1)
+}
+
+func printUsage() {
+	w := flag.CommandLine.Output()
+
+	fmt.Fprintf(w, "Usage: mcp-engine [-address host:port] [-output table|json] <resource> <action> [args]\n\n")
+	fmt.Fprintf(w, "Commands:\n")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		cmd := commands[name]
+		fmt.Fprintf(w, "  %-20s %s\n", name, strings.TrimSpace(cmd.usage))
+		fmt.Fprintf(w, "  %-20s   %s\n", "", cmd.description)
+	}
+
+	fmt.Fprintf(w, "\nPagination flags: -limit n, -after-id id, -before-id id, -order asc|desc, -after duration\n\n")
+	fmt.Fprintf(w, "Global flags:\n")
+	flag.PrintDefaults()
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type format string
+
+const (
+	formatTable format = "table"
+	formatJson  format = "json"
+)
+
+func parseFormat(value string) (format, error) {
+	switch format(value) {
+	case formatTable, formatJson:
+		return format(value), nil
+	}
+
+	return "", fmt.Errorf("unsupported output format %q, expected table or json", value)
+}
+
+type output struct {
+	w      io.Writer
+	format format
+}
+
+func newOutput(w io.Writer, format format) *output {
+	return &output{w: w, format: format}
+}
+
+// list prints a list response. In table mode only the given columns are
+// shown, in JSON mode the full response message is written.
+func (o *output) list(msg proto.Message, headers []string, rows [][]string) error {
+	if o.format == formatJson {
+		return o.json(msg)
+	}
+
+	tw := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// detail prints a single message. In table mode each populated top-level
+// field is written on its own line, nested messages are rendered as JSON.
+func (o *output) detail(msg proto.Message) error {
+	if o.format == formatJson {
+		return o.json(msg)
+	}
+
+	tw := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+
+	var err error
+	msg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		var value string
+		value, err = fieldString(fd, v)
+		if err != nil {
+			return false
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\n", fd.Name(), value)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Flush()
+}
+
+func (o *output) json(msg proto.Message) error {
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(o.w, string(data))
+	return err
+}
+
+// jsonLine writes a message as a single line of JSON, used for streams.
+func (o *output) jsonLine(msg proto.Message) error {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(o.w, string(data))
+	return err
+}
+
+func fieldString(fd protoreflect.FieldDescriptor, v protoreflect.Value) (string, error) {
+	switch {
+	case fd.IsList() || fd.IsMap() || fd.Kind() == protoreflect.MessageKind:
+		return compositeString(fd, v)
+	case fd.Kind() == protoreflect.EnumKind:
+		return enumString(fd, v.Enum()), nil
+	case fd.Kind() == protoreflect.Int64Kind && strings.HasSuffix(string(fd.Name()), "_at"):
+		return formatTime(v.Int()), nil
+	default:
+		return v.String(), nil
+	}
+}
+
+func compositeString(fd protoreflect.FieldDescriptor, v protoreflect.Value) (string, error) {
+	switch {
+	case fd.IsList():
+		list := v.List()
+		items := make([]string, 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			item, err := singularString(fd, list.Get(i))
+			if err != nil {
+				return "", err
+			}
+			items = append(items, item)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+
+	case fd.IsMap():
+		items := []string{}
+		var err error
+		v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+			var item string
+			item, err = singularString(fd.MapValue(), mv)
+			items = append(items, k.String()+"="+item)
+			return err == nil
+		})
+		return strings.Join(items, ", "), err
+
+	default:
+		return singularString(fd, v)
+	}
+}
+
+func singularString(fd protoreflect.FieldDescriptor, v protoreflect.Value) (string, error) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind:
+		data, err := protojson.Marshal(v.Message().Interface())
+		return string(data), err
+	case protoreflect.EnumKind:
+		return enumString(fd, v.Enum()), nil
+	default:
+		return v.String(), nil
+	}
+}
+
+func enumString(fd protoreflect.FieldDescriptor, n protoreflect.EnumNumber) string {
+	value := fd.Enum().Values().ByNumber(n)
+	if value == nil {
+		return fmt.Sprint(n)
+	}
+
+	return string(value.Name())
+}
+
+// trimEnum strips the common prefix of the engine's enum names, for
+// example session_status_active becomes active.
+func trimEnum(value fmt.Stringer, prefix string) string {
+	return strings.TrimPrefix(value.String(), prefix)
+}
+
+func formatTime(ms int64) string {
+	if ms == 0 {
+		return "-"
+	}
+
+	return time.UnixMilli(ms).UTC().Format(time.RFC3339)
+}
+
+func formatBool(value bool) string {
+	if value {
+		return "yes"
+	}
+
+	return "no"
+}
+
+func formatInt(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
+
+func truncate(value string, max int) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if len(value) <= max {
+		return value
+	}
+
+	return value[:max-3] + "..."
+}
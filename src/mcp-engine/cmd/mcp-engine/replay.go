@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
+	mcpPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/mcp"
+	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
+	"github.com/metorial/metorial/mcp-engine/pkg/recording"
+	"github.com/metorial/metorial/mcp-engine/pkg/replay"
+	"github.com/metorial/metorial/modules/util"
+	"google.golang.org/protobuf/proto"
+)
+
+type replayFlags struct {
+	imageTag string
+	ignore   string
+	keep     bool
+}
+
+func addReplayFlags(fs interface {
+	StringVar(p *string, name, value, usage string)
+	BoolVar(p *bool, name string, value bool, usage string)
+}) *replayFlags {
+	f := &replayFlags{}
+
+	fs.StringVar(&f.imageTag, "image-tag", "", "Replace the tag of the container image, e.g. to test an upgrade")
+	fs.StringVar(&f.ignore, "ignore", "", "Comma separated JSON paths to exclude from the diff, e.g. result.content[*].text")
+	fs.BoolVar(&f.keep, "keep", false, "Keep the replay session instead of discarding it")
+
+	return f
+}
+
+// replaySession replays a session stored by the manager through the
+// ReplaySession RPC.
+func replaySession(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("sessions replay")
+	configPath := fs.String("config", "", "YAML file with the SessionConfig to run the replay with")
+	flags := addReplayFlags(fs)
+	id, err := parseWithId(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if *configPath == "" {
+		return fmt.Errorf("sessions replay requires -config")
+	}
+
+	config := &managerPb.SessionConfig{}
+	if err := loadYamlProto(*configPath, config); err != nil {
+		return err
+	}
+
+	req := &managerPb.ReplaySessionRequest{
+		SessionId:   id,
+		Config:      config,
+		IgnorePaths: splitList(flags.ignore),
+		KeepSession: flags.keep,
+	}
+	if flags.imageTag != "" {
+		req.DockerImageTag = &flags.imageTag
+	}
+
+	res, err := c.client.ReplaySession(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return printReplay(c.out, res)
+}
+
+// replayRecording replays a recording made with `sessions connect -record`
+// against a new session, driven from the CLI.
+func replayRecording(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("recordings replay")
+	flags := addReplayFlags(fs)
+	path, err := parseWithId(fs, args)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	rec, err := recording.Read(file)
+	if err != nil {
+		return fmt.Errorf("failed to read recording %s: %w", path, err)
+	}
+
+	messages, err := replay.FromRecording(rec)
+	if err != nil {
+		return err
+	}
+
+	createReq := proto.Clone(rec.Session).(*managerPb.CreateSessionRequest)
+	createReq.SessionId = util.Must(uuid.NewV7()).String()
+
+	if flags.imageTag != "" {
+		if err := replay.ApplyImageTag(createReq.GetConfig().GetServerConfig(), flags.imageTag); err != nil {
+			return err
+		}
+	}
+
+	if _, err := c.client.CreateSession(ctx, createReq); err != nil {
+		return fmt.Errorf("failed to create replay session: %w", err)
+	}
+
+	if !flags.keep {
+		defer c.client.DiscardSession(context.Background(), &managerPb.DiscardSessionRequest{SessionId: createReq.SessionId})
+	}
+
+	ignorePaths := append(append([]string{}, replay.DefaultIgnorePaths...), splitList(flags.ignore)...)
+
+	target := &clientReplayTarget{client: c.client, sessionId: createReq.SessionId}
+	res := replay.Run(ctx, target, replay.BuildExchanges(messages), replay.NewIgnoreRules(ignorePaths...))
+	res.ReplaySessionId = createReq.SessionId
+
+	return printReplay(c.out, res)
+}
+
+type clientReplayTarget struct {
+	client    managerPb.McpManagerClient
+	sessionId string
+}
+
+func (t *clientReplayTarget) Send(ctx context.Context, message *mcpPb.McpMessageRaw) ([]*mcpPb.McpMessage, error) {
+	stream, err := t.client.SendMcpMessage(ctx, &managerPb.SendMcpMessageRequest{
+		SessionId:        t.sessionId,
+		McpMessages:      []*mcpPb.McpMessageRaw{message},
+		IncludeResponses: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*mcpPb.McpMessage, 0)
+	var mcpErr *mcpPb.McpError
+
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if message := res.GetMcpMessage(); message != nil {
+			messages = append(messages, message)
+		}
+		if e := res.GetMcpError(); e != nil {
+			mcpErr = e
+		}
+	}
+
+	if len(messages) == 0 && mcpErr != nil {
+		return nil, fmt.Errorf("%s: %s", mcpErr.ErrorCode.String(), mcpErr.ErrorMessage)
+	}
+
+	return messages, nil
+}
+
+func printReplay(out *output, res *managerPb.ReplaySessionResponse) error {
+	if out.format == formatJson {
+		return out.json(res)
+	}
+
+	rows := make([][]string, 0, len(res.Exchanges))
+	for i, exchange := range res.Exchanges {
+		method := "-"
+		if msg, err := mcp.FromPbRawMessage(exchange.Request); err == nil && msg.Method != nil {
+			method = *msg.Method
+		}
+
+		result := "match"
+		switch {
+		case exchange.Error != "":
+			result = "error: " + truncate(exchange.Error, 60)
+		case len(exchange.Differences) > 0:
+			result = "mismatch"
+		}
+
+		rows = append(rows, []string{formatInt(int64(i)), method, formatInt(int64(len(exchange.Expected))), formatInt(int64(len(exchange.Actual))), result})
+	}
+
+	if err := out.list(res, []string{"#", "METHOD", "EXPECTED", "ACTUAL", "RESULT"}, rows); err != nil {
+		return err
+	}
+
+	for i, exchange := range res.Exchanges {
+		for _, diff := range exchange.Differences {
+			fmt.Fprintf(out.w, "\n#%d %s\n  - %s\n  + %s\n", i, diff.Path, orDash(diff.ExpectedJson), orDash(diff.ActualJson))
+		}
+	}
+
+	fmt.Fprintf(out.w, "\nreplay session %s: %d matched, %d mismatched\n", res.ReplaySessionId, res.Matched, res.Mismatched)
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"strings"
+
+	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
+)
+
+var (
+	sessionHeaders = []string{"ID", "EXTERNAL ID", "TYPE", "STATUS", "ERROR", "SERVER", "CREATED", "LAST PING"}
+	runHeaders     = []string{"ID", "TYPE", "STATUS", "ERROR", "WORKER", "STARTED", "ENDED", "LAST PING"}
+	errorHeaders   = []string{"ID", "RUN", "CODE", "MESSAGE", "CREATED"}
+	eventHeaders   = []string{"ID", "RUN", "TYPE", "OUTPUT", "CREATED"}
+	messageHeaders = []string{"ID", "INDEX", "RUN", "SENDER", "TYPE", "METHOD", "MCP ID", "CREATED"}
+	serverHeaders  = []string{"ID", "IDENTIFIER", "TYPE", "STATUS", "TOOLS", "PROMPTS", "RESOURCES", "LAST DISCOVERY"}
+)
+
+func sessionRow(session *managerPb.EngineSession) []string {
+	serverId := "-"
+	if session.Server != nil {
+		serverId = session.Server.Id
+	}
+
+	return []string{
+		session.Id,
+		orDash(session.ExternalId),
+		trimEnum(session.Type, "session_type_"),
+		trimEnum(session.Status, "session_status_"),
+		formatBool(session.HasError),
+		serverId,
+		formatTime(session.CreatedAt),
+		formatTime(session.LastPingAt),
+	}
+}
+
+func runRow(run *managerPb.EngineSessionRun) []string {
+	return []string{
+		run.Id,
+		trimEnum(run.Type, "run_type_"),
+		trimEnum(run.Status, "run_status_"),
+		formatBool(run.HasError),
+		orDash(run.WorkerId),
+		formatTime(run.StartedAt),
+		formatTime(run.EndedAt),
+		formatTime(run.LastPingAt),
+	}
+}
+
+func errorRows(errors []*managerPb.EngineSessionError) [][]string {
+	rows := make([][]string, 0, len(errors))
+	for _, e := range errors {
+		rows = append(rows, []string{
+			e.Id,
+			orDash(e.RunId),
+			e.ErrorCode,
+			truncate(e.ErrorMessage, 80),
+			formatTime(e.CreatedAt),
+		})
+	}
+
+	return rows
+}
+
+func eventRows(events []*managerPb.EngineSessionEvent) [][]string {
+	rows := make([][]string, 0, len(events))
+	for _, event := range events {
+		output := "-"
+		if event.McpOutput != nil {
+			output = truncate(strings.Join(event.McpOutput.Lines, " | "), 80)
+		}
+
+		rows = append(rows, []string{
+			event.Id,
+			orDash(event.RunId),
+			trimEnum(event.Type, "session_event_type_"),
+			output,
+			formatTime(event.CreatedAt),
+		})
+	}
+
+	return rows
+}
+
+func messageRows(messages []*managerPb.EngineSessionMessage) [][]string {
+	rows := make([][]string, 0, len(messages))
+	for _, message := range messages {
+		messageType, method, mcpId := "-", "-", "-"
+		if message.McpMessage != nil {
+			messageType = message.McpMessage.MessageType.String()
+			method = orDash(message.McpMessage.Method)
+			mcpId = orDash(message.McpMessage.IdJson)
+		}
+
+		rows = append(rows, []string{
+			message.Id,
+			formatInt(int64(message.Index)),
+			orDash(message.RunId),
+			trimEnum(message.Sender, "session_message_sender_"),
+			messageType,
+			method,
+			mcpId,
+			formatTime(message.CreatedAt),
+		})
+	}
+
+	return rows
+}
+
+func serverRow(server *managerPb.EngineServer) []string {
+	lastDiscovery := "-"
+	if server.LastDiscoveryAt != nil {
+		lastDiscovery = formatTime(*server.LastDiscoveryAt)
+	}
+
+	return []string{
+		server.Id,
+		server.Identifier,
+		trimEnum(server.Type, "session_type_"),
+		trimEnum(server.Status, "session_status_"),
+		formatInt(int64(len(server.Tools))),
+		formatInt(int64(len(server.Prompts))),
+		formatInt(int64(len(server.Resources))),
+		lastDiscovery,
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
+)
+
+func getRun(ctx context.Context, c *cli, args []string) error {
+	id, err := parseWithId(newFlagSet("runs get"), args)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.GetRun(ctx, &managerPb.GetRunRequest{RunId: id})
+	if err != nil {
+		return err
+	}
+
+	return c.out.detail(res.Run)
+}
+
+func listActiveRuns(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("runs active")
+	since := fs.Duration("since", 15*time.Minute, "How far back to look for activity")
+	if err := parseWithoutArgs(fs, args); err != nil {
+		return err
+	}
+
+	res, err := c.client.ListRecentlyActiveRuns(ctx, &managerPb.ListRecentlyActiveRunsRequest{
+		Since: time.Now().Add(-*since).UnixMilli(),
+	})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(res.RunIds))
+	for _, id := range res.RunIds {
+		rows = append(rows, []string{id})
+	}
+
+	return c.out.list(res, []string{"RUN ID"}, rows)
+}
+
+func listRunErrors(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("runs errors")
+	pagination := addPaginationFlags(fs)
+	id, err := parseWithId(fs, args)
+	if err != nil {
+		return err
+	}
+
+	page, err := pagination.pagination()
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.ListRunErrors(ctx, &managerPb.ListRunErrorsRequest{
+		RunId:      id,
+		Pagination: page,
+		After:      pagination.afterMs(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.out.list(res, errorHeaders, errorRows(res.Errors))
+}
+
+func listRunEvents(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("runs events")
+	pagination := addPaginationFlags(fs)
+	id, err := parseWithId(fs, args)
+	if err != nil {
+		return err
+	}
+
+	page, err := pagination.pagination()
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.ListRunEvents(ctx, &managerPb.ListRunEventsRequest{
+		RunId:      id,
+		Pagination: page,
+		After:      pagination.afterMs(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.out.list(res, eventHeaders, eventRows(res.Events))
+}
+
+func listRunMessages(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("runs messages")
+	pagination := addPaginationFlags(fs)
+	id, err := parseWithId(fs, args)
+	if err != nil {
+		return err
+	}
+
+	page, err := pagination.pagination()
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.ListRunMessages(ctx, &managerPb.ListRunMessagesRequest{
+		RunId:      id,
+		Pagination: page,
+		After:      pagination.afterMs(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.out.list(res, messageHeaders, messageRows(res.Messages))
+}
+
+func getError(ctx context.Context, c *cli, args []string) error {
+	id, err := parseWithId(newFlagSet("errors get"), args)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.GetError(ctx, &managerPb.GetErrorRequest{ErrorId: id})
+	if err != nil {
+		return err
+	}
+
+	return c.out.detail(res.Error)
+}
+
+func getEvent(ctx context.Context, c *cli, args []string) error {
+	id, err := parseWithId(newFlagSet("events get"), args)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.GetEvent(ctx, &managerPb.GetEventRequest{EventId: id})
+	if err != nil {
+		return err
+	}
+
+	return c.out.detail(res.Event)
+}
+
+func getMessage(ctx context.Context, c *cli, args []string) error {
+	id, err := parseWithId(newFlagSet("messages get"), args)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.GetMessage(ctx, &managerPb.GetMessageRequest{MessageId: id})
+	if err != nil {
+		return err
+	}
+
+	return c.out.detail(res.Message)
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
+)
+
+func listSessions(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("sessions list")
+	externalId := fs.String("external-id", "", "Only list sessions with this external ID")
+	pagination := addPaginationFlags(fs)
+	if err := parseWithoutArgs(fs, args); err != nil {
+		return err
+	}
+
+	page, err := pagination.pagination()
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.ListSessions(ctx, &managerPb.ListSessionsRequest{
+		ExternalId: *externalId,
+		Pagination: page,
+	})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(res.Sessions))
+	for _, session := range res.Sessions {
+		rows = append(rows, sessionRow(session))
+	}
+
+	return c.out.list(res, sessionHeaders, rows)
+}
+
+func getSession(ctx context.Context, c *cli, args []string) error {
+	id, err := parseWithId(newFlagSet("sessions get"), args)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.GetSession(ctx, &managerPb.GetSessionRequest{SessionId: id})
+	if err != nil {
+		return err
+	}
+
+	return c.out.detail(res.Session)
+}
+
+func getSessionServer(ctx context.Context, c *cli, args []string) error {
+	id, err := parseWithId(newFlagSet("sessions server"), args)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.GetSessionServer(ctx, &managerPb.GetSessionRequest{SessionId: id})
+	if err != nil {
+		return err
+	}
+
+	return c.out.detail(res.Server)
+}
+
+func listActiveSessions(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("sessions active")
+	since := fs.Duration("since", 15*time.Minute, "How far back to look for activity")
+	if err := parseWithoutArgs(fs, args); err != nil {
+		return err
+	}
+
+	res, err := c.client.ListRecentlyActiveSessions(ctx, &managerPb.ListRecentlyActiveSessionsRequest{
+		Since: time.Now().Add(-*since).UnixMilli(),
+	})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(res.SessionIds))
+	for _, id := range res.SessionIds {
+		rows = append(rows, []string{id})
+	}
+
+	return c.out.list(res, []string{"SESSION ID"}, rows)
+}
+
+func listRuns(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("sessions runs")
+	pagination := addPaginationFlags(fs)
+	id, err := parseWithId(fs, args)
+	if err != nil {
+		return err
+	}
+
+	page, err := pagination.pagination()
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.ListRuns(ctx, &managerPb.ListRunsRequest{
+		SessionId:  id,
+		Pagination: page,
+		After:      pagination.afterMs(),
+	})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(res.Runs))
+	for _, run := range res.Runs {
+		rows = append(rows, runRow(run))
+	}
+
+	return c.out.list(res, runHeaders, rows)
+}
+
+func listSessionErrors(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("sessions errors")
+	pagination := addPaginationFlags(fs)
+	id, err := parseWithId(fs, args)
+	if err != nil {
+		return err
+	}
+
+	page, err := pagination.pagination()
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.ListSessionErrors(ctx, &managerPb.ListSessionErrorsRequest{
+		SessionId:  id,
+		Pagination: page,
+		After:      pagination.afterMs(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.out.list(res, errorHeaders, errorRows(res.Errors))
+}
+
+func listSessionEvents(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("sessions events")
+	pagination := addPaginationFlags(fs)
+	id, err := parseWithId(fs, args)
+	if err != nil {
+		return err
+	}
+
+	page, err := pagination.pagination()
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.ListSessionEvents(ctx, &managerPb.ListSessionEventsRequest{
+		SessionId:  id,
+		Pagination: page,
+		After:      pagination.afterMs(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.out.list(res, eventHeaders, eventRows(res.Events))
+}
+
+func listSessionMessages(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("sessions messages")
+	pagination := addPaginationFlags(fs)
+	id, err := parseWithId(fs, args)
+	if err != nil {
+		return err
+	}
+
+	page, err := pagination.pagination()
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.ListSessionMessages(ctx, &managerPb.ListSessionMessagesRequest{
+		SessionId:  id,
+		Pagination: page,
+		After:      pagination.afterMs(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.out.list(res, messageHeaders, messageRows(res.Messages))
+}
+
+func discardSession(ctx context.Context, c *cli, args []string) error {
+	id, err := parseWithId(newFlagSet("sessions discard"), args)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.DiscardSession(ctx, &managerPb.DiscardSessionRequest{SessionId: id})
+	if err != nil {
+		return err
+	}
+
+	return c.out.list(res, []string{"DISCARDED"}, [][]string{{id}})
+}
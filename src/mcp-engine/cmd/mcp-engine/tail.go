@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
+	mcpPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/mcp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func tailSession(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("sessions tail")
+	typesArg := fs.String("types", "", "Comma separated message types to stream (request, response, notification, error)")
+	idsArg := fs.String("ids", "", "Comma separated MCP message IDs to stream")
+	replayAfter := fs.String("replay-after", "", "Replay stored messages after this message UUID before streaming")
+	id, err := parseWithId(fs, args)
+	if err != nil {
+		return err
+	}
+
+	req := &managerPb.StreamMcpMessagesRequest{SessionId: id}
+
+	for _, name := range splitList(*typesArg) {
+		value, ok := mcpPb.McpMessageType_value[name]
+		if !ok {
+			return fmt.Errorf("unknown message type %q", name)
+		}
+		req.OnlyMessageTypes = append(req.OnlyMessageTypes, mcpPb.McpMessageType(value))
+	}
+
+	req.OnlyIds = splitList(*idsArg)
+
+	if *replayAfter != "" {
+		req.ReplayAfterUuid = replayAfter
+	}
+
+	stream, err := c.client.StreamMcpMessages(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) || status.Code(err) == codes.Canceled || ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		if c.out.format == formatJson {
+			if err := c.out.jsonLine(res); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fmt.Fprintln(c.out.w, streamLine(res))
+	}
+}
+
+// streamLine renders a stream response as a single human readable line.
+func streamLine(res *managerPb.McpConnectionStreamResponse) string {
+	prefix := time.Now().UTC().Format(time.TimeOnly)
+	if res.IsReplay {
+		prefix += " replay"
+	}
+
+	switch r := res.Response.(type) {
+	case *managerPb.McpConnectionStreamResponse_McpMessage:
+		msg := r.McpMessage
+		details := msg.MessageType.String()
+		if msg.Method != "" {
+			details += " " + msg.Method
+		}
+		if msg.IdJson != "" {
+			details += " id=" + msg.IdJson
+		}
+
+		body := ""
+		if msg.McpMessage != nil {
+			body = truncate(msg.McpMessage.Message, 200)
+		}
+
+		return fmt.Sprintf("%s message %s %s", prefix, details, body)
+
+	case *managerPb.McpConnectionStreamResponse_McpError:
+		return fmt.Sprintf("%s error %s %s", prefix, r.McpError.ErrorCode.String(), r.McpError.ErrorMessage)
+
+	case *managerPb.McpConnectionStreamResponse_McpOutput:
+		return fmt.Sprintf("%s output %s %s", prefix, r.McpOutput.OutputType.String(), strings.Join(r.McpOutput.Lines, "\n"))
+
+	case *managerPb.McpConnectionStreamResponse_SessionEvent:
+		return fmt.Sprintf("%s event %s", prefix, sessionEventString(r.SessionEvent))
+	}
+
+	return fmt.Sprintf("%s unknown %T", prefix, res.Response)
+}
+
+func sessionEventString(event *managerPb.SessionEvent) string {
+	switch e := event.Event.(type) {
+	case *managerPb.SessionEvent_StartRun:
+		return "start_run " + e.StartRun.GetRun().GetId()
+	case *managerPb.SessionEvent_StopRun:
+		return "stop_run " + e.StopRun.GetRun().GetId()
+	case *managerPb.SessionEvent_InfoRun:
+		return "info_run " + e.InfoRun.GetRun().GetId()
+	case *managerPb.SessionEvent_InfoSession:
+		return "info_session " + e.InfoSession.GetSession().GetId()
+	}
+
+	return fmt.Sprintf("%T", event.Event)
+}
+
+func splitList(value string) []string {
+	var res []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+
+	return res
+}
@@ -20,13 +20,20 @@ import (
 	workerMcpRemote "github.com/metorial/metorial/mcp-engine/internal/services/worker-mcp-remote"
 	workerMcpRunner "github.com/metorial/metorial/mcp-engine/internal/services/worker-mcp-runner"
 	"github.com/metorial/metorial/mcp-engine/pkg/docker"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
+	"github.com/metorial/metorial/mcp-engine/pkg/tracing"
 	sentryUtil "github.com/metorial/metorial/modules/sentry-util"
 )
 
 func main() {
+	logging.InitLoggingIfNeeded("mcp-engine-unified")
+
 	sentryUtil.InitSentryIfNeeded()
 	defer sentryUtil.ShutdownSentry()
 
+	tracing.InitTracingIfNeeded("mcp-engine-unified")
+	defer tracing.ShutdownTracing()
+
 	err := godotenv.Load()
 	if err != nil {
 		// ignore error if .env file is not found
@@ -12,14 +12,21 @@ import (
 	"github.com/metorial/metorial/mcp-engine/internal/services/worker"
 	workerMcpRemote "github.com/metorial/metorial/mcp-engine/internal/services/worker-mcp-remote"
 	"github.com/metorial/metorial/mcp-engine/pkg/aws"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
+	"github.com/metorial/metorial/mcp-engine/pkg/tracing"
 	"github.com/metorial/metorial/modules/addr"
 	sentryUtil "github.com/metorial/metorial/modules/sentry-util"
 )
 
 func main() {
+	logging.InitLoggingIfNeeded("mcp-engine-worker-mcp-remote")
+
 	sentryUtil.InitSentryIfNeeded()
 	defer sentryUtil.ShutdownSentry()
 
+	tracing.InitTracingIfNeeded("mcp-engine-worker-mcp-remote")
+	defer tracing.ShutdownTracing()
+
 	ownAddress, port, managerAddress := getConfig()
 
 	remote := workerMcpRemote.NewRemote()
@@ -14,14 +14,21 @@ import (
 	workerMcpRunner "github.com/metorial/metorial/mcp-engine/internal/services/worker-mcp-runner"
 	"github.com/metorial/metorial/mcp-engine/pkg/aws"
 	"github.com/metorial/metorial/mcp-engine/pkg/docker"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
+	"github.com/metorial/metorial/mcp-engine/pkg/tracing"
 	"github.com/metorial/metorial/modules/addr"
 	sentryUtil "github.com/metorial/metorial/modules/sentry-util"
 )
 
 func main() {
+	logging.InitLoggingIfNeeded("mcp-engine-worker-mcp-runner")
+
 	sentryUtil.InitSentryIfNeeded()
 	defer sentryUtil.ShutdownSentry()
 
+	tracing.InitTracingIfNeeded("mcp-engine-worker-mcp-runner")
+	defer tracing.ShutdownTracing()
+
 	ownAddress, port, managerAddress := getConfig()
 
 	config := docker.ImageManagerCreateOptions{}
@@ -24,16 +24,19 @@ const (
 type LauncherConfig_LauncherType int32
 
 const (
-	LauncherConfig_deno LauncherConfig_LauncherType = 0
+	LauncherConfig_deno     LauncherConfig_LauncherType = 0
+	LauncherConfig_embedded LauncherConfig_LauncherType = 1 // Evaluated by an embedded JavaScript engine, without Deno
 )
 
 // Enum value maps for LauncherConfig_LauncherType.
 var (
 	LauncherConfig_LauncherType_name = map[int32]string{
 		0: "deno",
+		1: "embedded",
 	}
 	LauncherConfig_LauncherType_value = map[string]int32{
-		"deno": 0,
+		"deno":     0,
+		"embedded": 1,
 	}
 )
 
@@ -111,10 +114,13 @@ func (RunLauncherResponse_Type) EnumDescriptor() ([]byte, []int) {
 }
 
 type LauncherConfig struct {
-	state         protoimpl.MessageState      `protogen:"open.v1"`
-	LauncherType  LauncherConfig_LauncherType `protobuf:"varint,1,opt,name=launcher_type,json=launcherType,proto3,enum=broker.launcher.LauncherConfig_LauncherType" json:"launcher_type,omitempty"`
-	Code          string                      `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
-	JsonConfig    string                      `protobuf:"bytes,3,opt,name=json_config,json=jsonConfig,proto3" json:"json_config,omitempty"`
+	state        protoimpl.MessageState      `protogen:"open.v1"`
+	LauncherType LauncherConfig_LauncherType `protobuf:"varint,1,opt,name=launcher_type,json=launcherType,proto3,enum=broker.launcher.LauncherConfig_LauncherType" json:"launcher_type,omitempty"`
+	Code         string                      `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	JsonConfig   string                      `protobuf:"bytes,3,opt,name=json_config,json=jsonConfig,proto3" json:"json_config,omitempty"`
+	// JSON Schema the json_config is validated against before running the
+	// launcher. Optional.
+	JsonSchema    string `protobuf:"bytes,4,opt,name=json_schema,json=jsonSchema,proto3" json:"json_schema,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -170,6 +176,13 @@ func (x *LauncherConfig) GetJsonConfig() string {
 	return ""
 }
 
+func (x *LauncherConfig) GetJsonSchema() string {
+	if x != nil {
+		return x.JsonSchema
+	}
+	return ""
+}
+
 type RunLauncherRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Config        *LauncherConfig        `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
@@ -278,14 +291,17 @@ var File_launcher_proto protoreflect.FileDescriptor
 
 const file_launcher_proto_rawDesc = "" +
 	"\n" +
-	"\x0elauncher.proto\x12\x0fbroker.launcher\"\xb2\x01\n" +
+	"\x0elauncher.proto\x12\x0fbroker.launcher\"\xe1\x01\n" +
 	"\x0eLauncherConfig\x12Q\n" +
 	"\rlauncher_type\x18\x01 \x01(\x0e2,.broker.launcher.LauncherConfig.LauncherTypeR\flauncherType\x12\x12\n" +
 	"\x04code\x18\x02 \x01(\tR\x04code\x12\x1f\n" +
 	"\vjson_config\x18\x03 \x01(\tR\n" +
-	"jsonConfig\"\x18\n" +
+	"jsonConfig\x12\x1f\n" +
+	"\vjson_schema\x18\x04 \x01(\tR\n" +
+	"jsonSchema\"&\n" +
 	"\fLauncherType\x12\b\n" +
-	"\x04deno\x10\x00\"M\n" +
+	"\x04deno\x10\x00\x12\f\n" +
+	"\bembedded\x10\x01\"M\n" +
 	"\x12RunLauncherRequest\x127\n" +
 	"\x06config\x18\x01 \x01(\v2\x1f.broker.launcher.LauncherConfigR\x06config\"\xba\x01\n" +
 	"\x13RunLauncherResponse\x12=\n" +
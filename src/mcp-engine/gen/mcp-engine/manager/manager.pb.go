@@ -1128,6 +1128,292 @@ func (*ServerConfig_LambdaRunConfigWithLauncher) isServerConfig_ConfigType() {}
 
 func (*ServerConfig_LambdaRunConfigWithServer) isServerConfig_ConfigType() {}
 
+type DryRunLauncherRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerConfig  *ServerConfig          `protobuf:"bytes,1,opt,name=server_config,json=serverConfig,proto3" json:"server_config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DryRunLauncherRequest) Reset() {
+	*x = DryRunLauncherRequest{}
+	mi := &file_manager_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DryRunLauncherRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DryRunLauncherRequest) ProtoMessage() {}
+
+func (x *DryRunLauncherRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_manager_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DryRunLauncherRequest.ProtoReflect.Descriptor instead.
+func (*DryRunLauncherRequest) Descriptor() ([]byte, []int) {
+	return file_manager_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DryRunLauncherRequest) GetServerConfig() *ServerConfig {
+	if x != nil {
+		return x.ServerConfig
+	}
+	return nil
+}
+
+type DryRunRunConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Config:
+	//
+	//	*DryRunRunConfig_ContainerRunConfig
+	//	*DryRunRunConfig_RemoteRunConfig
+	//	*DryRunRunConfig_LambdaRunConfig
+	Config        isDryRunRunConfig_Config `protobuf_oneof:"config"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DryRunRunConfig) Reset() {
+	*x = DryRunRunConfig{}
+	mi := &file_manager_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DryRunRunConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DryRunRunConfig) ProtoMessage() {}
+
+func (x *DryRunRunConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_manager_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DryRunRunConfig.ProtoReflect.Descriptor instead.
+func (*DryRunRunConfig) Descriptor() ([]byte, []int) {
+	return file_manager_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DryRunRunConfig) GetConfig() isDryRunRunConfig_Config {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *DryRunRunConfig) GetContainerRunConfig() *runner.RunConfig {
+	if x != nil {
+		if x, ok := x.Config.(*DryRunRunConfig_ContainerRunConfig); ok {
+			return x.ContainerRunConfig
+		}
+	}
+	return nil
+}
+
+func (x *DryRunRunConfig) GetRemoteRunConfig() *remote.RunConfigRemote {
+	if x != nil {
+		if x, ok := x.Config.(*DryRunRunConfig_RemoteRunConfig); ok {
+			return x.RemoteRunConfig
+		}
+	}
+	return nil
+}
+
+func (x *DryRunRunConfig) GetLambdaRunConfig() *remote.RunConfigLambda {
+	if x != nil {
+		if x, ok := x.Config.(*DryRunRunConfig_LambdaRunConfig); ok {
+			return x.LambdaRunConfig
+		}
+	}
+	return nil
+}
+
+type isDryRunRunConfig_Config interface {
+	isDryRunRunConfig_Config()
+}
+
+type DryRunRunConfig_ContainerRunConfig struct {
+	ContainerRunConfig *runner.RunConfig `protobuf:"bytes,1,opt,name=container_run_config,json=containerRunConfig,proto3,oneof"`
+}
+
+type DryRunRunConfig_RemoteRunConfig struct {
+	RemoteRunConfig *remote.RunConfigRemote `protobuf:"bytes,2,opt,name=remote_run_config,json=remoteRunConfig,proto3,oneof"`
+}
+
+type DryRunRunConfig_LambdaRunConfig struct {
+	LambdaRunConfig *remote.RunConfigLambda `protobuf:"bytes,3,opt,name=lambda_run_config,json=lambdaRunConfig,proto3,oneof"`
+}
+
+func (*DryRunRunConfig_ContainerRunConfig) isDryRunRunConfig_Config() {}
+
+func (*DryRunRunConfig_RemoteRunConfig) isDryRunRunConfig_Config() {}
+
+func (*DryRunRunConfig_LambdaRunConfig) isDryRunRunConfig_Config() {}
+
+type DryRunLauncherTimings struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LauncherMs    int64                  `protobuf:"varint,1,opt,name=launcher_ms,json=launcherMs,proto3" json:"launcher_ms,omitempty"`
+	ValidationMs  int64                  `protobuf:"varint,2,opt,name=validation_ms,json=validationMs,proto3" json:"validation_ms,omitempty"`
+	TotalMs       int64                  `protobuf:"varint,3,opt,name=total_ms,json=totalMs,proto3" json:"total_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DryRunLauncherTimings) Reset() {
+	*x = DryRunLauncherTimings{}
+	mi := &file_manager_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DryRunLauncherTimings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DryRunLauncherTimings) ProtoMessage() {}
+
+func (x *DryRunLauncherTimings) ProtoReflect() protoreflect.Message {
+	mi := &file_manager_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DryRunLauncherTimings.ProtoReflect.Descriptor instead.
+func (*DryRunLauncherTimings) Descriptor() ([]byte, []int) {
+	return file_manager_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DryRunLauncherTimings) GetLauncherMs() int64 {
+	if x != nil {
+		return x.LauncherMs
+	}
+	return 0
+}
+
+func (x *DryRunLauncherTimings) GetValidationMs() int64 {
+	if x != nil {
+		return x.ValidationMs
+	}
+	return 0
+}
+
+func (x *DryRunLauncherTimings) GetTotalMs() int64 {
+	if x != nil {
+		return x.TotalMs
+	}
+	return 0
+}
+
+type DryRunLauncherResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Success          bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	RunConfig        *DryRunRunConfig       `protobuf:"bytes,2,opt,name=run_config,json=runConfig,proto3" json:"run_config,omitempty"`                      // Resolved run config with secrets masked, set on success
+	LauncherError    string                 `protobuf:"bytes,3,opt,name=launcher_error,json=launcherError,proto3" json:"launcher_error,omitempty"`          // Set if the launcher failed to run
+	ValidationErrors []string               `protobuf:"bytes,4,rep,name=validation_errors,json=validationErrors,proto3" json:"validation_errors,omitempty"` // Set if the launcher output is invalid
+	Cached           bool                   `protobuf:"varint,5,opt,name=cached,proto3" json:"cached,omitempty"`                                            // Whether the launcher result came from the cache
+	Timings          *DryRunLauncherTimings `protobuf:"bytes,6,opt,name=timings,proto3" json:"timings,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DryRunLauncherResponse) Reset() {
+	*x = DryRunLauncherResponse{}
+	mi := &file_manager_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DryRunLauncherResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DryRunLauncherResponse) ProtoMessage() {}
+
+func (x *DryRunLauncherResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_manager_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DryRunLauncherResponse.ProtoReflect.Descriptor instead.
+func (*DryRunLauncherResponse) Descriptor() ([]byte, []int) {
+	return file_manager_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DryRunLauncherResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DryRunLauncherResponse) GetRunConfig() *DryRunRunConfig {
+	if x != nil {
+		return x.RunConfig
+	}
+	return nil
+}
+
+func (x *DryRunLauncherResponse) GetLauncherError() string {
+	if x != nil {
+		return x.LauncherError
+	}
+	return ""
+}
+
+func (x *DryRunLauncherResponse) GetValidationErrors() []string {
+	if x != nil {
+		return x.ValidationErrors
+	}
+	return nil
+}
+
+func (x *DryRunLauncherResponse) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+func (x *DryRunLauncherResponse) GetTimings() *DryRunLauncherTimings {
+	if x != nil {
+		return x.Timings
+	}
+	return nil
+}
+
 type SessionConfig struct {
 	state              protoimpl.MessageState `protogen:"open.v1"`
 	ServerConfig       *ServerConfig          `protobuf:"bytes,1,opt,name=server_config,json=serverConfig,proto3" json:"server_config,omitempty"`
@@ -1139,7 +1425,7 @@ type SessionConfig struct {
 
 func (x *SessionConfig) Reset() {
 	*x = SessionConfig{}
-	mi := &file_manager_proto_msgTypes[11]
+	mi := &file_manager_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1151,7 +1437,7 @@ func (x *SessionConfig) String() string {
 func (*SessionConfig) ProtoMessage() {}
 
 func (x *SessionConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[11]
+	mi := &file_manager_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1164,7 +1450,7 @@ func (x *SessionConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SessionConfig.ProtoReflect.Descriptor instead.
 func (*SessionConfig) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{11}
+	return file_manager_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *SessionConfig) GetServerConfig() *ServerConfig {
@@ -1198,7 +1484,7 @@ type CreateSessionResponse struct {
 
 func (x *CreateSessionResponse) Reset() {
 	*x = CreateSessionResponse{}
-	mi := &file_manager_proto_msgTypes[12]
+	mi := &file_manager_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1210,7 +1496,7 @@ func (x *CreateSessionResponse) String() string {
 func (*CreateSessionResponse) ProtoMessage() {}
 
 func (x *CreateSessionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[12]
+	mi := &file_manager_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1223,7 +1509,7 @@ func (x *CreateSessionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateSessionResponse.ProtoReflect.Descriptor instead.
 func (*CreateSessionResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{12}
+	return file_manager_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *CreateSessionResponse) GetSessionId() string {
@@ -1249,7 +1535,7 @@ type DiscoverRequest struct {
 
 func (x *DiscoverRequest) Reset() {
 	*x = DiscoverRequest{}
-	mi := &file_manager_proto_msgTypes[13]
+	mi := &file_manager_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1261,7 +1547,7 @@ func (x *DiscoverRequest) String() string {
 func (*DiscoverRequest) ProtoMessage() {}
 
 func (x *DiscoverRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[13]
+	mi := &file_manager_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1274,7 +1560,7 @@ func (x *DiscoverRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DiscoverRequest.ProtoReflect.Descriptor instead.
 func (*DiscoverRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{13}
+	return file_manager_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *DiscoverRequest) GetServerConfig() *ServerConfig {
@@ -1295,7 +1581,7 @@ type SendMcpMessageRequest struct {
 
 func (x *SendMcpMessageRequest) Reset() {
 	*x = SendMcpMessageRequest{}
-	mi := &file_manager_proto_msgTypes[14]
+	mi := &file_manager_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1307,7 +1593,7 @@ func (x *SendMcpMessageRequest) String() string {
 func (*SendMcpMessageRequest) ProtoMessage() {}
 
 func (x *SendMcpMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[14]
+	mi := &file_manager_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1320,7 +1606,7 @@ func (x *SendMcpMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SendMcpMessageRequest.ProtoReflect.Descriptor instead.
 func (*SendMcpMessageRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{14}
+	return file_manager_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *SendMcpMessageRequest) GetSessionId() string {
@@ -1356,7 +1642,7 @@ type StreamMcpMessagesRequest struct {
 
 func (x *StreamMcpMessagesRequest) Reset() {
 	*x = StreamMcpMessagesRequest{}
-	mi := &file_manager_proto_msgTypes[15]
+	mi := &file_manager_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1368,7 +1654,7 @@ func (x *StreamMcpMessagesRequest) String() string {
 func (*StreamMcpMessagesRequest) ProtoMessage() {}
 
 func (x *StreamMcpMessagesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[15]
+	mi := &file_manager_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1381,7 +1667,7 @@ func (x *StreamMcpMessagesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StreamMcpMessagesRequest.ProtoReflect.Descriptor instead.
 func (*StreamMcpMessagesRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{15}
+	return file_manager_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *StreamMcpMessagesRequest) GetSessionId() string {
@@ -1421,7 +1707,7 @@ type SessionEventInfoRun struct {
 
 func (x *SessionEventInfoRun) Reset() {
 	*x = SessionEventInfoRun{}
-	mi := &file_manager_proto_msgTypes[16]
+	mi := &file_manager_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1433,7 +1719,7 @@ func (x *SessionEventInfoRun) String() string {
 func (*SessionEventInfoRun) ProtoMessage() {}
 
 func (x *SessionEventInfoRun) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[16]
+	mi := &file_manager_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1446,7 +1732,7 @@ func (x *SessionEventInfoRun) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SessionEventInfoRun.ProtoReflect.Descriptor instead.
 func (*SessionEventInfoRun) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{16}
+	return file_manager_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *SessionEventInfoRun) GetRun() *EngineSessionRun {
@@ -1465,7 +1751,7 @@ type SessionEventInfoSession struct {
 
 func (x *SessionEventInfoSession) Reset() {
 	*x = SessionEventInfoSession{}
-	mi := &file_manager_proto_msgTypes[17]
+	mi := &file_manager_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1477,7 +1763,7 @@ func (x *SessionEventInfoSession) String() string {
 func (*SessionEventInfoSession) ProtoMessage() {}
 
 func (x *SessionEventInfoSession) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[17]
+	mi := &file_manager_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1490,7 +1776,7 @@ func (x *SessionEventInfoSession) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SessionEventInfoSession.ProtoReflect.Descriptor instead.
 func (*SessionEventInfoSession) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{17}
+	return file_manager_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *SessionEventInfoSession) GetSession() *EngineSession {
@@ -1509,7 +1795,7 @@ type SessionEventStartRun struct {
 
 func (x *SessionEventStartRun) Reset() {
 	*x = SessionEventStartRun{}
-	mi := &file_manager_proto_msgTypes[18]
+	mi := &file_manager_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1521,7 +1807,7 @@ func (x *SessionEventStartRun) String() string {
 func (*SessionEventStartRun) ProtoMessage() {}
 
 func (x *SessionEventStartRun) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[18]
+	mi := &file_manager_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1534,7 +1820,7 @@ func (x *SessionEventStartRun) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SessionEventStartRun.ProtoReflect.Descriptor instead.
 func (*SessionEventStartRun) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{18}
+	return file_manager_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *SessionEventStartRun) GetRun() *EngineSessionRun {
@@ -1553,7 +1839,7 @@ type SessionEventStopRun struct {
 
 func (x *SessionEventStopRun) Reset() {
 	*x = SessionEventStopRun{}
-	mi := &file_manager_proto_msgTypes[19]
+	mi := &file_manager_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1565,7 +1851,7 @@ func (x *SessionEventStopRun) String() string {
 func (*SessionEventStopRun) ProtoMessage() {}
 
 func (x *SessionEventStopRun) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[19]
+	mi := &file_manager_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1578,7 +1864,7 @@ func (x *SessionEventStopRun) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SessionEventStopRun.ProtoReflect.Descriptor instead.
 func (*SessionEventStopRun) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{19}
+	return file_manager_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *SessionEventStopRun) GetRun() *EngineSessionRun {
@@ -1603,7 +1889,7 @@ type SessionEvent struct {
 
 func (x *SessionEvent) Reset() {
 	*x = SessionEvent{}
-	mi := &file_manager_proto_msgTypes[20]
+	mi := &file_manager_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1615,7 +1901,7 @@ func (x *SessionEvent) String() string {
 func (*SessionEvent) ProtoMessage() {}
 
 func (x *SessionEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[20]
+	mi := &file_manager_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1628,7 +1914,7 @@ func (x *SessionEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SessionEvent.ProtoReflect.Descriptor instead.
 func (*SessionEvent) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{20}
+	return file_manager_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *SessionEvent) GetEvent() isSessionEvent_Event {
@@ -1718,7 +2004,7 @@ type McpConnectionStreamResponse struct {
 
 func (x *McpConnectionStreamResponse) Reset() {
 	*x = McpConnectionStreamResponse{}
-	mi := &file_manager_proto_msgTypes[21]
+	mi := &file_manager_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1730,7 +2016,7 @@ func (x *McpConnectionStreamResponse) String() string {
 func (*McpConnectionStreamResponse) ProtoMessage() {}
 
 func (x *McpConnectionStreamResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[21]
+	mi := &file_manager_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1743,7 +2029,7 @@ func (x *McpConnectionStreamResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use McpConnectionStreamResponse.ProtoReflect.Descriptor instead.
 func (*McpConnectionStreamResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{21}
+	return file_manager_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *McpConnectionStreamResponse) GetResponse() isMcpConnectionStreamResponse_Response {
@@ -1833,7 +2119,7 @@ type GetServerInfoRequest struct {
 
 func (x *GetServerInfoRequest) Reset() {
 	*x = GetServerInfoRequest{}
-	mi := &file_manager_proto_msgTypes[22]
+	mi := &file_manager_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1845,7 +2131,7 @@ func (x *GetServerInfoRequest) String() string {
 func (*GetServerInfoRequest) ProtoMessage() {}
 
 func (x *GetServerInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[22]
+	mi := &file_manager_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1858,7 +2144,7 @@ func (x *GetServerInfoRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetServerInfoRequest.ProtoReflect.Descriptor instead.
 func (*GetServerInfoRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{22}
+	return file_manager_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *GetServerInfoRequest) GetSessionId() string {
@@ -1876,7 +2162,7 @@ type ListWorkersRequest struct {
 
 func (x *ListWorkersRequest) Reset() {
 	*x = ListWorkersRequest{}
-	mi := &file_manager_proto_msgTypes[23]
+	mi := &file_manager_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1888,7 +2174,7 @@ func (x *ListWorkersRequest) String() string {
 func (*ListWorkersRequest) ProtoMessage() {}
 
 func (x *ListWorkersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[23]
+	mi := &file_manager_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1901,7 +2187,7 @@ func (x *ListWorkersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListWorkersRequest.ProtoReflect.Descriptor instead.
 func (*ListWorkersRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{23}
+	return file_manager_proto_rawDescGZIP(), []int{27}
 }
 
 type ListWorkersResponse struct {
@@ -1913,7 +2199,7 @@ type ListWorkersResponse struct {
 
 func (x *ListWorkersResponse) Reset() {
 	*x = ListWorkersResponse{}
-	mi := &file_manager_proto_msgTypes[24]
+	mi := &file_manager_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1925,7 +2211,7 @@ func (x *ListWorkersResponse) String() string {
 func (*ListWorkersResponse) ProtoMessage() {}
 
 func (x *ListWorkersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[24]
+	mi := &file_manager_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1938,7 +2224,7 @@ func (x *ListWorkersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListWorkersResponse.ProtoReflect.Descriptor instead.
 func (*ListWorkersResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{24}
+	return file_manager_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *ListWorkersResponse) GetWorkers() []*WorkerInfo {
@@ -1960,7 +2246,7 @@ type WorkerInfo struct {
 
 func (x *WorkerInfo) Reset() {
 	*x = WorkerInfo{}
-	mi := &file_manager_proto_msgTypes[25]
+	mi := &file_manager_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1972,7 +2258,7 @@ func (x *WorkerInfo) String() string {
 func (*WorkerInfo) ProtoMessage() {}
 
 func (x *WorkerInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[25]
+	mi := &file_manager_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1985,7 +2271,7 @@ func (x *WorkerInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WorkerInfo.ProtoReflect.Descriptor instead.
 func (*WorkerInfo) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{25}
+	return file_manager_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *WorkerInfo) GetWorkerId() string {
@@ -2025,7 +2311,7 @@ type DiscardSessionRequest struct {
 
 func (x *DiscardSessionRequest) Reset() {
 	*x = DiscardSessionRequest{}
-	mi := &file_manager_proto_msgTypes[26]
+	mi := &file_manager_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2037,7 +2323,7 @@ func (x *DiscardSessionRequest) String() string {
 func (*DiscardSessionRequest) ProtoMessage() {}
 
 func (x *DiscardSessionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[26]
+	mi := &file_manager_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2050,7 +2336,7 @@ func (x *DiscardSessionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DiscardSessionRequest.ProtoReflect.Descriptor instead.
 func (*DiscardSessionRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{26}
+	return file_manager_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *DiscardSessionRequest) GetSessionId() string {
@@ -2068,7 +2354,7 @@ type DiscardSessionResponse struct {
 
 func (x *DiscardSessionResponse) Reset() {
 	*x = DiscardSessionResponse{}
-	mi := &file_manager_proto_msgTypes[27]
+	mi := &file_manager_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2080,7 +2366,7 @@ func (x *DiscardSessionResponse) String() string {
 func (*DiscardSessionResponse) ProtoMessage() {}
 
 func (x *DiscardSessionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[27]
+	mi := &file_manager_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2093,7 +2379,7 @@ func (x *DiscardSessionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DiscardSessionResponse.ProtoReflect.Descriptor instead.
 func (*DiscardSessionResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{27}
+	return file_manager_proto_rawDescGZIP(), []int{31}
 }
 
 type EngineSession struct {
@@ -2118,7 +2404,7 @@ type EngineSession struct {
 
 func (x *EngineSession) Reset() {
 	*x = EngineSession{}
-	mi := &file_manager_proto_msgTypes[28]
+	mi := &file_manager_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2130,7 +2416,7 @@ func (x *EngineSession) String() string {
 func (*EngineSession) ProtoMessage() {}
 
 func (x *EngineSession) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[28]
+	mi := &file_manager_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2143,7 +2429,7 @@ func (x *EngineSession) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EngineSession.ProtoReflect.Descriptor instead.
 func (*EngineSession) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{28}
+	return file_manager_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *EngineSession) GetId() string {
@@ -2264,7 +2550,7 @@ type EngineSessionRun struct {
 
 func (x *EngineSessionRun) Reset() {
 	*x = EngineSessionRun{}
-	mi := &file_manager_proto_msgTypes[29]
+	mi := &file_manager_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2276,7 +2562,7 @@ func (x *EngineSessionRun) String() string {
 func (*EngineSessionRun) ProtoMessage() {}
 
 func (x *EngineSessionRun) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[29]
+	mi := &file_manager_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2289,7 +2575,7 @@ func (x *EngineSessionRun) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EngineSessionRun.ProtoReflect.Descriptor instead.
 func (*EngineSessionRun) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{29}
+	return file_manager_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *EngineSessionRun) GetId() string {
@@ -2394,7 +2680,7 @@ type EngineSessionError struct {
 
 func (x *EngineSessionError) Reset() {
 	*x = EngineSessionError{}
-	mi := &file_manager_proto_msgTypes[30]
+	mi := &file_manager_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2406,7 +2692,7 @@ func (x *EngineSessionError) String() string {
 func (*EngineSessionError) ProtoMessage() {}
 
 func (x *EngineSessionError) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[30]
+	mi := &file_manager_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2419,7 +2705,7 @@ func (x *EngineSessionError) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EngineSessionError.ProtoReflect.Descriptor instead.
 func (*EngineSessionError) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{30}
+	return file_manager_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *EngineSessionError) GetId() string {
@@ -2512,7 +2798,7 @@ type EngineSessionEvent struct {
 
 func (x *EngineSessionEvent) Reset() {
 	*x = EngineSessionEvent{}
-	mi := &file_manager_proto_msgTypes[31]
+	mi := &file_manager_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2524,7 +2810,7 @@ func (x *EngineSessionEvent) String() string {
 func (*EngineSessionEvent) ProtoMessage() {}
 
 func (x *EngineSessionEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[31]
+	mi := &file_manager_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2537,7 +2823,7 @@ func (x *EngineSessionEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EngineSessionEvent.ProtoReflect.Descriptor instead.
 func (*EngineSessionEvent) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{31}
+	return file_manager_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *EngineSessionEvent) GetId() string {
@@ -2635,7 +2921,7 @@ type EngineSessionMessage struct {
 
 func (x *EngineSessionMessage) Reset() {
 	*x = EngineSessionMessage{}
-	mi := &file_manager_proto_msgTypes[32]
+	mi := &file_manager_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2647,7 +2933,7 @@ func (x *EngineSessionMessage) String() string {
 func (*EngineSessionMessage) ProtoMessage() {}
 
 func (x *EngineSessionMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[32]
+	mi := &file_manager_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2660,7 +2946,7 @@ func (x *EngineSessionMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EngineSessionMessage.ProtoReflect.Descriptor instead.
 func (*EngineSessionMessage) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{32}
+	return file_manager_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *EngineSessionMessage) GetId() string {
@@ -2754,7 +3040,7 @@ type EngineServer struct {
 
 func (x *EngineServer) Reset() {
 	*x = EngineServer{}
-	mi := &file_manager_proto_msgTypes[33]
+	mi := &file_manager_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2766,7 +3052,7 @@ func (x *EngineServer) String() string {
 func (*EngineServer) ProtoMessage() {}
 
 func (x *EngineServer) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[33]
+	mi := &file_manager_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2779,7 +3065,7 @@ func (x *EngineServer) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EngineServer.ProtoReflect.Descriptor instead.
 func (*EngineServer) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{33}
+	return file_manager_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *EngineServer) GetId() string {
@@ -2885,7 +3171,7 @@ type ListPagination struct {
 
 func (x *ListPagination) Reset() {
 	*x = ListPagination{}
-	mi := &file_manager_proto_msgTypes[34]
+	mi := &file_manager_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2897,7 +3183,7 @@ func (x *ListPagination) String() string {
 func (*ListPagination) ProtoMessage() {}
 
 func (x *ListPagination) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[34]
+	mi := &file_manager_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2910,7 +3196,7 @@ func (x *ListPagination) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListPagination.ProtoReflect.Descriptor instead.
 func (*ListPagination) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{34}
+	return file_manager_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *ListPagination) GetAfterId() string {
@@ -2951,7 +3237,7 @@ type ListSessionsRequest struct {
 
 func (x *ListSessionsRequest) Reset() {
 	*x = ListSessionsRequest{}
-	mi := &file_manager_proto_msgTypes[35]
+	mi := &file_manager_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2963,7 +3249,7 @@ func (x *ListSessionsRequest) String() string {
 func (*ListSessionsRequest) ProtoMessage() {}
 
 func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[35]
+	mi := &file_manager_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2976,7 +3262,7 @@ func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
 func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{35}
+	return file_manager_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *ListSessionsRequest) GetExternalId() string {
@@ -3002,7 +3288,7 @@ type ListSessionsResponse struct {
 
 func (x *ListSessionsResponse) Reset() {
 	*x = ListSessionsResponse{}
-	mi := &file_manager_proto_msgTypes[36]
+	mi := &file_manager_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3014,7 +3300,7 @@ func (x *ListSessionsResponse) String() string {
 func (*ListSessionsResponse) ProtoMessage() {}
 
 func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[36]
+	mi := &file_manager_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3027,7 +3313,7 @@ func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
 func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{36}
+	return file_manager_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *ListSessionsResponse) GetSessions() []*EngineSession {
@@ -3046,7 +3332,7 @@ type GetSessionRequest struct {
 
 func (x *GetSessionRequest) Reset() {
 	*x = GetSessionRequest{}
-	mi := &file_manager_proto_msgTypes[37]
+	mi := &file_manager_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3058,7 +3344,7 @@ func (x *GetSessionRequest) String() string {
 func (*GetSessionRequest) ProtoMessage() {}
 
 func (x *GetSessionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[37]
+	mi := &file_manager_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3071,7 +3357,7 @@ func (x *GetSessionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetSessionRequest.ProtoReflect.Descriptor instead.
 func (*GetSessionRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{37}
+	return file_manager_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *GetSessionRequest) GetSessionId() string {
@@ -3090,7 +3376,7 @@ type GetSessionResponse struct {
 
 func (x *GetSessionResponse) Reset() {
 	*x = GetSessionResponse{}
-	mi := &file_manager_proto_msgTypes[38]
+	mi := &file_manager_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3102,7 +3388,7 @@ func (x *GetSessionResponse) String() string {
 func (*GetSessionResponse) ProtoMessage() {}
 
 func (x *GetSessionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[38]
+	mi := &file_manager_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3115,7 +3401,7 @@ func (x *GetSessionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetSessionResponse.ProtoReflect.Descriptor instead.
 func (*GetSessionResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{38}
+	return file_manager_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *GetSessionResponse) GetSession() *EngineSession {
@@ -3136,7 +3422,7 @@ type ListRunsRequest struct {
 
 func (x *ListRunsRequest) Reset() {
 	*x = ListRunsRequest{}
-	mi := &file_manager_proto_msgTypes[39]
+	mi := &file_manager_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3148,7 +3434,7 @@ func (x *ListRunsRequest) String() string {
 func (*ListRunsRequest) ProtoMessage() {}
 
 func (x *ListRunsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[39]
+	mi := &file_manager_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3161,7 +3447,7 @@ func (x *ListRunsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRunsRequest.ProtoReflect.Descriptor instead.
 func (*ListRunsRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{39}
+	return file_manager_proto_rawDescGZIP(), []int{43}
 }
 
 func (x *ListRunsRequest) GetSessionId() string {
@@ -3194,7 +3480,7 @@ type ListRunsResponse struct {
 
 func (x *ListRunsResponse) Reset() {
 	*x = ListRunsResponse{}
-	mi := &file_manager_proto_msgTypes[40]
+	mi := &file_manager_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3206,7 +3492,7 @@ func (x *ListRunsResponse) String() string {
 func (*ListRunsResponse) ProtoMessage() {}
 
 func (x *ListRunsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[40]
+	mi := &file_manager_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3219,7 +3505,7 @@ func (x *ListRunsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRunsResponse.ProtoReflect.Descriptor instead.
 func (*ListRunsResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{40}
+	return file_manager_proto_rawDescGZIP(), []int{44}
 }
 
 func (x *ListRunsResponse) GetRuns() []*EngineSessionRun {
@@ -3238,7 +3524,7 @@ type GetRunRequest struct {
 
 func (x *GetRunRequest) Reset() {
 	*x = GetRunRequest{}
-	mi := &file_manager_proto_msgTypes[41]
+	mi := &file_manager_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3250,7 +3536,7 @@ func (x *GetRunRequest) String() string {
 func (*GetRunRequest) ProtoMessage() {}
 
 func (x *GetRunRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[41]
+	mi := &file_manager_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3263,7 +3549,7 @@ func (x *GetRunRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRunRequest.ProtoReflect.Descriptor instead.
 func (*GetRunRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{41}
+	return file_manager_proto_rawDescGZIP(), []int{45}
 }
 
 func (x *GetRunRequest) GetRunId() string {
@@ -3282,7 +3568,7 @@ type GetRunResponse struct {
 
 func (x *GetRunResponse) Reset() {
 	*x = GetRunResponse{}
-	mi := &file_manager_proto_msgTypes[42]
+	mi := &file_manager_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3294,7 +3580,7 @@ func (x *GetRunResponse) String() string {
 func (*GetRunResponse) ProtoMessage() {}
 
 func (x *GetRunResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[42]
+	mi := &file_manager_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3307,7 +3593,7 @@ func (x *GetRunResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRunResponse.ProtoReflect.Descriptor instead.
 func (*GetRunResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{42}
+	return file_manager_proto_rawDescGZIP(), []int{46}
 }
 
 func (x *GetRunResponse) GetRun() *EngineSessionRun {
@@ -3326,7 +3612,7 @@ type GetErrorRequest struct {
 
 func (x *GetErrorRequest) Reset() {
 	*x = GetErrorRequest{}
-	mi := &file_manager_proto_msgTypes[43]
+	mi := &file_manager_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3338,7 +3624,7 @@ func (x *GetErrorRequest) String() string {
 func (*GetErrorRequest) ProtoMessage() {}
 
 func (x *GetErrorRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[43]
+	mi := &file_manager_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3351,7 +3637,7 @@ func (x *GetErrorRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetErrorRequest.ProtoReflect.Descriptor instead.
 func (*GetErrorRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{43}
+	return file_manager_proto_rawDescGZIP(), []int{47}
 }
 
 func (x *GetErrorRequest) GetErrorId() string {
@@ -3370,7 +3656,7 @@ type GetErrorResponse struct {
 
 func (x *GetErrorResponse) Reset() {
 	*x = GetErrorResponse{}
-	mi := &file_manager_proto_msgTypes[44]
+	mi := &file_manager_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3382,7 +3668,7 @@ func (x *GetErrorResponse) String() string {
 func (*GetErrorResponse) ProtoMessage() {}
 
 func (x *GetErrorResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[44]
+	mi := &file_manager_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3395,7 +3681,7 @@ func (x *GetErrorResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetErrorResponse.ProtoReflect.Descriptor instead.
 func (*GetErrorResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{44}
+	return file_manager_proto_rawDescGZIP(), []int{48}
 }
 
 func (x *GetErrorResponse) GetError() *EngineSessionError {
@@ -3414,7 +3700,7 @@ type GetEventRequest struct {
 
 func (x *GetEventRequest) Reset() {
 	*x = GetEventRequest{}
-	mi := &file_manager_proto_msgTypes[45]
+	mi := &file_manager_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3426,7 +3712,7 @@ func (x *GetEventRequest) String() string {
 func (*GetEventRequest) ProtoMessage() {}
 
 func (x *GetEventRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[45]
+	mi := &file_manager_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3439,7 +3725,7 @@ func (x *GetEventRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetEventRequest.ProtoReflect.Descriptor instead.
 func (*GetEventRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{45}
+	return file_manager_proto_rawDescGZIP(), []int{49}
 }
 
 func (x *GetEventRequest) GetEventId() string {
@@ -3458,7 +3744,7 @@ type GetEventResponse struct {
 
 func (x *GetEventResponse) Reset() {
 	*x = GetEventResponse{}
-	mi := &file_manager_proto_msgTypes[46]
+	mi := &file_manager_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3470,7 +3756,7 @@ func (x *GetEventResponse) String() string {
 func (*GetEventResponse) ProtoMessage() {}
 
 func (x *GetEventResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[46]
+	mi := &file_manager_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3483,7 +3769,7 @@ func (x *GetEventResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetEventResponse.ProtoReflect.Descriptor instead.
 func (*GetEventResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{46}
+	return file_manager_proto_rawDescGZIP(), []int{50}
 }
 
 func (x *GetEventResponse) GetEvent() *EngineSessionEvent {
@@ -3502,7 +3788,7 @@ type GetMessageRequest struct {
 
 func (x *GetMessageRequest) Reset() {
 	*x = GetMessageRequest{}
-	mi := &file_manager_proto_msgTypes[47]
+	mi := &file_manager_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3514,7 +3800,7 @@ func (x *GetMessageRequest) String() string {
 func (*GetMessageRequest) ProtoMessage() {}
 
 func (x *GetMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[47]
+	mi := &file_manager_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3527,7 +3813,7 @@ func (x *GetMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMessageRequest.ProtoReflect.Descriptor instead.
 func (*GetMessageRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{47}
+	return file_manager_proto_rawDescGZIP(), []int{51}
 }
 
 func (x *GetMessageRequest) GetMessageId() string {
@@ -3546,7 +3832,7 @@ type GetMessageResponse struct {
 
 func (x *GetMessageResponse) Reset() {
 	*x = GetMessageResponse{}
-	mi := &file_manager_proto_msgTypes[48]
+	mi := &file_manager_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3558,7 +3844,7 @@ func (x *GetMessageResponse) String() string {
 func (*GetMessageResponse) ProtoMessage() {}
 
 func (x *GetMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[48]
+	mi := &file_manager_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3571,7 +3857,7 @@ func (x *GetMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMessageResponse.ProtoReflect.Descriptor instead.
 func (*GetMessageResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{48}
+	return file_manager_proto_rawDescGZIP(), []int{52}
 }
 
 func (x *GetMessageResponse) GetMessage() *EngineSessionMessage {
@@ -3592,7 +3878,7 @@ type ListRunErrorsRequest struct {
 
 func (x *ListRunErrorsRequest) Reset() {
 	*x = ListRunErrorsRequest{}
-	mi := &file_manager_proto_msgTypes[49]
+	mi := &file_manager_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3604,7 +3890,7 @@ func (x *ListRunErrorsRequest) String() string {
 func (*ListRunErrorsRequest) ProtoMessage() {}
 
 func (x *ListRunErrorsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[49]
+	mi := &file_manager_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3617,7 +3903,7 @@ func (x *ListRunErrorsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRunErrorsRequest.ProtoReflect.Descriptor instead.
 func (*ListRunErrorsRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{49}
+	return file_manager_proto_rawDescGZIP(), []int{53}
 }
 
 func (x *ListRunErrorsRequest) GetRunId() string {
@@ -3650,7 +3936,7 @@ type ListRunErrorsResponse struct {
 
 func (x *ListRunErrorsResponse) Reset() {
 	*x = ListRunErrorsResponse{}
-	mi := &file_manager_proto_msgTypes[50]
+	mi := &file_manager_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3662,7 +3948,7 @@ func (x *ListRunErrorsResponse) String() string {
 func (*ListRunErrorsResponse) ProtoMessage() {}
 
 func (x *ListRunErrorsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[50]
+	mi := &file_manager_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3675,7 +3961,7 @@ func (x *ListRunErrorsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRunErrorsResponse.ProtoReflect.Descriptor instead.
 func (*ListRunErrorsResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{50}
+	return file_manager_proto_rawDescGZIP(), []int{54}
 }
 
 func (x *ListRunErrorsResponse) GetErrors() []*EngineSessionError {
@@ -3696,7 +3982,7 @@ type ListRunEventsRequest struct {
 
 func (x *ListRunEventsRequest) Reset() {
 	*x = ListRunEventsRequest{}
-	mi := &file_manager_proto_msgTypes[51]
+	mi := &file_manager_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3708,7 +3994,7 @@ func (x *ListRunEventsRequest) String() string {
 func (*ListRunEventsRequest) ProtoMessage() {}
 
 func (x *ListRunEventsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[51]
+	mi := &file_manager_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3721,7 +4007,7 @@ func (x *ListRunEventsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRunEventsRequest.ProtoReflect.Descriptor instead.
 func (*ListRunEventsRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{51}
+	return file_manager_proto_rawDescGZIP(), []int{55}
 }
 
 func (x *ListRunEventsRequest) GetRunId() string {
@@ -3754,7 +4040,7 @@ type ListRunEventsResponse struct {
 
 func (x *ListRunEventsResponse) Reset() {
 	*x = ListRunEventsResponse{}
-	mi := &file_manager_proto_msgTypes[52]
+	mi := &file_manager_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3766,7 +4052,7 @@ func (x *ListRunEventsResponse) String() string {
 func (*ListRunEventsResponse) ProtoMessage() {}
 
 func (x *ListRunEventsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[52]
+	mi := &file_manager_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3779,7 +4065,7 @@ func (x *ListRunEventsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRunEventsResponse.ProtoReflect.Descriptor instead.
 func (*ListRunEventsResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{52}
+	return file_manager_proto_rawDescGZIP(), []int{56}
 }
 
 func (x *ListRunEventsResponse) GetEvents() []*EngineSessionEvent {
@@ -3800,7 +4086,7 @@ type ListRunMessagesRequest struct {
 
 func (x *ListRunMessagesRequest) Reset() {
 	*x = ListRunMessagesRequest{}
-	mi := &file_manager_proto_msgTypes[53]
+	mi := &file_manager_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3812,7 +4098,7 @@ func (x *ListRunMessagesRequest) String() string {
 func (*ListRunMessagesRequest) ProtoMessage() {}
 
 func (x *ListRunMessagesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[53]
+	mi := &file_manager_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3825,7 +4111,7 @@ func (x *ListRunMessagesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRunMessagesRequest.ProtoReflect.Descriptor instead.
 func (*ListRunMessagesRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{53}
+	return file_manager_proto_rawDescGZIP(), []int{57}
 }
 
 func (x *ListRunMessagesRequest) GetRunId() string {
@@ -3858,7 +4144,7 @@ type ListRunMessagesResponse struct {
 
 func (x *ListRunMessagesResponse) Reset() {
 	*x = ListRunMessagesResponse{}
-	mi := &file_manager_proto_msgTypes[54]
+	mi := &file_manager_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3870,7 +4156,7 @@ func (x *ListRunMessagesResponse) String() string {
 func (*ListRunMessagesResponse) ProtoMessage() {}
 
 func (x *ListRunMessagesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[54]
+	mi := &file_manager_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3883,7 +4169,7 @@ func (x *ListRunMessagesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRunMessagesResponse.ProtoReflect.Descriptor instead.
 func (*ListRunMessagesResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{54}
+	return file_manager_proto_rawDescGZIP(), []int{58}
 }
 
 func (x *ListRunMessagesResponse) GetMessages() []*EngineSessionMessage {
@@ -3904,7 +4190,7 @@ type ListSessionEventsRequest struct {
 
 func (x *ListSessionEventsRequest) Reset() {
 	*x = ListSessionEventsRequest{}
-	mi := &file_manager_proto_msgTypes[55]
+	mi := &file_manager_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3916,7 +4202,7 @@ func (x *ListSessionEventsRequest) String() string {
 func (*ListSessionEventsRequest) ProtoMessage() {}
 
 func (x *ListSessionEventsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[55]
+	mi := &file_manager_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3929,7 +4215,7 @@ func (x *ListSessionEventsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSessionEventsRequest.ProtoReflect.Descriptor instead.
 func (*ListSessionEventsRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{55}
+	return file_manager_proto_rawDescGZIP(), []int{59}
 }
 
 func (x *ListSessionEventsRequest) GetSessionId() string {
@@ -3962,7 +4248,7 @@ type ListSessionEventsResponse struct {
 
 func (x *ListSessionEventsResponse) Reset() {
 	*x = ListSessionEventsResponse{}
-	mi := &file_manager_proto_msgTypes[56]
+	mi := &file_manager_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3974,7 +4260,7 @@ func (x *ListSessionEventsResponse) String() string {
 func (*ListSessionEventsResponse) ProtoMessage() {}
 
 func (x *ListSessionEventsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[56]
+	mi := &file_manager_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3987,7 +4273,7 @@ func (x *ListSessionEventsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSessionEventsResponse.ProtoReflect.Descriptor instead.
 func (*ListSessionEventsResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{56}
+	return file_manager_proto_rawDescGZIP(), []int{60}
 }
 
 func (x *ListSessionEventsResponse) GetEvents() []*EngineSessionEvent {
@@ -4008,7 +4294,7 @@ type ListSessionErrorsRequest struct {
 
 func (x *ListSessionErrorsRequest) Reset() {
 	*x = ListSessionErrorsRequest{}
-	mi := &file_manager_proto_msgTypes[57]
+	mi := &file_manager_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4020,7 +4306,7 @@ func (x *ListSessionErrorsRequest) String() string {
 func (*ListSessionErrorsRequest) ProtoMessage() {}
 
 func (x *ListSessionErrorsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[57]
+	mi := &file_manager_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4033,7 +4319,7 @@ func (x *ListSessionErrorsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSessionErrorsRequest.ProtoReflect.Descriptor instead.
 func (*ListSessionErrorsRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{57}
+	return file_manager_proto_rawDescGZIP(), []int{61}
 }
 
 func (x *ListSessionErrorsRequest) GetSessionId() string {
@@ -4066,7 +4352,7 @@ type ListSessionErrorsResponse struct {
 
 func (x *ListSessionErrorsResponse) Reset() {
 	*x = ListSessionErrorsResponse{}
-	mi := &file_manager_proto_msgTypes[58]
+	mi := &file_manager_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4078,7 +4364,7 @@ func (x *ListSessionErrorsResponse) String() string {
 func (*ListSessionErrorsResponse) ProtoMessage() {}
 
 func (x *ListSessionErrorsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[58]
+	mi := &file_manager_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4091,7 +4377,7 @@ func (x *ListSessionErrorsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSessionErrorsResponse.ProtoReflect.Descriptor instead.
 func (*ListSessionErrorsResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{58}
+	return file_manager_proto_rawDescGZIP(), []int{62}
 }
 
 func (x *ListSessionErrorsResponse) GetErrors() []*EngineSessionError {
@@ -4112,7 +4398,7 @@ type ListSessionMessagesRequest struct {
 
 func (x *ListSessionMessagesRequest) Reset() {
 	*x = ListSessionMessagesRequest{}
-	mi := &file_manager_proto_msgTypes[59]
+	mi := &file_manager_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4124,7 +4410,7 @@ func (x *ListSessionMessagesRequest) String() string {
 func (*ListSessionMessagesRequest) ProtoMessage() {}
 
 func (x *ListSessionMessagesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[59]
+	mi := &file_manager_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4137,7 +4423,7 @@ func (x *ListSessionMessagesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSessionMessagesRequest.ProtoReflect.Descriptor instead.
 func (*ListSessionMessagesRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{59}
+	return file_manager_proto_rawDescGZIP(), []int{63}
 }
 
 func (x *ListSessionMessagesRequest) GetSessionId() string {
@@ -4170,7 +4456,7 @@ type ListSessionMessagesResponse struct {
 
 func (x *ListSessionMessagesResponse) Reset() {
 	*x = ListSessionMessagesResponse{}
-	mi := &file_manager_proto_msgTypes[60]
+	mi := &file_manager_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4182,7 +4468,7 @@ func (x *ListSessionMessagesResponse) String() string {
 func (*ListSessionMessagesResponse) ProtoMessage() {}
 
 func (x *ListSessionMessagesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[60]
+	mi := &file_manager_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4195,7 +4481,7 @@ func (x *ListSessionMessagesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSessionMessagesResponse.ProtoReflect.Descriptor instead.
 func (*ListSessionMessagesResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{60}
+	return file_manager_proto_rawDescGZIP(), []int{64}
 }
 
 func (x *ListSessionMessagesResponse) GetMessages() []*EngineSessionMessage {
@@ -4214,7 +4500,7 @@ type ListRecentlyActiveRunsRequest struct {
 
 func (x *ListRecentlyActiveRunsRequest) Reset() {
 	*x = ListRecentlyActiveRunsRequest{}
-	mi := &file_manager_proto_msgTypes[61]
+	mi := &file_manager_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4226,7 +4512,7 @@ func (x *ListRecentlyActiveRunsRequest) String() string {
 func (*ListRecentlyActiveRunsRequest) ProtoMessage() {}
 
 func (x *ListRecentlyActiveRunsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[61]
+	mi := &file_manager_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4239,7 +4525,7 @@ func (x *ListRecentlyActiveRunsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRecentlyActiveRunsRequest.ProtoReflect.Descriptor instead.
 func (*ListRecentlyActiveRunsRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{61}
+	return file_manager_proto_rawDescGZIP(), []int{65}
 }
 
 func (x *ListRecentlyActiveRunsRequest) GetSince() int64 {
@@ -4258,7 +4544,7 @@ type ListRecentlyActiveRunsResponse struct {
 
 func (x *ListRecentlyActiveRunsResponse) Reset() {
 	*x = ListRecentlyActiveRunsResponse{}
-	mi := &file_manager_proto_msgTypes[62]
+	mi := &file_manager_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4270,7 +4556,7 @@ func (x *ListRecentlyActiveRunsResponse) String() string {
 func (*ListRecentlyActiveRunsResponse) ProtoMessage() {}
 
 func (x *ListRecentlyActiveRunsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[62]
+	mi := &file_manager_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4283,7 +4569,7 @@ func (x *ListRecentlyActiveRunsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRecentlyActiveRunsResponse.ProtoReflect.Descriptor instead.
 func (*ListRecentlyActiveRunsResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{62}
+	return file_manager_proto_rawDescGZIP(), []int{66}
 }
 
 func (x *ListRecentlyActiveRunsResponse) GetRunIds() []string {
@@ -4302,7 +4588,7 @@ type ListRecentlyActiveSessionsRequest struct {
 
 func (x *ListRecentlyActiveSessionsRequest) Reset() {
 	*x = ListRecentlyActiveSessionsRequest{}
-	mi := &file_manager_proto_msgTypes[63]
+	mi := &file_manager_proto_msgTypes[67]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4314,7 +4600,7 @@ func (x *ListRecentlyActiveSessionsRequest) String() string {
 func (*ListRecentlyActiveSessionsRequest) ProtoMessage() {}
 
 func (x *ListRecentlyActiveSessionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[63]
+	mi := &file_manager_proto_msgTypes[67]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4327,7 +4613,7 @@ func (x *ListRecentlyActiveSessionsRequest) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use ListRecentlyActiveSessionsRequest.ProtoReflect.Descriptor instead.
 func (*ListRecentlyActiveSessionsRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{63}
+	return file_manager_proto_rawDescGZIP(), []int{67}
 }
 
 func (x *ListRecentlyActiveSessionsRequest) GetSince() int64 {
@@ -4346,7 +4632,7 @@ type ListRecentlyActiveSessionsResponse struct {
 
 func (x *ListRecentlyActiveSessionsResponse) Reset() {
 	*x = ListRecentlyActiveSessionsResponse{}
-	mi := &file_manager_proto_msgTypes[64]
+	mi := &file_manager_proto_msgTypes[68]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4358,7 +4644,7 @@ func (x *ListRecentlyActiveSessionsResponse) String() string {
 func (*ListRecentlyActiveSessionsResponse) ProtoMessage() {}
 
 func (x *ListRecentlyActiveSessionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[64]
+	mi := &file_manager_proto_msgTypes[68]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4371,7 +4657,7 @@ func (x *ListRecentlyActiveSessionsResponse) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use ListRecentlyActiveSessionsResponse.ProtoReflect.Descriptor instead.
 func (*ListRecentlyActiveSessionsResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{64}
+	return file_manager_proto_rawDescGZIP(), []int{68}
 }
 
 func (x *ListRecentlyActiveSessionsResponse) GetSessionIds() []string {
@@ -4390,7 +4676,7 @@ type GetServerRequest struct {
 
 func (x *GetServerRequest) Reset() {
 	*x = GetServerRequest{}
-	mi := &file_manager_proto_msgTypes[65]
+	mi := &file_manager_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4402,7 +4688,7 @@ func (x *GetServerRequest) String() string {
 func (*GetServerRequest) ProtoMessage() {}
 
 func (x *GetServerRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[65]
+	mi := &file_manager_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4415,7 +4701,7 @@ func (x *GetServerRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetServerRequest.ProtoReflect.Descriptor instead.
 func (*GetServerRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{65}
+	return file_manager_proto_rawDescGZIP(), []int{69}
 }
 
 func (x *GetServerRequest) GetServerId() string {
@@ -4434,7 +4720,7 @@ type GetServerResponse struct {
 
 func (x *GetServerResponse) Reset() {
 	*x = GetServerResponse{}
-	mi := &file_manager_proto_msgTypes[66]
+	mi := &file_manager_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4446,7 +4732,7 @@ func (x *GetServerResponse) String() string {
 func (*GetServerResponse) ProtoMessage() {}
 
 func (x *GetServerResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[66]
+	mi := &file_manager_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4459,7 +4745,7 @@ func (x *GetServerResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetServerResponse.ProtoReflect.Descriptor instead.
 func (*GetServerResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{66}
+	return file_manager_proto_rawDescGZIP(), []int{70}
 }
 
 func (x *GetServerResponse) GetServer() *EngineServer {
@@ -4478,7 +4764,7 @@ type ListServersRequest struct {
 
 func (x *ListServersRequest) Reset() {
 	*x = ListServersRequest{}
-	mi := &file_manager_proto_msgTypes[67]
+	mi := &file_manager_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4490,7 +4776,7 @@ func (x *ListServersRequest) String() string {
 func (*ListServersRequest) ProtoMessage() {}
 
 func (x *ListServersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[67]
+	mi := &file_manager_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4503,7 +4789,7 @@ func (x *ListServersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListServersRequest.ProtoReflect.Descriptor instead.
 func (*ListServersRequest) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{67}
+	return file_manager_proto_rawDescGZIP(), []int{71}
 }
 
 func (x *ListServersRequest) GetPagination() *ListPagination {
@@ -4522,7 +4808,7 @@ type ListServersResponse struct {
 
 func (x *ListServersResponse) Reset() {
 	*x = ListServersResponse{}
-	mi := &file_manager_proto_msgTypes[68]
+	mi := &file_manager_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4534,7 +4820,7 @@ func (x *ListServersResponse) String() string {
 func (*ListServersResponse) ProtoMessage() {}
 
 func (x *ListServersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_manager_proto_msgTypes[68]
+	mi := &file_manager_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4547,7 +4833,7 @@ func (x *ListServersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListServersResponse.ProtoReflect.Descriptor instead.
 func (*ListServersResponse) Descriptor() ([]byte, []int) {
-	return file_manager_proto_rawDescGZIP(), []int{68}
+	return file_manager_proto_rawDescGZIP(), []int{72}
 }
 
 func (x *ListServersResponse) GetServers() []*EngineServer {
@@ -4557,6 +4843,286 @@ func (x *ListServersResponse) GetServers() []*EngineServer {
 	return nil
 }
 
+type ReplaySessionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SessionId      string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`                        // ID of the recorded session whose client messages are replayed
+	Config         *SessionConfig         `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`                                               // Config for the fresh session, usually the one the recorded session was created with
+	DockerImageTag *string                `protobuf:"bytes,3,opt,name=docker_image_tag,json=dockerImageTag,proto3,oneof" json:"docker_image_tag,omitempty"` // Optional, replaces the tag of the container image in the config
+	IgnorePaths    []string               `protobuf:"bytes,4,rep,name=ignore_paths,json=ignorePaths,proto3" json:"ignore_paths,omitempty"`                  // Optional, JSON paths excluded from the diff, e.g. result.content[*].text
+	KeepSession    bool                   `protobuf:"varint,5,opt,name=keep_session,json=keepSession,proto3" json:"keep_session,omitempty"`                 // Keep the replay session instead of discarding it once done
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReplaySessionRequest) Reset() {
+	*x = ReplaySessionRequest{}
+	mi := &file_manager_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplaySessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplaySessionRequest) ProtoMessage() {}
+
+func (x *ReplaySessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_manager_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplaySessionRequest.ProtoReflect.Descriptor instead.
+func (*ReplaySessionRequest) Descriptor() ([]byte, []int) {
+	return file_manager_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *ReplaySessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ReplaySessionRequest) GetConfig() *SessionConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *ReplaySessionRequest) GetDockerImageTag() string {
+	if x != nil && x.DockerImageTag != nil {
+		return *x.DockerImageTag
+	}
+	return ""
+}
+
+func (x *ReplaySessionRequest) GetIgnorePaths() []string {
+	if x != nil {
+		return x.IgnorePaths
+	}
+	return nil
+}
+
+func (x *ReplaySessionRequest) GetKeepSession() bool {
+	if x != nil {
+		return x.KeepSession
+	}
+	return false
+}
+
+type ReplayDifference struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	ExpectedJson  string                 `protobuf:"bytes,2,opt,name=expected_json,json=expectedJson,proto3" json:"expected_json,omitempty"` // Empty if the value is missing from the recorded response
+	ActualJson    string                 `protobuf:"bytes,3,opt,name=actual_json,json=actualJson,proto3" json:"actual_json,omitempty"`       // Empty if the value is missing from the replayed response
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayDifference) Reset() {
+	*x = ReplayDifference{}
+	mi := &file_manager_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayDifference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayDifference) ProtoMessage() {}
+
+func (x *ReplayDifference) ProtoReflect() protoreflect.Message {
+	mi := &file_manager_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayDifference.ProtoReflect.Descriptor instead.
+func (*ReplayDifference) Descriptor() ([]byte, []int) {
+	return file_manager_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *ReplayDifference) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ReplayDifference) GetExpectedJson() string {
+	if x != nil {
+		return x.ExpectedJson
+	}
+	return ""
+}
+
+func (x *ReplayDifference) GetActualJson() string {
+	if x != nil {
+		return x.ActualJson
+	}
+	return ""
+}
+
+type ReplayExchange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Request       *mcp.McpMessageRaw     `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	Expected      []*mcp.McpMessage      `protobuf:"bytes,2,rep,name=expected,proto3" json:"expected,omitempty"`
+	Actual        []*mcp.McpMessage      `protobuf:"bytes,3,rep,name=actual,proto3" json:"actual,omitempty"`
+	Differences   []*ReplayDifference    `protobuf:"bytes,4,rep,name=differences,proto3" json:"differences,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"` // Set if the request could not be replayed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayExchange) Reset() {
+	*x = ReplayExchange{}
+	mi := &file_manager_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayExchange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayExchange) ProtoMessage() {}
+
+func (x *ReplayExchange) ProtoReflect() protoreflect.Message {
+	mi := &file_manager_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayExchange.ProtoReflect.Descriptor instead.
+func (*ReplayExchange) Descriptor() ([]byte, []int) {
+	return file_manager_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *ReplayExchange) GetRequest() *mcp.McpMessageRaw {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *ReplayExchange) GetExpected() []*mcp.McpMessage {
+	if x != nil {
+		return x.Expected
+	}
+	return nil
+}
+
+func (x *ReplayExchange) GetActual() []*mcp.McpMessage {
+	if x != nil {
+		return x.Actual
+	}
+	return nil
+}
+
+func (x *ReplayExchange) GetDifferences() []*ReplayDifference {
+	if x != nil {
+		return x.Differences
+	}
+	return nil
+}
+
+func (x *ReplayExchange) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ReplaySessionResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ReplaySessionId string                 `protobuf:"bytes,1,opt,name=replay_session_id,json=replaySessionId,proto3" json:"replay_session_id,omitempty"`
+	Exchanges       []*ReplayExchange      `protobuf:"bytes,2,rep,name=exchanges,proto3" json:"exchanges,omitempty"`
+	Matched         int32                  `protobuf:"varint,3,opt,name=matched,proto3" json:"matched,omitempty"`
+	Mismatched      int32                  `protobuf:"varint,4,opt,name=mismatched,proto3" json:"mismatched,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ReplaySessionResponse) Reset() {
+	*x = ReplaySessionResponse{}
+	mi := &file_manager_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplaySessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplaySessionResponse) ProtoMessage() {}
+
+func (x *ReplaySessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_manager_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplaySessionResponse.ProtoReflect.Descriptor instead.
+func (*ReplaySessionResponse) Descriptor() ([]byte, []int) {
+	return file_manager_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *ReplaySessionResponse) GetReplaySessionId() string {
+	if x != nil {
+		return x.ReplaySessionId
+	}
+	return ""
+}
+
+func (x *ReplaySessionResponse) GetExchanges() []*ReplayExchange {
+	if x != nil {
+		return x.Exchanges
+	}
+	return nil
+}
+
+func (x *ReplaySessionResponse) GetMatched() int32 {
+	if x != nil {
+		return x.Matched
+	}
+	return 0
+}
+
+func (x *ReplaySessionResponse) GetMismatched() int32 {
+	if x != nil {
+		return x.Mismatched
+	}
+	return 0
+}
+
 var File_manager_proto protoreflect.FileDescriptor
 
 const file_manager_proto_rawDesc = "" +
@@ -4610,7 +5176,27 @@ const file_manager_proto_rawDesc = "" +
 	"\x1dremote_run_config_with_server\x18\x04 \x01(\v2\x1e.broker.remote.RunConfigRemoteH\x00R\x19remoteRunConfigWithServer\x12s\n" +
 	"\x1flambda_run_config_with_launcher\x18\x05 \x01(\v2+.broker.manager.LambdaRunConfigWithLauncherH\x00R\x1blambdaRunConfigWithLauncher\x12b\n" +
 	"\x1dlambda_run_config_with_server\x18\x06 \x01(\v2\x1e.broker.remote.RunConfigLambdaH\x00R\x19lambdaRunConfigWithServerB\r\n" +
-	"\vconfig_type\"\xfc\x01\n" +
+	"\vconfig_type\"Z\n" +
+	"\x15DryRunLauncherRequest\x12A\n" +
+	"\rserver_config\x18\x01 \x01(\v2\x1c.broker.manager.ServerConfigR\fserverConfig\"\x85\x02\n" +
+	"\x0fDryRunRunConfig\x12L\n" +
+	"\x14container_run_config\x18\x01 \x01(\v2\x18.broker.runner.RunConfigH\x00R\x12containerRunConfig\x12L\n" +
+	"\x11remote_run_config\x18\x02 \x01(\v2\x1e.broker.remote.RunConfigRemoteH\x00R\x0fremoteRunConfig\x12L\n" +
+	"\x11lambda_run_config\x18\x03 \x01(\v2\x1e.broker.remote.RunConfigLambdaH\x00R\x0flambdaRunConfigB\b\n" +
+	"\x06config\"x\n" +
+	"\x15DryRunLauncherTimings\x12\x1f\n" +
+	"\vlauncher_ms\x18\x01 \x01(\x03R\n" +
+	"launcherMs\x12#\n" +
+	"\rvalidation_ms\x18\x02 \x01(\x03R\fvalidationMs\x12\x19\n" +
+	"\btotal_ms\x18\x03 \x01(\x03R\atotalMs\"\x9f\x02\n" +
+	"\x16DryRunLauncherResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12>\n" +
+	"\n" +
+	"run_config\x18\x02 \x01(\v2\x1f.broker.manager.DryRunRunConfigR\trunConfig\x12%\n" +
+	"\x0elauncher_error\x18\x03 \x01(\tR\rlauncherError\x12+\n" +
+	"\x11validation_errors\x18\x04 \x03(\tR\x10validationErrors\x12\x16\n" +
+	"\x06cached\x18\x05 \x01(\bR\x06cached\x12?\n" +
+	"\atimings\x18\x06 \x01(\v2%.broker.manager.DryRunLauncherTimingsR\atimings\"\xfc\x01\n" +
 	"\rSessionConfig\x12A\n" +
 	"\rserver_config\x18\x01 \x01(\v2\x1c.broker.manager.ServerConfigR\fserverConfig\x124\n" +
 	"\n" +
@@ -4928,7 +5514,33 @@ const file_manager_proto_rawDesc = "" +
 	"pagination\x88\x01\x01B\r\n" +
 	"\v_pagination\"M\n" +
 	"\x13ListServersResponse\x126\n" +
-	"\aservers\x18\x01 \x03(\v2\x1c.broker.manager.EngineServerR\aservers*\x9d\x01\n" +
+	"\aservers\x18\x01 \x03(\v2\x1c.broker.manager.EngineServerR\aservers\"\xf6\x01\n" +
+	"\x14ReplaySessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x125\n" +
+	"\x06config\x18\x02 \x01(\v2\x1d.broker.manager.SessionConfigR\x06config\x12-\n" +
+	"\x10docker_image_tag\x18\x03 \x01(\tH\x00R\x0edockerImageTag\x88\x01\x01\x12!\n" +
+	"\fignore_paths\x18\x04 \x03(\tR\vignorePaths\x12!\n" +
+	"\fkeep_session\x18\x05 \x01(\bR\vkeepSessionB\x13\n" +
+	"\x11_docker_image_tag\"l\n" +
+	"\x10ReplayDifference\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12#\n" +
+	"\rexpected_json\x18\x02 \x01(\tR\fexpectedJson\x12\x1f\n" +
+	"\vactual_json\x18\x03 \x01(\tR\n" +
+	"actualJson\"\x83\x02\n" +
+	"\x0eReplayExchange\x123\n" +
+	"\arequest\x18\x01 \x01(\v2\x19.broker.mcp.McpMessageRawR\arequest\x122\n" +
+	"\bexpected\x18\x02 \x03(\v2\x16.broker.mcp.McpMessageR\bexpected\x12.\n" +
+	"\x06actual\x18\x03 \x03(\v2\x16.broker.mcp.McpMessageR\x06actual\x12B\n" +
+	"\vdifferences\x18\x04 \x03(\v2 .broker.manager.ReplayDifferenceR\vdifferences\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"\xbb\x01\n" +
+	"\x15ReplaySessionResponse\x12*\n" +
+	"\x11replay_session_id\x18\x01 \x01(\tR\x0freplaySessionId\x12<\n" +
+	"\texchanges\x18\x02 \x03(\v2\x1e.broker.manager.ReplayExchangeR\texchanges\x12\x18\n" +
+	"\amatched\x18\x03 \x01(\x05R\amatched\x12\x1e\n" +
+	"\n" +
+	"mismatched\x18\x04 \x01(\x05R\n" +
+	"mismatched*\x9d\x01\n" +
 	"\x13EngineSessionStatus\x12\x19\n" +
 	"\x15session_status_active\x10\x00\x12\x19\n" +
 	"\x15session_status_closed\x10\x01\x12\x1a\n" +
@@ -4967,7 +5579,7 @@ const file_manager_proto_rawDesc = "" +
 	"\x1dsession_status_not_discovered\x10\x01*L\n" +
 	"\x13ListPaginationOrder\x12\x19\n" +
 	"\x15list_cursor_order_asc\x10\x00\x12\x1a\n" +
-	"\x16list_cursor_order_desc\x10\x012\x8c\x14\n" +
+	"\x16list_cursor_order_desc\x10\x012\xcb\x15\n" +
 	"\n" +
 	"McpManager\x12k\n" +
 	"\x12CheckActiveSession\x12).broker.manager.CheckActiveSessionRequest\x1a*.broker.manager.CheckActiveSessionResponse\x12\\\n" +
@@ -4998,7 +5610,9 @@ const file_manager_proto_rawDesc = "" +
 	"\x16ListRecentlyActiveRuns\x12-.broker.manager.ListRecentlyActiveRunsRequest\x1a..broker.manager.ListRecentlyActiveRunsResponse\x12\x83\x01\n" +
 	"\x1aListRecentlyActiveSessions\x121.broker.manager.ListRecentlyActiveSessionsRequest\x1a2.broker.manager.ListRecentlyActiveSessionsResponse\x12P\n" +
 	"\tGetServer\x12 .broker.manager.GetServerRequest\x1a!.broker.manager.GetServerResponse\x12V\n" +
-	"\vListServers\x12\".broker.manager.ListServersRequest\x1a#.broker.manager.ListServersResponseBHZFgithub.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager;managerb\x06proto3"
+	"\vListServers\x12\".broker.manager.ListServersRequest\x1a#.broker.manager.ListServersResponse\x12\\\n" +
+	"\rReplaySession\x12$.broker.manager.ReplaySessionRequest\x1a%.broker.manager.ReplaySessionResponse\x12_\n" +
+	"\x0eDryRunLauncher\x12%.broker.manager.DryRunLauncherRequest\x1a&.broker.manager.DryRunLauncherResponseBHZFgithub.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager;managerb\x06proto3"
 
 var (
 	file_manager_proto_rawDescOnce sync.Once
@@ -5013,7 +5627,7 @@ func file_manager_proto_rawDescGZIP() []byte {
 }
 
 var file_manager_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
-var file_manager_proto_msgTypes = make([]protoimpl.MessageInfo, 74)
+var file_manager_proto_msgTypes = make([]protoimpl.MessageInfo, 82)
 var file_manager_proto_goTypes = []any{
 	(EngineSessionStatus)(0),                   // 0: broker.manager.EngineSessionStatus
 	(EngineSessionType)(0),                     // 1: broker.manager.EngineSessionType
@@ -5034,242 +5648,266 @@ var file_manager_proto_goTypes = []any{
 	(*RemoteRunConfigWithLauncher)(nil),        // 16: broker.manager.RemoteRunConfigWithLauncher
 	(*LambdaRunConfigWithLauncher)(nil),        // 17: broker.manager.LambdaRunConfigWithLauncher
 	(*ServerConfig)(nil),                       // 18: broker.manager.ServerConfig
-	(*SessionConfig)(nil),                      // 19: broker.manager.SessionConfig
-	(*CreateSessionResponse)(nil),              // 20: broker.manager.CreateSessionResponse
-	(*DiscoverRequest)(nil),                    // 21: broker.manager.DiscoverRequest
-	(*SendMcpMessageRequest)(nil),              // 22: broker.manager.SendMcpMessageRequest
-	(*StreamMcpMessagesRequest)(nil),           // 23: broker.manager.StreamMcpMessagesRequest
-	(*SessionEventInfoRun)(nil),                // 24: broker.manager.SessionEventInfoRun
-	(*SessionEventInfoSession)(nil),            // 25: broker.manager.SessionEventInfoSession
-	(*SessionEventStartRun)(nil),               // 26: broker.manager.SessionEventStartRun
-	(*SessionEventStopRun)(nil),                // 27: broker.manager.SessionEventStopRun
-	(*SessionEvent)(nil),                       // 28: broker.manager.SessionEvent
-	(*McpConnectionStreamResponse)(nil),        // 29: broker.manager.McpConnectionStreamResponse
-	(*GetServerInfoRequest)(nil),               // 30: broker.manager.GetServerInfoRequest
-	(*ListWorkersRequest)(nil),                 // 31: broker.manager.ListWorkersRequest
-	(*ListWorkersResponse)(nil),                // 32: broker.manager.ListWorkersResponse
-	(*WorkerInfo)(nil),                         // 33: broker.manager.WorkerInfo
-	(*DiscardSessionRequest)(nil),              // 34: broker.manager.DiscardSessionRequest
-	(*DiscardSessionResponse)(nil),             // 35: broker.manager.DiscardSessionResponse
-	(*EngineSession)(nil),                      // 36: broker.manager.EngineSession
-	(*EngineSessionRun)(nil),                   // 37: broker.manager.EngineSessionRun
-	(*EngineSessionError)(nil),                 // 38: broker.manager.EngineSessionError
-	(*EngineSessionEvent)(nil),                 // 39: broker.manager.EngineSessionEvent
-	(*EngineSessionMessage)(nil),               // 40: broker.manager.EngineSessionMessage
-	(*EngineServer)(nil),                       // 41: broker.manager.EngineServer
-	(*ListPagination)(nil),                     // 42: broker.manager.ListPagination
-	(*ListSessionsRequest)(nil),                // 43: broker.manager.ListSessionsRequest
-	(*ListSessionsResponse)(nil),               // 44: broker.manager.ListSessionsResponse
-	(*GetSessionRequest)(nil),                  // 45: broker.manager.GetSessionRequest
-	(*GetSessionResponse)(nil),                 // 46: broker.manager.GetSessionResponse
-	(*ListRunsRequest)(nil),                    // 47: broker.manager.ListRunsRequest
-	(*ListRunsResponse)(nil),                   // 48: broker.manager.ListRunsResponse
-	(*GetRunRequest)(nil),                      // 49: broker.manager.GetRunRequest
-	(*GetRunResponse)(nil),                     // 50: broker.manager.GetRunResponse
-	(*GetErrorRequest)(nil),                    // 51: broker.manager.GetErrorRequest
-	(*GetErrorResponse)(nil),                   // 52: broker.manager.GetErrorResponse
-	(*GetEventRequest)(nil),                    // 53: broker.manager.GetEventRequest
-	(*GetEventResponse)(nil),                   // 54: broker.manager.GetEventResponse
-	(*GetMessageRequest)(nil),                  // 55: broker.manager.GetMessageRequest
-	(*GetMessageResponse)(nil),                 // 56: broker.manager.GetMessageResponse
-	(*ListRunErrorsRequest)(nil),               // 57: broker.manager.ListRunErrorsRequest
-	(*ListRunErrorsResponse)(nil),              // 58: broker.manager.ListRunErrorsResponse
-	(*ListRunEventsRequest)(nil),               // 59: broker.manager.ListRunEventsRequest
-	(*ListRunEventsResponse)(nil),              // 60: broker.manager.ListRunEventsResponse
-	(*ListRunMessagesRequest)(nil),             // 61: broker.manager.ListRunMessagesRequest
-	(*ListRunMessagesResponse)(nil),            // 62: broker.manager.ListRunMessagesResponse
-	(*ListSessionEventsRequest)(nil),           // 63: broker.manager.ListSessionEventsRequest
-	(*ListSessionEventsResponse)(nil),          // 64: broker.manager.ListSessionEventsResponse
-	(*ListSessionErrorsRequest)(nil),           // 65: broker.manager.ListSessionErrorsRequest
-	(*ListSessionErrorsResponse)(nil),          // 66: broker.manager.ListSessionErrorsResponse
-	(*ListSessionMessagesRequest)(nil),         // 67: broker.manager.ListSessionMessagesRequest
-	(*ListSessionMessagesResponse)(nil),        // 68: broker.manager.ListSessionMessagesResponse
-	(*ListRecentlyActiveRunsRequest)(nil),      // 69: broker.manager.ListRecentlyActiveRunsRequest
-	(*ListRecentlyActiveRunsResponse)(nil),     // 70: broker.manager.ListRecentlyActiveRunsResponse
-	(*ListRecentlyActiveSessionsRequest)(nil),  // 71: broker.manager.ListRecentlyActiveSessionsRequest
-	(*ListRecentlyActiveSessionsResponse)(nil), // 72: broker.manager.ListRecentlyActiveSessionsResponse
-	(*GetServerRequest)(nil),                   // 73: broker.manager.GetServerRequest
-	(*GetServerResponse)(nil),                  // 74: broker.manager.GetServerResponse
-	(*ListServersRequest)(nil),                 // 75: broker.manager.ListServersRequest
-	(*ListServersResponse)(nil),                // 76: broker.manager.ListServersResponse
-	nil,                                        // 77: broker.manager.CreateSessionRequest.MetadataEntry
-	nil,                                        // 78: broker.manager.EngineSessionError.MetadataEntry
-	nil,                                        // 79: broker.manager.EngineSessionEvent.MetadataEntry
-	nil,                                        // 80: broker.manager.EngineSessionMessage.MetadataEntry
-	nil,                                        // 81: broker.manager.EngineServer.MetadataEntry
-	(*mcp.McpParticipant)(nil),                 // 82: broker.mcp.McpParticipant
-	(*runner.RunConfigContainer)(nil),          // 83: broker.runner.RunConfigContainer
-	(*launcher.LauncherConfig)(nil),            // 84: broker.launcher.LauncherConfig
-	(*remote.RunConfigRemoteServer)(nil),       // 85: broker.remote.RunConfigRemoteServer
-	(*remote.RunConfigLambdaServer)(nil),       // 86: broker.remote.RunConfigLambdaServer
-	(*runner.RunConfig)(nil),                   // 87: broker.runner.RunConfig
-	(*remote.RunConfigRemote)(nil),             // 88: broker.remote.RunConfigRemote
-	(*remote.RunConfigLambda)(nil),             // 89: broker.remote.RunConfigLambda
-	(*mcp.McpConfig)(nil),                      // 90: broker.mcp.McpConfig
-	(*mcp.McpMessageRaw)(nil),                  // 91: broker.mcp.McpMessageRaw
-	(mcp.McpMessageType)(0),                    // 92: broker.mcp.McpMessageType
-	(*mcp.McpMessage)(nil),                     // 93: broker.mcp.McpMessage
-	(*mcp.McpError)(nil),                       // 94: broker.mcp.McpError
-	(*mcp.McpOutput)(nil),                      // 95: broker.mcp.McpOutput
-	(*mcp.McpTool)(nil),                        // 96: broker.mcp.McpTool
-	(*mcp.McpPrompt)(nil),                      // 97: broker.mcp.McpPrompt
-	(*mcp.McpResource)(nil),                    // 98: broker.mcp.McpResource
-	(*mcp.McpResourceTemplate)(nil),            // 99: broker.mcp.McpResourceTemplate
+	(*DryRunLauncherRequest)(nil),              // 19: broker.manager.DryRunLauncherRequest
+	(*DryRunRunConfig)(nil),                    // 20: broker.manager.DryRunRunConfig
+	(*DryRunLauncherTimings)(nil),              // 21: broker.manager.DryRunLauncherTimings
+	(*DryRunLauncherResponse)(nil),             // 22: broker.manager.DryRunLauncherResponse
+	(*SessionConfig)(nil),                      // 23: broker.manager.SessionConfig
+	(*CreateSessionResponse)(nil),              // 24: broker.manager.CreateSessionResponse
+	(*DiscoverRequest)(nil),                    // 25: broker.manager.DiscoverRequest
+	(*SendMcpMessageRequest)(nil),              // 26: broker.manager.SendMcpMessageRequest
+	(*StreamMcpMessagesRequest)(nil),           // 27: broker.manager.StreamMcpMessagesRequest
+	(*SessionEventInfoRun)(nil),                // 28: broker.manager.SessionEventInfoRun
+	(*SessionEventInfoSession)(nil),            // 29: broker.manager.SessionEventInfoSession
+	(*SessionEventStartRun)(nil),               // 30: broker.manager.SessionEventStartRun
+	(*SessionEventStopRun)(nil),                // 31: broker.manager.SessionEventStopRun
+	(*SessionEvent)(nil),                       // 32: broker.manager.SessionEvent
+	(*McpConnectionStreamResponse)(nil),        // 33: broker.manager.McpConnectionStreamResponse
+	(*GetServerInfoRequest)(nil),               // 34: broker.manager.GetServerInfoRequest
+	(*ListWorkersRequest)(nil),                 // 35: broker.manager.ListWorkersRequest
+	(*ListWorkersResponse)(nil),                // 36: broker.manager.ListWorkersResponse
+	(*WorkerInfo)(nil),                         // 37: broker.manager.WorkerInfo
+	(*DiscardSessionRequest)(nil),              // 38: broker.manager.DiscardSessionRequest
+	(*DiscardSessionResponse)(nil),             // 39: broker.manager.DiscardSessionResponse
+	(*EngineSession)(nil),                      // 40: broker.manager.EngineSession
+	(*EngineSessionRun)(nil),                   // 41: broker.manager.EngineSessionRun
+	(*EngineSessionError)(nil),                 // 42: broker.manager.EngineSessionError
+	(*EngineSessionEvent)(nil),                 // 43: broker.manager.EngineSessionEvent
+	(*EngineSessionMessage)(nil),               // 44: broker.manager.EngineSessionMessage
+	(*EngineServer)(nil),                       // 45: broker.manager.EngineServer
+	(*ListPagination)(nil),                     // 46: broker.manager.ListPagination
+	(*ListSessionsRequest)(nil),                // 47: broker.manager.ListSessionsRequest
+	(*ListSessionsResponse)(nil),               // 48: broker.manager.ListSessionsResponse
+	(*GetSessionRequest)(nil),                  // 49: broker.manager.GetSessionRequest
+	(*GetSessionResponse)(nil),                 // 50: broker.manager.GetSessionResponse
+	(*ListRunsRequest)(nil),                    // 51: broker.manager.ListRunsRequest
+	(*ListRunsResponse)(nil),                   // 52: broker.manager.ListRunsResponse
+	(*GetRunRequest)(nil),                      // 53: broker.manager.GetRunRequest
+	(*GetRunResponse)(nil),                     // 54: broker.manager.GetRunResponse
+	(*GetErrorRequest)(nil),                    // 55: broker.manager.GetErrorRequest
+	(*GetErrorResponse)(nil),                   // 56: broker.manager.GetErrorResponse
+	(*GetEventRequest)(nil),                    // 57: broker.manager.GetEventRequest
+	(*GetEventResponse)(nil),                   // 58: broker.manager.GetEventResponse
+	(*GetMessageRequest)(nil),                  // 59: broker.manager.GetMessageRequest
+	(*GetMessageResponse)(nil),                 // 60: broker.manager.GetMessageResponse
+	(*ListRunErrorsRequest)(nil),               // 61: broker.manager.ListRunErrorsRequest
+	(*ListRunErrorsResponse)(nil),              // 62: broker.manager.ListRunErrorsResponse
+	(*ListRunEventsRequest)(nil),               // 63: broker.manager.ListRunEventsRequest
+	(*ListRunEventsResponse)(nil),              // 64: broker.manager.ListRunEventsResponse
+	(*ListRunMessagesRequest)(nil),             // 65: broker.manager.ListRunMessagesRequest
+	(*ListRunMessagesResponse)(nil),            // 66: broker.manager.ListRunMessagesResponse
+	(*ListSessionEventsRequest)(nil),           // 67: broker.manager.ListSessionEventsRequest
+	(*ListSessionEventsResponse)(nil),          // 68: broker.manager.ListSessionEventsResponse
+	(*ListSessionErrorsRequest)(nil),           // 69: broker.manager.ListSessionErrorsRequest
+	(*ListSessionErrorsResponse)(nil),          // 70: broker.manager.ListSessionErrorsResponse
+	(*ListSessionMessagesRequest)(nil),         // 71: broker.manager.ListSessionMessagesRequest
+	(*ListSessionMessagesResponse)(nil),        // 72: broker.manager.ListSessionMessagesResponse
+	(*ListRecentlyActiveRunsRequest)(nil),      // 73: broker.manager.ListRecentlyActiveRunsRequest
+	(*ListRecentlyActiveRunsResponse)(nil),     // 74: broker.manager.ListRecentlyActiveRunsResponse
+	(*ListRecentlyActiveSessionsRequest)(nil),  // 75: broker.manager.ListRecentlyActiveSessionsRequest
+	(*ListRecentlyActiveSessionsResponse)(nil), // 76: broker.manager.ListRecentlyActiveSessionsResponse
+	(*GetServerRequest)(nil),                   // 77: broker.manager.GetServerRequest
+	(*GetServerResponse)(nil),                  // 78: broker.manager.GetServerResponse
+	(*ListServersRequest)(nil),                 // 79: broker.manager.ListServersRequest
+	(*ListServersResponse)(nil),                // 80: broker.manager.ListServersResponse
+	(*ReplaySessionRequest)(nil),               // 81: broker.manager.ReplaySessionRequest
+	(*ReplayDifference)(nil),                   // 82: broker.manager.ReplayDifference
+	(*ReplayExchange)(nil),                     // 83: broker.manager.ReplayExchange
+	(*ReplaySessionResponse)(nil),              // 84: broker.manager.ReplaySessionResponse
+	nil,                                        // 85: broker.manager.CreateSessionRequest.MetadataEntry
+	nil,                                        // 86: broker.manager.EngineSessionError.MetadataEntry
+	nil,                                        // 87: broker.manager.EngineSessionEvent.MetadataEntry
+	nil,                                        // 88: broker.manager.EngineSessionMessage.MetadataEntry
+	nil,                                        // 89: broker.manager.EngineServer.MetadataEntry
+	(*mcp.McpParticipant)(nil),                 // 90: broker.mcp.McpParticipant
+	(*runner.RunConfigContainer)(nil),          // 91: broker.runner.RunConfigContainer
+	(*launcher.LauncherConfig)(nil),            // 92: broker.launcher.LauncherConfig
+	(*remote.RunConfigRemoteServer)(nil),       // 93: broker.remote.RunConfigRemoteServer
+	(*remote.RunConfigLambdaServer)(nil),       // 94: broker.remote.RunConfigLambdaServer
+	(*runner.RunConfig)(nil),                   // 95: broker.runner.RunConfig
+	(*remote.RunConfigRemote)(nil),             // 96: broker.remote.RunConfigRemote
+	(*remote.RunConfigLambda)(nil),             // 97: broker.remote.RunConfigLambda
+	(*mcp.McpConfig)(nil),                      // 98: broker.mcp.McpConfig
+	(*mcp.McpMessageRaw)(nil),                  // 99: broker.mcp.McpMessageRaw
+	(mcp.McpMessageType)(0),                    // 100: broker.mcp.McpMessageType
+	(*mcp.McpMessage)(nil),                     // 101: broker.mcp.McpMessage
+	(*mcp.McpError)(nil),                       // 102: broker.mcp.McpError
+	(*mcp.McpOutput)(nil),                      // 103: broker.mcp.McpOutput
+	(*mcp.McpTool)(nil),                        // 104: broker.mcp.McpTool
+	(*mcp.McpPrompt)(nil),                      // 105: broker.mcp.McpPrompt
+	(*mcp.McpResource)(nil),                    // 106: broker.mcp.McpResource
+	(*mcp.McpResourceTemplate)(nil),            // 107: broker.mcp.McpResourceTemplate
 }
 var file_manager_proto_depIdxs = []int32{
 	10,  // 0: broker.manager.ListManagersResponse.managers:type_name -> broker.manager.Manager
-	36,  // 1: broker.manager.CheckActiveSessionResponse.session:type_name -> broker.manager.EngineSession
-	19,  // 2: broker.manager.CreateSessionRequest.config:type_name -> broker.manager.SessionConfig
-	82,  // 3: broker.manager.CreateSessionRequest.mcp_client:type_name -> broker.mcp.McpParticipant
-	77,  // 4: broker.manager.CreateSessionRequest.metadata:type_name -> broker.manager.CreateSessionRequest.MetadataEntry
-	83,  // 5: broker.manager.ContainerRunConfigWithLauncher.container:type_name -> broker.runner.RunConfigContainer
-	84,  // 6: broker.manager.ContainerRunConfigWithLauncher.launcher:type_name -> broker.launcher.LauncherConfig
-	85,  // 7: broker.manager.RemoteRunConfigWithLauncher.server:type_name -> broker.remote.RunConfigRemoteServer
-	84,  // 8: broker.manager.RemoteRunConfigWithLauncher.launcher:type_name -> broker.launcher.LauncherConfig
-	86,  // 9: broker.manager.LambdaRunConfigWithLauncher.server:type_name -> broker.remote.RunConfigLambdaServer
-	84,  // 10: broker.manager.LambdaRunConfigWithLauncher.launcher:type_name -> broker.launcher.LauncherConfig
+	40,  // 1: broker.manager.CheckActiveSessionResponse.session:type_name -> broker.manager.EngineSession
+	23,  // 2: broker.manager.CreateSessionRequest.config:type_name -> broker.manager.SessionConfig
+	90,  // 3: broker.manager.CreateSessionRequest.mcp_client:type_name -> broker.mcp.McpParticipant
+	85,  // 4: broker.manager.CreateSessionRequest.metadata:type_name -> broker.manager.CreateSessionRequest.MetadataEntry
+	91,  // 5: broker.manager.ContainerRunConfigWithLauncher.container:type_name -> broker.runner.RunConfigContainer
+	92,  // 6: broker.manager.ContainerRunConfigWithLauncher.launcher:type_name -> broker.launcher.LauncherConfig
+	93,  // 7: broker.manager.RemoteRunConfigWithLauncher.server:type_name -> broker.remote.RunConfigRemoteServer
+	92,  // 8: broker.manager.RemoteRunConfigWithLauncher.launcher:type_name -> broker.launcher.LauncherConfig
+	94,  // 9: broker.manager.LambdaRunConfigWithLauncher.server:type_name -> broker.remote.RunConfigLambdaServer
+	92,  // 10: broker.manager.LambdaRunConfigWithLauncher.launcher:type_name -> broker.launcher.LauncherConfig
 	15,  // 11: broker.manager.ServerConfig.container_run_config_with_launcher:type_name -> broker.manager.ContainerRunConfigWithLauncher
-	87,  // 12: broker.manager.ServerConfig.container_run_config_with_container_arguments:type_name -> broker.runner.RunConfig
+	95,  // 12: broker.manager.ServerConfig.container_run_config_with_container_arguments:type_name -> broker.runner.RunConfig
 	16,  // 13: broker.manager.ServerConfig.remote_run_config_with_launcher:type_name -> broker.manager.RemoteRunConfigWithLauncher
-	88,  // 14: broker.manager.ServerConfig.remote_run_config_with_server:type_name -> broker.remote.RunConfigRemote
+	96,  // 14: broker.manager.ServerConfig.remote_run_config_with_server:type_name -> broker.remote.RunConfigRemote
 	17,  // 15: broker.manager.ServerConfig.lambda_run_config_with_launcher:type_name -> broker.manager.LambdaRunConfigWithLauncher
-	89,  // 16: broker.manager.ServerConfig.lambda_run_config_with_server:type_name -> broker.remote.RunConfigLambda
-	18,  // 17: broker.manager.SessionConfig.server_config:type_name -> broker.manager.ServerConfig
-	90,  // 18: broker.manager.SessionConfig.mcp_config:type_name -> broker.mcp.McpConfig
-	13,  // 19: broker.manager.SessionConfig.stateful_server_info:type_name -> broker.manager.StatefulServerInfo
-	36,  // 20: broker.manager.CreateSessionResponse.session:type_name -> broker.manager.EngineSession
-	18,  // 21: broker.manager.DiscoverRequest.server_config:type_name -> broker.manager.ServerConfig
-	91,  // 22: broker.manager.SendMcpMessageRequest.mcp_messages:type_name -> broker.mcp.McpMessageRaw
-	92,  // 23: broker.manager.StreamMcpMessagesRequest.only_message_types:type_name -> broker.mcp.McpMessageType
-	37,  // 24: broker.manager.SessionEventInfoRun.run:type_name -> broker.manager.EngineSessionRun
-	36,  // 25: broker.manager.SessionEventInfoSession.session:type_name -> broker.manager.EngineSession
-	37,  // 26: broker.manager.SessionEventStartRun.run:type_name -> broker.manager.EngineSessionRun
-	37,  // 27: broker.manager.SessionEventStopRun.run:type_name -> broker.manager.EngineSessionRun
-	26,  // 28: broker.manager.SessionEvent.start_run:type_name -> broker.manager.SessionEventStartRun
-	27,  // 29: broker.manager.SessionEvent.stop_run:type_name -> broker.manager.SessionEventStopRun
-	24,  // 30: broker.manager.SessionEvent.info_run:type_name -> broker.manager.SessionEventInfoRun
-	25,  // 31: broker.manager.SessionEvent.info_session:type_name -> broker.manager.SessionEventInfoSession
-	93,  // 32: broker.manager.McpConnectionStreamResponse.mcp_message:type_name -> broker.mcp.McpMessage
-	94,  // 33: broker.manager.McpConnectionStreamResponse.mcp_error:type_name -> broker.mcp.McpError
-	95,  // 34: broker.manager.McpConnectionStreamResponse.mcp_output:type_name -> broker.mcp.McpOutput
-	28,  // 35: broker.manager.McpConnectionStreamResponse.session_event:type_name -> broker.manager.SessionEvent
-	33,  // 36: broker.manager.ListWorkersResponse.workers:type_name -> broker.manager.WorkerInfo
-	1,   // 37: broker.manager.EngineSession.type:type_name -> broker.manager.EngineSessionType
-	0,   // 38: broker.manager.EngineSession.status:type_name -> broker.manager.EngineSessionStatus
-	82,  // 39: broker.manager.EngineSession.mcp_client:type_name -> broker.mcp.McpParticipant
-	82,  // 40: broker.manager.EngineSession.mcp_server:type_name -> broker.mcp.McpParticipant
-	41,  // 41: broker.manager.EngineSession.server:type_name -> broker.manager.EngineServer
-	90,  // 42: broker.manager.EngineSession.mcp_config:type_name -> broker.mcp.McpConfig
-	3,   // 43: broker.manager.EngineSessionRun.type:type_name -> broker.manager.EngineRunType
-	2,   // 44: broker.manager.EngineSessionRun.status:type_name -> broker.manager.EngineRunStatus
-	36,  // 45: broker.manager.EngineSessionRun.session:type_name -> broker.manager.EngineSession
-	37,  // 46: broker.manager.EngineSessionError.run:type_name -> broker.manager.EngineSessionRun
-	36,  // 47: broker.manager.EngineSessionError.session:type_name -> broker.manager.EngineSession
-	94,  // 48: broker.manager.EngineSessionError.mcp_error:type_name -> broker.mcp.McpError
-	78,  // 49: broker.manager.EngineSessionError.metadata:type_name -> broker.manager.EngineSessionError.MetadataEntry
-	4,   // 50: broker.manager.EngineSessionEvent.type:type_name -> broker.manager.EngineSessionEventType
-	37,  // 51: broker.manager.EngineSessionEvent.run:type_name -> broker.manager.EngineSessionRun
-	36,  // 52: broker.manager.EngineSessionEvent.session:type_name -> broker.manager.EngineSession
-	38,  // 53: broker.manager.EngineSessionEvent.error:type_name -> broker.manager.EngineSessionError
-	79,  // 54: broker.manager.EngineSessionEvent.metadata:type_name -> broker.manager.EngineSessionEvent.MetadataEntry
-	95,  // 55: broker.manager.EngineSessionEvent.mcp_output:type_name -> broker.mcp.McpOutput
-	5,   // 56: broker.manager.EngineSessionMessage.sender:type_name -> broker.manager.SessionMessageSender
-	37,  // 57: broker.manager.EngineSessionMessage.run:type_name -> broker.manager.EngineSessionRun
-	36,  // 58: broker.manager.EngineSessionMessage.session:type_name -> broker.manager.EngineSession
-	93,  // 59: broker.manager.EngineSessionMessage.mcp_message:type_name -> broker.mcp.McpMessage
-	80,  // 60: broker.manager.EngineSessionMessage.metadata:type_name -> broker.manager.EngineSessionMessage.MetadataEntry
-	1,   // 61: broker.manager.EngineServer.type:type_name -> broker.manager.EngineSessionType
-	6,   // 62: broker.manager.EngineServer.status:type_name -> broker.manager.EngineServerStatus
-	82,  // 63: broker.manager.EngineServer.mcp_server:type_name -> broker.mcp.McpParticipant
-	96,  // 64: broker.manager.EngineServer.tools:type_name -> broker.mcp.McpTool
-	97,  // 65: broker.manager.EngineServer.prompts:type_name -> broker.mcp.McpPrompt
-	98,  // 66: broker.manager.EngineServer.resources:type_name -> broker.mcp.McpResource
-	99,  // 67: broker.manager.EngineServer.resource_templates:type_name -> broker.mcp.McpResourceTemplate
-	81,  // 68: broker.manager.EngineServer.metadata:type_name -> broker.manager.EngineServer.MetadataEntry
-	7,   // 69: broker.manager.ListPagination.order:type_name -> broker.manager.ListPaginationOrder
-	42,  // 70: broker.manager.ListSessionsRequest.pagination:type_name -> broker.manager.ListPagination
-	36,  // 71: broker.manager.ListSessionsResponse.sessions:type_name -> broker.manager.EngineSession
-	36,  // 72: broker.manager.GetSessionResponse.session:type_name -> broker.manager.EngineSession
-	42,  // 73: broker.manager.ListRunsRequest.pagination:type_name -> broker.manager.ListPagination
-	37,  // 74: broker.manager.ListRunsResponse.runs:type_name -> broker.manager.EngineSessionRun
-	37,  // 75: broker.manager.GetRunResponse.run:type_name -> broker.manager.EngineSessionRun
-	38,  // 76: broker.manager.GetErrorResponse.error:type_name -> broker.manager.EngineSessionError
-	39,  // 77: broker.manager.GetEventResponse.event:type_name -> broker.manager.EngineSessionEvent
-	40,  // 78: broker.manager.GetMessageResponse.message:type_name -> broker.manager.EngineSessionMessage
-	42,  // 79: broker.manager.ListRunErrorsRequest.pagination:type_name -> broker.manager.ListPagination
-	38,  // 80: broker.manager.ListRunErrorsResponse.errors:type_name -> broker.manager.EngineSessionError
-	42,  // 81: broker.manager.ListRunEventsRequest.pagination:type_name -> broker.manager.ListPagination
-	39,  // 82: broker.manager.ListRunEventsResponse.events:type_name -> broker.manager.EngineSessionEvent
-	42,  // 83: broker.manager.ListRunMessagesRequest.pagination:type_name -> broker.manager.ListPagination
-	40,  // 84: broker.manager.ListRunMessagesResponse.messages:type_name -> broker.manager.EngineSessionMessage
-	42,  // 85: broker.manager.ListSessionEventsRequest.pagination:type_name -> broker.manager.ListPagination
-	39,  // 86: broker.manager.ListSessionEventsResponse.events:type_name -> broker.manager.EngineSessionEvent
-	42,  // 87: broker.manager.ListSessionErrorsRequest.pagination:type_name -> broker.manager.ListPagination
-	38,  // 88: broker.manager.ListSessionErrorsResponse.errors:type_name -> broker.manager.EngineSessionError
-	42,  // 89: broker.manager.ListSessionMessagesRequest.pagination:type_name -> broker.manager.ListPagination
-	40,  // 90: broker.manager.ListSessionMessagesResponse.messages:type_name -> broker.manager.EngineSessionMessage
-	41,  // 91: broker.manager.GetServerResponse.server:type_name -> broker.manager.EngineServer
-	42,  // 92: broker.manager.ListServersRequest.pagination:type_name -> broker.manager.ListPagination
-	41,  // 93: broker.manager.ListServersResponse.servers:type_name -> broker.manager.EngineServer
-	11,  // 94: broker.manager.McpManager.CheckActiveSession:input_type -> broker.manager.CheckActiveSessionRequest
-	14,  // 95: broker.manager.McpManager.CreateSession:input_type -> broker.manager.CreateSessionRequest
-	21,  // 96: broker.manager.McpManager.DiscoverServer:input_type -> broker.manager.DiscoverRequest
-	34,  // 97: broker.manager.McpManager.DiscardSession:input_type -> broker.manager.DiscardSessionRequest
-	22,  // 98: broker.manager.McpManager.SendMcpMessage:input_type -> broker.manager.SendMcpMessageRequest
-	23,  // 99: broker.manager.McpManager.StreamMcpMessages:input_type -> broker.manager.StreamMcpMessagesRequest
-	30,  // 100: broker.manager.McpManager.GetServerInfo:input_type -> broker.manager.GetServerInfoRequest
-	8,   // 101: broker.manager.McpManager.ListManagers:input_type -> broker.manager.ListManagersRequest
-	31,  // 102: broker.manager.McpManager.ListWorkers:input_type -> broker.manager.ListWorkersRequest
-	43,  // 103: broker.manager.McpManager.ListSessions:input_type -> broker.manager.ListSessionsRequest
-	45,  // 104: broker.manager.McpManager.GetSession:input_type -> broker.manager.GetSessionRequest
-	45,  // 105: broker.manager.McpManager.GetSessionServer:input_type -> broker.manager.GetSessionRequest
-	47,  // 106: broker.manager.McpManager.ListRuns:input_type -> broker.manager.ListRunsRequest
-	49,  // 107: broker.manager.McpManager.GetRun:input_type -> broker.manager.GetRunRequest
-	65,  // 108: broker.manager.McpManager.ListSessionErrors:input_type -> broker.manager.ListSessionErrorsRequest
-	63,  // 109: broker.manager.McpManager.ListSessionEvents:input_type -> broker.manager.ListSessionEventsRequest
-	67,  // 110: broker.manager.McpManager.ListSessionMessages:input_type -> broker.manager.ListSessionMessagesRequest
-	57,  // 111: broker.manager.McpManager.ListRunErrors:input_type -> broker.manager.ListRunErrorsRequest
-	59,  // 112: broker.manager.McpManager.ListRunEvents:input_type -> broker.manager.ListRunEventsRequest
-	61,  // 113: broker.manager.McpManager.ListRunMessages:input_type -> broker.manager.ListRunMessagesRequest
-	51,  // 114: broker.manager.McpManager.GetError:input_type -> broker.manager.GetErrorRequest
-	53,  // 115: broker.manager.McpManager.GetEvent:input_type -> broker.manager.GetEventRequest
-	55,  // 116: broker.manager.McpManager.GetMessage:input_type -> broker.manager.GetMessageRequest
-	69,  // 117: broker.manager.McpManager.ListRecentlyActiveRuns:input_type -> broker.manager.ListRecentlyActiveRunsRequest
-	71,  // 118: broker.manager.McpManager.ListRecentlyActiveSessions:input_type -> broker.manager.ListRecentlyActiveSessionsRequest
-	73,  // 119: broker.manager.McpManager.GetServer:input_type -> broker.manager.GetServerRequest
-	75,  // 120: broker.manager.McpManager.ListServers:input_type -> broker.manager.ListServersRequest
-	12,  // 121: broker.manager.McpManager.CheckActiveSession:output_type -> broker.manager.CheckActiveSessionResponse
-	20,  // 122: broker.manager.McpManager.CreateSession:output_type -> broker.manager.CreateSessionResponse
-	74,  // 123: broker.manager.McpManager.DiscoverServer:output_type -> broker.manager.GetServerResponse
-	35,  // 124: broker.manager.McpManager.DiscardSession:output_type -> broker.manager.DiscardSessionResponse
-	29,  // 125: broker.manager.McpManager.SendMcpMessage:output_type -> broker.manager.McpConnectionStreamResponse
-	29,  // 126: broker.manager.McpManager.StreamMcpMessages:output_type -> broker.manager.McpConnectionStreamResponse
-	82,  // 127: broker.manager.McpManager.GetServerInfo:output_type -> broker.mcp.McpParticipant
-	9,   // 128: broker.manager.McpManager.ListManagers:output_type -> broker.manager.ListManagersResponse
-	32,  // 129: broker.manager.McpManager.ListWorkers:output_type -> broker.manager.ListWorkersResponse
-	44,  // 130: broker.manager.McpManager.ListSessions:output_type -> broker.manager.ListSessionsResponse
-	46,  // 131: broker.manager.McpManager.GetSession:output_type -> broker.manager.GetSessionResponse
-	74,  // 132: broker.manager.McpManager.GetSessionServer:output_type -> broker.manager.GetServerResponse
-	48,  // 133: broker.manager.McpManager.ListRuns:output_type -> broker.manager.ListRunsResponse
-	50,  // 134: broker.manager.McpManager.GetRun:output_type -> broker.manager.GetRunResponse
-	66,  // 135: broker.manager.McpManager.ListSessionErrors:output_type -> broker.manager.ListSessionErrorsResponse
-	64,  // 136: broker.manager.McpManager.ListSessionEvents:output_type -> broker.manager.ListSessionEventsResponse
-	68,  // 137: broker.manager.McpManager.ListSessionMessages:output_type -> broker.manager.ListSessionMessagesResponse
-	58,  // 138: broker.manager.McpManager.ListRunErrors:output_type -> broker.manager.ListRunErrorsResponse
-	60,  // 139: broker.manager.McpManager.ListRunEvents:output_type -> broker.manager.ListRunEventsResponse
-	62,  // 140: broker.manager.McpManager.ListRunMessages:output_type -> broker.manager.ListRunMessagesResponse
-	52,  // 141: broker.manager.McpManager.GetError:output_type -> broker.manager.GetErrorResponse
-	54,  // 142: broker.manager.McpManager.GetEvent:output_type -> broker.manager.GetEventResponse
-	56,  // 143: broker.manager.McpManager.GetMessage:output_type -> broker.manager.GetMessageResponse
-	70,  // 144: broker.manager.McpManager.ListRecentlyActiveRuns:output_type -> broker.manager.ListRecentlyActiveRunsResponse
-	72,  // 145: broker.manager.McpManager.ListRecentlyActiveSessions:output_type -> broker.manager.ListRecentlyActiveSessionsResponse
-	74,  // 146: broker.manager.McpManager.GetServer:output_type -> broker.manager.GetServerResponse
-	76,  // 147: broker.manager.McpManager.ListServers:output_type -> broker.manager.ListServersResponse
-	121, // [121:148] is the sub-list for method output_type
-	94,  // [94:121] is the sub-list for method input_type
-	94,  // [94:94] is the sub-list for extension type_name
-	94,  // [94:94] is the sub-list for extension extendee
-	0,   // [0:94] is the sub-list for field type_name
+	97,  // 16: broker.manager.ServerConfig.lambda_run_config_with_server:type_name -> broker.remote.RunConfigLambda
+	18,  // 17: broker.manager.DryRunLauncherRequest.server_config:type_name -> broker.manager.ServerConfig
+	95,  // 18: broker.manager.DryRunRunConfig.container_run_config:type_name -> broker.runner.RunConfig
+	96,  // 19: broker.manager.DryRunRunConfig.remote_run_config:type_name -> broker.remote.RunConfigRemote
+	97,  // 20: broker.manager.DryRunRunConfig.lambda_run_config:type_name -> broker.remote.RunConfigLambda
+	20,  // 21: broker.manager.DryRunLauncherResponse.run_config:type_name -> broker.manager.DryRunRunConfig
+	21,  // 22: broker.manager.DryRunLauncherResponse.timings:type_name -> broker.manager.DryRunLauncherTimings
+	18,  // 23: broker.manager.SessionConfig.server_config:type_name -> broker.manager.ServerConfig
+	98,  // 24: broker.manager.SessionConfig.mcp_config:type_name -> broker.mcp.McpConfig
+	13,  // 25: broker.manager.SessionConfig.stateful_server_info:type_name -> broker.manager.StatefulServerInfo
+	40,  // 26: broker.manager.CreateSessionResponse.session:type_name -> broker.manager.EngineSession
+	18,  // 27: broker.manager.DiscoverRequest.server_config:type_name -> broker.manager.ServerConfig
+	99,  // 28: broker.manager.SendMcpMessageRequest.mcp_messages:type_name -> broker.mcp.McpMessageRaw
+	100, // 29: broker.manager.StreamMcpMessagesRequest.only_message_types:type_name -> broker.mcp.McpMessageType
+	41,  // 30: broker.manager.SessionEventInfoRun.run:type_name -> broker.manager.EngineSessionRun
+	40,  // 31: broker.manager.SessionEventInfoSession.session:type_name -> broker.manager.EngineSession
+	41,  // 32: broker.manager.SessionEventStartRun.run:type_name -> broker.manager.EngineSessionRun
+	41,  // 33: broker.manager.SessionEventStopRun.run:type_name -> broker.manager.EngineSessionRun
+	30,  // 34: broker.manager.SessionEvent.start_run:type_name -> broker.manager.SessionEventStartRun
+	31,  // 35: broker.manager.SessionEvent.stop_run:type_name -> broker.manager.SessionEventStopRun
+	28,  // 36: broker.manager.SessionEvent.info_run:type_name -> broker.manager.SessionEventInfoRun
+	29,  // 37: broker.manager.SessionEvent.info_session:type_name -> broker.manager.SessionEventInfoSession
+	101, // 38: broker.manager.McpConnectionStreamResponse.mcp_message:type_name -> broker.mcp.McpMessage
+	102, // 39: broker.manager.McpConnectionStreamResponse.mcp_error:type_name -> broker.mcp.McpError
+	103, // 40: broker.manager.McpConnectionStreamResponse.mcp_output:type_name -> broker.mcp.McpOutput
+	32,  // 41: broker.manager.McpConnectionStreamResponse.session_event:type_name -> broker.manager.SessionEvent
+	37,  // 42: broker.manager.ListWorkersResponse.workers:type_name -> broker.manager.WorkerInfo
+	1,   // 43: broker.manager.EngineSession.type:type_name -> broker.manager.EngineSessionType
+	0,   // 44: broker.manager.EngineSession.status:type_name -> broker.manager.EngineSessionStatus
+	90,  // 45: broker.manager.EngineSession.mcp_client:type_name -> broker.mcp.McpParticipant
+	90,  // 46: broker.manager.EngineSession.mcp_server:type_name -> broker.mcp.McpParticipant
+	45,  // 47: broker.manager.EngineSession.server:type_name -> broker.manager.EngineServer
+	98,  // 48: broker.manager.EngineSession.mcp_config:type_name -> broker.mcp.McpConfig
+	3,   // 49: broker.manager.EngineSessionRun.type:type_name -> broker.manager.EngineRunType
+	2,   // 50: broker.manager.EngineSessionRun.status:type_name -> broker.manager.EngineRunStatus
+	40,  // 51: broker.manager.EngineSessionRun.session:type_name -> broker.manager.EngineSession
+	41,  // 52: broker.manager.EngineSessionError.run:type_name -> broker.manager.EngineSessionRun
+	40,  // 53: broker.manager.EngineSessionError.session:type_name -> broker.manager.EngineSession
+	102, // 54: broker.manager.EngineSessionError.mcp_error:type_name -> broker.mcp.McpError
+	86,  // 55: broker.manager.EngineSessionError.metadata:type_name -> broker.manager.EngineSessionError.MetadataEntry
+	4,   // 56: broker.manager.EngineSessionEvent.type:type_name -> broker.manager.EngineSessionEventType
+	41,  // 57: broker.manager.EngineSessionEvent.run:type_name -> broker.manager.EngineSessionRun
+	40,  // 58: broker.manager.EngineSessionEvent.session:type_name -> broker.manager.EngineSession
+	42,  // 59: broker.manager.EngineSessionEvent.error:type_name -> broker.manager.EngineSessionError
+	87,  // 60: broker.manager.EngineSessionEvent.metadata:type_name -> broker.manager.EngineSessionEvent.MetadataEntry
+	103, // 61: broker.manager.EngineSessionEvent.mcp_output:type_name -> broker.mcp.McpOutput
+	5,   // 62: broker.manager.EngineSessionMessage.sender:type_name -> broker.manager.SessionMessageSender
+	41,  // 63: broker.manager.EngineSessionMessage.run:type_name -> broker.manager.EngineSessionRun
+	40,  // 64: broker.manager.EngineSessionMessage.session:type_name -> broker.manager.EngineSession
+	101, // 65: broker.manager.EngineSessionMessage.mcp_message:type_name -> broker.mcp.McpMessage
+	88,  // 66: broker.manager.EngineSessionMessage.metadata:type_name -> broker.manager.EngineSessionMessage.MetadataEntry
+	1,   // 67: broker.manager.EngineServer.type:type_name -> broker.manager.EngineSessionType
+	6,   // 68: broker.manager.EngineServer.status:type_name -> broker.manager.EngineServerStatus
+	90,  // 69: broker.manager.EngineServer.mcp_server:type_name -> broker.mcp.McpParticipant
+	104, // 70: broker.manager.EngineServer.tools:type_name -> broker.mcp.McpTool
+	105, // 71: broker.manager.EngineServer.prompts:type_name -> broker.mcp.McpPrompt
+	106, // 72: broker.manager.EngineServer.resources:type_name -> broker.mcp.McpResource
+	107, // 73: broker.manager.EngineServer.resource_templates:type_name -> broker.mcp.McpResourceTemplate
+	89,  // 74: broker.manager.EngineServer.metadata:type_name -> broker.manager.EngineServer.MetadataEntry
+	7,   // 75: broker.manager.ListPagination.order:type_name -> broker.manager.ListPaginationOrder
+	46,  // 76: broker.manager.ListSessionsRequest.pagination:type_name -> broker.manager.ListPagination
+	40,  // 77: broker.manager.ListSessionsResponse.sessions:type_name -> broker.manager.EngineSession
+	40,  // 78: broker.manager.GetSessionResponse.session:type_name -> broker.manager.EngineSession
+	46,  // 79: broker.manager.ListRunsRequest.pagination:type_name -> broker.manager.ListPagination
+	41,  // 80: broker.manager.ListRunsResponse.runs:type_name -> broker.manager.EngineSessionRun
+	41,  // 81: broker.manager.GetRunResponse.run:type_name -> broker.manager.EngineSessionRun
+	42,  // 82: broker.manager.GetErrorResponse.error:type_name -> broker.manager.EngineSessionError
+	43,  // 83: broker.manager.GetEventResponse.event:type_name -> broker.manager.EngineSessionEvent
+	44,  // 84: broker.manager.GetMessageResponse.message:type_name -> broker.manager.EngineSessionMessage
+	46,  // 85: broker.manager.ListRunErrorsRequest.pagination:type_name -> broker.manager.ListPagination
+	42,  // 86: broker.manager.ListRunErrorsResponse.errors:type_name -> broker.manager.EngineSessionError
+	46,  // 87: broker.manager.ListRunEventsRequest.pagination:type_name -> broker.manager.ListPagination
+	43,  // 88: broker.manager.ListRunEventsResponse.events:type_name -> broker.manager.EngineSessionEvent
+	46,  // 89: broker.manager.ListRunMessagesRequest.pagination:type_name -> broker.manager.ListPagination
+	44,  // 90: broker.manager.ListRunMessagesResponse.messages:type_name -> broker.manager.EngineSessionMessage
+	46,  // 91: broker.manager.ListSessionEventsRequest.pagination:type_name -> broker.manager.ListPagination
+	43,  // 92: broker.manager.ListSessionEventsResponse.events:type_name -> broker.manager.EngineSessionEvent
+	46,  // 93: broker.manager.ListSessionErrorsRequest.pagination:type_name -> broker.manager.ListPagination
+	42,  // 94: broker.manager.ListSessionErrorsResponse.errors:type_name -> broker.manager.EngineSessionError
+	46,  // 95: broker.manager.ListSessionMessagesRequest.pagination:type_name -> broker.manager.ListPagination
+	44,  // 96: broker.manager.ListSessionMessagesResponse.messages:type_name -> broker.manager.EngineSessionMessage
+	45,  // 97: broker.manager.GetServerResponse.server:type_name -> broker.manager.EngineServer
+	46,  // 98: broker.manager.ListServersRequest.pagination:type_name -> broker.manager.ListPagination
+	45,  // 99: broker.manager.ListServersResponse.servers:type_name -> broker.manager.EngineServer
+	23,  // 100: broker.manager.ReplaySessionRequest.config:type_name -> broker.manager.SessionConfig
+	99,  // 101: broker.manager.ReplayExchange.request:type_name -> broker.mcp.McpMessageRaw
+	101, // 102: broker.manager.ReplayExchange.expected:type_name -> broker.mcp.McpMessage
+	101, // 103: broker.manager.ReplayExchange.actual:type_name -> broker.mcp.McpMessage
+	82,  // 104: broker.manager.ReplayExchange.differences:type_name -> broker.manager.ReplayDifference
+	83,  // 105: broker.manager.ReplaySessionResponse.exchanges:type_name -> broker.manager.ReplayExchange
+	11,  // 106: broker.manager.McpManager.CheckActiveSession:input_type -> broker.manager.CheckActiveSessionRequest
+	14,  // 107: broker.manager.McpManager.CreateSession:input_type -> broker.manager.CreateSessionRequest
+	25,  // 108: broker.manager.McpManager.DiscoverServer:input_type -> broker.manager.DiscoverRequest
+	38,  // 109: broker.manager.McpManager.DiscardSession:input_type -> broker.manager.DiscardSessionRequest
+	26,  // 110: broker.manager.McpManager.SendMcpMessage:input_type -> broker.manager.SendMcpMessageRequest
+	27,  // 111: broker.manager.McpManager.StreamMcpMessages:input_type -> broker.manager.StreamMcpMessagesRequest
+	34,  // 112: broker.manager.McpManager.GetServerInfo:input_type -> broker.manager.GetServerInfoRequest
+	8,   // 113: broker.manager.McpManager.ListManagers:input_type -> broker.manager.ListManagersRequest
+	35,  // 114: broker.manager.McpManager.ListWorkers:input_type -> broker.manager.ListWorkersRequest
+	47,  // 115: broker.manager.McpManager.ListSessions:input_type -> broker.manager.ListSessionsRequest
+	49,  // 116: broker.manager.McpManager.GetSession:input_type -> broker.manager.GetSessionRequest
+	49,  // 117: broker.manager.McpManager.GetSessionServer:input_type -> broker.manager.GetSessionRequest
+	51,  // 118: broker.manager.McpManager.ListRuns:input_type -> broker.manager.ListRunsRequest
+	53,  // 119: broker.manager.McpManager.GetRun:input_type -> broker.manager.GetRunRequest
+	69,  // 120: broker.manager.McpManager.ListSessionErrors:input_type -> broker.manager.ListSessionErrorsRequest
+	67,  // 121: broker.manager.McpManager.ListSessionEvents:input_type -> broker.manager.ListSessionEventsRequest
+	71,  // 122: broker.manager.McpManager.ListSessionMessages:input_type -> broker.manager.ListSessionMessagesRequest
+	61,  // 123: broker.manager.McpManager.ListRunErrors:input_type -> broker.manager.ListRunErrorsRequest
+	63,  // 124: broker.manager.McpManager.ListRunEvents:input_type -> broker.manager.ListRunEventsRequest
+	65,  // 125: broker.manager.McpManager.ListRunMessages:input_type -> broker.manager.ListRunMessagesRequest
+	55,  // 126: broker.manager.McpManager.GetError:input_type -> broker.manager.GetErrorRequest
+	57,  // 127: broker.manager.McpManager.GetEvent:input_type -> broker.manager.GetEventRequest
+	59,  // 128: broker.manager.McpManager.GetMessage:input_type -> broker.manager.GetMessageRequest
+	73,  // 129: broker.manager.McpManager.ListRecentlyActiveRuns:input_type -> broker.manager.ListRecentlyActiveRunsRequest
+	75,  // 130: broker.manager.McpManager.ListRecentlyActiveSessions:input_type -> broker.manager.ListRecentlyActiveSessionsRequest
+	77,  // 131: broker.manager.McpManager.GetServer:input_type -> broker.manager.GetServerRequest
+	79,  // 132: broker.manager.McpManager.ListServers:input_type -> broker.manager.ListServersRequest
+	81,  // 133: broker.manager.McpManager.ReplaySession:input_type -> broker.manager.ReplaySessionRequest
+	19,  // 134: broker.manager.McpManager.DryRunLauncher:input_type -> broker.manager.DryRunLauncherRequest
+	12,  // 135: broker.manager.McpManager.CheckActiveSession:output_type -> broker.manager.CheckActiveSessionResponse
+	24,  // 136: broker.manager.McpManager.CreateSession:output_type -> broker.manager.CreateSessionResponse
+	78,  // 137: broker.manager.McpManager.DiscoverServer:output_type -> broker.manager.GetServerResponse
+	39,  // 138: broker.manager.McpManager.DiscardSession:output_type -> broker.manager.DiscardSessionResponse
+	33,  // 139: broker.manager.McpManager.SendMcpMessage:output_type -> broker.manager.McpConnectionStreamResponse
+	33,  // 140: broker.manager.McpManager.StreamMcpMessages:output_type -> broker.manager.McpConnectionStreamResponse
+	90,  // 141: broker.manager.McpManager.GetServerInfo:output_type -> broker.mcp.McpParticipant
+	9,   // 142: broker.manager.McpManager.ListManagers:output_type -> broker.manager.ListManagersResponse
+	36,  // 143: broker.manager.McpManager.ListWorkers:output_type -> broker.manager.ListWorkersResponse
+	48,  // 144: broker.manager.McpManager.ListSessions:output_type -> broker.manager.ListSessionsResponse
+	50,  // 145: broker.manager.McpManager.GetSession:output_type -> broker.manager.GetSessionResponse
+	78,  // 146: broker.manager.McpManager.GetSessionServer:output_type -> broker.manager.GetServerResponse
+	52,  // 147: broker.manager.McpManager.ListRuns:output_type -> broker.manager.ListRunsResponse
+	54,  // 148: broker.manager.McpManager.GetRun:output_type -> broker.manager.GetRunResponse
+	70,  // 149: broker.manager.McpManager.ListSessionErrors:output_type -> broker.manager.ListSessionErrorsResponse
+	68,  // 150: broker.manager.McpManager.ListSessionEvents:output_type -> broker.manager.ListSessionEventsResponse
+	72,  // 151: broker.manager.McpManager.ListSessionMessages:output_type -> broker.manager.ListSessionMessagesResponse
+	62,  // 152: broker.manager.McpManager.ListRunErrors:output_type -> broker.manager.ListRunErrorsResponse
+	64,  // 153: broker.manager.McpManager.ListRunEvents:output_type -> broker.manager.ListRunEventsResponse
+	66,  // 154: broker.manager.McpManager.ListRunMessages:output_type -> broker.manager.ListRunMessagesResponse
+	56,  // 155: broker.manager.McpManager.GetError:output_type -> broker.manager.GetErrorResponse
+	58,  // 156: broker.manager.McpManager.GetEvent:output_type -> broker.manager.GetEventResponse
+	60,  // 157: broker.manager.McpManager.GetMessage:output_type -> broker.manager.GetMessageResponse
+	74,  // 158: broker.manager.McpManager.ListRecentlyActiveRuns:output_type -> broker.manager.ListRecentlyActiveRunsResponse
+	76,  // 159: broker.manager.McpManager.ListRecentlyActiveSessions:output_type -> broker.manager.ListRecentlyActiveSessionsResponse
+	78,  // 160: broker.manager.McpManager.GetServer:output_type -> broker.manager.GetServerResponse
+	80,  // 161: broker.manager.McpManager.ListServers:output_type -> broker.manager.ListServersResponse
+	84,  // 162: broker.manager.McpManager.ReplaySession:output_type -> broker.manager.ReplaySessionResponse
+	22,  // 163: broker.manager.McpManager.DryRunLauncher:output_type -> broker.manager.DryRunLauncherResponse
+	135, // [135:164] is the sub-list for method output_type
+	106, // [106:135] is the sub-list for method input_type
+	106, // [106:106] is the sub-list for extension type_name
+	106, // [106:106] is the sub-list for extension extendee
+	0,   // [0:106] is the sub-list for field type_name
 }
 
 func init() { file_manager_proto_init() }
@@ -5285,37 +5923,43 @@ func file_manager_proto_init() {
 		(*ServerConfig_LambdaRunConfigWithLauncher)(nil),
 		(*ServerConfig_LambdaRunConfigWithServer)(nil),
 	}
-	file_manager_proto_msgTypes[11].OneofWrappers = []any{}
+	file_manager_proto_msgTypes[12].OneofWrappers = []any{
+		(*DryRunRunConfig_ContainerRunConfig)(nil),
+		(*DryRunRunConfig_RemoteRunConfig)(nil),
+		(*DryRunRunConfig_LambdaRunConfig)(nil),
+	}
 	file_manager_proto_msgTypes[15].OneofWrappers = []any{}
-	file_manager_proto_msgTypes[20].OneofWrappers = []any{
+	file_manager_proto_msgTypes[19].OneofWrappers = []any{}
+	file_manager_proto_msgTypes[24].OneofWrappers = []any{
 		(*SessionEvent_StartRun)(nil),
 		(*SessionEvent_StopRun)(nil),
 		(*SessionEvent_InfoRun)(nil),
 		(*SessionEvent_InfoSession)(nil),
 	}
-	file_manager_proto_msgTypes[21].OneofWrappers = []any{
+	file_manager_proto_msgTypes[25].OneofWrappers = []any{
 		(*McpConnectionStreamResponse_McpMessage)(nil),
 		(*McpConnectionStreamResponse_McpError)(nil),
 		(*McpConnectionStreamResponse_McpOutput)(nil),
 		(*McpConnectionStreamResponse_SessionEvent)(nil),
 	}
-	file_manager_proto_msgTypes[33].OneofWrappers = []any{}
-	file_manager_proto_msgTypes[35].OneofWrappers = []any{}
+	file_manager_proto_msgTypes[37].OneofWrappers = []any{}
 	file_manager_proto_msgTypes[39].OneofWrappers = []any{}
-	file_manager_proto_msgTypes[49].OneofWrappers = []any{}
-	file_manager_proto_msgTypes[51].OneofWrappers = []any{}
+	file_manager_proto_msgTypes[43].OneofWrappers = []any{}
 	file_manager_proto_msgTypes[53].OneofWrappers = []any{}
 	file_manager_proto_msgTypes[55].OneofWrappers = []any{}
 	file_manager_proto_msgTypes[57].OneofWrappers = []any{}
 	file_manager_proto_msgTypes[59].OneofWrappers = []any{}
-	file_manager_proto_msgTypes[67].OneofWrappers = []any{}
+	file_manager_proto_msgTypes[61].OneofWrappers = []any{}
+	file_manager_proto_msgTypes[63].OneofWrappers = []any{}
+	file_manager_proto_msgTypes[71].OneofWrappers = []any{}
+	file_manager_proto_msgTypes[73].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_manager_proto_rawDesc), len(file_manager_proto_rawDesc)),
 			NumEnums:      8,
-			NumMessages:   74,
+			NumMessages:   82,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -47,6 +47,8 @@ const (
 	McpManager_ListRecentlyActiveSessions_FullMethodName = "/broker.manager.McpManager/ListRecentlyActiveSessions"
 	McpManager_GetServer_FullMethodName                  = "/broker.manager.McpManager/GetServer"
 	McpManager_ListServers_FullMethodName                = "/broker.manager.McpManager/ListServers"
+	McpManager_ReplaySession_FullMethodName              = "/broker.manager.McpManager/ReplaySession"
+	McpManager_DryRunLauncher_FullMethodName             = "/broker.manager.McpManager/DryRunLauncher"
 )
 
 // McpManagerClient is the client API for McpManager service.
@@ -80,6 +82,8 @@ type McpManagerClient interface {
 	ListRecentlyActiveSessions(ctx context.Context, in *ListRecentlyActiveSessionsRequest, opts ...grpc.CallOption) (*ListRecentlyActiveSessionsResponse, error)
 	GetServer(ctx context.Context, in *GetServerRequest, opts ...grpc.CallOption) (*GetServerResponse, error)
 	ListServers(ctx context.Context, in *ListServersRequest, opts ...grpc.CallOption) (*ListServersResponse, error)
+	ReplaySession(ctx context.Context, in *ReplaySessionRequest, opts ...grpc.CallOption) (*ReplaySessionResponse, error)
+	DryRunLauncher(ctx context.Context, in *DryRunLauncherRequest, opts ...grpc.CallOption) (*DryRunLauncherResponse, error)
 }
 
 type mcpManagerClient struct {
@@ -378,6 +382,26 @@ func (c *mcpManagerClient) ListServers(ctx context.Context, in *ListServersReque
 	return out, nil
 }
 
+func (c *mcpManagerClient) ReplaySession(ctx context.Context, in *ReplaySessionRequest, opts ...grpc.CallOption) (*ReplaySessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplaySessionResponse)
+	err := c.cc.Invoke(ctx, McpManager_ReplaySession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mcpManagerClient) DryRunLauncher(ctx context.Context, in *DryRunLauncherRequest, opts ...grpc.CallOption) (*DryRunLauncherResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DryRunLauncherResponse)
+	err := c.cc.Invoke(ctx, McpManager_DryRunLauncher_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // McpManagerServer is the server API for McpManager service.
 // All implementations must embed UnimplementedMcpManagerServer
 // for forward compatibility.
@@ -409,6 +433,8 @@ type McpManagerServer interface {
 	ListRecentlyActiveSessions(context.Context, *ListRecentlyActiveSessionsRequest) (*ListRecentlyActiveSessionsResponse, error)
 	GetServer(context.Context, *GetServerRequest) (*GetServerResponse, error)
 	ListServers(context.Context, *ListServersRequest) (*ListServersResponse, error)
+	ReplaySession(context.Context, *ReplaySessionRequest) (*ReplaySessionResponse, error)
+	DryRunLauncher(context.Context, *DryRunLauncherRequest) (*DryRunLauncherResponse, error)
 	mustEmbedUnimplementedMcpManagerServer()
 }
 
@@ -500,6 +526,12 @@ func (UnimplementedMcpManagerServer) GetServer(context.Context, *GetServerReques
 func (UnimplementedMcpManagerServer) ListServers(context.Context, *ListServersRequest) (*ListServersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListServers not implemented")
 }
+func (UnimplementedMcpManagerServer) ReplaySession(context.Context, *ReplaySessionRequest) (*ReplaySessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplaySession not implemented")
+}
+func (UnimplementedMcpManagerServer) DryRunLauncher(context.Context, *DryRunLauncherRequest) (*DryRunLauncherResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DryRunLauncher not implemented")
+}
 func (UnimplementedMcpManagerServer) mustEmbedUnimplementedMcpManagerServer() {}
 func (UnimplementedMcpManagerServer) testEmbeddedByValue()                    {}
 
@@ -993,6 +1025,42 @@ func _McpManager_ListServers_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _McpManager_ReplaySession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplaySessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(McpManagerServer).ReplaySession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: McpManager_ReplaySession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(McpManagerServer).ReplaySession(ctx, req.(*ReplaySessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _McpManager_DryRunLauncher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DryRunLauncherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(McpManagerServer).DryRunLauncher(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: McpManager_DryRunLauncher_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(McpManagerServer).DryRunLauncher(ctx, req.(*DryRunLauncherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // McpManager_ServiceDesc is the grpc.ServiceDesc for McpManager service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1100,6 +1168,14 @@ var McpManager_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListServers",
 			Handler:    _McpManager_ListServers_Handler,
 		},
+		{
+			MethodName: "ReplaySession",
+			Handler:    _McpManager_ReplaySession_Handler,
+		},
+		{
+			MethodName: "DryRunLauncher",
+			Handler:    _McpManager_DryRunLauncher_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -86,6 +86,7 @@ const (
 	McpError_timeout             McpError_McpErrorCode = 4
 	McpError_launch_params_error McpError_McpErrorCode = 5
 	McpError_execution_error     McpError_McpErrorCode = 6
+	McpError_message_overflow    McpError_McpErrorCode = 7 // The receiver didn't keep up with MCP messages
 )
 
 // Enum value maps for McpError_McpErrorCode.
@@ -98,6 +99,7 @@ var (
 		4: "timeout",
 		5: "launch_params_error",
 		6: "execution_error",
+		7: "message_overflow",
 	}
 	McpError_McpErrorCode_value = map[string]int32{
 		"failed_to_start":     0,
@@ -107,6 +109,7 @@ var (
 		"timeout":             4,
 		"launch_params_error": 5,
 		"execution_error":     6,
+		"message_overflow":    7,
 	}
 )
 
@@ -837,7 +840,7 @@ var File_mcp_proto protoreflect.FileDescriptor
 const file_mcp_proto_rawDesc = "" +
 	"\n" +
 	"\tmcp.proto\x12\n" +
-	"broker.mcp\"\xa3\x03\n" +
+	"broker.mcp\"\xb9\x03\n" +
 	"\bMcpError\x12#\n" +
 	"\rerror_message\x18\x01 \x01(\tR\ferrorMessage\x12@\n" +
 	"\n" +
@@ -846,7 +849,7 @@ const file_mcp_proto_rawDesc = "" +
 	"\x04uuid\x18\x04 \x01(\tR\x04uuid\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x9e\x01\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb4\x01\n" +
 	"\fMcpErrorCode\x12\x13\n" +
 	"\x0ffailed_to_start\x10\x00\x12\x12\n" +
 	"\x0efailed_to_stop\x10\x01\x12\x17\n" +
@@ -854,7 +857,8 @@ const file_mcp_proto_rawDesc = "" +
 	"\runknown_error\x10\x03\x12\v\n" +
 	"\atimeout\x10\x04\x12\x17\n" +
 	"\x13launch_params_error\x10\x05\x12\x13\n" +
-	"\x0fexecution_error\x10\x06\"\xb0\x01\n" +
+	"\x0fexecution_error\x10\x06\x12\x14\n" +
+	"\x10message_overflow\x10\a\"\xb0\x01\n" +
 	"\tMcpOutput\x12D\n" +
 	"\voutput_type\x18\x01 \x01(\x0e2#.broker.mcp.McpOutput.McpOutputTypeR\n" +
 	"outputType\x12\x14\n" +
@@ -757,6 +757,8 @@ type RunRequestInit struct {
 	ConnectionId  string                 `protobuf:"bytes,1,opt,name=connection_id,json=connectionId,proto3" json:"connection_id,omitempty"`
 	RunConfig     *RunConfig             `protobuf:"bytes,2,opt,name=run_config,json=runConfig,proto3" json:"run_config,omitempty"`
 	Client        *RunConfigLambdaClient `protobuf:"bytes,3,opt,name=client,proto3,oneof" json:"client,omitempty"`
+	TraceContext  map[string]string      `protobuf:"bytes,4,rep,name=trace_context,json=traceContext,proto3" json:"trace_context,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // W3C trace context (traceparent, tracestate) of the run start
+	SessionId     string                 `protobuf:"bytes,5,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`                                                                                    // Session the run belongs to, used for logging
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -812,9 +814,24 @@ func (x *RunRequestInit) GetClient() *RunConfigLambdaClient {
 	return nil
 }
 
+func (x *RunRequestInit) GetTraceContext() map[string]string {
+	if x != nil {
+		return x.TraceContext
+	}
+	return nil
+}
+
+func (x *RunRequestInit) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
 type RunRequestMcpMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       *mcp.McpMessageRaw     `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	TraceContext  map[string]string      `protobuf:"bytes,2,rep,name=trace_context,json=traceContext,proto3" json:"trace_context,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // W3C trace context (traceparent, tracestate) of the message
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -856,6 +873,13 @@ func (x *RunRequestMcpMessage) GetMessage() *mcp.McpMessageRaw {
 	return nil
 }
 
+func (x *RunRequestMcpMessage) GetTraceContext() map[string]string {
+	if x != nil {
+		return x.TraceContext
+	}
+	return nil
+}
+
 type RunRequestClose struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -1283,15 +1307,25 @@ const file_remote_proto_rawDesc = "" +
 	"\vmcp_message\x18\x02 \x01(\v2#.broker.remote.RunRequestMcpMessageH\x00R\n" +
 	"mcpMessage\x126\n" +
 	"\x05close\x18\x03 \x01(\v2\x1e.broker.remote.RunRequestCloseH\x00R\x05closeB\x06\n" +
-	"\x04type\"\xbc\x01\n" +
+	"\x04type\"\xf2\x02\n" +
 	"\x0eRunRequestInit\x12#\n" +
 	"\rconnection_id\x18\x01 \x01(\tR\fconnectionId\x127\n" +
 	"\n" +
 	"run_config\x18\x02 \x01(\v2\x18.broker.remote.RunConfigR\trunConfig\x12A\n" +
-	"\x06client\x18\x03 \x01(\v2$.broker.remote.RunConfigLambdaClientH\x00R\x06client\x88\x01\x01B\t\n" +
-	"\a_client\"K\n" +
+	"\x06client\x18\x03 \x01(\v2$.broker.remote.RunConfigLambdaClientH\x00R\x06client\x88\x01\x01\x12T\n" +
+	"\rtrace_context\x18\x04 \x03(\v2/.broker.remote.RunRequestInit.TraceContextEntryR\ftraceContext\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x05 \x01(\tR\tsessionId\x1a?\n" +
+	"\x11TraceContextEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\t\n" +
+	"\a_client\"\xe8\x01\n" +
 	"\x14RunRequestMcpMessage\x123\n" +
-	"\amessage\x18\x01 \x01(\v2\x19.broker.mcp.McpMessageRawR\amessage\"\x11\n" +
+	"\amessage\x18\x01 \x01(\v2\x19.broker.mcp.McpMessageRawR\amessage\x12Z\n" +
+	"\rtrace_context\x18\x02 \x03(\v25.broker.remote.RunRequestMcpMessage.TraceContextEntryR\ftraceContext\x1a?\n" +
+	"\x11TraceContextEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x11\n" +
 	"\x0fRunRequestClose\"\xc2\x02\n" +
 	"\vRunResponse\x12G\n" +
 	"\vmcp_message\x18\x01 \x01(\v2$.broker.remote.RunResponseMcpMessageH\x00R\n" +
@@ -1326,7 +1360,7 @@ func file_remote_proto_rawDescGZIP() []byte {
 }
 
 var file_remote_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_remote_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_remote_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_remote_proto_goTypes = []any{
 	(RunConfigRemoteServer_ServerProtocol)(0), // 0: broker.remote.RunConfigRemoteServer.ServerProtocol
 	(RunConfigLambdaServer_Protocol)(0),       // 1: broker.remote.RunConfigLambdaServer.Protocol
@@ -1352,22 +1386,24 @@ var file_remote_proto_goTypes = []any{
 	(*RunResponseClose)(nil),                  // 21: broker.remote.RunResponseClose
 	nil,                                       // 22: broker.remote.RunConfigRemoteArguments.HeadersEntry
 	nil,                                       // 23: broker.remote.RunConfigRemoteArguments.QueryEntry
-	(*worker.WorkerInfoResponse)(nil),         // 24: broker.worker.WorkerInfoResponse
-	(*mcp.McpParticipant)(nil),                // 25: broker.mcp.McpParticipant
-	(*mcp.McpMessageRaw)(nil),                 // 26: broker.mcp.McpMessageRaw
-	(*mcp.McpMessage)(nil),                    // 27: broker.mcp.McpMessage
-	(*mcp.McpError)(nil),                      // 28: broker.mcp.McpError
-	(*mcp.McpOutput)(nil),                     // 29: broker.mcp.McpOutput
+	nil,                                       // 24: broker.remote.RunRequestInit.TraceContextEntry
+	nil,                                       // 25: broker.remote.RunRequestMcpMessage.TraceContextEntry
+	(*worker.WorkerInfoResponse)(nil),         // 26: broker.worker.WorkerInfoResponse
+	(*mcp.McpParticipant)(nil),                // 27: broker.mcp.McpParticipant
+	(*mcp.McpMessageRaw)(nil),                 // 28: broker.mcp.McpMessageRaw
+	(*mcp.McpMessage)(nil),                    // 29: broker.mcp.McpMessage
+	(*mcp.McpError)(nil),                      // 30: broker.mcp.McpError
+	(*mcp.McpOutput)(nil),                     // 31: broker.mcp.McpOutput
 }
 var file_remote_proto_depIdxs = []int32{
-	24, // 0: broker.remote.RemoteInfoResponse.worker_info:type_name -> broker.worker.WorkerInfoResponse
+	26, // 0: broker.remote.RemoteInfoResponse.worker_info:type_name -> broker.worker.WorkerInfoResponse
 	0,  // 1: broker.remote.RunConfigRemoteServer.protocol:type_name -> broker.remote.RunConfigRemoteServer.ServerProtocol
 	22, // 2: broker.remote.RunConfigRemoteArguments.headers:type_name -> broker.remote.RunConfigRemoteArguments.HeadersEntry
 	23, // 3: broker.remote.RunConfigRemoteArguments.query:type_name -> broker.remote.RunConfigRemoteArguments.QueryEntry
 	4,  // 4: broker.remote.RunConfigRemote.server:type_name -> broker.remote.RunConfigRemoteServer
 	5,  // 5: broker.remote.RunConfigRemote.arguments:type_name -> broker.remote.RunConfigRemoteArguments
 	1,  // 6: broker.remote.RunConfigLambdaServer.protocol:type_name -> broker.remote.RunConfigLambdaServer.Protocol
-	25, // 7: broker.remote.RunConfigLambdaClient.participant:type_name -> broker.mcp.McpParticipant
+	27, // 7: broker.remote.RunConfigLambdaClient.participant:type_name -> broker.mcp.McpParticipant
 	7,  // 8: broker.remote.RunConfigLambda.server:type_name -> broker.remote.RunConfigLambdaServer
 	8,  // 9: broker.remote.RunConfigLambda.arguments:type_name -> broker.remote.RunConfigLambdaArguments
 	6,  // 10: broker.remote.RunConfig.remote_run_config:type_name -> broker.remote.RunConfigRemote
@@ -1377,22 +1413,24 @@ var file_remote_proto_depIdxs = []int32{
 	15, // 14: broker.remote.RunRequest.close:type_name -> broker.remote.RunRequestClose
 	11, // 15: broker.remote.RunRequestInit.run_config:type_name -> broker.remote.RunConfig
 	9,  // 16: broker.remote.RunRequestInit.client:type_name -> broker.remote.RunConfigLambdaClient
-	26, // 17: broker.remote.RunRequestMcpMessage.message:type_name -> broker.mcp.McpMessageRaw
-	18, // 18: broker.remote.RunResponse.mcp_message:type_name -> broker.remote.RunResponseMcpMessage
-	17, // 19: broker.remote.RunResponse.init:type_name -> broker.remote.RunResponseInit
-	20, // 20: broker.remote.RunResponse.output:type_name -> broker.remote.RunResponseOutput
-	19, // 21: broker.remote.RunResponse.error:type_name -> broker.remote.RunResponseError
-	21, // 22: broker.remote.RunResponse.close:type_name -> broker.remote.RunResponseClose
-	27, // 23: broker.remote.RunResponseMcpMessage.message:type_name -> broker.mcp.McpMessage
-	28, // 24: broker.remote.RunResponseError.mcp_error:type_name -> broker.mcp.McpError
-	29, // 25: broker.remote.RunResponseOutput.mcp_output:type_name -> broker.mcp.McpOutput
-	12, // 26: broker.remote.McpRemote.StreamMcpRun:input_type -> broker.remote.RunRequest
-	16, // 27: broker.remote.McpRemote.StreamMcpRun:output_type -> broker.remote.RunResponse
-	27, // [27:28] is the sub-list for method output_type
-	26, // [26:27] is the sub-list for method input_type
-	26, // [26:26] is the sub-list for extension type_name
-	26, // [26:26] is the sub-list for extension extendee
-	0,  // [0:26] is the sub-list for field type_name
+	24, // 17: broker.remote.RunRequestInit.trace_context:type_name -> broker.remote.RunRequestInit.TraceContextEntry
+	28, // 18: broker.remote.RunRequestMcpMessage.message:type_name -> broker.mcp.McpMessageRaw
+	25, // 19: broker.remote.RunRequestMcpMessage.trace_context:type_name -> broker.remote.RunRequestMcpMessage.TraceContextEntry
+	18, // 20: broker.remote.RunResponse.mcp_message:type_name -> broker.remote.RunResponseMcpMessage
+	17, // 21: broker.remote.RunResponse.init:type_name -> broker.remote.RunResponseInit
+	20, // 22: broker.remote.RunResponse.output:type_name -> broker.remote.RunResponseOutput
+	19, // 23: broker.remote.RunResponse.error:type_name -> broker.remote.RunResponseError
+	21, // 24: broker.remote.RunResponse.close:type_name -> broker.remote.RunResponseClose
+	29, // 25: broker.remote.RunResponseMcpMessage.message:type_name -> broker.mcp.McpMessage
+	30, // 26: broker.remote.RunResponseError.mcp_error:type_name -> broker.mcp.McpError
+	31, // 27: broker.remote.RunResponseOutput.mcp_output:type_name -> broker.mcp.McpOutput
+	12, // 28: broker.remote.McpRemote.StreamMcpRun:input_type -> broker.remote.RunRequest
+	16, // 29: broker.remote.McpRemote.StreamMcpRun:output_type -> broker.remote.RunResponse
+	29, // [29:30] is the sub-list for method output_type
+	28, // [28:29] is the sub-list for method input_type
+	28, // [28:28] is the sub-list for extension type_name
+	28, // [28:28] is the sub-list for extension extendee
+	0,  // [0:28] is the sub-list for field type_name
 }
 
 func init() { file_remote_proto_init() }
@@ -1424,7 +1462,7 @@ func file_remote_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_remote_proto_rawDesc), len(file_remote_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   22,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
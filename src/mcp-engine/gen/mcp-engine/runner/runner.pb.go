@@ -753,6 +753,8 @@ type RunRequestInit struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ConnectionId  string                 `protobuf:"bytes,1,opt,name=connection_id,json=connectionId,proto3" json:"connection_id,omitempty"` // Unique identifier for the run
 	RunConfig     *RunConfig             `protobuf:"bytes,2,opt,name=run_config,json=runConfig,proto3" json:"run_config,omitempty"`
+	TraceContext  map[string]string      `protobuf:"bytes,3,rep,name=trace_context,json=traceContext,proto3" json:"trace_context,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // W3C trace context (traceparent, tracestate) of the run start
+	SessionId     string                 `protobuf:"bytes,4,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`                                                                                    // Session the run belongs to, used for logging
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -801,9 +803,24 @@ func (x *RunRequestInit) GetRunConfig() *RunConfig {
 	return nil
 }
 
+func (x *RunRequestInit) GetTraceContext() map[string]string {
+	if x != nil {
+		return x.TraceContext
+	}
+	return nil
+}
+
+func (x *RunRequestInit) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
 type RunRequestMcpMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       *mcp.McpMessageRaw     `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	TraceContext  map[string]string      `protobuf:"bytes,2,rep,name=trace_context,json=traceContext,proto3" json:"trace_context,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // W3C trace context (traceparent, tracestate) of the message
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -845,6 +862,13 @@ func (x *RunRequestMcpMessage) GetMessage() *mcp.McpMessageRaw {
 	return nil
 }
 
+func (x *RunRequestMcpMessage) GetTraceContext() map[string]string {
+	if x != nil {
+		return x.TraceContext
+	}
+	return nil
+}
+
 type RunRequestClose struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -1279,13 +1303,23 @@ const file_runner_proto_rawDesc = "" +
 	"\vmcp_message\x18\x02 \x01(\v2#.broker.runner.RunRequestMcpMessageH\x00R\n" +
 	"mcpMessage\x126\n" +
 	"\x05close\x18\x03 \x01(\v2\x1e.broker.runner.RunRequestCloseH\x00R\x05closeB\x06\n" +
-	"\x04type\"n\n" +
+	"\x04type\"\xa4\x02\n" +
 	"\x0eRunRequestInit\x12#\n" +
 	"\rconnection_id\x18\x01 \x01(\tR\fconnectionId\x127\n" +
 	"\n" +
-	"run_config\x18\x02 \x01(\v2\x18.broker.runner.RunConfigR\trunConfig\"K\n" +
+	"run_config\x18\x02 \x01(\v2\x18.broker.runner.RunConfigR\trunConfig\x12T\n" +
+	"\rtrace_context\x18\x03 \x03(\v2/.broker.runner.RunRequestInit.TraceContextEntryR\ftraceContext\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x04 \x01(\tR\tsessionId\x1a?\n" +
+	"\x11TraceContextEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xe8\x01\n" +
 	"\x14RunRequestMcpMessage\x123\n" +
-	"\amessage\x18\x01 \x01(\v2\x19.broker.mcp.McpMessageRawR\amessage\"\x11\n" +
+	"\amessage\x18\x01 \x01(\v2\x19.broker.mcp.McpMessageRawR\amessage\x12Z\n" +
+	"\rtrace_context\x18\x02 \x03(\v25.broker.runner.RunRequestMcpMessage.TraceContextEntryR\ftraceContext\x1a?\n" +
+	"\x11TraceContextEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x11\n" +
 	"\x0fRunRequestClose\"\xc2\x02\n" +
 	"\vRunResponse\x12G\n" +
 	"\vmcp_message\x18\x01 \x01(\v2$.broker.runner.RunResponseMcpMessageH\x00R\n" +
@@ -1319,7 +1353,7 @@ func file_runner_proto_rawDescGZIP() []byte {
 	return file_runner_proto_rawDescData
 }
 
-var file_runner_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_runner_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_runner_proto_goTypes = []any{
 	(*RunnerInfoRequest)(nil),           // 0: broker.runner.RunnerInfoRequest
 	(*RunnerInfoResponse)(nil),          // 1: broker.runner.RunnerInfoResponse
@@ -1343,13 +1377,15 @@ var file_runner_proto_goTypes = []any{
 	(*RunResponseOutput)(nil),           // 19: broker.runner.RunResponseOutput
 	(*RunResponseClose)(nil),            // 20: broker.runner.RunResponseClose
 	nil,                                 // 21: broker.runner.RunConfigContainerArguments.EnvVarsEntry
-	(*worker.WorkerInfoResponse)(nil),   // 22: broker.worker.WorkerInfoResponse
-	(*mcp.McpMessageRaw)(nil),           // 23: broker.mcp.McpMessageRaw
-	(*mcp.McpError)(nil),                // 24: broker.mcp.McpError
-	(*mcp.McpOutput)(nil),               // 25: broker.mcp.McpOutput
+	nil,                                 // 22: broker.runner.RunRequestInit.TraceContextEntry
+	nil,                                 // 23: broker.runner.RunRequestMcpMessage.TraceContextEntry
+	(*worker.WorkerInfoResponse)(nil),   // 24: broker.worker.WorkerInfoResponse
+	(*mcp.McpMessageRaw)(nil),           // 25: broker.mcp.McpMessageRaw
+	(*mcp.McpError)(nil),                // 26: broker.mcp.McpError
+	(*mcp.McpOutput)(nil),               // 27: broker.mcp.McpOutput
 }
 var file_runner_proto_depIdxs = []int32{
-	22, // 0: broker.runner.RunnerInfoResponse.worker_info:type_name -> broker.worker.WorkerInfoResponse
+	24, // 0: broker.runner.RunnerInfoResponse.worker_info:type_name -> broker.worker.WorkerInfoResponse
 	3,  // 1: broker.runner.ActiveRunsResponse.runs:type_name -> broker.runner.RunInfo
 	5,  // 2: broker.runner.DockerImagesResponse.images:type_name -> broker.runner.DockerImageInfo
 	7,  // 3: broker.runner.DockerContainersResponse.containers:type_name -> broker.runner.DockerContainerInfo
@@ -1360,22 +1396,24 @@ var file_runner_proto_depIdxs = []int32{
 	13, // 8: broker.runner.RunRequest.mcp_message:type_name -> broker.runner.RunRequestMcpMessage
 	14, // 9: broker.runner.RunRequest.close:type_name -> broker.runner.RunRequestClose
 	10, // 10: broker.runner.RunRequestInit.run_config:type_name -> broker.runner.RunConfig
-	23, // 11: broker.runner.RunRequestMcpMessage.message:type_name -> broker.mcp.McpMessageRaw
-	17, // 12: broker.runner.RunResponse.mcp_message:type_name -> broker.runner.RunResponseMcpMessage
-	16, // 13: broker.runner.RunResponse.init:type_name -> broker.runner.RunResponseInit
-	19, // 14: broker.runner.RunResponse.output:type_name -> broker.runner.RunResponseOutput
-	18, // 15: broker.runner.RunResponse.error:type_name -> broker.runner.RunResponseError
-	20, // 16: broker.runner.RunResponse.close:type_name -> broker.runner.RunResponseClose
-	23, // 17: broker.runner.RunResponseMcpMessage.message:type_name -> broker.mcp.McpMessageRaw
-	24, // 18: broker.runner.RunResponseError.mcp_error:type_name -> broker.mcp.McpError
-	25, // 19: broker.runner.RunResponseOutput.mcp_output:type_name -> broker.mcp.McpOutput
-	11, // 20: broker.runner.McpRunner.StreamMcpRun:input_type -> broker.runner.RunRequest
-	15, // 21: broker.runner.McpRunner.StreamMcpRun:output_type -> broker.runner.RunResponse
-	21, // [21:22] is the sub-list for method output_type
-	20, // [20:21] is the sub-list for method input_type
-	20, // [20:20] is the sub-list for extension type_name
-	20, // [20:20] is the sub-list for extension extendee
-	0,  // [0:20] is the sub-list for field type_name
+	22, // 11: broker.runner.RunRequestInit.trace_context:type_name -> broker.runner.RunRequestInit.TraceContextEntry
+	25, // 12: broker.runner.RunRequestMcpMessage.message:type_name -> broker.mcp.McpMessageRaw
+	23, // 13: broker.runner.RunRequestMcpMessage.trace_context:type_name -> broker.runner.RunRequestMcpMessage.TraceContextEntry
+	17, // 14: broker.runner.RunResponse.mcp_message:type_name -> broker.runner.RunResponseMcpMessage
+	16, // 15: broker.runner.RunResponse.init:type_name -> broker.runner.RunResponseInit
+	19, // 16: broker.runner.RunResponse.output:type_name -> broker.runner.RunResponseOutput
+	18, // 17: broker.runner.RunResponse.error:type_name -> broker.runner.RunResponseError
+	20, // 18: broker.runner.RunResponse.close:type_name -> broker.runner.RunResponseClose
+	25, // 19: broker.runner.RunResponseMcpMessage.message:type_name -> broker.mcp.McpMessageRaw
+	26, // 20: broker.runner.RunResponseError.mcp_error:type_name -> broker.mcp.McpError
+	27, // 21: broker.runner.RunResponseOutput.mcp_output:type_name -> broker.mcp.McpOutput
+	11, // 22: broker.runner.McpRunner.StreamMcpRun:input_type -> broker.runner.RunRequest
+	15, // 23: broker.runner.McpRunner.StreamMcpRun:output_type -> broker.runner.RunResponse
+	23, // [23:24] is the sub-list for method output_type
+	22, // [22:23] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_runner_proto_init() }
@@ -1401,7 +1439,7 @@ func file_runner_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_runner_proto_rawDesc), len(file_runner_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   22,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
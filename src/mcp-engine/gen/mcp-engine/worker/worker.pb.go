@@ -205,6 +205,7 @@ type WorkerInfoResponse struct {
 	AcceptingJobs WorkerAcceptingJobs    `protobuf:"varint,3,opt,name=accepting_jobs,json=acceptingJobs,proto3,enum=broker.worker.WorkerAcceptingJobs" json:"accepting_jobs,omitempty"`
 	Status        WorkerStatus           `protobuf:"varint,4,opt,name=status,proto3,enum=broker.worker.WorkerStatus" json:"status,omitempty"`
 	WorkerType    WorkerType             `protobuf:"varint,5,opt,name=worker_type,json=workerType,proto3,enum=broker.worker.WorkerType" json:"worker_type,omitempty"`
+	LauncherPool  *LauncherPoolStats     `protobuf:"bytes,6,opt,name=launcher_pool,json=launcherPool,proto3" json:"launcher_pool,omitempty"` // Only set by launcher workers
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -274,6 +275,105 @@ func (x *WorkerInfoResponse) GetWorkerType() WorkerType {
 	return WorkerType_mcp_runner
 }
 
+func (x *WorkerInfoResponse) GetLauncherPool() *LauncherPoolStats {
+	if x != nil {
+		return x.LauncherPool
+	}
+	return nil
+}
+
+type LauncherPoolStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Size          uint32                 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"` // Maximum number of processes
+	Idle          uint32                 `protobuf:"varint,2,opt,name=idle,proto3" json:"idle,omitempty"`
+	Busy          uint32                 `protobuf:"varint,3,opt,name=busy,proto3" json:"busy,omitempty"`
+	TotalRuns     uint64                 `protobuf:"varint,4,opt,name=total_runs,json=totalRuns,proto3" json:"total_runs,omitempty"`
+	Spawned       uint64                 `protobuf:"varint,5,opt,name=spawned,proto3" json:"spawned,omitempty"`
+	Recycled      uint64                 `protobuf:"varint,6,opt,name=recycled,proto3" json:"recycled,omitempty"` // Replaced after too many runs, memory growth or a tainted run
+	Failed        uint64                 `protobuf:"varint,7,opt,name=failed,proto3" json:"failed,omitempty"`     // Crashed, timed out or failed to start
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LauncherPoolStats) Reset() {
+	*x = LauncherPoolStats{}
+	mi := &file_worker_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LauncherPoolStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LauncherPoolStats) ProtoMessage() {}
+
+func (x *LauncherPoolStats) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LauncherPoolStats.ProtoReflect.Descriptor instead.
+func (*LauncherPoolStats) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LauncherPoolStats) GetSize() uint32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *LauncherPoolStats) GetIdle() uint32 {
+	if x != nil {
+		return x.Idle
+	}
+	return 0
+}
+
+func (x *LauncherPoolStats) GetBusy() uint32 {
+	if x != nil {
+		return x.Busy
+	}
+	return 0
+}
+
+func (x *LauncherPoolStats) GetTotalRuns() uint64 {
+	if x != nil {
+		return x.TotalRuns
+	}
+	return 0
+}
+
+func (x *LauncherPoolStats) GetSpawned() uint64 {
+	if x != nil {
+		return x.Spawned
+	}
+	return 0
+}
+
+func (x *LauncherPoolStats) GetRecycled() uint64 {
+	if x != nil {
+		return x.Recycled
+	}
+	return 0
+}
+
+func (x *LauncherPoolStats) GetFailed() uint64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
 type WorkerHealthRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -282,7 +382,7 @@ type WorkerHealthRequest struct {
 
 func (x *WorkerHealthRequest) Reset() {
 	*x = WorkerHealthRequest{}
-	mi := &file_worker_proto_msgTypes[2]
+	mi := &file_worker_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -294,7 +394,7 @@ func (x *WorkerHealthRequest) String() string {
 func (*WorkerHealthRequest) ProtoMessage() {}
 
 func (x *WorkerHealthRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_worker_proto_msgTypes[2]
+	mi := &file_worker_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -307,7 +407,7 @@ func (x *WorkerHealthRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WorkerHealthRequest.ProtoReflect.Descriptor instead.
 func (*WorkerHealthRequest) Descriptor() ([]byte, []int) {
-	return file_worker_proto_rawDescGZIP(), []int{2}
+	return file_worker_proto_rawDescGZIP(), []int{3}
 }
 
 var File_worker_proto protoreflect.FileDescriptor
@@ -315,7 +415,7 @@ var File_worker_proto protoreflect.FileDescriptor
 const file_worker_proto_rawDesc = "" +
 	"\n" +
 	"\fworker.proto\x12\rbroker.worker\"\x13\n" +
-	"\x11WorkerInfoRequest\"\x8c\x02\n" +
+	"\x11WorkerInfoRequest\"\xd3\x02\n" +
 	"\x12WorkerInfoResponse\x12\x1b\n" +
 	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x1d\n" +
 	"\n" +
@@ -323,7 +423,17 @@ const file_worker_proto_rawDesc = "" +
 	"\x0eaccepting_jobs\x18\x03 \x01(\x0e2\".broker.worker.WorkerAcceptingJobsR\racceptingJobs\x123\n" +
 	"\x06status\x18\x04 \x01(\x0e2\x1b.broker.worker.WorkerStatusR\x06status\x12:\n" +
 	"\vworker_type\x18\x05 \x01(\x0e2\x19.broker.worker.WorkerTypeR\n" +
-	"workerType\"\x15\n" +
+	"workerType\x12E\n" +
+	"\rlauncher_pool\x18\x06 \x01(\v2 .broker.worker.LauncherPoolStatsR\flauncherPool\"\xbc\x01\n" +
+	"\x11LauncherPoolStats\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\rR\x04size\x12\x12\n" +
+	"\x04idle\x18\x02 \x01(\rR\x04idle\x12\x12\n" +
+	"\x04busy\x18\x03 \x01(\rR\x04busy\x12\x1d\n" +
+	"\n" +
+	"total_runs\x18\x04 \x01(\x04R\ttotalRuns\x12\x18\n" +
+	"\aspawned\x18\x05 \x01(\x04R\aspawned\x12\x1a\n" +
+	"\brecycled\x18\x06 \x01(\x04R\brecycled\x12\x16\n" +
+	"\x06failed\x18\a \x01(\x04R\x06failed\"\x15\n" +
 	"\x13WorkerHealthRequest*:\n" +
 	"\n" +
 	"WorkerType\x12\x0e\n" +
@@ -356,28 +466,30 @@ func file_worker_proto_rawDescGZIP() []byte {
 }
 
 var file_worker_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_worker_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_worker_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_worker_proto_goTypes = []any{
 	(WorkerType)(0),             // 0: broker.worker.WorkerType
 	(WorkerStatus)(0),           // 1: broker.worker.WorkerStatus
 	(WorkerAcceptingJobs)(0),    // 2: broker.worker.WorkerAcceptingJobs
 	(*WorkerInfoRequest)(nil),   // 3: broker.worker.WorkerInfoRequest
 	(*WorkerInfoResponse)(nil),  // 4: broker.worker.WorkerInfoResponse
-	(*WorkerHealthRequest)(nil), // 5: broker.worker.WorkerHealthRequest
+	(*LauncherPoolStats)(nil),   // 5: broker.worker.LauncherPoolStats
+	(*WorkerHealthRequest)(nil), // 6: broker.worker.WorkerHealthRequest
 }
 var file_worker_proto_depIdxs = []int32{
 	2, // 0: broker.worker.WorkerInfoResponse.accepting_jobs:type_name -> broker.worker.WorkerAcceptingJobs
 	1, // 1: broker.worker.WorkerInfoResponse.status:type_name -> broker.worker.WorkerStatus
 	0, // 2: broker.worker.WorkerInfoResponse.worker_type:type_name -> broker.worker.WorkerType
-	3, // 3: broker.worker.Worker.GetWorkerInfo:input_type -> broker.worker.WorkerInfoRequest
-	5, // 4: broker.worker.Worker.StreamWorkerHealth:input_type -> broker.worker.WorkerHealthRequest
-	4, // 5: broker.worker.Worker.GetWorkerInfo:output_type -> broker.worker.WorkerInfoResponse
-	4, // 6: broker.worker.Worker.StreamWorkerHealth:output_type -> broker.worker.WorkerInfoResponse
-	5, // [5:7] is the sub-list for method output_type
-	3, // [3:5] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	5, // 3: broker.worker.WorkerInfoResponse.launcher_pool:type_name -> broker.worker.LauncherPoolStats
+	3, // 4: broker.worker.Worker.GetWorkerInfo:input_type -> broker.worker.WorkerInfoRequest
+	6, // 5: broker.worker.Worker.StreamWorkerHealth:input_type -> broker.worker.WorkerHealthRequest
+	4, // 6: broker.worker.Worker.GetWorkerInfo:output_type -> broker.worker.WorkerInfoResponse
+	4, // 7: broker.worker.Worker.StreamWorkerHealth:output_type -> broker.worker.WorkerInfoResponse
+	6, // [6:8] is the sub-list for method output_type
+	4, // [4:6] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_worker_proto_init() }
@@ -391,7 +503,7 @@ func file_worker_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_worker_proto_rawDesc), len(file_worker_proto_rawDesc)),
 			NumEnums:      3,
-			NumMessages:   3,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -218,3 +218,19 @@ func (d *DB) GetSessionMessageById(id string) (*SessionMessage, error) {
 	}
 	return &record, nil
 }
+
+// ListAllSessionMessagesBySession returns every message of a session in
+// the order it was exchanged, used to replay a session.
+func (d *DB) ListAllSessionMessagesBySession(sessionId string) ([]SessionMessage, error) {
+	messages := make([]SessionMessage, 0)
+	err := d.db.Model(&SessionMessage{}).
+		Where("session_id = ?", sessionId).
+		Order("created_at ASC").
+		Order(`"index" ASC`).
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
@@ -90,7 +90,7 @@ func (t *MetorialTransport) SendRequest(ctx context.Context, request transport.J
 		return nil, fmt.Errorf("failed to parse MCP message: %w", err)
 	}
 
-	err = t.connection.AcceptMessage(msg)
+	err = t.connection.AcceptMessage(ctx, msg)
 	if err != nil {
 		return nil, err
 	}
@@ -122,7 +122,7 @@ func (t *MetorialTransport) SendNotification(ctx context.Context, notification m
 		return fmt.Errorf("failed to parse MCP message: %w", err)
 	}
 
-	err = t.connection.AcceptMessage(msg)
+	err = t.connection.AcceptMessage(ctx, msg)
 	if err != nil {
 		return err
 	}
@@ -150,7 +150,11 @@ func (t *MetorialTransport) handleMessages() {
 			return
 		case <-done:
 			return
-		case message := <-msgChan:
+		case message, ok := <-msgChan:
+			if !ok {
+				return
+			}
+
 			go func() {
 				switch message.MsgType {
 
@@ -0,0 +1,132 @@
+package launcher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	launcherPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/launcher"
+)
+
+const (
+	defaultCacheTTL        = 5 * time.Minute
+	defaultCacheMaxEntries = 1000
+)
+
+type cacheEntry struct {
+	result    *launcherPb.RunLauncherResponse
+	expiresAt time.Time
+}
+
+// resultCache keeps successful launcher results by a hash of the launcher
+// code and config, as launchers are pure functions of the two.
+type resultCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mutex   sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func newResultCache(ttl time.Duration, maxEntries int) *resultCache {
+	return &resultCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]cacheEntry),
+	}
+}
+
+func cacheKey(input *launcherPb.LauncherConfig) string {
+	hash := sha256.New()
+	hash.Write([]byte(input.LauncherType.String()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(input.Code))
+	hash.Write([]byte{0})
+	hash.Write([]byte(input.JsonConfig))
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// isCacheable reports whether results for a config may be cached. Configs
+// with OAuth tokens are not, the tokens change with every refresh and
+// shouldn't be kept around.
+func isCacheable(input *launcherPb.LauncherConfig) bool {
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(input.JsonConfig), &config); err != nil {
+		return false
+	}
+
+	oauth, ok := config["__metorial_oauth__"]
+	return !ok || string(oauth) == "null"
+}
+
+func (c *resultCache) get(input *launcherPb.LauncherConfig) (*launcherPb.RunLauncherResponse, bool) {
+	if !isCacheable(input) {
+		return nil, false
+	}
+
+	key := cacheKey(input)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	return entry.result, true
+}
+
+// set stores a result. Only successful results are kept, failures may be
+// caused by the launcher worker rather than the launcher.
+func (c *resultCache) set(input *launcherPb.LauncherConfig, result *launcherPb.RunLauncherResponse) {
+	if result.Type != launcherPb.RunLauncherResponse_success || !isCacheable(input) {
+		return
+	}
+
+	key := cacheKey(input)
+	now := time.Now()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+
+	c.entries[key] = cacheEntry{
+		result:    result,
+		expiresAt: now.Add(c.ttl),
+	}
+}
+
+// evict removes expired entries, or the entry expiring first if there are
+// none.
+func (c *resultCache) evict(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+			continue
+		}
+
+		if oldestKey == "" || entry.expiresAt.Before(oldest) {
+			oldestKey = key
+			oldest = entry.expiresAt
+		}
+	}
+
+	if len(c.entries) >= c.maxEntries && oldestKey != "" {
+		delete(c.entries, oldestKey)
+	}
+}
@@ -0,0 +1,128 @@
+package launcher
+
+import (
+	"testing"
+	"time"
+
+	launcherPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/launcher"
+)
+
+func testLauncherConfig(jsonConfig string) *launcherPb.LauncherConfig {
+	return &launcherPb.LauncherConfig{
+		LauncherType: launcherPb.LauncherConfig_embedded,
+		Code:         "export default () => ({})",
+		JsonConfig:   jsonConfig,
+	}
+}
+
+func successResponse() *launcherPb.RunLauncherResponse {
+	return &launcherPb.RunLauncherResponse{Type: launcherPb.RunLauncherResponse_success, JsonOutput: "{}"}
+}
+
+func TestResultCache_Cacheable(t *testing.T) {
+	tests := []struct {
+		name       string
+		jsonConfig string
+		want       bool
+	}{
+		{"plain config", `{"port":8080}`, true},
+		{"empty config", `{}`, true},
+		{"null oauth", `{"__metorial_oauth__":null}`, true},
+		{"oauth token", `{"__metorial_oauth__":{"accessToken":"token"}}`, false},
+		{"not an object", `[1,2]`, false},
+		{"invalid json", `{`, false},
+	}
+	for _, tt := range tests {
+		cache := newResultCache(time.Minute, 10)
+		input := testLauncherConfig(tt.jsonConfig)
+
+		cache.set(input, successResponse())
+		if _, got := cache.get(input); got != tt.want {
+			t.Errorf("%s: cached = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResultCache_OnlySuccess(t *testing.T) {
+	cache := newResultCache(time.Minute, 10)
+	input := testLauncherConfig(`{}`)
+
+	cache.set(input, &launcherPb.RunLauncherResponse{Type: launcherPb.RunLauncherResponse_error, ErrorMessage: "failed"})
+	if _, ok := cache.get(input); ok {
+		t.Errorf("expected failed results not to be cached")
+	}
+}
+
+func TestResultCache_Key(t *testing.T) {
+	base := testLauncherConfig(`{"a":1}`)
+
+	otherConfig := testLauncherConfig(`{"a":2}`)
+	otherCode := testLauncherConfig(`{"a":1}`)
+	otherCode.Code = "export default () => ({ a: 1 })"
+	otherType := testLauncherConfig(`{"a":1}`)
+	otherType.LauncherType = launcherPb.LauncherConfig_deno
+
+	for _, input := range []*launcherPb.LauncherConfig{otherConfig, otherCode, otherType} {
+		if cacheKey(input) == cacheKey(base) {
+			t.Errorf("expected %v to have a different key than %v", input, base)
+		}
+	}
+
+	if cacheKey(testLauncherConfig(`{"a":1}`)) != cacheKey(base) {
+		t.Errorf("expected equal configs to have the same key")
+	}
+}
+
+func TestResultCache_TTL(t *testing.T) {
+	cache := newResultCache(time.Minute, 10)
+	input := testLauncherConfig(`{}`)
+	result := successResponse()
+
+	cache.set(input, result)
+	if got, ok := cache.get(input); !ok || got != result {
+		t.Fatalf("expected the result to be cached")
+	}
+
+	key := cacheKey(input)
+	cache.entries[key] = cacheEntry{result: result, expiresAt: time.Now().Add(-time.Second)}
+
+	if _, ok := cache.get(input); ok {
+		t.Errorf("expected expired result not to be returned")
+	}
+	if _, ok := cache.entries[key]; ok {
+		t.Errorf("expected expired entry to be removed")
+	}
+}
+
+func TestResultCache_Eviction(t *testing.T) {
+	cache := newResultCache(time.Minute, 2)
+
+	first := testLauncherConfig(`{"n":1}`)
+	second := testLauncherConfig(`{"n":2}`)
+	third := testLauncherConfig(`{"n":3}`)
+
+	cache.set(first, successResponse())
+	cache.set(second, successResponse())
+	cache.entries[cacheKey(second)] = cacheEntry{result: successResponse(), expiresAt: time.Now().Add(30 * time.Second)}
+
+	cache.set(third, successResponse())
+	if len(cache.entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(cache.entries))
+	}
+	if _, ok := cache.get(second); ok {
+		t.Errorf("expected the entry expiring first to be evicted")
+	}
+	if _, ok := cache.get(first); !ok {
+		t.Errorf("expected newer entries to be kept")
+	}
+
+	// Expired entries are evicted before live ones
+	cache.entries[cacheKey(first)] = cacheEntry{result: successResponse(), expiresAt: time.Now().Add(-time.Second)}
+	cache.set(second, successResponse())
+	if _, ok := cache.get(third); !ok {
+		t.Errorf("expected live entries to be kept while there are expired ones")
+	}
+	if _, ok := cache.get(second); !ok {
+		t.Errorf("expected the new entry to be stored")
+	}
+}
@@ -0,0 +1,153 @@
+package launcher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	launcherPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/launcher"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+const maxCompiledSchemas = 256
+
+// FieldError is a validation error of a single field of a launcher config.
+type FieldError struct {
+	// Path is the JSON pointer of the field, e.g. "/port".
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ConfigError is returned when a launcher config doesn't match the JSON
+// Schema of its server.
+type ConfigError struct {
+	Fields []FieldError
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("config validation failed: %s", strings.Join(e.Messages(), ", "))
+}
+
+// Messages returns a message per field, like the ones of ValidationError.
+func (e *ConfigError) Messages() []string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		path := field.Path
+		if path == "" {
+			path = "/"
+		}
+
+		messages = append(messages, fmt.Sprintf("Field '%s': %s", path, field.Message))
+	}
+
+	return messages
+}
+
+// noLoader refuses to load referenced schemas, schemas must be self
+// contained. Loading them would let configs read files or make requests.
+type noLoader struct{}
+
+func (noLoader) Load(url string) (any, error) {
+	return nil, fmt.Errorf("loading referenced schema %s is not supported", url)
+}
+
+var schemaCache = struct {
+	mutex   sync.Mutex
+	schemas map[string]*jsonschema.Schema
+}{schemas: make(map[string]*jsonschema.Schema)}
+
+func compileConfigSchema(schema string) (*jsonschema.Schema, error) {
+	hash := sha256.Sum256([]byte(schema))
+	key := hex.EncodeToString(hash[:])
+
+	schemaCache.mutex.Lock()
+	defer schemaCache.mutex.Unlock()
+
+	if compiled, ok := schemaCache.schemas[key]; ok {
+		return compiled, nil
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(schema))
+	if err != nil {
+		return nil, fmt.Errorf("invalid config schema: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.UseLoader(noLoader{})
+	compiler.AssertFormat()
+
+	if err := compiler.AddResource("config-schema.json", doc); err != nil {
+		return nil, fmt.Errorf("invalid config schema: %w", err)
+	}
+
+	compiled, err := compiler.Compile("config-schema.json")
+	if err != nil {
+		return nil, fmt.Errorf("invalid config schema: %w", err)
+	}
+
+	if len(schemaCache.schemas) >= maxCompiledSchemas {
+		clear(schemaCache.schemas)
+	}
+	schemaCache.schemas[key] = compiled
+
+	return compiled, nil
+}
+
+// ValidateConfig validates the json config of a launcher against its JSON
+// Schema, if it has one. Mismatches are returned as a *ConfigError.
+func ValidateConfig(input *launcherPb.LauncherConfig) error {
+	if strings.TrimSpace(input.GetJsonSchema()) == "" {
+		return nil
+	}
+
+	schema, err := compileConfigSchema(input.JsonSchema)
+	if err != nil {
+		return err
+	}
+
+	config, err := jsonschema.UnmarshalJSON(strings.NewReader(input.JsonConfig))
+	if err != nil {
+		return &ConfigError{Fields: []FieldError{{Message: "is not valid JSON"}}}
+	}
+
+	// The OAuth token is added by the platform, not part of the server's
+	// config.
+	if object, ok := config.(map[string]any); ok {
+		delete(object, "__metorial_oauth__")
+	}
+
+	err = schema.Validate(config)
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+
+	configErr := &ConfigError{}
+	collectFieldErrors(validationErr.BasicOutput(), configErr)
+
+	if len(configErr.Fields) == 0 {
+		configErr.Fields = append(configErr.Fields, FieldError{Message: validationErr.Error()})
+	}
+
+	return configErr
+}
+
+func collectFieldErrors(unit *jsonschema.OutputUnit, configErr *ConfigError) {
+	if unit.Error != nil && len(unit.Errors) == 0 {
+		configErr.Fields = append(configErr.Fields, FieldError{
+			Path:    unit.InstanceLocation,
+			Message: unit.Error.String(),
+		})
+	}
+
+	for i := range unit.Errors {
+		collectFieldErrors(&unit.Errors[i], configErr)
+	}
+}
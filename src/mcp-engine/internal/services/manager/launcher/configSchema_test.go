@@ -0,0 +1,118 @@
+package launcher
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	launcherPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/launcher"
+)
+
+const testConfigSchema = `{
+	"type": "object",
+	"required": ["token", "port"],
+	"additionalProperties": false,
+	"properties": {
+		"token": {"type": "string", "minLength": 1},
+		"port": {"type": "integer", "minimum": 1, "maximum": 65535},
+		"url": {"type": "string", "format": "uri"},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"db": {
+			"type": "object",
+			"required": ["host"],
+			"properties": {"host": {"type": "string"}}
+		}
+	}
+}`
+
+func TestValidateConfig_FieldPaths(t *testing.T) {
+	tests := []struct {
+		name       string
+		jsonConfig string
+		want       []string
+	}{
+		{"valid", `{"token":"t","port":80}`, nil},
+		{"oauth token is ignored", `{"token":"t","port":80,"__metorial_oauth__":{"accessToken":"a"}}`, nil},
+		{"missing fields", `{}`, []string{""}},
+		{"wrong type", `{"token":"t","port":"80"}`, []string{"/port"}},
+		{"out of range", `{"token":"t","port":70000}`, []string{"/port"}},
+		{"invalid format", `{"token":"t","port":80,"url":"not a uri"}`, []string{"/url"}},
+		{"array item", `{"token":"t","port":80,"tags":["a",1]}`, []string{"/tags/1"}},
+		{"nested field", `{"token":"t","port":80,"db":{}}`, []string{"/db"}},
+		{"nested type", `{"token":"t","port":80,"db":{"host":1}}`, []string{"/db/host"}},
+		{"additional property", `{"token":"t","port":80,"extra":1}`, []string{""}},
+		{"several fields", `{"token":"","port":0}`, []string{"/port", "/token"}},
+		{"invalid json", `{`, []string{""}},
+	}
+	for _, tt := range tests {
+		err := ValidateConfig(&launcherPb.LauncherConfig{JsonConfig: tt.jsonConfig, JsonSchema: testConfigSchema})
+
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("%s: ValidateConfig() = %v, want nil", tt.name, err)
+			}
+			continue
+		}
+
+		var configErr *ConfigError
+		if !errors.As(err, &configErr) {
+			t.Errorf("%s: ValidateConfig() = %v, want a *ConfigError", tt.name, err)
+			continue
+		}
+
+		var paths []string
+		for _, field := range configErr.Fields {
+			if field.Message == "" {
+				t.Errorf("%s: field %q has no message", tt.name, field.Path)
+			}
+			paths = append(paths, field.Path)
+		}
+		sort.Strings(paths)
+
+		if !reflect.DeepEqual(paths, tt.want) {
+			t.Errorf("%s: ValidateConfig() paths = %q, want %q (%v)", tt.name, paths, tt.want, err)
+		}
+	}
+}
+
+func TestValidateConfig_NoSchema(t *testing.T) {
+	for _, schema := range []string{"", "  "} {
+		if err := ValidateConfig(&launcherPb.LauncherConfig{JsonConfig: `not json`, JsonSchema: schema}); err != nil {
+			t.Errorf("ValidateConfig() without schema %q = %v, want nil", schema, err)
+		}
+	}
+}
+
+func TestValidateConfig_InvalidSchema(t *testing.T) {
+	tests := []string{
+		`{`,
+		`{"type": 1}`,
+		`{"$ref": "https://example.com/schema.json"}`,
+		`{"$ref": "file:///etc/passwd"}`,
+	}
+	for _, schema := range tests {
+		err := ValidateConfig(&launcherPb.LauncherConfig{JsonConfig: `{}`, JsonSchema: schema})
+
+		var configErr *ConfigError
+		if err == nil || errors.As(err, &configErr) {
+			t.Errorf("ValidateConfig() with schema %s = %v, want a schema error", schema, err)
+		}
+	}
+}
+
+func TestConfigError_Messages(t *testing.T) {
+	err := &ConfigError{Fields: []FieldError{
+		{Path: "", Message: "missing property 'port'"},
+		{Path: "/port", Message: "must be >= 1"},
+	}}
+
+	want := []string{"Field '/': missing property 'port'", "Field '/port': must be >= 1"}
+	if got := err.Messages(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Messages() = %q, want %q", got, want)
+	}
+	if !strings.HasPrefix(err.Error(), "config validation failed: Field '/'") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
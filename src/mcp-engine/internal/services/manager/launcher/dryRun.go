@@ -0,0 +1,163 @@
+package launcher
+
+import (
+	"errors"
+	"time"
+
+	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
+	remotePb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/remote"
+	runnerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/runner"
+	mterror "github.com/metorial/metorial/mcp-engine/pkg/mtError"
+)
+
+// DryRun resolves the run config of a server config the way a launch
+// does, without starting anything. Secrets in the result are masked.
+func (l *Launcher) DryRun(config *managerPb.ServerConfig) (*managerPb.DryRunLauncherResponse, *mterror.MTError) {
+	startTime := time.Now()
+	trace := &launchTrace{}
+	masker := newSecretMasker("{}")
+
+	runConfig := &managerPb.DryRunRunConfig{}
+	var err error
+
+	switch {
+	case config.GetContainerRunConfigWithLauncher() != nil:
+		input := config.GetContainerRunConfigWithLauncher()
+		masker = newSecretMasker(input.GetLauncher().GetJsonConfig())
+
+		var resolved *runnerPb.RunConfig
+		resolved, err = l.getContainerLaunchParams(input, trace)
+		runConfig.Config = &managerPb.DryRunRunConfig_ContainerRunConfig{ContainerRunConfig: resolved}
+
+	case config.GetContainerRunConfigWithContainerArguments() != nil:
+		runConfig.Config = &managerPb.DryRunRunConfig_ContainerRunConfig{
+			ContainerRunConfig: config.GetContainerRunConfigWithContainerArguments(),
+		}
+
+	case config.GetRemoteRunConfigWithLauncher() != nil:
+		input := config.GetRemoteRunConfigWithLauncher()
+		masker = newSecretMasker(input.GetLauncher().GetJsonConfig())
+
+		var resolved *remotePb.RunConfigRemote
+		resolved, err = l.getRemoteLaunchParams(input, trace)
+		runConfig.Config = &managerPb.DryRunRunConfig_RemoteRunConfig{RemoteRunConfig: resolved}
+
+	case config.GetRemoteRunConfigWithServer() != nil:
+		runConfig.Config = &managerPb.DryRunRunConfig_RemoteRunConfig{
+			RemoteRunConfig: config.GetRemoteRunConfigWithServer(),
+		}
+
+	case config.GetLambdaRunConfigWithLauncher() != nil:
+		input := config.GetLambdaRunConfigWithLauncher()
+		masker = newSecretMasker(input.GetLauncher().GetJsonConfig())
+
+		var resolved *remotePb.RunConfigLambda
+		resolved, err = l.getLambdaLaunchParams(input, trace)
+		runConfig.Config = &managerPb.DryRunRunConfig_LambdaRunConfig{LambdaRunConfig: resolved}
+
+	case config.GetLambdaRunConfigWithServer() != nil:
+		runConfig.Config = &managerPb.DryRunRunConfig_LambdaRunConfig{
+			LambdaRunConfig: config.GetLambdaRunConfigWithServer(),
+		}
+
+	default:
+		return nil, mterror.New(mterror.InvalidRequestKind, "server config must contain a run config")
+	}
+
+	res := &managerPb.DryRunLauncherResponse{
+		Cached: trace.Cached,
+		Timings: &managerPb.DryRunLauncherTimings{
+			LauncherMs:   trace.LauncherDuration.Milliseconds(),
+			ValidationMs: trace.ValidationDuration.Milliseconds(),
+			TotalMs:      time.Since(startTime).Milliseconds(),
+		},
+	}
+
+	if err != nil {
+		var validationErr *ValidationError
+		var configErr *ConfigError
+
+		switch {
+		case trace.LauncherFailed:
+			res.LauncherError = masker.maskString(err.Error())
+		case errors.As(err, &configErr):
+			for _, message := range configErr.Messages() {
+				res.ValidationErrors = append(res.ValidationErrors, masker.maskString(message))
+			}
+		case errors.As(err, &validationErr):
+			for _, message := range validationErr.Messages {
+				res.ValidationErrors = append(res.ValidationErrors, masker.maskString(message))
+			}
+		default:
+			res.ValidationErrors = []string{masker.maskString(err.Error())}
+		}
+
+		return res, nil
+	}
+
+	res.Success = true
+	res.RunConfig = maskRunConfig(masker, runConfig)
+
+	return res, nil
+}
+
+// maskRunConfig returns a copy of the run config with secrets masked.
+func maskRunConfig(masker *secretMasker, runConfig *managerPb.DryRunRunConfig) *managerPb.DryRunRunConfig {
+	switch config := runConfig.Config.(type) {
+	case *managerPb.DryRunRunConfig_ContainerRunConfig:
+		masked := &runnerPb.RunConfig{Container: config.ContainerRunConfig.GetContainer()}
+
+		if arguments := config.ContainerRunConfig.GetArguments(); arguments != nil {
+			masked.Arguments = &runnerPb.RunConfigContainerArguments{
+				Command: masker.maskString(arguments.Command),
+				Args:    masker.maskArgs(arguments.Args),
+				EnvVars: masker.maskMap(arguments.EnvVars),
+			}
+		}
+
+		return &managerPb.DryRunRunConfig{Config: &managerPb.DryRunRunConfig_ContainerRunConfig{ContainerRunConfig: masked}}
+
+	case *managerPb.DryRunRunConfig_RemoteRunConfig:
+		masked := &remotePb.RunConfigRemote{}
+
+		if server := config.RemoteRunConfig.GetServer(); server != nil {
+			masked.Server = &remotePb.RunConfigRemoteServer{
+				ServerUri: masker.maskString(server.ServerUri),
+				Protocol:  server.Protocol,
+			}
+		}
+
+		if arguments := config.RemoteRunConfig.GetArguments(); arguments != nil {
+			masked.Arguments = &remotePb.RunConfigRemoteArguments{
+				Headers: masker.maskMap(arguments.Headers),
+				Query:   masker.maskMap(arguments.Query),
+			}
+		}
+
+		return &managerPb.DryRunRunConfig{Config: &managerPb.DryRunRunConfig_RemoteRunConfig{RemoteRunConfig: masked}}
+
+	case *managerPb.DryRunRunConfig_LambdaRunConfig:
+		masked := &remotePb.RunConfigLambda{}
+
+		if server := config.LambdaRunConfig.GetServer(); server != nil {
+			masked.Server = &remotePb.RunConfigLambdaServer{
+				Protocol:                         server.Protocol,
+				ProviderResourceAccessIdentifier: server.ProviderResourceAccessIdentifier,
+			}
+			if server.SecurityToken != nil {
+				securityToken := maskedValue
+				masked.Server.SecurityToken = &securityToken
+			}
+		}
+
+		if arguments := config.LambdaRunConfig.GetArguments(); arguments != nil {
+			masked.Arguments = &remotePb.RunConfigLambdaArguments{
+				JsonArguments: masker.maskJSON(arguments.JsonArguments),
+			}
+		}
+
+		return &managerPb.DryRunRunConfig{Config: &managerPb.DryRunRunConfig_LambdaRunConfig{LambdaRunConfig: masked}}
+	}
+
+	return runConfig
+}
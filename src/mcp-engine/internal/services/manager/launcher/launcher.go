@@ -1,9 +1,10 @@
 package launcher
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/url"
 	"time"
 
@@ -12,19 +13,35 @@ import (
 	remotePb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/remote"
 	runnerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/runner"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/workers"
+	"github.com/metorial/metorial/mcp-engine/pkg/jsLauncher"
 	"github.com/metorial/metorial/modules/util"
 )
 
 type Launcher struct {
 	workerManager *workers.WorkerManager
+	cache         *resultCache
+	logger        *slog.Logger
 }
 
 func NewLauncher(workers *workers.WorkerManager) *Launcher {
 	return &Launcher{
 		workerManager: workers,
+		cache:         newResultCache(defaultCacheTTL, defaultCacheMaxEntries),
+		logger:        workers.Logger(),
 	}
 }
 
+// launchTrace records how launch params were resolved, for dry runs.
+type launchTrace struct {
+	Cached             bool
+	LauncherDuration   time.Duration
+	ValidationDuration time.Duration
+
+	// LauncherFailed is set if running the launcher failed, as opposed to
+	// validating its output.
+	LauncherFailed bool
+}
+
 type ContainerLaunchParams struct {
 	Command string            `json:"command" validate:"required"`
 	Args    []string          `json:"args,omitempty"`
@@ -32,7 +49,11 @@ type ContainerLaunchParams struct {
 }
 
 func (l *Launcher) GetContainerLaunchParams(input *managerPb.ContainerRunConfigWithLauncher) (*runnerPb.RunConfig, error) {
-	params, err := GetTypedLaunchParams[ContainerLaunchParams](l.workerManager, input.Launcher)
+	return l.getContainerLaunchParams(input, &launchTrace{})
+}
+
+func (l *Launcher) getContainerLaunchParams(input *managerPb.ContainerRunConfigWithLauncher, trace *launchTrace) (*runnerPb.RunConfig, error) {
+	params, err := getTypedLaunchParams[ContainerLaunchParams](l, input.Launcher, trace)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +85,11 @@ type RemoteLaunchParams struct {
 }
 
 func (l *Launcher) GetRemoteLaunchParams(input *managerPb.RemoteRunConfigWithLauncher) (*remotePb.RunConfigRemote, error) {
-	params, err := GetTypedLaunchParams[RemoteLaunchParams](l.workerManager, input.Launcher)
+	return l.getRemoteLaunchParams(input, &launchTrace{})
+}
+
+func (l *Launcher) getRemoteLaunchParams(input *managerPb.RemoteRunConfigWithLauncher, trace *launchTrace) (*remotePb.RunConfigRemote, error) {
+	params, err := getTypedLaunchParams[RemoteLaunchParams](l, input.Launcher, trace)
 	if err != nil {
 		return nil, err
 	}
@@ -136,7 +161,11 @@ type LambdaLaunchParams struct {
 }
 
 func (l *Launcher) GetLambdaLaunchParams(input *managerPb.LambdaRunConfigWithLauncher) (*remotePb.RunConfigLambda, error) {
-	params, err := GetTypedLaunchParams[LambdaLaunchParams](l.workerManager, input.Launcher)
+	return l.getLambdaLaunchParams(input, &launchTrace{})
+}
+
+func (l *Launcher) getLambdaLaunchParams(input *managerPb.LambdaRunConfigWithLauncher, trace *launchTrace) (*remotePb.RunConfigLambda, error) {
+	params, err := getTypedLaunchParams[LambdaLaunchParams](l, input.Launcher, trace)
 	if err != nil {
 		return nil, err
 	}
@@ -149,28 +178,68 @@ func (l *Launcher) GetLambdaLaunchParams(input *managerPb.LambdaRunConfigWithLau
 	}, nil
 }
 
-func GetTypedLaunchParams[T any](workerManager *workers.WorkerManager, input *launcherPb.LauncherConfig) (T, error) {
-	var zero T
+// runLauncher returns the cached result of a launcher if there is one,
+// otherwise embedded launchers are run in-process and all others on a
+// launcher worker.
+func (l *Launcher) runLauncher(input *launcherPb.LauncherConfig, trace *launchTrace) (*launcherPb.RunLauncherResponse, error) {
+	if result, ok := l.cache.get(input); ok {
+		trace.Cached = true
+		return result, nil
+	}
 
-	startTime := time.Now()
+	var result *launcherPb.RunLauncherResponse
+	if input.LauncherType == launcherPb.LauncherConfig_embedded {
+		result = jsLauncher.RunLauncher(context.Background(), input)
+	} else {
+		worker, exists := l.workerManager.PickWorkerRandomly(workers.WorkerTypeLauncher)
+		if !exists {
+			return nil, fmt.Errorf("no available launcher worker found")
+		}
 
-	worker, exists := workerManager.PickWorkerRandomly(workers.WorkerTypeLauncher)
-	if !exists {
-		return zero, fmt.Errorf("no available launcher worker found")
+		var err error
+		result, err = worker.RunLauncher(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to run launcher: %w", err)
+		}
 	}
 
-	result, err := worker.RunLauncher(input)
+	l.cache.set(input, result)
+
+	return result, nil
+}
+
+func getTypedLaunchParams[T any](l *Launcher, input *launcherPb.LauncherConfig, trace *launchTrace) (T, error) {
+	var zero T
+
+	if err := ValidateConfig(input); err != nil {
+		return zero, err
+	}
+
+	startTime := time.Now()
+
+	result, err := l.runLauncher(input, trace)
+	trace.LauncherDuration = time.Since(startTime)
 	if err != nil {
-		return zero, fmt.Errorf("failed to run launcher: %w", err)
+		trace.LauncherFailed = true
+		return zero, err
 	}
 
-	duration := time.Since(startTime)
-	log.Printf("Launcher executed in %s", duration.String())
+	l.logger.Info("launcher resolved",
+		slog.String("launcher_type", input.LauncherType.String()),
+		slog.Bool("cached", trace.Cached),
+		slog.Duration("duration", trace.LauncherDuration),
+	)
 
 	if result.Type != launcherPb.RunLauncherResponse_success {
+		trace.LauncherFailed = true
 		return zero, fmt.Errorf("launch params execution failed: %s", result.ErrorMessage)
 	}
 
+	validationStart := time.Now()
+	defer func() {
+		trace.ValidationDuration = time.Since(validationStart)
+	}()
+
 	var target T
 	err = ValidateAndConvert(result, &target)
 	if err != nil {
@@ -0,0 +1,145 @@
+package launcher
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const maskedValue = "********"
+
+// Values shorter than this are not masked where they appear in other
+// values, they would mask unrelated parts.
+const minSecretLength = 4
+
+var sensitiveNamePattern = regexp.MustCompile(`(?i)(token|secret|passw|api[-_]?key|private[-_]?key|access[-_]?key|auth|credential|cookie|^key$)`)
+
+func isSensitiveName(name string) bool {
+	return sensitiveNamePattern.MatchString(strings.TrimLeft(name, "-"))
+}
+
+// secretMasker masks secrets in resolved run configs. Secrets are the
+// values of config fields with sensitive names and OAuth tokens, wherever
+// they appear, and the values of headers, env vars and flags with
+// sensitive names.
+type secretMasker struct {
+	secrets []string
+}
+
+func newSecretMasker(jsonConfig string) *secretMasker {
+	masker := &secretMasker{}
+
+	var config any
+	if err := json.Unmarshal([]byte(jsonConfig), &config); err == nil {
+		masker.collect("", config, false)
+	}
+
+	// Longer secrets first, so secrets containing others are masked whole.
+	sort.Slice(masker.secrets, func(i, j int) bool {
+		return len(masker.secrets[i]) > len(masker.secrets[j])
+	})
+
+	return masker
+}
+
+func (m *secretMasker) collect(name string, value any, sensitive bool) {
+	sensitive = sensitive || name == "__metorial_oauth__" || isSensitiveName(name)
+
+	switch value := value.(type) {
+	case map[string]any:
+		for key, child := range value {
+			m.collect(key, child, sensitive)
+		}
+	case []any:
+		for _, child := range value {
+			m.collect(name, child, sensitive)
+		}
+	case string:
+		if sensitive && len(value) >= minSecretLength {
+			m.secrets = append(m.secrets, value)
+		}
+	}
+}
+
+func (m *secretMasker) maskString(value string) string {
+	for _, secret := range m.secrets {
+		value = strings.ReplaceAll(value, secret, maskedValue)
+	}
+
+	return value
+}
+
+func (m *secretMasker) maskNamed(name, value string) string {
+	if value != "" && isSensitiveName(name) {
+		return maskedValue
+	}
+
+	return m.maskString(value)
+}
+
+func (m *secretMasker) maskMap(values map[string]string) map[string]string {
+	masked := make(map[string]string, len(values))
+	for name, value := range values {
+		masked[name] = m.maskNamed(name, value)
+	}
+
+	return masked
+}
+
+// maskArgs masks the values of sensitive flags, given as "--flag value"
+// or "--flag=value".
+func (m *secretMasker) maskArgs(args []string) []string {
+	masked := make([]string, len(args))
+
+	for i, arg := range args {
+		if name, value, ok := strings.Cut(arg, "="); ok && strings.HasPrefix(name, "-") {
+			masked[i] = name + "=" + m.maskNamed(name, value)
+			continue
+		}
+
+		if i > 0 && strings.HasPrefix(args[i-1], "-") && !strings.HasPrefix(arg, "-") {
+			masked[i] = m.maskNamed(args[i-1], arg)
+			continue
+		}
+
+		masked[i] = m.maskString(arg)
+	}
+
+	return masked
+}
+
+func (m *secretMasker) maskJSON(value string) string {
+	var parsed any
+	if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+		return m.maskString(value)
+	}
+
+	masked, err := json.Marshal(m.maskJSONValue("", parsed))
+	if err != nil {
+		return m.maskString(value)
+	}
+
+	return string(masked)
+}
+
+func (m *secretMasker) maskJSONValue(name string, value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		masked := make(map[string]any, len(value))
+		for key, child := range value {
+			masked[key] = m.maskJSONValue(key, child)
+		}
+		return masked
+	case []any:
+		masked := make([]any, len(value))
+		for i, child := range value {
+			masked[i] = m.maskJSONValue(name, child)
+		}
+		return masked
+	case string:
+		return m.maskNamed(name, value)
+	default:
+		return value
+	}
+}
@@ -0,0 +1,107 @@
+package launcher
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testMaskConfig = `{
+	"apiKey": "sk-live-123456",
+	"region": "eu-west-1",
+	"nested": {"password": "hunter22"},
+	"short": {"token": "abc"},
+	"__metorial_oauth__": {"accessToken": "oauth-access-token", "expiresAt": 123}
+}`
+
+func TestSecretMasker_Args(t *testing.T) {
+	masker := newSecretMasker(testMaskConfig)
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"flag value", []string{"--token", "plain-value"}, []string{"--token", maskedValue}},
+		{"flag equals value", []string{"--api-key=plain-value"}, []string{"--api-key=" + maskedValue}},
+		{"single dash flag", []string{"-password", "plain-value"}, []string{"-password", maskedValue}},
+		{"insensitive flag", []string{"--region", "eu-west-1"}, []string{"--region", "eu-west-1"}},
+		{"flag followed by flag", []string{"--auth", "--verbose"}, []string{"--auth", "--verbose"}},
+		{"empty sensitive value", []string{"--token="}, []string{"--token="}},
+		{"config secret in positional", []string{"connect", "sk-live-123456"}, []string{"connect", maskedValue}},
+		{"config secret inside value", []string{"--url=https://x.dev/?k=sk-live-123456"}, []string{"--url=https://x.dev/?k=" + maskedValue}},
+		{"oauth token", []string{"--header", "Bearer oauth-access-token"}, []string{"--header", "Bearer " + maskedValue}},
+		{"short secret is kept", []string{"run", "abc"}, []string{"run", "abc"}},
+	}
+	for _, tt := range tests {
+		if got := masker.maskArgs(tt.args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: maskArgs(%q) = %q, want %q", tt.name, tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestSecretMasker_Map(t *testing.T) {
+	masker := newSecretMasker(testMaskConfig)
+
+	headers := map[string]string{
+		"Authorization": "Bearer anything",
+		"X-Api-Key":     "anything",
+		"Cookie":        "session=1",
+		"X-Region":      "eu-west-1",
+		"X-Forwarded":   "hunter22",
+		"X-Empty-Token": "",
+	}
+	want := map[string]string{
+		"Authorization": maskedValue,
+		"X-Api-Key":     maskedValue,
+		"Cookie":        maskedValue,
+		"X-Region":      "eu-west-1",
+		"X-Forwarded":   maskedValue,
+		"X-Empty-Token": "",
+	}
+
+	if got := masker.maskMap(headers); !reflect.DeepEqual(got, want) {
+		t.Errorf("maskMap() = %v, want %v", got, want)
+	}
+	if headers["Authorization"] != "Bearer anything" {
+		t.Errorf("maskMap() mutated its input")
+	}
+}
+
+func TestSecretMasker_JSON(t *testing.T) {
+	masker := newSecretMasker(testMaskConfig)
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"sensitive field", `{"token":"anything","port":8080}`, `{"port":8080,"token":"` + maskedValue + `"}`},
+		{"nested field", `{"db":{"password":"anything","host":"db"}}`, `{"db":{"host":"db","password":"` + maskedValue + `"}}`},
+		{"sensitive array", `{"keys":["a"],"apiKeys":["one","two"]}`, `{"apiKeys":["` + maskedValue + `","` + maskedValue + `"],"keys":["a"]}`},
+		{"config secret", `{"url":"https://x.dev/?k=sk-live-123456"}`, `{"url":"https://x.dev/?k=` + maskedValue + `"}`},
+		{"oauth token", `{"header":"Bearer oauth-access-token"}`, `{"header":"Bearer ` + maskedValue + `"}`},
+		{"invalid json", `not json sk-live-123456`, `not json ` + maskedValue},
+	}
+	for _, tt := range tests {
+		// Keys are sorted when the masked value is encoded again
+		if got := masker.maskJSON(tt.value); got != tt.want {
+			t.Errorf("%s: maskJSON(%s) = %s, want %s", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSecretMasker_LongestSecretFirst(t *testing.T) {
+	masker := newSecretMasker(`{"token":"secret","password":"secret-and-more"}`)
+
+	if got := masker.maskString("x secret-and-more y"); got != "x "+maskedValue+" y" {
+		t.Errorf("maskString() = %q, want the longer secret masked whole", got)
+	}
+}
+
+func TestSecretMasker_InvalidConfig(t *testing.T) {
+	masker := newSecretMasker("not json")
+
+	if got := masker.maskArgs([]string{"--token", "value", "plain"}); !reflect.DeepEqual(got, []string{"--token", maskedValue, "plain"}) {
+		t.Errorf("maskArgs() = %q, expected flag names to be masked without a config", got)
+	}
+}
@@ -9,6 +9,16 @@ import (
 	launcherPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/launcher"
 )
 
+// ValidationError lists the fields of a launcher output that failed
+// validation.
+type ValidationError struct {
+	Messages []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation failed: %s", strings.Join(e.Messages, ", "))
+}
+
 func ValidateAndConvert[T any](result *launcherPb.RunLauncherResponse, target *T) error {
 	if result.Type != launcherPb.RunLauncherResponse_success {
 		return fmt.Errorf("result is not successful: %v", result.ErrorMessage)
@@ -31,7 +41,7 @@ func ValidateAndConvert[T any](result *launcherPb.RunLauncherResponse, target *T
 		for _, err := range err.(validator.ValidationErrors) {
 			errorMessages = append(errorMessages, fmt.Sprintf("Field '%s' failed validation: %s", err.Field(), err.Tag()))
 		}
-		return fmt.Errorf("validation failed: %s", strings.Join(errorMessages, ", "))
+		return &ValidationError{Messages: errorMessages}
 	}
 
 	return nil
@@ -3,6 +3,7 @@ package manager
 import (
 	"context"
 	"log"
+	"log/slog"
 	"net"
 	"strconv"
 
@@ -17,6 +18,7 @@ import (
 	remoteWorker "github.com/metorial/metorial/mcp-engine/internal/services/manager/workers/remote-worker"
 	runnerWorker "github.com/metorial/metorial/mcp-engine/internal/services/manager/workers/runner-worker"
 	grpc_util "github.com/metorial/metorial/mcp-engine/pkg/grpcUtil"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/modules/addr"
 	"google.golang.org/grpc/reflection"
 )
@@ -31,6 +33,8 @@ type Manager struct {
 	workerServer  *workerBrokerServer
 	workers       *workers.WorkerManager
 	sessionServer *session.SessionServer
+
+	logger *slog.Logger
 }
 
 type StandaloneWorker struct {
@@ -57,7 +61,9 @@ func NewManager(db *db.DB, stateConfig state.Config, mangerAddress, workerBroker
 		return nil, err
 	}
 
-	workersManager := workers.NewWorkerManager()
+	logger := slog.Default().With(logging.ManagerId(sm.ManagerID))
+
+	workersManager := workers.NewWorkerManager(logger)
 
 	for _, sw := range standaloneWorkers {
 		var workerInstance workers.Worker
@@ -72,7 +78,11 @@ func NewManager(db *db.DB, stateConfig state.Config, mangerAddress, workerBroker
 			workerInstance = remoteWorker.NewRemoteWorker(context.Background(), workersManager, id, sw.Address, true)
 		}
 
-		log.Printf("Registering standalone worker %s of type %s at address %s", workerInstance.WorkerID(), sw.Type, sw.Address)
+		logger.Info("registering standalone worker",
+			logging.WorkerId(workerInstance.WorkerID()),
+			slog.String("worker_type", string(sw.Type)),
+			slog.String("address", sw.Address),
+		)
 
 		if err := workersManager.RegisterWorker(workerInstance); err != nil {
 			log.Panicf("failed to register standalone worker %s at %s: %v", workerInstance.WorkerID(), workerInstance.Address(), err)
@@ -90,6 +100,8 @@ func NewManager(db *db.DB, stateConfig state.Config, mangerAddress, workerBroker
 		workers:       workersManager,
 		workerServer:  &workerBrokerServer{state: sm, workerManager: workersManager},
 		sessionServer: session.NewSessionServer(db, sm, workersManager),
+
+		logger: logger,
 	}, nil
 }
 
@@ -109,11 +121,11 @@ func (m *Manager) Start() error {
 	}
 	reflection.Register(managerServer)
 
-	log.Printf("Starting manager server at %s", managerAddress)
+	m.logger.Info("starting manager server", slog.String("address", managerAddress))
 
 	go func() {
 		if err := managerServer.Serve(lis); err != nil {
-			log.Printf("Manager server at %s exited with error: %v", managerAddress, err)
+			m.logger.Error("manager server exited", slog.String("address", managerAddress), logging.Err(err))
 		}
 	}()
 
@@ -129,11 +141,11 @@ func (m *Manager) Start() error {
 		workerBrokerPb.RegisterMcpWorkerBrokerServer(workerBrokerServer, m.workerServer)
 		reflection.Register(workerBrokerServer)
 
-		log.Printf("Starting worker broker server at %s", workerBrokerAddress)
+		m.logger.Info("starting worker broker server", slog.String("address", workerBrokerAddress))
 
 		go func() {
 			if err := workerBrokerServer.Serve(lis); err != nil {
-				log.Printf("Worker broker server at %s exited with error: %v", workerBrokerAddress, err)
+				m.logger.Error("worker broker server exited", slog.String("address", workerBrokerAddress), logging.Err(err))
 			}
 		}()
 	}
@@ -2,18 +2,19 @@ package session
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/google/uuid"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/workers"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
 	"github.com/metorial/metorial/modules/util"
 
 	mterror "github.com/metorial/metorial/mcp-engine/pkg/mtError"
 )
 
-func createConnection(workerManager *workers.WorkerManager, connectionInput *workers.WorkerConnectionInput, mcpClient *mcp.MCPClient, workerType workers.WorkerType) (workers.WorkerConnection, workers.Worker, *mterror.MTError) {
+func createConnection(logger *slog.Logger, workerManager *workers.WorkerManager, connectionInput *workers.WorkerConnectionInput, mcpClient *mcp.MCPClient, workerType workers.WorkerType) (workers.WorkerConnection, workers.Worker, *mterror.MTError) {
 	// Update the connection input with the session ID and a new connection ID
 	connectionInput.ConnectionID = util.Must(uuid.NewV7()).String()
 	connectionInput.MCPClient = mcpClient
@@ -22,13 +23,13 @@ func createConnection(workerManager *workers.WorkerManager, connectionInput *wor
 	if err != nil {
 		sentry.CaptureException(err)
 
-		log.Printf("Failed to get connection hash for worker type %s: %v", workerType, err)
+		logger.Error("failed to get connection hash", slog.String("worker_type", string(workerType)), logging.Err(err))
 		return nil, nil, mterror.NewWithInnerError(mterror.InternalErrorKind, fmt.Sprintf("failed to get connection hash for worker type: %s", err.Error()), err)
 	}
 
 	worker, ok := workerManager.PickWorkerByHash(workerType, hash)
 	if !ok {
-		log.Printf("No available worker for worker type %s with hash %s", workerType, hash)
+		logger.Warn("no available worker", slog.String("worker_type", string(workerType)), slog.String("hash", string(hash)))
 		return nil, nil, mterror.NewWithCodeAndInnerError(mterror.InternalErrorKind, "run_error", "no available worker for worker type", err)
 	}
 
@@ -36,7 +37,7 @@ func createConnection(workerManager *workers.WorkerManager, connectionInput *wor
 	if err != nil {
 		sentry.CaptureException(err)
 
-		log.Printf("Failed to create connection for worker %s: %v", worker.WorkerID(), err)
+		logger.Error("failed to create connection", logging.WorkerId(worker.WorkerID()), logging.Err(err))
 		return nil, nil, mterror.NewWithCodeAndInnerError(mterror.InternalErrorKind, "run_error", "failed to create connection for worker", err)
 	}
 
@@ -1,46 +1,50 @@
 package session
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/metorial/metorial/mcp-engine/internal/db"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/client"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/workers"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 )
 
-func shouldDiscoverServer(server *db.Server) bool {
+func shouldDiscoverServer(logger *slog.Logger, server *db.Server) bool {
 	if server == nil {
-		log.Printf("Server is nil, skipping discovery check")
+		logger.Debug("server is nil, skipping discovery check")
 		return false
 	}
 
 	if server.LastDiscoveryAt.Valid &&
 		time.Since(server.LastDiscoveryAt.Time) < time.Hour*24 {
-		log.Printf("Server for server %s was discovered recently, skipping discovery", server.ID)
+		logger.Debug("server was discovered recently, skipping discovery", slog.String("server_id", server.ID))
 		return false
 	}
 
 	if server.DiscoveryErroredAt.Valid &&
 		time.Since(server.DiscoveryErroredAt.Time) < time.Hour*2 {
-		log.Printf("Server discovery for server %s is still in error state, skipping discovery", server.ID)
+		logger.Debug("server discovery is still in error state, skipping discovery", slog.String("server_id", server.ID))
 		return false
 	}
 
 	return true
 }
 
-func discoverServer(db_ *db.DB, server *db.Server, connection workers.WorkerConnection, force bool) error {
-	if !force && !shouldDiscoverServer(server) {
+func discoverServer(logger *slog.Logger, db_ *db.DB, server *db.Server, connection workers.WorkerConnection, force bool) error {
+	if !force && !shouldDiscoverServer(logger, server) {
 		return nil
 	}
 
-	log.Printf("Discovering server for server %s with connection %s", server.ID, connection.ConnectionID())
+	logger = logger.With(logging.RunId(connection.ConnectionID()))
+
+	logger.Info("discovering server")
 
 	err := client.WithClient(connection, func(c *client.Client) error {
-		log.Printf("Discovering server and applying updates for server %s", server.ID)
+		logger.Debug("applying discovered server updates")
 
 		c.DiscoverServerAndApplyUpdates(server)
 		server.LastDiscoveryAt = db.NullTimeNow()
@@ -52,7 +56,7 @@ func discoverServer(db_ *db.DB, server *db.Server, connection workers.WorkerConn
 	if err != nil {
 		sentry.CaptureException(err)
 
-		log.Printf("Failed to discover server for server %s: %v", server.ID, err)
+		logger.Warn("failed to discover server", logging.Err(err))
 
 		server.DiscoveryErroredAt = db.NullTimeNow()
 		server.DiscoveryCount++
@@ -62,20 +66,22 @@ func discoverServer(db_ *db.DB, server *db.Server, connection workers.WorkerConn
 	return err
 }
 
-func discoverServerWithEphemeralConnection(db_ *db.DB, server *db.Server, connection workers.WorkerConnection) error {
-	if !shouldDiscoverServer(server) {
+func discoverServerWithEphemeralConnection(logger *slog.Logger, db_ *db.DB, server *db.Server, connection workers.WorkerConnection) error {
+	if !shouldDiscoverServer(logger, server) {
 		return nil
 	}
 
-	log.Printf("Creating ephemeral connection for server %s with connection %s", server.ID, connection.ConnectionID())
+	logger = logger.With(slog.String("server_id", server.ID))
+
+	logger.Info("creating ephemeral connection for discovery", slog.String("original_run_id", connection.ConnectionID()))
 
-	err := withEphemeralConnectionForAutoDiscovery(connection, func(conn workers.WorkerConnection) error {
-		discoverServer(db_, server, conn, true)
+	err := withEphemeralConnectionForAutoDiscovery(logger, connection, func(conn workers.WorkerConnection) error {
+		discoverServer(logger, db_, server, conn, true)
 		return nil
 	})
 
 	if err != nil {
-		log.Printf("Failed to create ephemeral connection for server %s: %v", server.ID, err)
+		logger.Warn("failed to create ephemeral connection", logging.Err(err))
 
 		server.DiscoveryErroredAt = db.NullTimeNow()
 		server.DiscoveryCount++
@@ -85,7 +91,7 @@ func discoverServerWithEphemeralConnection(db_ *db.DB, server *db.Server, connec
 	return err
 }
 
-func withEphemeralConnectionForAutoDiscovery(originalConnection workers.WorkerConnection, fn func(conn workers.WorkerConnection) error) error {
+func withEphemeralConnectionForAutoDiscovery(logger *slog.Logger, originalConnection workers.WorkerConnection, fn func(conn workers.WorkerConnection) error) error {
 	connection, err := originalConnection.Clone()
 	if err != nil {
 		return err
@@ -94,11 +100,11 @@ func withEphemeralConnectionForAutoDiscovery(originalConnection workers.WorkerCo
 	defer func() {
 		err := connection.Close()
 		if err != nil {
-			log.Printf("Failed to close ephemeral connection: %v", err)
+			logger.Warn("failed to close ephemeral connection", logging.RunId(connection.ConnectionID()), logging.Err(err))
 		}
 	}()
 
-	connection.Start(false)
+	connection.Start(context.Background(), false)
 
 	return fn(connection)
 }
@@ -1,11 +1,13 @@
 package session
 
 import (
-	"log"
+	"context"
+	"log/slog"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/metorial/metorial/mcp-engine/internal/db"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/workers"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	mterror "github.com/metorial/metorial/mcp-engine/pkg/mtError"
 )
 
@@ -15,7 +17,9 @@ func discoverManual(
 	server *db.Server,
 	force bool,
 ) (*db.Server, *mterror.MTError) {
-	connection, _, err := createConnection(sessions.workerManager, connectionInput, nil, connectionInput.WorkerType)
+	logger := sessions.logger.With(slog.String("server_id", server.ID))
+
+	connection, _, err := createConnection(logger, sessions.workerManager, connectionInput, nil, connectionInput.WorkerType)
 	if err != nil {
 		sentry.CaptureException(err)
 		return nil, err
@@ -23,17 +27,17 @@ func discoverManual(
 
 	defer func() {
 		if err := connection.Close(); err != nil {
-			log.Printf("Failed to close connection for server %s: %v", server.ID, err)
+			logger.Warn("failed to close discovery connection", logging.Err(err))
 		}
 	}()
 
-	err2 := connection.Start(false)
+	err2 := connection.Start(context.Background(), false)
 	if err2 != nil {
 		sentry.CaptureException(err2)
 		return nil, mterror.NewWithInnerError(mterror.InternalErrorKind, "failed to start connection", err2)
 	}
 
-	err2 = discoverServer(sessions.db, server, connection, force)
+	err2 = discoverServer(logger, sessions.db, server, connection, force)
 	if err2 != nil {
 		sentry.CaptureException(err2)
 		return nil, mterror.NewWithInnerError(mterror.InternalErrorKind, "failed to discover server", err2)
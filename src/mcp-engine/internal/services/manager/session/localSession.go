@@ -3,7 +3,7 @@ package session
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"slices"
 	"sync"
 	"sync/atomic"
@@ -15,9 +15,13 @@ import (
 	"github.com/metorial/metorial/mcp-engine/internal/db"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/state"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/workers"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
 	mterror "github.com/metorial/metorial/mcp-engine/pkg/mtError"
+	"github.com/metorial/metorial/mcp-engine/pkg/tracing"
 	"github.com/metorial/metorial/modules/pubsub"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 )
 
@@ -64,6 +68,8 @@ type LocalSession struct {
 
 	workerManager *workers.WorkerManager
 
+	logger *slog.Logger
+
 	mutex sync.RWMutex
 }
 
@@ -110,7 +116,9 @@ func newLocalSession(
 
 		workerManager: sessions.workerManager,
 
-		internalMessages: pubsub.NewBroadcaster[*mcp.MCPMessage](),
+		logger: sessions.logger.With(logging.SessionId(storedSession.ID)),
+
+		internalMessages: pubsub.NewBroadcasterWithOptions[*mcp.MCPMessage](pubsub.LosslessBroadcasterOptions()),
 
 		context: ctx,
 		cancel:  cancel,
@@ -118,8 +126,15 @@ func newLocalSession(
 }
 
 func (s *LocalSession) SendMcpMessage(req *managerPb.SendMcpMessageRequest, stream grpc.ServerStreamingServer[managerPb.McpConnectionStreamResponse]) *mterror.MTError {
+	ctx, span := tracing.Start(stream.Context(), "session.SendMcpMessage", trace.WithAttributes(
+		attribute.String("session_id", s.storedSession.ID),
+		attribute.Int("mcp.message_count", len(req.McpMessages)),
+	))
+	defer span.End()
+
 	initMessage, mcpMessages, err := s.parseMessages(req)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return err
 	}
 
@@ -130,9 +145,10 @@ func (s *LocalSession) SendMcpMessage(req *managerPb.SendMcpMessageRequest, stre
 	// Now we need to get a handle on the connection
 	// this will block until the MCP client is set
 	// and the connection is established.
-	connection, run, err := s.ensureConnection()
+	connection, run, err := s.ensureConnection(ctx)
 	if err != nil {
 		sentry.CaptureException(err)
+		tracing.RecordError(span, err)
 		return err
 	}
 
@@ -140,8 +156,17 @@ func (s *LocalSession) SendMcpMessage(req *managerPb.SendMcpMessageRequest, stre
 		return mterror.New(mterror.InternalErrorKind, "no active connection for session")
 	}
 
+	span.SetAttributes(attribute.String("run_id", run.ID))
+
+	logger := s.runLogger(run)
+
 	go s.PersistMessages(run, db.SessionMessageSenderClient, mcpMessages)
 
+	// Request spans stay open until the matching response is seen
+	// (or the wait below ends), so the trace shows the full round trip.
+	requestSpans := tracing.NewSpanTracker()
+	defer requestSpans.EndAll()
+
 	// Wait group for this function
 	// 1. Wait for responses to be sent (if enabled)
 	// 2. Wait for the session and run info to be sent
@@ -172,7 +197,7 @@ func (s *LocalSession) SendMcpMessage(req *managerPb.SendMcpMessageRequest, stre
 				req.IncludeResponses,
 			)
 			if err != nil {
-				log.Printf("Failed to handle init message: %v", err)
+				logger.Warn("failed to handle init message", logging.Err(err))
 			}
 		}()
 	}
@@ -239,7 +264,7 @@ func (s *LocalSession) SendMcpMessage(req *managerPb.SendMcpMessageRequest, stre
 							ErrorMessage: fmt.Sprintf("timeout waiting for %d MCP responses", responsesToWaitFor),
 						})
 						if err != nil {
-							log.Printf("Failed to send response message: %v", err)
+							logger.Warn("failed to send response message", logging.Err(err))
 							return
 						}
 					}
@@ -253,29 +278,46 @@ func (s *LocalSession) SendMcpMessage(req *managerPb.SendMcpMessageRequest, stre
 							ErrorMessage: fmt.Sprintf("timeout waiting for %d MCP responses", responsesToWaitFor),
 						})
 						if err != nil {
-							log.Printf("Failed to send direct response message: %v", err)
+							logger.Warn("failed to send direct response message", logging.Err(err))
 							return
 						}
 					}
 
 					return
 
-				case message := <-msgChan:
+				case message, ok := <-msgChan:
+					if !ok {
+						if err := connection.Messages().Err(msgChan); err != nil {
+							logger.Warn("stopped waiting for MCP responses", logging.Err(err))
+							sendStreamResponseMcpOverflow(s.sendMu, stream, err)
+						}
+						return
+					}
+
 					if slices.Contains(mcpRequestMessageIdsToListenFor, message.GetStringId()) {
 						responsesToWaitFor--
+						requestSpans.End(message.GetStringId())
 						err := sendStreamResponseMcpMessage(s.sendMu, stream, message)
 						if err != nil {
-							log.Printf("Failed to send direct response message: %v", err)
+							logger.Warn("failed to send direct response message", logging.Err(err))
 							return
 						}
 					}
 
-				case message := <-internalMessages:
+				case message, ok := <-internalMessages:
+					if !ok {
+						if err := s.internalMessages.Err(internalMessages); err != nil {
+							logger.Warn("stopped waiting for MCP responses", logging.Err(err))
+							sendStreamResponseMcpOverflow(s.sendMu, stream, err)
+						}
+						return
+					}
+
 					if slices.Contains(mcpRequestMessageIdsToListenFor, message.GetStringId()) {
 						responsesToWaitFor--
 						err := sendStreamResponseMcpMessage(s.sendMu, stream, message)
 						if err != nil {
-							log.Printf("Failed to send direct response message (internal): %v", err)
+							logger.Warn("failed to send direct response message (internal)", logging.Err(err))
 							return
 						}
 					}
@@ -290,8 +332,19 @@ func (s *LocalSession) SendMcpMessage(req *managerPb.SendMcpMessageRequest, stre
 
 	// Send the messages to the connection
 	for _, message := range mcpMessages {
-		err := connection.AcceptMessage(message)
+		messageCtx, messageSpan := tracing.StartMessageSpan(ctx, "session.McpMessage", message,
+			attribute.String("session_id", s.storedSession.ID),
+			attribute.String("run_id", run.ID),
+		)
+		if req.IncludeResponses && message.MsgType == mcp.RequestType {
+			requestSpans.Track(message.GetStringId(), messageSpan)
+		} else {
+			defer messageSpan.End()
+		}
+
+		err := connection.AcceptMessage(messageCtx, message)
 		if err != nil {
+			tracing.RecordError(messageSpan, err)
 			sentry.CaptureException(err)
 			s.CreateStructuredErrorWithRun(
 				s.activeRunDb,
@@ -330,6 +383,8 @@ func (s *LocalSession) StreamMcpMessages(req *managerPb.StreamMcpMessagesRequest
 		req.OnlyMessageTypes = nil // If no message types are requested, we don't need to filter
 	}
 
+	logger := s.logger
+
 	responsesToWaitFor := 0
 	if req.OnlyIds == nil {
 		responsesToWaitFor = len(req.OnlyIds)
@@ -340,7 +395,7 @@ func (s *LocalSession) StreamMcpMessages(req *managerPb.StreamMcpMessagesRequest
 			messages, err := s.db.ListGlobalSessionMessagesAfter(req.SessionId, *req.ReplayAfterUuid)
 			if err != nil {
 				sentry.CaptureException(err)
-				log.Printf("Failed to list messages after UUID %s: %v", *req.ReplayAfterUuid, err)
+				s.logger.Error("failed to list messages for replay", slog.String("after_uuid", *req.ReplayAfterUuid), logging.Err(err))
 				return
 			}
 
@@ -348,7 +403,7 @@ func (s *LocalSession) StreamMcpMessages(req *managerPb.StreamMcpMessagesRequest
 				message, err := message.ToMcpMessage()
 
 				if err != nil {
-					log.Printf("Failed to convert message to mco message: %v", err)
+					s.logger.Warn("failed to convert message to mcp message", logging.Err(err))
 					continue
 				}
 
@@ -400,12 +455,16 @@ func (s *LocalSession) StreamMcpMessages(req *managerPb.StreamMcpMessagesRequest
 			case <-touchTicker.C:
 				s.Touch()
 
-			case message := <-internalMessages:
+			case message, ok := <-internalMessages:
+				if !ok {
+					return s.internalMessagesClosed(stream, internalMessages, logger)
+				}
+
 				if s.canSendMessage(req, message) {
 					responsesToWaitFor--
 					err := sendStreamResponseMcpMessage(s.sendMu, stream, message)
 					if err != nil {
-						log.Printf("Failed to send response message: %v", err)
+						logger.Warn("failed to send response message", logging.Err(err))
 						return nil
 					}
 				}
@@ -442,6 +501,8 @@ func (s *LocalSession) StreamMcpMessages(req *managerPb.StreamMcpMessagesRequest
 			s.mutex.RUnlock()
 
 			if dbRun != nil {
+				logger = s.runLogger(dbRun)
+
 				sendStreamResponseSessionEventInfoRun(s.sendMu, stream, dbRun)
 				sendStreamResponseSessionEventInfoSession(s.sendMu, stream, s.dbSession)
 			}
@@ -460,22 +521,38 @@ func (s *LocalSession) StreamMcpMessages(req *managerPb.StreamMcpMessagesRequest
 			// start over with the loop to wait for a new connection
 			continue
 
-		case message := <-msgChan:
+		case message, ok := <-msgChan:
+			if !ok {
+				if err := chansForCon.Messages().Err(msgChan); err != nil {
+					logger.Warn("MCP message stream disconnected", logging.Err(err))
+					sendStreamResponseMcpOverflow(s.sendMu, stream, err)
+					return nil
+				}
+
+				// The run has ended, its done message follows
+				msgChan = nil
+				continue
+			}
+
 			if s.canSendMessage(req, message) {
 				responsesToWaitFor--
 				err := sendStreamResponseMcpMessage(s.sendMu, stream, message)
 				if err != nil {
-					log.Printf("Failed to send response message: %v", err)
+					logger.Warn("failed to send response message", logging.Err(err))
 					return nil
 				}
 			}
 
-		case message := <-internalMessages:
+		case message, ok := <-internalMessages:
+			if !ok {
+				return s.internalMessagesClosed(stream, internalMessages, logger)
+			}
+
 			if s.canSendMessage(req, message) {
 				responsesToWaitFor--
 				err := sendStreamResponseMcpMessage(s.sendMu, stream, message)
 				if err != nil {
-					log.Printf("Failed to send response message (internal): %v", err)
+					logger.Warn("failed to send response message (internal)", logging.Err(err))
 					return nil
 				}
 			}
@@ -483,14 +560,14 @@ func (s *LocalSession) StreamMcpMessages(req *managerPb.StreamMcpMessagesRequest
 		case mcpErr := <-errChan:
 			err := sendStreamResponseMcpError(s.sendMu, stream, mcpErr)
 			if err != nil {
-				log.Printf("Failed to send error message: %v", err)
+				logger.Warn("failed to send error message", logging.Err(err))
 				return nil
 			}
 
 		case output := <-outChan:
 			err := sendStreamResponseMcpOutput(s.sendMu, stream, output)
 			if err != nil {
-				log.Printf("Failed to send output message: %v", err)
+				logger.Warn("failed to send output message", logging.Err(err))
 				return nil
 			}
 
@@ -503,7 +580,7 @@ func (s *LocalSession) GetServerInfo(req *managerPb.GetServerInfoRequest) (*mcpP
 	defer s.mcpServerInitMutex.Unlock()
 
 	if s.mcpServer == nil {
-		connection, _, err := s.ensureConnection()
+		connection, _, err := s.ensureConnection(context.Background())
 		if err != nil {
 			sentry.CaptureException(err)
 			return nil, mterror.NewWithCodeAndInnerError(mterror.InternalErrorKind, "run_error", "failed to ensure connection", err)
@@ -528,3 +605,14 @@ func (s *LocalSession) GetServerInfo(req *managerPb.GetServerInfoRequest) (*mcpP
 
 	return participant, nil
 }
+
+func (s *LocalSession) runLogger(run *db.SessionRun) *slog.Logger {
+	if run == nil {
+		return s.logger
+	}
+
+	return s.logger.With(
+		logging.RunId(run.ID),
+		logging.WorkerId(run.WorkerID),
+	)
+}
@@ -1,19 +1,23 @@
 package session
 
 import (
-	"log"
+	"context"
 	"strings"
 	"time"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/metorial/metorial/mcp-engine/internal/db"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/workers"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
 	mterror "github.com/metorial/metorial/mcp-engine/pkg/mtError"
+	"github.com/metorial/metorial/mcp-engine/pkg/tracing"
 	"github.com/metorial/metorial/modules/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func (s *LocalSession) ensureConnection() (workers.WorkerConnection, *db.SessionRun, *mterror.MTError) {
+func (s *LocalSession) ensureConnection(ctx context.Context) (workers.WorkerConnection, *db.SessionRun, *mterror.MTError) {
 	waitOk := util.WaitTimeout(s.mcpClientInitWg, time.Second*20)
 	if !waitOk {
 		return nil, nil, mterror.New(mterror.InternalErrorKind, "timeout waiting for MCP client initialization")
@@ -39,8 +43,15 @@ func (s *LocalSession) ensureConnection() (workers.WorkerConnection, *db.Session
 		return s.activeConnection, s.activeRunDb, nil
 	}
 
-	connection, worker, err2 := createConnection(s.workerManager, s.connectionInput, s.mcpClient, s.WorkerType)
+	ctx, span := tracing.Start(ctx, "session.createConnection", trace.WithAttributes(
+		attribute.String("session_id", s.storedSession.ID),
+		attribute.String("worker_type", string(s.WorkerType)),
+	))
+	defer span.End()
+
+	connection, worker, err2 := createConnection(s.logger, s.workerManager, s.connectionInput, s.mcpClient, s.WorkerType)
 	if err2 != nil {
+		tracing.RecordError(span, err2)
 		s.CreateStructuredErrorWithRun(
 			s.activeRunDb,
 			"run_error",
@@ -77,12 +88,20 @@ func (s *LocalSession) ensureConnection() (workers.WorkerConnection, *db.Session
 		return nil, nil, mterror.NewWithInnerError(mterror.InternalErrorKind, "failed to create connection in database", err)
 	}
 
-	log.Printf("Created connection %s for session %s with worker %s", connection.ConnectionID(), s.storedSession.ID, worker.WorkerID())
+	logger := s.runLogger(run)
+
+	logger.Info("created connection")
+
+	span.SetAttributes(
+		attribute.String("run_id", run.ID),
+		attribute.String("worker_id", worker.WorkerID()),
+	)
 
 	go s.monitorConnection(run, connection)
 
-	err = connection.Start(true)
+	err = connection.Start(ctx, true)
 	if err != nil {
+		tracing.RecordError(span, err)
 		s.CreateStructuredErrorWithRun(
 			run,
 			"run_error",
@@ -92,12 +111,12 @@ func (s *LocalSession) ensureConnection() (workers.WorkerConnection, *db.Session
 			},
 		)
 
-		log.Printf("Failed to start connection %s for session %s: %v", connection.ConnectionID(), s.storedSession.ID, err)
+		logger.Error("failed to start connection", logging.Err(err))
 
 		return nil, nil, mterror.NewWithCodeAndInnerError(mterror.InternalErrorKind, "run_error", "failed to start server", err)
 	}
 
-	log.Printf("Started connection %s for session %s with worker %s", connection.ConnectionID(), s.storedSession.ID, worker.WorkerID())
+	logger.Info("started connection")
 
 	s.activeConnection = connection
 	s.lastConnectionInteraction = time.Now()
@@ -144,6 +163,8 @@ func (s *LocalSession) ensureConnection() (workers.WorkerConnection, *db.Session
 }
 
 func (s *LocalSession) monitorConnection(run *db.SessionRun, connection workers.WorkerConnection) {
+	logger := s.runLogger(run)
+
 	timeout := connection.InactivityTimeout()
 
 	ticker := time.NewTicker(time.Second * 5)
@@ -155,8 +176,11 @@ func (s *LocalSession) monitorConnection(run *db.SessionRun, connection workers.
 	errChan := connection.Errors().Subscribe()
 	defer connection.Errors().Unsubscribe(errChan)
 
+	// Subscribed before the loop starts, so no message is missed
 	msgChan := connection.Messages().Subscribe()
-	defer connection.Messages().Unsubscribe(msgChan)
+	stopPersisting := make(chan struct{})
+	defer close(stopPersisting)
+	go s.persistConnectionMessages(run, connection, msgChan, stopPersisting)
 
 	outChan := connection.Output().Subscribe()
 	defer connection.Output().Unsubscribe(outChan)
@@ -178,7 +202,7 @@ loop:
 
 					go connection.Close()
 
-					log.Printf("Connection %s for session %s has been closed due to inactivity", connection.ConnectionID(), s.storedSession.ID)
+					logger.Info("connection closed due to inactivity")
 				}
 				s.mutex.Unlock()
 
@@ -189,7 +213,7 @@ loop:
 				err := connection.Close()
 				if err != nil {
 					sentry.CaptureException(err)
-					log.Printf("Failed to close old connection %s: %v", connection.ConnectionID(), err)
+					logger.Warn("failed to close replaced connection", logging.Err(err))
 				}
 
 				s.mutex.Lock()
@@ -226,15 +250,6 @@ loop:
 			s.hasError = true
 			s.CreateMcpError(run, err)
 
-		case message := <-msgChan:
-			if strings.HasPrefix(message.GetStringId(), "mte/") {
-				// Skip initialization messages, as they are always handled internally
-				// and not by the MCP client.
-				continue
-			}
-
-			go s.PersistMessages(run, db.SessionMessageSenderServer, []*mcp.MCPMessage{message})
-
 		case output := <-outChan:
 			go s.db.CreateEvent(
 				db.NewOutputEvent(
@@ -247,12 +262,105 @@ loop:
 		}
 	}
 
-	log.Printf("Connection %s for session %s has been closed", connection.ConnectionID(), s.storedSession.ID)
+	logger.Info("connection closed")
+}
+
+// maxQueuedConnectionMessages bounds how many messages of a connection wait
+// to be stored. Once it is reached, reading from the connection waits for
+// the database.
+const maxQueuedConnectionMessages = 1000
+
+// persistConnectionMessages stores the messages of a connection in the
+// order they arrive until stop is closed. Messages are read into a queue
+// that is stored in the background, so a slow database doesn't make the
+// subscriber fall behind right away. Messages received before stop are
+// stored before it returns. If the subscriber is disconnected anyway,
+// messages were lost, which is recorded as an error of the run.
+func (s *LocalSession) persistConnectionMessages(run *db.SessionRun, connection workers.WorkerConnection, msgChan chan *mcp.MCPMessage, stop chan struct{}) {
+	logger := s.runLogger(run)
+
+	queue := make(chan *mcp.MCPMessage, maxQueuedConnectionMessages)
+	stored := make(chan struct{})
+
+	go func() {
+		defer close(stored)
+
+		for message := range queue {
+			messages := []*mcp.MCPMessage{message}
+		batch:
+			for {
+				select {
+				case message, ok := <-queue:
+					if !ok {
+						break batch
+					}
+					messages = append(messages, message)
+				default:
+					break batch
+				}
+			}
+
+			s.PersistMessagesSync(run, db.SessionMessageSenderServer, messages)
+		}
+	}()
+
+	defer func() {
+		close(queue)
+		<-stored
+	}()
+
+	enqueue := func(message *mcp.MCPMessage) {
+		if strings.HasPrefix(message.GetStringId(), "mte/") {
+			// Skip initialization messages, as they are always handled internally
+			// and not by the MCP client.
+			return
+		}
+
+		queue <- message
+	}
+
+	for {
+		select {
+		case message, ok := <-msgChan:
+			if !ok {
+				err := connection.Messages().Err(msgChan)
+				if err == nil {
+					return
+				}
+
+				sentry.CaptureException(err)
+				logger.Error("MCP message persistence fell behind", logging.Err(err))
+
+				s.CreateStructuredErrorWithRun(
+					run,
+					"message_persistence_error",
+					"failed to store all MCP messages",
+					map[string]string{
+						"internal_error": err.Error(),
+					},
+				)
+
+				return
+			}
+
+			enqueue(message)
+
+		case <-stop:
+			connection.Messages().Unsubscribe(msgChan)
+
+			// Keep what was delivered before unsubscribing
+			for message := range msgChan {
+				enqueue(message)
+			}
+
+			return
+		}
+	}
 }
 
 func (s *LocalSession) discoverServer(connection workers.WorkerConnection) {
 	s.serverDiscoveryMutex.Lock()
 	defer s.serverDiscoveryMutex.Unlock()
 
-	discoverServerWithEphemeralConnection(s.db, s.dbSession.Server, connection)
+	discoverServerWithEphemeralConnection(s.logger, s.db, s.dbSession.Server, connection)
 }
@@ -139,3 +139,16 @@ func sendStreamResponseMcpOutput(
 
 	return sendStreamResponse(sendMu, stream, response)
 }
+
+// sendStreamResponseMcpOverflow tells the client that it was disconnected
+// from the MCP messages, as it didn't keep up with them.
+func sendStreamResponseMcpOverflow(
+	sendMu *sync.Mutex,
+	stream grpc.ServerStreamingServer[managerPb.McpConnectionStreamResponse],
+	err error,
+) error {
+	return sendStreamResponseMcpError(sendMu, stream, &mcpPb.McpError{
+		ErrorCode:    mcpPb.McpError_message_overflow,
+		ErrorMessage: err.Error(),
+	})
+}
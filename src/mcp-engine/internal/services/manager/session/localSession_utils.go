@@ -2,7 +2,7 @@ package session
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"slices"
 	"time"
 
@@ -10,8 +10,10 @@ import (
 	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
 	"github.com/metorial/metorial/mcp-engine/internal/db"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/state"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
 	mterror "github.com/metorial/metorial/mcp-engine/pkg/mtError"
+	"google.golang.org/grpc"
 )
 
 const LOCAL_SESSION_INACTIVITY_TIMEOUT = time.Second * 60 * 5
@@ -164,7 +166,7 @@ func (s *LocalSession) Touch() {
 		err := s.db.SaveSession(s.dbSession)
 		if err != nil {
 			sentry.CaptureException(err)
-			log.Printf("Failed to update last ping time for session %s: %v", s.dbSession.ID, err)
+			s.logger.Warn("failed to update session last ping time", logging.Err(err))
 		}
 	}
 
@@ -173,7 +175,23 @@ func (s *LocalSession) Touch() {
 		err := s.db.SaveRun(s.activeRunDb)
 		if err != nil {
 			sentry.CaptureException(err)
-			log.Printf("Failed to update last ping time for connection %s: %v", s.activeRunDb.ID, err)
+			s.runLogger(s.activeRunDb).Warn("failed to update run last ping time", logging.Err(err))
 		}
 	}
 }
+
+// internalMessagesClosed ends a message stream whose internal messages
+// subscription was closed, either as the session stopped or because the
+// stream didn't keep up.
+func (s *LocalSession) internalMessagesClosed(
+	stream grpc.ServerStreamingServer[managerPb.McpConnectionStreamResponse],
+	internalMessages chan *mcp.MCPMessage,
+	logger *slog.Logger,
+) *mterror.MTError {
+	if err := s.internalMessages.Err(internalMessages); err != nil {
+		logger.Warn("MCP message stream disconnected", logging.Err(err))
+		sendStreamResponseMcpOverflow(s.sendMu, stream, err)
+	}
+
+	return nil
+}
@@ -462,6 +462,7 @@ func (s *SessionServer) GetSessionServer(ctx context.Context, req *managerPb.Get
 
 func (s *SessionServer) DiscoverServer(ctx context.Context, req *managerPb.DiscoverRequest) (*managerPb.GetServerResponse, error) {
 	server, connectionInput, err := processServerConfig(
+		s.sessions.logger,
 		"",
 		nil,
 		req.ServerConfig,
@@ -473,7 +474,13 @@ func (s *SessionServer) DiscoverServer(ctx context.Context, req *managerPb.Disco
 		return nil, err.ToGRPCStatus().Err()
 	}
 
-	if shouldDiscoverServer(server) {
+	// The launcher only runs if the server needs to be discovered, but
+	// invalid configs are rejected either way.
+	if err := validateServerConfig(req.ServerConfig); err != nil {
+		return nil, err.ToGRPCStatus().Err()
+	}
+
+	if shouldDiscoverServer(s.sessions.logger, server) {
 		err = runLauncherForServerConfigIfNeeded(s.sessions.launcher, connectionInput, req.ServerConfig)
 		if err != nil {
 			return nil, err.ToGRPCStatus().Err()
@@ -492,3 +499,29 @@ func (s *SessionServer) DiscoverServer(ctx context.Context, req *managerPb.Disco
 
 	return &managerPb.GetServerResponse{Server: res}, nil
 }
+
+func (s *SessionServer) ReplaySession(ctx context.Context, req *managerPb.ReplaySessionRequest) (*managerPb.ReplaySessionResponse, error) {
+	if req.Config.GetServerConfig().GetConfigType() == nil {
+		return nil, mterror.New(mterror.InvalidRequestKind, "replay config must contain a run config").ToGRPCStatus().Err()
+	}
+
+	res, err := s.sessions.ReplaySession(ctx, req)
+	if err != nil {
+		return nil, err.ToGRPCStatus().Err()
+	}
+
+	return res, nil
+}
+
+func (s *SessionServer) DryRunLauncher(ctx context.Context, req *managerPb.DryRunLauncherRequest) (*managerPb.DryRunLauncherResponse, error) {
+	if req.ServerConfig.GetConfigType() == nil {
+		return nil, mterror.New(mterror.InvalidRequestKind, "server config must contain a run config").ToGRPCStatus().Err()
+	}
+
+	res, err := s.sessions.launcher.DryRun(req.ServerConfig)
+	if err != nil {
+		return nil, err.ToGRPCStatus().Err()
+	}
+
+	return res, nil
+}
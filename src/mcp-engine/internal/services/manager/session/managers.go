@@ -6,6 +6,7 @@ import (
 	"github.com/getsentry/sentry-go"
 	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/state"
+	grpc_util "github.com/metorial/metorial/mcp-engine/pkg/grpcUtil"
 	"github.com/metorial/metorial/mcp-engine/pkg/managerUtils"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -80,7 +81,7 @@ func (om *OtherManagers) GetManagerConnection(managerID string) (managerPb.McpMa
 		return connection, nil
 	}
 
-	conn, err := grpc.NewClient(managerUtils.GetManagerAddress(manager.ManagerAddress), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(managerUtils.GetManagerAddress(manager.ManagerAddress), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc_util.WithTracing())
 	if err != nil {
 		sentry.CaptureException(err)
 		return nil, err
@@ -3,7 +3,7 @@ package session
 import (
 	"context"
 	"io"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -11,6 +11,7 @@ import (
 	mcpPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/mcp"
 	"github.com/metorial/metorial/mcp-engine/internal/db"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/state"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	mterror "github.com/metorial/metorial/mcp-engine/pkg/mtError"
 	"google.golang.org/grpc"
 )
@@ -27,6 +28,8 @@ type RemoteSession struct {
 
 	context context.Context
 	cancel  context.CancelFunc
+
+	logger *slog.Logger
 }
 
 func newRemoteSession(
@@ -46,6 +49,11 @@ func newRemoteSession(
 
 		context: ctx,
 		cancel:  cancel,
+
+		logger: sessions.logger.With(
+			logging.SessionId(storedSession.ID),
+			slog.String("remote_manager_id", storedSession.ManagerID),
+		),
 	}
 }
 
@@ -54,7 +62,7 @@ func (s *RemoteSession) SendMcpMessage(req *managerPb.SendMcpMessageRequest, str
 
 	responseStream, err := s.connection.SendMcpMessage(s.context, req)
 	if err != nil {
-		log.Printf("Failed to send MCP message: %v", err)
+		s.logger.Warn("failed to forward MCP message to remote manager", logging.Err(err))
 		return mterror.NewWithInnerError(mterror.InternalErrorKind, "failed to send MCP message", err)
 	}
 
@@ -0,0 +1,187 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/google/uuid"
+	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
+	mcpPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/mcp"
+	"github.com/metorial/metorial/mcp-engine/internal/db"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
+	mterror "github.com/metorial/metorial/mcp-engine/pkg/mtError"
+	"github.com/metorial/metorial/mcp-engine/pkg/replay"
+	"github.com/metorial/metorial/modules/util"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// ReplaySession creates a fresh session from the given config and replays
+// the client messages of a recorded session against it, diffing the
+// responses with the recorded ones.
+func (s *Sessions) ReplaySession(ctx context.Context, req *managerPb.ReplaySessionRequest) (*managerPb.ReplaySessionResponse, *mterror.MTError) {
+	recorded, err := s.db.GetSessionById(req.SessionId)
+	if err != nil {
+		return nil, mterror.NewWithInnerError(mterror.InternalErrorKind, "failed to get recorded session", err)
+	}
+	if recorded == nil {
+		return nil, mterror.New(mterror.NotFoundKind, "recorded session not found")
+	}
+
+	messages, err := s.db.ListAllSessionMessagesBySession(recorded.ID)
+	if err != nil {
+		return nil, mterror.NewWithInnerError(mterror.InternalErrorKind, "failed to list recorded messages", err)
+	}
+
+	recordedMessages := make([]replay.RecordedMessage, 0, len(messages))
+	for _, message := range messages {
+		pbMessage, err := message.ToPbMessage()
+		if err != nil {
+			return nil, mterror.NewWithInnerError(mterror.InternalErrorKind, "failed to parse recorded message", err)
+		}
+
+		recordedMessages = append(recordedMessages, replay.RecordedMessage{
+			FromClient: message.Sender == db.SessionMessageSenderClient,
+			Message:    pbMessage,
+		})
+	}
+
+	config := proto.Clone(req.Config).(*managerPb.SessionConfig)
+	if config.McpConfig == nil || config.McpConfig.McpVersion == "" {
+		config.McpConfig = &mcpPb.McpConfig{McpVersion: recorded.McpVersion}
+	}
+
+	if req.DockerImageTag != nil {
+		if err := replay.ApplyImageTag(config.ServerConfig, *req.DockerImageTag); err != nil {
+			return nil, mterror.NewWithInnerError(mterror.InvalidRequestKind, err.Error(), err)
+		}
+	}
+
+	replaySessionId := util.Must(uuid.NewV7()).String()
+	logger := s.logger.With(logging.SessionId(replaySessionId), slog.String("recorded_session_id", recorded.ID))
+
+	createReq := &managerPb.CreateSessionRequest{
+		SessionId: replaySessionId,
+		Config:    config,
+		Metadata: map[string]string{
+			"replay_of": recorded.ID,
+		},
+	}
+
+	if recorded.McpClient != nil {
+		createReq.McpClient, err = recorded.McpClient.ToPbParticipant()
+		if err != nil {
+			return nil, mterror.NewWithInnerError(mterror.InternalErrorKind, "failed to convert recorded client", err)
+		}
+	}
+
+	session, mtErr := s.UpsertSession(createReq)
+	if mtErr != nil {
+		return nil, mtErr
+	}
+
+	if !req.KeepSession {
+		defer func() {
+			if err := session.DiscardSession(); err != nil {
+				logger.Warn("failed to discard replay session", logging.Err(err))
+			}
+		}()
+	}
+
+	target := &sessionReplayTarget{session: session, sessionId: replaySessionId}
+
+	// Initialize messages are not stored with the session, so the
+	// replay session is initialized with the recorded client info.
+	if recorded.McpClient != nil {
+		init, err := recorded.McpClient.ToInitMessage(recorded.McpVersion)
+		if err != nil {
+			return nil, mterror.NewWithInnerError(mterror.InternalErrorKind, "failed to create init message", err)
+		}
+
+		if _, err := target.Send(ctx, init.ToPbRawMessage()); err != nil {
+			sentry.CaptureException(err)
+			return nil, mterror.NewWithInnerError(mterror.InternalErrorKind, "failed to initialize replay session", err)
+		}
+	}
+
+	ignorePaths := append(append([]string{}, replay.DefaultIgnorePaths...), req.IgnorePaths...)
+
+	res := replay.Run(ctx, target, replay.BuildExchanges(recordedMessages), replay.NewIgnoreRules(ignorePaths...))
+	res.ReplaySessionId = replaySessionId
+
+	logger.Info("replayed session", slog.Int("matched", int(res.Matched)), slog.Int("mismatched", int(res.Mismatched)))
+
+	return res, nil
+}
+
+type sessionReplayTarget struct {
+	session   Session
+	sessionId string
+}
+
+func (t *sessionReplayTarget) Send(ctx context.Context, message *mcpPb.McpMessageRaw) ([]*mcpPb.McpMessage, error) {
+	stream := &collectingStream{ctx: ctx}
+
+	err := t.session.SendMcpMessage(&managerPb.SendMcpMessageRequest{
+		SessionId:        t.sessionId,
+		McpMessages:      []*mcpPb.McpMessageRaw{message},
+		IncludeResponses: true,
+	}, stream)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, mcpErr := stream.result()
+	if len(messages) == 0 && mcpErr != nil {
+		return nil, fmt.Errorf("%s: %s", mcpErr.ErrorCode.String(), mcpErr.ErrorMessage)
+	}
+
+	return messages, nil
+}
+
+// collectingStream stands in for the gRPC stream of SendMcpMessage when
+// the manager sends messages to a session itself.
+type collectingStream struct {
+	ctx context.Context
+
+	responses []*managerPb.McpConnectionStreamResponse
+	mutex     sync.Mutex
+}
+
+func (c *collectingStream) Send(res *managerPb.McpConnectionStreamResponse) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.responses = append(c.responses, res)
+	return nil
+}
+
+// result returns the MCP messages sent to the stream and the last error.
+func (c *collectingStream) result() ([]*mcpPb.McpMessage, *mcpPb.McpError) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	messages := make([]*mcpPb.McpMessage, 0)
+	var mcpErr *mcpPb.McpError
+
+	for _, response := range c.responses {
+		if message := response.GetMcpMessage(); message != nil {
+			messages = append(messages, message)
+		}
+		if err := response.GetMcpError(); err != nil {
+			mcpErr = err
+		}
+	}
+
+	return messages, mcpErr
+}
+
+func (c *collectingStream) Context() context.Context     { return c.ctx }
+func (c *collectingStream) SetHeader(metadata.MD) error  { return nil }
+func (c *collectingStream) SendHeader(metadata.MD) error { return nil }
+func (c *collectingStream) SetTrailer(metadata.MD)       {}
+func (c *collectingStream) SendMsg(m any) error          { return nil }
+func (c *collectingStream) RecvMsg(m any) error          { return nil }
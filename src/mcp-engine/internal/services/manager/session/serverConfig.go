@@ -2,21 +2,25 @@ package session
 
 import (
 	"encoding/json"
-	"log"
+	"errors"
+	"log/slog"
 
 	"github.com/google/uuid"
 	mcpTypes "github.com/mark3labs/mcp-go/mcp"
+	launcherPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/launcher"
 	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
 	mcpPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/mcp"
 	"github.com/metorial/metorial/mcp-engine/gen/mcp-engine/remote"
 	"github.com/metorial/metorial/mcp-engine/internal/db"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/launcher"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/workers"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
 	mterror "github.com/metorial/metorial/mcp-engine/pkg/mtError"
 )
 
 func processServerConfig(
+	logger *slog.Logger,
 	sessionId string,
 	mcpClient *mcp.MCPClient,
 	config *managerPb.ServerConfig,
@@ -80,7 +84,7 @@ func processServerConfig(
 
 	server, err := db_.EnsureServerByIdentifier(dbType, serverIdentifier)
 	if err != nil {
-		log.Printf("Failed to ensure server by identifier: %v\n", err)
+		logger.Error("failed to ensure server by identifier", slog.String("server_identifier", serverIdentifier), logging.Err(err))
 		return nil, nil, mterror.NewWithInnerError(mterror.InternalErrorKind, "failed to ensure server by identifier", err)
 	}
 
@@ -89,7 +93,7 @@ func processServerConfig(
 		if statefulServerInfo.ToolsJson != "" {
 			err := json.Unmarshal([]byte(statefulServerInfo.ToolsJson), &tools)
 			if err != nil {
-				log.Printf("Failed to unmarshal tools JSON: %v\n", err)
+				logger.Warn("failed to unmarshal stateful tools JSON", logging.Err(err))
 				return nil, nil, mterror.NewWithInnerError(mterror.InvalidRequestKind, "failed to unmarshal tools JSON", err)
 			}
 		}
@@ -98,7 +102,7 @@ func processServerConfig(
 		if statefulServerInfo.PromptsJson != "" {
 			err := json.Unmarshal([]byte(statefulServerInfo.PromptsJson), &prompts)
 			if err != nil {
-				log.Printf("Failed to unmarshal prompts JSON: %v\n", err)
+				logger.Warn("failed to unmarshal stateful prompts JSON", logging.Err(err))
 				return nil, nil, mterror.NewWithInnerError(mterror.InvalidRequestKind, "failed to unmarshal prompts JSON", err)
 			}
 		}
@@ -107,7 +111,7 @@ func processServerConfig(
 		if statefulServerInfo.ResourceTemplatesJson != "" {
 			err := json.Unmarshal([]byte(statefulServerInfo.ResourceTemplatesJson), &resourceTemplates)
 			if err != nil {
-				log.Printf("Failed to unmarshal resources JSON: %v\n", err)
+				logger.Warn("failed to unmarshal stateful resources JSON", logging.Err(err))
 				return nil, nil, mterror.NewWithInnerError(mterror.InvalidRequestKind, "failed to unmarshal resources JSON", err)
 			}
 		}
@@ -116,7 +120,7 @@ func processServerConfig(
 		if statefulServerInfo.CapabilitiesJson != "" {
 			err := json.Unmarshal([]byte(statefulServerInfo.CapabilitiesJson), &capabilities)
 			if err != nil {
-				log.Printf("Failed to unmarshal capabilities JSON: %v\n", err)
+				logger.Warn("failed to unmarshal stateful capabilities JSON", logging.Err(err))
 				return nil, nil, mterror.NewWithInnerError(mterror.InvalidRequestKind, "failed to unmarshal capabilities JSON", err)
 			}
 		}
@@ -125,7 +129,7 @@ func processServerConfig(
 		if statefulServerInfo.ServerInfoJson != "" {
 			err := json.Unmarshal([]byte(statefulServerInfo.ServerInfoJson), &serverInfo)
 			if err != nil {
-				log.Printf("Failed to unmarshal server info JSON: %v\n", err)
+				logger.Warn("failed to unmarshal stateful server info JSON", logging.Err(err))
 				return nil, nil, mterror.NewWithInnerError(mterror.InvalidRequestKind, "failed to unmarshal server info JSON", err)
 			}
 		}
@@ -134,7 +138,7 @@ func processServerConfig(
 		if statefulServerInfo.InstructionsJson != "" {
 			err := json.Unmarshal([]byte(statefulServerInfo.InstructionsJson), &instructions)
 			if err != nil {
-				log.Printf("Failed to unmarshal instructions JSON: %v\n", err)
+				logger.Warn("failed to unmarshal stateful instructions JSON", logging.Err(err))
 				return nil, nil, mterror.NewWithInnerError(mterror.InvalidRequestKind, "failed to unmarshal instructions JSON", err)
 			}
 		}
@@ -175,14 +179,14 @@ func runLauncherForServerConfigIfNeeded(launcher *launcher.Launcher, connectionI
 	if config.GetContainerRunConfigWithLauncher() != nil {
 		connectionInput.ContainerRunConfig, err = launcher.GetContainerLaunchParams(config.GetContainerRunConfigWithLauncher())
 		if err != nil {
-			return mterror.NewWithCodeAndInnerError(mterror.InvalidRequestKind, "failed_to_get_launch_params", err.Error(), err)
+			return launchParamsError(err)
 		}
 	} else if config.GetContainerRunConfigWithContainerArguments() != nil {
 		connectionInput.ContainerRunConfig = config.GetContainerRunConfigWithContainerArguments()
 	} else if config.GetRemoteRunConfigWithLauncher() != nil {
 		remoteConfig, err := launcher.GetRemoteLaunchParams(config.GetRemoteRunConfigWithLauncher())
 		if err != nil {
-			return mterror.NewWithCodeAndInnerError(mterror.InvalidRequestKind, "failed_to_get_launch_params", err.Error(), err)
+			return launchParamsError(err)
 		}
 		connectionInput.RemoteRunConfig = &remote.RunConfig{
 			Config: &remote.RunConfig_RemoteRunConfig{
@@ -198,7 +202,7 @@ func runLauncherForServerConfigIfNeeded(launcher *launcher.Launcher, connectionI
 	} else if config.GetLambdaRunConfigWithLauncher() != nil {
 		lambdaRunConfig, err := launcher.GetLambdaLaunchParams(config.GetLambdaRunConfigWithLauncher())
 		if err != nil {
-			return mterror.NewWithCodeAndInnerError(mterror.InvalidRequestKind, "failed_to_get_launch_params", err.Error(), err)
+			return launchParamsError(err)
 		}
 		connectionInput.RemoteRunConfig = &remote.RunConfig{
 			Config: &remote.RunConfig_LambdaRunConfig{
@@ -217,3 +221,41 @@ func runLauncherForServerConfigIfNeeded(launcher *launcher.Launcher, connectionI
 
 	return nil
 }
+
+// validateServerConfig validates the launcher config of a server config
+// against the server's schema, without running the launcher.
+func validateServerConfig(config *managerPb.ServerConfig) *mterror.MTError {
+	var launcherConfig *launcherPb.LauncherConfig
+
+	switch {
+	case config.GetContainerRunConfigWithLauncher() != nil:
+		launcherConfig = config.GetContainerRunConfigWithLauncher().Launcher
+	case config.GetRemoteRunConfigWithLauncher() != nil:
+		launcherConfig = config.GetRemoteRunConfigWithLauncher().Launcher
+	case config.GetLambdaRunConfigWithLauncher() != nil:
+		launcherConfig = config.GetLambdaRunConfigWithLauncher().Launcher
+	default:
+		return nil
+	}
+
+	if err := launcher.ValidateConfig(launcherConfig); err != nil {
+		return launchParamsError(err)
+	}
+
+	return nil
+}
+
+// launchParamsError converts errors of the launcher. Configs that don't
+// match the server's schema list the failing fields in the details.
+func launchParamsError(err error) *mterror.MTError {
+	var configErr *launcher.ConfigError
+	if errors.As(err, &configErr) {
+		fieldErrors, _ := json.Marshal(configErr.Fields)
+
+		return mterror.NewWithCodeAndDetails(mterror.InvalidRequestKind, "invalid_server_config", err.Error(), map[string]string{
+			"field_errors": string(fieldErrors),
+		})
+	}
+
+	return mterror.NewWithCodeAndInnerError(mterror.InvalidRequestKind, "failed_to_get_launch_params", err.Error(), err)
+}
@@ -1,8 +1,11 @@
 package session
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -14,6 +17,7 @@ import (
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/launcher"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/state"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/workers"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
 	mterror "github.com/metorial/metorial/mcp-engine/pkg/mtError"
 	"github.com/metorial/metorial/modules/limiter"
@@ -44,6 +48,10 @@ type Session interface {
 	stop(SessionStopType) error
 }
 
+// sessionLockTimeout limits how long requests wait for another request
+// on the same session, so a stuck backend call doesn't stall them all.
+const sessionLockTimeout = 30 * time.Second
+
 type Sessions struct {
 	sessions map[string]Session
 
@@ -54,10 +62,13 @@ type Sessions struct {
 	managers      *OtherManagers
 
 	keylock     *lock.KeyLock
+	sessionLock *lock.DistributedKeyLock
 	pingLimiter *limiter.Limiter
 	mutex       sync.RWMutex
 
 	launcher *launcher.Launcher
+
+	logger *slog.Logger
 }
 
 func NewSessions(
@@ -65,15 +76,19 @@ func NewSessions(
 	state *state.StateManager,
 	workerManager *workers.WorkerManager,
 ) *Sessions {
+	keylock := lock.NewKeyLock()
+
 	sessions := &Sessions{
 		sessions:      make(map[string]Session),
 		state:         state,
 		db:            db,
 		workerManager: workerManager,
 		managers:      NewOtherManagers(state),
-		keylock:       lock.NewKeyLock(),
+		keylock:       keylock,
+		sessionLock:   state.NewKeyLock("/session-locks/", keylock),
 		pingLimiter:   limiter.NewLimiter(100), // Max 100 ping updates at a time
 		launcher:      launcher.NewLauncher(workerManager),
+		logger:        slog.Default().With(logging.ManagerId(state.ManagerID)),
 	}
 
 	go sessions.discardRoutine()
@@ -95,7 +110,12 @@ func (s *Sessions) GetSessionSafe(sessionId string) (Session, *mterror.MTError)
 		return nil, mterror.New(mterror.InvalidRequestKind, "session ID cannot be empty")
 	}
 
-	s.keylock.Lock(sessionId)
+	ctx, cancel := context.WithTimeout(context.Background(), sessionLockTimeout)
+	defer cancel()
+
+	if err := s.keylock.LockContext(ctx, sessionId); err != nil {
+		return nil, sessionLockError(sessionId, err)
+	}
 	defer s.keylock.Unlock(sessionId)
 
 	s.mutex.RLock()
@@ -118,6 +138,19 @@ func (s *Sessions) GetSessionSafe(sessionId string) (Session, *mterror.MTError)
 	return s.EnsureRemoteSession(storedSession)
 }
 
+func sessionLockError(sessionId string, err error) *mterror.MTError {
+	details := map[string]string{
+		"session_id": sessionId,
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return mterror.NewWithDetails(mterror.TimeoutKind, "timed out waiting for session lock", details)
+	}
+
+	sentry.CaptureException(err)
+	return mterror.NewWithInnerErrorAndDetails(mterror.InternalErrorKind, "failed to lock session", err, details)
+}
+
 func (s *Sessions) GetSessionUnsafe(sessionId string) (Session, *mterror.MTError) {
 	nulSes, err := s.GetSessionSafe(sessionId)
 	if err != nil {
@@ -150,8 +183,18 @@ func (s *Sessions) UpsertSession(
 		return existing, nil
 	}
 
-	s.keylock.Lock(request.SessionId)
-	defer s.keylock.Unlock(request.SessionId)
+	// Serialized across managers, so only one of them creates the session
+	ctx, cancel := context.WithTimeout(context.Background(), sessionLockTimeout)
+	defer cancel()
+
+	if err := s.sessionLock.LockContext(ctx, request.SessionId); err != nil {
+		return nil, sessionLockError(request.SessionId, err)
+	}
+	defer func() {
+		if err := s.sessionLock.Unlock(context.Background(), request.SessionId); err != nil {
+			s.logger.Warn("failed to release session lock", logging.SessionId(request.SessionId), logging.Err(err))
+		}
+	}()
 
 	prospectiveSessionUuid := util.Must(uuid.NewV7()).String()
 
@@ -162,7 +205,7 @@ func (s *Sessions) UpsertSession(
 	)
 	if err != nil {
 		sentry.CaptureException(err)
-		log.Printf("Failed to upsert session %s: %v\n", request.SessionId, err)
+		s.logger.Error("failed to upsert session", logging.SessionId(request.SessionId), logging.Err(err))
 		return nil, mterror.NewWithInnerError(mterror.InternalErrorKind, "failed to upsert session", err)
 	}
 
@@ -277,12 +320,13 @@ func (s *Sessions) EnsureLocalSession(
 		err := s.state.UpdateSession(storedSession)
 		if err != nil {
 			sentry.CaptureException(err)
-			log.Printf("Failed to update session %s during takeover: %v\n", storedSession.ID, err)
+			s.logger.Error("failed to update session during takeover", logging.SessionId(storedSession.ID), logging.Err(err))
 			return nil, mterror.NewWithInnerError(mterror.InternalErrorKind, "failed to update session during takeover", err)
 		}
 	}
 
 	server, connectionInput, err2 := processServerConfig(
+		s.logger.With(logging.SessionId(storedSession.ID)),
 		storedSession.ID,
 		client,
 		request.Config.ServerConfig,
@@ -306,7 +350,7 @@ func (s *Sessions) EnsureLocalSession(
 	))
 	if err != nil {
 		sentry.CaptureException(err)
-		log.Printf("Failed to create session in DB: %v\n", err)
+		s.logger.Error("failed to create session in DB", logging.SessionId(storedSession.ID), logging.Err(err))
 		return nil, mterror.NewWithInnerError(mterror.InternalErrorKind, "failed to create session in DB", err)
 	}
 
@@ -325,7 +369,7 @@ func (s *Sessions) EnsureLocalSession(
 			map[string]string{},
 		))
 
-		log.Printf("Failed to get runner launch params: %v\n", err2)
+		s.logger.Warn("failed to get runner launch params", logging.SessionId(storedSession.ID), logging.Err(err2))
 		return nil, mterror.NewWithCodeAndInnerError(mterror.InvalidRequestKind, "failed_to_get_launch_params", err2.Error(), err2)
 	}
 
@@ -386,7 +430,7 @@ func (s *Sessions) Stop() error {
 	s.mutex.RUnlock()
 
 	for _, id := range sessionIds {
-		log.Printf("Stopping session %s\n", id)
+		s.logger.Info("stopping session", logging.SessionId(id))
 
 		session := s.GetLocalSession(id)
 
@@ -443,7 +487,7 @@ func (s *Sessions) pingRoutine() {
 			s.pingLimiter.Go(func() {
 				err := s.state.UpdateSession(storedSession)
 				if err != nil {
-					log.Printf("Failed to update session %s ping: %v\n", storedSession.ID, err)
+					s.logger.Warn("failed to update session ping", logging.SessionId(storedSession.ID), logging.Err(err))
 				}
 			})
 		}
@@ -454,12 +498,24 @@ func printState(s *Sessions) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
-	log.Println("== Sessions State ==")
-
-	log.Println("Total Sessions:", len(s.sessions))
+	s.logger.Info("sessions state", slog.Int("total_sessions", len(s.sessions)))
+
+	localLockStats := s.keylock.Stats()
+	sessionLockStats := s.sessionLock.Stats()
+	s.logger.Info(
+		"session lock waits",
+		slog.Uint64("local_contended", localLockStats.Contended),
+		slog.Uint64("local_failed", localLockStats.Failed),
+		slog.Duration("local_avg_wait", localLockStats.AverageWait()),
+		slog.Duration("local_max_wait", localLockStats.MaxWait),
+		slog.Uint64("distributed_contended", sessionLockStats.Contended),
+		slog.Uint64("distributed_failed", sessionLockStats.Failed),
+		slog.Duration("distributed_avg_wait", sessionLockStats.AverageWait()),
+		slog.Duration("distributed_max_wait", sessionLockStats.MaxWait),
+	)
 
 	for id, session := range s.sessions {
-		log.Printf("Session ID: %s, Type: %T\n", id, session)
+		s.logger.Debug("session state", logging.SessionId(id), slog.String("type", fmt.Sprintf("%T", session)))
 	}
 }
 
@@ -2,9 +2,11 @@ package state
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/metorial/metorial/modules/lock"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/client/v3/concurrency"
 )
@@ -73,6 +75,23 @@ func (e *EtcdBackend) List(ctx context.Context, prefix string) (map[string]strin
 }
 
 func (e *EtcdBackend) Lock(ctx context.Context, key string) (LockHandle, error) {
+	return e.lock(ctx, key, func(mutex *concurrency.Mutex) error {
+		return mutex.Lock(ctx)
+	})
+}
+
+func (e *EtcdBackend) TryLock(ctx context.Context, key string) (LockHandle, error) {
+	return e.lock(ctx, key, func(mutex *concurrency.Mutex) error {
+		err := mutex.TryLock(ctx)
+		if errors.Is(err, concurrency.ErrLocked) {
+			return lock.ErrLockHeld
+		}
+
+		return err
+	})
+}
+
+func (e *EtcdBackend) lock(ctx context.Context, key string, acquire func(mutex *concurrency.Mutex) error) (LockHandle, error) {
 	lockKey := fmt.Sprintf("/locks%s", key)
 
 	lockSession, err := concurrency.NewSession(e.client)
@@ -82,9 +101,9 @@ func (e *EtcdBackend) Lock(ctx context.Context, key string) (LockHandle, error)
 
 	mutex := concurrency.NewMutex(lockSession, lockKey)
 
-	if err := mutex.Lock(ctx); err != nil {
+	if err := acquire(mutex); err != nil {
 		lockSession.Close()
-		return nil, fmt.Errorf("failed to acquire lock: %v", err)
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
 	}
 
 	return &EtcdLockHandle{
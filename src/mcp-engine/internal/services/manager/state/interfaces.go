@@ -13,6 +13,10 @@ type StorageBackend interface {
 
 	Lock(ctx context.Context, key string) (LockHandle, error)
 
+	// TryLock takes the lock if it is free, otherwise it returns
+	// lock.ErrLockHeld without waiting.
+	TryLock(ctx context.Context, key string) (LockHandle, error)
+
 	Close() error
 }
 
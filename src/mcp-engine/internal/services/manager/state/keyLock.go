@@ -0,0 +1,32 @@
+package state
+
+import (
+	"context"
+
+	"github.com/metorial/metorial/modules/lock"
+)
+
+// lockBackend adapts a StorageBackend to the backend of a
+// lock.DistributedKeyLock.
+type lockBackend struct {
+	backend StorageBackend
+}
+
+func (b lockBackend) Lock(ctx context.Context, key string) (lock.Handle, error) {
+	return b.backend.Lock(ctx, key)
+}
+
+func (b lockBackend) TryLock(ctx context.Context, key string) (lock.Handle, error) {
+	return b.backend.TryLock(ctx, key)
+}
+
+// NewKeyLock returns a lock for keys that is shared by all managers using
+// the same backend. Keys are namespaced by prefix, so they don't collide
+// with the locks the state manager takes itself. Local holders of the
+// passed KeyLock are excluded as well.
+func (sm *StateManager) NewKeyLock(prefix string, local *lock.KeyLock) *lock.DistributedKeyLock {
+	return lock.NewDistributedKeyLock(local, lockBackend{backend: sm.backend}, lock.DistributedOptions{
+		Prefix:        prefix,
+		RetryInterval: lock.DefaultDistributedOptions().RetryInterval,
+	})
+}
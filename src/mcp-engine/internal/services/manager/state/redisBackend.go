@@ -2,24 +2,55 @@ package state
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/hex"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/metorial/metorial/modules/lock"
 )
 
 type RedisBackend struct {
 	client *redis.Client
 }
 
+// RedisLockHandle holds a lock key that expires after timeout. The
+// expiry is renewed while the lock is held, so work under the lock can
+// take longer than timeout, while a crashed holder releases it.
 type RedisLockHandle struct {
 	client  *redis.Client
 	key     string
 	value   string
 	timeout time.Duration
+
+	stopRenewal chan struct{}
+	renewalDone chan struct{}
+	stopOnce    sync.Once
 }
 
+const (
+	redisLockTimeout = 30 * time.Second
+
+	// Only the holder may renew or release a lock
+	renewLockScript = `
+		if redis.call("GET", KEYS[1]) == ARGV[1] then
+			return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+		else
+			return 0
+		end
+	`
+	unlockScript = `
+		if redis.call("GET", KEYS[1]) == ARGV[1] then
+			return redis.call("DEL", KEYS[1])
+		else
+			return 0
+		end
+	`
+)
+
 func NewRedisBackend(config Config) (*RedisBackend, error) {
 	if len(config.Endpoints) == 0 {
 		return nil, fmt.Errorf("no redis endpoints provided")
@@ -95,10 +126,21 @@ func (r *RedisBackend) List(ctx context.Context, prefix string) (map[string]stri
 	return result, nil
 }
 
+// Lock doesn't wait for held locks, it returns lock.ErrLockHeld like
+// TryLock.
 func (r *RedisBackend) Lock(ctx context.Context, key string) (LockHandle, error) {
+	return r.TryLock(ctx, key)
+}
+
+func (r *RedisBackend) TryLock(ctx context.Context, key string) (LockHandle, error) {
 	lockKey := fmt.Sprintf("lock:%s", key)
-	lockValue := fmt.Sprintf("%d", time.Now().UnixNano())
-	timeout := 30 * time.Second
+	timeout := redisLockTimeout
+
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("failed to create lock value: %v", err)
+	}
+	lockValue := hex.EncodeToString(random)
 
 	// Try to acquire lock with SET NX EX
 	result := r.client.SetNX(ctx, lockKey, lockValue, timeout)
@@ -107,32 +149,60 @@ func (r *RedisBackend) Lock(ctx context.Context, key string) (LockHandle, error)
 	}
 
 	if !result.Val() {
-		return nil, fmt.Errorf("lock already held")
+		return nil, lock.ErrLockHeld
 	}
 
-	return &RedisLockHandle{
-		client:  r.client,
-		key:     lockKey,
-		value:   lockValue,
-		timeout: timeout,
-	}, nil
+	handle := &RedisLockHandle{
+		client:      r.client,
+		key:         lockKey,
+		value:       lockValue,
+		timeout:     timeout,
+		stopRenewal: make(chan struct{}),
+		renewalDone: make(chan struct{}),
+	}
+
+	go handle.renew()
+
+	return handle, nil
 }
 
 func (r *RedisBackend) Close() error {
 	return r.client.Close()
 }
 
+// renew extends the expiry of the lock until it is unlocked. It stops
+// once the lock is lost, e.g. after Redis was unreachable for longer than
+// the timeout.
+func (h *RedisLockHandle) renew() {
+	defer close(h.renewalDone)
+
+	ticker := time.NewTicker(h.timeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-h.stopRenewal:
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), h.timeout/3)
+		renewed, err := h.client.Eval(ctx, renewLockScript, []string{h.key}, h.value, h.timeout.Milliseconds()).Int()
+		cancel()
+
+		if err == nil && renewed == 0 {
+			return
+		}
+	}
+}
+
 func (h *RedisLockHandle) Unlock(ctx context.Context) error {
-	// Use Lua script to ensure we only delete if we own the lock
-	script := `
-		if redis.call("GET", KEYS[1]) == ARGV[1] then
-			return redis.call("DEL", KEYS[1])
-		else
-			return 0
-		end
-	`
+	h.stopOnce.Do(func() {
+		close(h.stopRenewal)
+	})
+	<-h.renewalDone
 
-	result := h.client.Eval(ctx, script, []string{h.key}, h.value)
+	result := h.client.Eval(ctx, unlockScript, []string{h.key}, h.value)
 	if result.Err() != nil {
 		return fmt.Errorf("failed to release lock: %v", result.Err())
 	}
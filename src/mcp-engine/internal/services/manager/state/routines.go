@@ -2,8 +2,10 @@ package state
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
+
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 )
 
 const SESSION_DEAD_TIMEOUT = 1000 * 60
@@ -16,11 +18,11 @@ func (sm *StateManager) startPingRoutine() {
 	for {
 		select {
 		case <-sm.ctx.Done():
-			log.Println("Ping routine stopped")
+			sm.logger.Debug("ping routine stopped")
 			return
 		case <-ticker.C:
 			if err := sm.updateManagerPing(); err != nil {
-				log.Printf("Failed to update manager ping: %v", err)
+				sm.logger.Error("failed to update manager ping", logging.Err(err))
 			}
 		}
 	}
@@ -48,15 +50,15 @@ func (sm *StateManager) startCleanupRoutine() {
 	for {
 		select {
 		case <-sm.ctx.Done():
-			log.Println("Cleanup routine stopped")
+			sm.logger.Debug("cleanup routine stopped")
 			return
 		case <-ticker.C:
 			if err := sm.cleanupDeadManagers(); err != nil {
-				log.Printf("Failed to cleanup dead managers: %v", err)
+				sm.logger.Error("failed to cleanup dead managers", logging.Err(err))
 			}
 
 			if err := sm.cleanupDeadSessions(); err != nil {
-				log.Printf("Failed to cleanup dead sessions: %v", err)
+				sm.logger.Error("failed to cleanup dead sessions", logging.Err(err))
 			}
 		}
 	}
@@ -79,14 +81,14 @@ func (sm *StateManager) cleanupDeadManagers() error {
 
 	for _, managerID := range deadManagers {
 		if err := sm.DeleteManager(managerID); err != nil {
-			log.Printf("Failed to delete dead manager %s: %v", managerID, err)
+			sm.logger.Error("failed to delete dead manager", slog.String("target_manager_id", managerID), logging.Err(err))
 		} else {
-			log.Printf("Removed dead manager %s", managerID)
+			sm.logger.Info("removed dead manager", slog.String("target_manager_id", managerID))
 		}
 	}
 
 	if len(deadManagers) > 0 {
-		log.Printf("Cleaned up %d dead managers", len(deadManagers))
+		sm.logger.Info("cleaned up dead managers", slog.Int("count", len(deadManagers)))
 	}
 
 	return nil
@@ -109,14 +111,14 @@ func (sm *StateManager) cleanupDeadSessions() error {
 
 	for _, sessionID := range deadSessions {
 		if _, err := sm.DeleteSession(sessionID); err != nil {
-			log.Printf("Failed to delete dead session %s: %v", sessionID, err)
+			sm.logger.Error("failed to delete dead session", logging.SessionId(sessionID), logging.Err(err))
 		} else {
-			log.Printf("Removed dead session %s", sessionID)
+			sm.logger.Info("removed dead session", logging.SessionId(sessionID))
 		}
 	}
 
 	if len(deadSessions) > 0 {
-		log.Printf("Cleaned up %d dead sessions", len(deadSessions))
+		sm.logger.Info("cleaned up dead sessions", slog.Int("count", len(deadSessions)))
 	}
 
 	return nil
@@ -125,41 +127,34 @@ func (sm *StateManager) cleanupDeadSessions() error {
 func PrintStatus(sm *StateManager) {
 	managers, err := sm.ListManagers()
 	if err != nil {
-		log.Printf("Failed to get managers: %v", err)
+		sm.logger.Error("failed to get managers", logging.Err(err))
 		return
 	}
 
 	sessions, err := sm.ListSessions()
 	if err != nil {
-		log.Printf("Failed to get sessions: %v", err)
+		sm.logger.Error("failed to get sessions", logging.Err(err))
 		return
 	}
 
-	log.Printf("=== System Status ===")
-	log.Printf("Managers: %d, Sessions: %d",
-		len(managers), len(sessions))
+	sm.logger.Info("system status", slog.Int("managers", len(managers)), slog.Int("sessions", len(sessions)))
 
-	log.Printf("--- Managers ---")
 	for _, manager := range managers {
-		lastPing := time.UnixMilli(manager.LastPingAt)
-		joined := time.UnixMilli(manager.JoinedAt)
-		isSelf := ""
-		if manager.ID == sm.ManagerID {
-			isSelf = " (self)"
-		}
-		log.Printf("  %s%s - %s, Joined: %s, Last Ping: %s",
-			manager.ID[:8], isSelf, manager.ManagerAddress,
-			joined.Format("15:04:05"), lastPing.Format("15:04:05"))
+		sm.logger.Info("manager status",
+			slog.String("target_manager_id", manager.ID),
+			slog.Bool("self", manager.ID == sm.ManagerID),
+			slog.String("address", manager.ManagerAddress),
+			slog.Time("joined_at", time.UnixMilli(manager.JoinedAt)),
+			slog.Time("last_ping_at", time.UnixMilli(manager.LastPingAt)),
+		)
 	}
 
-	log.Printf("--- Sessions ---")
 	for _, conn := range sessions {
-		lastPing := time.UnixMilli(conn.LastPingAt)
-		created := time.UnixMilli(conn.CreatedAt)
-		log.Printf("  %s - Manager: %s, Created: %s, Last Ping: %s",
-			conn.ID[:8], conn.ManagerID[:8],
-			created.Format("15:04:05"), lastPing.Format("15:04:05"))
+		sm.logger.Info("session status",
+			logging.SessionId(conn.ID),
+			slog.String("session_manager_id", conn.ManagerID),
+			slog.Time("created_at", time.UnixMilli(conn.CreatedAt)),
+			slog.Time("last_ping_at", time.UnixMilli(conn.LastPingAt)),
+		)
 	}
-
-	log.Printf("====================")
 }
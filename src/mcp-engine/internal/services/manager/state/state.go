@@ -4,10 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/modules/util"
 )
 
@@ -19,6 +20,8 @@ type StateManager struct {
 
 	backend StorageBackend
 
+	logger *slog.Logger
+
 	ctx    context.Context
 	cancel context.CancelFunc
 }
@@ -41,10 +44,12 @@ func NewStateManager(config Config, managerAddress, workerBrokerAddress string)
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
+	managerID := util.Must(uuid.NewV7()).String()
 
 	return &StateManager{
 		backend:             backend,
-		ManagerID:           util.Must(uuid.NewV7()).String(),
+		logger:              slog.Default().With(logging.ManagerId(managerID)),
+		ManagerID:           managerID,
 		ManagerAddress:      managerAddress,
 		WorkerBrokerAddress: workerBrokerAddress,
 		ctx:                 ctx,
@@ -53,7 +58,7 @@ func NewStateManager(config Config, managerAddress, workerBrokerAddress string)
 }
 
 func (sm *StateManager) Start() error {
-	log.Printf("Starting state manager with ID: %s", sm.ManagerID)
+	sm.logger.Info("starting state manager")
 
 	if err := sm.CreateManager(sm.ManagerID, sm.ManagerAddress, sm.WorkerBrokerAddress); err != nil {
 		return fmt.Errorf("failed to register manager: %v", err)
@@ -66,7 +71,7 @@ func (sm *StateManager) Start() error {
 }
 
 func (sm *StateManager) Stop() error {
-	log.Printf("Stopping state manager with ID: %s", sm.ManagerID)
+	sm.logger.Info("stopping state manager")
 
 	sm.cancel()
 
@@ -76,7 +81,7 @@ func (sm *StateManager) Stop() error {
 	defer cleanupCancel()
 
 	if err := sm.deleteManagerWithContext(cleanupCtx, sm.ManagerID); err != nil {
-		log.Printf("Failed to unregister manager: %v", err)
+		sm.logger.Error("failed to unregister manager", logging.Err(err))
 	}
 
 	return sm.backend.Close()
@@ -108,10 +113,11 @@ func (sm *StateManager) CreateManager(id, managerAddress, workerBrokerAddress st
 		return fmt.Errorf("failed to create manager: %v", err)
 	}
 
-	log.Printf("Created manager %s at %s", id, managerAddress)
-	if workerBrokerAddress != managerAddress {
-		log.Printf("Worker broker address: %s", workerBrokerAddress)
-	}
+	sm.logger.Info("created manager",
+		slog.String("target_manager_id", id),
+		slog.String("address", managerAddress),
+		slog.String("worker_broker_address", workerBrokerAddress),
+	)
 
 	return nil
 }
@@ -144,7 +150,7 @@ func (sm *StateManager) ListManagers() ([]Manager, error) {
 	for _, value := range data {
 		var manager Manager
 		if err := json.Unmarshal([]byte(value), &manager); err != nil {
-			log.Printf("Failed to unmarshal manager data: %v", err)
+			sm.logger.Warn("failed to unmarshal manager data", logging.Err(err))
 			continue
 		}
 		managers = append(managers, manager)
@@ -173,7 +179,7 @@ func (sm *StateManager) deleteManagerWithContext(ctx context.Context, id string)
 		return fmt.Errorf("failed to delete manager: %v", err)
 	}
 
-	log.Printf("Deleted manager %s", id)
+	sm.logger.Info("deleted manager", slog.String("target_manager_id", id))
 	return nil
 }
 
@@ -215,7 +221,7 @@ func (sm *StateManager) UpsertSession(sessionID string, managerID string, sessio
 			return nil, fmt.Errorf("failed to store session: %v", err)
 		}
 
-		log.Printf("Created new session %s (manager: %s)", session.ID, managerID)
+		sm.logger.Info("created new session", logging.SessionId(session.ID), slog.String("session_manager_id", managerID))
 		return &session, nil
 	})
 }
@@ -249,7 +255,7 @@ func (sm *StateManager) ListSessions() ([]Session, error) {
 	for _, value := range data {
 		var session Session
 		if err := json.Unmarshal([]byte(value), &session); err != nil {
-			log.Printf("Failed to unmarshal session data: %v", err)
+			sm.logger.Warn("failed to unmarshal session data", logging.Err(err))
 			continue
 		}
 		sessions = append(sessions, session)
@@ -296,7 +302,7 @@ func (sm *StateManager) DeleteSession(id string) (*Session, error) {
 			return nil, fmt.Errorf("failed to delete session: %v", err)
 		}
 
-		log.Printf("Deleted session %s", id)
+		sm.logger.Info("deleted session", logging.SessionId(id))
 		return nil, nil
 	})
 }
@@ -308,7 +314,7 @@ func (sm *StateManager) withSessionLock(key string, fn func() (*Session, error))
 	}
 	defer func() {
 		if err := lock.Unlock(sm.ctx); err != nil {
-			log.Printf("failed to release lock for key %s: %v", key, err)
+			sm.logger.Error("failed to release lock", slog.String("key", key), logging.Err(err))
 		}
 	}()
 
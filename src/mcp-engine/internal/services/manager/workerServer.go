@@ -3,7 +3,7 @@ package manager
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
 	workerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/worker"
 	workerBrokerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/workerBroker"
@@ -12,6 +12,7 @@ import (
 	launcherWorker "github.com/metorial/metorial/mcp-engine/internal/services/manager/workers/launcher-worker"
 	remoteWorker "github.com/metorial/metorial/mcp-engine/internal/services/manager/workers/remote-worker"
 	runnerWorker "github.com/metorial/metorial/mcp-engine/internal/services/manager/workers/runner-worker"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 )
 
 type workerBrokerServer struct {
@@ -42,14 +43,20 @@ func (s *workerBrokerServer) ListManagers(ctx context.Context, req *workerBroker
 }
 
 func (s *workerBrokerServer) RegisterWorker(ctx context.Context, req *workerBrokerPb.RegisterWorkerRequest) (*workerBrokerPb.RegisterWorkerResponse, error) {
+	logger := s.workerManager.Logger().With(
+		logging.WorkerId(req.WorkerId),
+		slog.String("worker_type", req.WorkerType.String()),
+		slog.String("address", req.Address),
+	)
+
 	_, exiting := s.workerManager.GetWorker(req.WorkerId)
 	if exiting {
-		log.Printf("Worker %s already registered, ignoring registration request", req.WorkerId)
+		logger.Debug("worker already registered, ignoring registration request")
 		return &workerBrokerPb.RegisterWorkerResponse{}, nil
 	}
 
 	var worker workers.Worker
-	log.Printf("Registering worker %s of type %s at address %s", req.WorkerId, req.WorkerType, req.Address)
+	logger.Info("registering worker")
 
 	switch req.WorkerType {
 	case workerPb.WorkerType_mcp_runner:
@@ -64,7 +71,7 @@ func (s *workerBrokerServer) RegisterWorker(ctx context.Context, req *workerBrok
 
 	err := s.workerManager.RegisterWorker(worker)
 	if err != nil {
-		log.Printf("Failed to register worker %s: %v", req.WorkerId, err)
+		logger.Error("failed to register worker", logging.Err(err))
 		return nil, fmt.Errorf("failed to register worker %s: %w", req.WorkerId, err)
 	}
 
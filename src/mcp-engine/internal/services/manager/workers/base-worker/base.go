@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"sync"
 	"time"
 
 	launcherPB "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/launcher"
 	workerPB "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/worker"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/workers"
+	grpc_util "github.com/metorial/metorial/mcp-engine/pkg/grpcUtil"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/modules/pubsub"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/backoff"
@@ -35,10 +38,12 @@ type BaseWorkerConnection struct {
 	status        workerPB.WorkerStatus
 	lastUpdate    time.Time
 
+	logger *slog.Logger
+
 	mutex sync.Mutex
 }
 
-func NewBaseWorkerConnection(ctx context.Context, workerID, address string, isStandalone bool) *BaseWorkerConnection {
+func NewBaseWorkerConnection(ctx context.Context, logger *slog.Logger, workerID, address string, isStandalone bool) *BaseWorkerConnection {
 	ctx, cancel := context.WithCancel(ctx)
 
 	res := &BaseWorkerConnection{
@@ -56,6 +61,8 @@ func NewBaseWorkerConnection(ctx context.Context, workerID, address string, isSt
 		status:        workerPB.WorkerStatus_unhealthy,
 
 		lastUpdate: time.Now(),
+
+		logger: logger,
 	}
 
 	return res
@@ -86,6 +93,7 @@ func (bw *BaseWorkerConnection) Start() error {
 			},
 			MinConnectTimeout: 5 * time.Second,
 		}),
+		grpc_util.WithTracing(),
 	)
 	if err != nil {
 		return err
@@ -116,12 +124,21 @@ func (bw *BaseWorkerConnection) WorkerID() string {
 	return bw.workerID
 }
 
+// Logger returns a logger carrying the worker's ID, which is only known
+// once the worker has reported its info.
+func (bw *BaseWorkerConnection) Logger() *slog.Logger {
+	return bw.logger.With(
+		logging.WorkerId(bw.workerID),
+		slog.String("address", bw.address),
+	)
+}
+
 func (bw *BaseWorkerConnection) Address() string {
 	return bw.address
 }
 
 func (bw *BaseWorkerConnection) Stop() error {
-	log.Printf("Stopping worker connection for %s at %s", bw.workerID, bw.address)
+	bw.Logger().Info("stopping worker connection")
 
 	bw.mutex.Lock()
 	defer bw.mutex.Unlock()
@@ -208,7 +225,7 @@ func (bw *BaseWorkerConnection) healthRoutine() error {
 				return nil
 			}
 
-			log.Printf("Error receiving health update: %v\n", err)
+			bw.Logger().Warn("error receiving health update", logging.Err(err))
 
 			return err
 		}
@@ -1,6 +1,7 @@
 package workers
 
 import (
+	"context"
 	"time"
 
 	mcpPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/mcp"
@@ -13,10 +14,10 @@ import (
 type WorkerConnection interface {
 	ConnectionID() string
 
-	AcceptMessage(message *mcp.MCPMessage) error
+	AcceptMessage(ctx context.Context, message *mcp.MCPMessage) error
 	GetServer() (*mcp.MCPServer, error)
 
-	Start(shouldAutoInit bool) error
+	Start(ctx context.Context, shouldAutoInit bool) error
 	Close() error
 
 	Done() pubsub.BroadcasterReader[struct{}]
@@ -3,7 +3,7 @@ package launcher_worker
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 
 	launcherPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/launcher"
@@ -22,7 +22,7 @@ type LauncherWorker struct {
 
 func NewLauncherWorker(ctx context.Context, manager *workers.WorkerManager, workerID, address string, isStandalone bool) *LauncherWorker {
 	res := &LauncherWorker{
-		BaseWorkerConnection: base_worker.NewBaseWorkerConnection(ctx, workerID, address, isStandalone),
+		BaseWorkerConnection: base_worker.NewBaseWorkerConnection(ctx, manager.Logger(), workerID, address, isStandalone),
 
 		manager: manager,
 		client:  nil,
@@ -32,7 +32,7 @@ func NewLauncherWorker(ctx context.Context, manager *workers.WorkerManager, work
 }
 
 func (rw *LauncherWorker) Start() error {
-	log.Printf("Starting LauncherWorker %s at %s", rw.WorkerID(), rw.Address())
+	rw.Logger().Info("starting worker", slog.String("worker_type", string(rw.Type())))
 
 	rw.mutex.Lock()
 	defer rw.mutex.Unlock()
@@ -53,7 +53,7 @@ func (rw *LauncherWorker) Start() error {
 }
 
 func (rw *LauncherWorker) Stop() error {
-	log.Printf("Stopping LauncherWorker %s at %s", rw.WorkerID(), rw.Address())
+	rw.Logger().Info("stopping worker", slog.String("worker_type", string(rw.Type())))
 
 	rw.mutex.Lock()
 	defer rw.mutex.Unlock()
@@ -100,7 +100,7 @@ func (rw *LauncherWorker) monitor() {
 
 	rw.BaseWorkerConnection.Wait()
 
-	log.Printf("LauncherWorker %s at %s has stopped", rw.WorkerID(), rw.Address())
+	rw.Logger().Info("worker has stopped", slog.String("worker_type", string(rw.Type())))
 
 	rw.mutex.Lock()
 	defer rw.mutex.Unlock()
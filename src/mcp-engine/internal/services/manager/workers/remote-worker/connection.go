@@ -1,6 +1,7 @@
 package remote_worker
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -30,7 +31,7 @@ func (rw *RemoteWorker) CreateConnection(input *workers.WorkerConnectionInput) (
 		return nil, fmt.Errorf("McpRemoteClient is not initialized for worker %s at %s", rw.WorkerID(), rw.Address())
 	}
 
-	run := NewRun(input, rw.client, input.ConnectionID)
+	run := NewRun(input, rw.client, input.ConnectionID, rw.Logger())
 
 	res := &RemoteWorkerConnection{
 		run:       run,
@@ -44,12 +45,12 @@ func (rw *RemoteWorker) CreateConnection(input *workers.WorkerConnectionInput) (
 	return res, nil
 }
 
-func (rwc *RemoteWorkerConnection) Start(shouldAutoInit bool) error {
+func (rwc *RemoteWorkerConnection) Start(ctx context.Context, shouldAutoInit bool) error {
 	if shouldAutoInit && rwc.mcpClient == nil {
 		return fmt.Errorf("MCP client is not initialized, cannot auto-initialize")
 	}
 
-	if err := rwc.run.Start(); err != nil {
+	if err := rwc.run.Start(ctx); err != nil {
 		return fmt.Errorf("failed to start MCP run: %w", err)
 	}
 
@@ -59,7 +60,7 @@ func (rwc *RemoteWorkerConnection) Start(shouldAutoInit bool) error {
 			return fmt.Errorf("failed to create MCP init message: %w", err)
 		}
 
-		serverInitMsg, err := rwc.SendAndWaitForResponse(init)
+		serverInitMsg, err := rwc.SendAndWaitForResponse(ctx, init)
 		if err != nil {
 			return fmt.Errorf("failed to send MCP init message: %w", err)
 		}
@@ -98,11 +99,11 @@ func (rwc *RemoteWorkerConnection) GetServer() (*mcp.MCPServer, error) {
 	return rwc.mcpServer, nil
 }
 
-func (rwc *RemoteWorkerConnection) AcceptMessage(message *mcp.MCPMessage) error {
-	return rwc.run.SendMessage(message.ToPbRawMessage())
+func (rwc *RemoteWorkerConnection) AcceptMessage(ctx context.Context, message *mcp.MCPMessage) error {
+	return rwc.run.SendMessage(ctx, message)
 }
 
-func (rws *RemoteWorkerConnection) SendAndWaitForResponse(message *mcp.MCPMessage) (*mcp.MCPMessage, error) {
+func (rws *RemoteWorkerConnection) SendAndWaitForResponse(ctx context.Context, message *mcp.MCPMessage) (*mcp.MCPMessage, error) {
 	if message.MsgType != mcp.RequestType {
 		return nil, fmt.Errorf("only request messages can be sent and waited for a response")
 	}
@@ -117,7 +118,7 @@ func (rws *RemoteWorkerConnection) SendAndWaitForResponse(message *mcp.MCPMessag
 	defer rws.run.Done().Unsubscribe(donechan)
 
 	go func() {
-		if err := rws.AcceptMessage(message); err != nil {
+		if err := rws.AcceptMessage(ctx, message); err != nil {
 			errchan <- fmt.Errorf("failed to send message: %w", err)
 		}
 	}()
@@ -132,7 +133,14 @@ func (rws *RemoteWorkerConnection) SendAndWaitForResponse(message *mcp.MCPMessag
 			return nil, err
 		case <-timeout:
 			return nil, fmt.Errorf("timeout waiting for response to message: %s", message.GetStringPayload())
-		case msg := <-msgchan:
+		case msg, ok := <-msgchan:
+			if !ok {
+				if err := rws.run.messages.Err(msgchan); err != nil {
+					return nil, fmt.Errorf("stopped waiting for response: %w", err)
+				}
+				return nil, fmt.Errorf("connection closed before receiving response")
+			}
+
 			if msg != nil {
 				return msg, nil
 			}
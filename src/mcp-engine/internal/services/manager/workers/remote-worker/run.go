@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -12,8 +12,12 @@ import (
 	mcpPB "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/mcp"
 	remotePb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/remote"
 	"github.com/metorial/metorial/mcp-engine/internal/services/manager/workers"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
+	"github.com/metorial/metorial/mcp-engine/pkg/tracing"
 	"github.com/metorial/metorial/modules/pubsub"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Run struct {
@@ -35,12 +39,16 @@ type Run struct {
 	output   *pubsub.Broadcaster[*mcpPB.McpOutput]
 	errors   *pubsub.Broadcaster[*mcpPB.McpError]
 
-	initError error
+	initError    error
+	traceContext map[string]string
+
+	logger       *slog.Logger
+	parentLogger *slog.Logger
 }
 
-func NewRun(input *workers.WorkerConnectionInput, client remotePb.McpRemoteClient, connectionId string) *Run {
+func NewRun(input *workers.WorkerConnectionInput, client remotePb.McpRemoteClient, connectionId string, logger *slog.Logger) *Run {
 	if client == nil {
-		log.Println("McpRemoteClient is nil, cannot create Run")
+		logger.Error("McpRemoteClient is nil, cannot create Run")
 		return nil
 	}
 
@@ -58,13 +66,16 @@ func NewRun(input *workers.WorkerConnectionInput, client remotePb.McpRemoteClien
 
 		doneBroadcaster: pubsub.NewBroadcaster[struct{}](),
 
-		messages: pubsub.NewBroadcaster[*mcp.MCPMessage](),
+		messages: pubsub.NewBroadcasterWithOptions[*mcp.MCPMessage](pubsub.LosslessBroadcasterOptions()),
 		errors:   pubsub.NewBroadcaster[*mcpPB.McpError](),
 		output:   pubsub.NewBroadcaster[*mcpPB.McpOutput](),
+
+		logger:       logger.With(logging.SessionId(input.SessionID), logging.RunId(connectionId)),
+		parentLogger: logger,
 	}
 }
 
-func (r *Run) Start() error {
+func (r *Run) Start(ctx context.Context) error {
 	if r.client == nil {
 		return fmt.Errorf("McpRemoteClient is not initialized")
 	}
@@ -73,6 +84,13 @@ func (r *Run) Start() error {
 		return fmt.Errorf("Run stream is already initialized")
 	}
 
+	ctx, span := tracing.Start(ctx, "remote_worker.Run.Start", trace.WithAttributes(
+		attribute.String("connection_id", r.ConnectionID),
+	))
+	defer span.End()
+
+	r.traceContext = tracing.InjectMap(ctx)
+
 	r.createStreamWg.Add(1)
 
 	go r.handleStream()
@@ -80,26 +98,38 @@ func (r *Run) Start() error {
 	r.createStreamWg.Wait()
 
 	if r.initError != nil {
+		tracing.RecordError(span, r.initError)
 		return fmt.Errorf("failed to create MCP run stream: %w", r.initError)
 	}
 
 	return nil
 }
 
-func (r *Run) SendMessage(msg *mcpPB.McpMessageRaw) error {
+func (r *Run) SendMessage(ctx context.Context, message *mcp.MCPMessage) error {
 	r.createStreamWg.Wait()
 
+	ctx, span := tracing.StartMessageSpan(ctx, "remote_worker.Run.SendMessage", message,
+		attribute.String("connection_id", r.ConnectionID),
+	)
+	defer span.End()
+
 	if r.stream == nil {
-		return fmt.Errorf("Run stream is not initialized")
+		err := fmt.Errorf("Run stream is not initialized")
+		tracing.RecordError(span, err)
+		return err
 	}
 
-	return r.stream.Send(&remotePb.RunRequest{
+	err := r.stream.Send(&remotePb.RunRequest{
 		Type: &remotePb.RunRequest_McpMessage{
 			McpMessage: &remotePb.RunRequestMcpMessage{
-				Message: msg,
+				Message:      message.ToPbRawMessage(),
+				TraceContext: tracing.InjectMap(ctx),
 			},
 		},
 	})
+	tracing.RecordError(span, err)
+
+	return err
 }
 
 func (r *Run) Close() error {
@@ -143,20 +173,25 @@ func (r *Run) Done() pubsub.BroadcasterReader[struct{}] {
 func (r *Run) Clone() *Run {
 	ctx, cancel := context.WithCancel(context.Background())
 
+	connectionId := uuid.Must(uuid.NewV7()).String()
+
 	newRun := &Run{
 		context: ctx,
 		cancel:  cancel,
 
 		Config:       r.Config,
-		ConnectionID: uuid.Must(uuid.NewV7()).String(),
+		ConnectionID: connectionId,
 
 		input:  r.input,
 		client: r.client,
 
 		doneBroadcaster: pubsub.NewBroadcaster[struct{}](),
-		messages:        pubsub.NewBroadcaster[*mcp.MCPMessage](),
+		messages:        pubsub.NewBroadcasterWithOptions[*mcp.MCPMessage](pubsub.LosslessBroadcasterOptions()),
 		output:          pubsub.NewBroadcaster[*mcpPB.McpOutput](),
 		errors:          pubsub.NewBroadcaster[*mcpPB.McpError](),
+
+		logger:       r.parentLogger.With(logging.SessionId(r.input.SessionID), logging.RunId(connectionId)),
+		parentLogger: r.parentLogger,
 	}
 
 	return newRun
@@ -165,6 +200,14 @@ func (r *Run) Clone() *Run {
 func (r *Run) handleStream() {
 	defer r.cancel()
 	defer r.messages.Close()
+	defer func() {
+		if stats := r.messages.Stats(); stats.Dropped > 0 {
+			r.logger.Warn("MCP messages were not delivered to all subscribers",
+				slog.Uint64("dropped", stats.Dropped),
+				slog.Uint64("overflows", stats.Overflows),
+			)
+		}
+	}()
 	defer r.errors.Close()
 	defer r.output.Close()
 	defer func() {
@@ -221,6 +264,8 @@ func (r *Run) handleStream() {
 				Client: &remotePb.RunConfigLambdaClient{
 					Participant: participant,
 				},
+				TraceContext: r.traceContext,
+				SessionId:    r.input.SessionID,
 			},
 		},
 	})
@@ -262,7 +307,7 @@ loop:
 				break loop
 			}
 
-			log.Printf("Error receiving response: %v\n", err)
+			r.logger.Warn("error receiving response", logging.Err(err))
 			break loop
 		}
 
@@ -293,11 +338,11 @@ loop:
 			go r.Close()
 
 		case *remotePb.RunResponse_Close:
-			log.Printf("Run %s closed by server\n", r.ConnectionID)
+			r.logger.Info("run closed by server")
 			break loop
 
 		default:
-			log.Printf("Unknown response type: %T\n", msg)
+			r.logger.Warn("unknown response type", slog.String("type", fmt.Sprintf("%T", msg)))
 		}
 	}
 }
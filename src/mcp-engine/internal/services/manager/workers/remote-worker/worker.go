@@ -3,7 +3,7 @@ package remote_worker
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 
 	remotePb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/remote"
@@ -22,7 +22,7 @@ type RemoteWorker struct {
 
 func NewRemoteWorker(ctx context.Context, manager *workers.WorkerManager, workerID, address string, isStandalone bool) *RemoteWorker {
 	res := &RemoteWorker{
-		BaseWorkerConnection: base_worker.NewBaseWorkerConnection(ctx, workerID, address, isStandalone),
+		BaseWorkerConnection: base_worker.NewBaseWorkerConnection(ctx, manager.Logger(), workerID, address, isStandalone),
 
 		manager: manager,
 		client:  nil,
@@ -32,7 +32,7 @@ func NewRemoteWorker(ctx context.Context, manager *workers.WorkerManager, worker
 }
 
 func (rw *RemoteWorker) Start() error {
-	log.Printf("Starting RemoteWorker %s at %s", rw.WorkerID(), rw.Address())
+	rw.Logger().Info("starting worker", slog.String("worker_type", string(rw.Type())))
 
 	rw.mutex.Lock()
 	defer rw.mutex.Unlock()
@@ -53,7 +53,7 @@ func (rw *RemoteWorker) Start() error {
 }
 
 func (rw *RemoteWorker) Stop() error {
-	log.Printf("Stopping RemoteWorker %s at %s", rw.WorkerID(), rw.Address())
+	rw.Logger().Info("stopping worker", slog.String("worker_type", string(rw.Type())))
 
 	rw.mutex.Lock()
 	defer rw.mutex.Unlock()
@@ -85,7 +85,7 @@ func (rw *RemoteWorker) monitor() {
 
 	rw.BaseWorkerConnection.Wait()
 
-	log.Printf("RemoteWorker %s at %s has stopped", rw.WorkerID(), rw.Address())
+	rw.Logger().Info("worker has stopped", slog.String("worker_type", string(rw.Type())))
 
 	rw.mutex.Lock()
 	defer rw.mutex.Unlock()
@@ -4,16 +4,20 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	mcpPB "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/mcp"
 	runnerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/runner"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
+	"github.com/metorial/metorial/mcp-engine/pkg/tracing"
 	"github.com/metorial/metorial/modules/pubsub"
 	"github.com/metorial/metorial/modules/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Run struct {
@@ -21,6 +25,7 @@ type Run struct {
 	cancel  context.CancelFunc
 
 	ConnectionID string
+	SessionID    string
 	Config       *runnerPb.RunConfig
 
 	client runnerPb.McpRunnerClient
@@ -34,12 +39,16 @@ type Run struct {
 	output   *pubsub.Broadcaster[*mcpPB.McpOutput]
 	errors   *pubsub.Broadcaster[*mcpPB.McpError]
 
-	initError error
+	initError    error
+	traceContext map[string]string
+
+	logger       *slog.Logger
+	parentLogger *slog.Logger
 }
 
-func NewRun(config *runnerPb.RunConfig, client runnerPb.McpRunnerClient, connectionId string) *Run {
+func NewRun(config *runnerPb.RunConfig, client runnerPb.McpRunnerClient, connectionId, sessionId string, logger *slog.Logger) *Run {
 	if client == nil {
-		log.Println("McpRunnerClient is nil, cannot create Run")
+		logger.Error("McpRunnerClient is nil, cannot create Run")
 		return nil
 	}
 
@@ -51,18 +60,22 @@ func NewRun(config *runnerPb.RunConfig, client runnerPb.McpRunnerClient, connect
 
 		Config:       config,
 		ConnectionID: connectionId,
+		SessionID:    sessionId,
 
 		client: client,
 
 		doneBroadcaster: pubsub.NewBroadcaster[struct{}](),
 
-		messages: pubsub.NewBroadcaster[*mcp.MCPMessage](),
+		messages: pubsub.NewBroadcasterWithOptions[*mcp.MCPMessage](pubsub.LosslessBroadcasterOptions()),
 		errors:   pubsub.NewBroadcaster[*mcpPB.McpError](),
 		output:   pubsub.NewBroadcaster[*mcpPB.McpOutput](),
+
+		logger:       logger.With(logging.SessionId(sessionId), logging.RunId(connectionId)),
+		parentLogger: logger,
 	}
 }
 
-func (r *Run) Start() error {
+func (r *Run) Start(ctx context.Context) error {
 	if r.client == nil {
 		return fmt.Errorf("McpRunnerClient is not initialized")
 	}
@@ -71,6 +84,14 @@ func (r *Run) Start() error {
 		return fmt.Errorf("Run stream is already initialized")
 	}
 
+	ctx, span := tracing.Start(ctx, "runner_worker.Run.Start", trace.WithAttributes(
+		attribute.String("connection_id", r.ConnectionID),
+		attribute.String("docker_image", r.Config.GetContainer().GetDockerImage()),
+	))
+	defer span.End()
+
+	r.traceContext = tracing.InjectMap(ctx)
+
 	r.createStreamWg.Add(1)
 
 	go r.handleStream()
@@ -78,26 +99,38 @@ func (r *Run) Start() error {
 	r.createStreamWg.Wait()
 
 	if r.initError != nil {
+		tracing.RecordError(span, r.initError)
 		return fmt.Errorf("failed to create MCP run stream: %w", r.initError)
 	}
 
 	return nil
 }
 
-func (r *Run) SendMessage(msg *mcpPB.McpMessageRaw) error {
+func (r *Run) SendMessage(ctx context.Context, message *mcp.MCPMessage) error {
 	r.createStreamWg.Wait()
 
+	ctx, span := tracing.StartMessageSpan(ctx, "runner_worker.Run.SendMessage", message,
+		attribute.String("connection_id", r.ConnectionID),
+	)
+	defer span.End()
+
 	if r.stream == nil {
-		return fmt.Errorf("Run stream is not initialized")
+		err := fmt.Errorf("Run stream is not initialized")
+		tracing.RecordError(span, err)
+		return err
 	}
 
-	return r.stream.Send(&runnerPb.RunRequest{
+	err := r.stream.Send(&runnerPb.RunRequest{
 		Type: &runnerPb.RunRequest_McpMessage{
 			McpMessage: &runnerPb.RunRequestMcpMessage{
-				Message: msg,
+				Message:      message.ToPbRawMessage(),
+				TraceContext: tracing.InjectMap(ctx),
 			},
 		},
 	})
+	tracing.RecordError(span, err)
+
+	return err
 }
 
 func (r *Run) Close() error {
@@ -138,12 +171,20 @@ func (r *Run) Done() pubsub.BroadcasterReader[struct{}] {
 }
 
 func (r *Run) Clone() *Run {
-	return NewRun(r.Config, r.client, util.Must(uuid.NewV7()).String())
+	return NewRun(r.Config, r.client, util.Must(uuid.NewV7()).String(), r.SessionID, r.parentLogger)
 }
 
 func (r *Run) handleStream() {
 	defer r.cancel()
 	defer r.messages.Close()
+	defer func() {
+		if stats := r.messages.Stats(); stats.Dropped > 0 {
+			r.logger.Warn("MCP messages were not delivered to all subscribers",
+				slog.Uint64("dropped", stats.Dropped),
+				slog.Uint64("overflows", stats.Overflows),
+			)
+		}
+	}()
 	defer r.errors.Close()
 	defer r.output.Close()
 	defer func() {
@@ -173,6 +214,8 @@ func (r *Run) handleStream() {
 			Init: &runnerPb.RunRequestInit{
 				RunConfig:    r.Config,
 				ConnectionId: r.ConnectionID,
+				TraceContext: r.traceContext,
+				SessionId:    r.SessionID,
 			},
 		},
 	})
@@ -214,7 +257,7 @@ loop:
 				break loop
 			}
 
-			log.Printf("Error receiving response: %v\n", err)
+			r.logger.Warn("error receiving response", logging.Err(err))
 			break loop
 		}
 
@@ -245,11 +288,11 @@ loop:
 			go r.Close()
 
 		case *runnerPb.RunResponse_Close:
-			log.Printf("Run %s closed by server\n", r.ConnectionID)
+			r.logger.Info("run closed by server")
 			break loop
 
 		default:
-			log.Printf("Unknown response type: %T\n", msg)
+			r.logger.Warn("unknown response type", slog.String("type", fmt.Sprintf("%T", msg)))
 		}
 	}
 }
@@ -3,7 +3,7 @@ package runner_worker
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 
 	runnerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/runner"
@@ -22,7 +22,7 @@ type RunnerWorker struct {
 
 func NewRunnerWorker(ctx context.Context, manager *workers.WorkerManager, workerID, address string, isStandalone bool) *RunnerWorker {
 	res := &RunnerWorker{
-		BaseWorkerConnection: base_worker.NewBaseWorkerConnection(ctx, workerID, address, isStandalone),
+		BaseWorkerConnection: base_worker.NewBaseWorkerConnection(ctx, manager.Logger(), workerID, address, isStandalone),
 
 		manager: manager,
 		client:  nil,
@@ -32,7 +32,7 @@ func NewRunnerWorker(ctx context.Context, manager *workers.WorkerManager, worker
 }
 
 func (rw *RunnerWorker) Start() error {
-	log.Printf("Starting RunnerWorker %s at %s", rw.WorkerID(), rw.Address())
+	rw.Logger().Info("starting worker", slog.String("worker_type", string(rw.Type())))
 
 	rw.mutex.Lock()
 	defer rw.mutex.Unlock()
@@ -53,7 +53,7 @@ func (rw *RunnerWorker) Start() error {
 }
 
 func (rw *RunnerWorker) Stop() error {
-	log.Printf("Stopping RunnerWorker %s at %s", rw.WorkerID(), rw.Address())
+	rw.Logger().Info("stopping worker", slog.String("worker_type", string(rw.Type())))
 
 	rw.mutex.Lock()
 	defer rw.mutex.Unlock()
@@ -85,7 +85,7 @@ func (rw *RunnerWorker) monitor() {
 
 	rw.BaseWorkerConnection.Wait()
 
-	log.Printf("RunnerWorker %s at %s has stopped", rw.WorkerID(), rw.Address())
+	rw.Logger().Info("worker has stopped", slog.String("worker_type", string(rw.Type())))
 
 	rw.mutex.Lock()
 	defer rw.mutex.Unlock()
@@ -2,6 +2,7 @@ package workers
 
 import (
 	"fmt"
+	"log/slog"
 	"math/rand"
 	"sync"
 
@@ -38,18 +39,26 @@ type WorkerManager struct {
 	workers       map[string]Worker
 	workersByType map[WorkerType][]string
 
+	logger *slog.Logger
+
 	mutex sync.RWMutex
 }
 
-func NewWorkerManager() *WorkerManager {
+func NewWorkerManager(logger *slog.Logger) *WorkerManager {
 	return &WorkerManager{
 		workers:       make(map[string]Worker),
 		workersByType: make(map[WorkerType][]string),
 
+		logger: logger,
+
 		mutex: sync.RWMutex{},
 	}
 }
 
+func (wm *WorkerManager) Logger() *slog.Logger {
+	return wm.logger
+}
+
 func (wm *WorkerManager) RegisterWorker(worker Worker) error {
 	wm.mutex.Lock()
 	defer wm.mutex.Unlock()
@@ -72,6 +81,11 @@ func (wm *WorkerManager) RegisterWorker(worker Worker) error {
 	}
 	wm.workersByType[workerType] = append(wm.workersByType[workerType], worker.WorkerID())
 
+	wm.logger.Info("registered worker",
+		slog.String("worker_id", worker.WorkerID()),
+		slog.String("worker_type", string(workerType)),
+	)
+
 	return nil
 }
 
@@ -238,5 +252,10 @@ func (wm *WorkerManager) SelfUnregisterWorker(workerID string) {
 		}
 	}
 
+	wm.logger.Info("unregistered worker",
+		slog.String("worker_id", workerID),
+		slog.String("worker_type", string(worker.Type())),
+	)
+
 	go worker.Stop()
 }
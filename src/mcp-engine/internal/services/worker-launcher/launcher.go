@@ -1,9 +1,10 @@
 package launcher
 
 import (
-	"log"
+	"log/slog"
 
 	"github.com/google/uuid"
+	workerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/worker"
 	"github.com/metorial/metorial/mcp-engine/internal/services/worker"
 	"github.com/metorial/metorial/modules/util"
 	"google.golang.org/grpc"
@@ -15,6 +16,10 @@ type launcher struct {
 	worker.WorkerImpl
 
 	id string
+
+	logger *slog.Logger
+
+	pool *processPool
 }
 
 func NewLauncher() *launcher {
@@ -24,16 +29,34 @@ func NewLauncher() *launcher {
 }
 
 func (r *launcher) Start(worker *worker.Worker, grpc *grpc.Server) error {
-	launcherPb.RegisterLauncherServer(grpc, &launcherServer{})
+	r.logger = worker.Logger()
+
+	pool, err := newProcessPool(poolOptionsFromEnv(), r.logger)
+	if err != nil {
+		return err
+	}
+	r.pool = pool
+
+	launcherPb.RegisterLauncherServer(grpc, &launcherServer{pool: pool})
 
 	return nil
 }
 
 func (r *launcher) Stop() error {
-	log.Println("Runner stopped successfully")
+	if r.pool != nil {
+		r.pool.close()
+	}
+
+	r.logger.Info("launcher stopped")
 	return nil
 }
 
 func (r *launcher) WorkerId() string {
 	return r.id
 }
+
+func (r *launcher) AddWorkerInfo(info *workerPb.WorkerInfoResponse) {
+	if r.pool != nil {
+		info.LauncherPool = r.pool.stats()
+	}
+}
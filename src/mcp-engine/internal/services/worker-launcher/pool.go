@@ -0,0 +1,457 @@
+package launcher
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	workerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/worker"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
+)
+
+var (
+	errPoolClosed      = errors.New("launcher pool is closed")
+	errLauncherTimeout = errors.New("launcher timed out")
+)
+
+type poolOptions struct {
+	// Size is the maximum number of processes, i.e. of concurrent runs.
+	Size int
+	// MaxRuns is the number of runs after which a process is replaced.
+	MaxRuns int
+	// MaxHeapBytes is the heap size after which a process is replaced.
+	MaxHeapBytes int64
+
+	RunTimeout   time.Duration
+	StartTimeout time.Duration
+}
+
+func defaultPoolOptions() poolOptions {
+	return poolOptions{
+		Size:         4,
+		MaxRuns:      100,
+		MaxHeapBytes: 12 * 1024 * 1024,
+		RunTimeout:   5 * time.Second,
+		StartTimeout: 10 * time.Second,
+	}
+}
+
+// poolOptionsFromEnv returns the default options, overridden by
+// LAUNCHER_POOL_SIZE, LAUNCHER_POOL_MAX_RUNS and LAUNCHER_POOL_MAX_HEAP_MB.
+func poolOptionsFromEnv() poolOptions {
+	options := defaultPoolOptions()
+
+	if value, err := strconv.Atoi(os.Getenv("LAUNCHER_POOL_SIZE")); err == nil && value > 0 {
+		options.Size = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("LAUNCHER_POOL_MAX_RUNS")); err == nil && value > 0 {
+		options.MaxRuns = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("LAUNCHER_POOL_MAX_HEAP_MB")); err == nil && value > 0 {
+		options.MaxHeapBytes = int64(value) * 1024 * 1024
+	}
+
+	return options
+}
+
+type poolRequest struct {
+	ID     uint64 `json:"id"`
+	Code   string `json:"code"`
+	Config string `json:"config"`
+}
+
+type poolResponse struct {
+	ID       uint64          `json:"id"`
+	Type     string          `json:"type"`
+	Data     json.RawMessage `json:"data"`
+	Error    string          `json:"error"`
+	HeapUsed int64           `json:"heap_used"`
+	Tainted  bool            `json:"tainted"`
+}
+
+// processPool keeps long-lived Deno processes that evaluate launcher code,
+// so runs don't pay the startup of a process each.
+type processPool struct {
+	options    poolOptions
+	scriptDir  string
+	scriptPath string
+
+	slots chan struct{}
+
+	logger *slog.Logger
+
+	mutex  sync.Mutex
+	idle   []*denoProcess
+	closed bool
+
+	busy      atomic.Int64
+	totalRuns atomic.Uint64
+	spawned   atomic.Uint64
+	recycled  atomic.Uint64
+	failed    atomic.Uint64
+}
+
+func newProcessPool(options poolOptions, logger *slog.Logger) (*processPool, error) {
+	scriptDir, err := os.MkdirTemp("", "metorial-launcher-pool-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create script dir: %w", err)
+	}
+
+	scriptPath := filepath.Join(scriptDir, "metorial-launcher.js")
+	if err := os.WriteFile(scriptPath, []byte(poolProcessScript), 0644); err != nil {
+		os.RemoveAll(scriptDir)
+		return nil, fmt.Errorf("failed to write launcher script: %w", err)
+	}
+
+	pool := &processPool{
+		options:    options,
+		scriptDir:  scriptDir,
+		scriptPath: scriptPath,
+		slots:      make(chan struct{}, options.Size),
+		logger:     logger,
+	}
+
+	go pool.warm()
+
+	return pool, nil
+}
+
+// warm starts the processes ahead of the first runs.
+func (p *processPool) warm() {
+	for i := 0; i < p.options.Size; i++ {
+		p.mutex.Lock()
+		full := p.closed || len(p.idle)+int(p.busy.Load()) >= p.options.Size
+		p.mutex.Unlock()
+		if full {
+			return
+		}
+
+		process, err := p.spawn()
+		if err != nil {
+			p.logger.Error("failed to start launcher process", logging.Err(err))
+			return
+		}
+
+		p.release(process)
+	}
+}
+
+func (p *processPool) spawn() (*denoProcess, error) {
+	process, err := startDenoProcess(p.scriptPath, p.options.StartTimeout)
+	if err != nil {
+		p.failed.Add(1)
+		return nil, err
+	}
+
+	p.spawned.Add(1)
+	return process, nil
+}
+
+func (p *processPool) acquire(ctx context.Context) (*denoProcess, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		<-p.slots
+		return nil, errPoolClosed
+	}
+
+	for len(p.idle) > 0 {
+		process := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+
+		if process.alive() {
+			p.mutex.Unlock()
+			return process, nil
+		}
+
+		// The process exited while it was idle, e.g. it was killed.
+		p.failed.Add(1)
+	}
+	p.mutex.Unlock()
+
+	process, err := p.spawn()
+	if err != nil {
+		<-p.slots
+		return nil, err
+	}
+
+	return process, nil
+}
+
+// release returns a process to the pool, or stops it if the pool is
+// closed or full.
+func (p *processPool) release(process *denoProcess) {
+	p.mutex.Lock()
+	if !p.closed && len(p.idle) < p.options.Size {
+		p.idle = append(p.idle, process)
+		p.mutex.Unlock()
+		return
+	}
+	p.mutex.Unlock()
+
+	process.kill()
+}
+
+// run evaluates launcher code in a pooled process. Errors of the launcher
+// itself are part of the response, the error is only set if the launcher
+// couldn't be run.
+func (p *processPool) run(ctx context.Context, code, jsonConfig string) (*poolResponse, error) {
+	process, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.busy.Add(1)
+	p.totalRuns.Add(1)
+
+	response, err := process.run(ctx, code, jsonConfig, p.options.RunTimeout)
+
+	p.busy.Add(-1)
+
+	switch {
+	case err != nil:
+		p.failed.Add(1)
+		process.kill()
+
+	case response.Tainted || process.runs >= p.options.MaxRuns || response.HeapUsed > p.options.MaxHeapBytes:
+		p.recycled.Add(1)
+		process.kill()
+
+	default:
+		p.release(process)
+	}
+
+	<-p.slots
+
+	return response, err
+}
+
+func (p *processPool) stats() *workerPb.LauncherPoolStats {
+	p.mutex.Lock()
+	idle := len(p.idle)
+	p.mutex.Unlock()
+
+	return &workerPb.LauncherPoolStats{
+		Size:      uint32(p.options.Size),
+		Idle:      uint32(idle),
+		Busy:      uint32(p.busy.Load()),
+		TotalRuns: p.totalRuns.Load(),
+		Spawned:   p.spawned.Load(),
+		Recycled:  p.recycled.Load(),
+		Failed:    p.failed.Load(),
+	}
+}
+
+func (p *processPool) close() {
+	p.mutex.Lock()
+	idle := p.idle
+	p.idle = nil
+	p.closed = true
+	p.mutex.Unlock()
+
+	for _, process := range idle {
+		process.kill()
+	}
+
+	os.RemoveAll(p.scriptDir)
+}
+
+type denoProcess struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	lines  chan []byte
+	stderr *tailBuffer
+
+	exited   chan struct{}
+	killed   chan struct{}
+	killOnce sync.Once
+
+	scriptPath string
+	runs       int
+	nextID     uint64
+}
+
+func startDenoProcess(scriptPath string, startTimeout time.Duration) (*denoProcess, error) {
+	cmd := exec.Command("deno", "run",
+		"--v8-flags=--max-old-space-size=20",
+		fmt.Sprintf("--allow-read=%s", scriptPath),
+		"--deny-write",
+		"--deny-env",
+		"--deny-sys",
+		"--deny-net",
+		"--deny-run",
+		"--deny-ffi",
+		scriptPath)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	process := &denoProcess{
+		cmd:        cmd,
+		stdin:      stdin,
+		lines:      make(chan []byte),
+		stderr:     &tailBuffer{limit: 4096},
+		exited:     make(chan struct{}),
+		killed:     make(chan struct{}),
+		scriptPath: scriptPath,
+	}
+	cmd.Stderr = process.stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start deno: %w", err)
+	}
+
+	go process.readLines(stdout)
+
+	timer := time.NewTimer(startTimeout)
+	defer timer.Stop()
+
+	select {
+	case line, ok := <-process.lines:
+		if !ok {
+			return nil, fmt.Errorf("launcher process exited on startup: %s", process.stderr.String())
+		}
+
+		var ready poolResponse
+		if err := json.Unmarshal(line, &ready); err != nil || ready.Type != "ready" {
+			process.kill()
+			return nil, fmt.Errorf("unexpected output from launcher process: %s", line)
+		}
+
+	case <-timer.C:
+		process.kill()
+		return nil, fmt.Errorf("launcher process didn't start within %s", startTimeout)
+	}
+
+	return process, nil
+}
+
+// readLines forwards the lines written by the process until it exits or
+// is killed.
+func (d *denoProcess) readLines(stdout io.Reader) {
+	defer close(d.exited)
+	defer close(d.lines)
+
+	reader := bufio.NewReaderSize(stdout, 64*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			d.cmd.Wait()
+			return
+		}
+
+		select {
+		case d.lines <- line:
+		case <-d.killed:
+		}
+	}
+}
+
+func (d *denoProcess) alive() bool {
+	select {
+	case <-d.exited:
+		return false
+	default:
+		return true
+	}
+}
+
+func (d *denoProcess) run(ctx context.Context, code, jsonConfig string, timeout time.Duration) (*poolResponse, error) {
+	d.runs++
+	d.nextID++
+
+	request, err := json.Marshal(poolRequest{ID: d.nextID, Code: code, Config: jsonConfig})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := d.stdin.Write(append(request, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to send launcher to process: %w", err)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case line, ok := <-d.lines:
+		if !ok {
+			return nil, fmt.Errorf("launcher process exited: %s", d.stderr.String())
+		}
+
+		var response poolResponse
+		if err := json.Unmarshal(line, &response); err != nil {
+			return nil, fmt.Errorf("failed to parse output from launcher process: %s", line)
+		}
+		if response.ID != d.nextID {
+			return nil, fmt.Errorf("unexpected response from launcher process: %s", line)
+		}
+
+		response.Error = strings.ReplaceAll(response.Error, d.scriptPath, "metorial-launcher.js")
+
+		return &response, nil
+
+	case <-timer.C:
+		return nil, fmt.Errorf("%w after %s", errLauncherTimeout, timeout)
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (d *denoProcess) kill() {
+	d.killOnce.Do(func() {
+		close(d.killed)
+		d.stdin.Close()
+		d.cmd.Process.Kill()
+	})
+}
+
+// tailBuffer keeps the last bytes written to it.
+type tailBuffer struct {
+	mutex sync.Mutex
+	limit int
+	data  []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.data = append(b.data, p...)
+	if len(b.data) > b.limit {
+		b.data = b.data[len(b.data)-b.limit:]
+	}
+
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return strings.TrimSpace(string(b.data))
+}
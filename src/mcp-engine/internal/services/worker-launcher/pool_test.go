@@ -0,0 +1,83 @@
+package launcher
+
+import (
+	"context"
+	"log/slog"
+	"os/exec"
+	"testing"
+)
+
+func newTestPool(t *testing.T) *processPool {
+	t.Helper()
+
+	if _, err := exec.LookPath("deno"); err != nil {
+		t.Skip("deno is not installed")
+	}
+
+	options := defaultPoolOptions()
+	options.Size = 1
+
+	pool, err := newProcessPool(options, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.close)
+
+	return pool
+}
+
+func TestPool_LauncherCantReachScriptBindings(t *testing.T) {
+	pool := newTestPool(t)
+
+	response, err := pool.run(context.Background(), `() => [typeof snapshot, typeof isTainted, typeof write, typeof parse, typeof launcherContext].join()`, `{}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Type != "success" || string(response.Data) != `"undefined,undefined,undefined,undefined,undefined"` {
+		t.Fatalf("expected the script's bindings to be out of reach, got %s %s %s", response.Type, response.Data, response.Error)
+	}
+	if response.Tainted {
+		t.Error("expected the process not to be tainted")
+	}
+}
+
+func TestPool_PoisonedProcessIsReplaced(t *testing.T) {
+	pool := newTestPool(t)
+
+	attempts := map[string]string{
+		"clear snapshot":   `() => { snapshot.length = 0; JSON.parse = (s) => s; return 1 }`,
+		"replace builtin":  `() => { JSON.parse = (s) => s; return 1 }`,
+		"add global":       `() => { globalThis.steal = (config) => config; return 1 }`,
+		"patch prototype":  `() => { Object.prototype.toJSON = function () { return this }; return 1 }`,
+		"replace function": `() => { Array.prototype.map = () => []; return 1 }`,
+	}
+
+	for name, code := range attempts {
+		spawned := pool.spawned.Load()
+
+		response, err := pool.run(context.Background(), code, `{}`)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if name == "clear snapshot" {
+			if response.Type != "error" {
+				t.Errorf("%s: expected the snapshot to be out of reach, got %s", name, response.Data)
+			}
+			continue
+		}
+
+		if !response.Tainted {
+			t.Errorf("%s: expected the process to be tainted", name)
+		}
+
+		// The next run gets a fresh process
+		if _, err := pool.run(context.Background(), `() => 1`, `{}`); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if pool.spawned.Load() == spawned {
+			t.Errorf("%s: expected the process to be replaced", name)
+		}
+	}
+}
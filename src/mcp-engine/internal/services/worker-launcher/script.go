@@ -0,0 +1,202 @@
+package launcher
+
+import "github.com/metorial/metorial/mcp-engine/pkg/jsLauncher"
+
+// poolProcessScript is run by the pooled Deno processes. It reads one
+// JSON request per line from stdin and writes one JSON response per line
+// to stdout:
+//
+//	-> {"id": 1, "code": "...", "config": "{...}"}
+//	<- {"id": 1, "type": "success", "data": ..., "heap_used": 123, "tainted": false}
+//	<- {"id": 1, "type": "error", "error": "...", "heap_used": 123, "tainted": false}
+//
+// A {"type": "ready"} line is written once the process accepts requests.
+// Launchers of different sessions share a process, so they are evaluated
+// in the global scope, out of reach of the script's own bindings, and
+// after every run the script checks that the launcher didn't modify the
+// globals or builtins, which could leak the config of later runs. If it
+// did, the response is marked as tainted and the process is replaced.
+const poolProcessScript = jsLauncher.ContextScript + `
+// Builtins used after launchers ran are captured first, so launchers
+// can't change how the result is reported or checked.
+const globalEval = eval;
+const stringify = JSON.stringify;
+const parse = JSON.parse;
+const getOwnPropertyDescriptor = Object.getOwnPropertyDescriptor;
+const getPrototypeOf = Object.getPrototypeOf;
+const hasOwn = Object.hasOwn;
+const createObject = Object.create;
+const is = Object.is;
+const ownKeys = Reflect.ownKeys;
+const apply = Reflect.apply;
+const encode = TextEncoder.prototype.encode;
+const subarray = Uint8Array.prototype.subarray;
+const toString = String;
+const encoder = new TextEncoder();
+const decoder = new TextDecoder();
+const stdout = Deno.stdout;
+const writeSync = stdout.writeSync;
+const memoryUsage = Deno.memoryUsage;
+
+const write = (line) => {
+  const data = apply(encode, encoder, [line + '\n']);
+  let written = 0;
+  while (written < data.length) {
+    written += apply(writeSync, stdout, [apply(subarray, data, [written])]);
+  }
+};
+
+const heapUsed = () => {
+  try {
+    return memoryUsage().heapUsed;
+  } catch {
+    return 0;
+  }
+};
+
+// Output of launchers is not part of the result, it would corrupt the
+// protocol otherwise.
+for (const method of ['log', 'info', 'warn', 'error', 'debug', 'trace', 'dir', 'dirxml', 'table']) {
+  console[method] = () => {};
+}
+
+// Touch every global once so lazily initialized ones are settled before
+// the snapshot is taken.
+for (const key of ownKeys(globalThis)) {
+  try {
+    globalThis[key];
+  } catch {}
+}
+
+const snapshotObjects = () => {
+  const snapshot = [];
+  const seen = new Set();
+  const pending = [[globalThis, 0]];
+
+  while (pending.length > 0 && snapshot.length < 10000) {
+    const [value, depth] = pending.pop();
+    if ((typeof value !== 'object' && typeof value !== 'function') || value === null || seen.has(value)) {
+      continue;
+    }
+    seen.add(value);
+
+    const properties = createObject(null);
+    for (const key of ownKeys(value)) {
+      const descriptor = getOwnPropertyDescriptor(value, key);
+      properties[key] = descriptor;
+
+      if (depth < 4 && hasOwn(descriptor, 'value')) {
+        pending.push([descriptor.value, depth + 1]);
+      }
+    }
+
+    const prototype = getPrototypeOf(value);
+    snapshot.push({ value, prototype, properties });
+    pending.push([prototype, depth + 1]);
+  }
+
+  return snapshot;
+};
+
+const isReference = (descriptor) => {
+  if (!hasOwn(descriptor, 'value')) return true;
+  return (typeof descriptor.value === 'object' && descriptor.value !== null) || typeof descriptor.value === 'function';
+};
+
+// Only functions, objects and accessors matter: a launcher needs them to
+// run code or keep data during later runs. Primitive values change with
+// internal state, e.g. of the stdin stream. Plain loops are used, as
+// iterators may have been replaced.
+const isUnchanged = (snapshot) => {
+  for (let i = 0; i < snapshot.length; i++) {
+    const { value, prototype, properties } = snapshot[i];
+    if (getPrototypeOf(value) !== prototype) return false;
+
+    const keys = ownKeys(value);
+    for (let j = 0; j < keys.length; j++) {
+      const before = properties[keys[j]];
+      const after = getOwnPropertyDescriptor(value, keys[j]);
+
+      if (!before) {
+        if (isReference(after)) return false;
+        continue;
+      }
+
+      if (!isReference(before) && !isReference(after)) continue;
+      if (!is(before.value, after.value) || before.get !== after.get || before.set !== after.set) return false;
+    }
+  }
+
+  return true;
+};
+
+// The snapshot is only reachable through this closure.
+const isTainted = (() => {
+  const snapshot = snapshotObjects();
+  return () => !isUnchanged(snapshot);
+})();
+
+const run = (request) => {
+  let config = parse(request.config);
+
+  // Not called as eval, so this is an indirect eval in the global scope
+  let launcher = globalEval(request.code);
+
+  let sanitizedConfig = { ...config };
+  delete sanitizedConfig.__metorial_oauth__;
+
+  return typeof launcher == 'function' ?
+    launcher(sanitizedConfig, launcherContext(config)) :
+    launcher;
+};
+
+const describeError = (e) => {
+  try {
+    return toString((e && e.stack) || e);
+  } catch {
+    return 'Unknown error';
+  }
+};
+
+// Responses are assembled from strings, as the prototypes used by
+// JSON.stringify for objects may have been changed by the launcher.
+const handle = (line) => {
+  let request;
+  try {
+    request = parse(line);
+  } catch (e) {
+    write('{"type":"error","error":' + stringify('Invalid request: ' + describeError(e)) + '}');
+    return;
+  }
+
+  const id = stringify(typeof request.id === 'number' ? request.id : 0);
+
+  let result;
+  try {
+    // Serialize inside the try, so outputs that can't be serialized are
+    // reported as launcher errors.
+    const output = stringify(run(request));
+    result = '"type":"success","data":' + (typeof output === 'string' ? output : 'null');
+  } catch (e) {
+    result = '"type":"error","error":' + stringify(describeError(e));
+  }
+
+  const tainted = isTainted() ? 'true' : 'false';
+  write('{"id":' + id + ',' + result + ',"heap_used":' + stringify(heapUsed()) + ',"tainted":' + tainted + '}');
+};
+
+write('{"type":"ready"}');
+
+let buffer = '';
+for await (const chunk of Deno.stdin.readable) {
+  buffer += decoder.decode(chunk, { stream: true });
+
+  let index;
+  while ((index = buffer.indexOf('\n')) >= 0) {
+    const line = buffer.slice(0, index);
+    buffer = buffer.slice(index + 1);
+
+    if (line.trim() !== '') handle(line);
+  }
+}
+`
@@ -8,8 +8,10 @@ import (
 
 type launcherServer struct {
 	launcherPb.UnimplementedLauncherServer
+
+	pool *processPool
 }
 
 func (l *launcherServer) RunLauncher(ctx context.Context, req *launcherPb.RunLauncherRequest) (*launcherPb.RunLauncherResponse, error) {
-	return runLaunchParamsFunction(req.Config)
+	return runLaunchParamsFunction(ctx, l.pool, req.Config)
 }
@@ -12,7 +12,7 @@ type MessageReceiver func(resp *remotePb.RunResponse)
 type Connection interface {
 	Close() error
 	Context() context.Context
-	Send(msg *mcpPb.McpMessageRaw) error
+	Send(ctx context.Context, msg *mcpPb.McpMessageRaw) error
 	SendControl(msg string) error
 	Subscribe(cb MessageReceiver)
 	Done() <-chan struct{}
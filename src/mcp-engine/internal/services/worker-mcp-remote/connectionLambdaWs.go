@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"path"
@@ -15,7 +15,9 @@ import (
 	"github.com/gorilla/websocket"
 	mcpPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/mcp"
 	remotePb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/remote"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
+	ssrfProtection "github.com/metorial/metorial/modules/ssrf-protection"
 	"github.com/metorial/metorial/modules/util"
 )
 
@@ -72,10 +74,12 @@ type ConnectionLambdaWs struct {
 
 	client *remotePb.RunConfigLambdaClient
 
+	logger *slog.Logger
+
 	mutex sync.Mutex
 }
 
-func NewConnectionLambdaWs(ctx context.Context, client *remotePb.RunConfigLambdaClient, config *remotePb.RunConfigLambda) (*ConnectionLambdaWs, error) {
+func NewConnectionLambdaWs(ctx context.Context, logger *slog.Logger, client *remotePb.RunConfigLambdaClient, config *remotePb.RunConfigLambda) (*ConnectionLambdaWs, error) {
 	ctx, cancel := context.WithCancelCause(ctx)
 
 	res := &ConnectionLambdaWs{
@@ -86,6 +90,8 @@ func NewConnectionLambdaWs(ctx context.Context, client *remotePb.RunConfigLambda
 
 		config: config,
 		client: client,
+
+		logger: logger,
 	}
 
 	if res.context.Err() != nil {
@@ -114,7 +120,7 @@ func (c *ConnectionLambdaWs) ensureConnection() (*websocket.Conn, error) {
 	}
 	u.Path = path.Join(u.Path, "mcp")
 
-	fmt.Println("Connecting to WebSocket server at", u.String())
+	c.logger.Info("connecting to lambda websocket", slog.String("url", u.String()))
 
 	headers := http.Header{}
 	headers.Set("User-Agent", "Metorial MCP Engine (https://metorial.com)")
@@ -122,7 +128,14 @@ func (c *ConnectionLambdaWs) ensureConnection() (*websocket.Conn, error) {
 	headers.Set("Metorial-Stellar-Client", c.client.Participant.ParticipantJson)
 	headers.Set("Metorial-Stellar-Arguments", c.config.Arguments.JsonArguments)
 
-	conn, _, err := websocket.DefaultDialer.Dial(u.String(), headers)
+	// Connect through the SSRF protected dialer, which checks the IPs the
+	// host resolves to and connects to one of those, without a proxy.
+	dialer := &websocket.Dialer{
+		NetDialContext:   ssrfProtection.NewDialer().DialContext,
+		HandshakeTimeout: 45 * time.Second,
+	}
+
+	conn, _, err := dialer.Dial(u.String(), headers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to WebSocket server: %w", err)
 	}
@@ -133,14 +146,14 @@ func (c *ConnectionLambdaWs) ensureConnection() (*websocket.Conn, error) {
 		for {
 			_, message, err := conn.ReadMessage()
 			if err != nil {
-				log.Println("lambda ws read error:", err)
+				c.logger.Warn("lambda ws read error", logging.Err(err))
 				return
 			}
 
 			// First unmarshal into BaseMessage to check type
 			var base BaseMessage
 			if err := json.Unmarshal(message, &base); err != nil {
-				log.Println("lambda ws unmarshal error:", err)
+				c.logger.Warn("lambda ws unmarshal error", logging.Err(err))
 				continue
 			}
 
@@ -148,7 +161,7 @@ func (c *ConnectionLambdaWs) ensureConnection() (*websocket.Conn, error) {
 			case "logs":
 				var logsMsg LogsMessage
 				if err := json.Unmarshal(message, &logsMsg); err != nil {
-					log.Println("lambda ws unmarshal logs error:", err)
+					c.logger.Warn("lambda ws unmarshal logs error", logging.Err(err))
 					continue
 				}
 
@@ -173,7 +186,7 @@ func (c *ConnectionLambdaWs) ensureConnection() (*websocket.Conn, error) {
 			case "mcp.message":
 				var mcpMsg McpMessageResponse
 				if err := json.Unmarshal(message, &mcpMsg); err != nil {
-					log.Println("lambda ws unmarshal mcp message error:", err)
+					c.logger.Warn("lambda ws unmarshal mcp message error", logging.Err(err))
 					continue
 				}
 
@@ -202,7 +215,7 @@ func (c *ConnectionLambdaWs) ensureConnection() (*websocket.Conn, error) {
 			case "error":
 				var errMsg ErrorMessage
 				if err := json.Unmarshal(message, &errMsg); err != nil {
-					log.Println("lambda ws unmarshal error message error:", err)
+					c.logger.Warn("lambda ws unmarshal error message error", logging.Err(err))
 					continue
 				}
 
@@ -221,7 +234,7 @@ func (c *ConnectionLambdaWs) ensureConnection() (*websocket.Conn, error) {
 				}
 
 			default:
-				log.Printf("lambda ws unknown message type: %s\n", base.Type)
+				c.logger.Warn("lambda ws unknown message type", slog.String("type", base.Type))
 			}
 		}
 	}()
@@ -258,7 +271,7 @@ func (c *ConnectionLambdaWs) ensureConnection() (*websocket.Conn, error) {
 	return conn, nil
 }
 
-func (c *ConnectionLambdaWs) Send(msg *mcpPb.McpMessageRaw) error {
+func (c *ConnectionLambdaWs) Send(ctx context.Context, msg *mcpPb.McpMessageRaw) error {
 	return c.SendMcp(msg.Message)
 }
 
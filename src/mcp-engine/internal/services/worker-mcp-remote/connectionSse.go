@@ -14,6 +14,7 @@ import (
 	mcpPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/mcp"
 	remotePb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/remote"
 	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
+	"github.com/metorial/metorial/mcp-engine/pkg/tracing"
 	"github.com/metorial/metorial/modules/addr"
 	ssrfProtection "github.com/metorial/metorial/modules/ssrf-protection"
 	"github.com/metorial/metorial/modules/util"
@@ -215,15 +216,15 @@ func (c *ConnectionSSE) getEndpointUrl(endpoint string) string {
 	return uri
 }
 
-func (c *ConnectionSSE) Send(msg *mcpPb.McpMessageRaw) error {
-	return c.SendString(msg.Message)
+func (c *ConnectionSSE) Send(ctx context.Context, msg *mcpPb.McpMessageRaw) error {
+	return c.SendString(ctx, msg.Message)
 }
 
 func (c *ConnectionSSE) SendControl(msg string) error {
-	return c.SendString(msg)
+	return c.SendString(context.Background(), msg)
 }
 
-func (c *ConnectionSSE) SendString(msg string) error {
+func (c *ConnectionSSE) SendString(ctx context.Context, msg string) error {
 	c.wg.Wait()
 
 	if c.conn == nil {
@@ -248,6 +249,7 @@ func (c *ConnectionSSE) SendString(msg string) error {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "*/*")
 	req.Header.Set("User-Agent", "Metorial MCP Engine (https://metorial.com)")
+	tracing.InjectHeaders(ctx, req.Header)
 
 	if c.config.Arguments.Headers != nil {
 		for k, v := range c.config.Arguments.Headers {
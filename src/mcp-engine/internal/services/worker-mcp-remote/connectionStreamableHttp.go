@@ -14,6 +14,7 @@ import (
 	mcpPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/mcp"
 	remotePb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/remote"
 	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
+	"github.com/metorial/metorial/mcp-engine/pkg/tracing"
 	ssrfProtection "github.com/metorial/metorial/modules/ssrf-protection"
 	"github.com/metorial/metorial/modules/util"
 )
@@ -108,15 +109,15 @@ func (c *ConnectionStreamableHTTP) createRequest(ctx context.Context, method str
 	return req, nil
 }
 
-func (c *ConnectionStreamableHTTP) Send(msg *mcpPb.McpMessageRaw) error {
-	return c.SendString(msg.Message)
+func (c *ConnectionStreamableHTTP) Send(ctx context.Context, msg *mcpPb.McpMessageRaw) error {
+	return c.SendString(ctx, msg.Message)
 }
 
 func (c *ConnectionStreamableHTTP) SendControl(msg string) error {
-	return c.SendString(msg)
+	return c.SendString(context.Background(), msg)
 }
 
-func (c *ConnectionStreamableHTTP) SendString(msg string) error {
+func (c *ConnectionStreamableHTTP) SendString(ctx context.Context, msg string) error {
 	c.sendMutex.Lock()
 	defer c.sendMutex.Unlock()
 
@@ -133,6 +134,7 @@ func (c *ConnectionStreamableHTTP) SendString(msg string) error {
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json, text/event-stream")
+	tracing.InjectHeaders(ctx, req.Header)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -1,10 +1,11 @@
 package remote
 
 import (
-	"log"
+	"log/slog"
 
 	"github.com/google/uuid"
 	"github.com/metorial/metorial/mcp-engine/internal/services/worker"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/modules/util"
 	"google.golang.org/grpc"
 
@@ -30,7 +31,7 @@ func (r *remote) Start(worker *worker.Worker, grpc *grpc.Server) error {
 }
 
 func (r *remote) Stop() error {
-	log.Println("Runner stopped successfully")
+	slog.Info("remote stopped", logging.WorkerId(r.id))
 	return nil
 }
 
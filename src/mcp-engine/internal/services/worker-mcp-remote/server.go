@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +14,11 @@ import (
 	remotePb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/remote"
 	workerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/worker"
 	"github.com/metorial/metorial/mcp-engine/internal/services/worker"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
+	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
+	"github.com/metorial/metorial/mcp-engine/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 )
 
@@ -66,37 +71,56 @@ func (r *remoteServer) StreamMcpRun(stream grpc.BidiStreamingServer[remotePb.Run
 		return fmt.Errorf("expected McpInit request, got %T", req.Type)
 	}
 
+	_, startSpan := tracing.Start(
+		tracing.ExtractMap(stream.Context(), msg.Init.TraceContext),
+		"mcp_remote.Connect",
+		trace.WithAttributes(attribute.String("connection_id", msg.Init.ConnectionId)),
+	)
+
+	logger := slog.Default().With(
+		logging.WorkerId(r.remote.WorkerId()),
+		logging.SessionId(msg.Init.SessionId),
+		logging.RunId(msg.Init.ConnectionId),
+	)
+
 	var conn Connection
 	switch msg.Init.RunConfig.Config.(type) {
 	case *remotePb.RunConfig_RemoteRunConfig:
 		switch msg.Init.RunConfig.GetRemoteRunConfig().Server.Protocol {
 		case remotePb.RunConfigRemoteServer_sse:
-			fmt.Printf("Creating SSE connection to %s\n", msg.Init.RunConfig.GetRemoteRunConfig().Server.ServerUri)
+			logger.Info("creating sse connection", slog.String("server_uri", msg.Init.RunConfig.GetRemoteRunConfig().Server.ServerUri))
 			conn, err = NewConnectionSSE(stream.Context(), msg.Init.RunConfig.GetRemoteRunConfig())
 			if err != nil {
-				log.Printf("Failed to create SSE connection: %v", err)
-				return err
+				logger.Error("failed to create sse connection", logging.Err(err))
 			}
 		case remotePb.RunConfigRemoteServer_streamable_http:
-			fmt.Printf("Creating Streamable HTTP connection to %s\n", msg.Init.RunConfig.GetRemoteRunConfig().Server.ServerUri)
+			logger.Info("creating streamable http connection", slog.String("server_uri", msg.Init.RunConfig.GetRemoteRunConfig().Server.ServerUri))
 			conn, err = NewConnectionStreamableHTTP(stream.Context(), msg.Init.RunConfig.GetRemoteRunConfig())
 			if err != nil {
-				log.Printf("Failed to create Streamable HTTP connection: %v", err)
-				return err
+				logger.Error("failed to create streamable http connection", logging.Err(err))
 			}
 		default:
-			return fmt.Errorf("unsupported remote server protocol: %v", msg.Init.RunConfig.GetRemoteRunConfig().Server.Protocol)
+			err = fmt.Errorf("unsupported remote server protocol: %v", msg.Init.RunConfig.GetRemoteRunConfig().Server.Protocol)
 		}
 	case *remotePb.RunConfig_LambdaRunConfig:
-		conn, err = NewConnectionLambdaWs(stream.Context(), msg.Init.Client, msg.Init.RunConfig.GetLambdaRunConfig())
+		conn, err = NewConnectionLambdaWs(stream.Context(), logger, msg.Init.Client, msg.Init.RunConfig.GetLambdaRunConfig())
 		if err != nil {
-			log.Printf("Failed to create SSE connection: %v", err)
-			return err
+			logger.Error("failed to create lambda connection", logging.Err(err))
 		}
 	default:
-		return fmt.Errorf("unsupported run config type: %T", msg.Init.RunConfig.Config)
+		err = fmt.Errorf("unsupported run config type: %T", msg.Init.RunConfig.Config)
+	}
+	tracing.RecordError(startSpan, err)
+	startSpan.End()
+	if err != nil {
+		return err
 	}
 
+	// Spans for requests forwarded to the remote server, ended
+	// once the matching response comes back.
+	requestSpans := tracing.NewSpanTracker()
+	defer requestSpans.EndAll()
+
 	lastPing := time.Now()
 
 	go func() {
@@ -158,16 +182,21 @@ func (r *remoteServer) StreamMcpRun(stream grpc.BidiStreamingServer[remotePb.Run
 
 				return // Ignore ping requests
 			}
+
+			if message.McpMessage.Message.MessageType == mcpPb.McpMessageType_response ||
+				message.McpMessage.Message.MessageType == mcpPb.McpMessageType_error {
+				requestSpans.End(message.McpMessage.Message.IdString)
+			}
 		}
 
 		err := stream.Send(response)
 		if err != nil {
 			if stream.Context().Err() != nil {
-				log.Printf("Stream context error: %v", stream.Context().Err())
+				logger.Debug("stream context closed", logging.Err(stream.Context().Err()))
 				return // Client has closed the stream
 			}
 
-			log.Printf("Failed to send response: %v", err)
+			logger.Warn("failed to send response", logging.Err(err))
 			return
 		}
 	})
@@ -199,7 +228,7 @@ func (r *remoteServer) StreamMcpRun(stream grpc.BidiStreamingServer[remotePb.Run
 
 				sentry.CaptureException(err)
 				errChan <- fmt.Errorf("failed to receive request: %w", err)
-				log.Printf("Failed to receive request: %v", err)
+				logger.Warn("failed to receive request", logging.Err(err))
 
 				return
 			}
@@ -207,19 +236,19 @@ func (r *remoteServer) StreamMcpRun(stream grpc.BidiStreamingServer[remotePb.Run
 			switch msg := req.Type.(type) {
 
 			case *remotePb.RunRequest_McpMessage:
-				err = conn.Send(msg.McpMessage.Message)
+				err = sendMessage(conn, requestSpans, msg.McpMessage)
 				if err != nil {
 					sentry.CaptureException(err)
-					log.Printf("Failed to send message: %v", err)
+					logger.Warn("failed to send message", logging.Err(err))
 				}
 
 			case *remotePb.RunRequest_Close:
-				log.Printf("Manager requested to close the connection\n")
+				logger.Info("manager requested to close the connection")
 
 				err := conn.Close()
 				if err != nil {
 					sentry.CaptureException(err)
-					log.Printf("Failed to close connection: %v", err)
+					logger.Warn("failed to close connection", logging.Err(err))
 				}
 
 				err = stream.Send(&remotePb.RunResponse{
@@ -229,13 +258,13 @@ func (r *remoteServer) StreamMcpRun(stream grpc.BidiStreamingServer[remotePb.Run
 				})
 				if err != nil {
 					sentry.CaptureException(err)
-					log.Printf("Failed to send close response: %v", err)
+					logger.Warn("failed to send close response", logging.Err(err))
 				}
 
 				return
 
 			default:
-				log.Printf("Unknown request type: %T", msg)
+				logger.Warn("unknown request type", slog.String("type", fmt.Sprintf("%T", msg)))
 				return
 			}
 		}
@@ -244,7 +273,7 @@ func (r *remoteServer) StreamMcpRun(stream grpc.BidiStreamingServer[remotePb.Run
 	select {
 	case err := <-errChan:
 		if err != nil {
-			log.Printf("Remote server error: %v", err)
+			logger.Error("remote server error", logging.Err(err))
 			conn.Close()
 			break
 		}
@@ -273,3 +302,26 @@ func (r *remoteServer) StreamMcpRun(stream grpc.BidiStreamingServer[remotePb.Run
 
 	return nil
 }
+
+func sendMessage(conn Connection, requestSpans *tracing.SpanTracker, msg *remotePb.RunRequestMcpMessage) error {
+	ctx := tracing.ExtractMap(conn.Context(), msg.TraceContext)
+
+	message, err := mcp.FromPbRawMessage(msg.Message)
+	if err != nil {
+		// Not parsable, so there is nothing to trace or annotate
+		return conn.Send(ctx, msg.Message)
+	}
+
+	ctx, span := tracing.StartMessageSpan(ctx, "mcp_remote.Send", message)
+
+	err = conn.Send(ctx, tracing.InjectMcpMeta(ctx, message).ToPbRawMessage())
+	tracing.RecordError(span, err)
+
+	if err == nil && message.MsgType == mcp.RequestType {
+		requestSpans.Track(message.GetStringId(), span)
+	} else {
+		span.End()
+	}
+
+	return err
+}
@@ -1,17 +1,22 @@
 package worker_mcp_runner
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/metorial/metorial/mcp-engine/pkg/docker"
 	lineBuffer "github.com/metorial/metorial/mcp-engine/pkg/lineBuffer"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
+	"github.com/metorial/metorial/mcp-engine/pkg/tracing"
 	"github.com/metorial/metorial/modules/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Run struct {
@@ -21,10 +26,13 @@ type Run struct {
 	state            *RunnerState
 	StartTime        time.Time
 	LastServerAction time.Time
+
+	logger *slog.Logger
 }
 
 type RunInit struct {
 	ID                 string
+	SessionID          string
 	DockerImage        string
 	ContainerEnv       map[string]string
 	ContainerArgs      []string
@@ -43,7 +51,9 @@ const (
 type OutputHandler func(outputType OutputType, line string)
 type MultiOutputHandler func(outputType OutputType, line []string)
 
-func newRun(state *RunnerState, init *RunInit) (*Run, error) {
+func newRun(ctx context.Context, state *RunnerState, init *RunInit) (*Run, error) {
+	logger := state.logger.With(logging.SessionId(init.SessionID), logging.RunId(init.ID))
+
 	config := &docker.ContainerStartOptions{
 		ID:        init.ID,
 		ImageRef:  init.DockerImage,
@@ -52,9 +62,15 @@ func newRun(state *RunnerState, init *RunInit) (*Run, error) {
 		Command:   init.ContainerCommand,
 		MaxMemory: init.ContainerMaxMemory,
 		MaxCPU:    init.ContainerMaxCPU,
+		Logger:    logger,
 	}
 
+	_, span := tracing.Start(ctx, "docker.StartContainer", trace.WithAttributes(
+		attribute.String("docker_image", init.DockerImage),
+	))
 	container, err := state.dockerManager.StartContainer(config)
+	tracing.RecordError(span, err)
+	span.End()
 	if err != nil {
 		return nil, err
 	}
@@ -67,6 +83,7 @@ func newRun(state *RunnerState, init *RunInit) (*Run, error) {
 
 		container: container,
 		state:     state,
+		logger:    logger,
 	}
 
 	go run.monitor()
@@ -91,7 +108,7 @@ func (m *Run) pingRoutine() {
 			// Send a ping message
 			pingMessage := fmt.Sprintf(`{"jsonrpc": "2.0", "id": "mtr/ping/%d", "method": "ping"}`, time.Now().UnixMicro())
 			if err := m.input(pingMessage + "\n"); err != nil {
-				log.Printf("Failed to send ping: %v\n", err)
+				m.logger.Warn("failed to send ping", logging.Err(err))
 			}
 
 		case <-m.container.Done():
@@ -175,13 +192,13 @@ func (m *Run) HandleOutput(messageHandler McpMessageHandler, outputHandler Multi
 						"result":  map[string]any{},
 					})
 					if err != nil {
-						log.Printf("Failed to marshal ping response: %v\n", err)
+						m.logger.Warn("failed to marshal ping response", logging.Err(err))
 						return
 					}
 
 					err = m.input(string(resp) + "\n")
 					if err != nil {
-						log.Printf("Failed to send ping response: %v\n", err)
+						m.logger.Warn("failed to send ping response", logging.Err(err))
 						return
 					}
 
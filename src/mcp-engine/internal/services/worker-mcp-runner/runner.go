@@ -2,7 +2,7 @@ package worker_mcp_runner
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/metorial/metorial/mcp-engine/internal/services/worker"
@@ -24,8 +24,7 @@ func NewRunner(ctx context.Context, dockerManager *docker.DockerManager) *runner
 	state := newRunnerState(dockerManager, ctx.Done())
 	state.startPrintStateRoutine(time.Second * 60 * 5)
 
-	log.Println("Runner ID:", state.RunnerID)
-	log.Println("Start Time:", state.StartTime)
+	state.logger.Info("runner started", slog.Time("start_time", state.StartTime))
 
 	return &runner{
 		state: state,
@@ -41,7 +40,7 @@ func (r *runner) Start(worker *worker.Worker, grpc *grpc.Server) error {
 }
 
 func (r *runner) Stop() error {
-	log.Println("Runner stopped successfully")
+	r.state.logger.Info("runner stopped")
 	return nil
 }
 
@@ -3,7 +3,7 @@ package worker_mcp_runner
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -13,8 +13,12 @@ import (
 	runnerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/runner"
 	workerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/worker"
 	"github.com/metorial/metorial/mcp-engine/internal/services/worker"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
+	"github.com/metorial/metorial/mcp-engine/pkg/tracing"
 	"github.com/metorial/metorial/modules/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type runnerServer struct {
@@ -106,8 +110,18 @@ func (s *runnerServer) StreamMcpRun(stream runnerPb.McpRunner_StreamMcpRunServer
 		return fmt.Errorf("expected McpInit request, got %T", req.Type)
 	}
 
-	run, err := s.state.StartRun(&RunInit{
-		ID: msg.Init.ConnectionId,
+	startCtx, startSpan := tracing.Start(
+		tracing.ExtractMap(stream.Context(), msg.Init.TraceContext),
+		"mcp_runner.StartRun",
+		trace.WithAttributes(
+			attribute.String("connection_id", msg.Init.ConnectionId),
+			attribute.String("docker_image", msg.Init.RunConfig.Container.DockerImage),
+		),
+	)
+
+	run, err := s.state.StartRun(startCtx, &RunInit{
+		ID:        msg.Init.ConnectionId,
+		SessionID: msg.Init.SessionId,
 
 		DockerImage:        msg.Init.RunConfig.Container.DockerImage,
 		ContainerMaxMemory: msg.Init.RunConfig.Container.MaxMemory,
@@ -117,6 +131,8 @@ func (s *runnerServer) StreamMcpRun(stream runnerPb.McpRunner_StreamMcpRunServer
 		ContainerArgs:    msg.Init.RunConfig.Arguments.Args,
 		ContainerCommand: msg.Init.RunConfig.Arguments.Command,
 	})
+	tracing.RecordError(startSpan, err)
+	startSpan.End()
 	if err != nil {
 		return stream.Send(&runnerPb.RunResponse{
 			Type: &runnerPb.RunResponse_Error{
@@ -139,8 +155,17 @@ func (s *runnerServer) StreamMcpRun(stream runnerPb.McpRunner_StreamMcpRunServer
 		return err
 	}
 
+	// Spans for requests sent to the container's stdin, ended
+	// once the server writes the response to stdout.
+	requestSpans := tracing.NewSpanTracker()
+	defer requestSpans.EndAll()
+
 	go run.HandleOutput(
 		func(message *mcp.MCPMessage) {
+			if message.MsgType == mcp.ResponseType || message.MsgType == mcp.ErrorType {
+				requestSpans.End(message.GetStringId())
+			}
+
 			err := stream.Send(&runnerPb.RunResponse{
 				Type: &runnerPb.RunResponse_McpMessage{
 					McpMessage: &runnerPb.RunResponseMcpMessage{
@@ -152,7 +177,7 @@ func (s *runnerServer) StreamMcpRun(stream runnerPb.McpRunner_StreamMcpRunServer
 				},
 			})
 			if err != nil {
-				log.Printf("Failed to send MCP message: %v\n", err)
+				run.logger.Warn("failed to send MCP message", logging.Err(err))
 			}
 		},
 		func(outputType OutputType, lines []string) {
@@ -179,7 +204,7 @@ func (s *runnerServer) StreamMcpRun(stream runnerPb.McpRunner_StreamMcpRunServer
 
 			err := stream.Send(&runnerPb.RunResponse{Type: outputMsg})
 			if err != nil {
-				log.Printf("Failed to send output message: %v\n", err)
+				run.logger.Warn("failed to send output message", logging.Err(err))
 			}
 		},
 	)
@@ -215,7 +240,7 @@ func (s *runnerServer) StreamMcpRun(stream runnerPb.McpRunner_StreamMcpRunServer
 		})
 	}()
 
-	log.Printf("Run started with ID: %s\n", run.ID)
+	run.logger.Info("run started", slog.String("docker_image", run.Init.DockerImage))
 
 loop:
 	for {
@@ -252,7 +277,7 @@ loop:
 			break loop
 
 		case *runnerPb.RunRequest_McpMessage:
-			err = run.HandleInput(msg.McpMessage.Message.Message)
+			err = s.handleInput(run, requestSpans, msg.McpMessage)
 			if err != nil {
 				return stream.Send(&runnerPb.RunResponse{
 					Type: &runnerPb.RunResponse_Error{
@@ -272,3 +297,28 @@ loop:
 
 	return nil
 }
+
+func (s *runnerServer) handleInput(run *Run, requestSpans *tracing.SpanTracker, msg *runnerPb.RunRequestMcpMessage) error {
+	ctx := tracing.ExtractMap(context.Background(), msg.TraceContext)
+
+	message, err := mcp.FromPbRawMessage(msg.Message)
+	if err != nil {
+		// Not parsable, so there is nothing to trace or annotate
+		return run.HandleInput(msg.Message.Message)
+	}
+
+	ctx, span := tracing.StartMessageSpan(ctx, "mcp_runner.HandleInput", message,
+		attribute.String("connection_id", run.ID),
+	)
+
+	err = run.HandleInput(tracing.InjectMcpMeta(ctx, message).GetStringPayload())
+	tracing.RecordError(span, err)
+
+	if err == nil && message.MsgType == mcp.RequestType {
+		requestSpans.Track(message.GetStringId(), span)
+	} else {
+		span.End()
+	}
+
+	return err
+}
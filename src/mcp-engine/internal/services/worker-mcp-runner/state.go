@@ -1,13 +1,15 @@
 package worker_mcp_runner
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/metorial/metorial/mcp-engine/pkg/docker"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/modules/util"
 )
 
@@ -20,19 +22,25 @@ type RunnerState struct {
 	active_runs map[string]*Run
 	total_runs  uint64
 
+	logger *slog.Logger
+
 	mutex sync.RWMutex
 	done  <-chan struct{}
 }
 
 func newRunnerState(dockerManager *docker.DockerManager, done <-chan struct{}) *RunnerState {
+	runnerID := util.Must(uuid.NewV7()).String()
+
 	return &RunnerState{
-		RunnerID:  util.Must(uuid.NewV7()).String(),
+		RunnerID:  runnerID,
 		StartTime: time.Now(),
 
 		dockerManager: dockerManager,
 		active_runs:   make(map[string]*Run),
 		total_runs:    0,
 		done:          done,
+
+		logger: slog.Default().With(logging.WorkerId(runnerID)),
 	}
 }
 
@@ -59,10 +67,10 @@ func (state *RunnerState) removeRun(runID string) {
 	delete(state.active_runs, runID)
 }
 
-func (state *RunnerState) StartRun(init *RunInit) (*Run, error) {
-	run, err := newRun(state, init)
+func (state *RunnerState) StartRun(ctx context.Context, init *RunInit) (*Run, error) {
+	run, err := newRun(ctx, state, init)
 	if err != nil {
-		log.Printf("Failed to start run: %v", err)
+		state.logger.Error("failed to start run", logging.SessionId(init.SessionID), logging.RunId(init.ID), slog.String("docker_image", init.DockerImage), logging.Err(err))
 		return nil, fmt.Errorf("failed to start run: %w", err)
 	}
 
@@ -103,10 +111,10 @@ func (state *RunnerState) printState() {
 	state.mutex.RLock()
 	defer state.mutex.RUnlock()
 
-	log.Println("\n== Runner State ==")
-
-	log.Println("Total Runs:", state.total_runs)
-	log.Println("Active Runs:", len(state.active_runs))
+	state.logger.Info("runner state",
+		slog.Uint64("total_runs", state.total_runs),
+		slog.Int("active_runs", len(state.active_runs)),
+	)
 }
 
 func (state *RunnerState) startPrintStateRoutine(interval time.Duration) {
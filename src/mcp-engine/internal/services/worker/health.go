@@ -1,7 +1,7 @@
 package worker
 
 import (
-	"log"
+	"log/slog"
 	"os"
 	"sync"
 	"time"
@@ -18,13 +18,15 @@ type WorkerHealth struct {
 type WorkerHealthManager struct {
 	Health          WorkerHealth
 	HealthBroadcast *pubsub.Broadcaster[WorkerHealth]
+	logger          *slog.Logger
 	mutex           sync.Mutex
 }
 
-func newWorkerHealthManager() *WorkerHealthManager {
+func newWorkerHealthManager(logger *slog.Logger) *WorkerHealthManager {
 	res := &WorkerHealthManager{
 		Health:          WorkerHealth{Healthy: true, AcceptingJobs: true},
 		HealthBroadcast: pubsub.NewBroadcaster[WorkerHealth](),
+		logger:          logger,
 	}
 
 	go res.routine()
@@ -37,9 +39,7 @@ func (m *WorkerHealthManager) SetHealth(healthy, acceptingJobs bool) {
 	defer m.mutex.Unlock()
 
 	if m.Health.Healthy != healthy || m.Health.AcceptingJobs != acceptingJobs {
-		log.Println("\n== Worker Health UPDATE ==")
-		log.Println("Healthy:", healthy)
-		log.Println("Accepting Jobs:", acceptingJobs)
+		m.logger.Info("worker health changed", slog.Bool("healthy", healthy), slog.Bool("accepting_jobs", acceptingJobs))
 	}
 
 	m.Health.Healthy = healthy
@@ -57,7 +57,7 @@ func (m *WorkerHealthManager) GetHealth() WorkerHealth {
 
 func (m *WorkerHealthManager) routine() {
 	if os.Getenv("ENABLE_RESOURCE_CHECK") == "false" {
-		log.Println("Resource checks are disabled, skipping health checks.")
+		m.logger.Info("resource checks are disabled, skipping health checks")
 		return
 	}
 
@@ -80,7 +80,7 @@ func (m *WorkerHealthManager) routine() {
 		if cpuOk && memOk {
 			m.SetHealth(true, true)
 		} else {
-			log.Printf("Worker usage high, not accepting jobs: CPU OK=%v, Memory OK=%v", cpuOk, memOk)
+			m.logger.Warn("worker usage high, not accepting jobs", slog.Bool("cpu_ok", cpuOk), slog.Bool("memory_ok", memOk))
 			m.SetHealth(true, false)
 		}
 	}
@@ -59,5 +59,9 @@ func (r *workerServer) getWorkerInfo() *workerPb.WorkerInfoResponse {
 		res.AcceptingJobs = workerPb.WorkerAcceptingJobs_not_accepting
 	}
 
+	if provider, ok := r.worker.impl.(WorkerInfoProvider); ok {
+		provider.AddWorkerInfo(res)
+	}
+
 	return res
 }
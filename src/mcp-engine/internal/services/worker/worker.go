@@ -3,8 +3,9 @@ package worker
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
+	"os"
 	"slices"
 	"sync"
 	"time"
@@ -13,6 +14,7 @@ import (
 	workerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/worker"
 	workerBrokerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/workerBroker"
 	grpc_util "github.com/metorial/metorial/mcp-engine/pkg/grpcUtil"
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/mcp-engine/pkg/managerUtils"
 	"github.com/metorial/metorial/modules/addr"
 	"google.golang.org/grpc"
@@ -26,6 +28,12 @@ type WorkerImpl interface {
 	WorkerId() string
 }
 
+// WorkerInfoProvider can be implemented by a WorkerImpl to add details
+// about the implementation to the worker info.
+type WorkerInfoProvider interface {
+	AddWorkerInfo(info *workerPb.WorkerInfoResponse)
+}
+
 type Worker struct {
 	WorkerID  string
 	Address   string
@@ -47,6 +55,8 @@ type Worker struct {
 
 	health WorkerHealthManager
 
+	logger *slog.Logger
+
 	context context.Context
 	cancel  context.CancelFunc
 
@@ -65,6 +75,11 @@ func NewWorker(ctx context.Context, workerType workerPb.WorkerType, ownAddress s
 
 	ctx, cancel := context.WithCancel(ctx)
 
+	logger := slog.Default().With(
+		logging.WorkerId(impl.WorkerId()),
+		slog.String("worker_type", workerType.String()),
+	)
+
 	worker := &Worker{
 		port: port,
 
@@ -74,7 +89,9 @@ func NewWorker(ctx context.Context, workerType workerPb.WorkerType, ownAddress s
 		Address:   ownAddress,
 		StartTime: time.Now(),
 
-		health: *newWorkerHealthManager(),
+		health: *newWorkerHealthManager(logger),
+
+		logger: logger,
 
 		managerConns:       make(map[string]*grpc.ClientConn),
 		managerClients:     make(map[string]workerBrokerPb.McpWorkerBrokerClient),
@@ -106,7 +123,8 @@ func (w *Worker) start() error {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", w.port))
 	if err != nil {
 		sentry.CaptureException(err)
-		log.Fatalf("Failed to listen: %v", err)
+		w.logger.Error("failed to listen", logging.Err(err))
+		os.Exit(1)
 	}
 
 	grpcServer := grpc_util.NewGrpcServer(fmt.Sprintf("worker.%s", w.workerType.String()))
@@ -117,21 +135,23 @@ func (w *Worker) start() error {
 
 	err = w.impl.Start(w, w.grpcServer)
 	if err != nil {
-		log.Fatalf("Failed to start worker: %v", err)
+		w.logger.Error("failed to start worker", logging.Err(err))
+		os.Exit(1)
 	}
 
 	reflection.Register(w.grpcServer)
 
 	if w.initialDiscoveryManagerAddress != "" {
-		log.Printf("Starting worker server at %s", w.Address)
+		w.logger.Info("starting worker server", slog.String("address", w.Address))
 	} else {
-		log.Printf("Starting standalone worker server at %s", w.Address)
+		w.logger.Info("starting standalone worker server", slog.String("address", w.Address))
 	}
 
 	err = w.grpcServer.Serve(lis)
 	if err != nil {
 		sentry.CaptureException(err)
-		log.Fatalf("Failed to serve: %v", err)
+		w.logger.Error("failed to serve", logging.Err(err))
+		os.Exit(1)
 	}
 
 	return nil
@@ -141,7 +161,7 @@ func (w *Worker) Stop() error {
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
 
-	log.Printf("Worker server at %s stopped", w.Address)
+	w.logger.Info("worker server stopped", slog.String("address", w.Address))
 
 	if w.grpcServer != nil {
 		w.grpcServer.Stop()
@@ -174,13 +194,14 @@ func (w *Worker) Stop() error {
 }
 
 func (w *Worker) registerWithManager(address string) error {
-	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc_util.WithTracing())
 	if err != nil {
 		sentry.CaptureException(err)
 		return err
 	}
 
 	client := workerBrokerPb.NewMcpWorkerBrokerClient(conn)
+	logger := w.logger.With(slog.String("manager_address", address))
 
 	w.managerMutex.Lock()
 	defer w.managerMutex.Unlock()
@@ -188,19 +209,20 @@ func (w *Worker) registerWithManager(address string) error {
 	managerInfo, err := client.GetManagerInfo(context.Background(), &workerBrokerPb.GetManagerInfoRequest{})
 	if err != nil {
 		sentry.CaptureException(err)
-		log.Printf("Failed to get manager info from %s: %v", address, err)
+		logger.Error("failed to get manager info", logging.Err(err))
 		return fmt.Errorf("failed to get manager info from %s: %w", address, err)
 	}
 
 	if lastStoredManagerId, exists := w.managerAddressToId[address]; exists {
 		// Already registered with this manager
 		if lastStoredManagerId == managerInfo.Id {
-			log.Printf("Worker %s already registered with manager %s at %s", w.WorkerID, lastStoredManagerId, address)
+			logger.Debug("worker already registered with manager", logging.ManagerId(lastStoredManagerId))
 			return nil
 		}
 	}
 
-	log.Printf("Registering worker %s with manager %s at %s", w.WorkerID, managerInfo.Id, address)
+	logger = logger.With(logging.ManagerId(managerInfo.Id))
+	logger.Info("registering worker with manager")
 
 	w.managerConns[address] = conn
 	w.managerClients[address] = client
@@ -215,7 +237,7 @@ func (w *Worker) registerWithManager(address string) error {
 		return err
 	}
 
-	log.Printf("Worker %s registered with manager at %s", w.WorkerID, address)
+	logger.Info("worker registered with manager")
 
 	return nil
 }
@@ -228,7 +250,7 @@ func (w *Worker) connectToNewManagers() error {
 
 	w.managerMutex.RLock()
 
-	discoveryConn, err := grpc.NewClient(w.initialDiscoveryManagerAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	discoveryConn, err := grpc.NewClient(w.initialDiscoveryManagerAddress, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc_util.WithTracing())
 	if err != nil {
 		sentry.CaptureException(err)
 		return err
@@ -278,7 +300,7 @@ func (w *Worker) connectToNewManagersRoutine() {
 
 	err := w.connectToNewManagers()
 	if err != nil {
-		log.Printf("Failed to connect to new managers: %v", err)
+		w.logger.Warn("failed to connect to new managers", logging.Err(err))
 	}
 
 	for {
@@ -288,7 +310,7 @@ func (w *Worker) connectToNewManagersRoutine() {
 		case <-ticker.C:
 			err := w.connectToNewManagers()
 			if err != nil {
-				log.Printf("Failed to connect to new managers: %v", err)
+				w.logger.Warn("failed to connect to new managers", logging.Err(err))
 			}
 		}
 	}
@@ -301,6 +323,12 @@ func (w *Worker) Health() WorkerHealth {
 	return w.health.GetHealth()
 }
 
+// Logger returns the logger of the worker, which implementations use to
+// log with the worker's attributes.
+func (w *Worker) Logger() *slog.Logger {
+	return w.logger
+}
+
 func (w *Worker) WorkerServer() *workerServer {
 	return w.workerServer
 }
@@ -311,5 +339,5 @@ func (w *Worker) Done() <-chan struct{} {
 
 func (w *Worker) Wait() {
 	<-w.context.Done()
-	log.Printf("Worker %s stopped", w.WorkerID)
+	w.logger.Info("worker stopped")
 }
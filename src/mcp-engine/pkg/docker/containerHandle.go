@@ -5,8 +5,10 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os/exec"
+
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 )
 
 type ContainerHandle struct {
@@ -24,6 +26,7 @@ type ContainerHandle struct {
 	done    chan struct{}
 	cancel  context.CancelFunc
 	manager *ContainerManager
+	logger  *slog.Logger
 }
 
 type LineHandler func(line string)
@@ -67,7 +70,7 @@ func (c *ContainerHandle) ListenToStderr(handler LineHandler) {
 }
 
 func (c *ContainerHandle) Stop() error {
-	log.Printf("Stopping container %s", c.ID)
+	c.logger.Info("stopping container")
 
 	select {
 	case <-c.done:
@@ -107,7 +110,7 @@ func (c *ContainerHandle) monitor() {
 	default:
 		close(c.done)
 
-		log.Printf("Container %s has exited", c.ID)
+		c.logger.Info("container has exited")
 
 		if err != nil {
 			if exitError, ok := err.(*exec.ExitError); ok {
@@ -140,7 +143,7 @@ func (c *ContainerHandle) monitor() {
 				c.ExitCode = exitError.ExitCode()
 			} else {
 				c.ExitCode = -1 // Indicate an error occurred
-				log.Printf("Container %s exited with error: %v\n", c.ID, err)
+				c.logger.Warn("container exited with error", logging.Err(err))
 			}
 		}
 	}
@@ -3,7 +3,7 @@ package docker
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"strings"
@@ -28,6 +28,8 @@ type ContainerManager struct {
 	ExternalHostMetorialServiceBroker string
 	ExternalHostMetorialListToken     string
 	ExternalHostPrivateKey            string
+
+	logger *slog.Logger
 }
 
 type ContainerStartOptions struct {
@@ -39,9 +41,13 @@ type ContainerStartOptions struct {
 	Command   string
 	MaxMemory string // Optional, e.g., "512m" or "1g"
 	MaxCPU    string // Optional, e.g., "1" or "2"
+
+	// Logger carries the attributes of the run the container
+	// belongs to. Optional, defaults to the manager's logger.
+	Logger *slog.Logger
 }
 
-func newContainerManager(runtime Runtime, imageManager *ImageManager) *ContainerManager {
+func newContainerManager(runtime Runtime, imageManager *ImageManager, logger *slog.Logger) *ContainerManager {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	m := &ContainerManager{
@@ -55,6 +61,8 @@ func newContainerManager(runtime Runtime, imageManager *ImageManager) *Container
 		ExternalHostMetorialServiceBroker: imageManager.ExternalHostMetorialServiceBroker,
 		ExternalHostMetorialListToken:     imageManager.ExternalHostMetorialListToken,
 		ExternalHostPrivateKey:            imageManager.ExternalHostPrivateKey,
+
+		logger: logger,
 	}
 
 	return m
@@ -84,6 +92,12 @@ func (m *ContainerManager) startContainer(opts *ContainerStartOptions) (*Contain
 
 	containerID := fmt.Sprintf("mtrc-%s", opts.ID)
 
+	logger := opts.Logger
+	if logger == nil {
+		logger = m.logger
+	}
+	logger = logger.With(slog.String("container_id", containerID))
+
 	dockerArgs := []string{
 		"run", "--interactive", "--rm",
 		"--name", containerID,
@@ -105,7 +119,7 @@ func (m *ContainerManager) startContainer(opts *ContainerStartOptions) (*Contain
 			return nil, fmt.Errorf("no available hosts from broker")
 		}
 
-		initRemoteKey(host, m.ExternalHostPrivateKey)
+		initRemoteKey(logger, host, m.ExternalHostPrivateKey)
 
 		dockerCommandEnv["DOCKER_HOST"] = fmt.Sprintf("ssh://ec2-user@%s", host)
 	}
@@ -162,7 +176,7 @@ func (m *ContainerManager) startContainer(opts *ContainerStartOptions) (*Contain
 	}
 	cmd.Dir = "/tmp"
 
-	log.Printf("Starting container for image %s with ID %s", image.FullName(), containerID)
+	logger.Info("starting container", slog.String("image", image.FullName()))
 
 	// Get stdin, stdout, stderr pipes
 	stdin, err := cmd.StdinPipe()
@@ -211,6 +225,7 @@ func (m *ContainerManager) startContainer(opts *ContainerStartOptions) (*Contain
 		done:    make(chan struct{}),
 		cancel:  cancel,
 		manager: m,
+		logger:  logger,
 	}
 
 	m.mutex.Lock()
@@ -1,15 +1,21 @@
 package docker
 
+import "log/slog"
+
 type DockerManager struct {
 	containerManager *ContainerManager
 	imageManager     *ImageManager
 }
 
 func NewDockerManager(runtime Runtime, opts ImageManagerCreateOptions) *DockerManager {
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+
 	imageManager := newImageManager(opts)
 
 	return &DockerManager{
-		containerManager: newContainerManager(runtime, imageManager),
+		containerManager: newContainerManager(runtime, imageManager, opts.Logger),
 		imageManager:     imageManager,
 	}
 }
@@ -3,7 +3,7 @@ package docker
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"slices"
 	"sync"
 	"time"
@@ -28,6 +28,8 @@ type ImageManager struct {
 	ExternalHostMetorialServiceBroker string
 	ExternalHostMetorialListToken     string
 	ExternalHostPrivateKey            string
+
+	logger *slog.Logger
 }
 
 type ImageManagerCreateOptions struct {
@@ -35,6 +37,10 @@ type ImageManagerCreateOptions struct {
 	ExternalHostMetorialServiceBroker string
 	ExternalHostMetorialListToken     string
 	ExternalHostPrivateKey            string
+
+	// Logger is used for all image and container related logs,
+	// defaults to slog.Default().
+	Logger *slog.Logger
 }
 
 func newImageManager(opts ImageManagerCreateOptions) *ImageManager {
@@ -51,6 +57,8 @@ func newImageManager(opts ImageManagerCreateOptions) *ImageManager {
 		ExternalHostMetorialServiceBroker: opts.ExternalHostMetorialServiceBroker,
 		ExternalHostMetorialListToken:     opts.ExternalHostMetorialListToken,
 		ExternalHostPrivateKey:            opts.ExternalHostPrivateKey,
+
+		logger: opts.Logger,
 	}
 
 	manager.startCleanupTask()
@@ -78,7 +86,10 @@ func (im *ImageManager) ensureImage(ctx context.Context, repository, tag string)
 		)
 
 		if len(hosts) < 1 {
-			log.Printf("No remote hosts available from broker %s for service %s", im.ExternalHostMetorialServiceBroker, im.ExternalHostMetorialServiceName)
+			im.logger.Warn("no remote hosts available from broker",
+				slog.String("broker", im.ExternalHostMetorialServiceBroker),
+				slog.String("service", im.ExternalHostMetorialServiceName),
+			)
 			return nil, fmt.Errorf("no remote hosts available from broker %s for service %s", im.ExternalHostMetorialServiceBroker, im.ExternalHostMetorialServiceName)
 		}
 
@@ -104,7 +115,7 @@ func (im *ImageManager) ensureImage(ctx context.Context, repository, tag string)
 			opts.ExternalHostPrivateKey = im.ExternalHostPrivateKey
 		}
 
-		imageManager = newLocalImageManager(opts, im.ctx)
+		imageManager = newLocalImageManager(opts, im.ctx, im.logger)
 		im.localImageManager[imageManagerKey] = imageManager
 	}
 
@@ -182,5 +193,5 @@ func (im *ImageManager) performCleanup() {
 		im.cleanupDuplicateImages()
 	}
 
-	log.Println("Image cleanup completed")
+	im.logger.Debug("image cleanup completed")
 }
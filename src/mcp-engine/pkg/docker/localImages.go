@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"sort"
@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/modules/datastructures"
 	"github.com/metorial/metorial/modules/util"
 )
@@ -46,6 +47,8 @@ type localImageManager struct {
 
 	ExternalHost           string
 	ExternalHostPrivateKey string
+
+	logger *slog.Logger
 }
 
 type localImageManagerHostOptions struct {
@@ -55,7 +58,11 @@ type localImageManagerHostOptions struct {
 
 type localImageManagerCreateOptions localImageManagerHostOptions
 
-func newLocalImageManager(opts localImageManagerCreateOptions, ctx context.Context) *localImageManager {
+func newLocalImageManager(opts localImageManagerCreateOptions, ctx context.Context, logger *slog.Logger) *localImageManager {
+	if opts.ExternalHost != "" {
+		logger = logger.With(slog.String("docker_host", opts.ExternalHost))
+	}
+
 	res := &localImageManager{
 		imagesByRepository: make(map[string][]*localImage),
 		imagesByID:         make(map[string]*localImage),
@@ -70,6 +77,8 @@ func newLocalImageManager(opts localImageManagerCreateOptions, ctx context.Conte
 
 		ExternalHost:           opts.ExternalHost,
 		ExternalHostPrivateKey: opts.ExternalHostPrivateKey,
+
+		logger: logger,
 	}
 
 	go res.monitor()
@@ -77,11 +86,11 @@ func newLocalImageManager(opts localImageManagerCreateOptions, ctx context.Conte
 	return res
 }
 
-func getLocalImages(ctx context.Context, opts localImageManagerHostOptions) ([]localImage, error) {
+func getLocalImages(ctx context.Context, logger *slog.Logger, opts localImageManagerHostOptions) ([]localImage, error) {
 	cmd := exec.CommandContext(ctx, "docker", "images", "--format", "{{json .}}")
 	cmd.Env = os.Environ()
 	if opts.ExternalHost != "" && opts.ExternalHostPrivateKey != "" {
-		initRemoteKey(opts.ExternalHost, opts.ExternalHostPrivateKey)
+		initRemoteKey(logger, opts.ExternalHost, opts.ExternalHostPrivateKey)
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", "DOCKER_HOST", fmt.Sprintf("ssh://ec2-user@%s", opts.ExternalHost)))
 	}
 
@@ -115,7 +124,7 @@ func (m *localImageManager) updateImages() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	images, err := getLocalImages(m.context, localImageManagerHostOptions{
+	images, err := getLocalImages(m.context, m.logger, localImageManagerHostOptions{
 		ExternalHost:           m.ExternalHost,
 		ExternalHostPrivateKey: m.ExternalHostPrivateKey,
 	})
@@ -144,10 +153,10 @@ func (m *localImageManager) monitor() {
 		select {
 		case <-ticker.C:
 			if err := m.updateImages(); err != nil {
-				log.Printf("Error updating local images: %v\n", err)
+				m.logger.Warn("failed to update local images", logging.Err(err))
 			}
 		case <-m.context.Done():
-			log.Println("Stopping local image manager monitor")
+			m.logger.Debug("stopping local image manager monitor")
 			return
 		}
 	}
@@ -166,17 +175,17 @@ func (m *localImageManager) pullImage(ctx context.Context, repository, tag strin
 	defer m.imagePullLocks[fullName].Unlock()
 
 	if image, exists := m.imagesByFullName[fullName]; exists {
-		log.Printf("Image %s already exists, skipping pull\n", fullName)
+		m.logger.Debug("image already exists, skipping pull", slog.String("image", fullName))
 		return image, nil
 	}
 
-	log.Printf("Pulling image %s", fullName)
+	m.logger.Info("pulling image", slog.String("image", fullName))
 
 	// Pull the image using Docker CLI
 	cmd := exec.CommandContext(ctx, "docker", "pull", fullName)
 	cmd.Env = os.Environ()
 	if m.ExternalHost != "" && m.ExternalHostPrivateKey != "" {
-		initRemoteKey(m.ExternalHost, m.ExternalHostPrivateKey)
+		initRemoteKey(m.logger, m.ExternalHost, m.ExternalHostPrivateKey)
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", "DOCKER_HOST", fmt.Sprintf("ssh://ec2-user@%s", m.ExternalHost)))
 	}
 
@@ -189,7 +198,7 @@ func (m *localImageManager) pullImage(ctx context.Context, repository, tag strin
 	cmd = exec.CommandContext(ctx, "docker", "images", "--format", "{{json .}}", fullName)
 	cmd.Env = os.Environ()
 	if m.ExternalHost != "" && m.ExternalHostPrivateKey != "" {
-		initRemoteKey(m.ExternalHost, m.ExternalHostPrivateKey)
+		initRemoteKey(m.logger, m.ExternalHost, m.ExternalHostPrivateKey)
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", "DOCKER_HOST", fmt.Sprintf("ssh://ec2-user@%s", m.ExternalHost)))
 	}
 
@@ -208,7 +217,7 @@ func (m *localImageManager) pullImage(ctx context.Context, repository, tag strin
 
 	m.setImageWithoutMutex(&img, false)
 
-	log.Printf("Successfully pulled image: %s\n", fullName)
+	m.logger.Info("pulled image", slog.String("image", fullName))
 
 	return &img, nil
 }
@@ -244,7 +253,7 @@ func (m *localImageManager) getImageOrFallback(ctx context.Context, repository,
 		go func() {
 			_, err := m.pullImage(ctx, repository, tag)
 			if err != nil {
-				log.Printf("Error pulling latest image for %s:%s: %v\n", repository, tag, err)
+				m.logger.Warn("failed to pull latest image", slog.String("image", repository+":"+tag), logging.Err(err))
 			}
 		}()
 
@@ -265,7 +274,7 @@ func (m *localImageManager) removeImage(ctx context.Context, imageId string) err
 		cmd := exec.CommandContext(ctx, "docker", "image", "rm", img.ID)
 		cmd.Env = os.Environ()
 		if m.ExternalHost != "" && m.ExternalHostPrivateKey != "" {
-			initRemoteKey(m.ExternalHost, m.ExternalHostPrivateKey)
+			initRemoteKey(m.logger, m.ExternalHost, m.ExternalHostPrivateKey)
 			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", "DOCKER_HOST", fmt.Sprintf("ssh://ec2-user@%s", m.ExternalHost)))
 		}
 
@@ -276,7 +285,7 @@ func (m *localImageManager) removeImage(ctx context.Context, imageId string) err
 				return // Ignore these errors
 			}
 
-			log.Printf("Error removing image %s: %v\nOutput: %s\n", img.ID, err, string(output))
+			m.logger.Warn("failed to remove image", slog.String("image_id", img.ID), logging.Err(err), slog.String("output", string(output)))
 		}
 	}()
 
@@ -379,7 +388,7 @@ func (m *localImageManager) cleanupDuplicateImages() {
 				go func() {
 					err := m.removeImage(context.Background(), img.ID)
 					if err != nil {
-						log.Printf("Error removing duplicate image %s: %v\n", img.ID, err)
+						m.logger.Warn("failed to remove duplicate image", slog.String("image_id", img.ID), logging.Err(err))
 					}
 				}()
 			}
@@ -400,15 +409,15 @@ func (m *localImageManager) cleanupUnused() {
 	}
 
 	usage, err := GetSystemStorageUsage()
-	log.Printf("Current system storage usage: %d percent\n", usage)
+	m.logger.Debug("current system storage usage", slog.Uint64("percent", usage))
 
 	if err != nil {
-		log.Printf("Error getting system storage usage: %v\n", err)
+		m.logger.Warn("failed to get system storage usage", logging.Err(err))
 		return
 	}
 
 	if usage < IMAGE_USAGE_THRESHOLD {
-		log.Println("System storage usage is below threshold, no cleanup needed")
+		m.logger.Debug("system storage usage is below threshold, no cleanup needed")
 		return
 	}
 
@@ -429,18 +438,18 @@ func (m *localImageManager) cleanupUnused() {
 		for _, img := range imagesSortedByLastUsed {
 			err := m.removeImage(context.Background(), img.ID)
 			if err != nil {
-				log.Printf("Error removing unused image %s: %v\n", img.ID, err)
+				m.logger.Warn("failed to remove unused image", slog.String("image_id", img.ID), logging.Err(err))
 			}
 
 			// Check if we are below the threshold after each removal
 			currentUsage, err := GetSystemStorageUsage()
 			if err != nil {
-				log.Printf("Error getting system storage usage during cleanup: %v\n", err)
+				m.logger.Warn("failed to get system storage usage during cleanup", logging.Err(err))
 				break
 			}
 
 			if currentUsage < IMAGE_USAGE_THRESHOLD {
-				log.Println("Cleanup complete, system storage usage is below threshold")
+				m.logger.Info("image cleanup complete, system storage usage is below threshold")
 				break
 			}
 		}
@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"sync"
 	"time"
 
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 	"github.com/metorial/metorial/mcp-engine/pkg/rendezvous"
 )
 
@@ -95,7 +97,7 @@ func (b *remoteBroker) ListRemoteHosts(address, serviceName, listToken string) [
 		defer b.mutex.Unlock()
 
 		if b.brokerAddress == "" {
-			fmt.Printf("Setting up remote broker with address %s, service %s\n", address, serviceName)
+			slog.Info("setting up remote broker", slog.String("broker", address), slog.String("service", serviceName))
 
 			b.brokerAddress = address
 			b.serviceName = serviceName
@@ -106,7 +108,7 @@ func (b *remoteBroker) ListRemoteHosts(address, serviceName, listToken string) [
 
 			// Initial fetch
 			if err := b.FetchInstances(); err != nil {
-				fmt.Printf("Failed to fetch instances from broker: %v\n", err)
+				slog.Warn("failed to fetch instances from broker", slog.String("broker", address), logging.Err(err))
 			}
 		}
 	}
@@ -124,7 +126,7 @@ func (b *remoteBroker) updateRoutine() {
 
 	for range ticker.C {
 		if err := b.FetchInstances(); err != nil {
-			fmt.Printf("Failed to fetch instances from broker: %v\n", err)
+			slog.Warn("failed to fetch instances from broker", slog.String("broker", b.brokerAddress), logging.Err(err))
 		}
 	}
 }
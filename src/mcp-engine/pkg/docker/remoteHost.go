@@ -1,14 +1,16 @@
 package docker
 
 import (
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
+
+	"github.com/metorial/metorial/mcp-engine/pkg/logging"
 )
 
 var initializedRemoteAddresses = make(map[string]bool)
 
-func initRemoteKey(externalHost, externalHostPrivateKey string) {
+func initRemoteKey(logger *slog.Logger, externalHost, externalHostPrivateKey string) {
 	if externalHost == "" || externalHostPrivateKey == "" {
 		return
 	}
@@ -18,23 +20,25 @@ func initRemoteKey(externalHost, externalHostPrivateKey string) {
 	}
 	initializedRemoteAddresses[externalHost] = true
 
+	logger = logger.With(slog.String("docker_host", externalHost))
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		log.Printf("failed to get home dir: %v", err)
+		logger.Error("failed to get home dir", logging.Err(err))
 		return
 	}
 
 	sshDir := filepath.Join(homeDir, ".ssh")
 	err = os.MkdirAll(sshDir, 0700)
 	if err != nil {
-		log.Printf("failed to create .ssh dir: %v", err)
+		logger.Error("failed to create .ssh dir", logging.Err(err))
 		return
 	}
 
 	keyPath := filepath.Join(sshDir, "id_rsa_"+externalHost)
 	err = os.WriteFile(keyPath, []byte(externalHostPrivateKey), 0600)
 	if err != nil {
-		log.Printf("failed to write ssh key for %s: %v", externalHost, err)
+		logger.Error("failed to write ssh key", logging.Err(err))
 		return
 	}
 
@@ -49,16 +53,16 @@ Host ` + externalHost + `
 
 	f, err := os.OpenFile(configPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
 	if err != nil {
-		log.Printf("failed to open ssh config: %v", err)
+		logger.Error("failed to open ssh config", logging.Err(err))
 		return
 	}
 	defer f.Close()
 
 	_, err = f.WriteString(configEntry)
 	if err != nil {
-		log.Printf("failed to write ssh config: %v", err)
+		logger.Error("failed to write ssh config", logging.Err(err))
 		return
 	}
 
-	log.Printf("SSH key for %s initialized at %s", externalHost, keyPath)
+	logger.Info("ssh key initialized", slog.String("path", keyPath))
 }
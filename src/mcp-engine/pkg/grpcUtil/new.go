@@ -1,13 +1,17 @@
 package grpc_util
 
 import (
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func NewGrpcServer(serviceName string) *grpc.Server {
-	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(RecoveryInterceptor))
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(RecoveryInterceptor),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+	)
 
 	healthServer := health.NewServer()
 	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
@@ -15,3 +19,9 @@ func NewGrpcServer(serviceName string) *grpc.Server {
 
 	return grpcServer
 }
+
+// WithTracing propagates the W3C trace context of outgoing calls
+// in gRPC metadata.
+func WithTracing() grpc.DialOption {
+	return grpc.WithStatsHandler(otelgrpc.NewClientHandler())
+}
@@ -0,0 +1,412 @@
+package jsLauncher
+
+import (
+	"math"
+	"reflect"
+	"sync"
+
+	"github.com/dop251/goja"
+)
+
+// Rough sizes the allocation budget charges. Strings are charged as if
+// they were UTF-16 and array elements include their share of the array,
+// so both err on the large side.
+const (
+	charBytes    = 2
+	elementBytes = 64
+)
+
+// adoptScript makes a wrapper take the place of a constructor: it gets
+// the properties and prototype chain of the constructor, and instances
+// report it as their constructor, which the engine uses to create
+// derived objects like the result of slice.
+const adoptScript = `
+(wrapper, original) => {
+  Object.defineProperties(wrapper, Object.getOwnPropertyDescriptors(original));
+  Object.setPrototypeOf(wrapper, Object.getPrototypeOf(original));
+  Object.defineProperty(original.prototype, 'constructor', { value: wrapper });
+}
+`
+
+var compileAdoptScript = sync.OnceValues(func() (*goja.Program, error) {
+	return goja.Compile("metorial-budget.js", adoptScript, true)
+})
+
+var (
+	proxyType       = reflect.TypeOf(goja.Proxy{})
+	arrayBufferType = reflect.TypeOf(goja.ArrayBuffer{})
+)
+
+// budget charges the memory built-in functions are about to allocate
+// against the allocation limit of a run. The functions are replaced by
+// proxies that charge their cost before calling them, so a single call
+// like 'x'.repeat(2 ** 30) is stopped before it allocates. Once the
+// budget is exceeded, the run is interrupted with ErrMemoryLimit.
+//
+// Arguments the cost is computed from are converted once and passed on
+// converted, so values with side effects, like objects with a valueOf
+// method or a length getter, can't report a different size to the
+// function than the one that was charged.
+type budget struct {
+	vm        *goja.Runtime
+	remaining int64
+	exceeded  bool
+}
+
+// costFunc returns the bytes a call may allocate and the this value to
+// call the function with. It may replace arguments with converted ones.
+type costFunc func(b *budget, this goja.Value, args []goja.Value) (int64, goja.Value)
+
+func newBudget(vm *goja.Runtime, maxBytes int64) *budget {
+	b := &budget{vm: vm, remaining: maxBytes}
+	if maxBytes <= 0 {
+		return b
+	}
+
+	stringProto := b.object("String", "prototype")
+	b.wrap(stringProto, "repeat", repeatCost)
+	b.wrap(stringProto, "padStart", padCost)
+	b.wrap(stringProto, "padEnd", padCost)
+
+	arrayProto := b.object("Array", "prototype")
+	for _, name := range []string{
+		"fill", "filter", "flat", "flatMap", "map", "reverse", "slice", "sort",
+		"splice", "toReversed", "toSorted", "toSpliced", "with",
+	} {
+		b.wrap(arrayProto, name, lengthCost)
+	}
+	b.wrap(arrayProto, "join", joinCost)
+	b.wrap(arrayProto, "concat", concatCost)
+	b.wrap(arrayProto, "push", argumentsCost)
+	b.wrap(arrayProto, "unshift", argumentsCost)
+
+	array := b.object("Array")
+	b.wrap(array, "from", fromCost)
+	b.wrap(array, "of", argumentsCost)
+
+	b.wrap(b.object("Function", "prototype"), "apply", argumentListCost(1))
+	reflectObject := b.object("Reflect")
+	b.wrap(reflectObject, "apply", argumentListCost(2))
+	b.wrap(reflectObject, "construct", argumentListCost(1))
+
+	b.wrapConstructor("ArrayBuffer", bufferCost)
+	b.wrapConstructor("SharedArrayBuffer", bufferCost)
+	for name, size := range map[string]int64{
+		"Int8Array": 1, "Uint8Array": 1, "Uint8ClampedArray": 1,
+		"Int16Array": 2, "Uint16Array": 2,
+		"Int32Array": 4, "Uint32Array": 4, "Float32Array": 4,
+		"Float64Array": 8, "BigInt64Array": 8, "BigUint64Array": 8,
+	} {
+		b.wrapConstructor(name, typedArrayCost(size))
+	}
+
+	return b
+}
+
+// object returns a built-in object by its path from the global object.
+func (b *budget) object(path ...string) *goja.Object {
+	object := b.vm.GlobalObject()
+	for _, name := range path {
+		object = object.Get(name).ToObject(b.vm)
+	}
+
+	return object
+}
+
+// wrap replaces a function with a proxy that charges its cost first.
+// Functions the engine doesn't provide are skipped.
+func (b *budget) wrap(holder *goja.Object, name string, cost costFunc) {
+	function, ok := holder.Get(name).(*goja.Object)
+	if !ok {
+		return
+	}
+
+	proxy := b.vm.NewProxy(function, &goja.ProxyTrapConfig{
+		Apply: func(target *goja.Object, this goja.Value, args []goja.Value) goja.Value {
+			bytes, this := cost(b, this, args)
+			b.charge(bytes)
+
+			call, _ := goja.AssertFunction(target)
+			result, err := call(this, args...)
+			if err != nil {
+				panic(err)
+			}
+			return result
+		},
+	})
+
+	holder.DefineDataProperty(name, b.vm.ToValue(proxy), goja.FLAG_TRUE, goja.FLAG_FALSE, goja.FLAG_TRUE)
+}
+
+// wrapConstructor replaces a constructor with one that charges its cost
+// first. The engine doesn't support instanceof with proxies, so unlike
+// wrap this uses a native constructor that takes over the properties of
+// the original one, including its prototype.
+func (b *budget) wrapConstructor(name string, cost costFunc) {
+	original, ok := b.vm.Get(name).(*goja.Object)
+	if !ok {
+		return
+	}
+	construct, _ := goja.AssertConstructor(original)
+
+	// The engine doesn't tell calls without new apart, so the wrapper
+	// constructs for those too
+	wrapper := b.vm.ToValue(func(call goja.ConstructorCall) *goja.Object {
+		bytes, _ := cost(b, goja.Undefined(), call.Arguments)
+		b.charge(bytes)
+
+		result, err := construct(call.NewTarget, call.Arguments...)
+		if err != nil {
+			panic(err)
+		}
+		return result
+	})
+
+	program, err := compileAdoptScript()
+	if err != nil {
+		panic(err)
+	}
+	adoptValue, err := b.vm.RunProgram(program)
+	if err != nil {
+		panic(err)
+	}
+	adopt, _ := goja.AssertFunction(adoptValue)
+	if _, err := adopt(goja.Undefined(), wrapper, original); err != nil {
+		panic(err)
+	}
+
+	b.vm.GlobalObject().DefineDataProperty(name, wrapper, goja.FLAG_TRUE, goja.FLAG_FALSE, goja.FLAG_TRUE)
+}
+
+func (b *budget) charge(bytes int64) {
+	if bytes <= b.remaining {
+		b.remaining -= bytes
+		return
+	}
+
+	b.exceeded = true
+	b.vm.Interrupt(ErrMemoryLimit)
+	panic(b.vm.NewGoError(ErrMemoryLimit))
+}
+
+// integer converts an argument to an integer once and replaces it with
+// the converted number. Missing, negative and NaN arguments count as 0.
+func (b *budget) integer(args []goja.Value, i int) int64 {
+	if i >= len(args) {
+		return 0
+	}
+
+	value := args[i].ToFloat()
+	args[i] = b.vm.ToValue(value)
+
+	switch {
+	case math.IsNaN(value) || value <= 0:
+		return 0
+	case value >= math.MaxInt64:
+		return math.MaxInt64
+	default:
+		return int64(value)
+	}
+}
+
+// stringLength converts a value to a string once and returns it with its
+// length.
+func (b *budget) stringLength(value goja.Value) (int64, goja.Value) {
+	if goja.IsUndefined(value) || goja.IsNull(value) {
+		// The function throws for these
+		return 0, value
+	}
+
+	str := value.ToString()
+	return str.ToObject(b.vm).Get("length").ToInteger(), str
+}
+
+// arrayLength returns the length of an array-like value. Objects other
+// than arrays may compute their length, so they are wrapped in a proxy
+// that reports the length that was read.
+func (b *budget) arrayLength(value goja.Value) (int64, goja.Value) {
+	if goja.IsUndefined(value) || goja.IsNull(value) {
+		return 0, value
+	}
+
+	object := value.ToObject(b.vm)
+	objectLength := lengthOf(object)
+	if isArray(object) {
+		return objectLength, object
+	}
+
+	pinned := b.vm.ToValue(objectLength)
+	proxy := b.vm.NewProxy(object, &goja.ProxyTrapConfig{
+		Get: func(target *goja.Object, property string, receiver goja.Value) goja.Value {
+			if property == "length" {
+				return pinned
+			}
+			return target.Get(property)
+		},
+	})
+
+	return objectLength, b.vm.ToValue(proxy)
+}
+
+// lengthOf returns the length property of an object as a size.
+func lengthOf(object *goja.Object) int64 {
+	value := object.Get("length")
+	if value == nil {
+		return 0
+	}
+
+	return max(value.ToInteger(), 0)
+}
+
+// isArray reports whether a value is an array, whose length is a plain
+// number.
+func isArray(value goja.Value) bool {
+	object, ok := value.(*goja.Object)
+	return ok && object.ClassName() == "Array" && object.ExportType() != proxyType
+}
+
+func isIterable(object *goja.Object) bool {
+	iterator := object.GetSymbol(goja.SymIterator)
+	return iterator != nil && !goja.IsUndefined(iterator)
+}
+
+// multiply multiplies sizes, saturating instead of overflowing.
+func multiply(a, b int64) int64 {
+	if a <= 0 || b <= 0 {
+		return 0
+	}
+	if a > math.MaxInt64/b {
+		return math.MaxInt64
+	}
+
+	return a * b
+}
+
+// add adds sizes, saturating instead of overflowing.
+func add(a, b int64) int64 {
+	if a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+
+	return a + b
+}
+
+func repeatCost(b *budget, this goja.Value, args []goja.Value) (int64, goja.Value) {
+	length, this := b.stringLength(this)
+	return multiply(multiply(length, b.integer(args, 0)), charBytes), this
+}
+
+func padCost(b *budget, this goja.Value, args []goja.Value) (int64, goja.Value) {
+	return multiply(b.integer(args, 0), charBytes), this
+}
+
+func lengthCost(b *budget, this goja.Value, args []goja.Value) (int64, goja.Value) {
+	length, this := b.arrayLength(this)
+	return multiply(length, elementBytes), this
+}
+
+func joinCost(b *budget, this goja.Value, args []goja.Value) (int64, goja.Value) {
+	length, this := b.arrayLength(this)
+
+	separator := int64(1)
+	if len(args) > 0 && !goja.IsUndefined(args[0]) {
+		separator, args[0] = b.stringLength(args[0])
+	}
+
+	return add(multiply(length, elementBytes), multiply(multiply(length, separator), charBytes)), this
+}
+
+func concatCost(b *budget, this goja.Value, args []goja.Value) (int64, goja.Value) {
+	// Values other than arrays are added as a single element, so they
+	// aren't replaced
+	length := int64(1)
+	if isArray(this) {
+		length, _ = b.arrayLength(this)
+	}
+
+	for _, arg := range args {
+		if isArray(arg) {
+			argLength, _ := b.arrayLength(arg)
+			length = add(length, argLength)
+		} else {
+			length = add(length, 1)
+		}
+	}
+
+	return multiply(length, elementBytes), this
+}
+
+func argumentsCost(b *budget, this goja.Value, args []goja.Value) (int64, goja.Value) {
+	return multiply(int64(len(args)), elementBytes), this
+}
+
+func fromCost(b *budget, this goja.Value, args []goja.Value) (int64, goja.Value) {
+	if len(args) == 0 {
+		return 0, this
+	}
+
+	// Iterables are read through their iterator, which would fail on a
+	// proxy. Only arrays among them have a known length.
+	if object, ok := args[0].(*goja.Object); ok && isIterable(object) && !isArray(object) {
+		return 0, this
+	}
+
+	length, arrayLike := b.arrayLength(args[0])
+	args[0] = arrayLike
+
+	return multiply(length, elementBytes), this
+}
+
+// argumentListCost charges the argument list a function builds from the
+// array-like argument at index i.
+func argumentListCost(i int) costFunc {
+	return func(b *budget, this goja.Value, args []goja.Value) (int64, goja.Value) {
+		if i >= len(args) {
+			return 0, this
+		}
+
+		if _, ok := args[i].(*goja.Object); !ok {
+			// The function throws for these
+			return 0, this
+		}
+
+		length, arrayLike := b.arrayLength(args[i])
+		args[i] = arrayLike
+
+		return multiply(length, elementBytes), this
+	}
+}
+
+func bufferCost(b *budget, this goja.Value, args []goja.Value) (int64, goja.Value) {
+	return b.integer(args, 0), this
+}
+
+// typedArrayCost charges typed arrays with elements of the given size.
+// Views of existing buffers don't allocate.
+func typedArrayCost(size int64) costFunc {
+	return func(b *budget, this goja.Value, args []goja.Value) (int64, goja.Value) {
+		if len(args) == 0 {
+			return 0, this
+		}
+
+		object, ok := args[0].(*goja.Object)
+		if !ok {
+			return multiply(b.integer(args, 0), size), this
+		}
+
+		if object.ExportType() == arrayBufferType {
+			return 0, this
+		}
+
+		// Typed arrays and other iterables are read through their
+		// iterator, so their length isn't replaced
+		if isIterable(object) {
+			return multiply(lengthOf(object), size), this
+		}
+
+		length, arrayLike := b.arrayLength(object)
+		args[0] = arrayLike
+
+		return multiply(length, size), this
+	}
+}
@@ -0,0 +1,226 @@
+package jsLauncher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+	launcherPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/launcher"
+)
+
+// ContextScript defines the context helpers passed to launcher functions
+// as their second argument. It is shared by all launcher types.
+const ContextScript = `
+let launcherContext = (config) => ({
+  args: {
+    flags: (input) => {
+      if (typeof input !== 'object' || input === null) {
+        throw new Error('Invalid input, expected object');
+      }
+
+      if (typeof input.separator !== 'string' && input.separator !== undefined) {
+        throw new Error('Invalid input.separator, expected string');
+      }
+
+      if (typeof input.args !== 'object' || input.args === null) {
+        throw new Error('Invalid input.args, expected object');
+      }
+
+      let args = (Array.isArray(input.args) ? input.args : Object.entries(input.args))
+        .map(arg => {
+          let configValue = config[arg[1]];
+          if (configValue === undefined || configValue === null || configValue === '')
+            return undefined;
+
+          return [arg[0], configValue];
+        })
+        .filter(Boolean);
+
+      if (input.separator) {
+        return args.map(arg => arg.join(input.separator));
+      }
+
+      return args.flatMap(arg => arg);
+    }
+  },
+
+	getHeadersWithAuthorization: (otherHeaders) => {
+		let newHeaders = {};
+		if (typeof otherHeaders == 'object' && otherHeaders !== null) {
+			newHeaders = { ...otherHeaders };
+		}
+
+		if (config.token) {
+			newHeaders['Authorization'] = 'Bearer ' + config.token;
+		}
+
+		if (config.__metorial_oauth__ && config.__metorial_oauth__.accessToken) {
+			newHeaders['Authorization'] = 'Bearer ' + config.__metorial_oauth__.accessToken;
+		}
+
+		return newHeaders;
+	},
+})
+`
+
+const runScript = ContextScript + `
+let console = {
+  log: () => {}, info: () => {}, warn: () => {}, error: () => {}, debug: () => {}, trace: () => {},
+};
+
+let run = (code, configJSON) => {
+  let config = JSON.parse(configJSON);
+
+  let launcher = eval(code);
+
+  let sanitizedConfig = { ...config };
+  delete sanitizedConfig.__metorial_oauth__;
+
+  let output = typeof launcher == 'function' ?
+    launcher(sanitizedConfig, launcherContext(config)) :
+    launcher;
+
+  return JSON.stringify(output);
+};
+
+run
+`
+
+var (
+	ErrTimeout     = errors.New("launcher exceeded its time limit")
+	ErrMemoryLimit = errors.New("launcher exceeded its memory limit")
+)
+
+// Limits bound the resources of a launcher run.
+//
+// The engine can't count steps, so CPU is limited by interrupting runs
+// after MaxDuration; runs are single threaded, so this bounds the CPU
+// time as well. Built-in functions that allocate in proportion to their
+// arguments, like String.prototype.repeat or new ArrayBuffer, are charged
+// against MaxAllocBytes before they run, see budget. Other allocations,
+// like adding strings, are only bounded by MaxDuration.
+type Limits struct {
+	MaxDuration      time.Duration
+	MaxAllocBytes    int64
+	MaxCallStackSize int
+}
+
+func DefaultLimits() Limits {
+	return Limits{
+		MaxDuration:      time.Second,
+		MaxAllocBytes:    64 * 1024 * 1024,
+		MaxCallStackSize: 1000,
+	}
+}
+
+// LauncherError is an error thrown by the launcher code.
+type LauncherError struct {
+	Message string
+}
+
+func (e *LauncherError) Error() string {
+	return e.Message
+}
+
+var compileRunScript = sync.OnceValues(func() (*goja.Program, error) {
+	return goja.Compile("metorial-launcher.js", runScript, true)
+})
+
+// Run evaluates launcher code with the given config and returns the
+// JSON output of the launcher.
+func Run(ctx context.Context, code, jsonConfig string, limits Limits) (string, error) {
+	program, err := compileRunScript()
+	if err != nil {
+		return "", fmt.Errorf("failed to compile launcher runtime: %w", err)
+	}
+
+	vm := goja.New()
+	if limits.MaxCallStackSize > 0 {
+		vm.SetMaxCallStackSize(limits.MaxCallStackSize)
+	}
+
+	budget := newBudget(vm, limits.MaxAllocBytes)
+
+	value, err := vm.RunProgram(program)
+	if err != nil {
+		return "", fmt.Errorf("failed to start launcher runtime: %w", err)
+	}
+
+	run, ok := goja.AssertFunction(value)
+	if !ok {
+		return "", fmt.Errorf("failed to start launcher runtime")
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go watch(ctx, vm, limits, done)
+
+	result, err := run(goja.Undefined(), vm.ToValue(code), vm.ToValue(jsonConfig))
+	if budget.exceeded {
+		return "", ErrMemoryLimit
+	}
+	if err != nil {
+		var interrupted *goja.InterruptedError
+		if errors.As(err, &interrupted) {
+			if cause, ok := interrupted.Value().(error); ok {
+				return "", cause
+			}
+		}
+
+		var exception *goja.Exception
+		if errors.As(err, &exception) {
+			return "", &LauncherError{Message: exception.String()}
+		}
+
+		var stackOverflow *goja.StackOverflowError
+		if errors.As(err, &stackOverflow) {
+			return "", &LauncherError{Message: stackOverflow.Error()}
+		}
+
+		return "", err
+	}
+
+	if goja.IsUndefined(result) {
+		return "null", nil
+	}
+
+	return result.String(), nil
+}
+
+// watch interrupts the run once it exceeds its time limit or ctx is done,
+// with the cause of ctx.
+func watch(ctx context.Context, vm *goja.Runtime, limits Limits, done <-chan struct{}) {
+	var timeout <-chan time.Time
+	if limits.MaxDuration > 0 {
+		timer := time.NewTimer(limits.MaxDuration)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		vm.Interrupt(context.Cause(ctx))
+	case <-timeout:
+		vm.Interrupt(ErrTimeout)
+	}
+}
+
+// RunLauncher runs a launcher config with the default limits.
+func RunLauncher(ctx context.Context, input *launcherPb.LauncherConfig) *launcherPb.RunLauncherResponse {
+	output, err := Run(ctx, input.Code, input.JsonConfig, DefaultLimits())
+	if err != nil {
+		return &launcherPb.RunLauncherResponse{
+			Type:         launcherPb.RunLauncherResponse_error,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	return &launcherPb.RunLauncherResponse{
+		Type:       launcherPb.RunLauncherResponse_success,
+		JsonOutput: output,
+	}
+}
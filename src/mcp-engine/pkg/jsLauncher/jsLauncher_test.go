@@ -0,0 +1,242 @@
+package jsLauncher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	launcherPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/launcher"
+)
+
+func runJSON(t *testing.T, code, config string) any {
+	t.Helper()
+
+	output, err := Run(context.Background(), code, config, DefaultLimits())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	var result any
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("output is not JSON: %q", output)
+	}
+
+	return result
+}
+
+func TestRun_Flags(t *testing.T) {
+	code := `(config, ctx) => ({
+		command: 'server',
+		args: ctx.args.flags({ args: { '--port': 'port', '--host': 'host', '--empty': 'empty' } }),
+		joined: ctx.args.flags({ args: [['--port', 'port']], separator: '=' }),
+	})`
+
+	got := runJSON(t, code, `{"port": 8080, "host": "localhost", "empty": ""}`)
+	want := map[string]any{
+		"command": "server",
+		"args":    []any{"--port", float64(8080), "--host", "localhost"},
+		"joined":  []any{"--port=8080"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestRun_Headers(t *testing.T) {
+	code := `(config, ctx) => ({ headers: ctx.getHeadersWithAuthorization({ 'X-Custom': 'a' }), config })`
+
+	got := runJSON(t, code, `{"token": "secret", "__metorial_oauth__": {"accessToken": "oauth"}}`)
+	want := map[string]any{
+		"headers": map[string]any{"X-Custom": "a", "Authorization": "Bearer oauth"},
+		"config":  map[string]any{"token": "secret"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestRun_StaticAndUndefined(t *testing.T) {
+	if got := runJSON(t, `({ command: 'static' })`, `{}`); !reflect.DeepEqual(got, map[string]any{"command": "static"}) {
+		t.Errorf("static launcher: got %v", got)
+	}
+
+	if got := runJSON(t, `() => { console.log('ignored') }`, `{}`); got != nil {
+		t.Errorf("undefined output: got %v, want nil", got)
+	}
+}
+
+func TestRun_LauncherErrors(t *testing.T) {
+	cases := map[string]string{
+		"throw":     `() => { throw new Error('missing api key') }`,
+		"syntax":    `(config) => {`,
+		"recursion": `() => { let f = () => f(); return f() }`,
+	}
+
+	for name, code := range cases {
+		_, err := Run(context.Background(), code, `{}`, DefaultLimits())
+
+		var launcherErr *LauncherError
+		if !errors.As(err, &launcherErr) {
+			t.Errorf("%s: expected launcher error, got %v", name, err)
+		}
+	}
+
+	_, err := Run(context.Background(), `() => { throw new Error('missing api key') }`, `{}`, DefaultLimits())
+	if err == nil || !strings.Contains(err.Error(), "missing api key") {
+		t.Errorf("expected error message to be kept, got %v", err)
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	limits := DefaultLimits()
+	limits.MaxDuration = 100 * time.Millisecond
+
+	start := time.Now()
+	_, err := Run(context.Background(), `() => { while (true) {} }`, `{}`, limits)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("run wasn't interrupted in time: %s", elapsed)
+	}
+}
+
+func TestRun_MemoryLimit(t *testing.T) {
+	limits := DefaultLimits()
+	limits.MaxDuration = 30 * time.Second
+	limits.MaxAllocBytes = 16 * 1024 * 1024
+
+	_, err := Run(context.Background(), `() => { let items = []; while (true) items.push({ value: 'x'.repeat(64) + items.length }) }`, `{}`, limits)
+	if !errors.Is(err, ErrMemoryLimit) {
+		t.Fatalf("expected memory limit, got %v", err)
+	}
+}
+
+func TestRun_LargeAllocation(t *testing.T) {
+	limits := DefaultLimits()
+	limits.MaxDuration = 30 * time.Second
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := Run(context.Background(), `() => 'x'.repeat(2 ** 30).length`, `{}`, limits)
+		done <- err
+	}()
+
+	// Runs next to it keep working
+	if got := runJSON(t, `() => ({ command: 'server' })`, `{}`); !reflect.DeepEqual(got, map[string]any{"command": "server"}) {
+		t.Errorf("concurrent run: got %v", got)
+	}
+
+	if err := <-done; !errors.Is(err, ErrMemoryLimit) {
+		t.Fatalf("expected memory limit, got %v", err)
+	}
+
+	for _, code := range []string{
+		`() => new ArrayBuffer(2 ** 31).byteLength`,
+		`() => new Array(2 ** 26).fill('x').join('')`,
+	} {
+		if _, err := Run(context.Background(), code, `{}`, limits); !errors.Is(err, ErrMemoryLimit) {
+			t.Errorf("%s: expected memory limit, got %v", code, err)
+		}
+	}
+}
+
+func TestRun_AllocationBudget(t *testing.T) {
+	limits := DefaultLimits()
+	limits.MaxDuration = 30 * time.Second
+
+	exceeding := []string{
+		`() => 'x'.padStart(2 ** 30)`,
+		`() => { try { 'x'.repeat(2 ** 30) } catch (e) { return 'caught' } }`,
+		`() => new (new Uint8Array(1).constructor)(2 ** 31)`,
+		`() => new Uint8Array(2 ** 20).map(x => x).slice().constructor.from({ length: 2 ** 31 })`,
+		`() => Array.from({ length: 2 ** 26 })`,
+		`() => { let a = []; a.length = 2 ** 26; return a.map(x => x) }`,
+		`() => String.fromCharCode.apply(null, { length: 2 ** 26 })`,
+		`() => new Uint32Array(2 ** 28)`,
+		`() => new Float64Array({ length: 2 ** 26 })`,
+	}
+	for _, code := range exceeding {
+		if _, err := Run(context.Background(), code, `{}`, limits); !errors.Is(err, ErrMemoryLimit) {
+			t.Errorf("%s: expected memory limit, got %v", code, err)
+		}
+	}
+
+	// Arguments are only converted once, so they can't report a
+	// different size to the function than the one that was charged
+	code := `() => {
+		let calls = 0;
+		let count = { valueOf: () => calls++ ? 2 ** 30 : 1 };
+		let lengthCalls = 0;
+		let arrayLike = { get length() { return lengthCalls++ ? 2 ** 30 : 1 } };
+		return [ 'x'.repeat(count), Array.prototype.fill.call(arrayLike, 1)[0] ];
+	}`
+	if got := runJSON(t, code, `{}`); !reflect.DeepEqual(got, []any{"x", float64(1)}) {
+		t.Errorf("converted arguments: got %v", got)
+	}
+
+	// Wrapped functions keep working within the budget
+	code = `() => ({
+		repeat: 'ab'.repeat(2),
+		pad: '1'.padStart(3, '0'),
+		set: Array.from(new Set([1, 2])),
+		arrayLike: Array.from({ length: 2, 0: 'a', 1: 'b' }),
+		map: Array.prototype.map.call('ab', c => c + c),
+		joined: [1, 2].concat([3], 4).join('-'),
+		max: Math.max.apply(null, [1, 3, 2]),
+		bytes: new Uint8Array(new ArrayBuffer(4), 1, 2).length,
+		copy: Array.from(new Uint8Array([1, 2])),
+		isArray: Array.isArray(Array.of(1)) && [] instanceof Array && new ArrayBuffer(1) instanceof ArrayBuffer,
+		typed: new Uint16Array(2).constructor === Uint16Array && Uint16Array.BYTES_PER_ELEMENT,
+	})`
+	want := map[string]any{
+		"repeat":    "abab",
+		"pad":       "001",
+		"set":       []any{float64(1), float64(2)},
+		"arrayLike": []any{"a", "b"},
+		"map":       []any{"aa", "bb"},
+		"joined":    "1-2-3-4",
+		"max":       float64(3),
+		"bytes":     float64(2),
+		"copy":      []any{float64(1), float64(2)},
+		"isArray":   true,
+		"typed":     float64(2),
+	}
+	if got := runJSON(t, code, `{}`); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestRun_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := Run(ctx, `() => { while (true) {} }`, `{}`, DefaultLimits())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context error, got %v", err)
+	}
+}
+
+func TestRunLauncher(t *testing.T) {
+	res := RunLauncher(context.Background(), &launcherPb.LauncherConfig{
+		LauncherType: launcherPb.LauncherConfig_embedded,
+		Code:         `(config) => ({ command: config.command })`,
+		JsonConfig:   `{"command": "run"}`,
+	})
+	if res.Type != launcherPb.RunLauncherResponse_success || res.JsonOutput != `{"command":"run"}` {
+		t.Errorf("unexpected response: %v", res)
+	}
+
+	res = RunLauncher(context.Background(), &launcherPb.LauncherConfig{
+		LauncherType: launcherPb.LauncherConfig_embedded,
+		Code:         `() => { throw new Error('boom') }`,
+		JsonConfig:   `{}`,
+	})
+	if res.Type != launcherPb.RunLauncherResponse_error || !strings.Contains(res.ErrorMessage, "boom") {
+		t.Errorf("unexpected response: %v", res)
+	}
+}
@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Attribute keys shared by all mcp-engine binaries, so that log lines can
+// be filtered by the session, run, worker or manager they belong to.
+const (
+	SessionIdKey = "session_id"
+	RunIdKey     = "run_id"
+	WorkerIdKey  = "worker_id"
+	ManagerIdKey = "manager_id"
+	ServiceKey   = "service"
+	ErrorKey     = "error"
+)
+
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJson Format = "json"
+)
+
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func ParseFormat(format string) Format {
+	switch strings.ToLower(format) {
+	case "json":
+		return FormatJson
+	default:
+		return FormatText
+	}
+}
+
+func NewHandler(w io.Writer, format Format, level slog.Level) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+
+	if format == FormatJson {
+		return slog.NewJSONHandler(w, opts)
+	}
+
+	return slog.NewTextHandler(w, opts)
+}
+
+// InitLoggingIfNeeded installs the default structured logger for a binary.
+// The level and format are read from LOG_LEVEL (debug, info, warn, error)
+// and LOG_FORMAT (text, json). Output of the standard `log` package is
+// routed through the same handler.
+func InitLoggingIfNeeded(serviceName string) {
+	level := ParseLevel(os.Getenv("LOG_LEVEL"))
+	format := ParseFormat(os.Getenv("LOG_FORMAT"))
+
+	logger := slog.New(NewHandler(os.Stderr, format, level)).With(slog.String(ServiceKey, serviceName))
+	slog.SetDefault(logger)
+}
+
+func SessionId(id string) slog.Attr {
+	return slog.String(SessionIdKey, id)
+}
+
+func RunId(id string) slog.Attr {
+	return slog.String(RunIdKey, id)
+}
+
+func WorkerId(id string) slog.Attr {
+	return slog.String(WorkerIdKey, id)
+}
+
+func ManagerId(id string) slog.Attr {
+	return slog.String(ManagerIdKey, id)
+}
+
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+
+	return slog.String(ErrorKey, err.Error())
+}
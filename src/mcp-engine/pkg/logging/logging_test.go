@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	cases := map[string]slog.Level{
+		"debug":   slog.LevelDebug,
+		"DEBUG":   slog.LevelDebug,
+		"info":    slog.LevelInfo,
+		"warn":    slog.LevelWarn,
+		"warning": slog.LevelWarn,
+		"error":   slog.LevelError,
+		"":        slog.LevelInfo,
+		"bogus":   slog.LevelInfo,
+	}
+
+	for input, expected := range cases {
+		if got := ParseLevel(input); got != expected {
+			t.Errorf("ParseLevel(%q) = %v, expected %v", input, got, expected)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if ParseFormat("JSON") != FormatJson {
+		t.Errorf("expected json format")
+	}
+	if ParseFormat("") != FormatText {
+		t.Errorf("expected text format by default")
+	}
+}
+
+func TestJsonHandlerAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, FormatJson, slog.LevelInfo)).
+		With(ManagerId("mgr"), WorkerId("wrk")).
+		With(SessionId("ses"), RunId("run"))
+
+	logger.Debug("hidden")
+	logger.Info("visible", Err(errors.New("boom")), Err(nil))
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("expected a single json line, got %q: %v", buf.String(), err)
+	}
+
+	expected := map[string]string{
+		"msg":        "visible",
+		ManagerIdKey: "mgr",
+		WorkerIdKey:  "wrk",
+		SessionIdKey: "ses",
+		RunIdKey:     "run",
+		ErrorKey:     "boom",
+	}
+	for key, value := range expected {
+		if line[key] != value {
+			t.Errorf("expected %s=%q, got %v", key, value, line[key])
+		}
+	}
+}
@@ -286,6 +286,68 @@ func (m *MCPMessage) GetMethod() string {
 	return ""
 }
 
+// WithMeta returns a copy of the message with the given entries merged
+// into `params._meta`. Messages whose params are not an object are
+// returned unchanged.
+func (m *MCPMessage) WithMeta(meta map[string]string) (*MCPMessage, error) {
+	if len(meta) == 0 || m.raw == nil {
+		return m, nil
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(m.raw, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	params := map[string]json.RawMessage{}
+	if paramsRaw, ok := raw["params"]; ok && string(paramsRaw) != "null" {
+		if err := json.Unmarshal(paramsRaw, &params); err != nil {
+			return m, nil // Positional params, nowhere to put _meta
+		}
+	}
+
+	metaObj := map[string]json.RawMessage{}
+	if metaRaw, ok := params["_meta"]; ok {
+		if err := json.Unmarshal(metaRaw, &metaObj); err != nil {
+			return m, nil
+		}
+	}
+
+	for k, v := range meta {
+		if _, exists := metaObj[k]; exists {
+			continue // Never override what the client sent
+		}
+
+		value, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		metaObj[k] = value
+	}
+
+	var err error
+	if params["_meta"], err = json.Marshal(metaObj); err != nil {
+		return nil, err
+	}
+	if raw["params"], err = json.Marshal(params); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MCPMessage{
+		Method:       m.Method,
+		MsgType:      m.MsgType,
+		rawId:        m.rawId,
+		stringId:     m.stringId,
+		raw:          data,
+		internalUuid: m.GetUuid(),
+	}, nil
+}
+
 func (m *MCPMessage) GetUuid() string {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
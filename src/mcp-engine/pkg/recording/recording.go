@@ -0,0 +1,168 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
+	mcpPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/mcp"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// A recording is a JSON lines file. The first entry holds the request the
+// session was created with, followed by the messages the client sent and
+// every response streamed back by the manager, in the order they occurred.
+
+type EntryType string
+
+const (
+	EntryTypeSession       EntryType = "session"
+	EntryTypeClientMessage EntryType = "client_message"
+	EntryTypeResponse      EntryType = "response"
+)
+
+type Entry struct {
+	Type EntryType
+	Time time.Time
+
+	Session  *managerPb.CreateSessionRequest
+	Message  *mcpPb.McpMessageRaw
+	Response *managerPb.McpConnectionStreamResponse
+}
+
+type Recording struct {
+	Session *managerPb.CreateSessionRequest
+	Entries []*Entry
+}
+
+// ClientMessages returns the messages sent by the client, in order.
+func (r *Recording) ClientMessages() []*mcpPb.McpMessageRaw {
+	res := make([]*mcpPb.McpMessageRaw, 0)
+	for _, entry := range r.Entries {
+		if entry.Type == EntryTypeClientMessage {
+			res = append(res, entry.Message)
+		}
+	}
+
+	return res
+}
+
+// Responses returns the responses received from the manager, in order.
+func (r *Recording) Responses() []*managerPb.McpConnectionStreamResponse {
+	res := make([]*managerPb.McpConnectionStreamResponse, 0)
+	for _, entry := range r.Entries {
+		if entry.Type == EntryTypeResponse {
+			res = append(res, entry.Response)
+		}
+	}
+
+	return res
+}
+
+type fileEntry struct {
+	Type EntryType       `json:"type"`
+	Time time.Time       `json:"time"`
+	Data json.RawMessage `json:"data"`
+}
+
+type Writer struct {
+	w     io.Writer
+	mutex sync.Mutex
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (w *Writer) WriteSession(req *managerPb.CreateSessionRequest) error {
+	return w.write(EntryTypeSession, req)
+}
+
+func (w *Writer) WriteClientMessage(msg *mcpPb.McpMessageRaw) error {
+	return w.write(EntryTypeClientMessage, msg)
+}
+
+func (w *Writer) WriteResponse(res *managerPb.McpConnectionStreamResponse) error {
+	return w.write(EntryTypeResponse, res)
+}
+
+func (w *Writer) write(entryType EntryType, msg proto.Message) error {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s entry: %w", entryType, err)
+	}
+
+	line, err := json.Marshal(fileEntry{Type: entryType, Time: time.Now().UTC(), Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s entry: %w", entryType, err)
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	_, err = w.w.Write(append(line, '\n'))
+	return err
+}
+
+func Read(r io.Reader) (*Recording, error) {
+	res := &Recording{Entries: make([]*Entry, 0)}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var fe fileEntry
+		if err := json.Unmarshal(scanner.Bytes(), &fe); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		entry := &Entry{Type: fe.Type, Time: fe.Time}
+
+		var msg proto.Message
+		switch fe.Type {
+		case EntryTypeSession:
+			entry.Session = &managerPb.CreateSessionRequest{}
+			msg = entry.Session
+		case EntryTypeClientMessage:
+			entry.Message = &mcpPb.McpMessageRaw{}
+			msg = entry.Message
+		case EntryTypeResponse:
+			entry.Response = &managerPb.McpConnectionStreamResponse{}
+			msg = entry.Response
+		default:
+			return nil, fmt.Errorf("line %d: unknown entry type %q", lineNumber, fe.Type)
+		}
+
+		if err := protojson.Unmarshal(fe.Data, msg); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		if entry.Session != nil && res.Session == nil {
+			res.Session = entry.Session
+		}
+
+		res.Entries = append(res.Entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if res.Session == nil {
+		return nil, fmt.Errorf("recording does not contain a session entry")
+	}
+
+	return res, nil
+}
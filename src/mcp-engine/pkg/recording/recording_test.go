@@ -0,0 +1,69 @@
+package recording
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
+	mcpPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/mcp"
+)
+
+func TestWriteRead(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	if err := w.WriteSession(&managerPb.CreateSessionRequest{SessionId: "ses_1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteClientMessage(&mcpPb.McpMessageRaw{Message: `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`, Uuid: "u1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteResponse(&managerPb.McpConnectionStreamResponse{
+		Response: &managerPb.McpConnectionStreamResponse_McpOutput{
+			McpOutput: &mcpPb.McpOutput{Lines: []string{"hello"}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 3 {
+		t.Fatalf("expected 3 lines, got %d", lines)
+	}
+
+	rec, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Session.SessionId != "ses_1" {
+		t.Errorf("unexpected session: %v", rec.Session)
+	}
+	if len(rec.Entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(rec.Entries))
+	}
+
+	messages := rec.ClientMessages()
+	if len(messages) != 1 || messages[0].Uuid != "u1" {
+		t.Errorf("unexpected client messages: %v", messages)
+	}
+
+	responses := rec.Responses()
+	if len(responses) != 1 || responses[0].GetMcpOutput().GetLines()[0] != "hello" {
+		t.Errorf("unexpected responses: %v", responses)
+	}
+}
+
+func TestReadRequiresSession(t *testing.T) {
+	_, err := Read(strings.NewReader(`{"type":"client_message","time":"2024-01-01T00:00:00Z","data":{"message":"{}"}}` + "\n"))
+	if err == nil {
+		t.Fatal("expected an error for a recording without a session")
+	}
+}
+
+func TestReadRejectsUnknownType(t *testing.T) {
+	_, err := Read(strings.NewReader(`{"type":"bogus","time":"2024-01-01T00:00:00Z","data":{}}` + "\n"))
+	if err == nil {
+		t.Fatal("expected an error for an unknown entry type")
+	}
+}
@@ -0,0 +1,221 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DefaultIgnorePaths are ignored in addition to user supplied paths, they
+// cover fields that are expected to change between runs.
+var DefaultIgnorePaths = []string{"**._meta"}
+
+// IgnoreRules match paths in a JSON document that are excluded from diffs.
+//
+// Paths are written as dot separated keys with array indices in brackets,
+// for example `result.content[0].text`. A `*` segment (or `[*]` index)
+// matches any single key or index and `**` matches any number of segments.
+type IgnoreRules struct {
+	patterns [][]string
+}
+
+func NewIgnoreRules(paths ...string) *IgnoreRules {
+	rules := &IgnoreRules{}
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path != "" {
+			rules.patterns = append(rules.patterns, splitPath(path))
+		}
+	}
+
+	return rules
+}
+
+func (r *IgnoreRules) Matches(path []string) bool {
+	if r == nil {
+		return false
+	}
+
+	for _, pattern := range r.patterns {
+		if matchPath(pattern, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Difference struct {
+	Path     string
+	Expected string
+	Actual   string
+}
+
+// Diff compares two JSON documents structurally and returns every
+// difference that is not ignored. Missing values are reported as an empty
+// string on the side they are missing from.
+func Diff(expected, actual []byte, rules *IgnoreRules) ([]Difference, error) {
+	var expectedValue, actualValue any
+
+	if err := json.Unmarshal(expected, &expectedValue); err != nil {
+		return nil, fmt.Errorf("invalid expected JSON: %w", err)
+	}
+	if err := json.Unmarshal(actual, &actualValue); err != nil {
+		return nil, fmt.Errorf("invalid actual JSON: %w", err)
+	}
+
+	res := make([]Difference, 0)
+	diffValues(expectedValue, actualValue, []string{}, rules, &res, true, true)
+	return res, nil
+}
+
+func diffValues(expected, actual any, path []string, rules *IgnoreRules, res *[]Difference, hasExpected, hasActual bool) {
+	if rules.Matches(path) {
+		return
+	}
+
+	if hasExpected && hasActual {
+		expectedMap, expectedIsMap := expected.(map[string]any)
+		actualMap, actualIsMap := actual.(map[string]any)
+		if expectedIsMap && actualIsMap {
+			for _, key := range unionKeys(expectedMap, actualMap) {
+				expectedChild, inExpected := expectedMap[key]
+				actualChild, inActual := actualMap[key]
+				diffValues(expectedChild, actualChild, append(path, key), rules, res, inExpected, inActual)
+			}
+			return
+		}
+
+		expectedList, expectedIsList := expected.([]any)
+		actualList, actualIsList := actual.([]any)
+		if expectedIsList && actualIsList {
+			for i := 0; i < max(len(expectedList), len(actualList)); i++ {
+				var expectedChild, actualChild any
+				if i < len(expectedList) {
+					expectedChild = expectedList[i]
+				}
+				if i < len(actualList) {
+					actualChild = actualList[i]
+				}
+				diffValues(expectedChild, actualChild, append(path, fmt.Sprintf("[%d]", i)), rules, res, i < len(expectedList), i < len(actualList))
+			}
+			return
+		}
+
+		if reflect.DeepEqual(expected, actual) {
+			return
+		}
+	}
+
+	diff := Difference{Path: joinPath(path)}
+	if hasExpected {
+		diff.Expected = encode(expected)
+	}
+	if hasActual {
+		diff.Actual = encode(actual)
+	}
+
+	*res = append(*res, diff)
+}
+
+func unionKeys(a, b map[string]any) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+func encode(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(data)
+}
+
+// splitPath turns `a.b[0].c` into [a b [0] c].
+func splitPath(path string) []string {
+	res := make([]string, 0)
+	for _, part := range strings.Split(path, ".") {
+		for part != "" {
+			open := strings.Index(part, "[")
+			if open == -1 {
+				res = append(res, part)
+				break
+			}
+			if open > 0 {
+				res = append(res, part[:open])
+			}
+
+			close := strings.Index(part[open:], "]")
+			if close == -1 {
+				res = append(res, part[open:])
+				break
+			}
+
+			res = append(res, part[open:open+close+1])
+			part = part[open+close+1:]
+		}
+	}
+
+	return res
+}
+
+func joinPath(path []string) string {
+	var sb strings.Builder
+	for _, segment := range path {
+		if sb.Len() > 0 && !strings.HasPrefix(segment, "[") {
+			sb.WriteString(".")
+		}
+		sb.WriteString(segment)
+	}
+
+	return sb.String()
+}
+
+func matchPath(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchPath(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(path) == 0 {
+		return false
+	}
+
+	if !matchSegment(pattern[0], path[0]) {
+		return false
+	}
+
+	return matchPath(pattern[1:], path[1:])
+}
+
+func matchSegment(pattern, segment string) bool {
+	switch pattern {
+	case "*":
+		return true
+	case "[*]":
+		return strings.HasPrefix(segment, "[")
+	}
+
+	return pattern == segment
+}
@@ -0,0 +1,86 @@
+package replay
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffEqual(t *testing.T) {
+	diffs, err := Diff(
+		[]byte(`{"jsonrpc":"2.0","id":1,"result":{"tools":[{"name":"a"}]}}`),
+		[]byte(`{"id":1,"jsonrpc":"2.0","result":{"tools":[{"name":"a"}]}}`),
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("expected no differences, got %v", diffs)
+	}
+}
+
+func TestDiffReportsChangedMissingAndExtra(t *testing.T) {
+	diffs, err := Diff(
+		[]byte(`{"result":{"content":[{"text":"a"},{"text":"b"}],"removed":true}}`),
+		[]byte(`{"result":{"content":[{"text":"x"}],"added":1}}`),
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Difference{
+		{Path: "result.added", Actual: "1"},
+		{Path: "result.content[0].text", Expected: `"a"`, Actual: `"x"`},
+		{Path: "result.content[1]", Expected: `{"text":"b"}`},
+		{Path: "result.removed", Expected: "true"},
+	}
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("unexpected differences:\n got %+v\nwant %+v", diffs, expected)
+	}
+}
+
+func TestDiffIgnoreRules(t *testing.T) {
+	rules := NewIgnoreRules("result.content[*].text", "**._meta", "result.*.timestamp")
+
+	diffs, err := Diff(
+		[]byte(`{"result":{"content":[{"text":"a","type":"text"}],"_meta":{"a":1},"log":{"timestamp":1}}}`),
+		[]byte(`{"result":{"content":[{"text":"b","type":"text"}],"_meta":{"a":2},"log":{"timestamp":2}}}`),
+		rules,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("expected all differences to be ignored, got %v", diffs)
+	}
+}
+
+func TestSplitPath(t *testing.T) {
+	got := splitPath("result.content[0][1].text")
+	expected := []string{"result", "content", "[0]", "[1]", "text"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("splitPath = %v, expected %v", got, expected)
+	}
+
+	if joinPath(got) != "result.content[0][1].text" {
+		t.Errorf("joinPath did not round trip: %s", joinPath(got))
+	}
+}
+
+func TestWithImageTag(t *testing.T) {
+	cases := map[string]string{
+		"server":                           "server:2",
+		"server:1":                         "server:2",
+		"ghcr.io/acme/server:1":            "ghcr.io/acme/server:2",
+		"localhost:5000/acme/server":       "localhost:5000/acme/server:2",
+		"localhost:5000/acme/server:1":     "localhost:5000/acme/server:2",
+		"acme/server:1@sha256:abcdef01234": "acme/server:2",
+	}
+
+	for image, expected := range cases {
+		if got := WithImageTag(image, "2"); got != expected {
+			t.Errorf("WithImageTag(%q) = %q, expected %q", image, got, expected)
+		}
+	}
+}
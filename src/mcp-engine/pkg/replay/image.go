@@ -0,0 +1,44 @@
+package replay
+
+import (
+	"fmt"
+	"strings"
+
+	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
+	runnerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/runner"
+)
+
+// WithImageTag replaces the tag (and digest) of a container image
+// reference, e.g. `ghcr.io/acme/server:1.0` becomes `ghcr.io/acme/server:1.1`.
+func WithImageTag(image, tag string) string {
+	if at := strings.Index(image, "@"); at != -1 {
+		image = image[:at]
+	}
+
+	nameStart := strings.LastIndex(image, "/") + 1
+	if colon := strings.LastIndex(image[nameStart:], ":"); colon != -1 {
+		image = image[:nameStart+colon]
+	}
+
+	return image + ":" + tag
+}
+
+// ApplyImageTag changes the image tag of a container server config. It
+// fails for remote and lambda servers, which do not run an image.
+func ApplyImageTag(config *managerPb.ServerConfig, tag string) error {
+	var container *runnerPb.RunConfigContainer
+
+	switch c := config.GetConfigType().(type) {
+	case *managerPb.ServerConfig_ContainerRunConfigWithLauncher:
+		container = c.ContainerRunConfigWithLauncher.GetContainer()
+	case *managerPb.ServerConfig_ContainerRunConfigWithContainerArguments:
+		container = c.ContainerRunConfigWithContainerArguments.GetContainer()
+	}
+
+	if container == nil {
+		return fmt.Errorf("an image tag can only be set for container servers")
+	}
+
+	container.DockerImage = WithImageTag(container.DockerImage, tag)
+	return nil
+}
@@ -0,0 +1,169 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	managerPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/manager"
+	mcpPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/mcp"
+	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
+	"github.com/metorial/metorial/mcp-engine/pkg/recording"
+	"github.com/metorial/metorial/modules/util"
+)
+
+// Target is the session recorded client messages are replayed against.
+// Send delivers a single message and returns the MCP responses to it.
+type Target interface {
+	Send(ctx context.Context, message *mcpPb.McpMessageRaw) ([]*mcpPb.McpMessage, error)
+}
+
+// RecordedMessage is a message of a recorded exchange, in the order it
+// was sent or received.
+type RecordedMessage struct {
+	FromClient bool
+	Message    *mcpPb.McpMessage
+}
+
+// BuildExchanges groups recorded messages into client messages and the
+// responses the server sent for them. Responses are matched to the most
+// recent request with the same JSON-RPC ID that has not been answered yet.
+func BuildExchanges(messages []RecordedMessage) []*managerPb.ReplayExchange {
+	exchanges := make([]*managerPb.ReplayExchange, 0)
+	pending := map[string]*managerPb.ReplayExchange{}
+
+	for _, message := range messages {
+		if message.Message == nil {
+			continue
+		}
+
+		if message.FromClient {
+			exchange := &managerPb.ReplayExchange{Request: message.Message.McpMessage}
+			exchanges = append(exchanges, exchange)
+
+			if message.Message.MessageType == mcpPb.McpMessageType_request && message.Message.IdJson != "" {
+				pending[message.Message.IdJson] = exchange
+			}
+			continue
+		}
+
+		if message.Message.MessageType != mcpPb.McpMessageType_response && message.Message.MessageType != mcpPb.McpMessageType_error {
+			continue
+		}
+
+		exchange, ok := pending[message.Message.IdJson]
+		if !ok {
+			continue
+		}
+
+		exchange.Expected = append(exchange.Expected, message.Message)
+		delete(pending, message.Message.IdJson)
+	}
+
+	return exchanges
+}
+
+// Run replays the exchanges in order and diffs every response against the
+// recorded one. Replayed messages get fresh UUIDs so they can be stored
+// next to the recorded ones.
+func Run(ctx context.Context, target Target, exchanges []*managerPb.ReplayExchange, rules *IgnoreRules) *managerPb.ReplaySessionResponse {
+	res := &managerPb.ReplaySessionResponse{
+		Exchanges: make([]*managerPb.ReplayExchange, 0, len(exchanges)),
+	}
+
+	for _, recorded := range exchanges {
+		exchange := &managerPb.ReplayExchange{
+			Request:  recorded.Request,
+			Expected: recorded.Expected,
+		}
+		res.Exchanges = append(res.Exchanges, exchange)
+
+		if ctx.Err() != nil {
+			exchange.Error = ctx.Err().Error()
+			res.Mismatched++
+			continue
+		}
+
+		actual, err := target.Send(ctx, &mcpPb.McpMessageRaw{
+			Message: recorded.Request.Message,
+			Uuid:    util.Must(uuid.NewV7()).String(),
+		})
+		if err != nil {
+			exchange.Error = err.Error()
+			res.Mismatched++
+			continue
+		}
+
+		exchange.Actual = actual
+		exchange.Differences = compareResponses(recorded.Expected, actual, rules)
+
+		if len(exchange.Differences) == 0 {
+			res.Matched++
+		} else {
+			res.Mismatched++
+		}
+	}
+
+	return res
+}
+
+func compareResponses(expected, actual []*mcpPb.McpMessage, rules *IgnoreRules) []*managerPb.ReplayDifference {
+	res := make([]*managerPb.ReplayDifference, 0)
+
+	if len(expected) != len(actual) {
+		res = append(res, &managerPb.ReplayDifference{
+			Path:         "responses",
+			ExpectedJson: fmt.Sprint(len(expected)),
+			ActualJson:   fmt.Sprint(len(actual)),
+		})
+	}
+
+	for i := 0; i < min(len(expected), len(actual)); i++ {
+		diffs, err := Diff(
+			[]byte(expected[i].GetMcpMessage().GetMessage()),
+			[]byte(actual[i].GetMcpMessage().GetMessage()),
+			rules,
+		)
+		if err != nil {
+			res = append(res, &managerPb.ReplayDifference{
+				Path:         fmt.Sprintf("responses[%d]", i),
+				ExpectedJson: expected[i].GetMcpMessage().GetMessage(),
+				ActualJson:   actual[i].GetMcpMessage().GetMessage(),
+			})
+			continue
+		}
+
+		for _, diff := range diffs {
+			res = append(res, &managerPb.ReplayDifference{
+				Path:         diff.Path,
+				ExpectedJson: diff.Expected,
+				ActualJson:   diff.Actual,
+			})
+		}
+	}
+
+	return res
+}
+
+// FromRecording returns the messages of a recording made by the CLI client.
+func FromRecording(rec *recording.Recording) ([]RecordedMessage, error) {
+	res := make([]RecordedMessage, 0, len(rec.Entries))
+
+	for _, entry := range rec.Entries {
+		switch entry.Type {
+		case recording.EntryTypeClientMessage:
+			message, err := mcp.FromPbRawMessage(entry.Message)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, RecordedMessage{FromClient: true, Message: message.ToPbMessage()})
+
+		case recording.EntryTypeResponse:
+			if message := entry.Response.GetMcpMessage(); message != nil {
+				res = append(res, RecordedMessage{Message: message})
+			}
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,99 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	mcpPb "github.com/metorial/metorial/mcp-engine/gen/mcp-engine/mcp"
+)
+
+func message(messageType mcpPb.McpMessageType, idJson, body string) *mcpPb.McpMessage {
+	return &mcpPb.McpMessage{
+		McpMessage:  &mcpPb.McpMessageRaw{Message: body, Uuid: "recorded"},
+		MessageType: messageType,
+		IdJson:      idJson,
+	}
+}
+
+type fakeTarget struct {
+	responses map[string]string
+	sent      []*mcpPb.McpMessageRaw
+}
+
+func (f *fakeTarget) Send(ctx context.Context, msg *mcpPb.McpMessageRaw) ([]*mcpPb.McpMessage, error) {
+	f.sent = append(f.sent, msg)
+
+	response, ok := f.responses[msg.Message]
+	if !ok {
+		return nil, nil
+	}
+	if response == "" {
+		return nil, fmt.Errorf("send failed")
+	}
+
+	return []*mcpPb.McpMessage{message(mcpPb.McpMessageType_response, "", response)}, nil
+}
+
+func TestBuildExchanges(t *testing.T) {
+	exchanges := BuildExchanges([]RecordedMessage{
+		{FromClient: true, Message: message(mcpPb.McpMessageType_request, "1", `req1`)},
+		{FromClient: true, Message: message(mcpPb.McpMessageType_notification, "", `note`)},
+		{FromClient: true, Message: message(mcpPb.McpMessageType_request, "2", `req2`)},
+		{Message: message(mcpPb.McpMessageType_notification, "", `server note`)},
+		{Message: message(mcpPb.McpMessageType_response, "2", `res2`)},
+		{Message: message(mcpPb.McpMessageType_response, "1", `res1`)},
+		{Message: message(mcpPb.McpMessageType_response, "3", `unknown`)},
+	})
+
+	if len(exchanges) != 3 {
+		t.Fatalf("expected 3 exchanges, got %d", len(exchanges))
+	}
+
+	if len(exchanges[0].Expected) != 1 || exchanges[0].Expected[0].McpMessage.Message != "res1" {
+		t.Errorf("unexpected responses for req1: %v", exchanges[0].Expected)
+	}
+	if len(exchanges[1].Expected) != 0 {
+		t.Errorf("notification should not have responses: %v", exchanges[1].Expected)
+	}
+	if len(exchanges[2].Expected) != 1 || exchanges[2].Expected[0].McpMessage.Message != "res2" {
+		t.Errorf("unexpected responses for req2: %v", exchanges[2].Expected)
+	}
+}
+
+func TestRun(t *testing.T) {
+	exchanges := BuildExchanges([]RecordedMessage{
+		{FromClient: true, Message: message(mcpPb.McpMessageType_request, "1", `{"id":1,"method":"a"}`)},
+		{Message: message(mcpPb.McpMessageType_response, "1", `{"id":1,"result":{"value":1}}`)},
+		{FromClient: true, Message: message(mcpPb.McpMessageType_request, "2", `{"id":2,"method":"b"}`)},
+		{Message: message(mcpPb.McpMessageType_response, "2", `{"id":2,"result":{"value":1}}`)},
+		{FromClient: true, Message: message(mcpPb.McpMessageType_request, "3", `{"id":3,"method":"c"}`)},
+		{Message: message(mcpPb.McpMessageType_response, "3", `{"id":3,"result":{}}`)},
+	})
+
+	target := &fakeTarget{responses: map[string]string{
+		`{"id":1,"method":"a"}`: `{"id":1,"result":{"value":1}}`,
+		`{"id":2,"method":"b"}`: `{"id":2,"result":{"value":2}}`,
+		`{"id":3,"method":"c"}`: "",
+	}}
+
+	res := Run(context.Background(), target, exchanges, NewIgnoreRules(DefaultIgnorePaths...))
+
+	if res.Matched != 1 || res.Mismatched != 2 {
+		t.Fatalf("expected 1 match and 2 mismatches, got %d and %d", res.Matched, res.Mismatched)
+	}
+
+	if diffs := res.Exchanges[1].Differences; len(diffs) != 1 || diffs[0].Path != "result.value" {
+		t.Errorf("unexpected differences for exchange 2: %v", diffs)
+	}
+
+	if res.Exchanges[2].Error != "send failed" {
+		t.Errorf("expected send error, got %q", res.Exchanges[2].Error)
+	}
+
+	for _, sent := range target.sent {
+		if sent.Uuid == "recorded" {
+			t.Errorf("replayed messages must get a fresh uuid")
+		}
+	}
+}
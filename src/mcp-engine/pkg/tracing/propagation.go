@@ -0,0 +1,126 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InjectMap serializes the trace context of ctx into a map that can be
+// carried inside protobuf messages, e.g. on long-lived bidi streams where
+// gRPC metadata is only sent once.
+func InjectMap(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	if len(carrier) == 0 {
+		return nil
+	}
+
+	return carrier
+}
+
+func ExtractMap(ctx context.Context, carrier map[string]string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if len(carrier) == 0 {
+		return ctx
+	}
+
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// InjectHeaders sets `traceparent` (and `tracestate`/`baggage`, if present)
+// on outgoing HTTP requests to remote MCP servers.
+func InjectHeaders(ctx context.Context, header http.Header) {
+	if ctx == nil {
+		return
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+func isMcpMetaPropagationEnabled() bool {
+	return os.Getenv("OTEL_MCP_META_PROPAGATION") == "true"
+}
+
+// InjectMcpMeta adds the trace context to the `_meta` of MCP requests, so
+// servers which understand it can continue the trace. This is opt-in via
+// OTEL_MCP_META_PROPAGATION=true, since not every server tolerates
+// unknown `_meta` keys.
+func InjectMcpMeta(ctx context.Context, message *mcp.MCPMessage) *mcp.MCPMessage {
+	if !isMcpMetaPropagationEnabled() || message.MsgType != mcp.RequestType {
+		return message
+	}
+
+	carrier := InjectMap(ctx)
+	if carrier == nil {
+		return message
+	}
+
+	res, err := message.WithMeta(carrier)
+	if err != nil {
+		return message
+	}
+
+	return res
+}
+
+// SpanTracker keeps spans for in-flight MCP requests open until the
+// matching response (by JSON-RPC id) passes through the same hop.
+type SpanTracker struct {
+	spans map[string]trace.Span
+	mutex sync.Mutex
+}
+
+func NewSpanTracker() *SpanTracker {
+	return &SpanTracker{
+		spans: make(map[string]trace.Span),
+	}
+}
+
+func (t *SpanTracker) Track(id string, span trace.Span) {
+	if id == "" {
+		span.End()
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if existing, ok := t.spans[id]; ok {
+		existing.End()
+	}
+
+	t.spans[id] = span
+}
+
+func (t *SpanTracker) End(id string) {
+	t.mutex.Lock()
+	span, ok := t.spans[id]
+	delete(t.spans, id)
+	t.mutex.Unlock()
+
+	if ok {
+		span.End()
+	}
+}
+
+func (t *SpanTracker) EndAll() {
+	t.mutex.Lock()
+	spans := t.spans
+	t.spans = make(map[string]trace.Span)
+	t.mutex.Unlock()
+
+	for _, span := range spans {
+		span.End()
+	}
+}
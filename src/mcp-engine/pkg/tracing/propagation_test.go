@@ -0,0 +1,102 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func testContext() context.Context {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	traceId, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanId, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceId,
+		SpanID:     spanId,
+		TraceFlags: trace.FlagsSampled,
+	}))
+}
+
+func TestInjectExtractMap(t *testing.T) {
+	ctx := testContext()
+
+	carrier := InjectMap(ctx)
+	if carrier["traceparent"] != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Fatalf("unexpected traceparent: %v", carrier)
+	}
+
+	extracted := trace.SpanContextFromContext(ExtractMap(context.Background(), carrier))
+	if extracted.TraceID() != trace.SpanContextFromContext(ctx).TraceID() {
+		t.Errorf("trace id not propagated: %s", extracted.TraceID())
+	}
+	if !extracted.IsRemote() {
+		t.Errorf("expected extracted span context to be remote")
+	}
+
+	if InjectMap(context.Background()) != nil {
+		t.Errorf("expected nil carrier without a span")
+	}
+}
+
+func TestInjectHeaders(t *testing.T) {
+	header := http.Header{}
+	InjectHeaders(testContext(), header)
+
+	if header.Get("traceparent") == "" {
+		t.Errorf("expected traceparent header")
+	}
+}
+
+func TestInjectMcpMeta(t *testing.T) {
+	ctx := testContext()
+
+	msg, err := mcp.ParseMCPMessage("", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"x","_meta":{"progressToken":"p"}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res := InjectMcpMeta(ctx, msg); res != msg {
+		t.Errorf("expected message to be unchanged when disabled")
+	}
+
+	t.Setenv("OTEL_MCP_META_PROPAGATION", "true")
+
+	res := InjectMcpMeta(ctx, msg)
+
+	var payload struct {
+		Id     int `json:"id"`
+		Params struct {
+			Name string            `json:"name"`
+			Meta map[string]string `json:"_meta"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(res.GetRawPayload(), &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if payload.Id != 1 || payload.Params.Name != "x" {
+		t.Errorf("payload changed: %s", res.GetStringPayload())
+	}
+	if payload.Params.Meta["progressToken"] != "p" {
+		t.Errorf("existing _meta lost: %s", res.GetStringPayload())
+	}
+	if payload.Params.Meta["traceparent"] == "" {
+		t.Errorf("traceparent missing from _meta: %s", res.GetStringPayload())
+	}
+	if res.GetUuid() != msg.GetUuid() || res.GetStringId() != msg.GetStringId() {
+		t.Errorf("message identity not preserved")
+	}
+
+	notification, _ := mcp.ParseMCPMessage("", `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	if InjectMcpMeta(ctx, notification) != notification {
+		t.Errorf("notifications should not be modified")
+	}
+}
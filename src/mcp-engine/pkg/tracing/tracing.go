@@ -0,0 +1,141 @@
+package tracing
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/metorial/metorial/mcp-engine/pkg/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/metorial/metorial/mcp-engine"
+
+type ExporterType string
+
+const (
+	ExporterNone   ExporterType = "none"
+	ExporterOtlp   ExporterType = "otlp"
+	ExporterStdout ExporterType = "stdout"
+)
+
+var provider *sdktrace.TracerProvider
+
+func getExporterType() ExporterType {
+	switch strings.ToLower(os.Getenv("OTEL_TRACES_EXPORTER")) {
+	case "otlp":
+		return ExporterOtlp
+	case "stdout", "console":
+		return ExporterStdout
+	default:
+		return ExporterNone
+	}
+}
+
+// InitTracingIfNeeded installs the W3C trace context propagator and, if
+// OTEL_TRACES_EXPORTER is set to "otlp" or "stdout", a tracer provider
+// exporting spans for the given service. The OTLP exporter is configured
+// through the standard OTEL_EXPORTER_OTLP_* environment variables.
+func InitTracingIfNeeded(serviceName string) {
+	// Always propagate, so that a process without an exporter
+	// still forwards the trace context it received.
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exporterType := getExporterType()
+	if exporterType == ExporterNone {
+		return
+	}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch exporterType {
+	case ExporterOtlp:
+		exporter, err = otlptracegrpc.New(context.Background())
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	}
+	if err != nil {
+		log.Fatalf("failed to create %s trace exporter: %v", exporterType, err)
+	}
+
+	serviceNameEnv := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceNameEnv != "" {
+		serviceName = serviceNameEnv
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)),
+	)
+	if err != nil {
+		log.Fatalf("failed to create trace resource: %v", err)
+	}
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	log.Printf("Tracing enabled for %s using %s exporter", serviceName, exporterType)
+}
+
+func ShutdownTracing() {
+	if provider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := provider.Shutdown(ctx); err != nil {
+		log.Printf("Failed to shut down tracer provider: %v", err)
+	}
+}
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return Tracer().Start(ctx, name, opts...)
+}
+
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+func StartMessageSpan(ctx context.Context, name string, message *mcp.MCPMessage, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		attribute.String("mcp.message_type", string(message.MsgType)),
+		attribute.String("mcp.method", message.GetMethod()),
+		attribute.String("mcp.id", message.GetStringId()),
+	)
+
+	return Start(ctx, name, trace.WithAttributes(attrs...))
+}
@@ -0,0 +1,174 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrLockHeld is returned by backends when a lock is held by someone else
+// and they don't wait for it.
+var ErrLockHeld = errors.New("lock already held")
+
+type Handle interface {
+	Unlock(ctx context.Context) error
+}
+
+// Backend takes locks that are shared between processes.
+type Backend interface {
+	// Lock takes the lock. It may either wait for it, or return
+	// ErrLockHeld, in which case the lock is tried again.
+	Lock(ctx context.Context, key string) (Handle, error)
+
+	// TryLock takes the lock or returns ErrLockHeld without waiting.
+	TryLock(ctx context.Context, key string) (Handle, error)
+}
+
+type DistributedOptions struct {
+	// Prefix is prepended to the keys passed to the backend.
+	Prefix string
+
+	// RetryInterval is how long to wait before trying again when the
+	// backend returns ErrLockHeld.
+	RetryInterval time.Duration
+}
+
+func DefaultDistributedOptions() DistributedOptions {
+	return DistributedOptions{
+		RetryInterval: 50 * time.Millisecond,
+	}
+}
+
+// DistributedKeyLock locks keys across processes. Keys are first locked in
+// a local KeyLock, so callers in the same process wait for each other
+// without going to the backend.
+type DistributedKeyLock struct {
+	local   *KeyLock
+	backend Backend
+	options DistributedOptions
+
+	mu      sync.Mutex
+	handles map[string]Handle
+
+	stats waitStats
+}
+
+// NewDistributedKeyLock creates a distributed lock. If local is nil, a new
+// KeyLock is used; pass the one used elsewhere in the process to exclude
+// its holders too.
+func NewDistributedKeyLock(local *KeyLock, backend Backend, options DistributedOptions) *DistributedKeyLock {
+	if local == nil {
+		local = NewKeyLock()
+	}
+
+	if options.RetryInterval <= 0 {
+		options.RetryInterval = DefaultDistributedOptions().RetryInterval
+	}
+
+	return &DistributedKeyLock{
+		local:   local,
+		backend: backend,
+		options: options,
+		handles: make(map[string]Handle),
+	}
+}
+
+// LockContext locks the key locally and in the backend, or returns an error
+// if the context is done or the backend fails first.
+func (dl *DistributedKeyLock) LockContext(ctx context.Context, key string) error {
+	start := time.Now()
+
+	if err := dl.local.LockContext(ctx, key); err != nil {
+		dl.stats.recordFailure(time.Since(start))
+		return err
+	}
+
+	contended := false
+
+	for {
+		handle, err := dl.backend.Lock(ctx, dl.options.Prefix+key)
+		if err == nil {
+			dl.setHandle(key, handle)
+
+			// Backends that wait for locks themselves only show up as slow
+			wait := time.Since(start)
+			dl.stats.record(wait, contended || wait >= dl.options.RetryInterval)
+			return nil
+		}
+
+		if !errors.Is(err, ErrLockHeld) {
+			dl.local.Unlock(key)
+			dl.stats.recordFailure(time.Since(start))
+			return fmt.Errorf("failed to acquire lock for %s: %w", key, err)
+		}
+
+		contended = true
+
+		select {
+		case <-time.After(dl.options.RetryInterval):
+		case <-ctx.Done():
+			dl.local.Unlock(key)
+			dl.stats.recordFailure(time.Since(start))
+			return ctx.Err()
+		}
+	}
+}
+
+// TryLock locks the key if it isn't locked here or in the backend and
+// reports whether it did.
+func (dl *DistributedKeyLock) TryLock(ctx context.Context, key string) (bool, error) {
+	if !dl.local.TryLock(key) {
+		return false, nil
+	}
+
+	handle, err := dl.backend.TryLock(ctx, dl.options.Prefix+key)
+	if err != nil {
+		dl.local.Unlock(key)
+
+		if errors.Is(err, ErrLockHeld) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to acquire lock for %s: %w", key, err)
+	}
+
+	dl.setHandle(key, handle)
+	dl.stats.record(0, false)
+
+	return true, nil
+}
+
+// Unlock releases the key. The local lock is released even if the backend
+// fails to unlock, the backend lock then expires on its own.
+func (dl *DistributedKeyLock) Unlock(ctx context.Context, key string) error {
+	dl.mu.Lock()
+	handle, exists := dl.handles[key]
+	delete(dl.handles, key)
+	dl.mu.Unlock()
+
+	if !exists {
+		panic("unlock of unacquired key")
+	}
+
+	defer dl.local.Unlock(key)
+
+	if err := handle.Unlock(ctx); err != nil {
+		return fmt.Errorf("failed to release lock for %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (dl *DistributedKeyLock) setHandle(key string, handle Handle) {
+	dl.mu.Lock()
+	dl.handles[key] = handle
+	dl.mu.Unlock()
+}
+
+// Stats returns how long callers waited for keys, locally and in the
+// backend.
+func (dl *DistributedKeyLock) Stats() WaitStats {
+	return dl.stats.snapshot()
+}
@@ -0,0 +1,161 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryBackend is a backend that refuses held locks, like the Redis one.
+type memoryBackend struct {
+	mu     sync.Mutex
+	held   map[string]bool
+	failed error
+}
+
+type memoryHandle struct {
+	backend *memoryBackend
+	key     string
+}
+
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{held: make(map[string]bool)}
+}
+
+func (b *memoryBackend) Lock(ctx context.Context, key string) (Handle, error) {
+	return b.TryLock(ctx, key)
+}
+
+func (b *memoryBackend) TryLock(ctx context.Context, key string) (Handle, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failed != nil {
+		return nil, b.failed
+	}
+	if b.held[key] {
+		return nil, ErrLockHeld
+	}
+
+	b.held[key] = true
+	return &memoryHandle{backend: b, key: key}, nil
+}
+
+func (h *memoryHandle) Unlock(ctx context.Context) error {
+	h.backend.mu.Lock()
+	defer h.backend.mu.Unlock()
+
+	delete(h.backend.held, h.key)
+	return nil
+}
+
+func newTestDistributedLock(backend Backend) *DistributedKeyLock {
+	return NewDistributedKeyLock(nil, backend, DistributedOptions{Prefix: "/locks/", RetryInterval: 5 * time.Millisecond})
+}
+
+func TestDistributedKeyLock_SerializesAcrossInstances(t *testing.T) {
+	backend := newMemoryBackend()
+	first := newTestDistributedLock(backend)
+	second := newTestDistributedLock(backend)
+
+	ctx := context.Background()
+
+	if err := first.LockContext(ctx, "foo"); err != nil {
+		t.Fatalf("LockContext failed: %v", err)
+	}
+
+	if ok, err := second.TryLock(ctx, "foo"); ok || err != nil {
+		t.Fatalf("TryLock should fail while held elsewhere, got %v, %v", ok, err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		if err := second.LockContext(ctx, "foo"); err != nil {
+			t.Errorf("LockContext failed: %v", err)
+		}
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Lock should be held by the first instance")
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	if err := first.Unlock(ctx, "foo"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("Unlock did not let the second instance acquire the lock")
+	}
+
+	second.Unlock(ctx, "foo")
+
+	if stats := second.Stats(); stats.Contended != 1 || stats.MaxWait < 30*time.Millisecond {
+		t.Fatalf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestDistributedKeyLock_Timeout(t *testing.T) {
+	backend := newMemoryBackend()
+	backend.held["/locks/foo"] = true
+
+	dl := newTestDistributedLock(backend)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := dl.LockContext(ctx, "foo"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+
+	// The local lock is released again
+	if !dl.local.TryLock("foo") {
+		t.Fatal("Local lock should be released after a timeout")
+	}
+	dl.local.Unlock("foo")
+
+	if stats := dl.Stats(); stats.Failed != 1 {
+		t.Fatalf("Expected a failed wait, got %+v", stats)
+	}
+}
+
+func TestDistributedKeyLock_BackendError(t *testing.T) {
+	backend := newMemoryBackend()
+	backend.failed = errors.New("connection refused")
+
+	local := NewKeyLock()
+	dl := NewDistributedKeyLock(local, backend, DefaultDistributedOptions())
+
+	if err := dl.LockContext(context.Background(), "foo"); err == nil || !errors.Is(err, backend.failed) {
+		t.Fatalf("Expected backend error, got %v", err)
+	}
+
+	if !local.TryLock("foo") {
+		t.Fatal("Local lock should be released after a backend error")
+	}
+	local.Unlock("foo")
+}
+
+func TestDistributedKeyLock_SharesLocalLock(t *testing.T) {
+	local := NewKeyLock()
+	dl := NewDistributedKeyLock(local, newMemoryBackend(), DefaultDistributedOptions())
+
+	local.Lock("foo")
+
+	if ok, _ := dl.TryLock(context.Background(), "foo"); ok {
+		t.Fatal("TryLock should fail while the key is locked locally")
+	}
+
+	local.Unlock("foo")
+
+	if ok, err := dl.TryLock(context.Background(), "foo"); !ok || err != nil {
+		t.Fatalf("TryLock should succeed, got %v, %v", ok, err)
+	}
+	dl.Unlock(context.Background(), "foo")
+}
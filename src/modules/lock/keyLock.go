@@ -1,17 +1,23 @@
 package lock
 
 import (
+	"context"
 	"sync"
+	"time"
 )
 
 type refMutex struct {
-	mu   sync.Mutex
+	// ch holds a value while the key is locked, so waiting for it can be
+	// combined with a context.
+	ch   chan struct{}
 	refs int
 }
 
 type KeyLock struct {
 	mu    sync.Mutex
 	locks map[string]*refMutex
+
+	stats waitStats
 }
 
 func NewKeyLock() *KeyLock {
@@ -20,39 +26,94 @@ func NewKeyLock() *KeyLock {
 	}
 }
 
-func (kl *KeyLock) Lock(key string) {
+func (kl *KeyLock) acquireRef(key string) *refMutex {
 	kl.mu.Lock()
+	defer kl.mu.Unlock()
+
 	rm, exists := kl.locks[key]
 	if !exists {
-		rm = &refMutex{}
+		rm = &refMutex{ch: make(chan struct{}, 1)}
 		kl.locks[key] = rm
 	}
 	rm.refs++
-	kl.mu.Unlock()
 
-	rm.mu.Lock()
+	return rm
+}
+
+func (kl *KeyLock) releaseRef(key string, rm *refMutex) {
+	kl.mu.Lock()
+	defer kl.mu.Unlock()
+
+	rm.refs--
+	if rm.refs == 0 {
+		delete(kl.locks, key)
+	}
+}
+
+// Lock locks the key, waiting as long as it takes.
+func (kl *KeyLock) Lock(key string) {
+	kl.LockContext(context.Background(), key)
+}
+
+// LockContext locks the key, or returns the context's error if it is done
+// before the key could be locked.
+func (kl *KeyLock) LockContext(ctx context.Context, key string) error {
+	rm := kl.acquireRef(key)
+
+	select {
+	case rm.ch <- struct{}{}:
+		kl.stats.record(0, false)
+		return nil
+	default:
+	}
+
+	start := time.Now()
+
+	select {
+	case rm.ch <- struct{}{}:
+		kl.stats.record(time.Since(start), true)
+		return nil
+
+	case <-ctx.Done():
+		kl.releaseRef(key, rm)
+		kl.stats.recordFailure(time.Since(start))
+		return ctx.Err()
+	}
+}
+
+// TryLock locks the key if it isn't locked and reports whether it did.
+func (kl *KeyLock) TryLock(key string) bool {
+	rm := kl.acquireRef(key)
+
+	select {
+	case rm.ch <- struct{}{}:
+		kl.stats.record(0, false)
+		return true
+	default:
+		kl.releaseRef(key, rm)
+		return false
+	}
 }
 
 func (kl *KeyLock) Unlock(key string) {
 	kl.mu.Lock()
 	rm, exists := kl.locks[key]
+	kl.mu.Unlock()
+
 	if !exists {
-		kl.mu.Unlock()
 		panic("unlock of unacquired key")
 	}
 
-	rm.refs--
-	shouldDelete := rm.refs == 0
-	kl.mu.Unlock()
+	select {
+	case <-rm.ch:
+	default:
+		panic("unlock of unacquired key")
+	}
 
-	rm.mu.Unlock()
+	kl.releaseRef(key, rm)
+}
 
-	if shouldDelete {
-		kl.mu.Lock()
-		// double-check refs in case another goroutine acquired the lock again
-		if rm.refs == 0 {
-			delete(kl.locks, key)
-		}
-		kl.mu.Unlock()
-	}
+// Stats returns how long callers waited for keys.
+func (kl *KeyLock) Stats() WaitStats {
+	return kl.stats.snapshot()
 }
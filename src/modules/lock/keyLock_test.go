@@ -1,6 +1,8 @@
 package lock
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -104,3 +106,92 @@ func TestKeyLock_DeletesUnusedLocks(t *testing.T) {
 		t.Fatal("Lock for key should be deleted after last unlock")
 	}
 }
+
+func TestKeyLock_LockContext_Timeout(t *testing.T) {
+	kl := NewKeyLock()
+	key := "foo"
+
+	kl.Lock(key)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := kl.LockContext(ctx, key); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+
+	kl.Unlock(key)
+
+	kl.mu.Lock()
+	_, exists := kl.locks[key]
+	kl.mu.Unlock()
+	if exists {
+		t.Fatal("Lock for key should be deleted after a cancelled wait and the last unlock")
+	}
+
+	stats := kl.Stats()
+	if stats.Acquired != 1 || stats.Failed != 1 || stats.MaxWait < 20*time.Millisecond {
+		t.Fatalf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestKeyLock_LockContext_AcquiresAfterUnlock(t *testing.T) {
+	kl := NewKeyLock()
+	key := "foo"
+
+	kl.Lock(key)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		kl.Unlock(key)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := kl.LockContext(ctx, key); err != nil {
+		t.Fatalf("Expected lock to be acquired, got %v", err)
+	}
+	kl.Unlock(key)
+
+	if stats := kl.Stats(); stats.Contended != 1 {
+		t.Fatalf("Expected one contended lock, got %+v", stats)
+	}
+}
+
+func TestKeyLock_TryLock(t *testing.T) {
+	kl := NewKeyLock()
+	key := "foo"
+
+	if !kl.TryLock(key) {
+		t.Fatal("TryLock should lock a free key")
+	}
+	if kl.TryLock(key) {
+		t.Fatal("TryLock should not lock a locked key")
+	}
+	if !kl.TryLock("bar") {
+		t.Fatal("TryLock should lock a different key")
+	}
+
+	kl.Unlock(key)
+	kl.Unlock("bar")
+
+	if !kl.TryLock(key) {
+		t.Fatal("TryLock should lock an unlocked key")
+	}
+	kl.Unlock(key)
+}
+
+func TestKeyLock_UnlockTwice_Panics(t *testing.T) {
+	kl := NewKeyLock()
+	kl.Lock("foo")
+	kl.Lock("bar")
+	kl.Unlock("foo")
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic when unlocking a key twice")
+		}
+	}()
+	kl.Unlock("foo")
+}
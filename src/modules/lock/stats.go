@@ -0,0 +1,67 @@
+package lock
+
+import (
+	"sync"
+	"time"
+)
+
+type WaitStats struct {
+	// Acquired counts locks taken, Contended the ones that had to wait.
+	Acquired  uint64
+	Contended uint64
+
+	// Failed counts waits that ended without the lock, because the context
+	// was done or the backend failed.
+	Failed uint64
+
+	TotalWait time.Duration
+	MaxWait   time.Duration
+}
+
+// AverageWait returns the average wait of contended locks.
+func (s WaitStats) AverageWait() time.Duration {
+	waits := s.Contended + s.Failed
+	if waits == 0 {
+		return 0
+	}
+
+	return s.TotalWait / time.Duration(waits)
+}
+
+type waitStats struct {
+	mu    sync.Mutex
+	stats WaitStats
+}
+
+func (w *waitStats) record(wait time.Duration, contended bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.stats.Acquired++
+	if contended {
+		w.stats.Contended++
+		w.addWait(wait)
+	}
+}
+
+func (w *waitStats) recordFailure(wait time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.stats.Failed++
+	w.addWait(wait)
+}
+
+func (w *waitStats) addWait(wait time.Duration) {
+	w.stats.TotalWait += wait
+	if wait > w.stats.MaxWait {
+		w.stats.MaxWait = wait
+	}
+}
+
+func (w *waitStats) snapshot() WaitStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.stats
+}
@@ -0,0 +1,482 @@
+package memoryQueue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+var (
+	ErrQueueStopped       = errors.New("queue is stopped")
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+)
+
+type DurableOptions struct {
+	Concurrency int
+
+	// SyncEveryWrite syncs the log to disk after every write. Otherwise it
+	// is synced every SyncInterval. Written jobs survive crashes of the
+	// process either way, syncing protects against crashes of the machine.
+	SyncEveryWrite bool
+	SyncInterval   time.Duration
+
+	// CompactAfter is the number of writes after which the log is
+	// rewritten with only the pending jobs and dead letters.
+	CompactAfter int
+}
+
+func DefaultDurableOptions() DurableOptions {
+	return DurableOptions{
+		Concurrency:  50,
+		SyncInterval: time.Second,
+		CompactAfter: 10000,
+	}
+}
+
+// DeadLetter is a job that failed on all of its tries.
+type DeadLetter[T any] struct {
+	ID       string
+	Job      T
+	Attempts int
+	Error    string
+	FailedAt time.Time
+}
+
+// DurableJobQueue runs jobs like JobQueue, but keeps them in a write-ahead
+// log on disk until they have succeeded. Jobs that were pending or running
+// when the process stopped are run again once the queue is reopened, so
+// handlers have to be idempotent. Jobs that fail on all of their tries are
+// kept as dead letters, which can be inspected and requeued.
+//
+// As jobs are written to disk, they are data passed to a handler rather
+// than functions. So unlike JobQueue.Add, Add takes a value instead of a
+// JobFunc, and it returns an error, as the job may not be written.
+type DurableJobQueue[T any] struct {
+	handler func(T) error
+	options DurableOptions
+	backoff func(retry int) time.Duration
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	wal     *wal
+	state   *walState
+	ready   []string
+	running int
+	stopped bool
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	semaphore chan struct{}
+	workers   sync.WaitGroup
+	stopSync  chan struct{}
+}
+
+// NewDurableJobQueue opens the queue log at path, creating it if needed,
+// and starts running the jobs pending in it.
+func NewDurableJobQueue[T any](path string, handler func(T) error, options DurableOptions) (*DurableJobQueue[T], error) {
+	defaults := DefaultDurableOptions()
+	if options.Concurrency < 1 {
+		options.Concurrency = defaults.Concurrency
+	}
+	if options.SyncInterval <= 0 {
+		options.SyncInterval = defaults.SyncInterval
+	}
+	if options.CompactAfter < 1 {
+		options.CompactAfter = defaults.CompactAfter
+	}
+
+	wal, state, err := openWAL(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	q := &DurableJobQueue[T]{
+		handler:   handler,
+		options:   options,
+		backoff:   defaultBackoff,
+		wal:       wal,
+		state:     state,
+		ctx:       ctx,
+		cancel:    cancel,
+		semaphore: make(chan struct{}, options.Concurrency),
+		stopSync:  make(chan struct{}),
+	}
+	q.cond = sync.NewCond(&q.mu)
+
+	for _, job := range state.pendingJobs() {
+		q.ready = append(q.ready, job.id)
+	}
+
+	if len(q.ready) > 0 {
+		log.Printf("memory-queue: resuming %d pending jobs from %s", len(q.ready), path)
+	}
+
+	go q.dispatcher()
+
+	if !options.SyncEveryWrite {
+		go q.syncer()
+	}
+
+	return q, nil
+}
+
+func newJobID() string {
+	random := make([]byte, 8)
+	rand.Read(random)
+
+	return fmt.Sprintf("%016x-%s", time.Now().UnixNano(), hex.EncodeToString(random))
+}
+
+// Add writes the job to the log and queues it. Once Add returns, the job
+// is run even if the process restarts. If it fails, the job is not queued,
+// though with a partially written log it may still run after a restart.
+func (q *DurableJobQueue[T]) Add(job T, maxTries int) error {
+	if maxTries < 1 {
+		maxTries = 1
+	}
+
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %w", err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.stopped {
+		return ErrQueueStopped
+	}
+
+	id := newJobID()
+
+	err = q.write(walRecord{
+		Op:       walOpAdd,
+		ID:       id,
+		Payload:  payload,
+		MaxTries: maxTries,
+		Time:     time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	q.ready = append(q.ready, id)
+	q.cond.Broadcast()
+
+	return nil
+}
+
+// write appends a record to the log and applies it once it is written,
+// and synced with SyncEveryWrite. The caller holds mu.
+func (q *DurableJobQueue[T]) write(record walRecord) error {
+	if err := q.persist(record); err != nil {
+		return err
+	}
+
+	q.apply(record)
+
+	return nil
+}
+
+// writeLogged writes a record of a job's progress. If it can't be written
+// the job runs again after a restart, which is logged but not fatal. The
+// record is applied either way, so tries are still counted.
+func (q *DurableJobQueue[T]) writeLogged(record walRecord) {
+	if err := q.persist(record); err != nil {
+		log.Printf("memory-queue: %v", err)
+	}
+
+	q.apply(record)
+}
+
+func (q *DurableJobQueue[T]) persist(record walRecord) error {
+	if err := q.wal.append(record); err != nil {
+		return err
+	}
+
+	if q.options.SyncEveryWrite {
+		if err := q.wal.sync(); err != nil {
+			return fmt.Errorf("failed to sync queue log: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// apply updates the state and compacts the log once it is mostly made of
+// records that are no longer needed.
+func (q *DurableJobQueue[T]) apply(record walRecord) {
+	q.state.apply(record)
+
+	live := len(q.state.jobs) + len(q.state.deadLetter)
+	if q.wal.written >= q.options.CompactAfter && q.wal.written > 2*live {
+		if err := q.wal.compact(q.state); err != nil {
+			// The log is still complete, just longer than needed
+			log.Printf("memory-queue: failed to compact queue log: %v", err)
+		}
+	}
+}
+
+func (q *DurableJobQueue[T]) dispatcher() {
+	for {
+		q.mu.Lock()
+		for len(q.ready) == 0 && !q.stopped {
+			q.cond.Wait()
+		}
+
+		if q.stopped {
+			q.mu.Unlock()
+			return
+		}
+
+		id := q.ready[0]
+		q.ready = q.ready[1:]
+
+		job, ok := q.state.jobs[id]
+		if !ok {
+			q.mu.Unlock()
+			continue
+		}
+
+		q.running++
+		q.mu.Unlock()
+
+		select {
+		case q.semaphore <- struct{}{}:
+		case <-q.ctx.Done():
+			q.finish()
+			return
+		}
+
+		// Checked under mu, so Stop waits for every job started
+		q.mu.Lock()
+		if q.stopped {
+			q.mu.Unlock()
+			<-q.semaphore
+			q.finish()
+			return
+		}
+		q.workers.Add(1)
+		q.mu.Unlock()
+
+		go q.runJob(job)
+	}
+}
+
+func (q *DurableJobQueue[T]) finish() {
+	q.mu.Lock()
+	q.running--
+	q.cond.Broadcast()
+	q.mu.Unlock()
+}
+
+func (q *DurableJobQueue[T]) runJob(job *walJob) {
+	defer func() {
+		<-q.semaphore
+		q.workers.Done()
+		q.finish()
+	}()
+
+	var value T
+	if err := json.Unmarshal(job.payload, &value); err != nil {
+		q.mu.Lock()
+		q.writeLogged(walRecord{Op: walOpDead, ID: job.id, Error: fmt.Sprintf("failed to decode job: %v", err), Time: time.Now()})
+		q.mu.Unlock()
+		return
+	}
+
+	for {
+		err := runJobWithRecovery(func() error {
+			return q.handler(value)
+		})
+
+		q.mu.Lock()
+
+		if err == nil {
+			q.writeLogged(walRecord{Op: walOpDone, ID: job.id})
+			q.mu.Unlock()
+			return
+		}
+
+		attempts := job.attempts + 1
+
+		if attempts >= job.maxTries {
+			log.Printf("memory-queue: job %s failed after %d attempts, moved to dead letters: %v", job.id, attempts, err)
+			q.writeLogged(walRecord{Op: walOpAttempt, ID: job.id, Attempts: attempts, Error: err.Error()})
+			q.writeLogged(walRecord{Op: walOpDead, ID: job.id, Error: err.Error(), Time: time.Now()})
+			q.mu.Unlock()
+			return
+		}
+
+		q.writeLogged(walRecord{Op: walOpAttempt, ID: job.id, Attempts: attempts, Error: err.Error()})
+		q.mu.Unlock()
+
+		select {
+		case <-time.After(q.backoff(attempts)):
+		case <-q.ctx.Done():
+			// Still pending in the log, it's retried after a restart
+			return
+		}
+	}
+}
+
+func (q *DurableJobQueue[T]) syncer() {
+	ticker := time.NewTicker(q.options.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-q.stopSync:
+			return
+		case <-ticker.C:
+			q.mu.Lock()
+			if q.wal.file != nil {
+				if err := q.wal.sync(); err != nil {
+					log.Printf("memory-queue: failed to sync queue log: %v", err)
+				}
+			}
+			q.mu.Unlock()
+		}
+	}
+}
+
+// Wait blocks until all queued jobs have finished, or the queue is
+// stopped.
+func (q *DurableJobQueue[T]) Wait() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for (len(q.ready) > 0 && !q.stopped) || q.running > 0 {
+		q.cond.Wait()
+	}
+}
+
+// Stop stops running jobs and closes the log. Running attempts are waited
+// for; jobs that haven't succeeded stay in the log for the next start.
+func (q *DurableJobQueue[T]) Stop() {
+	q.mu.Lock()
+	if q.stopped {
+		q.mu.Unlock()
+		return
+	}
+
+	q.stopped = true
+	q.cancel()
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	q.workers.Wait()
+
+	if !q.options.SyncEveryWrite {
+		close(q.stopSync)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := q.wal.close(); err != nil {
+		log.Printf("memory-queue: failed to close queue log: %v", err)
+	}
+}
+
+// Pending returns the number of jobs that haven't finished yet.
+func (q *DurableJobQueue[T]) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.state.jobs)
+}
+
+// DeadLetters returns the jobs that failed on all of their tries, oldest
+// first.
+func (q *DurableJobQueue[T]) DeadLetters() ([]DeadLetter[T], error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	deadLetters := q.state.deadLetters()
+	res := make([]DeadLetter[T], 0, len(deadLetters))
+
+	for _, dead := range deadLetters {
+		var job T
+		if err := json.Unmarshal(dead.job.payload, &job); err != nil {
+			return nil, fmt.Errorf("failed to decode dead letter %s: %w", dead.job.id, err)
+		}
+
+		res = append(res, DeadLetter[T]{
+			ID:       dead.job.id,
+			Job:      job,
+			Attempts: dead.job.attempts,
+			Error:    dead.job.lastError,
+			FailedAt: dead.failedAt,
+		})
+	}
+
+	return res, nil
+}
+
+// Requeue moves a dead letter back into the queue with its tries reset.
+func (q *DurableJobQueue[T]) Requeue(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.requeue(id)
+}
+
+func (q *DurableJobQueue[T]) requeue(id string) error {
+	if q.stopped {
+		return ErrQueueStopped
+	}
+
+	if _, ok := q.state.deadLetter[id]; !ok {
+		return ErrDeadLetterNotFound
+	}
+
+	if err := q.write(walRecord{Op: walOpRequeue, ID: id}); err != nil {
+		return err
+	}
+
+	q.ready = append(q.ready, id)
+	q.cond.Broadcast()
+
+	return nil
+}
+
+// RequeueAll requeues all dead letters and returns how many there were.
+func (q *DurableJobQueue[T]) RequeueAll() (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	count := 0
+	for _, dead := range q.state.deadLetters() {
+		if err := q.requeue(dead.job.id); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// Discard deletes a dead letter.
+func (q *DurableJobQueue[T]) Discard(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.stopped {
+		return ErrQueueStopped
+	}
+
+	if _, ok := q.state.deadLetter[id]; !ok {
+		return ErrDeadLetterNotFound
+	}
+
+	return q.write(walRecord{Op: walOpDiscard, ID: id})
+}
@@ -0,0 +1,374 @@
+package memoryQueue
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testJob struct {
+	Name string `json:"name"`
+}
+
+func noBackoff(int) time.Duration { return time.Millisecond }
+
+func openTestQueue(t *testing.T, path string, handler func(testJob) error) *DurableJobQueue[testJob] {
+	t.Helper()
+
+	q, err := NewDurableJobQueue(path, handler, DurableOptions{Concurrency: 4})
+	if err != nil {
+		t.Fatalf("failed to open queue: %v", err)
+	}
+	q.backoff = noBackoff
+
+	return q
+}
+
+func TestDurableJobQueue_RunsJobs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	var mu sync.Mutex
+	seen := map[string]bool{}
+
+	q := openTestQueue(t, path, func(job testJob) error {
+		mu.Lock()
+		seen[job.Name] = true
+		mu.Unlock()
+		return nil
+	})
+	defer q.Stop()
+
+	for _, name := range []string{"a", "b", "c"} {
+		if err := q.Add(testJob{Name: name}, 1); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	q.Wait()
+
+	if len(seen) != 3 {
+		t.Errorf("expected 3 jobs to run, got %v", seen)
+	}
+	if q.Pending() != 0 {
+		t.Errorf("expected no pending jobs, got %d", q.Pending())
+	}
+}
+
+func TestDurableJobQueue_DeadLettersAndRequeue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	var fail atomic.Bool
+	fail.Store(true)
+	var calls atomic.Int32
+
+	q := openTestQueue(t, path, func(job testJob) error {
+		calls.Add(1)
+		if fail.Load() {
+			return errors.New("backend unavailable")
+		}
+		return nil
+	})
+	defer q.Stop()
+
+	q.Add(testJob{Name: "flaky"}, 3)
+	q.Wait()
+
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 tries, got %d", calls.Load())
+	}
+
+	deadLetters, err := q.DeadLetters()
+	if err != nil {
+		t.Fatalf("DeadLetters failed: %v", err)
+	}
+	if len(deadLetters) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(deadLetters))
+	}
+
+	dead := deadLetters[0]
+	if dead.Job.Name != "flaky" || dead.Attempts != 3 || dead.Error != "backend unavailable" || dead.FailedAt.IsZero() {
+		t.Errorf("unexpected dead letter: %+v", dead)
+	}
+
+	fail.Store(false)
+	if err := q.Requeue(dead.ID); err != nil {
+		t.Fatalf("Requeue failed: %v", err)
+	}
+	q.Wait()
+
+	if deadLetters, _ := q.DeadLetters(); len(deadLetters) != 0 {
+		t.Errorf("expected no dead letters after requeue, got %d", len(deadLetters))
+	}
+	if err := q.Requeue(dead.ID); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestDurableJobQueue_ResumesAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	var calls atomic.Int32
+	q := openTestQueue(t, path, func(job testJob) error {
+		calls.Add(1)
+		return errors.New("interrupted")
+	})
+
+	// The retry is still waiting when the queue stops
+	q.backoff = func(int) time.Duration { return time.Hour }
+
+	q.Add(testJob{Name: "first"}, 5)
+	q.Add(testJob{Name: "second"}, 5)
+
+	for calls.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	q.Stop()
+
+	if err := q.Add(testJob{Name: "late"}, 1); !errors.Is(err, ErrQueueStopped) {
+		t.Errorf("expected stopped error, got %v", err)
+	}
+
+	var mu sync.Mutex
+	var names []string
+
+	q = openTestQueue(t, path, func(job testJob) error {
+		mu.Lock()
+		names = append(names, job.Name)
+		mu.Unlock()
+		return nil
+	})
+	defer q.Stop()
+
+	q.Wait()
+
+	if len(names) != 2 {
+		t.Errorf("expected both jobs to be resumed, got %v", names)
+	}
+}
+
+func TestDurableJobQueue_DeadLettersSurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	q := openTestQueue(t, path, func(job testJob) error {
+		return errors.New("always fails")
+	})
+	q.Add(testJob{Name: "doomed"}, 2)
+	q.Add(testJob{Name: "discarded"}, 1)
+	q.Wait()
+
+	deadLetters, _ := q.DeadLetters()
+	for _, dead := range deadLetters {
+		if dead.Job.Name == "discarded" {
+			if err := q.Discard(dead.ID); err != nil {
+				t.Fatalf("Discard failed: %v", err)
+			}
+		}
+	}
+	q.Stop()
+
+	q = openTestQueue(t, path, func(job testJob) error { return nil })
+	defer q.Stop()
+
+	deadLetters, err := q.DeadLetters()
+	if err != nil {
+		t.Fatalf("DeadLetters failed: %v", err)
+	}
+	if len(deadLetters) != 1 || deadLetters[0].Job.Name != "doomed" || deadLetters[0].Attempts != 2 {
+		t.Fatalf("unexpected dead letters after restart: %+v", deadLetters)
+	}
+
+	count, err := q.RequeueAll()
+	if err != nil || count != 1 {
+		t.Fatalf("RequeueAll: %d, %v", count, err)
+	}
+	q.Wait()
+
+	if deadLetters, _ := q.DeadLetters(); len(deadLetters) != 0 {
+		t.Errorf("expected no dead letters, got %d", len(deadLetters))
+	}
+}
+
+func TestDurableJobQueue_IgnoresTornLastRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	content := `{"op":"add","id":"1","payload":{"name":"kept"},"max_tries":1}` + "\n" + `{"op":"add","id":"2","pay`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	var mu sync.Mutex
+	q := openTestQueue(t, path, func(job testJob) error {
+		mu.Lock()
+		names = append(names, job.Name)
+		mu.Unlock()
+		return nil
+	})
+	defer q.Stop()
+
+	q.Wait()
+
+	if len(names) != 1 || names[0] != "kept" {
+		t.Errorf("expected only the complete job to run, got %v", names)
+	}
+}
+
+func TestDurableJobQueue_Compacts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	q, err := NewDurableJobQueue(path, func(job testJob) error { return nil }, DurableOptions{Concurrency: 2, CompactAfter: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Stop()
+
+	for i := 0; i < 200; i++ {
+		q.Add(testJob{Name: "job"}, 1)
+	}
+	q.Wait()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 200 adds and 200 dones would be well over 20KB uncompacted
+	if info.Size() > 10000 {
+		t.Errorf("expected log to be compacted, size is %d", info.Size())
+	}
+}
+
+func TestWAL_FailedCompactionKeepsLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	w, state, err := openWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.close()
+
+	// A non-empty directory in place of the log makes the rename fail
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(path, "blocker"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.compact(state); err == nil {
+		t.Fatal("expected compaction to fail")
+	}
+
+	if err := w.append(walRecord{Op: walOpAdd, ID: "a"}); err != nil {
+		t.Fatalf("expected the old log to stay usable, got %v", err)
+	}
+
+	if _, err := os.Stat(path + ".tmp"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the compacted log to be removed, got %v", err)
+	}
+}
+
+// breakLog makes writes to the queue log fail.
+func breakLog(t *testing.T, q *DurableJobQueue[testJob], path string) {
+	t.Helper()
+
+	readOnly, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q.mu.Lock()
+	q.wal.file.Close()
+	q.wal.file = readOnly
+	q.mu.Unlock()
+}
+
+// breakSync makes syncing the queue log fail while writes still succeed,
+// as pipes can't be synced.
+func breakSync(t *testing.T, q *DurableJobQueue[testJob]) {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { reader.Close() })
+
+	q.mu.Lock()
+	q.wal.file.Close()
+	q.wal.file = writer
+	q.mu.Unlock()
+}
+
+func TestDurableJobQueue_FailedAddIsNotQueued(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	var calls atomic.Int32
+	q, err := NewDurableJobQueue(path, func(testJob) error {
+		calls.Add(1)
+		return nil
+	}, DurableOptions{Concurrency: 1, SyncEveryWrite: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Stop()
+
+	breakSync(t, q)
+
+	if err := q.Add(testJob{Name: "unsynced"}, 1); err == nil {
+		t.Fatal("expected Add to fail when the log can't be synced")
+	}
+
+	breakLog(t, q, path)
+
+	if err := q.Add(testJob{Name: "unwritten"}, 1); err == nil {
+		t.Fatal("expected Add to fail when the log can't be written")
+	}
+
+	q.Wait()
+
+	if pending := q.Pending(); pending != 0 {
+		t.Errorf("expected the failed jobs not to be pending, got %d", pending)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("expected the failed jobs not to run, ran %d times", calls.Load())
+	}
+}
+
+func TestDurableJobQueue_UnloggedProgressIsApplied(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	q := openTestQueue(t, path, func(testJob) error {
+		return errors.New("failed")
+	})
+	defer q.Stop()
+
+	q.mu.Lock()
+	if err := q.write(walRecord{Op: walOpAdd, ID: "a", Payload: []byte(`{"name":"a"}`), MaxTries: 2, Time: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	q.mu.Unlock()
+
+	breakLog(t, q, path)
+
+	q.mu.Lock()
+	q.writeLogged(walRecord{Op: walOpAttempt, ID: "a", Attempts: 1, Error: "failed"})
+	q.writeLogged(walRecord{Op: walOpDead, ID: "a", Error: "failed", Time: time.Now()})
+	q.mu.Unlock()
+
+	if pending := q.Pending(); pending != 0 {
+		t.Errorf("expected the job to have left the queue, %d pending", pending)
+	}
+
+	deadLetters, err := q.DeadLetters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deadLetters) != 1 || deadLetters[0].Attempts != 1 {
+		t.Fatalf("expected one dead letter after one attempt, got %+v", deadLetters)
+	}
+}
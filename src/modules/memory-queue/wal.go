@@ -0,0 +1,352 @@
+package memoryQueue
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	walOpAdd     = "add"
+	walOpAttempt = "attempt"
+	walOpDone    = "done"
+	walOpDead    = "dead"
+	walOpRequeue = "requeue"
+	walOpDiscard = "discard"
+)
+
+// walRecord is a line of the write-ahead log. Replaying all records
+// restores the pending jobs and dead letters.
+type walRecord struct {
+	Op       string          `json:"op"`
+	ID       string          `json:"id"`
+	Payload  json.RawMessage `json:"payload,omitempty"`
+	MaxTries int             `json:"max_tries,omitempty"`
+	Attempts int             `json:"attempts,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	Time     time.Time       `json:"time,omitzero"`
+}
+
+type walJob struct {
+	id        string
+	payload   json.RawMessage
+	maxTries  int
+	attempts  int
+	lastError string
+	addedAt   time.Time
+}
+
+type walDeadLetter struct {
+	job      walJob
+	failedAt time.Time
+}
+
+type walState struct {
+	// pending keeps the order jobs were added in
+	pending    []string
+	jobs       map[string]*walJob
+	deadOrder  []string
+	deadLetter map[string]*walDeadLetter
+}
+
+func newWALState() *walState {
+	return &walState{
+		jobs:       make(map[string]*walJob),
+		deadLetter: make(map[string]*walDeadLetter),
+	}
+}
+
+func (s *walState) apply(record walRecord) {
+	switch record.Op {
+	case walOpAdd:
+		s.jobs[record.ID] = &walJob{
+			id:       record.ID,
+			payload:  record.Payload,
+			maxTries: record.MaxTries,
+			attempts: record.Attempts,
+			addedAt:  record.Time,
+		}
+		s.pending = append(s.pending, record.ID)
+
+	case walOpAttempt:
+		if job, ok := s.jobs[record.ID]; ok {
+			job.attempts = record.Attempts
+			job.lastError = record.Error
+		}
+
+	case walOpDone:
+		delete(s.jobs, record.ID)
+
+	case walOpDead:
+		if job, ok := s.jobs[record.ID]; ok {
+			delete(s.jobs, record.ID)
+			job.lastError = record.Error
+			s.deadLetter[record.ID] = &walDeadLetter{job: *job, failedAt: record.Time}
+			s.deadOrder = append(s.deadOrder, record.ID)
+		} else if record.Payload != nil {
+			// Compacted dead letter, written with its payload
+			s.deadLetter[record.ID] = &walDeadLetter{
+				job: walJob{
+					id:        record.ID,
+					payload:   record.Payload,
+					maxTries:  record.MaxTries,
+					attempts:  record.Attempts,
+					lastError: record.Error,
+				},
+				failedAt: record.Time,
+			}
+			s.deadOrder = append(s.deadOrder, record.ID)
+		}
+
+	case walOpRequeue:
+		if dead, ok := s.deadLetter[record.ID]; ok {
+			delete(s.deadLetter, record.ID)
+			job := dead.job
+			job.attempts = 0
+			job.lastError = ""
+			s.jobs[record.ID] = &job
+			s.pending = append(s.pending, record.ID)
+		}
+
+	case walOpDiscard:
+		delete(s.deadLetter, record.ID)
+	}
+}
+
+// records returns the minimal records that restore the state.
+func (s *walState) records() []walRecord {
+	records := make([]walRecord, 0, len(s.jobs)+len(s.deadLetter))
+
+	for _, job := range s.pendingJobs() {
+		records = append(records, walRecord{
+			Op:       walOpAdd,
+			ID:       job.id,
+			Payload:  job.payload,
+			MaxTries: job.maxTries,
+			Attempts: job.attempts,
+			Time:     job.addedAt,
+		})
+	}
+
+	for _, dead := range s.deadLetters() {
+
+		records = append(records, walRecord{
+			Op:       walOpDead,
+			ID:       dead.job.id,
+			Payload:  dead.job.payload,
+			MaxTries: dead.job.maxTries,
+			Attempts: dead.job.attempts,
+			Error:    dead.job.lastError,
+			Time:     dead.failedAt,
+		})
+	}
+
+	return records
+}
+
+// pendingJobs returns the jobs that are still to be run, in order. Entries
+// of finished and requeued jobs are dropped from the order.
+func (s *walState) pendingJobs() []*walJob {
+	seen := make(map[string]bool, len(s.jobs))
+	jobs := make([]*walJob, 0, len(s.jobs))
+
+	for _, id := range s.pending {
+		if job, ok := s.jobs[id]; ok && !seen[id] {
+			seen[id] = true
+			jobs = append(jobs, job)
+		}
+	}
+
+	return jobs
+}
+
+// deadLetters returns the dead letters in the order the jobs failed.
+func (s *walState) deadLetters() []*walDeadLetter {
+	seen := make(map[string]bool, len(s.deadLetter))
+	deadLetters := make([]*walDeadLetter, 0, len(s.deadLetter))
+
+	for _, id := range s.deadOrder {
+		if dead, ok := s.deadLetter[id]; ok && !seen[id] {
+			seen[id] = true
+			deadLetters = append(deadLetters, dead)
+		}
+	}
+
+	return deadLetters
+}
+
+type wal struct {
+	path string
+	file *os.File
+
+	// records written since the last compaction
+	written int
+}
+
+// openWAL replays the log at path, compacts it and opens it for appending.
+func openWAL(path string) (*wal, *walState, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create queue directory: %w", err)
+	}
+
+	state := newWALState()
+
+	file, err := os.Open(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, fmt.Errorf("failed to open queue log: %w", err)
+	}
+
+	if file != nil {
+		err := replayWAL(file, state)
+		file.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	w := &wal{path: path}
+	if err := w.compact(state); err != nil {
+		return nil, nil, err
+	}
+
+	return w, state, nil
+}
+
+func replayWAL(r io.Reader, state *walState) error {
+	reader := bufio.NewReader(r)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var record walRecord
+			if jsonErr := json.Unmarshal(line, &record); jsonErr != nil {
+				if err == io.EOF {
+					// Torn write of the last record before a crash
+					log.Printf("memory-queue: ignoring incomplete last queue log record")
+					return nil
+				}
+
+				return fmt.Errorf("corrupt queue log record: %w", jsonErr)
+			}
+
+			state.apply(record)
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read queue log: %w", err)
+		}
+	}
+}
+
+func (w *wal) append(record walRecord) error {
+	if w.file == nil {
+		return ErrQueueStopped
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode queue log record: %w", err)
+	}
+
+	// A single write per record, so a crash can only tear the last one
+	if _, err := w.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write queue log: %w", err)
+	}
+
+	w.written++
+
+	return nil
+}
+
+func (w *wal) sync() error {
+	return w.file.Sync()
+}
+
+// compact replaces the log with the records of the current state. The
+// compacted log is written to a new file that becomes the live log once
+// it was renamed into place, so on failure the old log stays in use.
+func (w *wal) compact(state *walState) error {
+	tmpPath := w.path + ".tmp"
+
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create queue log: %w", err)
+	}
+
+	discard := func() {
+		tmp.Close()
+		os.Remove(tmpPath)
+	}
+
+	records := state.records()
+
+	writer := bufio.NewWriter(tmp)
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			discard()
+			return fmt.Errorf("failed to encode queue log record: %w", err)
+		}
+
+		writer.Write(data)
+		writer.WriteByte('\n')
+	}
+
+	if err := writer.Flush(); err != nil {
+		discard()
+		return fmt.Errorf("failed to write queue log: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		discard()
+		return fmt.Errorf("failed to sync queue log: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, w.path); err != nil {
+		discard()
+		return fmt.Errorf("failed to replace queue log: %w", err)
+	}
+
+	if w.file != nil {
+		w.file.Close()
+	}
+
+	w.file = tmp
+	w.written = 0
+
+	state.pending = state.pending[:0]
+	state.deadOrder = state.deadOrder[:0]
+	for _, record := range records {
+		switch record.Op {
+		case walOpAdd:
+			state.pending = append(state.pending, record.ID)
+		case walOpDead:
+			state.deadOrder = append(state.deadOrder, record.ID)
+		}
+	}
+
+	return nil
+}
+
+func (w *wal) close() error {
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Sync()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	w.file = nil
+
+	return err
+}
@@ -1,63 +1,278 @@
 package pubsub
 
 import (
+	"errors"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// ErrSubscriberOverflow is reported by Err for subscribers that were
+// disconnected because they didn't keep up with published messages.
+var ErrSubscriberOverflow = errors.New("subscriber disconnected: it did not keep up with published messages")
+
 type BroadcasterReader[T any] interface {
 	Subscribe() chan T
 	Unsubscribe(ch chan T)
+
+	// Err returns why a subscriber channel was closed by the broadcaster,
+	// or nil if it wasn't.
+	Err(ch chan T) error
+}
+
+// OverflowPolicy decides what happens when a subscriber's buffer is full.
+type OverflowPolicy int
+
+const (
+	// OverflowDrop drops the message for the subscriber.
+	OverflowDrop OverflowPolicy = iota
+
+	// OverflowBlock blocks the publisher until the subscriber has room.
+	// Subscribers that don't make room within the block timeout are
+	// disconnected.
+	OverflowBlock
+
+	// OverflowDisconnect disconnects the subscriber.
+	OverflowDisconnect
+)
+
+type BroadcasterOptions struct {
+	BufferSize int
+	Overflow   OverflowPolicy
+
+	// BlockTimeout limits how long OverflowBlock waits for a subscriber.
+	// Zero waits until the subscriber unsubscribes.
+	BlockTimeout time.Duration
+}
+
+func DefaultBroadcasterOptions() BroadcasterOptions {
+	return BroadcasterOptions{
+		BufferSize: 10,
+		Overflow:   OverflowDrop,
+	}
+}
+
+// LosslessBroadcasterOptions never drop messages silently: publishers wait
+// for slow subscribers, and subscribers that stay stuck are disconnected
+// with ErrSubscriberOverflow.
+func LosslessBroadcasterOptions() BroadcasterOptions {
+	return BroadcasterOptions{
+		BufferSize:   100,
+		Overflow:     OverflowBlock,
+		BlockTimeout: 10 * time.Second,
+	}
+}
+
+type BroadcasterStats struct {
+	Subscribers int
+	Published   uint64
+	Delivered   uint64
+
+	// Dropped counts messages not delivered to a subscriber, including the
+	// ones that caused an overflow.
+	Dropped uint64
+
+	// Overflows counts subscribers disconnected for not keeping up.
+	Overflows uint64
+}
+
+type subscriber[T any] struct {
+	ch chan T
+
+	// sendMu is held while sending, so the channel isn't closed mid-send.
+	sendMu sync.Mutex
+	closed bool
+
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+// stop wakes up publishers blocked on the subscriber.
+func (s *subscriber[T]) stop() {
+	s.doneOnce.Do(func() { close(s.done) })
+}
+
+func (s *subscriber[T]) close() {
+	s.stop()
+
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
 }
 
 type Broadcaster[T any] struct {
+	options BroadcasterOptions
+
 	mu          sync.RWMutex
-	subscribers map[chan T]struct{}
+	subscribers map[chan T]*subscriber[T]
+	overflowed  map[chan T]struct{}
+
+	published atomic.Uint64
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+	overflows atomic.Uint64
 }
 
 func NewBroadcaster[T any]() *Broadcaster[T] {
+	return NewBroadcasterWithOptions[T](DefaultBroadcasterOptions())
+}
+
+func NewBroadcasterWithOptions[T any](options BroadcasterOptions) *Broadcaster[T] {
+	if options.BufferSize < 0 {
+		options.BufferSize = 0
+	}
+
 	return &Broadcaster[T]{
-		subscribers: make(map[chan T]struct{}),
+		options:     options,
+		subscribers: make(map[chan T]*subscriber[T]),
+		overflowed:  make(map[chan T]struct{}),
 	}
 }
 
 func (b *Broadcaster[T]) Subscribe() chan T {
-	ch := make(chan T, 10) // buffered to avoid blocking
+	sub := &subscriber[T]{
+		ch:   make(chan T, b.options.BufferSize),
+		done: make(chan struct{}),
+	}
+
 	b.mu.Lock()
-	b.subscribers[ch] = struct{}{}
+	b.subscribers[sub.ch] = sub
 	b.mu.Unlock()
-	return ch
+
+	return sub.ch
 }
 
 func (b *Broadcaster[T]) Unsubscribe(ch chan T) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	delete(b.overflowed, ch)
 
-	_, exists := b.subscribers[ch]
+	sub, exists := b.subscribers[ch]
 	if !exists {
+		b.mu.Unlock()
 		return // channel not subscribed
 	}
 
 	delete(b.subscribers, ch)
-	close(ch)
+	b.mu.Unlock()
+
+	sub.close()
 }
 
-func (b *Broadcaster[T]) Publish(msg T) {
+func (b *Broadcaster[T]) Err(ch chan T) error {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
-	for ch := range b.subscribers {
+
+	if _, overflowed := b.overflowed[ch]; overflowed {
+		return ErrSubscriberOverflow
+	}
+
+	return nil
+}
+
+func (b *Broadcaster[T]) Publish(msg T) {
+	b.published.Add(1)
+
+	b.mu.RLock()
+	subscribers := make([]*subscriber[T], 0, len(b.subscribers))
+	for _, sub := range b.subscribers {
+		subscribers = append(subscribers, sub)
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range subscribers {
+		b.deliver(sub, msg)
+	}
+}
+
+func (b *Broadcaster[T]) deliver(sub *subscriber[T], msg T) {
+	sub.sendMu.Lock()
+	defer sub.sendMu.Unlock()
+
+	if sub.closed {
+		return
+	}
+
+	select {
+	case sub.ch <- msg:
+		b.delivered.Add(1)
+		return
+	default:
+	}
+
+	switch b.options.Overflow {
+	case OverflowDrop:
+		b.dropped.Add(1)
+		return
+
+	case OverflowBlock:
+		var timeout <-chan time.Time
+		if b.options.BlockTimeout > 0 {
+			timer := time.NewTimer(b.options.BlockTimeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+
 		select {
-		case ch <- msg:
-		default:
-			// drop message if subscriber is slow
+		case sub.ch <- msg:
+			b.delivered.Add(1)
+			return
+		case <-sub.done:
+			// Unsubscribed while waiting
+			b.dropped.Add(1)
+			return
+		case <-timeout:
 		}
 	}
+
+	b.disconnect(sub)
+}
+
+// disconnect removes a subscriber that overflowed. The caller holds the
+// subscriber's sendMu.
+func (b *Broadcaster[T]) disconnect(sub *subscriber[T]) {
+	b.dropped.Add(1)
+	b.overflows.Add(1)
+
+	b.mu.Lock()
+	if b.subscribers[sub.ch] == sub {
+		delete(b.subscribers, sub.ch)
+		b.overflowed[sub.ch] = struct{}{}
+	}
+	b.mu.Unlock()
+
+	sub.stop()
+	sub.closed = true
+	close(sub.ch)
+}
+
+func (b *Broadcaster[T]) Stats() BroadcasterStats {
+	b.mu.RLock()
+	subscribers := len(b.subscribers)
+	b.mu.RUnlock()
+
+	return BroadcasterStats{
+		Subscribers: subscribers,
+		Published:   b.published.Load(),
+		Delivered:   b.delivered.Load(),
+		Dropped:     b.dropped.Load(),
+		Overflows:   b.overflows.Load(),
+	}
 }
 
 func (b *Broadcaster[T]) Close() {
 	b.mu.Lock()
-	defer b.mu.Unlock()
-	for ch := range b.subscribers {
-		close(ch)
+	subscribers := make([]*subscriber[T], 0, len(b.subscribers))
+	for ch, sub := range b.subscribers {
+		subscribers = append(subscribers, sub)
 		delete(b.subscribers, ch)
 	}
+	b.mu.Unlock()
+
+	for _, sub := range subscribers {
+		sub.close()
+	}
 }
@@ -1,6 +1,7 @@
 package pubsub
 
 import (
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -145,3 +146,130 @@ func TestBroadcaster_ConcurrentSubscribePublish(t *testing.T) {
 	b.Close()
 	wg.Wait()
 }
+
+func TestBroadcaster_StatsCountDrops(t *testing.T) {
+	b := NewBroadcaster[int]()
+	ch := b.Subscribe()
+	defer b.Unsubscribe(ch)
+
+	for i := 0; i < 15; i++ {
+		b.Publish(i)
+	}
+
+	stats := b.Stats()
+	if stats.Subscribers != 1 || stats.Published != 15 || stats.Delivered != 10 || stats.Dropped != 5 || stats.Overflows != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestBroadcaster_BlockDeliversAll(t *testing.T) {
+	b := NewBroadcasterWithOptions[int](BroadcasterOptions{BufferSize: 2, Overflow: OverflowBlock})
+	ch := b.Subscribe()
+	defer b.Unsubscribe(ch)
+
+	go func() {
+		for i := 0; i < 100; i++ {
+			b.Publish(i)
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		select {
+		case got := <-ch:
+			if got != i {
+				t.Fatalf("expected %d, got %d", i, got)
+			}
+			if i%10 == 0 {
+				time.Sleep(time.Millisecond) // slow subscriber
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for message")
+		}
+	}
+
+	if stats := b.Stats(); stats.Dropped != 0 {
+		t.Errorf("expected no drops, got %+v", stats)
+	}
+}
+
+func TestBroadcaster_BlockTimeoutDisconnects(t *testing.T) {
+	b := NewBroadcasterWithOptions[int](BroadcasterOptions{BufferSize: 1, Overflow: OverflowBlock, BlockTimeout: 20 * time.Millisecond})
+	slow := b.Subscribe()
+	defer b.Unsubscribe(slow)
+	fast := b.Subscribe()
+	defer b.Unsubscribe(fast)
+
+	b.Publish(1)
+	<-fast
+	b.Publish(2) // blocks on slow, then disconnects it
+	<-fast
+
+	if got := <-slow; got != 1 {
+		t.Errorf("expected buffered message 1, got %d", got)
+	}
+	if _, ok := <-slow; ok {
+		t.Error("expected slow subscriber to be disconnected")
+	}
+	if err := b.Err(slow); !errors.Is(err, ErrSubscriberOverflow) {
+		t.Errorf("expected overflow error, got %v", err)
+	}
+	if err := b.Err(fast); err != nil {
+		t.Errorf("expected no error for fast subscriber, got %v", err)
+	}
+
+	stats := b.Stats()
+	if stats.Subscribers != 1 || stats.Overflows != 1 || stats.Dropped != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	b.Unsubscribe(slow)
+	if err := b.Err(slow); err != nil {
+		t.Errorf("expected error to be cleared on unsubscribe, got %v", err)
+	}
+}
+
+func TestBroadcaster_DisconnectOnFull(t *testing.T) {
+	b := NewBroadcasterWithOptions[int](BroadcasterOptions{BufferSize: 1, Overflow: OverflowDisconnect})
+	ch := b.Subscribe()
+	defer b.Unsubscribe(ch)
+
+	b.Publish(1)
+	b.Publish(2)
+	b.Publish(3) // not delivered, already disconnected
+
+	if got := <-ch; got != 1 {
+		t.Errorf("expected 1, got %d", got)
+	}
+	if _, ok := <-ch; ok {
+		t.Error("expected channel to be closed after overflow")
+	}
+	if !errors.Is(b.Err(ch), ErrSubscriberOverflow) {
+		t.Errorf("expected overflow error, got %v", b.Err(ch))
+	}
+}
+
+func TestBroadcaster_UnsubscribeUnblocksPublisher(t *testing.T) {
+	b := NewBroadcasterWithOptions[int](BroadcasterOptions{BufferSize: 1, Overflow: OverflowBlock})
+	ch := b.Subscribe()
+
+	b.Publish(1)
+
+	published := make(chan struct{})
+	go func() {
+		b.Publish(2)
+		close(published)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	b.Unsubscribe(ch)
+
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("publisher still blocked after unsubscribe")
+	}
+
+	if b.Err(ch) != nil {
+		t.Errorf("expected no error after unsubscribe, got %v", b.Err(ch))
+	}
+}
@@ -0,0 +1,138 @@
+package ssrfProtection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+)
+
+// Dialer resolves the host once, validates all of its IPs and connects to
+// a validated IP. Since the hostname isn't resolved again when connecting,
+// DNS rebinding can't swap in a blocked address after validation.
+//
+// DialContext fits both http.Transport.DialContext and
+// websocket.Dialer.NetDialContext.
+type Dialer struct {
+	// Policy defaults to DefaultPolicy().
+	Policy *Policy
+
+	// Resolver defaults to net.DefaultResolver.
+	Resolver *net.Resolver
+
+	Timeout   time.Duration
+	KeepAlive time.Duration
+}
+
+func NewDialer() *Dialer {
+	return &Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 10 * time.Second,
+	}
+}
+
+func (d *Dialer) policy() *Policy {
+	if d.Policy != nil {
+		return d.Policy
+	}
+
+	return DefaultPolicy()
+}
+
+func (d *Dialer) resolver() *net.Resolver {
+	if d.Resolver != nil {
+		return d.Resolver
+	}
+
+	return net.DefaultResolver
+}
+
+func (d *Dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+	default:
+		return nil, fmt.Errorf("connection blocked: unsupported network %s", network)
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse address %s: %w", addr, err)
+	}
+
+	policy := d.policy()
+
+	err = policy.validatePort(port)
+	if err != nil {
+		return nil, fmt.Errorf("connection blocked: %w", err)
+	}
+
+	ips, err := d.resolve(ctx, network, host)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every IP has to be valid, not just the one we connect to, so a
+	// host can't get through by mixing in a valid IP.
+	for _, ip := range ips {
+		err := policy.validateIP(net.IP(ip.AsSlice()))
+		if err != nil {
+			return nil, fmt.Errorf("connection blocked: %w", err)
+		}
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   d.Timeout,
+		KeepAlive: d.KeepAlive,
+	}
+
+	var errs []error
+	for _, ip := range ips {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+
+		errs = append(errs, err)
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// resolve returns the IPs of a host, unmapped so IPv4 addresses in IPv6
+// form are checked against the IPv4 ranges.
+func (d *Dialer) resolve(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	var ips []netip.Addr
+
+	if ip, err := netip.ParseAddr(host); err == nil {
+		ips = []netip.Addr{ip}
+	} else {
+		lookupNetwork := "ip"
+		switch network {
+		case "tcp4":
+			lookupNetwork = "ip4"
+		case "tcp6":
+			lookupNetwork = "ip6"
+		}
+
+		ips, err = d.resolver().LookupNetIP(ctx, lookupNetwork, host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", host, err)
+		}
+	}
+
+	for i, ip := range ips {
+		ips[i] = ip.Unmap().WithZone("")
+	}
+
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("failed to resolve %s: no addresses", host)
+	}
+
+	return ips, nil
+}
@@ -0,0 +1,122 @@
+package ssrfProtection
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func listen(t *testing.T) (string, int) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	return listener.Addr().String(), listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestDialer_BlocksPrivateAddresses(t *testing.T) {
+	addr, port := listen(t)
+	dialer := &Dialer{Policy: &Policy{AllowedPorts: []int{port}}}
+
+	for _, target := range []string{
+		addr,
+		net.JoinHostPort("localhost", strconv.Itoa(port)),
+		net.JoinHostPort("::ffff:127.0.0.1", strconv.Itoa(port)),
+	} {
+		conn, err := dialer.DialContext(context.Background(), "tcp", target)
+		if err == nil {
+			conn.Close()
+			t.Errorf("expected dial to %s to be blocked", target)
+			continue
+		}
+		if !strings.Contains(err.Error(), "connection blocked") {
+			t.Errorf("expected blocked error for %s, got %v", target, err)
+		}
+	}
+}
+
+func TestDialer_BlocksPorts(t *testing.T) {
+	dialer := &Dialer{Policy: &Policy{}}
+
+	_, err := dialer.DialContext(context.Background(), "tcp", "93.184.215.14:22")
+	if err == nil || !strings.Contains(err.Error(), "port 22 is blocked") {
+		t.Errorf("expected port to be blocked, got %v", err)
+	}
+}
+
+func TestDialer_AllowlistedNetwork(t *testing.T) {
+	_, port := listen(t)
+
+	networks, err := ParseNetworks("127.0.0.0/8, ::1")
+	if err != nil {
+		t.Fatalf("ParseNetworks failed: %v", err)
+	}
+
+	dialer := &Dialer{Policy: &Policy{AllowedNetworks: networks, AllowedPorts: []int{port}}}
+
+	conn, err := dialer.DialContext(context.Background(), "tcp4", net.JoinHostPort("localhost", strconv.Itoa(port)))
+	if err != nil {
+		t.Fatalf("expected allowlisted dial to succeed, got %v", err)
+	}
+	defer conn.Close()
+
+	// The connection goes to the IP that was validated
+	if host, _, _ := net.SplitHostPort(conn.RemoteAddr().String()); host != "127.0.0.1" {
+		t.Errorf("expected connection to 127.0.0.1, got %s", host)
+	}
+}
+
+func TestParseNetworks(t *testing.T) {
+	networks, err := ParseNetworks("10.1.0.0/16,192.168.1.5, fd00::/8")
+	if err != nil {
+		t.Fatalf("ParseNetworks failed: %v", err)
+	}
+
+	want := []string{"10.1.0.0/16", "192.168.1.5/32", "fd00::/8"}
+	if len(networks) != len(want) {
+		t.Fatalf("expected %d networks, got %d", len(want), len(networks))
+	}
+	for i, network := range networks {
+		if network.String() != want[i] {
+			t.Errorf("expected %s, got %s", want[i], network)
+		}
+	}
+
+	if _, err := ParseNetworks("10.0.0.0/33"); err == nil {
+		t.Error("expected invalid CIDR to fail")
+	}
+	if _, err := ParseNetworks("not-an-ip"); err == nil {
+		t.Error("expected invalid IP to fail")
+	}
+}
+
+func TestPolicy_ValidateURL(t *testing.T) {
+	networks, _ := ParseNetworks("127.0.0.1")
+	policy := &Policy{AllowedNetworks: networks, AllowedPorts: []int{8080}}
+
+	if err := policy.ValidateURL("http://127.0.0.1:8080/mcp"); err != nil {
+		t.Errorf("expected allowlisted URL to be valid, got %v", err)
+	}
+	if err := (&Policy{}).ValidateURL("http://127.0.0.1:8080/mcp"); err == nil {
+		t.Error("expected URL to be blocked without allowlist")
+	}
+	if err := policy.ValidateURL("http://127.0.0.2/mcp"); err == nil {
+		t.Error("expected URL outside of the allowlist to be blocked")
+	}
+}
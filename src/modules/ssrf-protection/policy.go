@@ -0,0 +1,152 @@
+package ssrfProtection
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Policy decides which addresses may be connected to. On top of the
+// default rules it allows networks and ports that were allowlisted
+// deliberately, e.g. the private services of on-prem deployments.
+type Policy struct {
+	// AllowedNetworks may be connected to even if they are in a blocked
+	// range.
+	AllowedNetworks []*net.IPNet
+
+	// AllowedPorts may be connected to in addition to 80 and 443.
+	AllowedPorts []int
+}
+
+func (p *Policy) validateIP(ip net.IP) error {
+	if p != nil {
+		for _, network := range p.AllowedNetworks {
+			if network.Contains(ip) {
+				return nil
+			}
+		}
+	}
+
+	return validateIP(ip)
+}
+
+func (p *Policy) validatePort(port string) error {
+	if p != nil && port != "" {
+		if portNum, err := strconv.Atoi(port); err == nil {
+			for _, allowed := range p.AllowedPorts {
+				if portNum == allowed {
+					return nil
+				}
+			}
+		}
+	}
+
+	return validatePort(port)
+}
+
+// ParseNetworks parses a comma separated list of CIDRs and IPs.
+func ParseNetworks(value string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP %q", entry)
+			}
+
+			bits := 128
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", entry, err)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// ParsePorts parses a comma separated list of ports.
+func ParsePorts(value string) ([]int, error) {
+	var ports []int
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		port, err := strconv.Atoi(entry)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("invalid port %q", entry)
+		}
+
+		ports = append(ports, port)
+	}
+
+	return ports, nil
+}
+
+// PolicyFromEnv reads the allowlists from SSRF_ALLOWED_NETWORKS and
+// SSRF_ALLOWED_PORTS, both comma separated.
+func PolicyFromEnv() (*Policy, error) {
+	networks, err := ParseNetworks(os.Getenv("SSRF_ALLOWED_NETWORKS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSRF_ALLOWED_NETWORKS: %w", err)
+	}
+
+	ports, err := ParsePorts(os.Getenv("SSRF_ALLOWED_PORTS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSRF_ALLOWED_PORTS: %w", err)
+	}
+
+	return &Policy{
+		AllowedNetworks: networks,
+		AllowedPorts:    ports,
+	}, nil
+}
+
+var defaultPolicy atomic.Pointer[Policy]
+
+var loadDefaultPolicy = sync.OnceFunc(func() {
+	policy, err := PolicyFromEnv()
+	if err != nil {
+		// Nothing is allowlisted rather than failing open
+		log.Printf("ssrf-protection: %v, ignoring allowlists", err)
+		policy = &Policy{}
+	}
+
+	defaultPolicy.Store(policy)
+})
+
+// DefaultPolicy is the policy of ValidateURL and the secure clients. It is
+// read from the environment unless set with SetDefaultPolicy.
+func DefaultPolicy() *Policy {
+	loadDefaultPolicy()
+	return defaultPolicy.Load()
+}
+
+func SetDefaultPolicy(policy *Policy) {
+	loadDefaultPolicy()
+	defaultPolicy.Store(policy)
+}
@@ -10,9 +10,17 @@ import (
 var allowedSchemes = map[string]bool{
 	"http":  true,
 	"https": true,
+	"ws":    true,
+	"wss":   true,
 }
 
+// ValidateURL checks a URL against the default policy. Connections should
+// still go through a Dialer, the hostname may resolve differently later.
 func ValidateURL(rawURL string) error {
+	return DefaultPolicy().ValidateURL(rawURL)
+}
+
+func (p *Policy) ValidateURL(rawURL string) error {
 	uri, err := url.Parse(rawURL)
 	if err != nil {
 		return fmt.Errorf("invalid URL format: %w", err)
@@ -37,7 +45,7 @@ func ValidateURL(rawURL string) error {
 	}
 
 	for _, ip := range ips {
-		err := validateIP(ip)
+		err := p.validateIP(ip)
 		if err != nil {
 			return fmt.Errorf("invalid IP %s for hostname %s: %w", ip.String(), hostname, err)
 		}
@@ -45,19 +53,19 @@ func ValidateURL(rawURL string) error {
 
 	port := uri.Port()
 	if port != "" {
-		err := validatePort(port)
+		err := p.validatePort(port)
 		if err != nil {
 			return err
 		}
 	} else {
 		switch strings.ToLower(uri.Scheme) {
-		case "http":
-			err := validatePort("80")
+		case "http", "ws":
+			err := p.validatePort("80")
 			if err != nil {
 				return err
 			}
-		case "https":
-			err := validatePort("443")
+		case "https", "wss":
+			err := p.validatePort("443")
 			if err != nil {
 				return err
 			}
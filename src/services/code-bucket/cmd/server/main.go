@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 
 	"github.com/joho/godotenv"
@@ -60,6 +61,12 @@ func main() {
 		fs.WithS3Bucket(awsBucket),
 		fs.WithAwsEndpoint(awsEndpoint),
 		fs.WithRedisURL(redisURL),
+		fs.WithQuota(fs.Quota{
+			MaxTotalBytes: getEnvInt64OrDefault("CODE_BUCKET_MAX_BUCKET_BYTES", 512*1024*1024),
+			MaxFiles:      getEnvInt64OrDefault("CODE_BUCKET_MAX_BUCKET_FILES", 20000),
+			MaxFileBytes:  getEnvInt64OrDefault("CODE_BUCKET_MAX_FILE_BYTES", 50*1024*1024),
+		}),
+		fs.WithMaxArchiveBytes(getEnvInt64OrDefault("CODE_BUCKET_MAX_ARCHIVE_BYTES", 256*1024*1024)),
 	)
 
 	service.Start(httpAddress, rpcAddress)
@@ -91,3 +98,16 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return value
 }
+
+func getEnvInt64OrDefault(key string, defaultValue int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Fatalf("Environment variable %s must be an integer: %v", key, err)
+	}
+	return parsed
+}
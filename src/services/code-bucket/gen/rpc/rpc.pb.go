@@ -449,6 +449,90 @@ func (x *CreateBucketFromGithubRequest) GetToken() string {
 	return ""
 }
 
+type CreateBucketFromGitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NewBucketId   string                 `protobuf:"bytes,1,opt,name=new_bucket_id,json=newBucketId,proto3" json:"new_bucket_id,omitempty"`
+	RemoteUrl     string                 `protobuf:"bytes,2,opt,name=remote_url,json=remoteUrl,proto3" json:"remote_url,omitempty"` // HTTPS URL of the repository
+	Ref           string                 `protobuf:"bytes,3,opt,name=ref,proto3" json:"ref,omitempty"`                              // Optional branch, tag or commit; defaults to the default branch
+	Path          string                 `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`                            // Optional subdirectory to import
+	Username      string                 `protobuf:"bytes,5,opt,name=username,proto3" json:"username,omitempty"`                    // Optional, defaults to "git" when a token is set
+	Token         string                 `protobuf:"bytes,6,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBucketFromGitRequest) Reset() {
+	*x = CreateBucketFromGitRequest{}
+	mi := &file_rpc_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBucketFromGitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBucketFromGitRequest) ProtoMessage() {}
+
+func (x *CreateBucketFromGitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBucketFromGitRequest.ProtoReflect.Descriptor instead.
+func (*CreateBucketFromGitRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateBucketFromGitRequest) GetNewBucketId() string {
+	if x != nil {
+		return x.NewBucketId
+	}
+	return ""
+}
+
+func (x *CreateBucketFromGitRequest) GetRemoteUrl() string {
+	if x != nil {
+		return x.RemoteUrl
+	}
+	return ""
+}
+
+func (x *CreateBucketFromGitRequest) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (x *CreateBucketFromGitRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *CreateBucketFromGitRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateBucketFromGitRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
 type CreateBucketResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -457,7 +541,7 @@ type CreateBucketResponse struct {
 
 func (x *CreateBucketResponse) Reset() {
 	*x = CreateBucketResponse{}
-	mi := &file_rpc_proto_msgTypes[7]
+	mi := &file_rpc_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -469,7 +553,7 @@ func (x *CreateBucketResponse) String() string {
 func (*CreateBucketResponse) ProtoMessage() {}
 
 func (x *CreateBucketResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_proto_msgTypes[7]
+	mi := &file_rpc_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -482,7 +566,7 @@ func (x *CreateBucketResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateBucketResponse.ProtoReflect.Descriptor instead.
 func (*CreateBucketResponse) Descriptor() ([]byte, []int) {
-	return file_rpc_proto_rawDescGZIP(), []int{7}
+	return file_rpc_proto_rawDescGZIP(), []int{8}
 }
 
 type GetBucketTokenRequest struct {
@@ -496,7 +580,7 @@ type GetBucketTokenRequest struct {
 
 func (x *GetBucketTokenRequest) Reset() {
 	*x = GetBucketTokenRequest{}
-	mi := &file_rpc_proto_msgTypes[8]
+	mi := &file_rpc_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -508,7 +592,7 @@ func (x *GetBucketTokenRequest) String() string {
 func (*GetBucketTokenRequest) ProtoMessage() {}
 
 func (x *GetBucketTokenRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_proto_msgTypes[8]
+	mi := &file_rpc_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -521,7 +605,7 @@ func (x *GetBucketTokenRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBucketTokenRequest.ProtoReflect.Descriptor instead.
 func (*GetBucketTokenRequest) Descriptor() ([]byte, []int) {
-	return file_rpc_proto_rawDescGZIP(), []int{8}
+	return file_rpc_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetBucketTokenRequest) GetBucketId() string {
@@ -554,7 +638,7 @@ type GetBucketTokenResponse struct {
 
 func (x *GetBucketTokenResponse) Reset() {
 	*x = GetBucketTokenResponse{}
-	mi := &file_rpc_proto_msgTypes[9]
+	mi := &file_rpc_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -566,7 +650,7 @@ func (x *GetBucketTokenResponse) String() string {
 func (*GetBucketTokenResponse) ProtoMessage() {}
 
 func (x *GetBucketTokenResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_proto_msgTypes[9]
+	mi := &file_rpc_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -579,7 +663,7 @@ func (x *GetBucketTokenResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBucketTokenResponse.ProtoReflect.Descriptor instead.
 func (*GetBucketTokenResponse) Descriptor() ([]byte, []int) {
-	return file_rpc_proto_rawDescGZIP(), []int{9}
+	return file_rpc_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *GetBucketTokenResponse) GetToken() string {
@@ -593,13 +677,14 @@ type GetBucketFileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	BucketId      string                 `protobuf:"bytes,1,opt,name=bucket_id,json=bucketId,proto3" json:"bucket_id,omitempty"`
 	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Snapshot      string                 `protobuf:"bytes,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"` // Optional snapshot ID or name
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetBucketFileRequest) Reset() {
 	*x = GetBucketFileRequest{}
-	mi := &file_rpc_proto_msgTypes[10]
+	mi := &file_rpc_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -611,7 +696,7 @@ func (x *GetBucketFileRequest) String() string {
 func (*GetBucketFileRequest) ProtoMessage() {}
 
 func (x *GetBucketFileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_proto_msgTypes[10]
+	mi := &file_rpc_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -624,7 +709,7 @@ func (x *GetBucketFileRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBucketFileRequest.ProtoReflect.Descriptor instead.
 func (*GetBucketFileRequest) Descriptor() ([]byte, []int) {
-	return file_rpc_proto_rawDescGZIP(), []int{10}
+	return file_rpc_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *GetBucketFileRequest) GetBucketId() string {
@@ -641,6 +726,13 @@ func (x *GetBucketFileRequest) GetPath() string {
 	return ""
 }
 
+func (x *GetBucketFileRequest) GetSnapshot() string {
+	if x != nil {
+		return x.Snapshot
+	}
+	return ""
+}
+
 type GetBucketFileResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Content       *FileContent           `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
@@ -650,7 +742,7 @@ type GetBucketFileResponse struct {
 
 func (x *GetBucketFileResponse) Reset() {
 	*x = GetBucketFileResponse{}
-	mi := &file_rpc_proto_msgTypes[11]
+	mi := &file_rpc_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -662,7 +754,7 @@ func (x *GetBucketFileResponse) String() string {
 func (*GetBucketFileResponse) ProtoMessage() {}
 
 func (x *GetBucketFileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_proto_msgTypes[11]
+	mi := &file_rpc_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -675,7 +767,7 @@ func (x *GetBucketFileResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBucketFileResponse.ProtoReflect.Descriptor instead.
 func (*GetBucketFileResponse) Descriptor() ([]byte, []int) {
-	return file_rpc_proto_rawDescGZIP(), []int{11}
+	return file_rpc_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *GetBucketFileResponse) GetContent() *FileContent {
@@ -688,14 +780,15 @@ func (x *GetBucketFileResponse) GetContent() *FileContent {
 type GetBucketFilesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	BucketId      string                 `protobuf:"bytes,1,opt,name=bucket_id,json=bucketId,proto3" json:"bucket_id,omitempty"`
-	Prefix        string                 `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"` // Optional filter
+	Prefix        string                 `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`     // Optional filter
+	Snapshot      string                 `protobuf:"bytes,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"` // Optional snapshot ID or name
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetBucketFilesRequest) Reset() {
 	*x = GetBucketFilesRequest{}
-	mi := &file_rpc_proto_msgTypes[12]
+	mi := &file_rpc_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -707,7 +800,7 @@ func (x *GetBucketFilesRequest) String() string {
 func (*GetBucketFilesRequest) ProtoMessage() {}
 
 func (x *GetBucketFilesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_proto_msgTypes[12]
+	mi := &file_rpc_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -720,7 +813,7 @@ func (x *GetBucketFilesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBucketFilesRequest.ProtoReflect.Descriptor instead.
 func (*GetBucketFilesRequest) Descriptor() ([]byte, []int) {
-	return file_rpc_proto_rawDescGZIP(), []int{12}
+	return file_rpc_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *GetBucketFilesRequest) GetBucketId() string {
@@ -737,16 +830,24 @@ func (x *GetBucketFilesRequest) GetPrefix() string {
 	return ""
 }
 
+func (x *GetBucketFilesRequest) GetSnapshot() string {
+	if x != nil {
+		return x.Snapshot
+	}
+	return ""
+}
+
 type GetBucketFilesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Files         []*FileInfo            `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	Revision      string                 `protobuf:"bytes,2,opt,name=revision,proto3" json:"revision,omitempty"` // Empty when reading a snapshot
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetBucketFilesResponse) Reset() {
 	*x = GetBucketFilesResponse{}
-	mi := &file_rpc_proto_msgTypes[13]
+	mi := &file_rpc_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -758,7 +859,7 @@ func (x *GetBucketFilesResponse) String() string {
 func (*GetBucketFilesResponse) ProtoMessage() {}
 
 func (x *GetBucketFilesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_proto_msgTypes[13]
+	mi := &file_rpc_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -771,7 +872,7 @@ func (x *GetBucketFilesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBucketFilesResponse.ProtoReflect.Descriptor instead.
 func (*GetBucketFilesResponse) Descriptor() ([]byte, []int) {
-	return file_rpc_proto_rawDescGZIP(), []int{13}
+	return file_rpc_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *GetBucketFilesResponse) GetFiles() []*FileInfo {
@@ -781,6 +882,13 @@ func (x *GetBucketFilesResponse) GetFiles() []*FileInfo {
 	return nil
 }
 
+func (x *GetBucketFilesResponse) GetRevision() string {
+	if x != nil {
+		return x.Revision
+	}
+	return ""
+}
+
 type GetBucketFilesWithContentResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Files         []*FileContent         `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
@@ -790,7 +898,7 @@ type GetBucketFilesWithContentResponse struct {
 
 func (x *GetBucketFilesWithContentResponse) Reset() {
 	*x = GetBucketFilesWithContentResponse{}
-	mi := &file_rpc_proto_msgTypes[14]
+	mi := &file_rpc_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -802,7 +910,7 @@ func (x *GetBucketFilesWithContentResponse) String() string {
 func (*GetBucketFilesWithContentResponse) ProtoMessage() {}
 
 func (x *GetBucketFilesWithContentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_proto_msgTypes[14]
+	mi := &file_rpc_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -815,7 +923,7 @@ func (x *GetBucketFilesWithContentResponse) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use GetBucketFilesWithContentResponse.ProtoReflect.Descriptor instead.
 func (*GetBucketFilesWithContentResponse) Descriptor() ([]byte, []int) {
-	return file_rpc_proto_rawDescGZIP(), []int{14}
+	return file_rpc_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *GetBucketFilesWithContentResponse) GetFiles() []*FileContent {
@@ -835,7 +943,7 @@ type GetBucketFilesAsZipRequest struct {
 
 func (x *GetBucketFilesAsZipRequest) Reset() {
 	*x = GetBucketFilesAsZipRequest{}
-	mi := &file_rpc_proto_msgTypes[15]
+	mi := &file_rpc_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -847,7 +955,7 @@ func (x *GetBucketFilesAsZipRequest) String() string {
 func (*GetBucketFilesAsZipRequest) ProtoMessage() {}
 
 func (x *GetBucketFilesAsZipRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_proto_msgTypes[15]
+	mi := &file_rpc_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -860,7 +968,7 @@ func (x *GetBucketFilesAsZipRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBucketFilesAsZipRequest.ProtoReflect.Descriptor instead.
 func (*GetBucketFilesAsZipRequest) Descriptor() ([]byte, []int) {
-	return file_rpc_proto_rawDescGZIP(), []int{15}
+	return file_rpc_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GetBucketFilesAsZipRequest) GetBucketId() string {
@@ -887,7 +995,7 @@ type GetBucketFilesAsZipResponse struct {
 
 func (x *GetBucketFilesAsZipResponse) Reset() {
 	*x = GetBucketFilesAsZipResponse{}
-	mi := &file_rpc_proto_msgTypes[16]
+	mi := &file_rpc_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -899,7 +1007,7 @@ func (x *GetBucketFilesAsZipResponse) String() string {
 func (*GetBucketFilesAsZipResponse) ProtoMessage() {}
 
 func (x *GetBucketFilesAsZipResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_proto_msgTypes[16]
+	mi := &file_rpc_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -912,7 +1020,7 @@ func (x *GetBucketFilesAsZipResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBucketFilesAsZipResponse.ProtoReflect.Descriptor instead.
 func (*GetBucketFilesAsZipResponse) Descriptor() ([]byte, []int) {
-	return file_rpc_proto_rawDescGZIP(), []int{16}
+	return file_rpc_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GetBucketFilesAsZipResponse) GetDownloadUrl() string {
@@ -942,7 +1050,7 @@ type ExportBucketToGithubRequest struct {
 
 func (x *ExportBucketToGithubRequest) Reset() {
 	*x = ExportBucketToGithubRequest{}
-	mi := &file_rpc_proto_msgTypes[17]
+	mi := &file_rpc_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -954,7 +1062,7 @@ func (x *ExportBucketToGithubRequest) String() string {
 func (*ExportBucketToGithubRequest) ProtoMessage() {}
 
 func (x *ExportBucketToGithubRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_proto_msgTypes[17]
+	mi := &file_rpc_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -967,7 +1075,7 @@ func (x *ExportBucketToGithubRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExportBucketToGithubRequest.ProtoReflect.Descriptor instead.
 func (*ExportBucketToGithubRequest) Descriptor() ([]byte, []int) {
-	return file_rpc_proto_rawDescGZIP(), []int{17}
+	return file_rpc_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *ExportBucketToGithubRequest) GetBucketId() string {
@@ -1013,7 +1121,7 @@ type ExportBucketToGithubResponse struct {
 
 func (x *ExportBucketToGithubResponse) Reset() {
 	*x = ExportBucketToGithubResponse{}
-	mi := &file_rpc_proto_msgTypes[18]
+	mi := &file_rpc_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1025,7 +1133,7 @@ func (x *ExportBucketToGithubResponse) String() string {
 func (*ExportBucketToGithubResponse) ProtoMessage() {}
 
 func (x *ExportBucketToGithubResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_proto_msgTypes[18]
+	mi := &file_rpc_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1038,93 +1146,1461 @@ func (x *ExportBucketToGithubResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExportBucketToGithubResponse.ProtoReflect.Descriptor instead.
 func (*ExportBucketToGithubResponse) Descriptor() ([]byte, []int) {
-	return file_rpc_proto_rawDescGZIP(), []int{18}
+	return file_rpc_proto_rawDescGZIP(), []int{19}
 }
 
-var File_rpc_proto protoreflect.FileDescriptor
+type ExportBucketToGitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BucketId      string                 `protobuf:"bytes,1,opt,name=bucket_id,json=bucketId,proto3" json:"bucket_id,omitempty"`
+	RemoteUrl     string                 `protobuf:"bytes,2,opt,name=remote_url,json=remoteUrl,proto3" json:"remote_url,omitempty"` // HTTPS URL of the repository
+	Branch        string                 `protobuf:"bytes,3,opt,name=branch,proto3" json:"branch,omitempty"`                        // Created from the default branch if it doesn't exist
+	Path          string                 `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`                            // Optional directory in the repository to write to
+	Username      string                 `protobuf:"bytes,5,opt,name=username,proto3" json:"username,omitempty"`                    // Optional, defaults to "git" when a token is set
+	Token         string                 `protobuf:"bytes,6,opt,name=token,proto3" json:"token,omitempty"`
+	AuthorName    string                 `protobuf:"bytes,7,opt,name=author_name,json=authorName,proto3" json:"author_name,omitempty"`
+	AuthorEmail   string                 `protobuf:"bytes,8,opt,name=author_email,json=authorEmail,proto3" json:"author_email,omitempty"`
+	Message       string                 `protobuf:"bytes,9,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_rpc_proto_rawDesc = "" +
-	"\n" +
-	"\trpc.proto\x12\arpc.rpc\"v\n" +
-	"\bFileInfo\x12\x12\n" +
-	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
-	"\x04size\x18\x02 \x01(\x03R\x04size\x12!\n" +
-	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\x12\x1f\n" +
-	"\vmodified_at\x18\x04 \x01(\x03R\n" +
-	"modifiedAt\"W\n" +
-	"\vFileContent\x12\x18\n" +
-	"\acontent\x18\x01 \x01(\fR\acontent\x12.\n" +
-	"\tfile_info\x18\x02 \x01(\v2\x11.rpc.rpc.FileInfoR\bfileInfo\"b\n" +
-	"\x12CloneBucketRequest\x12(\n" +
-	"\x10source_bucket_id\x18\x01 \x01(\tR\x0esourceBucketId\x12\"\n" +
-	"\rnew_bucket_id\x18\x02 \x01(\tR\vnewBucketId\"\xf5\x01\n" +
-	"\x1aCreateBucketFromZipRequest\x12\"\n" +
-	"\rnew_bucket_id\x18\x01 \x01(\tR\vnewBucketId\x12\x17\n" +
-	"\azip_url\x18\x02 \x01(\tR\x06zipUrl\x12\x12\n" +
-	"\x04path\x18\x03 \x01(\tR\x04path\x12J\n" +
-	"\aheaders\x18\x04 \x03(\v20.rpc.rpc.CreateBucketFromZipRequest.HeadersEntryR\aheaders\x1a:\n" +
-	"\fHeadersEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"@\n" +
-	"\x10FileContentsBase\x12\x12\n" +
-	"\x04path\x18\x01 \x01(\tR\x04path\x12\x18\n" +
-	"\acontent\x18\x02 \x01(\fR\acontent\"|\n" +
-	"\x1fCreateBucketFromContentsRequest\x12\"\n" +
-	"\rnew_bucket_id\x18\x01 \x01(\tR\vnewBucketId\x125\n" +
-	"\bcontents\x18\x02 \x03(\v2\x19.rpc.rpc.FileContentsBaseR\bcontents\"\xa9\x01\n" +
-	"\x1dCreateBucketFromGithubRequest\x12\"\n" +
-	"\rnew_bucket_id\x18\x01 \x01(\tR\vnewBucketId\x12\x14\n" +
-	"\x05owner\x18\x02 \x01(\tR\x05owner\x12\x12\n" +
-	"\x04repo\x18\x03 \x01(\tR\x04repo\x12\x12\n" +
-	"\x04path\x18\x04 \x01(\tR\x04path\x12\x10\n" +
-	"\x03ref\x18\x05 \x01(\tR\x03ref\x12\x14\n" +
-	"\x05token\x18\x06 \x01(\tR\x05token\"\x16\n" +
-	"\x14CreateBucketResponse\"\x84\x01\n" +
-	"\x15GetBucketTokenRequest\x12\x1b\n" +
-	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12,\n" +
-	"\x12expires_in_seconds\x18\x02 \x01(\x03R\x10expiresInSeconds\x12 \n" +
-	"\fis_read_only\x18\x03 \x01(\bR\n" +
-	"isReadOnly\".\n" +
-	"\x16GetBucketTokenResponse\x12\x14\n" +
-	"\x05token\x18\x01 \x01(\tR\x05token\"G\n" +
-	"\x14GetBucketFileRequest\x12\x1b\n" +
-	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12\x12\n" +
-	"\x04path\x18\x02 \x01(\tR\x04path\"G\n" +
-	"\x15GetBucketFileResponse\x12.\n" +
-	"\acontent\x18\x01 \x01(\v2\x14.rpc.rpc.FileContentR\acontent\"L\n" +
-	"\x15GetBucketFilesRequest\x12\x1b\n" +
-	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12\x16\n" +
-	"\x06prefix\x18\x02 \x01(\tR\x06prefix\"A\n" +
-	"\x16GetBucketFilesResponse\x12'\n" +
-	"\x05files\x18\x01 \x03(\v2\x11.rpc.rpc.FileInfoR\x05files\"O\n" +
-	"!GetBucketFilesWithContentResponse\x12*\n" +
-	"\x05files\x18\x01 \x03(\v2\x14.rpc.rpc.FileContentR\x05files\"Q\n" +
-	"\x1aGetBucketFilesAsZipRequest\x12\x1b\n" +
-	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12\x16\n" +
-	"\x06prefix\x18\x02 \x01(\tR\x06prefix\"_\n" +
-	"\x1bGetBucketFilesAsZipResponse\x12!\n" +
-	"\fdownload_url\x18\x01 \x01(\tR\vdownloadUrl\x12\x1d\n" +
-	"\n" +
-	"expires_at\x18\x02 \x01(\x03R\texpiresAt\"\x8e\x01\n" +
-	"\x1bExportBucketToGithubRequest\x12\x1b\n" +
-	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12\x14\n" +
-	"\x05owner\x18\x02 \x01(\tR\x05owner\x12\x12\n" +
-	"\x04repo\x18\x03 \x01(\tR\x04repo\x12\x12\n" +
-	"\x04path\x18\x04 \x01(\tR\x04path\x12\x14\n" +
-	"\x05token\x18\x05 \x01(\tR\x05token\"\x1e\n" +
-	"\x1cExportBucketToGithubResponse2\x9e\a\n" +
-	"\n" +
-	"CodeBucket\x12I\n" +
-	"\vCloneBucket\x12\x1b.rpc.rpc.CloneBucketRequest\x1a\x1d.rpc.rpc.CreateBucketResponse\x12c\n" +
-	"\x18CreateBucketFromContents\x12(.rpc.rpc.CreateBucketFromContentsRequest\x1a\x1d.rpc.rpc.CreateBucketResponse\x12Y\n" +
-	"\x13CreateBucketFromZip\x12#.rpc.rpc.CreateBucketFromZipRequest\x1a\x1d.rpc.rpc.CreateBucketResponse\x12_\n" +
-	"\x16CreateBucketFromGithub\x12&.rpc.rpc.CreateBucketFromGithubRequest\x1a\x1d.rpc.rpc.CreateBucketResponse\x12Q\n" +
-	"\x0eGetBucketToken\x12\x1e.rpc.rpc.GetBucketTokenRequest\x1a\x1f.rpc.rpc.GetBucketTokenResponse\x12N\n" +
-	"\rGetBucketFile\x12\x1d.rpc.rpc.GetBucketFileRequest\x1a\x1e.rpc.rpc.GetBucketFileResponse\x12Q\n" +
-	"\x0eGetBucketFiles\x12\x1e.rpc.rpc.GetBucketFilesRequest\x1a\x1f.rpc.rpc.GetBucketFilesResponse\x12g\n" +
-	"\x19GetBucketFilesWithContent\x12\x1e.rpc.rpc.GetBucketFilesRequest\x1a*.rpc.rpc.GetBucketFilesWithContentResponse\x12`\n" +
-	"\x13GetBucketFilesAsZip\x12#.rpc.rpc.GetBucketFilesAsZipRequest\x1a$.rpc.rpc.GetBucketFilesAsZipResponse\x12c\n" +
-	"\x14ExportBucketToGithub\x12$.rpc.rpc.ExportBucketToGithubRequest\x1a%.rpc.rpc.ExportBucketToGithubResponseB7Z5github.com/metorial/metorial/services/rpc/gen/rpc;rpcb\x06proto3"
+func (x *ExportBucketToGitRequest) Reset() {
+	*x = ExportBucketToGitRequest{}
+	mi := &file_rpc_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportBucketToGitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportBucketToGitRequest) ProtoMessage() {}
+
+func (x *ExportBucketToGitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportBucketToGitRequest.ProtoReflect.Descriptor instead.
+func (*ExportBucketToGitRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ExportBucketToGitRequest) GetBucketId() string {
+	if x != nil {
+		return x.BucketId
+	}
+	return ""
+}
+
+func (x *ExportBucketToGitRequest) GetRemoteUrl() string {
+	if x != nil {
+		return x.RemoteUrl
+	}
+	return ""
+}
+
+func (x *ExportBucketToGitRequest) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+func (x *ExportBucketToGitRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ExportBucketToGitRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ExportBucketToGitRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ExportBucketToGitRequest) GetAuthorName() string {
+	if x != nil {
+		return x.AuthorName
+	}
+	return ""
+}
+
+func (x *ExportBucketToGitRequest) GetAuthorEmail() string {
+	if x != nil {
+		return x.AuthorEmail
+	}
+	return ""
+}
+
+func (x *ExportBucketToGitRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ExportBucketToGitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommitSha     string                 `protobuf:"bytes,1,opt,name=commit_sha,json=commitSha,proto3" json:"commit_sha,omitempty"`
+	Changed       bool                   `protobuf:"varint,2,opt,name=changed,proto3" json:"changed,omitempty"` // False if the branch already matched the bucket
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportBucketToGitResponse) Reset() {
+	*x = ExportBucketToGitResponse{}
+	mi := &file_rpc_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportBucketToGitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportBucketToGitResponse) ProtoMessage() {}
+
+func (x *ExportBucketToGitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportBucketToGitResponse.ProtoReflect.Descriptor instead.
+func (*ExportBucketToGitResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ExportBucketToGitResponse) GetCommitSha() string {
+	if x != nil {
+		return x.CommitSha
+	}
+	return ""
+}
+
+func (x *ExportBucketToGitResponse) GetChanged() bool {
+	if x != nil {
+		return x.Changed
+	}
+	return false
+}
+
+type Snapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	BucketId      string                 `protobuf:"bytes,2,opt,name=bucket_id,json=bucketId,proto3" json:"bucket_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	FileCount     int64                  `protobuf:"varint,5,opt,name=file_count,json=fileCount,proto3" json:"file_count,omitempty"`
+	TotalSize     int64                  `protobuf:"varint,6,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Snapshot) Reset() {
+	*x = Snapshot{}
+	mi := &file_rpc_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Snapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Snapshot) ProtoMessage() {}
+
+func (x *Snapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Snapshot.ProtoReflect.Descriptor instead.
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Snapshot) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Snapshot) GetBucketId() string {
+	if x != nil {
+		return x.BucketId
+	}
+	return ""
+}
+
+func (x *Snapshot) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Snapshot) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Snapshot) GetFileCount() int64 {
+	if x != nil {
+		return x.FileCount
+	}
+	return 0
+}
+
+func (x *Snapshot) GetTotalSize() int64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type CreateSnapshotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BucketId      string                 `protobuf:"bytes,1,opt,name=bucket_id,json=bucketId,proto3" json:"bucket_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSnapshotRequest) Reset() {
+	*x = CreateSnapshotRequest{}
+	mi := &file_rpc_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSnapshotRequest) ProtoMessage() {}
+
+func (x *CreateSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*CreateSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CreateSnapshotRequest) GetBucketId() string {
+	if x != nil {
+		return x.BucketId
+	}
+	return ""
+}
+
+func (x *CreateSnapshotRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type SnapshotResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Snapshot      *Snapshot              `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotResponse) Reset() {
+	*x = SnapshotResponse{}
+	mi := &file_rpc_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotResponse) ProtoMessage() {}
+
+func (x *SnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotResponse.ProtoReflect.Descriptor instead.
+func (*SnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SnapshotResponse) GetSnapshot() *Snapshot {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+type ListSnapshotsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BucketId      string                 `protobuf:"bytes,1,opt,name=bucket_id,json=bucketId,proto3" json:"bucket_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSnapshotsRequest) Reset() {
+	*x = ListSnapshotsRequest{}
+	mi := &file_rpc_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSnapshotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSnapshotsRequest) ProtoMessage() {}
+
+func (x *ListSnapshotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSnapshotsRequest.ProtoReflect.Descriptor instead.
+func (*ListSnapshotsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListSnapshotsRequest) GetBucketId() string {
+	if x != nil {
+		return x.BucketId
+	}
+	return ""
+}
+
+type ListSnapshotsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Snapshots     []*Snapshot            `protobuf:"bytes,1,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSnapshotsResponse) Reset() {
+	*x = ListSnapshotsResponse{}
+	mi := &file_rpc_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSnapshotsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSnapshotsResponse) ProtoMessage() {}
+
+func (x *ListSnapshotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSnapshotsResponse.ProtoReflect.Descriptor instead.
+func (*ListSnapshotsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListSnapshotsResponse) GetSnapshots() []*Snapshot {
+	if x != nil {
+		return x.Snapshots
+	}
+	return nil
+}
+
+type DiffSnapshotsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BucketId      string                 `protobuf:"bytes,1,opt,name=bucket_id,json=bucketId,proto3" json:"bucket_id,omitempty"`
+	FromSnapshot  string                 `protobuf:"bytes,2,opt,name=from_snapshot,json=fromSnapshot,proto3" json:"from_snapshot,omitempty"` // Empty for the current state
+	ToSnapshot    string                 `protobuf:"bytes,3,opt,name=to_snapshot,json=toSnapshot,proto3" json:"to_snapshot,omitempty"`       // Empty for the current state
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffSnapshotsRequest) Reset() {
+	*x = DiffSnapshotsRequest{}
+	mi := &file_rpc_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffSnapshotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffSnapshotsRequest) ProtoMessage() {}
+
+func (x *DiffSnapshotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffSnapshotsRequest.ProtoReflect.Descriptor instead.
+func (*DiffSnapshotsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DiffSnapshotsRequest) GetBucketId() string {
+	if x != nil {
+		return x.BucketId
+	}
+	return ""
+}
+
+func (x *DiffSnapshotsRequest) GetFromSnapshot() string {
+	if x != nil {
+		return x.FromSnapshot
+	}
+	return ""
+}
+
+func (x *DiffSnapshotsRequest) GetToSnapshot() string {
+	if x != nil {
+		return x.ToSnapshot
+	}
+	return ""
+}
+
+type SnapshotDiffEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Change        string                 `protobuf:"bytes,2,opt,name=change,proto3" json:"change,omitempty"` // added, removed or modified
+	FromHash      string                 `protobuf:"bytes,3,opt,name=from_hash,json=fromHash,proto3" json:"from_hash,omitempty"`
+	ToHash        string                 `protobuf:"bytes,4,opt,name=to_hash,json=toHash,proto3" json:"to_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotDiffEntry) Reset() {
+	*x = SnapshotDiffEntry{}
+	mi := &file_rpc_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotDiffEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotDiffEntry) ProtoMessage() {}
+
+func (x *SnapshotDiffEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotDiffEntry.ProtoReflect.Descriptor instead.
+func (*SnapshotDiffEntry) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SnapshotDiffEntry) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *SnapshotDiffEntry) GetChange() string {
+	if x != nil {
+		return x.Change
+	}
+	return ""
+}
+
+func (x *SnapshotDiffEntry) GetFromHash() string {
+	if x != nil {
+		return x.FromHash
+	}
+	return ""
+}
+
+func (x *SnapshotDiffEntry) GetToHash() string {
+	if x != nil {
+		return x.ToHash
+	}
+	return ""
+}
+
+type DiffSnapshotsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*SnapshotDiffEntry   `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffSnapshotsResponse) Reset() {
+	*x = DiffSnapshotsResponse{}
+	mi := &file_rpc_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffSnapshotsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffSnapshotsResponse) ProtoMessage() {}
+
+func (x *DiffSnapshotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffSnapshotsResponse.ProtoReflect.Descriptor instead.
+func (*DiffSnapshotsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DiffSnapshotsResponse) GetChanges() []*SnapshotDiffEntry {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+type RestoreSnapshotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BucketId      string                 `protobuf:"bytes,1,opt,name=bucket_id,json=bucketId,proto3" json:"bucket_id,omitempty"`
+	Snapshot      string                 `protobuf:"bytes,2,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreSnapshotRequest) Reset() {
+	*x = RestoreSnapshotRequest{}
+	mi := &file_rpc_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreSnapshotRequest) ProtoMessage() {}
+
+func (x *RestoreSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*RestoreSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RestoreSnapshotRequest) GetBucketId() string {
+	if x != nil {
+		return x.BucketId
+	}
+	return ""
+}
+
+func (x *RestoreSnapshotRequest) GetSnapshot() string {
+	if x != nil {
+		return x.Snapshot
+	}
+	return ""
+}
+
+type RestoreSnapshotResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreSnapshotResponse) Reset() {
+	*x = RestoreSnapshotResponse{}
+	mi := &file_rpc_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreSnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreSnapshotResponse) ProtoMessage() {}
+
+func (x *RestoreSnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreSnapshotResponse.ProtoReflect.Descriptor instead.
+func (*RestoreSnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{31}
+}
+
+type WatchBucketRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BucketId      string                 `protobuf:"bytes,1,opt,name=bucket_id,json=bucketId,proto3" json:"bucket_id,omitempty"`
+	AfterEventId  string                 `protobuf:"bytes,2,opt,name=after_event_id,json=afterEventId,proto3" json:"after_event_id,omitempty"` // Optional, resumes after this event
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchBucketRequest) Reset() {
+	*x = WatchBucketRequest{}
+	mi := &file_rpc_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchBucketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchBucketRequest) ProtoMessage() {}
+
+func (x *WatchBucketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchBucketRequest.ProtoReflect.Descriptor instead.
+func (*WatchBucketRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *WatchBucketRequest) GetBucketId() string {
+	if x != nil {
+		return x.BucketId
+	}
+	return ""
+}
+
+func (x *WatchBucketRequest) GetAfterEventId() string {
+	if x != nil {
+		return x.AfterEventId
+	}
+	return ""
+}
+
+type BucketChangeEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Operation     string                 `protobuf:"bytes,3,opt,name=operation,proto3" json:"operation,omitempty"`  // put or delete
+	Hash          string                 `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`            // sha256 of the content, empty for deletes
+	Timestamp     int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix milliseconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BucketChangeEvent) Reset() {
+	*x = BucketChangeEvent{}
+	mi := &file_rpc_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BucketChangeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BucketChangeEvent) ProtoMessage() {}
+
+func (x *BucketChangeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BucketChangeEvent.ProtoReflect.Descriptor instead.
+func (*BucketChangeEvent) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *BucketChangeEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BucketChangeEvent) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *BucketChangeEvent) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *BucketChangeEvent) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *BucketChangeEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type BucketQuota struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaxTotalBytes int64                  `protobuf:"varint,1,opt,name=max_total_bytes,json=maxTotalBytes,proto3" json:"max_total_bytes,omitempty"` // 0 for no limit
+	MaxFiles      int64                  `protobuf:"varint,2,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`                  // 0 for no limit
+	MaxFileBytes  int64                  `protobuf:"varint,3,opt,name=max_file_bytes,json=maxFileBytes,proto3" json:"max_file_bytes,omitempty"`    // 0 for no limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BucketQuota) Reset() {
+	*x = BucketQuota{}
+	mi := &file_rpc_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BucketQuota) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BucketQuota) ProtoMessage() {}
+
+func (x *BucketQuota) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BucketQuota.ProtoReflect.Descriptor instead.
+func (*BucketQuota) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *BucketQuota) GetMaxTotalBytes() int64 {
+	if x != nil {
+		return x.MaxTotalBytes
+	}
+	return 0
+}
+
+func (x *BucketQuota) GetMaxFiles() int64 {
+	if x != nil {
+		return x.MaxFiles
+	}
+	return 0
+}
+
+func (x *BucketQuota) GetMaxFileBytes() int64 {
+	if x != nil {
+		return x.MaxFileBytes
+	}
+	return 0
+}
+
+type BucketUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TotalBytes    int64                  `protobuf:"varint,1,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	Files         int64                  `protobuf:"varint,2,opt,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BucketUsage) Reset() {
+	*x = BucketUsage{}
+	mi := &file_rpc_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BucketUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BucketUsage) ProtoMessage() {}
+
+func (x *BucketUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BucketUsage.ProtoReflect.Descriptor instead.
+func (*BucketUsage) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *BucketUsage) GetTotalBytes() int64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *BucketUsage) GetFiles() int64 {
+	if x != nil {
+		return x.Files
+	}
+	return 0
+}
+
+type GetBucketQuotaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BucketId      string                 `protobuf:"bytes,1,opt,name=bucket_id,json=bucketId,proto3" json:"bucket_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBucketQuotaRequest) Reset() {
+	*x = GetBucketQuotaRequest{}
+	mi := &file_rpc_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBucketQuotaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBucketQuotaRequest) ProtoMessage() {}
+
+func (x *GetBucketQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBucketQuotaRequest.ProtoReflect.Descriptor instead.
+func (*GetBucketQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetBucketQuotaRequest) GetBucketId() string {
+	if x != nil {
+		return x.BucketId
+	}
+	return ""
+}
+
+type SetBucketQuotaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BucketId      string                 `protobuf:"bytes,1,opt,name=bucket_id,json=bucketId,proto3" json:"bucket_id,omitempty"`
+	Quota         *BucketQuota           `protobuf:"bytes,2,opt,name=quota,proto3" json:"quota,omitempty"` // Unset to restore the default quota
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetBucketQuotaRequest) Reset() {
+	*x = SetBucketQuotaRequest{}
+	mi := &file_rpc_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetBucketQuotaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetBucketQuotaRequest) ProtoMessage() {}
+
+func (x *SetBucketQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetBucketQuotaRequest.ProtoReflect.Descriptor instead.
+func (*SetBucketQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *SetBucketQuotaRequest) GetBucketId() string {
+	if x != nil {
+		return x.BucketId
+	}
+	return ""
+}
+
+func (x *SetBucketQuotaRequest) GetQuota() *BucketQuota {
+	if x != nil {
+		return x.Quota
+	}
+	return nil
+}
+
+type BucketQuotaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Quota         *BucketQuota           `protobuf:"bytes,1,opt,name=quota,proto3" json:"quota,omitempty"`
+	Usage         *BucketUsage           `protobuf:"bytes,2,opt,name=usage,proto3" json:"usage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BucketQuotaResponse) Reset() {
+	*x = BucketQuotaResponse{}
+	mi := &file_rpc_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BucketQuotaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BucketQuotaResponse) ProtoMessage() {}
+
+func (x *BucketQuotaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BucketQuotaResponse.ProtoReflect.Descriptor instead.
+func (*BucketQuotaResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *BucketQuotaResponse) GetQuota() *BucketQuota {
+	if x != nil {
+		return x.Quota
+	}
+	return nil
+}
+
+func (x *BucketQuotaResponse) GetUsage() *BucketUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+type BatchOperation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Op            string                 `protobuf:"bytes,1,opt,name=op,proto3" json:"op,omitempty"` // put, delete or rename
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	From          string                 `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"` // Source path of renames
+	Content       []byte                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	ContentType   string                 `protobuf:"bytes,5,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchOperation) Reset() {
+	*x = BatchOperation{}
+	mi := &file_rpc_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchOperation) ProtoMessage() {}
+
+func (x *BatchOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchOperation.ProtoReflect.Descriptor instead.
+func (*BatchOperation) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *BatchOperation) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *BatchOperation) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *BatchOperation) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *BatchOperation) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *BatchOperation) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type ApplyBatchRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	BucketId        string                 `protobuf:"bytes,1,opt,name=bucket_id,json=bucketId,proto3" json:"bucket_id,omitempty"`
+	IfMatchRevision string                 `protobuf:"bytes,2,opt,name=if_match_revision,json=ifMatchRevision,proto3" json:"if_match_revision,omitempty"` // Optional, fails unless the bucket is at this revision
+	Operations      []*BatchOperation      `protobuf:"bytes,3,rep,name=operations,proto3" json:"operations,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ApplyBatchRequest) Reset() {
+	*x = ApplyBatchRequest{}
+	mi := &file_rpc_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyBatchRequest) ProtoMessage() {}
+
+func (x *ApplyBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyBatchRequest.ProtoReflect.Descriptor instead.
+func (*ApplyBatchRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ApplyBatchRequest) GetBucketId() string {
+	if x != nil {
+		return x.BucketId
+	}
+	return ""
+}
+
+func (x *ApplyBatchRequest) GetIfMatchRevision() string {
+	if x != nil {
+		return x.IfMatchRevision
+	}
+	return ""
+}
+
+func (x *ApplyBatchRequest) GetOperations() []*BatchOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+type ApplyBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revision      string                 `protobuf:"bytes,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyBatchResponse) Reset() {
+	*x = ApplyBatchResponse{}
+	mi := &file_rpc_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyBatchResponse) ProtoMessage() {}
+
+func (x *ApplyBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyBatchResponse.ProtoReflect.Descriptor instead.
+func (*ApplyBatchResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ApplyBatchResponse) GetRevision() string {
+	if x != nil {
+		return x.Revision
+	}
+	return ""
+}
+
+var File_rpc_proto protoreflect.FileDescriptor
+
+const file_rpc_proto_rawDesc = "" +
+	"\n" +
+	"\trpc.proto\x12\arpc.rpc\"v\n" +
+	"\bFileInfo\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\x12\x1f\n" +
+	"\vmodified_at\x18\x04 \x01(\x03R\n" +
+	"modifiedAt\"W\n" +
+	"\vFileContent\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\fR\acontent\x12.\n" +
+	"\tfile_info\x18\x02 \x01(\v2\x11.rpc.rpc.FileInfoR\bfileInfo\"b\n" +
+	"\x12CloneBucketRequest\x12(\n" +
+	"\x10source_bucket_id\x18\x01 \x01(\tR\x0esourceBucketId\x12\"\n" +
+	"\rnew_bucket_id\x18\x02 \x01(\tR\vnewBucketId\"\xf5\x01\n" +
+	"\x1aCreateBucketFromZipRequest\x12\"\n" +
+	"\rnew_bucket_id\x18\x01 \x01(\tR\vnewBucketId\x12\x17\n" +
+	"\azip_url\x18\x02 \x01(\tR\x06zipUrl\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12J\n" +
+	"\aheaders\x18\x04 \x03(\v20.rpc.rpc.CreateBucketFromZipRequest.HeadersEntryR\aheaders\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"@\n" +
+	"\x10FileContentsBase\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\fR\acontent\"|\n" +
+	"\x1fCreateBucketFromContentsRequest\x12\"\n" +
+	"\rnew_bucket_id\x18\x01 \x01(\tR\vnewBucketId\x125\n" +
+	"\bcontents\x18\x02 \x03(\v2\x19.rpc.rpc.FileContentsBaseR\bcontents\"\xa9\x01\n" +
+	"\x1dCreateBucketFromGithubRequest\x12\"\n" +
+	"\rnew_bucket_id\x18\x01 \x01(\tR\vnewBucketId\x12\x14\n" +
+	"\x05owner\x18\x02 \x01(\tR\x05owner\x12\x12\n" +
+	"\x04repo\x18\x03 \x01(\tR\x04repo\x12\x12\n" +
+	"\x04path\x18\x04 \x01(\tR\x04path\x12\x10\n" +
+	"\x03ref\x18\x05 \x01(\tR\x03ref\x12\x14\n" +
+	"\x05token\x18\x06 \x01(\tR\x05token\"\xb7\x01\n" +
+	"\x1aCreateBucketFromGitRequest\x12\"\n" +
+	"\rnew_bucket_id\x18\x01 \x01(\tR\vnewBucketId\x12\x1d\n" +
+	"\n" +
+	"remote_url\x18\x02 \x01(\tR\tremoteUrl\x12\x10\n" +
+	"\x03ref\x18\x03 \x01(\tR\x03ref\x12\x12\n" +
+	"\x04path\x18\x04 \x01(\tR\x04path\x12\x1a\n" +
+	"\busername\x18\x05 \x01(\tR\busername\x12\x14\n" +
+	"\x05token\x18\x06 \x01(\tR\x05token\"\x16\n" +
+	"\x14CreateBucketResponse\"\x84\x01\n" +
+	"\x15GetBucketTokenRequest\x12\x1b\n" +
+	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12,\n" +
+	"\x12expires_in_seconds\x18\x02 \x01(\x03R\x10expiresInSeconds\x12 \n" +
+	"\fis_read_only\x18\x03 \x01(\bR\n" +
+	"isReadOnly\".\n" +
+	"\x16GetBucketTokenResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"c\n" +
+	"\x14GetBucketFileRequest\x12\x1b\n" +
+	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x1a\n" +
+	"\bsnapshot\x18\x03 \x01(\tR\bsnapshot\"G\n" +
+	"\x15GetBucketFileResponse\x12.\n" +
+	"\acontent\x18\x01 \x01(\v2\x14.rpc.rpc.FileContentR\acontent\"h\n" +
+	"\x15GetBucketFilesRequest\x12\x1b\n" +
+	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12\x16\n" +
+	"\x06prefix\x18\x02 \x01(\tR\x06prefix\x12\x1a\n" +
+	"\bsnapshot\x18\x03 \x01(\tR\bsnapshot\"]\n" +
+	"\x16GetBucketFilesResponse\x12'\n" +
+	"\x05files\x18\x01 \x03(\v2\x11.rpc.rpc.FileInfoR\x05files\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\tR\brevision\"O\n" +
+	"!GetBucketFilesWithContentResponse\x12*\n" +
+	"\x05files\x18\x01 \x03(\v2\x14.rpc.rpc.FileContentR\x05files\"Q\n" +
+	"\x1aGetBucketFilesAsZipRequest\x12\x1b\n" +
+	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12\x16\n" +
+	"\x06prefix\x18\x02 \x01(\tR\x06prefix\"_\n" +
+	"\x1bGetBucketFilesAsZipResponse\x12!\n" +
+	"\fdownload_url\x18\x01 \x01(\tR\vdownloadUrl\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\"\x8e\x01\n" +
+	"\x1bExportBucketToGithubRequest\x12\x1b\n" +
+	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12\x14\n" +
+	"\x05owner\x18\x02 \x01(\tR\x05owner\x12\x12\n" +
+	"\x04repo\x18\x03 \x01(\tR\x04repo\x12\x12\n" +
+	"\x04path\x18\x04 \x01(\tR\x04path\x12\x14\n" +
+	"\x05token\x18\x05 \x01(\tR\x05token\"\x1e\n" +
+	"\x1cExportBucketToGithubResponse\"\x92\x02\n" +
+	"\x18ExportBucketToGitRequest\x12\x1b\n" +
+	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12\x1d\n" +
+	"\n" +
+	"remote_url\x18\x02 \x01(\tR\tremoteUrl\x12\x16\n" +
+	"\x06branch\x18\x03 \x01(\tR\x06branch\x12\x12\n" +
+	"\x04path\x18\x04 \x01(\tR\x04path\x12\x1a\n" +
+	"\busername\x18\x05 \x01(\tR\busername\x12\x14\n" +
+	"\x05token\x18\x06 \x01(\tR\x05token\x12\x1f\n" +
+	"\vauthor_name\x18\a \x01(\tR\n" +
+	"authorName\x12!\n" +
+	"\fauthor_email\x18\b \x01(\tR\vauthorEmail\x12\x18\n" +
+	"\amessage\x18\t \x01(\tR\amessage\"T\n" +
+	"\x19ExportBucketToGitResponse\x12\x1d\n" +
+	"\n" +
+	"commit_sha\x18\x01 \x01(\tR\tcommitSha\x12\x18\n" +
+	"\achanged\x18\x02 \x01(\bR\achanged\"\xa8\x01\n" +
+	"\bSnapshot\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tbucket_id\x18\x02 \x01(\tR\bbucketId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"file_count\x18\x05 \x01(\x03R\tfileCount\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x06 \x01(\x03R\ttotalSize\"H\n" +
+	"\x15CreateSnapshotRequest\x12\x1b\n" +
+	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"A\n" +
+	"\x10SnapshotResponse\x12-\n" +
+	"\bsnapshot\x18\x01 \x01(\v2\x11.rpc.rpc.SnapshotR\bsnapshot\"3\n" +
+	"\x14ListSnapshotsRequest\x12\x1b\n" +
+	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\"H\n" +
+	"\x15ListSnapshotsResponse\x12/\n" +
+	"\tsnapshots\x18\x01 \x03(\v2\x11.rpc.rpc.SnapshotR\tsnapshots\"y\n" +
+	"\x14DiffSnapshotsRequest\x12\x1b\n" +
+	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12#\n" +
+	"\rfrom_snapshot\x18\x02 \x01(\tR\ffromSnapshot\x12\x1f\n" +
+	"\vto_snapshot\x18\x03 \x01(\tR\n" +
+	"toSnapshot\"u\n" +
+	"\x11SnapshotDiffEntry\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06change\x18\x02 \x01(\tR\x06change\x12\x1b\n" +
+	"\tfrom_hash\x18\x03 \x01(\tR\bfromHash\x12\x17\n" +
+	"\ato_hash\x18\x04 \x01(\tR\x06toHash\"M\n" +
+	"\x15DiffSnapshotsResponse\x124\n" +
+	"\achanges\x18\x01 \x03(\v2\x1a.rpc.rpc.SnapshotDiffEntryR\achanges\"Q\n" +
+	"\x16RestoreSnapshotRequest\x12\x1b\n" +
+	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12\x1a\n" +
+	"\bsnapshot\x18\x02 \x01(\tR\bsnapshot\"\x19\n" +
+	"\x17RestoreSnapshotResponse\"W\n" +
+	"\x12WatchBucketRequest\x12\x1b\n" +
+	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12$\n" +
+	"\x0eafter_event_id\x18\x02 \x01(\tR\fafterEventId\"\x87\x01\n" +
+	"\x11BucketChangeEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x1c\n" +
+	"\toperation\x18\x03 \x01(\tR\toperation\x12\x12\n" +
+	"\x04hash\x18\x04 \x01(\tR\x04hash\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\"x\n" +
+	"\vBucketQuota\x12&\n" +
+	"\x0fmax_total_bytes\x18\x01 \x01(\x03R\rmaxTotalBytes\x12\x1b\n" +
+	"\tmax_files\x18\x02 \x01(\x03R\bmaxFiles\x12$\n" +
+	"\x0emax_file_bytes\x18\x03 \x01(\x03R\fmaxFileBytes\"D\n" +
+	"\vBucketUsage\x12\x1f\n" +
+	"\vtotal_bytes\x18\x01 \x01(\x03R\n" +
+	"totalBytes\x12\x14\n" +
+	"\x05files\x18\x02 \x01(\x03R\x05files\"4\n" +
+	"\x15GetBucketQuotaRequest\x12\x1b\n" +
+	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\"`\n" +
+	"\x15SetBucketQuotaRequest\x12\x1b\n" +
+	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12*\n" +
+	"\x05quota\x18\x02 \x01(\v2\x14.rpc.rpc.BucketQuotaR\x05quota\"m\n" +
+	"\x13BucketQuotaResponse\x12*\n" +
+	"\x05quota\x18\x01 \x01(\v2\x14.rpc.rpc.BucketQuotaR\x05quota\x12*\n" +
+	"\x05usage\x18\x02 \x01(\v2\x14.rpc.rpc.BucketUsageR\x05usage\"\x85\x01\n" +
+	"\x0eBatchOperation\x12\x0e\n" +
+	"\x02op\x18\x01 \x01(\tR\x02op\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\tR\x04from\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\fR\acontent\x12!\n" +
+	"\fcontent_type\x18\x05 \x01(\tR\vcontentType\"\x95\x01\n" +
+	"\x11ApplyBatchRequest\x12\x1b\n" +
+	"\tbucket_id\x18\x01 \x01(\tR\bbucketId\x12*\n" +
+	"\x11if_match_revision\x18\x02 \x01(\tR\x0fifMatchRevision\x127\n" +
+	"\n" +
+	"operations\x18\x03 \x03(\v2\x17.rpc.rpc.BatchOperationR\n" +
+	"operations\"0\n" +
+	"\x12ApplyBatchResponse\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\tR\brevision2\xc9\r\n" +
+	"\n" +
+	"CodeBucket\x12I\n" +
+	"\vCloneBucket\x12\x1b.rpc.rpc.CloneBucketRequest\x1a\x1d.rpc.rpc.CreateBucketResponse\x12c\n" +
+	"\x18CreateBucketFromContents\x12(.rpc.rpc.CreateBucketFromContentsRequest\x1a\x1d.rpc.rpc.CreateBucketResponse\x12Y\n" +
+	"\x13CreateBucketFromZip\x12#.rpc.rpc.CreateBucketFromZipRequest\x1a\x1d.rpc.rpc.CreateBucketResponse\x12_\n" +
+	"\x16CreateBucketFromGithub\x12&.rpc.rpc.CreateBucketFromGithubRequest\x1a\x1d.rpc.rpc.CreateBucketResponse\x12Y\n" +
+	"\x13CreateBucketFromGit\x12#.rpc.rpc.CreateBucketFromGitRequest\x1a\x1d.rpc.rpc.CreateBucketResponse\x12Q\n" +
+	"\x0eGetBucketToken\x12\x1e.rpc.rpc.GetBucketTokenRequest\x1a\x1f.rpc.rpc.GetBucketTokenResponse\x12N\n" +
+	"\rGetBucketFile\x12\x1d.rpc.rpc.GetBucketFileRequest\x1a\x1e.rpc.rpc.GetBucketFileResponse\x12Q\n" +
+	"\x0eGetBucketFiles\x12\x1e.rpc.rpc.GetBucketFilesRequest\x1a\x1f.rpc.rpc.GetBucketFilesResponse\x12g\n" +
+	"\x19GetBucketFilesWithContent\x12\x1e.rpc.rpc.GetBucketFilesRequest\x1a*.rpc.rpc.GetBucketFilesWithContentResponse\x12`\n" +
+	"\x13GetBucketFilesAsZip\x12#.rpc.rpc.GetBucketFilesAsZipRequest\x1a$.rpc.rpc.GetBucketFilesAsZipResponse\x12c\n" +
+	"\x14ExportBucketToGithub\x12$.rpc.rpc.ExportBucketToGithubRequest\x1a%.rpc.rpc.ExportBucketToGithubResponse\x12Z\n" +
+	"\x11ExportBucketToGit\x12!.rpc.rpc.ExportBucketToGitRequest\x1a\".rpc.rpc.ExportBucketToGitResponse\x12K\n" +
+	"\x0eCreateSnapshot\x12\x1e.rpc.rpc.CreateSnapshotRequest\x1a\x19.rpc.rpc.SnapshotResponse\x12N\n" +
+	"\rListSnapshots\x12\x1d.rpc.rpc.ListSnapshotsRequest\x1a\x1e.rpc.rpc.ListSnapshotsResponse\x12N\n" +
+	"\rDiffSnapshots\x12\x1d.rpc.rpc.DiffSnapshotsRequest\x1a\x1e.rpc.rpc.DiffSnapshotsResponse\x12T\n" +
+	"\x0fRestoreSnapshot\x12\x1f.rpc.rpc.RestoreSnapshotRequest\x1a .rpc.rpc.RestoreSnapshotResponse\x12H\n" +
+	"\vWatchBucket\x12\x1b.rpc.rpc.WatchBucketRequest\x1a\x1a.rpc.rpc.BucketChangeEvent0\x01\x12N\n" +
+	"\x0eGetBucketQuota\x12\x1e.rpc.rpc.GetBucketQuotaRequest\x1a\x1c.rpc.rpc.BucketQuotaResponse\x12N\n" +
+	"\x0eSetBucketQuota\x12\x1e.rpc.rpc.SetBucketQuotaRequest\x1a\x1c.rpc.rpc.BucketQuotaResponse\x12E\n" +
+	"\n" +
+	"ApplyBatch\x12\x1a.rpc.rpc.ApplyBatchRequest\x1a\x1b.rpc.rpc.ApplyBatchResponseB7Z5github.com/metorial/metorial/services/rpc/gen/rpc;rpcb\x06proto3"
 
 var (
 	file_rpc_proto_rawDescOnce sync.Once
@@ -1138,7 +2614,7 @@ func file_rpc_proto_rawDescGZIP() []byte {
 	return file_rpc_proto_rawDescData
 }
 
-var file_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
 var file_rpc_proto_goTypes = []any{
 	(*FileInfo)(nil),                          // 0: rpc.rpc.FileInfo
 	(*FileContent)(nil),                       // 1: rpc.rpc.FileContent
@@ -1147,52 +2623,102 @@ var file_rpc_proto_goTypes = []any{
 	(*FileContentsBase)(nil),                  // 4: rpc.rpc.FileContentsBase
 	(*CreateBucketFromContentsRequest)(nil),   // 5: rpc.rpc.CreateBucketFromContentsRequest
 	(*CreateBucketFromGithubRequest)(nil),     // 6: rpc.rpc.CreateBucketFromGithubRequest
-	(*CreateBucketResponse)(nil),              // 7: rpc.rpc.CreateBucketResponse
-	(*GetBucketTokenRequest)(nil),             // 8: rpc.rpc.GetBucketTokenRequest
-	(*GetBucketTokenResponse)(nil),            // 9: rpc.rpc.GetBucketTokenResponse
-	(*GetBucketFileRequest)(nil),              // 10: rpc.rpc.GetBucketFileRequest
-	(*GetBucketFileResponse)(nil),             // 11: rpc.rpc.GetBucketFileResponse
-	(*GetBucketFilesRequest)(nil),             // 12: rpc.rpc.GetBucketFilesRequest
-	(*GetBucketFilesResponse)(nil),            // 13: rpc.rpc.GetBucketFilesResponse
-	(*GetBucketFilesWithContentResponse)(nil), // 14: rpc.rpc.GetBucketFilesWithContentResponse
-	(*GetBucketFilesAsZipRequest)(nil),        // 15: rpc.rpc.GetBucketFilesAsZipRequest
-	(*GetBucketFilesAsZipResponse)(nil),       // 16: rpc.rpc.GetBucketFilesAsZipResponse
-	(*ExportBucketToGithubRequest)(nil),       // 17: rpc.rpc.ExportBucketToGithubRequest
-	(*ExportBucketToGithubResponse)(nil),      // 18: rpc.rpc.ExportBucketToGithubResponse
-	nil,                                       // 19: rpc.rpc.CreateBucketFromZipRequest.HeadersEntry
+	(*CreateBucketFromGitRequest)(nil),        // 7: rpc.rpc.CreateBucketFromGitRequest
+	(*CreateBucketResponse)(nil),              // 8: rpc.rpc.CreateBucketResponse
+	(*GetBucketTokenRequest)(nil),             // 9: rpc.rpc.GetBucketTokenRequest
+	(*GetBucketTokenResponse)(nil),            // 10: rpc.rpc.GetBucketTokenResponse
+	(*GetBucketFileRequest)(nil),              // 11: rpc.rpc.GetBucketFileRequest
+	(*GetBucketFileResponse)(nil),             // 12: rpc.rpc.GetBucketFileResponse
+	(*GetBucketFilesRequest)(nil),             // 13: rpc.rpc.GetBucketFilesRequest
+	(*GetBucketFilesResponse)(nil),            // 14: rpc.rpc.GetBucketFilesResponse
+	(*GetBucketFilesWithContentResponse)(nil), // 15: rpc.rpc.GetBucketFilesWithContentResponse
+	(*GetBucketFilesAsZipRequest)(nil),        // 16: rpc.rpc.GetBucketFilesAsZipRequest
+	(*GetBucketFilesAsZipResponse)(nil),       // 17: rpc.rpc.GetBucketFilesAsZipResponse
+	(*ExportBucketToGithubRequest)(nil),       // 18: rpc.rpc.ExportBucketToGithubRequest
+	(*ExportBucketToGithubResponse)(nil),      // 19: rpc.rpc.ExportBucketToGithubResponse
+	(*ExportBucketToGitRequest)(nil),          // 20: rpc.rpc.ExportBucketToGitRequest
+	(*ExportBucketToGitResponse)(nil),         // 21: rpc.rpc.ExportBucketToGitResponse
+	(*Snapshot)(nil),                          // 22: rpc.rpc.Snapshot
+	(*CreateSnapshotRequest)(nil),             // 23: rpc.rpc.CreateSnapshotRequest
+	(*SnapshotResponse)(nil),                  // 24: rpc.rpc.SnapshotResponse
+	(*ListSnapshotsRequest)(nil),              // 25: rpc.rpc.ListSnapshotsRequest
+	(*ListSnapshotsResponse)(nil),             // 26: rpc.rpc.ListSnapshotsResponse
+	(*DiffSnapshotsRequest)(nil),              // 27: rpc.rpc.DiffSnapshotsRequest
+	(*SnapshotDiffEntry)(nil),                 // 28: rpc.rpc.SnapshotDiffEntry
+	(*DiffSnapshotsResponse)(nil),             // 29: rpc.rpc.DiffSnapshotsResponse
+	(*RestoreSnapshotRequest)(nil),            // 30: rpc.rpc.RestoreSnapshotRequest
+	(*RestoreSnapshotResponse)(nil),           // 31: rpc.rpc.RestoreSnapshotResponse
+	(*WatchBucketRequest)(nil),                // 32: rpc.rpc.WatchBucketRequest
+	(*BucketChangeEvent)(nil),                 // 33: rpc.rpc.BucketChangeEvent
+	(*BucketQuota)(nil),                       // 34: rpc.rpc.BucketQuota
+	(*BucketUsage)(nil),                       // 35: rpc.rpc.BucketUsage
+	(*GetBucketQuotaRequest)(nil),             // 36: rpc.rpc.GetBucketQuotaRequest
+	(*SetBucketQuotaRequest)(nil),             // 37: rpc.rpc.SetBucketQuotaRequest
+	(*BucketQuotaResponse)(nil),               // 38: rpc.rpc.BucketQuotaResponse
+	(*BatchOperation)(nil),                    // 39: rpc.rpc.BatchOperation
+	(*ApplyBatchRequest)(nil),                 // 40: rpc.rpc.ApplyBatchRequest
+	(*ApplyBatchResponse)(nil),                // 41: rpc.rpc.ApplyBatchResponse
+	nil,                                       // 42: rpc.rpc.CreateBucketFromZipRequest.HeadersEntry
 }
 var file_rpc_proto_depIdxs = []int32{
 	0,  // 0: rpc.rpc.FileContent.file_info:type_name -> rpc.rpc.FileInfo
-	19, // 1: rpc.rpc.CreateBucketFromZipRequest.headers:type_name -> rpc.rpc.CreateBucketFromZipRequest.HeadersEntry
+	42, // 1: rpc.rpc.CreateBucketFromZipRequest.headers:type_name -> rpc.rpc.CreateBucketFromZipRequest.HeadersEntry
 	4,  // 2: rpc.rpc.CreateBucketFromContentsRequest.contents:type_name -> rpc.rpc.FileContentsBase
 	1,  // 3: rpc.rpc.GetBucketFileResponse.content:type_name -> rpc.rpc.FileContent
 	0,  // 4: rpc.rpc.GetBucketFilesResponse.files:type_name -> rpc.rpc.FileInfo
 	1,  // 5: rpc.rpc.GetBucketFilesWithContentResponse.files:type_name -> rpc.rpc.FileContent
-	2,  // 6: rpc.rpc.CodeBucket.CloneBucket:input_type -> rpc.rpc.CloneBucketRequest
-	5,  // 7: rpc.rpc.CodeBucket.CreateBucketFromContents:input_type -> rpc.rpc.CreateBucketFromContentsRequest
-	3,  // 8: rpc.rpc.CodeBucket.CreateBucketFromZip:input_type -> rpc.rpc.CreateBucketFromZipRequest
-	6,  // 9: rpc.rpc.CodeBucket.CreateBucketFromGithub:input_type -> rpc.rpc.CreateBucketFromGithubRequest
-	8,  // 10: rpc.rpc.CodeBucket.GetBucketToken:input_type -> rpc.rpc.GetBucketTokenRequest
-	10, // 11: rpc.rpc.CodeBucket.GetBucketFile:input_type -> rpc.rpc.GetBucketFileRequest
-	12, // 12: rpc.rpc.CodeBucket.GetBucketFiles:input_type -> rpc.rpc.GetBucketFilesRequest
-	12, // 13: rpc.rpc.CodeBucket.GetBucketFilesWithContent:input_type -> rpc.rpc.GetBucketFilesRequest
-	15, // 14: rpc.rpc.CodeBucket.GetBucketFilesAsZip:input_type -> rpc.rpc.GetBucketFilesAsZipRequest
-	17, // 15: rpc.rpc.CodeBucket.ExportBucketToGithub:input_type -> rpc.rpc.ExportBucketToGithubRequest
-	7,  // 16: rpc.rpc.CodeBucket.CloneBucket:output_type -> rpc.rpc.CreateBucketResponse
-	7,  // 17: rpc.rpc.CodeBucket.CreateBucketFromContents:output_type -> rpc.rpc.CreateBucketResponse
-	7,  // 18: rpc.rpc.CodeBucket.CreateBucketFromZip:output_type -> rpc.rpc.CreateBucketResponse
-	7,  // 19: rpc.rpc.CodeBucket.CreateBucketFromGithub:output_type -> rpc.rpc.CreateBucketResponse
-	9,  // 20: rpc.rpc.CodeBucket.GetBucketToken:output_type -> rpc.rpc.GetBucketTokenResponse
-	11, // 21: rpc.rpc.CodeBucket.GetBucketFile:output_type -> rpc.rpc.GetBucketFileResponse
-	13, // 22: rpc.rpc.CodeBucket.GetBucketFiles:output_type -> rpc.rpc.GetBucketFilesResponse
-	14, // 23: rpc.rpc.CodeBucket.GetBucketFilesWithContent:output_type -> rpc.rpc.GetBucketFilesWithContentResponse
-	16, // 24: rpc.rpc.CodeBucket.GetBucketFilesAsZip:output_type -> rpc.rpc.GetBucketFilesAsZipResponse
-	18, // 25: rpc.rpc.CodeBucket.ExportBucketToGithub:output_type -> rpc.rpc.ExportBucketToGithubResponse
-	16, // [16:26] is the sub-list for method output_type
-	6,  // [6:16] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	22, // 6: rpc.rpc.SnapshotResponse.snapshot:type_name -> rpc.rpc.Snapshot
+	22, // 7: rpc.rpc.ListSnapshotsResponse.snapshots:type_name -> rpc.rpc.Snapshot
+	28, // 8: rpc.rpc.DiffSnapshotsResponse.changes:type_name -> rpc.rpc.SnapshotDiffEntry
+	34, // 9: rpc.rpc.SetBucketQuotaRequest.quota:type_name -> rpc.rpc.BucketQuota
+	34, // 10: rpc.rpc.BucketQuotaResponse.quota:type_name -> rpc.rpc.BucketQuota
+	35, // 11: rpc.rpc.BucketQuotaResponse.usage:type_name -> rpc.rpc.BucketUsage
+	39, // 12: rpc.rpc.ApplyBatchRequest.operations:type_name -> rpc.rpc.BatchOperation
+	2,  // 13: rpc.rpc.CodeBucket.CloneBucket:input_type -> rpc.rpc.CloneBucketRequest
+	5,  // 14: rpc.rpc.CodeBucket.CreateBucketFromContents:input_type -> rpc.rpc.CreateBucketFromContentsRequest
+	3,  // 15: rpc.rpc.CodeBucket.CreateBucketFromZip:input_type -> rpc.rpc.CreateBucketFromZipRequest
+	6,  // 16: rpc.rpc.CodeBucket.CreateBucketFromGithub:input_type -> rpc.rpc.CreateBucketFromGithubRequest
+	7,  // 17: rpc.rpc.CodeBucket.CreateBucketFromGit:input_type -> rpc.rpc.CreateBucketFromGitRequest
+	9,  // 18: rpc.rpc.CodeBucket.GetBucketToken:input_type -> rpc.rpc.GetBucketTokenRequest
+	11, // 19: rpc.rpc.CodeBucket.GetBucketFile:input_type -> rpc.rpc.GetBucketFileRequest
+	13, // 20: rpc.rpc.CodeBucket.GetBucketFiles:input_type -> rpc.rpc.GetBucketFilesRequest
+	13, // 21: rpc.rpc.CodeBucket.GetBucketFilesWithContent:input_type -> rpc.rpc.GetBucketFilesRequest
+	16, // 22: rpc.rpc.CodeBucket.GetBucketFilesAsZip:input_type -> rpc.rpc.GetBucketFilesAsZipRequest
+	18, // 23: rpc.rpc.CodeBucket.ExportBucketToGithub:input_type -> rpc.rpc.ExportBucketToGithubRequest
+	20, // 24: rpc.rpc.CodeBucket.ExportBucketToGit:input_type -> rpc.rpc.ExportBucketToGitRequest
+	23, // 25: rpc.rpc.CodeBucket.CreateSnapshot:input_type -> rpc.rpc.CreateSnapshotRequest
+	25, // 26: rpc.rpc.CodeBucket.ListSnapshots:input_type -> rpc.rpc.ListSnapshotsRequest
+	27, // 27: rpc.rpc.CodeBucket.DiffSnapshots:input_type -> rpc.rpc.DiffSnapshotsRequest
+	30, // 28: rpc.rpc.CodeBucket.RestoreSnapshot:input_type -> rpc.rpc.RestoreSnapshotRequest
+	32, // 29: rpc.rpc.CodeBucket.WatchBucket:input_type -> rpc.rpc.WatchBucketRequest
+	36, // 30: rpc.rpc.CodeBucket.GetBucketQuota:input_type -> rpc.rpc.GetBucketQuotaRequest
+	37, // 31: rpc.rpc.CodeBucket.SetBucketQuota:input_type -> rpc.rpc.SetBucketQuotaRequest
+	40, // 32: rpc.rpc.CodeBucket.ApplyBatch:input_type -> rpc.rpc.ApplyBatchRequest
+	8,  // 33: rpc.rpc.CodeBucket.CloneBucket:output_type -> rpc.rpc.CreateBucketResponse
+	8,  // 34: rpc.rpc.CodeBucket.CreateBucketFromContents:output_type -> rpc.rpc.CreateBucketResponse
+	8,  // 35: rpc.rpc.CodeBucket.CreateBucketFromZip:output_type -> rpc.rpc.CreateBucketResponse
+	8,  // 36: rpc.rpc.CodeBucket.CreateBucketFromGithub:output_type -> rpc.rpc.CreateBucketResponse
+	8,  // 37: rpc.rpc.CodeBucket.CreateBucketFromGit:output_type -> rpc.rpc.CreateBucketResponse
+	10, // 38: rpc.rpc.CodeBucket.GetBucketToken:output_type -> rpc.rpc.GetBucketTokenResponse
+	12, // 39: rpc.rpc.CodeBucket.GetBucketFile:output_type -> rpc.rpc.GetBucketFileResponse
+	14, // 40: rpc.rpc.CodeBucket.GetBucketFiles:output_type -> rpc.rpc.GetBucketFilesResponse
+	15, // 41: rpc.rpc.CodeBucket.GetBucketFilesWithContent:output_type -> rpc.rpc.GetBucketFilesWithContentResponse
+	17, // 42: rpc.rpc.CodeBucket.GetBucketFilesAsZip:output_type -> rpc.rpc.GetBucketFilesAsZipResponse
+	19, // 43: rpc.rpc.CodeBucket.ExportBucketToGithub:output_type -> rpc.rpc.ExportBucketToGithubResponse
+	21, // 44: rpc.rpc.CodeBucket.ExportBucketToGit:output_type -> rpc.rpc.ExportBucketToGitResponse
+	24, // 45: rpc.rpc.CodeBucket.CreateSnapshot:output_type -> rpc.rpc.SnapshotResponse
+	26, // 46: rpc.rpc.CodeBucket.ListSnapshots:output_type -> rpc.rpc.ListSnapshotsResponse
+	29, // 47: rpc.rpc.CodeBucket.DiffSnapshots:output_type -> rpc.rpc.DiffSnapshotsResponse
+	31, // 48: rpc.rpc.CodeBucket.RestoreSnapshot:output_type -> rpc.rpc.RestoreSnapshotResponse
+	33, // 49: rpc.rpc.CodeBucket.WatchBucket:output_type -> rpc.rpc.BucketChangeEvent
+	38, // 50: rpc.rpc.CodeBucket.GetBucketQuota:output_type -> rpc.rpc.BucketQuotaResponse
+	38, // 51: rpc.rpc.CodeBucket.SetBucketQuota:output_type -> rpc.rpc.BucketQuotaResponse
+	41, // 52: rpc.rpc.CodeBucket.ApplyBatch:output_type -> rpc.rpc.ApplyBatchResponse
+	33, // [33:53] is the sub-list for method output_type
+	13, // [13:33] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_rpc_proto_init() }
@@ -1206,7 +2732,7 @@ func file_rpc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_proto_rawDesc), len(file_rpc_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   20,
+			NumMessages:   43,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
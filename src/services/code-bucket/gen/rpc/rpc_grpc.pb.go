@@ -23,12 +23,22 @@ const (
 	CodeBucket_CreateBucketFromContents_FullMethodName  = "/rpc.rpc.CodeBucket/CreateBucketFromContents"
 	CodeBucket_CreateBucketFromZip_FullMethodName       = "/rpc.rpc.CodeBucket/CreateBucketFromZip"
 	CodeBucket_CreateBucketFromGithub_FullMethodName    = "/rpc.rpc.CodeBucket/CreateBucketFromGithub"
+	CodeBucket_CreateBucketFromGit_FullMethodName       = "/rpc.rpc.CodeBucket/CreateBucketFromGit"
 	CodeBucket_GetBucketToken_FullMethodName            = "/rpc.rpc.CodeBucket/GetBucketToken"
 	CodeBucket_GetBucketFile_FullMethodName             = "/rpc.rpc.CodeBucket/GetBucketFile"
 	CodeBucket_GetBucketFiles_FullMethodName            = "/rpc.rpc.CodeBucket/GetBucketFiles"
 	CodeBucket_GetBucketFilesWithContent_FullMethodName = "/rpc.rpc.CodeBucket/GetBucketFilesWithContent"
 	CodeBucket_GetBucketFilesAsZip_FullMethodName       = "/rpc.rpc.CodeBucket/GetBucketFilesAsZip"
 	CodeBucket_ExportBucketToGithub_FullMethodName      = "/rpc.rpc.CodeBucket/ExportBucketToGithub"
+	CodeBucket_ExportBucketToGit_FullMethodName         = "/rpc.rpc.CodeBucket/ExportBucketToGit"
+	CodeBucket_CreateSnapshot_FullMethodName            = "/rpc.rpc.CodeBucket/CreateSnapshot"
+	CodeBucket_ListSnapshots_FullMethodName             = "/rpc.rpc.CodeBucket/ListSnapshots"
+	CodeBucket_DiffSnapshots_FullMethodName             = "/rpc.rpc.CodeBucket/DiffSnapshots"
+	CodeBucket_RestoreSnapshot_FullMethodName           = "/rpc.rpc.CodeBucket/RestoreSnapshot"
+	CodeBucket_WatchBucket_FullMethodName               = "/rpc.rpc.CodeBucket/WatchBucket"
+	CodeBucket_GetBucketQuota_FullMethodName            = "/rpc.rpc.CodeBucket/GetBucketQuota"
+	CodeBucket_SetBucketQuota_FullMethodName            = "/rpc.rpc.CodeBucket/SetBucketQuota"
+	CodeBucket_ApplyBatch_FullMethodName                = "/rpc.rpc.CodeBucket/ApplyBatch"
 )
 
 // CodeBucketClient is the client API for CodeBucket service.
@@ -39,12 +49,22 @@ type CodeBucketClient interface {
 	CreateBucketFromContents(ctx context.Context, in *CreateBucketFromContentsRequest, opts ...grpc.CallOption) (*CreateBucketResponse, error)
 	CreateBucketFromZip(ctx context.Context, in *CreateBucketFromZipRequest, opts ...grpc.CallOption) (*CreateBucketResponse, error)
 	CreateBucketFromGithub(ctx context.Context, in *CreateBucketFromGithubRequest, opts ...grpc.CallOption) (*CreateBucketResponse, error)
+	CreateBucketFromGit(ctx context.Context, in *CreateBucketFromGitRequest, opts ...grpc.CallOption) (*CreateBucketResponse, error)
 	GetBucketToken(ctx context.Context, in *GetBucketTokenRequest, opts ...grpc.CallOption) (*GetBucketTokenResponse, error)
 	GetBucketFile(ctx context.Context, in *GetBucketFileRequest, opts ...grpc.CallOption) (*GetBucketFileResponse, error)
 	GetBucketFiles(ctx context.Context, in *GetBucketFilesRequest, opts ...grpc.CallOption) (*GetBucketFilesResponse, error)
 	GetBucketFilesWithContent(ctx context.Context, in *GetBucketFilesRequest, opts ...grpc.CallOption) (*GetBucketFilesWithContentResponse, error)
 	GetBucketFilesAsZip(ctx context.Context, in *GetBucketFilesAsZipRequest, opts ...grpc.CallOption) (*GetBucketFilesAsZipResponse, error)
 	ExportBucketToGithub(ctx context.Context, in *ExportBucketToGithubRequest, opts ...grpc.CallOption) (*ExportBucketToGithubResponse, error)
+	ExportBucketToGit(ctx context.Context, in *ExportBucketToGitRequest, opts ...grpc.CallOption) (*ExportBucketToGitResponse, error)
+	CreateSnapshot(ctx context.Context, in *CreateSnapshotRequest, opts ...grpc.CallOption) (*SnapshotResponse, error)
+	ListSnapshots(ctx context.Context, in *ListSnapshotsRequest, opts ...grpc.CallOption) (*ListSnapshotsResponse, error)
+	DiffSnapshots(ctx context.Context, in *DiffSnapshotsRequest, opts ...grpc.CallOption) (*DiffSnapshotsResponse, error)
+	RestoreSnapshot(ctx context.Context, in *RestoreSnapshotRequest, opts ...grpc.CallOption) (*RestoreSnapshotResponse, error)
+	WatchBucket(ctx context.Context, in *WatchBucketRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BucketChangeEvent], error)
+	GetBucketQuota(ctx context.Context, in *GetBucketQuotaRequest, opts ...grpc.CallOption) (*BucketQuotaResponse, error)
+	SetBucketQuota(ctx context.Context, in *SetBucketQuotaRequest, opts ...grpc.CallOption) (*BucketQuotaResponse, error)
+	ApplyBatch(ctx context.Context, in *ApplyBatchRequest, opts ...grpc.CallOption) (*ApplyBatchResponse, error)
 }
 
 type codeBucketClient struct {
@@ -95,6 +115,16 @@ func (c *codeBucketClient) CreateBucketFromGithub(ctx context.Context, in *Creat
 	return out, nil
 }
 
+func (c *codeBucketClient) CreateBucketFromGit(ctx context.Context, in *CreateBucketFromGitRequest, opts ...grpc.CallOption) (*CreateBucketResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateBucketResponse)
+	err := c.cc.Invoke(ctx, CodeBucket_CreateBucketFromGit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *codeBucketClient) GetBucketToken(ctx context.Context, in *GetBucketTokenRequest, opts ...grpc.CallOption) (*GetBucketTokenResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetBucketTokenResponse)
@@ -155,6 +185,105 @@ func (c *codeBucketClient) ExportBucketToGithub(ctx context.Context, in *ExportB
 	return out, nil
 }
 
+func (c *codeBucketClient) ExportBucketToGit(ctx context.Context, in *ExportBucketToGitRequest, opts ...grpc.CallOption) (*ExportBucketToGitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportBucketToGitResponse)
+	err := c.cc.Invoke(ctx, CodeBucket_ExportBucketToGit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *codeBucketClient) CreateSnapshot(ctx context.Context, in *CreateSnapshotRequest, opts ...grpc.CallOption) (*SnapshotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SnapshotResponse)
+	err := c.cc.Invoke(ctx, CodeBucket_CreateSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *codeBucketClient) ListSnapshots(ctx context.Context, in *ListSnapshotsRequest, opts ...grpc.CallOption) (*ListSnapshotsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSnapshotsResponse)
+	err := c.cc.Invoke(ctx, CodeBucket_ListSnapshots_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *codeBucketClient) DiffSnapshots(ctx context.Context, in *DiffSnapshotsRequest, opts ...grpc.CallOption) (*DiffSnapshotsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiffSnapshotsResponse)
+	err := c.cc.Invoke(ctx, CodeBucket_DiffSnapshots_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *codeBucketClient) RestoreSnapshot(ctx context.Context, in *RestoreSnapshotRequest, opts ...grpc.CallOption) (*RestoreSnapshotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreSnapshotResponse)
+	err := c.cc.Invoke(ctx, CodeBucket_RestoreSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *codeBucketClient) WatchBucket(ctx context.Context, in *WatchBucketRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BucketChangeEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CodeBucket_ServiceDesc.Streams[0], CodeBucket_WatchBucket_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchBucketRequest, BucketChangeEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CodeBucket_WatchBucketClient = grpc.ServerStreamingClient[BucketChangeEvent]
+
+func (c *codeBucketClient) GetBucketQuota(ctx context.Context, in *GetBucketQuotaRequest, opts ...grpc.CallOption) (*BucketQuotaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BucketQuotaResponse)
+	err := c.cc.Invoke(ctx, CodeBucket_GetBucketQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *codeBucketClient) SetBucketQuota(ctx context.Context, in *SetBucketQuotaRequest, opts ...grpc.CallOption) (*BucketQuotaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BucketQuotaResponse)
+	err := c.cc.Invoke(ctx, CodeBucket_SetBucketQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *codeBucketClient) ApplyBatch(ctx context.Context, in *ApplyBatchRequest, opts ...grpc.CallOption) (*ApplyBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApplyBatchResponse)
+	err := c.cc.Invoke(ctx, CodeBucket_ApplyBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CodeBucketServer is the server API for CodeBucket service.
 // All implementations must embed UnimplementedCodeBucketServer
 // for forward compatibility.
@@ -163,12 +292,22 @@ type CodeBucketServer interface {
 	CreateBucketFromContents(context.Context, *CreateBucketFromContentsRequest) (*CreateBucketResponse, error)
 	CreateBucketFromZip(context.Context, *CreateBucketFromZipRequest) (*CreateBucketResponse, error)
 	CreateBucketFromGithub(context.Context, *CreateBucketFromGithubRequest) (*CreateBucketResponse, error)
+	CreateBucketFromGit(context.Context, *CreateBucketFromGitRequest) (*CreateBucketResponse, error)
 	GetBucketToken(context.Context, *GetBucketTokenRequest) (*GetBucketTokenResponse, error)
 	GetBucketFile(context.Context, *GetBucketFileRequest) (*GetBucketFileResponse, error)
 	GetBucketFiles(context.Context, *GetBucketFilesRequest) (*GetBucketFilesResponse, error)
 	GetBucketFilesWithContent(context.Context, *GetBucketFilesRequest) (*GetBucketFilesWithContentResponse, error)
 	GetBucketFilesAsZip(context.Context, *GetBucketFilesAsZipRequest) (*GetBucketFilesAsZipResponse, error)
 	ExportBucketToGithub(context.Context, *ExportBucketToGithubRequest) (*ExportBucketToGithubResponse, error)
+	ExportBucketToGit(context.Context, *ExportBucketToGitRequest) (*ExportBucketToGitResponse, error)
+	CreateSnapshot(context.Context, *CreateSnapshotRequest) (*SnapshotResponse, error)
+	ListSnapshots(context.Context, *ListSnapshotsRequest) (*ListSnapshotsResponse, error)
+	DiffSnapshots(context.Context, *DiffSnapshotsRequest) (*DiffSnapshotsResponse, error)
+	RestoreSnapshot(context.Context, *RestoreSnapshotRequest) (*RestoreSnapshotResponse, error)
+	WatchBucket(*WatchBucketRequest, grpc.ServerStreamingServer[BucketChangeEvent]) error
+	GetBucketQuota(context.Context, *GetBucketQuotaRequest) (*BucketQuotaResponse, error)
+	SetBucketQuota(context.Context, *SetBucketQuotaRequest) (*BucketQuotaResponse, error)
+	ApplyBatch(context.Context, *ApplyBatchRequest) (*ApplyBatchResponse, error)
 	mustEmbedUnimplementedCodeBucketServer()
 }
 
@@ -191,6 +330,9 @@ func (UnimplementedCodeBucketServer) CreateBucketFromZip(context.Context, *Creat
 func (UnimplementedCodeBucketServer) CreateBucketFromGithub(context.Context, *CreateBucketFromGithubRequest) (*CreateBucketResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateBucketFromGithub not implemented")
 }
+func (UnimplementedCodeBucketServer) CreateBucketFromGit(context.Context, *CreateBucketFromGitRequest) (*CreateBucketResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBucketFromGit not implemented")
+}
 func (UnimplementedCodeBucketServer) GetBucketToken(context.Context, *GetBucketTokenRequest) (*GetBucketTokenResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBucketToken not implemented")
 }
@@ -209,6 +351,33 @@ func (UnimplementedCodeBucketServer) GetBucketFilesAsZip(context.Context, *GetBu
 func (UnimplementedCodeBucketServer) ExportBucketToGithub(context.Context, *ExportBucketToGithubRequest) (*ExportBucketToGithubResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExportBucketToGithub not implemented")
 }
+func (UnimplementedCodeBucketServer) ExportBucketToGit(context.Context, *ExportBucketToGitRequest) (*ExportBucketToGitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportBucketToGit not implemented")
+}
+func (UnimplementedCodeBucketServer) CreateSnapshot(context.Context, *CreateSnapshotRequest) (*SnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSnapshot not implemented")
+}
+func (UnimplementedCodeBucketServer) ListSnapshots(context.Context, *ListSnapshotsRequest) (*ListSnapshotsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSnapshots not implemented")
+}
+func (UnimplementedCodeBucketServer) DiffSnapshots(context.Context, *DiffSnapshotsRequest) (*DiffSnapshotsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffSnapshots not implemented")
+}
+func (UnimplementedCodeBucketServer) RestoreSnapshot(context.Context, *RestoreSnapshotRequest) (*RestoreSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreSnapshot not implemented")
+}
+func (UnimplementedCodeBucketServer) WatchBucket(*WatchBucketRequest, grpc.ServerStreamingServer[BucketChangeEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchBucket not implemented")
+}
+func (UnimplementedCodeBucketServer) GetBucketQuota(context.Context, *GetBucketQuotaRequest) (*BucketQuotaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBucketQuota not implemented")
+}
+func (UnimplementedCodeBucketServer) SetBucketQuota(context.Context, *SetBucketQuotaRequest) (*BucketQuotaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetBucketQuota not implemented")
+}
+func (UnimplementedCodeBucketServer) ApplyBatch(context.Context, *ApplyBatchRequest) (*ApplyBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyBatch not implemented")
+}
 func (UnimplementedCodeBucketServer) mustEmbedUnimplementedCodeBucketServer() {}
 func (UnimplementedCodeBucketServer) testEmbeddedByValue()                    {}
 
@@ -302,6 +471,24 @@ func _CodeBucket_CreateBucketFromGithub_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CodeBucket_CreateBucketFromGit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBucketFromGitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CodeBucketServer).CreateBucketFromGit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CodeBucket_CreateBucketFromGit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CodeBucketServer).CreateBucketFromGit(ctx, req.(*CreateBucketFromGitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CodeBucket_GetBucketToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetBucketTokenRequest)
 	if err := dec(in); err != nil {
@@ -410,6 +597,161 @@ func _CodeBucket_ExportBucketToGithub_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CodeBucket_ExportBucketToGit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportBucketToGitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CodeBucketServer).ExportBucketToGit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CodeBucket_ExportBucketToGit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CodeBucketServer).ExportBucketToGit(ctx, req.(*ExportBucketToGitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CodeBucket_CreateSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CodeBucketServer).CreateSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CodeBucket_CreateSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CodeBucketServer).CreateSnapshot(ctx, req.(*CreateSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CodeBucket_ListSnapshots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSnapshotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CodeBucketServer).ListSnapshots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CodeBucket_ListSnapshots_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CodeBucketServer).ListSnapshots(ctx, req.(*ListSnapshotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CodeBucket_DiffSnapshots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffSnapshotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CodeBucketServer).DiffSnapshots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CodeBucket_DiffSnapshots_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CodeBucketServer).DiffSnapshots(ctx, req.(*DiffSnapshotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CodeBucket_RestoreSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CodeBucketServer).RestoreSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CodeBucket_RestoreSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CodeBucketServer).RestoreSnapshot(ctx, req.(*RestoreSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CodeBucket_WatchBucket_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchBucketRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CodeBucketServer).WatchBucket(m, &grpc.GenericServerStream[WatchBucketRequest, BucketChangeEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CodeBucket_WatchBucketServer = grpc.ServerStreamingServer[BucketChangeEvent]
+
+func _CodeBucket_GetBucketQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBucketQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CodeBucketServer).GetBucketQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CodeBucket_GetBucketQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CodeBucketServer).GetBucketQuota(ctx, req.(*GetBucketQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CodeBucket_SetBucketQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBucketQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CodeBucketServer).SetBucketQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CodeBucket_SetBucketQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CodeBucketServer).SetBucketQuota(ctx, req.(*SetBucketQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CodeBucket_ApplyBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CodeBucketServer).ApplyBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CodeBucket_ApplyBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CodeBucketServer).ApplyBatch(ctx, req.(*ApplyBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CodeBucket_ServiceDesc is the grpc.ServiceDesc for CodeBucket service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -433,6 +775,10 @@ var CodeBucket_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateBucketFromGithub",
 			Handler:    _CodeBucket_CreateBucketFromGithub_Handler,
 		},
+		{
+			MethodName: "CreateBucketFromGit",
+			Handler:    _CodeBucket_CreateBucketFromGit_Handler,
+		},
 		{
 			MethodName: "GetBucketToken",
 			Handler:    _CodeBucket_GetBucketToken_Handler,
@@ -457,7 +803,45 @@ var CodeBucket_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ExportBucketToGithub",
 			Handler:    _CodeBucket_ExportBucketToGithub_Handler,
 		},
+		{
+			MethodName: "ExportBucketToGit",
+			Handler:    _CodeBucket_ExportBucketToGit_Handler,
+		},
+		{
+			MethodName: "CreateSnapshot",
+			Handler:    _CodeBucket_CreateSnapshot_Handler,
+		},
+		{
+			MethodName: "ListSnapshots",
+			Handler:    _CodeBucket_ListSnapshots_Handler,
+		},
+		{
+			MethodName: "DiffSnapshots",
+			Handler:    _CodeBucket_DiffSnapshots_Handler,
+		},
+		{
+			MethodName: "RestoreSnapshot",
+			Handler:    _CodeBucket_RestoreSnapshot_Handler,
+		},
+		{
+			MethodName: "GetBucketQuota",
+			Handler:    _CodeBucket_GetBucketQuota_Handler,
+		},
+		{
+			MethodName: "SetBucketQuota",
+			Handler:    _CodeBucket_SetBucketQuota_Handler,
+		},
+		{
+			MethodName: "ApplyBatch",
+			Handler:    _CodeBucket_ApplyBatch_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchBucket",
+			Handler:       _CodeBucket_WatchBucket_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpc.proto",
 }
@@ -2,10 +2,12 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/gorilla/mux"
@@ -35,11 +37,21 @@ func newHttpServiceRouter(service *Service) *mux.Router {
 	httpRouter.HandleFunc("/files/{path:.*}", hs.handlePutFile).Methods("PUT")
 	httpRouter.HandleFunc("/files/{path:.*}", hs.handleDeleteFile).Methods("DELETE")
 	httpRouter.HandleFunc("/files/{path:.*}", hs.handleOptions).Methods("OPTIONS")
+	httpRouter.HandleFunc("/snapshots", hs.handleGetSnapshots).Methods("GET")
+	httpRouter.HandleFunc("/changes", hs.handleWatchChanges).Methods("GET")
+	httpRouter.HandleFunc("/batch", hs.handleBatch).Methods("POST")
+	httpRouter.HandleFunc("/batch", hs.handleOptions).Methods("OPTIONS")
 
 	return httpRouter
 }
 
-func (hs *HttpService) authenticateRequest(r *http.Request) (string, error) {
+// maxBatchBodyBytes limits the body of batch requests. Contents are base64
+// encoded, so a batch can hold about three quarters of it.
+const maxBatchBodyBytes = 64 * 1024 * 1024
+
+var errReadOnlyToken = errors.New("token is read-only")
+
+func (hs *HttpService) parseClaims(r *http.Request) (*Claims, error) {
 	authHeader := r.Header.Get("Authorization")
 	authQuery := r.URL.Query().Get("metorial-code-bucket-token")
 
@@ -47,14 +59,14 @@ func (hs *HttpService) authenticateRequest(r *http.Request) (string, error) {
 
 	if authHeader != "" {
 		if !strings.HasPrefix(authHeader, "Bearer ") {
-			return "", fmt.Errorf("missing or invalid authorization header")
+			return nil, fmt.Errorf("missing or invalid authorization header")
 		}
 
 		tokenString = strings.TrimPrefix(authHeader, "Bearer ")
 	}
 
 	if tokenString == "" {
-		return "", fmt.Errorf("missing authorization token")
+		return nil, fmt.Errorf("missing authorization token")
 	}
 
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (any, error) {
@@ -65,20 +77,47 @@ func (hs *HttpService) authenticateRequest(r *http.Request) (string, error) {
 	})
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims.BucketID, nil
+		return claims, nil
+	}
+
+	return nil, fmt.Errorf("invalid token")
+}
+
+func (hs *HttpService) authenticateRequest(r *http.Request) (string, error) {
+	claims, err := hs.parseClaims(r)
+	if err != nil {
+		return "", err
+	}
+
+	return claims.BucketID, nil
+}
+
+// authenticateWrite authenticates a batch request, which read-only tokens
+// may not make.
+func (hs *HttpService) authenticateWrite(w http.ResponseWriter, r *http.Request) (string, bool) {
+	claims, err := hs.parseClaims(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return "", false
 	}
 
-	return "", fmt.Errorf("invalid token")
+	if claims.IsReadOnly {
+		http.Error(w, errReadOnlyToken.Error(), http.StatusForbidden)
+		return "", false
+	}
+
+	return claims.BucketID, true
 }
 
 func (hs *HttpService) setCorsHeaders(w http.ResponseWriter) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Last-Event-ID, If-Match")
+	w.Header().Set("Access-Control-Expose-Headers", "ETag")
 }
 
 func (hs *HttpService) handleGetFiles(w http.ResponseWriter, r *http.Request) {
@@ -91,12 +130,22 @@ func (hs *HttpService) handleGetFiles(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	files, err := hs.fsm.GetBucketFiles(r.Context(), authBucketID, "")
+	files, err := hs.fsm.GetBucketFilesAt(r.Context(), authBucketID, r.URL.Query().Get("snapshot"), "")
 	if err != nil {
+		if errors.Is(err, fs.ErrSnapshotNotFound) {
+			http.Error(w, "Snapshot not found", http.StatusNotFound)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	if r.URL.Query().Get("snapshot") == "" {
+		if revision, err := hs.fsm.GetBucketRevision(r.Context(), authBucketID); err == nil {
+			w.Header().Set("ETag", fmt.Sprintf("%q", revision))
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(files)
 }
@@ -114,9 +163,11 @@ func (hs *HttpService) handleGetFile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, content, err := hs.fsm.GetBucketFile(r.Context(), authBucketID, filePath)
+	_, content, err := hs.fsm.GetBucketFileAt(r.Context(), authBucketID, r.URL.Query().Get("snapshot"), filePath)
 	if err != nil {
-		if err.Error() == "file not found" {
+		if errors.Is(err, fs.ErrSnapshotNotFound) {
+			http.Error(w, "Snapshot not found", http.StatusNotFound)
+		} else if err.Error() == "file not found" {
 			http.Error(w, "File not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -141,8 +192,24 @@ func (hs *HttpService) handlePutFile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	content, err := io.ReadAll(r.Body)
+	quota, err := hs.fsm.GetBucketQuota(r.Context(), authBucketID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	body := r.Body
+	if quota.MaxFileBytes > 0 {
+		body = http.MaxBytesReader(w, r.Body, quota.MaxFileBytes)
+	}
+
+	content, err := io.ReadAll(body)
 	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, fmt.Sprintf("file exceeds the limit of %d bytes", quota.MaxFileBytes), http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -154,7 +221,14 @@ func (hs *HttpService) handlePutFile(w http.ResponseWriter, r *http.Request) {
 
 	err = hs.fsm.PutBucketFile(r.Context(), authBucketID, filePath, content, contentType)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, fs.ErrQuotaExceeded):
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		case errors.Is(err, fs.ErrInvalidPath):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
 		return
 	}
 
@@ -187,6 +261,151 @@ func (hs *HttpService) handleDeleteFile(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (hs *HttpService) handleGetSnapshots(w http.ResponseWriter, r *http.Request) {
+	hs.setCorsHeaders(w)
+
+	// Authenticate
+	authBucketID, err := hs.authenticateRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	snapshots, err := hs.fsm.ListSnapshots(r.Context(), authBucketID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snapshots)
+}
+
+// handleWatchChanges streams the changes of the bucket as server-sent
+// events. Clients resume with the Last-Event-ID header or the
+// after query parameter.
+func (hs *HttpService) handleWatchChanges(w http.ResponseWriter, r *http.Request) {
+	hs.setCorsHeaders(w)
+
+	// Authenticate
+	authBucketID, err := hs.authenticateRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	afterID := r.Header.Get("Last-Event-ID")
+	if afterID == "" {
+		afterID = r.URL.Query().Get("after")
+	}
+
+	events, err := hs.fsm.WatchBucket(r.Context(), authBucketID, afterID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			data, _ := json.Marshal(event)
+			fmt.Fprintf(w, "id: %s\nevent: change\ndata: %s\n\n", event.ID, data)
+			flusher.Flush()
+
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// handleBatch applies several file changes at once, all or nothing. An
+// If-Match header with a revision from the ETag of the file listing or a
+// previous batch makes it fail with 412 if the bucket changed since.
+func (hs *HttpService) handleBatch(w http.ResponseWriter, r *http.Request) {
+	hs.setCorsHeaders(w)
+
+	// Authenticate
+	authBucketID, ok := hs.authenticateWrite(w, r)
+	if !ok {
+		return
+	}
+
+	quota, err := hs.fsm.GetBucketQuota(r.Context(), authBucketID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	limit := int64(maxBatchBodyBytes)
+	if quota.MaxTotalBytes > 0 && quota.MaxTotalBytes*4/3+1024*1024 < limit {
+		limit = quota.MaxTotalBytes*4/3 + 1024*1024
+	}
+
+	var req struct {
+		Operations []fs.BatchOperation `json:"operations"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, limit)).Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, fmt.Sprintf("batch exceeds the limit of %d bytes", limit), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	expectedRevision := strings.Trim(strings.TrimPrefix(r.Header.Get("If-Match"), "W/"), `"`)
+	if expectedRevision == "*" {
+		expectedRevision = ""
+	}
+
+	revision, err := hs.fsm.ApplyBatch(r.Context(), authBucketID, expectedRevision, req.Operations)
+	if err != nil {
+		switch {
+		case errors.Is(err, fs.ErrRevisionMismatch):
+			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		case errors.Is(err, fs.ErrBatchConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, fs.ErrQuotaExceeded):
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		case errors.Is(err, fs.ErrInvalidBatch), errors.Is(err, fs.ErrInvalidPath):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("ETag", fmt.Sprintf("%q", revision))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"revision": revision,
+	})
+}
+
 func (hs *HttpService) handleOptions(w http.ResponseWriter, r *http.Request) {
 	hs.setCorsHeaders(w)
 	w.WriteHeader(http.StatusOK)
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/metorial/metorial/services/code-bucket/pkg/fs"
+)
+
+var testJwtSecret = []byte("bucket-secret")
+
+func newTestHttpService(t *testing.T) (*HttpService, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	fsm := fs.NewFileSystemManager(
+		fs.WithRedisURL("redis://"+mr.Addr()),
+		fs.WithAwsRegion("us-east-1"),
+	)
+
+	return &HttpService{fsm: fsm, jwtSecret: testJwtSecret}, mr
+}
+
+func signTestToken(t *testing.T, bucketID string) string {
+	t.Helper()
+
+	return signTestClaims(t, &Claims{BucketID: bucketID})
+}
+
+func signTestClaims(t *testing.T, claims *Claims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testJwtSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+// addChange appends a change the way the file system manager publishes
+// it.
+func addChange(t *testing.T, mr *miniredis.Miniredis, bucketID, filePath string) string {
+	t.Helper()
+
+	id, err := mr.XAdd("changes:"+bucketID, "*", []string{
+		"path", filePath,
+		"operation", fs.ChangeOperationPut,
+		"hash", "",
+		"timestamp", "0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return id
+}
+
+// readEventIDs reads the IDs of n server-sent events.
+func readEventIDs(t *testing.T, reader *bufio.Reader, n int) []string {
+	t.Helper()
+
+	var ids []string
+	for len(ids) < n {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading events after %v: %v", ids, err)
+		}
+
+		if id, ok := strings.CutPrefix(line, "id: "); ok {
+			ids = append(ids, strings.TrimSpace(id))
+		}
+	}
+	return ids
+}
+
+func TestHandleWatchChanges_Resume(t *testing.T) {
+	hs, mr := newTestHttpService(t)
+	server := httptest.NewServer(http.HandlerFunc(hs.handleWatchChanges))
+	defer server.Close()
+
+	first := addChange(t, mr, "bucket", "a.ts")
+	second := addChange(t, mr, "bucket", "b.ts")
+	third := addChange(t, mr, "bucket", "c.ts")
+	addChange(t, mr, "other", "d.ts")
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		after       string
+		want        []string
+	}{
+		{"last event id", first, "", []string{second, third}},
+		{"after", "", second, []string{third}},
+		{"last event id before after", second, first, []string{third}},
+	}
+	for _, tt := range tests {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+		url := server.URL + "/changes?metorial-code-bucket-token=" + signTestToken(t, "bucket")
+		if tt.after != "" {
+			url += "&after=" + tt.after
+		}
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if tt.lastEventID != "" {
+			req.Header.Set("Last-Event-ID", tt.lastEventID)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+			t.Errorf("%s: content type %q", tt.name, ct)
+		}
+
+		got := readEventIDs(t, bufio.NewReader(resp.Body), len(tt.want))
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got events %v, want %v", tt.name, got, tt.want)
+		}
+
+		resp.Body.Close()
+		cancel()
+	}
+}
+
+func TestHandleWatchChanges_ClientCancel(t *testing.T) {
+	hs, mr := newTestHttpService(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest(http.MethodGet, "/changes", nil).WithContext(ctx)
+	r.Header.Set("Authorization", "Bearer "+signTestToken(t, "bucket"))
+
+	recorder := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		hs.handleWatchChanges(recorder, r)
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	addChange(t, mr, "bucket", "a.ts")
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler did not return after the client went away")
+	}
+
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"path":"a.ts"`) {
+		t.Errorf("got status %d and body %q", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestHandleWatchChanges_Unauthorized(t *testing.T) {
+	hs, _ := newTestHttpService(t)
+
+	recorder := httptest.NewRecorder()
+	hs.handleWatchChanges(recorder, httptest.NewRequest(http.MethodGet, "/changes", nil))
+
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("got status %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAuthenticateWrite(t *testing.T) {
+	hs, _ := newTestHttpService(t)
+
+	tests := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"writable", signTestToken(t, "bucket"), 0},
+		{"read-only", signTestClaims(t, &Claims{BucketID: "bucket", IsReadOnly: true}), http.StatusForbidden},
+		{"missing", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/batch", nil)
+		if tt.token != "" {
+			r.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+
+		recorder := httptest.NewRecorder()
+		bucketID, ok := hs.authenticateWrite(recorder, r)
+
+		if tt.status == 0 && (!ok || bucketID != "bucket") {
+			t.Errorf("%s: authenticateWrite() = %q, %v, status %d", tt.name, bucketID, ok, recorder.Code)
+		}
+		if tt.status != 0 && (ok || recorder.Code != tt.status) {
+			t.Errorf("%s: authenticateWrite() = %v, status %d, want %d", tt.name, ok, recorder.Code, tt.status)
+		}
+	}
+
+	// Single file requests keep accepting read-only tokens
+	r := httptest.NewRequest(http.MethodPut, "/files/a.ts", nil)
+	r.Header.Set("Authorization", "Bearer "+signTestClaims(t, &Claims{BucketID: "bucket", IsReadOnly: true}))
+	if bucketID, err := hs.authenticateRequest(r); err != nil || bucketID != "bucket" {
+		t.Errorf("authenticateRequest() with a read-only token = %q, %v", bucketID, err)
+	}
+}
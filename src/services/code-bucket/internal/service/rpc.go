@@ -2,11 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/metorial/metorial/services/code-bucket/gen/rpc"
 	"github.com/metorial/metorial/services/code-bucket/pkg/fs"
+	gitTransport "github.com/metorial/metorial/services/code-bucket/pkg/git-transport"
 	"github.com/metorial/metorial/services/code-bucket/pkg/github"
 	zipImporter "github.com/metorial/metorial/services/code-bucket/pkg/zip-importer"
 
@@ -30,35 +32,86 @@ func newRcpService(service *Service) *RcpService {
 	return rs
 }
 
+// importError maps errors of writing bucket files to status errors.
+func importError(err error, message string) error {
+	switch {
+	case errors.Is(err, fs.ErrQuotaExceeded), errors.Is(err, zipImporter.ErrLimitExceeded):
+		return status.Errorf(codes.ResourceExhausted, "%s: %v", message, err)
+	case errors.Is(err, fs.ErrInvalidPath), errors.Is(err, fs.ErrInvalidBatch), errors.Is(err, fs.ErrInvalidSnapshotName):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", message, err)
+	case errors.Is(err, fs.ErrRevisionMismatch):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", message, err)
+	case errors.Is(err, fs.ErrBatchConflict):
+		return status.Errorf(codes.Aborted, "%s: %v", message, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", message, err)
+	}
+}
+
 func (rs *RcpService) CloneBucket(ctx context.Context, req *rpc.CloneBucketRequest) (*rpc.CreateBucketResponse, error) {
 	if err := rs.fsm.Clone(ctx, req.SourceBucketId, req.NewBucketId); err != nil {
-		return nil, err
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, importError(err, "failed to clone bucket")
 	}
 
 	return &rpc.CreateBucketResponse{}, nil
 }
 
 func (rs *RcpService) CreateBucketFromGithub(ctx context.Context, req *rpc.CreateBucketFromGithubRequest) (*rpc.CreateBucketResponse, error) {
-	iter, err := github.DownloadRepo(req.Owner, req.Repo, req.Path, req.Ref, req.Token)
+	limits, err := rs.fsm.ImportLimits(ctx, req.NewBucketId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get bucket quota: %v", err)
+	}
+
+	iter, err := github.DownloadRepo(req.Owner, req.Repo, req.Path, req.Ref, req.Token, limits)
+	if err != nil {
+		return nil, importError(err, "failed to download GitHub repository")
+	}
+	defer iter.Close()
+
+	if err := rs.fsm.ImportZip(ctx, req.NewBucketId, iter); err != nil {
+		return nil, importError(err, "failed to import zip")
+	}
+
+	return &rpc.CreateBucketResponse{}, nil
+}
+
+func (rs *RcpService) CreateBucketFromGit(ctx context.Context, req *rpc.CreateBucketFromGitRequest) (*rpc.CreateBucketResponse, error) {
+	limits, err := rs.fsm.ImportLimits(ctx, req.NewBucketId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get bucket quota: %v", err)
+	}
+
+	remote := gitTransport.Remote{URL: req.RemoteUrl, Username: req.Username, Token: req.Token}
+	iter, err := gitTransport.Clone(ctx, remote, req.Ref, req.Path, limits)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to download GitHub repository: %v", err)
+		return nil, importError(err, "failed to clone git repository")
 	}
+	defer iter.Close()
 
 	if err := rs.fsm.ImportZip(ctx, req.NewBucketId, iter); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to import zip: %v", err)
+		return nil, importError(err, "failed to import repository")
 	}
 
 	return &rpc.CreateBucketResponse{}, nil
 }
 
 func (rs *RcpService) CreateBucketFromZip(ctx context.Context, req *rpc.CreateBucketFromZipRequest) (*rpc.CreateBucketResponse, error) {
-	iter, err := zipImporter.DownloadZip(req.ZipUrl, req.Path, req.Headers)
+	limits, err := rs.fsm.ImportLimits(ctx, req.NewBucketId)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to download zip: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to get bucket quota: %v", err)
 	}
 
+	iter, err := zipImporter.DownloadZip(req.ZipUrl, req.Path, req.Headers, limits)
+	if err != nil {
+		return nil, importError(err, "failed to download zip")
+	}
+	defer iter.Close()
+
 	if err := rs.fsm.ImportZip(ctx, req.NewBucketId, iter); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to import zip: %v", err)
+		return nil, importError(err, "failed to import zip")
 	}
 
 	return &rpc.CreateBucketResponse{}, nil
@@ -74,7 +127,7 @@ func (rs *RcpService) CreateBucketFromContents(ctx context.Context, req *rpc.Cre
 	}
 
 	if err := rs.fsm.ImportContents(ctx, req.NewBucketId, contents); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to import contents: %v", err)
+		return nil, importError(err, "failed to import contents")
 	}
 
 	return &rpc.CreateBucketResponse{}, nil
@@ -107,8 +160,11 @@ func (rs *RcpService) GetBucketToken(ctx context.Context, req *rpc.GetBucketToke
 }
 
 func (rs *RcpService) GetBucketFile(ctx context.Context, req *rpc.GetBucketFileRequest) (*rpc.GetBucketFileResponse, error) {
-	info, content, err := rs.fsm.GetBucketFile(ctx, req.BucketId, req.Path)
+	info, content, err := rs.fsm.GetBucketFileAt(ctx, req.BucketId, req.Snapshot, req.Path)
 	if err != nil {
+		if errors.Is(err, fs.ErrSnapshotNotFound) {
+			return nil, status.Errorf(codes.NotFound, "snapshot not found")
+		}
 		if err.Error() == "file not found" {
 			return nil, status.Errorf(codes.NotFound, "file not found")
 		}
@@ -129,8 +185,11 @@ func (rs *RcpService) GetBucketFile(ctx context.Context, req *rpc.GetBucketFileR
 }
 
 func (rs *RcpService) GetBucketFiles(ctx context.Context, req *rpc.GetBucketFilesRequest) (*rpc.GetBucketFilesResponse, error) {
-	files, err := rs.fsm.GetBucketFiles(ctx, req.BucketId, req.Prefix)
+	files, err := rs.fsm.GetBucketFilesAt(ctx, req.BucketId, req.Snapshot, req.Prefix)
 	if err != nil {
+		if errors.Is(err, fs.ErrSnapshotNotFound) {
+			return nil, status.Errorf(codes.NotFound, "snapshot not found")
+		}
 		return nil, status.Errorf(codes.Internal, "failed to get files: %v", err)
 	}
 
@@ -144,7 +203,15 @@ func (rs *RcpService) GetBucketFiles(ctx context.Context, req *rpc.GetBucketFile
 		})
 	}
 
-	return &rpc.GetBucketFilesResponse{Files: pbFiles}, nil
+	var revision string
+	if req.Snapshot == "" {
+		revision, err = rs.fsm.GetBucketRevision(ctx, req.BucketId)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get bucket revision: %v", err)
+		}
+	}
+
+	return &rpc.GetBucketFilesResponse{Files: pbFiles, Revision: revision}, nil
 }
 
 func (rs *RcpService) GetBucketFilesAsZip(ctx context.Context, req *rpc.GetBucketFilesAsZipRequest) (*rpc.GetBucketFilesAsZipResponse, error) {
@@ -160,14 +227,17 @@ func (rs *RcpService) GetBucketFilesAsZip(ctx context.Context, req *rpc.GetBucke
 }
 
 func (rs *RcpService) GetBucketFilesWithContent(ctx context.Context, req *rpc.GetBucketFilesRequest) (*rpc.GetBucketFilesWithContentResponse, error) {
-	files, err := rs.fsm.GetBucketFiles(ctx, req.BucketId, req.Prefix)
+	files, err := rs.fsm.GetBucketFilesAt(ctx, req.BucketId, req.Snapshot, req.Prefix)
 	if err != nil {
+		if errors.Is(err, fs.ErrSnapshotNotFound) {
+			return nil, status.Errorf(codes.NotFound, "snapshot not found")
+		}
 		return nil, status.Errorf(codes.Internal, "failed to get files: %v", err)
 	}
 
 	var pbFiles []*rpc.FileContent
 	for _, file := range files {
-		_, content, err := rs.fsm.GetBucketFile(ctx, req.BucketId, file.Path)
+		_, content, err := rs.fsm.GetBucketFileAt(ctx, req.BucketId, req.Snapshot, file.Path)
 		if err != nil {
 			continue
 		}
@@ -211,3 +281,194 @@ func (rs *RcpService) ExportBucketToGithub(ctx context.Context, req *rpc.ExportB
 
 	return &rpc.ExportBucketToGithubResponse{}, nil
 }
+
+func (rs *RcpService) ExportBucketToGit(ctx context.Context, req *rpc.ExportBucketToGitRequest) (*rpc.ExportBucketToGitResponse, error) {
+	files, err := rs.fsm.GetBucketFiles(ctx, req.BucketId, "")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get bucket files: %v", err)
+	}
+
+	filesToExport := make([]gitTransport.File, 0, len(files))
+	for _, file := range files {
+		_, content, err := rs.fsm.GetBucketFile(ctx, req.BucketId, file.Path)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to read %s: %v", file.Path, err)
+		}
+
+		filesToExport = append(filesToExport, gitTransport.File{
+			Path:    file.Path,
+			Content: content.Content,
+		})
+	}
+
+	remote := gitTransport.Remote{URL: req.RemoteUrl, Username: req.Username, Token: req.Token}
+	commit, err := gitTransport.Export(ctx, remote, filesToExport, gitTransport.ExportOptions{
+		Branch:      req.Branch,
+		Path:        req.Path,
+		AuthorName:  req.AuthorName,
+		AuthorEmail: req.AuthorEmail,
+		Message:     req.Message,
+	})
+	if errors.Is(err, gitTransport.ErrNoChanges) {
+		return &rpc.ExportBucketToGitResponse{CommitSha: commit, Changed: false}, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to export to git: %v", err)
+	}
+
+	return &rpc.ExportBucketToGitResponse{CommitSha: commit, Changed: true}, nil
+}
+
+func snapshotToPb(info fs.SnapshotInfo) *rpc.Snapshot {
+	return &rpc.Snapshot{
+		Id:        info.ID,
+		BucketId:  info.BucketID,
+		Name:      info.Name,
+		CreatedAt: info.CreatedAt.Unix(),
+		FileCount: int64(info.FileCount),
+		TotalSize: info.TotalSize,
+	}
+}
+
+func (rs *RcpService) CreateSnapshot(ctx context.Context, req *rpc.CreateSnapshotRequest) (*rpc.SnapshotResponse, error) {
+	snapshot, err := rs.fsm.CreateSnapshot(ctx, req.BucketId, req.Name)
+	if err != nil {
+		return nil, importError(err, "failed to create snapshot")
+	}
+
+	return &rpc.SnapshotResponse{Snapshot: snapshotToPb(snapshot.Info())}, nil
+}
+
+func (rs *RcpService) ListSnapshots(ctx context.Context, req *rpc.ListSnapshotsRequest) (*rpc.ListSnapshotsResponse, error) {
+	snapshots, err := rs.fsm.ListSnapshots(ctx, req.BucketId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list snapshots: %v", err)
+	}
+
+	var pbSnapshots []*rpc.Snapshot
+	for _, snapshot := range snapshots {
+		pbSnapshots = append(pbSnapshots, snapshotToPb(snapshot))
+	}
+
+	return &rpc.ListSnapshotsResponse{Snapshots: pbSnapshots}, nil
+}
+
+func (rs *RcpService) DiffSnapshots(ctx context.Context, req *rpc.DiffSnapshotsRequest) (*rpc.DiffSnapshotsResponse, error) {
+	changes, err := rs.fsm.DiffSnapshots(ctx, req.BucketId, req.FromSnapshot, req.ToSnapshot)
+	if err != nil {
+		if errors.Is(err, fs.ErrSnapshotNotFound) {
+			return nil, status.Errorf(codes.NotFound, "snapshot not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to diff snapshots: %v", err)
+	}
+
+	var pbChanges []*rpc.SnapshotDiffEntry
+	for _, change := range changes {
+		pbChanges = append(pbChanges, &rpc.SnapshotDiffEntry{
+			Path:     change.Path,
+			Change:   change.Change,
+			FromHash: change.FromHash,
+			ToHash:   change.ToHash,
+		})
+	}
+
+	return &rpc.DiffSnapshotsResponse{Changes: pbChanges}, nil
+}
+
+func (rs *RcpService) RestoreSnapshot(ctx context.Context, req *rpc.RestoreSnapshotRequest) (*rpc.RestoreSnapshotResponse, error) {
+	if err := rs.fsm.RestoreSnapshot(ctx, req.BucketId, req.Snapshot); err != nil {
+		if errors.Is(err, fs.ErrSnapshotNotFound) {
+			return nil, status.Errorf(codes.NotFound, "snapshot not found")
+		}
+		return nil, importError(err, "failed to restore snapshot")
+	}
+
+	return &rpc.RestoreSnapshotResponse{}, nil
+}
+
+func (rs *RcpService) WatchBucket(req *rpc.WatchBucketRequest, stream rpc.CodeBucket_WatchBucketServer) error {
+	events, err := rs.fsm.WatchBucket(stream.Context(), req.BucketId, req.AfterEventId)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to watch bucket: %v", err)
+	}
+
+	for event := range events {
+		err := stream.Send(&rpc.BucketChangeEvent{
+			Id:        event.ID,
+			Path:      event.Path,
+			Operation: event.Operation,
+			Hash:      event.Hash,
+			Timestamp: event.Timestamp.UnixMilli(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return stream.Context().Err()
+}
+
+func (rs *RcpService) bucketQuotaResponse(ctx context.Context, bucketID string) (*rpc.BucketQuotaResponse, error) {
+	quota, err := rs.fsm.GetBucketQuota(ctx, bucketID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get bucket quota: %v", err)
+	}
+
+	usage, err := rs.fsm.GetBucketUsage(ctx, bucketID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get bucket usage: %v", err)
+	}
+
+	return &rpc.BucketQuotaResponse{
+		Quota: &rpc.BucketQuota{
+			MaxTotalBytes: quota.MaxTotalBytes,
+			MaxFiles:      quota.MaxFiles,
+			MaxFileBytes:  quota.MaxFileBytes,
+		},
+		Usage: &rpc.BucketUsage{
+			TotalBytes: usage.TotalBytes,
+			Files:      usage.Files,
+		},
+	}, nil
+}
+
+func (rs *RcpService) GetBucketQuota(ctx context.Context, req *rpc.GetBucketQuotaRequest) (*rpc.BucketQuotaResponse, error) {
+	return rs.bucketQuotaResponse(ctx, req.BucketId)
+}
+
+func (rs *RcpService) SetBucketQuota(ctx context.Context, req *rpc.SetBucketQuotaRequest) (*rpc.BucketQuotaResponse, error) {
+	var quota *fs.Quota
+	if req.Quota != nil {
+		quota = &fs.Quota{
+			MaxTotalBytes: req.Quota.MaxTotalBytes,
+			MaxFiles:      req.Quota.MaxFiles,
+			MaxFileBytes:  req.Quota.MaxFileBytes,
+		}
+	}
+
+	if err := rs.fsm.SetBucketQuota(ctx, req.BucketId, quota); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to set bucket quota: %v", err)
+	}
+
+	return rs.bucketQuotaResponse(ctx, req.BucketId)
+}
+
+func (rs *RcpService) ApplyBatch(ctx context.Context, req *rpc.ApplyBatchRequest) (*rpc.ApplyBatchResponse, error) {
+	operations := make([]fs.BatchOperation, 0, len(req.Operations))
+	for _, op := range req.Operations {
+		operations = append(operations, fs.BatchOperation{
+			Operation:   op.Op,
+			Path:        op.Path,
+			From:        op.From,
+			Content:     op.Content,
+			ContentType: op.ContentType,
+		})
+	}
+
+	revision, err := rs.fsm.ApplyBatch(ctx, req.BucketId, req.IfMatchRevision, operations)
+	if err != nil {
+		return nil, importError(err, "failed to apply batch")
+	}
+
+	return &rpc.ApplyBatchResponse{Revision: revision}, nil
+}
@@ -0,0 +1,327 @@
+package fs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	BatchOperationPut    = "put"
+	BatchOperationDelete = "delete"
+	BatchOperationRename = "rename"
+
+	maxBatchOperations = 1000
+	maxBatchAttempts   = 5
+)
+
+var (
+	ErrRevisionMismatch = errors.New("bucket revision does not match")
+	ErrInvalidBatch     = errors.New("invalid batch")
+	ErrBatchConflict    = errors.New("bucket was modified concurrently")
+)
+
+// BatchOperation is a single change of a batch. Puts write Content to
+// Path, deletes remove Path and renames move From to Path.
+type BatchOperation struct {
+	Operation   string `json:"op"`
+	Path        string `json:"path"`
+	From        string `json:"from,omitempty"`
+	Content     []byte `json:"content,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// batchFile is the state of a file while a batch is applied. A nil data
+// means the file doesn't exist.
+type batchFile struct {
+	data    *FileData
+	changed bool
+}
+
+func revisionKey(bucketID string) string {
+	return fmt.Sprintf("revision:%s", bucketID)
+}
+
+// GetBucketRevision returns the revision of a bucket, which changes with
+// every write.
+func (fsm *FileSystemManager) GetBucketRevision(ctx context.Context, bucketID string) (string, error) {
+	revision, err := fsm.redis.Get(ctx, revisionKey(bucketID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return "0", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(revision, 10), nil
+}
+
+// ApplyBatch applies the operations in order, all or nothing. If
+// expectedRevision is set, the batch fails with ErrRevisionMismatch
+// unless the bucket is still at that revision. It returns the revision
+// of the bucket after the batch.
+func (fsm *FileSystemManager) ApplyBatch(ctx context.Context, bucketID, expectedRevision string, operations []BatchOperation) (string, error) {
+	if len(operations) == 0 {
+		return "", fmt.Errorf("%w: no operations", ErrInvalidBatch)
+	}
+	if len(operations) > maxBatchOperations {
+		return "", fmt.Errorf("%w: more than %d operations", ErrInvalidBatch, maxBatchOperations)
+	}
+
+	for attempt := 0; attempt < maxBatchAttempts; attempt++ {
+		revision, err := fsm.applyBatch(ctx, bucketID, expectedRevision, operations)
+		if errors.Is(err, redis.TxFailedErr) {
+			// Another write happened while the batch was prepared. With a
+			// precondition the batch is based on a stale state, otherwise
+			// it is prepared again on top of the new one.
+			if expectedRevision != "" {
+				return "", ErrRevisionMismatch
+			}
+			continue
+		}
+
+		return revision, err
+	}
+
+	return "", ErrBatchConflict
+}
+
+func (fsm *FileSystemManager) applyBatch(ctx context.Context, bucketID, expectedRevision string, operations []BatchOperation) (string, error) {
+	var newRevision *redis.IntCmd
+	var files map[string]*batchFile
+	var delta Usage
+
+	// Single file writes bump the revision together with the file, the
+	// files are watched as well so nothing slips in between
+	watchKeys := []string{revisionKey(bucketID)}
+	for _, operation := range operations {
+		watchKeys = append(watchKeys, fmt.Sprintf("bucket:%s:file:%s", bucketID, operation.Path))
+		if operation.From != "" {
+			watchKeys = append(watchKeys, fmt.Sprintf("bucket:%s:file:%s", bucketID, operation.From))
+		}
+	}
+
+	err := fsm.redis.Watch(ctx, func(tx *redis.Tx) error {
+		revision, err := tx.Get(ctx, revisionKey(bucketID)).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		if expectedRevision != "" && expectedRevision != strconv.FormatInt(revision, 10) {
+			return ErrRevisionMismatch
+		}
+
+		files, err = fsm.prepareBatch(ctx, bucketID, operations)
+		if err != nil {
+			return err
+		}
+
+		delta, err = fsm.checkBatchQuota(ctx, bucketID, files)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for filePath, file := range files {
+				if !file.changed {
+					continue
+				}
+
+				data, err := json.Marshal(file.stored(now))
+				if err != nil {
+					return err
+				}
+
+				pipe.Set(ctx, fmt.Sprintf("bucket:%s:file:%s", bucketID, filePath), data, redisFlushDelay*10)
+				pipe.Set(ctx, fmt.Sprintf("flush:%s:%s", bucketID, filePath), now.Unix(), redisFlushDelay*10)
+			}
+
+			pipe.HIncrBy(ctx, usageKey(bucketID), "bytes", delta.TotalBytes)
+			pipe.HIncrBy(ctx, usageKey(bucketID), "files", delta.Files)
+			newRevision = pipe.Incr(ctx, revisionKey(bucketID))
+
+			return nil
+		})
+
+		return err
+	}, watchKeys...)
+	if err != nil {
+		return "", err
+	}
+
+	for filePath, file := range files {
+		if !file.changed {
+			continue
+		}
+
+		if file.data == nil {
+			fsm.publishChange(ctx, bucketID, filePath, ChangeOperationDelete, "")
+		} else {
+			fsm.publishChange(ctx, bucketID, filePath, ChangeOperationPut, contentHash(file.data.Content))
+		}
+	}
+
+	return strconv.FormatInt(newRevision.Val(), 10), nil
+}
+
+// stored returns the data to store for a file changed by a batch. Files
+// that don't exist anymore are stored as tombstones until their deletion
+// is flushed, so they aren't read from S3 again.
+func (file *batchFile) stored(now time.Time) *FileData {
+	if file.data == nil {
+		return &FileData{Deleted: true, ModifiedAt: now}
+	}
+
+	file.data.ModifiedAt = now
+	return file.data
+}
+
+// prepareBatch applies the operations in memory to the files they touch
+// and returns the state of those files after the batch.
+func (fsm *FileSystemManager) prepareBatch(ctx context.Context, bucketID string, operations []BatchOperation) (map[string]*batchFile, error) {
+	return applyOperations(operations, func(filePath string) (*FileData, error) {
+		_, data, err := fsm.GetBucketFile(ctx, bucketID, filePath)
+		if err != nil && err.Error() == "file not found" {
+			return nil, nil
+		}
+
+		return data, err
+	})
+}
+
+// applyOperations applies the operations to the files they touch, which
+// are read with lookup the first time. lookup returns nil for files that
+// don't exist.
+func applyOperations(operations []BatchOperation, lookup func(filePath string) (*FileData, error)) (map[string]*batchFile, error) {
+	files := make(map[string]*batchFile)
+
+	load := func(filePath string) (*batchFile, error) {
+		if err := validateFilePath(filePath); err != nil {
+			return nil, err
+		}
+
+		if file, ok := files[filePath]; ok {
+			return file, nil
+		}
+
+		data, err := lookup(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		file := &batchFile{data: data}
+		files[filePath] = file
+		return file, nil
+	}
+
+	for i, operation := range operations {
+		target, err := load(operation.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		switch operation.Operation {
+		case BatchOperationPut:
+			contentType := operation.ContentType
+			if contentType == "" {
+				contentType = "application/octet-stream"
+			}
+
+			target.data = &FileData{
+				Content:     operation.Content,
+				ContentType: contentType,
+			}
+
+		case BatchOperationDelete:
+			if target.data == nil {
+				return nil, fmt.Errorf("%w: operation %d deletes %s, which doesn't exist", ErrInvalidBatch, i, operation.Path)
+			}
+
+			target.data = nil
+
+		case BatchOperationRename:
+			source, err := load(operation.From)
+			if err != nil {
+				return nil, err
+			}
+			if source.data == nil {
+				return nil, fmt.Errorf("%w: operation %d renames %s, which doesn't exist", ErrInvalidBatch, i, operation.From)
+			}
+			if source == target {
+				continue
+			}
+
+			target.data = source.data
+			source.data = nil
+			source.changed = true
+
+		default:
+			return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidBatch, operation.Operation)
+		}
+
+		target.changed = true
+	}
+
+	return files, nil
+}
+
+// checkBatchQuota checks the state after a batch against the bucket's
+// quota and returns the change in usage.
+func (fsm *FileSystemManager) checkBatchQuota(ctx context.Context, bucketID string, files map[string]*batchFile) (Usage, error) {
+	quota, err := fsm.GetBucketQuota(ctx, bucketID)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	usage, err := fsm.GetBucketUsage(ctx, bucketID)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	delta, err := batchDelta(quota, files, func(filePath string) (int64, bool) {
+		return fsm.fileSize(ctx, bucketID, filePath)
+	})
+	if err != nil {
+		return Usage{}, err
+	}
+
+	if err := checkUsage(quota, usage, delta); err != nil {
+		return Usage{}, err
+	}
+
+	return delta, nil
+}
+
+// batchDelta returns the change in usage of the changed files, given the
+// size of each before the batch if it existed.
+func batchDelta(quota Quota, files map[string]*batchFile, fileSize func(filePath string) (int64, bool)) (Usage, error) {
+	var delta Usage
+	for filePath, file := range files {
+		if !file.changed {
+			continue
+		}
+
+		if previousSize, exists := fileSize(filePath); exists {
+			delta.TotalBytes -= previousSize
+			delta.Files--
+		}
+
+		if file.data != nil {
+			size := int64(len(file.data.Content))
+			if err := checkFileSize(quota, filePath, size); err != nil {
+				return Usage{}, err
+			}
+
+			delta.TotalBytes += size
+			delta.Files++
+		}
+	}
+
+	return delta, nil
+}
@@ -0,0 +1,222 @@
+package fs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// testLookup serves files of a bucket from a map.
+func testLookup(existing map[string]string) func(string) (*FileData, error) {
+	return func(filePath string) (*FileData, error) {
+		content, ok := existing[filePath]
+		if !ok {
+			return nil, nil
+		}
+
+		return &FileData{Content: []byte(content), ContentType: "text/plain"}, nil
+	}
+}
+
+// fileStates returns the content of each file after a batch, or "-" for
+// files that were removed.
+func fileStates(files map[string]*batchFile) map[string]string {
+	states := make(map[string]string)
+	for filePath, file := range files {
+		if !file.changed {
+			continue
+		}
+
+		if file.data == nil {
+			states[filePath] = "-"
+		} else {
+			states[filePath] = string(file.data.Content)
+		}
+	}
+	return states
+}
+
+func TestApplyOperations(t *testing.T) {
+	existing := map[string]string{"a.ts": "a", "b.ts": "b"}
+
+	tests := []struct {
+		name       string
+		operations []BatchOperation
+		want       map[string]string
+	}{
+		{
+			"put",
+			[]BatchOperation{{Operation: BatchOperationPut, Path: "c.ts", Content: []byte("c")}},
+			map[string]string{"c.ts": "c"},
+		},
+		{
+			"delete",
+			[]BatchOperation{{Operation: BatchOperationDelete, Path: "a.ts"}},
+			map[string]string{"a.ts": "-"},
+		},
+		{
+			"rename",
+			[]BatchOperation{{Operation: BatchOperationRename, From: "a.ts", Path: "c.ts"}},
+			map[string]string{"a.ts": "-", "c.ts": "a"},
+		},
+		{
+			"rename over existing file",
+			[]BatchOperation{{Operation: BatchOperationRename, From: "a.ts", Path: "b.ts"}},
+			map[string]string{"a.ts": "-", "b.ts": "a"},
+		},
+		{
+			"rename onto itself",
+			[]BatchOperation{{Operation: BatchOperationRename, From: "a.ts", Path: "a.ts"}},
+			map[string]string{},
+		},
+		{
+			"operations see earlier ones",
+			[]BatchOperation{
+				{Operation: BatchOperationPut, Path: "c.ts", Content: []byte("c")},
+				{Operation: BatchOperationRename, From: "c.ts", Path: "d.ts"},
+				{Operation: BatchOperationDelete, Path: "d.ts"},
+				{Operation: BatchOperationPut, Path: "a.ts", Content: []byte("new")},
+			},
+			map[string]string{"a.ts": "new", "c.ts": "-", "d.ts": "-"},
+		},
+		{
+			"swap",
+			[]BatchOperation{
+				{Operation: BatchOperationRename, From: "a.ts", Path: "tmp.ts"},
+				{Operation: BatchOperationRename, From: "b.ts", Path: "a.ts"},
+				{Operation: BatchOperationRename, From: "tmp.ts", Path: "b.ts"},
+			},
+			map[string]string{"a.ts": "b", "b.ts": "a", "tmp.ts": "-"},
+		},
+	}
+
+	for _, tt := range tests {
+		files, err := applyOperations(tt.operations, testLookup(existing))
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+
+		got := fileStates(files)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: changed files %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for filePath, content := range tt.want {
+			if got[filePath] != content {
+				t.Errorf("%s: changed files %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestApplyOperations_Invalid(t *testing.T) {
+	existing := map[string]string{"a.ts": "a"}
+
+	tests := []struct {
+		name       string
+		operations []BatchOperation
+		want       error
+	}{
+		{"delete missing file", []BatchOperation{{Operation: BatchOperationDelete, Path: "b.ts"}}, ErrInvalidBatch},
+		{"rename missing file", []BatchOperation{{Operation: BatchOperationRename, From: "b.ts", Path: "c.ts"}}, ErrInvalidBatch},
+		{"delete twice", []BatchOperation{
+			{Operation: BatchOperationDelete, Path: "a.ts"},
+			{Operation: BatchOperationDelete, Path: "a.ts"},
+		}, ErrInvalidBatch},
+		{"unknown operation", []BatchOperation{{Operation: "copy", Path: "a.ts"}}, ErrInvalidBatch},
+		{"invalid path", []BatchOperation{{Operation: BatchOperationPut, Path: "../a.ts"}}, ErrInvalidPath},
+		{"invalid source", []BatchOperation{{Operation: BatchOperationRename, From: "/a.ts", Path: "b.ts"}}, ErrInvalidPath},
+	}
+
+	for _, tt := range tests {
+		if _, err := applyOperations(tt.operations, testLookup(existing)); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	lookupErr := errors.New("redis unavailable")
+	_, err := applyOperations([]BatchOperation{{Operation: BatchOperationPut, Path: "a.ts"}}, func(string) (*FileData, error) {
+		return nil, lookupErr
+	})
+	if !errors.Is(err, lookupErr) {
+		t.Errorf("expected lookup errors to be returned, got %v", err)
+	}
+}
+
+func TestApplyOperations_LooksUpOnce(t *testing.T) {
+	lookups := make(map[string]int)
+	lookup := testLookup(map[string]string{"a.ts": "a"})
+
+	_, err := applyOperations([]BatchOperation{
+		{Operation: BatchOperationPut, Path: "a.ts", Content: []byte("1")},
+		{Operation: BatchOperationPut, Path: "a.ts", Content: []byte("2")},
+		{Operation: BatchOperationRename, From: "a.ts", Path: "b.ts"},
+	}, func(filePath string) (*FileData, error) {
+		lookups[filePath]++
+		return lookup(filePath)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lookups["a.ts"] != 1 || lookups["b.ts"] != 1 {
+		t.Errorf("expected each file to be looked up once, got %v", lookups)
+	}
+}
+
+func TestBatchFile_Stored(t *testing.T) {
+	now := time.Now()
+
+	removed := (&batchFile{changed: true}).stored(now)
+	if !removed.Deleted || !removed.ModifiedAt.Equal(now) {
+		t.Errorf("expected removed files to be stored as tombstones, got %+v", removed)
+	}
+
+	written := (&batchFile{data: &FileData{Content: []byte("a")}, changed: true}).stored(now)
+	if written.Deleted || string(written.Content) != "a" || !written.ModifiedAt.Equal(now) {
+		t.Errorf("expected written files to be stored, got %+v", written)
+	}
+}
+
+func TestBatchDelta(t *testing.T) {
+	sizes := map[string]int64{"a.ts": 10, "b.ts": 20}
+	fileSize := func(filePath string) (int64, bool) {
+		size, ok := sizes[filePath]
+		return size, ok
+	}
+
+	file := func(content string) *batchFile {
+		return &batchFile{data: &FileData{Content: []byte(content)}, changed: true}
+	}
+
+	tests := []struct {
+		name  string
+		files map[string]*batchFile
+		want  Usage
+	}{
+		{"new file", map[string]*batchFile{"c.ts": file("12345")}, Usage{TotalBytes: 5, Files: 1}},
+		{"overwrite", map[string]*batchFile{"a.ts": file("12345")}, Usage{TotalBytes: -5}},
+		{"delete", map[string]*batchFile{"a.ts": {changed: true}}, Usage{TotalBytes: -10, Files: -1}},
+		{"rename", map[string]*batchFile{"a.ts": {changed: true}, "c.ts": file("0123456789")}, Usage{}},
+		{"unchanged", map[string]*batchFile{"a.ts": {data: &FileData{Content: []byte("1")}}}, Usage{}},
+		{"delete missing tombstone", map[string]*batchFile{"c.ts": {changed: true}}, Usage{}},
+	}
+
+	for _, tt := range tests {
+		got, err := batchDelta(Quota{}, tt.files, fileSize)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: delta %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	_, err := batchDelta(Quota{MaxFileBytes: 4}, map[string]*batchFile{"c.ts": file("12345")}, fileSize)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded for a large file, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package fs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	ChangeOperationPut    = "put"
+	ChangeOperationDelete = "delete"
+
+	// changeStreamLength is roughly how many events are kept per bucket
+	// for watchers resuming after a disconnect.
+	changeStreamLength = 1000
+	changeReadBlock    = 30 * time.Second
+)
+
+// ChangeEvent describes a change of a bucket file. The ID orders events
+// of a bucket and can be used to resume watching after it.
+type ChangeEvent struct {
+	ID        string    `json:"id"`
+	BucketID  string    `json:"bucket_id"`
+	Path      string    `json:"path"`
+	Operation string    `json:"operation"`
+	Hash      string    `json:"hash,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func changeStreamKey(bucketID string) string {
+	return fmt.Sprintf("changes:%s", bucketID)
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// publishChange appends an event to the change stream of a bucket.
+// Failing to publish doesn't fail the change itself.
+func (fsm *FileSystemManager) publishChange(ctx context.Context, bucketID, filePath, operation, hash string) {
+	err := fsm.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: changeStreamKey(bucketID),
+		MaxLen: changeStreamLength,
+		Approx: true,
+		Values: map[string]interface{}{
+			"path":      filePath,
+			"operation": operation,
+			"hash":      hash,
+			"timestamp": time.Now().UnixMilli(),
+		},
+	}).Err()
+	if err != nil {
+		log.Printf("Error publishing change of %s/%s: %v", bucketID, filePath, err)
+	}
+}
+
+// WatchBucket streams the changes of a bucket until ctx is done. Events
+// after afterID are replayed first if they are still retained; with an
+// empty afterID only new changes are sent.
+func (fsm *FileSystemManager) WatchBucket(ctx context.Context, bucketID, afterID string) (<-chan ChangeEvent, error) {
+	lastID := afterID
+	if lastID == "" {
+		// Resolve "new changes" to a concrete position now, so changes
+		// made before the first read are not missed.
+		lastID = "0-0"
+
+		messages, err := fsm.redis.XRevRangeN(ctx, changeStreamKey(bucketID), "+", "-", 1).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		if len(messages) > 0 {
+			lastID = messages[0].ID
+		}
+	}
+
+	events := make(chan ChangeEvent)
+
+	go func() {
+		defer close(events)
+
+		for ctx.Err() == nil {
+			streams, err := fsm.redis.XRead(ctx, &redis.XReadArgs{
+				Streams: []string{changeStreamKey(bucketID), lastID},
+				Count:   100,
+				Block:   changeReadBlock,
+			}).Result()
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("Error reading changes of bucket %s: %v", bucketID, err)
+				}
+				return
+			}
+
+			for _, stream := range streams {
+				for _, message := range stream.Messages {
+					lastID = message.ID
+
+					select {
+					case events <- changeEventFromMessage(bucketID, message):
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func changeEventFromMessage(bucketID string, message redis.XMessage) ChangeEvent {
+	value := func(key string) string {
+		s, _ := message.Values[key].(string)
+		return s
+	}
+
+	timestamp, _ := strconv.ParseInt(value("timestamp"), 10, 64)
+
+	return ChangeEvent{
+		ID:        message.ID,
+		BucketID:  bucketID,
+		Path:      value("path"),
+		Operation: value("operation"),
+		Hash:      value("hash"),
+		Timestamp: time.UnixMilli(timestamp),
+	}
+}
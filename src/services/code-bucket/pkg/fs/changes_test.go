@@ -0,0 +1,194 @@
+package fs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// newTestManager returns a manager backed by an in-memory Redis. It has
+// no S3 client, so only Redis backed methods can be used.
+func newTestManager(t *testing.T) *FileSystemManager {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	return &FileSystemManager{redis: rdb}
+}
+
+// receiveEvents reads n events, failing the test if they don't arrive.
+func receiveEvents(t *testing.T, events <-chan ChangeEvent, n int) []ChangeEvent {
+	t.Helper()
+
+	var received []ChangeEvent
+	for len(received) < n {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatalf("events closed after %d of %d events", len(received), n)
+			}
+			received = append(received, event)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out after %d of %d events", len(received), n)
+		}
+	}
+	return received
+}
+
+// expectNoEvent fails the test if an event arrives shortly.
+func expectNoEvent(t *testing.T, events <-chan ChangeEvent) {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected event %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWatchBucket_NewChanges(t *testing.T) {
+	fsm := newTestManager(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fsm.publishChange(ctx, "bucket", "old.ts", ChangeOperationPut, "h0")
+
+	events, err := fsm.WatchBucket(ctx, "bucket", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changes published right after watching, before the first read,
+	// are not missed
+	fsm.publishChange(ctx, "bucket", "a.ts", ChangeOperationPut, "h1")
+	fsm.publishChange(ctx, "other", "b.ts", ChangeOperationPut, "h2")
+	fsm.publishChange(ctx, "bucket", "a.ts", ChangeOperationDelete, "")
+
+	received := receiveEvents(t, events, 2)
+
+	want := []struct{ path, operation, hash string }{
+		{"a.ts", ChangeOperationPut, "h1"},
+		{"a.ts", ChangeOperationDelete, ""},
+	}
+	for i, w := range want {
+		event := received[i]
+		if event.BucketID != "bucket" || event.Path != w.path || event.Operation != w.operation || event.Hash != w.hash {
+			t.Errorf("event %d = %+v, want %s %s %q", i, event, w.operation, w.path, w.hash)
+		}
+		if event.ID == "" || time.Since(event.Timestamp) > time.Minute {
+			t.Errorf("event %d has ID %q and timestamp %v", i, event.ID, event.Timestamp)
+		}
+	}
+	if received[0].ID == received[1].ID {
+		t.Errorf("expected distinct IDs, got %s twice", received[0].ID)
+	}
+
+	expectNoEvent(t, events)
+}
+
+func TestWatchBucket_Resume(t *testing.T) {
+	fsm := newTestManager(t)
+	ctx := context.Background()
+
+	for _, path := range []string{"a.ts", "b.ts", "c.ts"} {
+		fsm.publishChange(ctx, "bucket", path, ChangeOperationPut, "")
+	}
+
+	// Watching from the start replays everything retained
+	watchCtx, cancel := context.WithCancel(ctx)
+	events, err := fsm.WatchBucket(watchCtx, "bucket", "0-0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := receiveEvents(t, events, 3)
+	cancel()
+
+	for i, path := range []string{"a.ts", "b.ts", "c.ts"} {
+		if all[i].Path != path {
+			t.Errorf("event %d is for %s, want %s", i, all[i].Path, path)
+		}
+	}
+
+	// Resuming after an event replays only the later ones, then
+	// continues with new changes
+	watchCtx, cancel = context.WithCancel(ctx)
+	defer cancel()
+
+	events, err = fsm.WatchBucket(watchCtx, "bucket", all[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayed := receiveEvents(t, events, 2)
+	if replayed[0].ID != all[1].ID || replayed[1].ID != all[2].ID {
+		t.Errorf("replayed %s, %s, want %s, %s", replayed[0].ID, replayed[1].ID, all[1].ID, all[2].ID)
+	}
+
+	fsm.publishChange(ctx, "bucket", "d.ts", ChangeOperationPut, "")
+
+	if event := receiveEvents(t, events, 1)[0]; event.Path != "d.ts" {
+		t.Errorf("expected the new change of d.ts, got %+v", event)
+	}
+	expectNoEvent(t, events)
+}
+
+func TestWatchBucket_Cursor(t *testing.T) {
+	fsm := newTestManager(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := fsm.WatchBucket(ctx, "bucket", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Events arriving across several reads are each delivered once
+	var received []ChangeEvent
+	for round := 0; round < 3; round++ {
+		fsm.publishChange(ctx, "bucket", "a.ts", ChangeOperationPut, "")
+		fsm.publishChange(ctx, "bucket", "b.ts", ChangeOperationPut, "")
+
+		received = append(received, receiveEvents(t, events, 2)...)
+		expectNoEvent(t, events)
+	}
+
+	seen := make(map[string]bool)
+	for i, event := range received {
+		if seen[event.ID] {
+			t.Errorf("event %s delivered twice", event.ID)
+		}
+		seen[event.ID] = true
+
+		if want := []string{"a.ts", "b.ts"}[i%2]; event.Path != want {
+			t.Errorf("event %d is for %s, want %s", i, event.Path, want)
+		}
+	}
+}
+
+func TestWatchBucket_Cancel(t *testing.T) {
+	fsm := newTestManager(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, err := fsm.WatchBucket(ctx, "bucket", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Let the watcher block in its read
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case event, ok := <-events:
+		if ok {
+			t.Fatalf("unexpected event %+v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("events were not closed after cancelling")
+	}
+}
@@ -40,6 +40,9 @@ type FileData struct {
 	Content     []byte    `json:"content"`
 	ContentType string    `json:"content_type"`
 	ModifiedAt  time.Time `json:"modified_at"`
+	// Deleted marks a file deleted by a batch whose deletion is not
+	// flushed to S3 yet.
+	Deleted bool `json:"deleted,omitempty"`
 }
 
 type FileSystemManager struct {
@@ -47,6 +50,9 @@ type FileSystemManager struct {
 	s3Client    *s3.S3
 	bucketName  string
 	flushTicker *time.Ticker
+
+	defaultQuota    Quota
+	maxArchiveBytes int64
 }
 
 type FileContentsBase struct {
@@ -112,6 +118,9 @@ func NewFileSystemManager(opts ...FileSystemManagerOption) *FileSystemManager {
 		s3Client:    s3Client,
 		bucketName:  options.S3Bucket,
 		flushTicker: time.NewTicker(60 * time.Second),
+
+		defaultQuota:    options.Quota,
+		maxArchiveBytes: options.MaxArchiveBytes,
 	}
 
 	// Start background flush routines
@@ -129,6 +138,10 @@ func (fsm *FileSystemManager) GetBucketFile(ctx context.Context, bucketID, fileP
 	if err == nil {
 		var fileData FileData
 		if err := json.Unmarshal([]byte(result), &fileData); err == nil {
+			if fileData.Deleted {
+				return nil, nil, fmt.Errorf("file not found")
+			}
+
 			// return fileData.Content, fileData.ContentType, nil
 
 			info := &FileInfo{
@@ -189,6 +202,15 @@ func (fsm *FileSystemManager) GetBucketFile(ctx context.Context, bucketID, fileP
 }
 
 func (fsm *FileSystemManager) PutBucketFile(ctx context.Context, bucketID, filePath string, content []byte, contentType string) error {
+	if err := validateFilePath(filePath); err != nil {
+		return err
+	}
+
+	delta, err := fsm.reserveQuota(ctx, bucketID, filePath, int64(len(content)))
+	if err != nil {
+		return err
+	}
+
 	// Store in Redis first
 	redisKey := fmt.Sprintf("bucket:%s:file:%s", bucketID, filePath)
 	fileData := FileData{
@@ -202,40 +224,61 @@ func (fsm *FileSystemManager) PutBucketFile(ctx context.Context, bucketID, fileP
 		return err
 	}
 
-	err = fsm.redis.Set(ctx, redisKey, data, redisFlushDelay*10).Err()
+	// The file is marked for flush and the revision bumped together with
+	// the write, so batches prepared meanwhile see the conflict
+	flushKey := fmt.Sprintf("flush:%s:%s", bucketID, filePath)
+	_, err = fsm.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, redisKey, data, redisFlushDelay*10)
+		pipe.Set(ctx, flushKey, time.Now().Unix(), redisFlushDelay*10)
+		pipe.Incr(ctx, revisionKey(bucketID))
+		return nil
+	})
 	if err != nil {
 		return err
 	}
 
-	// Mark for flush
-	flushKey := fmt.Sprintf("flush:%s:%s", bucketID, filePath)
-	fsm.redis.Set(ctx, flushKey, time.Now().Unix(), redisFlushDelay*10)
+	fsm.recordUsage(ctx, bucketID, delta)
+	fsm.publishChange(ctx, bucketID, filePath, ChangeOperationPut, contentHash(content))
 
 	return nil
 }
 
 func (fsm *FileSystemManager) DeleteBucketFile(ctx context.Context, bucketID, filePath string) error {
-	// Check if file exists first
-	redisKey := fmt.Sprintf("bucket:%s:file:%s", bucketID, filePath)
-	exists := fsm.redis.Exists(ctx, redisKey).Val()
+	size, existed := fsm.fileSize(ctx, bucketID, filePath)
 
-	// Delete from Redis
-	if exists != 0 {
-		fsm.redis.Del(ctx, redisKey)
+	// Delete from Redis, bumping the revision together with it
+	redisKey := fmt.Sprintf("bucket:%s:file:%s", bucketID, filePath)
+	_, err := fsm.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, redisKey)
+		pipe.Incr(ctx, revisionKey(bucketID))
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// Delete from S3
 	s3Key := fmt.Sprintf("%s/%s", bucketID, filePath)
-	_, err := fsm.s3Client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+	_, err = fsm.s3Client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(fsm.bucketName),
 		Key:    aws.String(s3Key),
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	if existed {
+		fsm.recordUsage(ctx, bucketID, Usage{TotalBytes: -size, Files: -1})
+	}
+
+	fsm.publishChange(ctx, bucketID, filePath, ChangeOperationDelete, "")
+
+	return nil
 }
 
 func (fsm *FileSystemManager) GetBucketFiles(ctx context.Context, bucketID, prefix string) ([]FileInfo, error) {
 	files := make([]FileInfo, 0)
+	deleted := make(map[string]bool)
 
 	// Get files from Redis
 	pattern := fmt.Sprintf("bucket:%s:file:*", bucketID)
@@ -257,6 +300,11 @@ func (fsm *FileSystemManager) GetBucketFiles(ctx context.Context, bucketID, pref
 				continue
 			}
 
+			if fileData.Deleted {
+				deleted[filePath] = true
+				continue
+			}
+
 			files = append(files, FileInfo{
 				Path:        filePath,
 				Size:        int64(len(fileData.Content)),
@@ -279,6 +327,9 @@ func (fsm *FileSystemManager) GetBucketFiles(ctx context.Context, bucketID, pref
 	if err == nil {
 		for _, obj := range result.Contents {
 			filePath := strings.TrimPrefix(*obj.Key, bucketID+"/")
+			if deleted[filePath] {
+				continue
+			}
 
 			// Skip if already in Redis results
 			found := false
@@ -388,13 +439,14 @@ func (fsm *FileSystemManager) Clone(ctx context.Context, sourceBucketId, newBuck
 	for _, file := range files {
 		queue.AddAndBlockIfFull(func() error {
 			info, content, err := fsm.GetBucketFile(ctx, sourceBucketId, file.Path)
-			if err != nil && !strings.Contains(err.Error(), "not found") {
+			if err != nil {
+				if strings.Contains(err.Error(), "not found") {
+					return nil
+				}
 				return err
 			}
 
-			fsm.PutBucketFile(ctx, newBucketId, file.Path, content.Content, info.ContentType)
-
-			return nil
+			return fsm.PutBucketFile(ctx, newBucketId, file.Path, content.Content, info.ContentType)
 		})
 	}
 
@@ -411,9 +463,7 @@ func (fsm *FileSystemManager) ImportZip(ctx context.Context, newBucketId string,
 		}
 
 		queue.AddAndBlockIfFull(func() error {
-			fsm.PutBucketFile(ctx, newBucketId, file.Path, file.Content, "application/octet-stream")
-
-			return nil
+			return fsm.PutBucketFile(ctx, newBucketId, file.Path, file.Content, "application/octet-stream")
 		})
 	}
 
@@ -426,9 +476,7 @@ func (fsm *FileSystemManager) ImportContents(ctx context.Context, newBucketId st
 	for _, file := range contents {
 		f := file
 		queue.AddAndBlockIfFull(func() error {
-			fsm.PutBucketFile(ctx, newBucketId, f.Path, f.Content, "application/octet-stream")
-
-			return nil
+			return fsm.PutBucketFile(ctx, newBucketId, f.Path, f.Content, "application/octet-stream")
 		})
 	}
 
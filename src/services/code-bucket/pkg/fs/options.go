@@ -8,6 +8,9 @@ type FileSystemManagerOptions struct {
 	AwsAccessKey string
 	AwsSecretKey string
 	AwsEndpoint  string
+
+	Quota           Quota
+	MaxArchiveBytes int64
 }
 
 type FileSystemManagerOption func(*FileSystemManagerOptions)
@@ -51,3 +54,18 @@ func WithAwsEndpoint(endpoint string) FileSystemManagerOption {
 		opts.AwsEndpoint = endpoint
 	}
 }
+
+// WithQuota sets the default quota of buckets.
+func WithQuota(quota Quota) FileSystemManagerOption {
+	return func(opts *FileSystemManagerOptions) {
+		opts.Quota = quota
+	}
+}
+
+// WithMaxArchiveBytes limits the size of zip archives downloaded for
+// imports.
+func WithMaxArchiveBytes(maxArchiveBytes int64) FileSystemManagerOption {
+	return func(opts *FileSystemManagerOptions) {
+		opts.MaxArchiveBytes = maxArchiveBytes
+	}
+}
@@ -0,0 +1,218 @@
+package fs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/go-redis/redis/v8"
+	zipImporter "github.com/metorial/metorial/services/code-bucket/pkg/zip-importer"
+)
+
+var (
+	ErrQuotaExceeded = errors.New("bucket quota exceeded")
+	ErrInvalidPath   = errors.New("invalid file path")
+)
+
+// Quota limits the contents of a bucket. Zero values mean no limit.
+type Quota struct {
+	MaxTotalBytes int64 `json:"max_total_bytes"`
+	MaxFiles      int64 `json:"max_files"`
+	MaxFileBytes  int64 `json:"max_file_bytes"`
+}
+
+type Usage struct {
+	TotalBytes int64 `json:"total_bytes"`
+	Files      int64 `json:"files"`
+}
+
+func quotaKey(bucketID string) string {
+	return fmt.Sprintf("quota:%s", bucketID)
+}
+
+func usageKey(bucketID string) string {
+	return fmt.Sprintf("usage:%s", bucketID)
+}
+
+// validateFilePath rejects paths that are absolute or leave the bucket
+// when the bucket is written to a directory, e.g. by a zip export.
+func validateFilePath(filePath string) error {
+	if filePath == "" || strings.HasPrefix(filePath, "/") || strings.Contains(filePath, "\\") || strings.ContainsRune(filePath, 0) {
+		return fmt.Errorf("%w: %q", ErrInvalidPath, filePath)
+	}
+
+	if cleaned := path.Clean(filePath); cleaned != filePath || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return fmt.Errorf("%w: %q", ErrInvalidPath, filePath)
+	}
+
+	return nil
+}
+
+// GetBucketQuota returns the quota of a bucket, which is the default
+// quota unless it was overridden for the bucket.
+func (fsm *FileSystemManager) GetBucketQuota(ctx context.Context, bucketID string) (Quota, error) {
+	result, err := fsm.redis.Get(ctx, quotaKey(bucketID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return fsm.defaultQuota, nil
+	}
+	if err != nil {
+		return Quota{}, err
+	}
+
+	var quota Quota
+	if err := json.Unmarshal([]byte(result), &quota); err != nil {
+		return Quota{}, err
+	}
+
+	return quota, nil
+}
+
+// SetBucketQuota overrides the quota of a bucket. A nil quota restores
+// the default. Existing contents are kept even if they exceed it.
+func (fsm *FileSystemManager) SetBucketQuota(ctx context.Context, bucketID string, quota *Quota) error {
+	if quota == nil {
+		return fsm.redis.Del(ctx, quotaKey(bucketID)).Err()
+	}
+
+	data, err := json.Marshal(quota)
+	if err != nil {
+		return err
+	}
+
+	return fsm.redis.Set(ctx, quotaKey(bucketID), data, 0).Err()
+}
+
+// GetBucketUsage returns how much of its quota a bucket uses. Usage is
+// counted on writes; buckets written before quotas existed are measured
+// once on first use.
+func (fsm *FileSystemManager) GetBucketUsage(ctx context.Context, bucketID string) (Usage, error) {
+	values, err := fsm.redis.HGetAll(ctx, usageKey(bucketID)).Result()
+	if err != nil {
+		return Usage{}, err
+	}
+
+	if len(values) > 0 {
+		totalBytes, _ := strconv.ParseInt(values["bytes"], 10, 64)
+		files, _ := strconv.ParseInt(values["files"], 10, 64)
+		return Usage{TotalBytes: totalBytes, Files: files}, nil
+	}
+
+	files, err := fsm.GetBucketFiles(ctx, bucketID, "")
+	if err != nil {
+		return Usage{}, err
+	}
+
+	usage := Usage{Files: int64(len(files))}
+	for _, file := range files {
+		usage.TotalBytes += file.Size
+	}
+
+	// Another instance may have initialized the counters meanwhile, in
+	// which case theirs are kept.
+	fsm.redis.HSetNX(ctx, usageKey(bucketID), "bytes", usage.TotalBytes)
+	fsm.redis.HSetNX(ctx, usageKey(bucketID), "files", usage.Files)
+
+	return usage, nil
+}
+
+// fileSize returns the size of a file if it exists.
+func (fsm *FileSystemManager) fileSize(ctx context.Context, bucketID, filePath string) (int64, bool) {
+	redisKey := fmt.Sprintf("bucket:%s:file:%s", bucketID, filePath)
+	if result, err := fsm.redis.Get(ctx, redisKey).Result(); err == nil {
+		var fileData FileData
+		if err := json.Unmarshal([]byte(result), &fileData); err == nil {
+			return int64(len(fileData.Content)), !fileData.Deleted
+		}
+	}
+
+	obj, err := fsm.s3Client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(fsm.bucketName),
+		Key:    aws.String(fmt.Sprintf("%s/%s", bucketID, filePath)),
+	})
+	if err != nil || obj.ContentLength == nil {
+		return 0, false
+	}
+
+	return *obj.ContentLength, true
+}
+
+// reserveQuota checks that writing size bytes to a file keeps the bucket
+// within its quota and returns the change in usage to record once the
+// write succeeded. Concurrent writes may overshoot the quota slightly.
+func (fsm *FileSystemManager) reserveQuota(ctx context.Context, bucketID, filePath string, size int64) (Usage, error) {
+	quota, err := fsm.GetBucketQuota(ctx, bucketID)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	if err := checkFileSize(quota, filePath, size); err != nil {
+		return Usage{}, err
+	}
+
+	usage, err := fsm.GetBucketUsage(ctx, bucketID)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	delta := Usage{TotalBytes: size, Files: 1}
+	if previousSize, exists := fsm.fileSize(ctx, bucketID, filePath); exists {
+		delta = Usage{TotalBytes: size - previousSize}
+	}
+
+	if err := checkUsage(quota, usage, delta); err != nil {
+		return Usage{}, err
+	}
+
+	return delta, nil
+}
+
+func checkFileSize(quota Quota, filePath string, size int64) error {
+	if quota.MaxFileBytes > 0 && size > quota.MaxFileBytes {
+		return fmt.Errorf("%w: %s is %d bytes, the limit is %d bytes per file", ErrQuotaExceeded, filePath, size, quota.MaxFileBytes)
+	}
+
+	return nil
+}
+
+// checkUsage checks that changing the usage of a bucket by delta keeps
+// it within its quota. Changes that don't grow the bucket are allowed
+// even if it is over quota already.
+func checkUsage(quota Quota, usage, delta Usage) error {
+	if quota.MaxFiles > 0 && delta.Files > 0 && usage.Files+delta.Files > quota.MaxFiles {
+		return fmt.Errorf("%w: the limit is %d files", ErrQuotaExceeded, quota.MaxFiles)
+	}
+	if quota.MaxTotalBytes > 0 && delta.TotalBytes > 0 && usage.TotalBytes+delta.TotalBytes > quota.MaxTotalBytes {
+		return fmt.Errorf("%w: the limit is %d bytes per bucket", ErrQuotaExceeded, quota.MaxTotalBytes)
+	}
+
+	return nil
+}
+
+func (fsm *FileSystemManager) recordUsage(ctx context.Context, bucketID string, delta Usage) {
+	pipe := fsm.redis.TxPipeline()
+	pipe.HIncrBy(ctx, usageKey(bucketID), "bytes", delta.TotalBytes)
+	pipe.HIncrBy(ctx, usageKey(bucketID), "files", delta.Files)
+	pipe.Exec(ctx)
+}
+
+// ImportLimits returns the limits for importing an archive into a
+// bucket, derived from the bucket's quota.
+func (fsm *FileSystemManager) ImportLimits(ctx context.Context, bucketID string) (zipImporter.Limits, error) {
+	quota, err := fsm.GetBucketQuota(ctx, bucketID)
+	if err != nil {
+		return zipImporter.Limits{}, err
+	}
+
+	return zipImporter.Limits{
+		MaxArchiveBytes: fsm.maxArchiveBytes,
+		MaxFiles:        quota.MaxFiles,
+		MaxFileBytes:    quota.MaxFileBytes,
+		MaxTotalBytes:   quota.MaxTotalBytes,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package fs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateFilePath(t *testing.T) {
+	tests := []struct {
+		path  string
+		valid bool
+	}{
+		{"main.ts", true},
+		{"src/main.ts", true},
+		{".env", true},
+		{"a/.hidden/b", true},
+		{"a..b", true},
+		{"", false},
+		{"/etc/passwd", false},
+		{"..", false},
+		{"../x", false},
+		{"a/../../x", false},
+		{"a/../b", false},
+		{"./a", false},
+		{"a//b", false},
+		{"a/", false},
+		{"a\\b", false},
+		{"a\x00b", false},
+	}
+
+	for _, tt := range tests {
+		err := validateFilePath(tt.path)
+		if tt.valid && err != nil {
+			t.Errorf("%q: unexpected error %v", tt.path, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("%q: expected ErrInvalidPath, got %v", tt.path, err)
+		}
+	}
+}
+
+func TestCheckUsage(t *testing.T) {
+	quota := Quota{MaxTotalBytes: 100, MaxFiles: 3}
+
+	tests := []struct {
+		name  string
+		quota Quota
+		usage Usage
+		delta Usage
+		valid bool
+	}{
+		{"within quota", quota, Usage{TotalBytes: 50, Files: 2}, Usage{TotalBytes: 50, Files: 1}, true},
+		{"too many files", quota, Usage{TotalBytes: 50, Files: 3}, Usage{TotalBytes: 1, Files: 1}, false},
+		{"too many bytes", quota, Usage{TotalBytes: 90, Files: 1}, Usage{TotalBytes: 11, Files: 1}, false},
+		{"overwrite grows bucket", quota, Usage{TotalBytes: 90, Files: 3}, Usage{TotalBytes: 11}, false},
+		{"overwrite within quota", quota, Usage{TotalBytes: 90, Files: 3}, Usage{TotalBytes: 10}, true},
+		{"shrinking over quota", quota, Usage{TotalBytes: 500, Files: 10}, Usage{TotalBytes: -10, Files: -1}, true},
+		{"no quota", Quota{}, Usage{TotalBytes: 1 << 40, Files: 1 << 20}, Usage{TotalBytes: 1, Files: 1}, true},
+	}
+
+	for _, tt := range tests {
+		err := checkUsage(tt.quota, tt.usage, tt.delta)
+		if tt.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrQuotaExceeded) {
+			t.Errorf("%s: expected ErrQuotaExceeded, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestCheckFileSize(t *testing.T) {
+	if err := checkFileSize(Quota{MaxFileBytes: 10}, "a", 10); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := checkFileSize(Quota{MaxFileBytes: 10}, "a", 11); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if err := checkFileSize(Quota{}, "a", 1<<40); err != nil {
+		t.Errorf("unexpected error without a limit %v", err)
+	}
+}
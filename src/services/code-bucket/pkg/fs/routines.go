@@ -97,6 +97,15 @@ func (fsm *FileSystemManager) flushFileToS3(ctx context.Context, bucketID, fileP
 	}
 
 	s3Key := fmt.Sprintf("%s/%s", bucketID, filePath)
+
+	if fileData.Deleted {
+		_, err = fsm.s3Client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(fsm.bucketName),
+			Key:    aws.String(s3Key),
+		})
+		return err
+	}
+
 	_, err = fsm.s3Client.PutObjectWithContext(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(fsm.bucketName),
 		Key:         aws.String(s3Key),
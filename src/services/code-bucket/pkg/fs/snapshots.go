@@ -0,0 +1,575 @@
+package fs
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	memoryQueue "github.com/metorial/metorial/modules/memory-queue"
+)
+
+var (
+	ErrSnapshotNotFound    = errors.New("snapshot not found")
+	ErrInvalidSnapshotName = errors.New("invalid snapshot name")
+)
+
+const (
+	SnapshotChangeAdded    = "added"
+	SnapshotChangeRemoved  = "removed"
+	SnapshotChangeModified = "modified"
+)
+
+// SnapshotFile is a file of a snapshot. Its content is stored once per
+// hash, shared by all snapshots of all buckets.
+type SnapshotFile struct {
+	Path        string `json:"path"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type"`
+	Hash        string `json:"hash"`
+}
+
+// Snapshot is an immutable state of a bucket. Its ID is the hash of its
+// file list, so snapshots of the same state share an ID. Name is the name
+// it was last created under, Names holds all of them.
+type Snapshot struct {
+	ID        string         `json:"id"`
+	BucketID  string         `json:"bucket_id"`
+	Name      string         `json:"name,omitempty"`
+	Names     []string       `json:"names,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	Files     []SnapshotFile `json:"files"`
+}
+
+type SnapshotInfo struct {
+	ID        string    `json:"id"`
+	BucketID  string    `json:"bucket_id"`
+	Name      string    `json:"name,omitempty"`
+	Names     []string  `json:"names,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	FileCount int       `json:"file_count"`
+	TotalSize int64     `json:"total_size"`
+}
+
+type SnapshotDiffEntry struct {
+	Path     string `json:"path"`
+	Change   string `json:"change"`
+	FromHash string `json:"from_hash,omitempty"`
+	ToHash   string `json:"to_hash,omitempty"`
+}
+
+func (s *Snapshot) Info() SnapshotInfo {
+	info := SnapshotInfo{
+		ID:        s.ID,
+		BucketID:  s.BucketID,
+		Name:      s.Name,
+		Names:     s.Names,
+		CreatedAt: s.CreatedAt,
+		FileCount: len(s.Files),
+	}
+
+	for _, file := range s.Files {
+		info.TotalSize += file.Size
+	}
+
+	return info
+}
+
+// hasName reports whether the snapshot was created under a name.
+// Snapshots stored before Names was added only carry Name.
+func (s *Snapshot) hasName(name string) bool {
+	if s.Name == name {
+		return true
+	}
+
+	for _, n := range s.Names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Snapshot) file(filePath string) (*SnapshotFile, bool) {
+	i := sort.Search(len(s.Files), func(i int) bool {
+		return s.Files[i].Path >= filePath
+	})
+	if i < len(s.Files) && s.Files[i].Path == filePath {
+		return &s.Files[i], true
+	}
+
+	return nil, false
+}
+
+func snapshotID(files []SnapshotFile) string {
+	hash := sha256.New()
+	for _, file := range files {
+		fmt.Fprintf(hash, "%s\x00%s\x00%s\n", file.Path, file.ContentType, file.Hash)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// isSnapshotID reports whether ref has the form of a snapshot ID. Only
+// such refs are used to build object keys.
+func isSnapshotID(ref string) bool {
+	if len(ref) != sha256.Size*2 {
+		return false
+	}
+
+	_, err := hex.DecodeString(ref)
+	return err == nil && strings.ToLower(ref) == ref
+}
+
+// validateSnapshotName applies the rules of file paths to snapshot names,
+// so a name can't address anything outside of the bucket's snapshots.
+func validateSnapshotName(name string) error {
+	if err := validateFilePath(name); err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidSnapshotName, name)
+	}
+
+	return nil
+}
+
+func blobKey(hash string) string {
+	return fmt.Sprintf("blobs/%s", hash)
+}
+
+func snapshotKey(bucketID, snapshotID string) string {
+	return fmt.Sprintf("snapshots/%s/%s.json", bucketID, snapshotID)
+}
+
+// currentState reads all files of a bucket and hashes them. If storeBlobs
+// is set, contents not yet in the blob store are uploaded.
+func (fsm *FileSystemManager) currentState(ctx context.Context, bucketID string, storeBlobs bool) ([]SnapshotFile, error) {
+	infos, err := fsm.GetBucketFiles(ctx, bucketID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]SnapshotFile, 0, len(infos))
+	var mu sync.Mutex
+
+	queue := memoryQueue.NewBlockingJobQueue(15)
+
+	for _, info := range infos {
+		filePath := info.Path
+		queue.AddAndBlockIfFull(func() error {
+			_, data, err := fsm.GetBucketFile(ctx, bucketID, filePath)
+			if err != nil {
+				// Deleted since it was listed
+				return nil
+			}
+
+			sum := sha256.Sum256(data.Content)
+			hash := hex.EncodeToString(sum[:])
+
+			if storeBlobs {
+				if err := fsm.putBlob(ctx, hash, data.Content); err != nil {
+					return err
+				}
+			}
+
+			mu.Lock()
+			files = append(files, SnapshotFile{
+				Path:        filePath,
+				Size:        int64(len(data.Content)),
+				ContentType: data.ContentType,
+				Hash:        hash,
+			})
+			mu.Unlock()
+
+			return nil
+		})
+	}
+
+	if err := queue.Wait(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	return files, nil
+}
+
+// putBlob stores content under its hash unless it is stored already.
+func (fsm *FileSystemManager) putBlob(ctx context.Context, hash string, content []byte) error {
+	_, err := fsm.s3Client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(fsm.bucketName),
+		Key:    aws.String(blobKey(hash)),
+	})
+	if err == nil {
+		return nil
+	}
+
+	_, err = fsm.s3Client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(fsm.bucketName),
+		Key:    aws.String(blobKey(hash)),
+		Body:   bytes.NewReader(content),
+	})
+
+	return err
+}
+
+func (fsm *FileSystemManager) getBlob(ctx context.Context, hash string) ([]byte, error) {
+	obj, err := fsm.s3Client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(fsm.bucketName),
+		Key:    aws.String(blobKey(hash)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob %s: %w", hash, err)
+	}
+	defer obj.Body.Close()
+
+	return io.ReadAll(obj.Body)
+}
+
+// CreateSnapshot stores the current state of a bucket as a snapshot.
+// Creating a snapshot of a state that was snapshotted before returns the
+// existing snapshot, with the name added to its names if one is given.
+func (fsm *FileSystemManager) CreateSnapshot(ctx context.Context, bucketID, name string) (*Snapshot, error) {
+	if name != "" {
+		if err := validateSnapshotName(name); err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := fsm.currentState(ctx, bucketID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{
+		ID:        snapshotID(files),
+		BucketID:  bucketID,
+		Name:      name,
+		CreatedAt: time.Now(),
+		Files:     files,
+	}
+	if name != "" {
+		snapshot.Names = []string{name}
+	}
+
+	if existing, err := fsm.readSnapshot(ctx, bucketID, snapshot.ID); err == nil {
+		if name == "" || existing.hasName(name) {
+			return existing, nil
+		}
+
+		if len(existing.Names) == 0 && existing.Name != "" {
+			existing.Names = []string{existing.Name}
+		}
+		existing.Name = name
+		existing.Names = append(existing.Names, name)
+		snapshot = existing
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = fsm.s3Client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(fsm.bucketName),
+		Key:         aws.String(snapshotKey(bucketID, snapshot.ID)),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+func (fsm *FileSystemManager) readSnapshot(ctx context.Context, bucketID, snapshotID string) (*Snapshot, error) {
+	obj, err := fsm.s3Client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(fsm.bucketName),
+		Key:    aws.String(snapshotKey(bucketID, snapshotID)),
+	})
+	if err != nil {
+		return nil, ErrSnapshotNotFound
+	}
+	defer obj.Body.Close()
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(obj.Body).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", snapshotID, err)
+	}
+
+	return &snapshot, nil
+}
+
+func (fsm *FileSystemManager) listSnapshots(ctx context.Context, bucketID string) ([]*Snapshot, error) {
+	snapshots := make([]*Snapshot, 0)
+
+	err := fsm.s3Client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(fsm.bucketName),
+		Prefix: aws.String(fmt.Sprintf("snapshots/%s/", bucketID)),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			id := strings.TrimSuffix(strings.TrimPrefix(*obj.Key, fmt.Sprintf("snapshots/%s/", bucketID)), ".json")
+			if !isSnapshotID(id) {
+				continue
+			}
+
+			snapshot, err := fsm.readSnapshot(ctx, bucketID, id)
+			if err != nil {
+				continue
+			}
+
+			snapshots = append(snapshots, snapshot)
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+
+	return snapshots, nil
+}
+
+// ListSnapshots returns the snapshots of a bucket, newest first.
+func (fsm *FileSystemManager) ListSnapshots(ctx context.Context, bucketID string) ([]SnapshotInfo, error) {
+	snapshots, err := fsm.listSnapshots(ctx, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]SnapshotInfo, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		infos = append(infos, snapshot.Info())
+	}
+
+	return infos, nil
+}
+
+// GetSnapshot returns a snapshot by its ID or any of its names. If
+// several snapshots share a name, the newest one is returned.
+func (fsm *FileSystemManager) GetSnapshot(ctx context.Context, bucketID, ref string) (*Snapshot, error) {
+	if ref == "" || validateSnapshotName(ref) != nil {
+		return nil, ErrSnapshotNotFound
+	}
+
+	if isSnapshotID(ref) {
+		if snapshot, err := fsm.readSnapshot(ctx, bucketID, ref); err == nil {
+			return snapshot, nil
+		}
+	}
+
+	snapshots, err := fsm.listSnapshots(ctx, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, snapshot := range snapshots {
+		if snapshot.hasName(ref) {
+			return snapshot, nil
+		}
+	}
+
+	return nil, ErrSnapshotNotFound
+}
+
+// GetSnapshotFiles lists the files of a snapshot, like GetBucketFiles
+// does for the current state.
+func (fsm *FileSystemManager) GetSnapshotFiles(ctx context.Context, bucketID, ref, prefix string) ([]FileInfo, error) {
+	snapshot, err := fsm.GetSnapshot(ctx, bucketID, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]FileInfo, 0, len(snapshot.Files))
+	for _, file := range snapshot.Files {
+		if prefix != "" && !strings.HasPrefix(file.Path, prefix) {
+			continue
+		}
+
+		files = append(files, FileInfo{
+			Path:        file.Path,
+			Size:        file.Size,
+			ContentType: file.ContentType,
+			ModifiedAt:  snapshot.CreatedAt,
+		})
+	}
+
+	return files, nil
+}
+
+// GetSnapshotFile returns a file as it was when the snapshot was taken.
+func (fsm *FileSystemManager) GetSnapshotFile(ctx context.Context, bucketID, ref, filePath string) (*FileInfo, *FileData, error) {
+	snapshot, err := fsm.GetSnapshot(ctx, bucketID, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, ok := snapshot.file(filePath)
+	if !ok {
+		return nil, nil, fmt.Errorf("file not found")
+	}
+
+	content, err := fsm.getBlob(ctx, file.Hash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := &FileInfo{
+		Path:        file.Path,
+		Size:        file.Size,
+		ContentType: file.ContentType,
+		ModifiedAt:  snapshot.CreatedAt,
+	}
+
+	data := &FileData{
+		Content:     content,
+		ContentType: file.ContentType,
+		ModifiedAt:  snapshot.CreatedAt,
+	}
+
+	return info, data, nil
+}
+
+// DiffSnapshots lists the changes between two snapshots. An empty ref
+// stands for the current state of the bucket.
+func (fsm *FileSystemManager) DiffSnapshots(ctx context.Context, bucketID, fromRef, toRef string) ([]SnapshotDiffEntry, error) {
+	stateOf := func(ref string) ([]SnapshotFile, error) {
+		if ref == "" {
+			return fsm.currentState(ctx, bucketID, false)
+		}
+
+		snapshot, err := fsm.GetSnapshot(ctx, bucketID, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		return snapshot.Files, nil
+	}
+
+	from, err := stateOf(fromRef)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := stateOf(toRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffFiles(from, to), nil
+}
+
+// diffFiles compares two file lists sorted by path.
+func diffFiles(from, to []SnapshotFile) []SnapshotDiffEntry {
+	changes := make([]SnapshotDiffEntry, 0)
+
+	i, j := 0, 0
+	for i < len(from) || j < len(to) {
+		switch {
+		case j >= len(to) || (i < len(from) && from[i].Path < to[j].Path):
+			changes = append(changes, SnapshotDiffEntry{
+				Path:     from[i].Path,
+				Change:   SnapshotChangeRemoved,
+				FromHash: from[i].Hash,
+			})
+			i++
+
+		case i >= len(from) || to[j].Path < from[i].Path:
+			changes = append(changes, SnapshotDiffEntry{
+				Path:   to[j].Path,
+				Change: SnapshotChangeAdded,
+				ToHash: to[j].Hash,
+			})
+			j++
+
+		default:
+			if from[i].Hash != to[j].Hash || from[i].ContentType != to[j].ContentType {
+				changes = append(changes, SnapshotDiffEntry{
+					Path:     from[i].Path,
+					Change:   SnapshotChangeModified,
+					FromHash: from[i].Hash,
+					ToHash:   to[j].Hash,
+				})
+			}
+			i++
+			j++
+		}
+	}
+
+	return changes
+}
+
+// RestoreSnapshot resets a bucket to the state of a snapshot. Files
+// added after the snapshot was taken are deleted.
+func (fsm *FileSystemManager) RestoreSnapshot(ctx context.Context, bucketID, ref string) error {
+	snapshot, err := fsm.GetSnapshot(ctx, bucketID, ref)
+	if err != nil {
+		return err
+	}
+
+	current, err := fsm.currentState(ctx, bucketID, false)
+	if err != nil {
+		return err
+	}
+
+	queue := memoryQueue.NewBlockingJobQueue(15)
+
+	for _, change := range diffFiles(current, snapshot.Files) {
+		filePath := change.Path
+
+		if change.Change == SnapshotChangeRemoved {
+			queue.AddAndBlockIfFull(func() error {
+				return fsm.DeleteBucketFile(ctx, bucketID, filePath)
+			})
+			continue
+		}
+
+		file, _ := snapshot.file(filePath)
+		queue.AddAndBlockIfFull(func() error {
+			content, err := fsm.getBlob(ctx, file.Hash)
+			if err != nil {
+				return err
+			}
+
+			return fsm.PutBucketFile(ctx, bucketID, file.Path, content, file.ContentType)
+		})
+	}
+
+	return queue.Wait()
+}
+
+// GetBucketFileAt returns a file of a snapshot, or of the current state
+// if ref is empty.
+func (fsm *FileSystemManager) GetBucketFileAt(ctx context.Context, bucketID, ref, filePath string) (*FileInfo, *FileData, error) {
+	if ref == "" {
+		return fsm.GetBucketFile(ctx, bucketID, filePath)
+	}
+
+	return fsm.GetSnapshotFile(ctx, bucketID, ref, filePath)
+}
+
+// GetBucketFilesAt lists the files of a snapshot, or of the current
+// state if ref is empty.
+func (fsm *FileSystemManager) GetBucketFilesAt(ctx context.Context, bucketID, ref, prefix string) ([]FileInfo, error) {
+	if ref == "" {
+		return fsm.GetBucketFiles(ctx, bucketID, prefix)
+	}
+
+	return fsm.GetSnapshotFiles(ctx, bucketID, ref, prefix)
+}
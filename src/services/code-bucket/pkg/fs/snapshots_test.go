@@ -0,0 +1,119 @@
+package fs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateSnapshotName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"release", true},
+		{"v1.2.3", true},
+		{"releases/v1", true},
+		{"", false},
+		{"/etc/passwd", false},
+		{"..", false},
+		{"../other-bucket/x", false},
+		{"a/../../b", false},
+		{"a\\b", false},
+		{"a\x00b", false},
+	}
+
+	for _, tt := range tests {
+		err := validateSnapshotName(tt.name)
+		if tt.valid && err != nil {
+			t.Errorf("%q: unexpected error %v", tt.name, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidSnapshotName) {
+			t.Errorf("%q: expected ErrInvalidSnapshotName, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestIsSnapshotID(t *testing.T) {
+	id := snapshotID([]SnapshotFile{{Path: "a", Hash: "h"}})
+
+	if !isSnapshotID(id) {
+		t.Fatalf("expected %q to be a snapshot ID", id)
+	}
+
+	for _, ref := range []string{"", "release", strings.ToUpper(id), id[:63], id + "0", "../" + id[3:]} {
+		if isSnapshotID(ref) {
+			t.Errorf("expected %q not to be a snapshot ID", ref)
+		}
+	}
+}
+
+func TestSnapshotHasName(t *testing.T) {
+	legacy := &Snapshot{Name: "old"}
+	if !legacy.hasName("old") || legacy.hasName("new") {
+		t.Fatal("legacy snapshot should match its single name")
+	}
+
+	snapshot := &Snapshot{Name: "new", Names: []string{"old", "new"}}
+	for _, name := range []string{"old", "new"} {
+		if !snapshot.hasName(name) {
+			t.Errorf("expected snapshot to be named %q", name)
+		}
+	}
+}
+
+func TestDiffFiles(t *testing.T) {
+	a := SnapshotFile{Path: "a.ts", Hash: "ha", ContentType: "text/plain"}
+	b := SnapshotFile{Path: "b.ts", Hash: "hb", ContentType: "text/plain"}
+	c := SnapshotFile{Path: "c.ts", Hash: "hc", ContentType: "text/plain"}
+
+	bModified := b
+	bModified.Hash = "hb2"
+	bRetyped := b
+	bRetyped.ContentType = "application/typescript"
+
+	tests := []struct {
+		name string
+		from []SnapshotFile
+		to   []SnapshotFile
+		want []SnapshotDiffEntry
+	}{
+		{"equal", []SnapshotFile{a, b}, []SnapshotFile{a, b}, []SnapshotDiffEntry{}},
+		{"both empty", nil, nil, []SnapshotDiffEntry{}},
+		{"added", []SnapshotFile{a}, []SnapshotFile{a, b}, []SnapshotDiffEntry{
+			{Path: "b.ts", Change: SnapshotChangeAdded, ToHash: "hb"},
+		}},
+		{"removed", []SnapshotFile{a, b, c}, []SnapshotFile{b}, []SnapshotDiffEntry{
+			{Path: "a.ts", Change: SnapshotChangeRemoved, FromHash: "ha"},
+			{Path: "c.ts", Change: SnapshotChangeRemoved, FromHash: "hc"},
+		}},
+		{"modified", []SnapshotFile{a, b}, []SnapshotFile{a, bModified}, []SnapshotDiffEntry{
+			{Path: "b.ts", Change: SnapshotChangeModified, FromHash: "hb", ToHash: "hb2"},
+		}},
+		{"content type changed", []SnapshotFile{b}, []SnapshotFile{bRetyped}, []SnapshotDiffEntry{
+			{Path: "b.ts", Change: SnapshotChangeModified, FromHash: "hb", ToHash: "hb"},
+		}},
+		{"mixed", []SnapshotFile{a, b}, []SnapshotFile{bModified, c}, []SnapshotDiffEntry{
+			{Path: "a.ts", Change: SnapshotChangeRemoved, FromHash: "ha"},
+			{Path: "b.ts", Change: SnapshotChangeModified, FromHash: "hb", ToHash: "hb2"},
+			{Path: "c.ts", Change: SnapshotChangeAdded, ToHash: "hc"},
+		}},
+		{"from empty", nil, []SnapshotFile{a}, []SnapshotDiffEntry{
+			{Path: "a.ts", Change: SnapshotChangeAdded, ToHash: "ha"},
+		}},
+	}
+
+	for _, tt := range tests {
+		got := diffFiles(tt.from, tt.to)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: diff %+v, want %+v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: diff %+v, want %+v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
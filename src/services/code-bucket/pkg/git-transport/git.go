@@ -0,0 +1,538 @@
+package gitTransport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	ssrfProtection "github.com/metorial/metorial/modules/ssrf-protection"
+	zipImporter "github.com/metorial/metorial/services/code-bucket/pkg/zip-importer"
+)
+
+var ErrNoChanges = errors.New("bucket matches the branch, nothing to commit")
+
+// Remote is a git repository reachable over HTTPS.
+type Remote struct {
+	URL string
+	// Username defaults to "git". Most hosts ignore it when Token is an
+	// access token, GitLab expects "oauth2".
+	Username string
+	Token    string
+}
+
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// validateRemote only allows HTTPS remotes on public addresses. Tests
+// replace it to use local repositories.
+var validateRemote = func(remoteURL string) error {
+	uri, err := url.Parse(remoteURL)
+	if err != nil {
+		return fmt.Errorf("invalid remote URL: %w", err)
+	}
+	if uri.Scheme != "https" {
+		return fmt.Errorf("only https remotes are supported")
+	}
+
+	return ssrfProtection.ValidateURL(remoteURL)
+}
+
+var installTransport sync.Once
+
+// useSecureTransport makes go-git connect through the SSRF protected
+// client, which also validates the addresses of redirects.
+func useSecureTransport() {
+	installTransport.Do(func() {
+		httpClient := ssrfProtection.CreateSecureHTTPClient()
+		httpClient.Timeout = 10 * time.Minute
+		httpClient.Transport = &limitedTransport{base: httpClient.Transport}
+
+		client.InstallProtocol("https", githttp.NewClient(httpClient))
+	})
+}
+
+type transferLimitKey struct{}
+
+// transferLimit caps the bytes received for one operation, across all of
+// its requests.
+type transferLimit struct {
+	maxBytes int64
+	received atomic.Int64
+}
+
+func withTransferLimit(ctx context.Context, maxBytes int64) (context.Context, *transferLimit) {
+	if maxBytes <= 0 {
+		return ctx, nil
+	}
+
+	limit := &transferLimit{maxBytes: maxBytes}
+	return context.WithValue(ctx, transferLimitKey{}, limit), limit
+}
+
+func (l *transferLimit) err() error {
+	if l == nil || l.received.Load() <= l.maxBytes {
+		return nil
+	}
+	return fmt.Errorf("%w: repository transfer is larger than %d bytes", zipImporter.ErrLimitExceeded, l.maxBytes)
+}
+
+// limitedTransport counts response bodies against the transfer limit of
+// the request's context, if it has one.
+type limitedTransport struct {
+	base http.RoundTripper
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit, ok := req.Context().Value(transferLimitKey{}).(*transferLimit); ok {
+		resp.Body = &limitedBody{ReadCloser: resp.Body, limit: limit}
+	}
+
+	return resp, nil
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	limit *transferLimit
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.limit.received.Add(int64(n))
+
+	if limitErr := b.limit.err(); limitErr != nil {
+		return n, limitErr
+	}
+	return n, err
+}
+
+func (r Remote) auth() transport.AuthMethod {
+	if r.Token == "" {
+		return nil
+	}
+
+	username := r.Username
+	if username == "" {
+		username = "git"
+	}
+
+	return &githttp.BasicAuth{Username: username, Password: r.Token}
+}
+
+// Clone checks out ref, a branch, tag or commit hash, or the default
+// branch if empty, and iterates over the files below path. The download
+// is limited to MaxArchiveBytes, the other limits are checked against
+// the files of the commit before they are written.
+func Clone(ctx context.Context, remote Remote, ref, path string, limits zipImporter.Limits) (*zipImporter.ZipFileIterator, error) {
+	if err := validateRemote(remote.URL); err != nil {
+		return nil, err
+	}
+	useSecureTransport()
+
+	tmpDir, err := os.MkdirTemp("", "git-clone-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+
+	// The repository is kept apart from the checkout, so files of the
+	// commit can't write into it.
+	repoDir := filepath.Join(tmpDir, "repo.git")
+	checkoutDir := filepath.Join(tmpDir, "checkout")
+
+	targetPath := filepath.Join(checkoutDir, path)
+	if targetPath != checkoutDir && !strings.HasPrefix(targetPath, checkoutDir+string(os.PathSeparator)) {
+		os.RemoveAll(tmpDir)
+		return nil, fmt.Errorf("illegal path: %s", path)
+	}
+
+	ctx, limit := withTransferLimit(ctx, limits.MaxArchiveBytes)
+
+	commit, err := fetchCommit(ctx, remote, ref, repoDir)
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		if limitErr := limit.err(); limitErr != nil {
+			return nil, limitErr
+		}
+		return nil, err
+	}
+
+	filePaths, err := writeFiles(commit, path, targetPath, limits)
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, err
+	}
+
+	// The repository is not needed anymore, only the checkout.
+	os.RemoveAll(repoDir)
+
+	return zipImporter.NewFileIterator(tmpDir, targetPath, filePaths), nil
+}
+
+// fetchCommit clones the repository into dir as a bare repository and
+// returns the commit ref points to.
+func fetchCommit(ctx context.Context, remote Remote, ref, dir string) (*object.Commit, error) {
+	options := &git.CloneOptions{
+		URL:          remote.URL,
+		Auth:         remote.auth(),
+		Depth:        1,
+		SingleBranch: true,
+		Tags:         git.NoTags,
+	}
+
+	if ref == "" {
+		repo, err := git.PlainCloneContext(ctx, dir, true, options)
+		if err != nil {
+			return nil, wrapCloneError(err)
+		}
+		return headCommit(repo)
+	}
+
+	for _, name := range []plumbing.ReferenceName{plumbing.NewBranchReferenceName(ref), plumbing.NewTagReferenceName(ref)} {
+		options.ReferenceName = name
+
+		repo, err := git.PlainCloneContext(ctx, dir, true, options)
+		if err == nil {
+			return headCommit(repo)
+		}
+		if !errors.Is(err, plumbing.ErrReferenceNotFound) && !isNoMatchingRef(err) {
+			return nil, wrapCloneError(err)
+		}
+
+		os.RemoveAll(dir)
+	}
+
+	// Not a branch or tag, so it has to be a commit. Servers generally
+	// don't allow fetching arbitrary commits, so the full history is
+	// fetched.
+	if !plumbing.IsHash(ref) {
+		return nil, fmt.Errorf("ref %s not found", ref)
+	}
+
+	repo, err := git.PlainCloneContext(ctx, dir, true, &git.CloneOptions{
+		URL:  remote.URL,
+		Auth: remote.auth(),
+	})
+	if err != nil {
+		return nil, wrapCloneError(err)
+	}
+
+	commit, err := repo.CommitObject(plumbing.NewHash(ref))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find commit %s: %w", ref, err)
+	}
+
+	return commit, nil
+}
+
+// headCommit returns the commit HEAD points to, peeling annotated tags.
+func headCommit(repo *git.Repository) (*object.Commit, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	if tag, err := repo.TagObject(head.Hash()); err == nil {
+		return tag.Commit()
+	}
+
+	return repo.CommitObject(head.Hash())
+}
+
+func isNoMatchingRef(err error) bool {
+	var noMatchingRefSpec git.NoMatchingRefSpecError
+	return errors.As(err, &noMatchingRefSpec)
+}
+
+func wrapCloneError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("failed to clone repository: %w", err)
+}
+
+// writeFiles writes the regular files of the commit below path to
+// targetPath. The limits are checked for each file before it is written.
+func writeFiles(commit *object.Commit, path, targetPath string, limits zipImporter.Limits) ([]string, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	if treePath := filepath.ToSlash(filepath.Clean(path)); treePath != "." {
+		tree, err = tree.Tree(treePath)
+		if err != nil {
+			return nil, fmt.Errorf("path %s not found: %w", path, err)
+		}
+	}
+
+	var filePaths []string
+	var totalBytes int64
+
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if f.Mode != filemode.Regular && f.Mode != filemode.Executable && f.Mode != filemode.Deprecated {
+			return nil
+		}
+
+		if limits.MaxFileBytes > 0 && f.Size > limits.MaxFileBytes {
+			return fmt.Errorf("%w: %s is too large", zipImporter.ErrLimitExceeded, f.Name)
+		}
+
+		totalBytes += f.Size
+		if limits.MaxTotalBytes > 0 && totalBytes > limits.MaxTotalBytes {
+			return fmt.Errorf("%w: more than %d bytes", zipImporter.ErrLimitExceeded, limits.MaxTotalBytes)
+		}
+
+		if limits.MaxFiles > 0 && int64(len(filePaths)) >= limits.MaxFiles {
+			return fmt.Errorf("%w: more than %d files", zipImporter.ErrLimitExceeded, limits.MaxFiles)
+		}
+
+		fullPath := filepath.Join(targetPath, filepath.FromSlash(f.Name))
+		if !strings.HasPrefix(fullPath, targetPath+string(os.PathSeparator)) {
+			return fmt.Errorf("illegal file path: %s", f.Name)
+		}
+
+		if err := writeBlob(f, fullPath); err != nil {
+			return err
+		}
+
+		filePaths = append(filePaths, fullPath)
+		return nil
+	})
+
+	return filePaths, err
+}
+
+func writeBlob(f *object.File, fullPath string) error {
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+
+	reader, err := f.Reader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	out, err := os.Create(fullPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, reader)
+	return err
+}
+
+type ExportOptions struct {
+	Branch string
+	// Path is the directory of the repository the files are written to.
+	// Everything else in the repository is kept.
+	Path        string
+	AuthorName  string
+	AuthorEmail string
+	Message     string
+}
+
+// Export replaces the contents of a directory of a branch with files in
+// a single commit and pushes it. The branch is created from the default
+// branch if it doesn't exist. It returns the hash of the commit, or of
+// the branch's head together with ErrNoChanges if nothing changed.
+func Export(ctx context.Context, remote Remote, files []File, opts ExportOptions) (string, error) {
+	if err := validateRemote(remote.URL); err != nil {
+		return "", err
+	}
+	useSecureTransport()
+
+	if opts.Branch == "" {
+		return "", fmt.Errorf("branch is required")
+	}
+	if opts.Message == "" {
+		opts.Message = "Update from Metorial"
+	}
+	if opts.AuthorName == "" {
+		opts.AuthorName = "Metorial"
+	}
+
+	tmpDir, err := os.MkdirTemp("", "git-export-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	repo, err := openBranch(ctx, remote, opts.Branch, tmpDir)
+	if err != nil {
+		return "", err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+
+	targetDir := filepath.Join(tmpDir, opts.Path)
+	if targetDir != tmpDir && !strings.HasPrefix(targetDir, tmpDir+string(os.PathSeparator)) {
+		return "", fmt.Errorf("illegal path: %s", opts.Path)
+	}
+	if err := replaceDir(tmpDir, targetDir, files); err != nil {
+		return "", err
+	}
+
+	if err := worktree.AddWithOptions(&git.AddOptions{All: true}); err != nil {
+		return "", fmt.Errorf("failed to stage files: %w", err)
+	}
+
+	commit, err := worktree.Commit(opts.Message, &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  opts.AuthorName,
+			Email: opts.AuthorEmail,
+			When:  time.Now(),
+		},
+	})
+	if errors.Is(err, git.ErrEmptyCommit) {
+		head, headErr := repo.Head()
+		if headErr != nil {
+			return "", ErrNoChanges
+		}
+		return head.Hash().String(), ErrNoChanges
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to commit: %w", err)
+	}
+
+	branchRef := plumbing.NewBranchReferenceName(opts.Branch)
+	err = repo.PushContext(ctx, &git.PushOptions{
+		RemoteName: git.DefaultRemoteName,
+		Auth:       remote.auth(),
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", branchRef, branchRef))},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to push: %w", err)
+	}
+
+	return commit.String(), nil
+}
+
+// openBranch clones the branch into dir, or the default branch checked
+// out as a new branch if it doesn't exist, or initializes an empty
+// repository if the remote has no commits yet.
+func openBranch(ctx context.Context, remote Remote, branch, dir string) (*git.Repository, error) {
+	branchRef := plumbing.NewBranchReferenceName(branch)
+
+	repo, err := git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
+		URL:           remote.URL,
+		Auth:          remote.auth(),
+		ReferenceName: branchRef,
+		SingleBranch:  true,
+		Tags:          git.NoTags,
+	})
+	if err == nil {
+		return repo, nil
+	}
+	if !errors.Is(err, plumbing.ErrReferenceNotFound) && !isNoMatchingRef(err) && !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return nil, wrapCloneError(err)
+	}
+	os.RemoveAll(dir)
+
+	if !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		repo, err = git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
+			URL:          remote.URL,
+			Auth:         remote.auth(),
+			SingleBranch: true,
+			Tags:         git.NoTags,
+		})
+		if err != nil && !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+			return nil, wrapCloneError(err)
+		}
+	}
+
+	if err == nil {
+		worktree, err := repo.Worktree()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := worktree.Checkout(&git.CheckoutOptions{Branch: branchRef, Create: true}); err != nil {
+			return nil, fmt.Errorf("failed to create branch %s: %w", branch, err)
+		}
+
+		return repo, nil
+	}
+
+	os.RemoveAll(dir)
+
+	repo, err = git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: branchRef},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := repo.CreateRemote(&config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{remote.URL},
+	}); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// replaceDir removes everything in targetDir except the .git directory
+// of the repository and writes the files into it.
+func replaceDir(repoDir, targetDir string, files []File) error {
+	entries, err := os.ReadDir(targetDir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, entry := range entries {
+		if targetDir == repoDir && entry.Name() == ".git" {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(targetDir, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range files {
+		fullPath := filepath.Join(targetDir, file.Path)
+		if !strings.HasPrefix(fullPath, targetDir+string(os.PathSeparator)) {
+			return fmt.Errorf("illegal file path: %s", file.Path)
+		}
+		if fullPath == filepath.Join(repoDir, ".git") || strings.HasPrefix(fullPath, filepath.Join(repoDir, ".git")+string(os.PathSeparator)) {
+			return fmt.Errorf("illegal file path: %s", file.Path)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(fullPath, file.Content, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
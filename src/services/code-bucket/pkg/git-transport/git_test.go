@@ -0,0 +1,363 @@
+package gitTransport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	zipImporter "github.com/metorial/metorial/services/code-bucket/pkg/zip-importer"
+)
+
+func init() {
+	validateRemote = func(string) error { return nil }
+}
+
+// newBareRemote creates a bare repository with one commit on main and a
+// tag v1 pointing to it.
+func newBareRemote(t *testing.T, files map[string]string) (string, string) {
+	t.Helper()
+
+	bareDir := filepath.Join(t.TempDir(), "remote.git")
+	_, err := git.PlainInitWithOptions(bareDir, &git.PlainInitOptions{
+		Bare:        true,
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName("main")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workDir := t.TempDir()
+	repo, err := git.PlainInitWithOptions(workDir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName("main")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, content := range files {
+		fullPath := filepath.Join(workDir, path)
+		os.MkdirAll(filepath.Dir(fullPath), 0755)
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	worktree, _ := repo.Worktree()
+	if err := worktree.AddWithOptions(&git.AddOptions{All: true}); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := worktree.Commit("initial", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.CreateTag("v1", hash, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{bareDir}})
+	err = repo.Push(&git.PushOptions{
+		RefSpecs: []config.RefSpec{"refs/heads/*:refs/heads/*", "refs/tags/*:refs/tags/*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return bareDir, hash.String()
+}
+
+func readIterator(t *testing.T, iter *zipImporter.ZipFileIterator) map[string]string {
+	t.Helper()
+	defer iter.Close()
+
+	files := make(map[string]string)
+	for {
+		file, ok := iter.Next()
+		if !ok {
+			return files
+		}
+		files[file.Path] = string(file.Content)
+	}
+}
+
+func TestClone_Refs(t *testing.T) {
+	remote, hash := newBareRemote(t, map[string]string{
+		"README.md":   "readme",
+		"src/main.ts": "console.log(1)",
+	})
+
+	for _, ref := range []string{"", "main", "v1", hash} {
+		iter, err := Clone(context.Background(), Remote{URL: remote}, ref, "", zipImporter.Limits{})
+		if err != nil {
+			t.Fatalf("ref %q: %v", ref, err)
+		}
+
+		files := readIterator(t, iter)
+		if len(files) != 2 || files["src/main.ts"] != "console.log(1)" {
+			t.Fatalf("ref %q: unexpected files %v", ref, files)
+		}
+	}
+}
+
+func TestClone_Path(t *testing.T) {
+	remote, _ := newBareRemote(t, map[string]string{
+		"README.md":   "readme",
+		"src/main.ts": "console.log(1)",
+	})
+
+	iter, err := Clone(context.Background(), Remote{URL: remote}, "main", "src", zipImporter.Limits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := readIterator(t, iter)
+	if len(files) != 1 || files["main.ts"] != "console.log(1)" {
+		t.Fatalf("unexpected files %v", files)
+	}
+
+	if _, err := Clone(context.Background(), Remote{URL: remote}, "main", "../..", zipImporter.Limits{}); err == nil {
+		t.Fatal("expected path outside the repository to be rejected")
+	}
+}
+
+func TestClone_CloseRemovesCheckout(t *testing.T) {
+	remote, _ := newBareRemote(t, map[string]string{
+		"README.md":   "readme",
+		"src/main.ts": "console.log(1)",
+	})
+
+	tmpDir := t.TempDir()
+	t.Setenv("TMPDIR", tmpDir)
+
+	iter, err := Clone(context.Background(), Remote{URL: remote}, "main", "src", zipImporter.Limits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	readIterator(t, iter)
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected the checkout to be removed, found %v", entries)
+	}
+}
+
+func TestClone_UnknownRef(t *testing.T) {
+	remote, _ := newBareRemote(t, map[string]string{"a": "a"})
+
+	if _, err := Clone(context.Background(), Remote{URL: remote}, "does-not-exist", "", zipImporter.Limits{}); err == nil {
+		t.Fatal("expected unknown ref to fail")
+	}
+}
+
+func TestClone_Limits(t *testing.T) {
+	remote, _ := newBareRemote(t, map[string]string{"a": "aaaa", "b": "bbbb"})
+
+	_, err := Clone(context.Background(), Remote{URL: remote}, "", "", zipImporter.Limits{MaxFiles: 1})
+	if !errors.Is(err, zipImporter.ErrLimitExceeded) {
+		t.Fatalf("expected file count limit, got %v", err)
+	}
+
+	_, err = Clone(context.Background(), Remote{URL: remote}, "", "", zipImporter.Limits{MaxFileBytes: 3})
+	if !errors.Is(err, zipImporter.ErrLimitExceeded) {
+		t.Fatalf("expected file size limit, got %v", err)
+	}
+
+	_, err = Clone(context.Background(), Remote{URL: remote}, "", "", zipImporter.Limits{MaxTotalBytes: 7})
+	if !errors.Is(err, zipImporter.ErrLimitExceeded) {
+		t.Fatalf("expected total size limit, got %v", err)
+	}
+
+	iter, err := Clone(context.Background(), Remote{URL: remote}, "", "", zipImporter.Limits{MaxFiles: 2, MaxFileBytes: 4, MaxTotalBytes: 8})
+	if err != nil {
+		t.Fatalf("expected files at the limits to be accepted, got %v", err)
+	}
+	readIterator(t, iter)
+}
+
+func TestClone_LimitsRemoveCheckout(t *testing.T) {
+	remote, _ := newBareRemote(t, map[string]string{"a": "aaaa", "b": "bbbb", "c": "cccc"})
+
+	tmpDir := t.TempDir()
+	t.Setenv("TMPDIR", tmpDir)
+
+	_, err := Clone(context.Background(), Remote{URL: remote}, "", "", zipImporter.Limits{MaxFiles: 2})
+	if !errors.Is(err, zipImporter.ErrLimitExceeded) {
+		t.Fatalf("expected file count limit, got %v", err)
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected the checkout to be removed, found %v", entries)
+	}
+}
+
+func TestLimitedTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bytes.Repeat([]byte("x"), 1000))
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{Transport: &limitedTransport{base: http.DefaultTransport}}
+
+	tests := []struct {
+		name     string
+		maxBytes int64
+		valid    bool
+	}{
+		{"no limit", 0, true},
+		{"within limit", 1000, true},
+		{"over limit", 999, false},
+	}
+	for _, tt := range tests {
+		ctx, limit := withTransferLimit(context.Background(), tt.maxBytes)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		_, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if tt.valid && (err != nil || limit.err() != nil) {
+			t.Errorf("%s: unexpected error %v, %v", tt.name, err, limit.err())
+		}
+		if !tt.valid && (!errors.Is(err, zipImporter.ErrLimitExceeded) || !errors.Is(limit.err(), zipImporter.ErrLimitExceeded)) {
+			t.Errorf("%s: expected the limit to be exceeded, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestExport_ExistingBranch(t *testing.T) {
+	remote, _ := newBareRemote(t, map[string]string{
+		"README.md":   "readme",
+		"src/old.ts":  "old",
+		"src/keep.ts": "keep",
+	})
+
+	files := []File{
+		{Path: "keep.ts", Content: []byte("changed")},
+		{Path: "new/file.ts", Content: []byte("new")},
+	}
+
+	hash, err := Export(context.Background(), Remote{URL: remote}, files, ExportOptions{
+		Branch:      "main",
+		Path:        "src",
+		AuthorName:  "Jane",
+		AuthorEmail: "jane@example.com",
+		Message:     "Sync bucket",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, _ := git.PlainOpen(remote)
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName("main"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.Hash().String() != hash {
+		t.Fatalf("branch is at %s, expected %s", ref.Hash(), hash)
+	}
+
+	commit, _ := repo.CommitObject(ref.Hash())
+	if commit.Message != "Sync bucket" || commit.Author.Name != "Jane" || commit.Author.Email != "jane@example.com" {
+		t.Fatalf("unexpected commit %q by %s <%s>", commit.Message, commit.Author.Name, commit.Author.Email)
+	}
+	if commit.NumParents() != 1 {
+		t.Fatalf("expected commit on top of the branch, has %d parents", commit.NumParents())
+	}
+
+	tree, _ := commit.Tree()
+	var paths []string
+	tree.Files().ForEach(func(f *object.File) error {
+		paths = append(paths, f.Name)
+		return nil
+	})
+	sort.Strings(paths)
+
+	expected := []string{"README.md", "src/keep.ts", "src/new/file.ts"}
+	if len(paths) != len(expected) {
+		t.Fatalf("unexpected files %v", paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Fatalf("unexpected files %v", paths)
+		}
+	}
+
+	_, err = Export(context.Background(), Remote{URL: remote}, files, ExportOptions{Branch: "main", Path: "src"})
+	if !errors.Is(err, ErrNoChanges) {
+		t.Fatalf("expected no changes, got %v", err)
+	}
+}
+
+func TestExport_NewBranch(t *testing.T) {
+	remote, base := newBareRemote(t, map[string]string{"README.md": "readme"})
+
+	hash, err := Export(context.Background(), Remote{URL: remote}, []File{{Path: "a.ts", Content: []byte("a")}}, ExportOptions{
+		Branch:  "feature",
+		Path:    "app",
+		Message: "Add app",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, _ := git.PlainOpen(remote)
+	commit, err := repo.CommitObject(plumbing.NewHash(hash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit.NumParents() != 1 || commit.ParentHashes[0].String() != base {
+		t.Fatalf("expected branch to start at %s", base)
+	}
+
+	if _, err := commit.File("app/a.ts"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := commit.File("README.md"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExport_EmptyRemote(t *testing.T) {
+	remote := filepath.Join(t.TempDir(), "empty.git")
+	if _, err := git.PlainInit(remote, true); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := Export(context.Background(), Remote{URL: remote}, []File{{Path: "a.ts", Content: []byte("a")}}, ExportOptions{
+		Branch: "main",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, _ := git.PlainOpen(remote)
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName("main"), true)
+	if err != nil || ref.Hash().String() != hash {
+		t.Fatalf("branch not pushed: %v", err)
+	}
+}
@@ -13,7 +13,7 @@ import (
 	zipImporter "github.com/metorial/metorial/services/code-bucket/pkg/zip-importer"
 )
 
-func DownloadRepo(owner, repo, repoPath, ref, token string) (*zipImporter.ZipFileIterator, error) {
+func DownloadRepo(owner, repo, repoPath, ref, token string, limits zipImporter.Limits) (*zipImporter.ZipFileIterator, error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/zipball/%s", owner, repo, ref)
 
 	headers := map[string]string{
@@ -24,7 +24,7 @@ func DownloadRepo(owner, repo, repoPath, ref, token string) (*zipImporter.ZipFil
 		headers["Authorization"] = fmt.Sprintf("Bearer %s", token)
 	}
 
-	return zipImporter.DownloadZip(url, repoPath, headers)
+	return zipImporter.DownloadZip(url, repoPath, headers, limits)
 }
 
 type FileToUpload struct {
@@ -12,7 +12,10 @@ import (
 type ZipFileIterator struct {
 	filePaths []string
 	current   int
-	tempDir   string
+
+	// Paths are returned relative to rootDir, tempDir is removed on Close
+	rootDir string
+	tempDir string
 
 	mutex sync.Mutex
 }
@@ -39,7 +42,7 @@ func (it *ZipFileIterator) Next() (*ZipFileItem, bool) {
 
 	return &ZipFileItem{
 		Content: content,
-		Path:    util.MustOrFallback(filePath)(filepath.Rel(it.tempDir, filePath)),
+		Path:    util.MustOrFallback(filePath)(filepath.Rel(it.rootDir, filePath)),
 	}, true
 }
 
@@ -57,3 +60,14 @@ func (it *ZipFileIterator) Close() error {
 
 	return nil
 }
+
+// NewFileIterator iterates over files below rootDir, e.g. a directory of
+// a checkout in tempDir. Paths are returned relative to rootDir, tempDir
+// is removed on Close.
+func NewFileIterator(tempDir, rootDir string, filePaths []string) *ZipFileIterator {
+	return &ZipFileIterator{
+		filePaths: filePaths,
+		rootDir:   rootDir,
+		tempDir:   tempDir,
+	}
+}
@@ -2,79 +2,109 @@ package zipImporter
 
 import (
 	"archive/zip"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	ssrfProtection "github.com/metorial/metorial/modules/ssrf-protection"
 )
 
-func DownloadZip(url, path string, headers map[string]string) (*ZipFileIterator, error) {
+var ErrLimitExceeded = errors.New("archive exceeds import limits")
+
+// Limits restrict what an archive may contain. Zero values mean no limit.
+type Limits struct {
+	MaxArchiveBytes int64
+	MaxFiles        int64
+	MaxFileBytes    int64
+	MaxTotalBytes   int64
+}
+
+func DownloadZip(url, path string, headers map[string]string, limits Limits) (*ZipFileIterator, error) {
 	tmpDir, err := os.MkdirTemp("", "gh-zip-*")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create temp dir: %w", err)
 	}
 
 	zipPath := filepath.Join(tmpDir, "repo.zip")
-	if err := downloadFile(url, zipPath, headers); err != nil {
+	if err := downloadFile(url, zipPath, headers, limits.MaxArchiveBytes); err != nil {
+		os.RemoveAll(tmpDir)
 		return nil, fmt.Errorf("failed to download zip: %w", err)
 	}
 
 	extractDir := filepath.Join(tmpDir, "unzipped")
-	if err := unzip(zipPath, extractDir); err != nil {
+	if err := unzip(zipPath, extractDir, limits); err != nil {
+		os.RemoveAll(tmpDir)
 		return nil, fmt.Errorf("failed to unzip archive: %w", err)
 	}
 
 	topDirs, err := os.ReadDir(extractDir)
 	if err != nil || len(topDirs) == 0 {
+		os.RemoveAll(tmpDir)
 		return nil, fmt.Errorf("unexpected archive structure")
 	}
 	repoRoot := filepath.Join(extractDir, topDirs[0].Name())
 
 	targetPath := filepath.Join(repoRoot, path)
+	if targetPath != repoRoot && !strings.HasPrefix(targetPath, repoRoot+string(os.PathSeparator)) {
+		os.RemoveAll(tmpDir)
+		return nil, fmt.Errorf("illegal path: %s", path)
+	}
+
 	var filePaths []string
 	err = filepath.Walk(targetPath, func(p string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-		if !info.IsDir() {
+		if info.Mode().IsRegular() {
 			filePaths = append(filePaths, p)
 		}
 		return nil
 	})
 	if err != nil {
+		os.RemoveAll(tmpDir)
 		return nil, fmt.Errorf("error walking subdirectory: %w", err)
 	}
 
-	return &ZipFileIterator{
-		filePaths: filePaths,
-		current:   0,
-		tempDir:   targetPath,
-	}, nil
+	return NewFileIterator(tmpDir, targetPath, filePaths), nil
 }
 
-func downloadFile(url, dest string, headers map[string]string) error {
-	req, _ := http.NewRequest("GET", url, nil)
+func downloadFile(url, dest string, headers map[string]string, maxBytes int64) error {
+	if err := ssrfProtection.ValidateURL(url); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
 	req.Header.Set("User-Agent", "Metorial CodeBucket (https://metorial.com)")
 
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	// The secure client validates every address it connects to,
+	// including redirect targets. Archives may take a while to download.
+	client := ssrfProtection.CreateSecureHTTPClient()
+	client.Timeout = 5 * time.Minute
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusFound {
+	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("bad status: %s", resp.Status)
 	}
 
-	if resp.StatusCode == http.StatusFound {
-		loc := resp.Header.Get("Location")
-		return downloadFile(loc, dest, headers)
+	if maxBytes > 0 && resp.ContentLength > maxBytes {
+		return fmt.Errorf("%w: archive is %d bytes, the limit is %d bytes", ErrLimitExceeded, resp.ContentLength, maxBytes)
 	}
 
 	out, err := os.Create(dest)
@@ -83,17 +113,33 @@ func downloadFile(url, dest string, headers map[string]string) error {
 	}
 	defer out.Close()
 
-	_, err = io.Copy(out, resp.Body)
-	return err
+	var body io.Reader = resp.Body
+	if maxBytes > 0 {
+		body = io.LimitReader(resp.Body, maxBytes+1)
+	}
+
+	written, err := io.Copy(out, body)
+	if err != nil {
+		return err
+	}
+	if maxBytes > 0 && written > maxBytes {
+		return fmt.Errorf("%w: archive is larger than %d bytes", ErrLimitExceeded, maxBytes)
+	}
+
+	return nil
 }
 
-func unzip(src, dest string) error {
+// unzip extracts an archive, enforcing the limits on the actual
+// decompressed sizes rather than the sizes the archive claims.
+func unzip(src, dest string, limits Limits) error {
 	r, err := zip.OpenReader(src)
 	if err != nil {
 		return err
 	}
 	defer r.Close()
 
+	var files, totalBytes int64
+
 	for _, f := range r.File {
 		fpath := filepath.Join(dest, f.Name)
 		if !strings.HasPrefix(fpath, filepath.Clean(dest)+string(os.PathSeparator)) {
@@ -108,26 +154,63 @@ func unzip(src, dest string) error {
 			continue
 		}
 
-		if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
-			return err
+		// Symlinks and other special files are skipped
+		if !f.Mode().IsRegular() {
+			continue
 		}
 
-		inFile, err := f.Open()
-		if err != nil {
-			return err
+		files++
+		if limits.MaxFiles > 0 && files > limits.MaxFiles {
+			return fmt.Errorf("%w: more than %d files", ErrLimitExceeded, limits.MaxFiles)
 		}
-		defer inFile.Close()
 
-		outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-		if err != nil {
+		if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
 			return err
 		}
-		defer outFile.Close()
 
-		_, err = io.Copy(outFile, inFile)
+		maxFileBytes := limits.MaxFileBytes
+		if limits.MaxTotalBytes > 0 && (maxFileBytes <= 0 || limits.MaxTotalBytes-totalBytes < maxFileBytes) {
+			maxFileBytes = limits.MaxTotalBytes - totalBytes
+		}
+
+		written, err := extractFile(f, fpath, maxFileBytes)
 		if err != nil {
 			return err
 		}
+		totalBytes += written
 	}
 	return nil
 }
+
+func extractFile(f *zip.File, fpath string, maxBytes int64) (int64, error) {
+	if maxBytes > 0 && f.UncompressedSize64 > uint64(maxBytes) {
+		return 0, fmt.Errorf("%w: %s is too large", ErrLimitExceeded, f.Name)
+	}
+
+	inFile, err := f.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer inFile.Close()
+
+	outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode().Perm()|0600)
+	if err != nil {
+		return 0, err
+	}
+	defer outFile.Close()
+
+	var reader io.Reader = inFile
+	if maxBytes > 0 {
+		reader = io.LimitReader(inFile, maxBytes+1)
+	}
+
+	written, err := io.Copy(outFile, reader)
+	if err != nil {
+		return 0, err
+	}
+	if maxBytes > 0 && written > maxBytes {
+		return 0, fmt.Errorf("%w: %s is too large", ErrLimitExceeded, f.Name)
+	}
+
+	return written, nil
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+)
+
+const (
+	managementTokenQueryParam = "metorial-deno-runner-token"
+	proxyTokenHeader          = "Metorial-Deployment-Token"
+	proxyTokenQueryParam      = "metorial-deployment-token"
+
+	maxProxyTokenExpiry = 30 * 24 * time.Hour
+
+	// proxyTokenAudience is the audience of proxy tokens, which tells
+	// them apart from management tokens signed with the same secret.
+	proxyTokenAudience = "deno-runner-proxy"
+)
+
+// Claims of tokens for the deno-runner. Management tokens carry the
+// tenant whose deployments they may manage, or are admin tokens with
+// access to all tenants. Proxy tokens have the proxy audience and carry
+// a deployment ID; they only grant access to that deployment's proxy
+// route. Both kinds must expire.
+type Claims struct {
+	TenantID     string `json:"tenant_id,omitempty"`
+	IsAdmin      bool   `json:"is_admin,omitempty"`
+	DeploymentID string `json:"deployment_id,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type authenticator struct {
+	jwtSecret []byte
+	disabled  bool
+}
+
+func (a *authenticator) parseToken(tokenString string, options ...jwt.ParserOption) (*Claims, error) {
+	options = append(options, jwt.WithExpirationRequired())
+
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return a.jwtSecret, nil
+	}, options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		return claims, nil
+	}
+
+	return nil, fmt.Errorf("invalid token")
+}
+
+func (a *authenticator) authenticateManagement(r *http.Request) (*Claims, error) {
+	if a.disabled {
+		return &Claims{IsAdmin: true}, nil
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	tokenString := r.URL.Query().Get(managementTokenQueryParam)
+
+	if authHeader != "" {
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			return nil, fmt.Errorf("missing or invalid authorization header")
+		}
+
+		tokenString = strings.TrimPrefix(authHeader, "Bearer ")
+	}
+
+	if tokenString == "" {
+		return nil, fmt.Errorf("missing authorization token")
+	}
+
+	claims, err := a.parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.DeploymentID != "" || slices.Contains(claims.Audience, proxyTokenAudience) {
+		return nil, fmt.Errorf("proxy tokens can't be used for management routes")
+	}
+	if claims.TenantID == "" && !claims.IsAdmin {
+		return nil, fmt.Errorf("token has no tenant")
+	}
+
+	return claims, nil
+}
+
+// authenticateProxy checks the access token of a deployment that
+// requires one. The token is removed from the request so it is not
+// passed on to the deployment.
+func (a *authenticator) authenticateProxy(r *http.Request, deploymentID string) error {
+	tokenString := r.Header.Get(proxyTokenHeader)
+	r.Header.Del(proxyTokenHeader)
+
+	query := r.URL.Query()
+	if queryToken := query.Get(proxyTokenQueryParam); queryToken != "" {
+		if tokenString == "" {
+			tokenString = queryToken
+		}
+		query.Del(proxyTokenQueryParam)
+		r.URL.RawQuery = query.Encode()
+	}
+
+	if a.disabled {
+		return nil
+	}
+
+	if tokenString == "" {
+		return fmt.Errorf("missing deployment token")
+	}
+
+	claims, err := a.parseToken(tokenString, jwt.WithAudience(proxyTokenAudience))
+	if err != nil {
+		return err
+	}
+
+	if claims.DeploymentID != deploymentID {
+		return fmt.Errorf("token is not valid for this deployment")
+	}
+
+	return nil
+}
+
+func (a *authenticator) createProxyToken(deploymentID string, expiresIn time.Duration) (string, time.Time, error) {
+	expiresAt := time.Now().Add(expiresIn)
+
+	claims := &Claims{
+		DeploymentID: deploymentID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{proxyTokenAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString(a.jwtSecret)
+	return tokenString, expiresAt, err
+}
+
+// canAccess reports whether the claims grant access to a deployment.
+func (c *Claims) canAccess(meta *DeploymentMeta) bool {
+	return c.IsAdmin || (c.TenantID != "" && c.TenantID == meta.TenantID)
+}
+
+// management wraps a handler of a management route with authentication.
+func (a *authenticator) management(handler func(w http.ResponseWriter, r *http.Request, claims *Claims)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, err := a.authenticateManagement(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		handler(w, r, claims)
+	}
+}
+
+// authorize returns errDeploymentNotFound for deployments of other
+// tenants, so their existence is not revealed.
+func (dm *DeployManager) authorize(claims *Claims, deploymentID string) error {
+	meta, err := dm.readMeta(deploymentID)
+	if err != nil {
+		return err
+	}
+
+	if !claims.canAccess(meta) {
+		return errDeploymentNotFound
+	}
+
+	return nil
+}
+
+func (dm *DeployManager) proxyTokenHandler(auth *authenticator) http.HandlerFunc {
+	return auth.management(func(w http.ResponseWriter, r *http.Request, claims *Claims) {
+		deploymentID := mux.Vars(r)["deploymentId"]
+		if err := dm.authorize(claims, deploymentID); err != nil {
+			writeDeploymentError(w, err)
+			return
+		}
+
+		var req struct {
+			ExpiresInSeconds int64 `json:"expiresInSeconds"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		expiresIn := time.Duration(req.ExpiresInSeconds) * time.Second
+		if expiresIn <= 0 || expiresIn > maxProxyTokenExpiry {
+			expiresIn = maxProxyTokenExpiry
+		}
+
+		token, expiresAt, err := auth.createProxyToken(deploymentID, expiresIn)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"token":     token,
+			"expiresAt": expiresAt,
+		})
+	})
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+var testAuthSecret = []byte("runner-secret")
+
+func signTestToken(t *testing.T, claims *Claims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testAuthSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func expiringClaims(claims Claims) *Claims {
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Hour))
+	return &claims
+}
+
+func TestAuthenticateManagement(t *testing.T) {
+	auth := &authenticator{jwtSecret: testAuthSecret}
+
+	tenant := signTestToken(t, expiringClaims(Claims{TenantID: "a"}))
+	proxy, _, err := auth.createProxyToken(uuid.New().String(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxyAudience := signTestToken(t, expiringClaims(Claims{TenantID: "a", RegisteredClaims: jwt.RegisteredClaims{
+		Audience: jwt.ClaimStrings{proxyTokenAudience},
+	}}))
+
+	tests := []struct {
+		name   string
+		header string
+		query  string
+		tenant string
+		valid  bool
+	}{
+		{"header", "Bearer " + tenant, "", "a", true},
+		{"query", "", tenant, "a", true},
+		{"admin", "Bearer " + signTestToken(t, expiringClaims(Claims{IsAdmin: true})), "", "", true},
+		{"missing", "", "", "", false},
+		{"not bearer", "Basic " + tenant, "", "", false},
+		{"no tenant", "Bearer " + signTestToken(t, expiringClaims(Claims{})), "", "", false},
+		{"no expiry", "Bearer " + signTestToken(t, &Claims{TenantID: "a"}), "", "", false},
+		{"expired", "Bearer " + signTestToken(t, &Claims{TenantID: "a", RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+		}}), "", "", false},
+		{"wrong secret", "Bearer " + func() string {
+			token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, expiringClaims(Claims{TenantID: "a"})).SignedString([]byte("other"))
+			return token
+		}(), "", "", false},
+		{"proxy token", "Bearer " + proxy, "", "", false},
+		{"proxy audience", "Bearer " + proxyAudience, "", "", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/deployments", nil)
+		if tt.header != "" {
+			r.Header.Set("Authorization", tt.header)
+		}
+		if tt.query != "" {
+			r.URL.RawQuery = managementTokenQueryParam + "=" + tt.query
+		}
+
+		claims, err := auth.authenticateManagement(r)
+		if tt.valid && (err != nil || claims.TenantID != tt.tenant) {
+			t.Errorf("%s: authenticateManagement() = %+v, %v, want tenant %q", tt.name, claims, err, tt.tenant)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+
+	disabled := &authenticator{disabled: true}
+	if claims, err := disabled.authenticateManagement(httptest.NewRequest(http.MethodGet, "/", nil)); err != nil || !claims.IsAdmin {
+		t.Errorf("authenticateManagement() with auth disabled = %+v, %v", claims, err)
+	}
+}
+
+func TestAuthenticateProxy(t *testing.T) {
+	auth := &authenticator{jwtSecret: testAuthSecret}
+	deploymentID := uuid.New().String()
+
+	valid, _, err := auth.createProxyToken(deploymentID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherDeployment, _, err := auth.createProxyToken(uuid.New().String(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		query  string
+		valid  bool
+	}{
+		{"header", valid, "", true},
+		{"query", "", valid, true},
+		{"header before query", valid, otherDeployment, true},
+		{"missing", "", "", false},
+		{"other deployment", otherDeployment, "", false},
+		{"management token", signTestToken(t, expiringClaims(Claims{TenantID: "a"})), "", false},
+		{"management token with deployment", signTestToken(t, expiringClaims(Claims{DeploymentID: deploymentID})), "", false},
+		{"no expiry", signTestToken(t, &Claims{DeploymentID: deploymentID, RegisteredClaims: jwt.RegisteredClaims{
+			Audience: jwt.ClaimStrings{proxyTokenAudience},
+		}}), "", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/proxy/"+deploymentID+"/path?a=1", nil)
+		if tt.header != "" {
+			r.Header.Set(proxyTokenHeader, tt.header)
+		}
+		if tt.query != "" {
+			r.URL.RawQuery += "&" + proxyTokenQueryParam + "=" + tt.query
+		}
+
+		err := auth.authenticateProxy(r, deploymentID)
+		if tt.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+
+		// Tokens are never passed on to the deployment
+		if r.Header.Get(proxyTokenHeader) != "" || strings.Contains(r.URL.RawQuery, proxyTokenQueryParam) {
+			t.Errorf("%s: token was not removed, header %q, query %q", tt.name, r.Header.Get(proxyTokenHeader), r.URL.RawQuery)
+		}
+		if r.URL.Query().Get("a") != "1" {
+			t.Errorf("%s: other query parameters were removed: %q", tt.name, r.URL.RawQuery)
+		}
+	}
+
+	disabled := &authenticator{disabled: true}
+	r := httptest.NewRequest(http.MethodGet, "/proxy/"+deploymentID+"?"+proxyTokenQueryParam+"=x", nil)
+	r.Header.Set(proxyTokenHeader, "x")
+	if err := disabled.authenticateProxy(r, deploymentID); err != nil {
+		t.Errorf("authenticateProxy() with auth disabled = %v", err)
+	}
+	if r.Header.Get(proxyTokenHeader) != "" || r.URL.RawQuery != "" {
+		t.Errorf("expected tokens to be removed with auth disabled")
+	}
+}
+
+func TestCanAccess(t *testing.T) {
+	meta := &DeploymentMeta{TenantID: "a"}
+
+	tests := []struct {
+		name   string
+		claims Claims
+		want   bool
+	}{
+		{"same tenant", Claims{TenantID: "a"}, true},
+		{"other tenant", Claims{TenantID: "b"}, false},
+		{"admin", Claims{IsAdmin: true}, true},
+		{"no tenant", Claims{}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.claims.canAccess(meta); got != tt.want {
+			t.Errorf("%s: canAccess() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if (&Claims{}).canAccess(&DeploymentMeta{}) {
+		t.Errorf("expected tokens without tenant not to access deployments without tenant")
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	dm := newTestDeployManager(t, DeployManagerOptions{})
+	deploymentID := uuid.New().String()
+	if err := dm.writeMeta(&DeploymentMeta{ID: deploymentID, TenantID: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		claims       Claims
+		deploymentID string
+		want         error
+	}{
+		{"same tenant", Claims{TenantID: "a"}, deploymentID, nil},
+		{"admin", Claims{IsAdmin: true}, deploymentID, nil},
+		{"other tenant", Claims{TenantID: "b"}, deploymentID, errDeploymentNotFound},
+		{"missing deployment", Claims{TenantID: "a"}, uuid.New().String(), errDeploymentNotFound},
+	}
+	for _, tt := range tests {
+		if err := dm.authorize(&tt.claims, tt.deploymentID); !errors.Is(err, tt.want) {
+			t.Errorf("%s: authorize() = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	// Deployments of other tenants look like missing ones
+	auth := &authenticator{jwtSecret: testAuthSecret}
+	handler := dm.proxyTokenHandler(auth)
+
+	for tenantID, want := range map[string]int{"a": http.StatusOK, "b": http.StatusNotFound} {
+		r := httptest.NewRequest(http.MethodPost, "/deployments/"+deploymentID+"/token", nil)
+		r.Header.Set("Authorization", "Bearer "+signTestToken(t, expiringClaims(Claims{TenantID: tenantID})))
+		r = mux.SetURLVars(r, map[string]string{"deploymentId": deploymentID})
+
+		recorder := httptest.NewRecorder()
+		handler(recorder, r)
+
+		if recorder.Code != want {
+			t.Errorf("tenant %s: status %d, want %d", tenantID, recorder.Code, want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cgroupManager places deployment processes into their own cgroup v2
+// group below root to limit their CPU and memory.
+type cgroupManager struct {
+	root string
+}
+
+// newCgroupManager returns nil if cgroup v2 is not available or the root
+// group can't be set up, in which case deployments run without cgroup
+// limits.
+func newCgroupManager(root string) *cgroupManager {
+	if root == "" {
+		return nil
+	}
+
+	if _, err := os.Stat("/sys/fs/cgroup/cgroup.controllers"); err != nil {
+		log.Printf("cgroup v2 not available, running deployments without cgroup limits")
+		return nil
+	}
+
+	if err := os.MkdirAll(root, 0755); err != nil {
+		log.Printf("Failed to create cgroup %s, running deployments without cgroup limits: %v", root, err)
+		return nil
+	}
+
+	// Controllers have to be enabled in the parent before they can be
+	// used by our group, and in our group before they can be used by the
+	// deployment groups.
+	os.WriteFile(filepath.Join(filepath.Dir(root), "cgroup.subtree_control"), []byte("+cpu +memory"), 0644)
+	if err := os.WriteFile(filepath.Join(root, "cgroup.subtree_control"), []byte("+cpu +memory"), 0644); err != nil {
+		log.Printf("Failed to enable cgroup controllers in %s, running deployments without cgroup limits: %v", root, err)
+		return nil
+	}
+
+	log.Printf("Using cgroup %s for deployment limits", root)
+
+	return &cgroupManager{root: root}
+}
+
+func (c *cgroupManager) path(deploymentID string) string {
+	return filepath.Join(c.root, deploymentID)
+}
+
+// Prepare creates the deployment's group and sets its limits. It returns
+// the group directory, to start the process in with cgroupProcAttr, and
+// the number of OOM kills recorded for the group so far, to tell new ones
+// apart later.
+func (c *cgroupManager) Prepare(deploymentID string, limits DeploymentLimits) (*os.File, int, error) {
+	dir := c.path(deploymentID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, 0, fmt.Errorf("failed to create cgroup: %w", err)
+	}
+
+	memoryMax := "max"
+	if limits.MemoryMB > 0 {
+		memoryMax = strconv.FormatInt(int64(limits.MemoryMB)*1024*1024, 10)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "memory.max"), []byte(memoryMax), 0644); err != nil {
+		return nil, 0, fmt.Errorf("failed to set memory limit: %w", err)
+	}
+	os.WriteFile(filepath.Join(dir, "memory.swap.max"), []byte("0"), 0644)
+
+	if err := os.WriteFile(filepath.Join(dir, "cpu.max"), []byte(cpuMax(limits)), 0644); err != nil {
+		return nil, 0, fmt.Errorf("failed to set cpu limit: %w", err)
+	}
+
+	group, err := os.Open(dir)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open cgroup: %w", err)
+	}
+
+	return group, c.OOMKills(deploymentID), nil
+}
+
+func cpuMax(limits DeploymentLimits) string {
+	const cpuPeriod = 100000
+	if limits.CPUPercent <= 0 {
+		return fmt.Sprintf("max %d", cpuPeriod)
+	}
+
+	return fmt.Sprintf("%d %d", limits.CPUPercent*cpuPeriod/100, cpuPeriod)
+}
+
+// OOMKills returns the number of processes in the deployment's group
+// killed for exceeding the memory limit.
+func (c *cgroupManager) OOMKills(deploymentID string) int {
+	return readCgroupStat(filepath.Join(c.path(deploymentID), "memory.events"), "oom_kill")
+}
+
+// ThrottledPeriods returns how often the deployment's group hit its CPU
+// limit.
+func (c *cgroupManager) ThrottledPeriods(deploymentID string) int {
+	return readCgroupStat(filepath.Join(c.path(deploymentID), "cpu.stat"), "nr_throttled")
+}
+
+// Remove deletes the deployment's group. It fails while processes are
+// still in it.
+func (c *cgroupManager) Remove(deploymentID string) {
+	os.Remove(c.path(deploymentID))
+}
+
+func readCgroupStat(path, key string) int {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == key {
+			value, _ := strconv.Atoi(fields[1])
+			return value
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// cgroupProcAttr makes a process start inside the group, see
+// cgroupManager.Prepare.
+func cgroupProcAttr(group *os.File) *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		UseCgroupFD: true,
+		CgroupFD:    int(group.Fd()),
+	}
+}
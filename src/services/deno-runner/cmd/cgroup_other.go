@@ -0,0 +1,14 @@
+//go:build !linux
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// cgroupProcAttr is never used outside of Linux, newCgroupManager doesn't
+// find cgroup v2 there.
+func cgroupProcAttr(group *os.File) *syscall.SysProcAttr {
+	return nil
+}
@@ -0,0 +1,626 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	stagingDirPrefix  = ".staging-"
+	previousDirPrefix = ".previous-"
+
+	// Metadata is kept outside of the deployment directories, which the
+	// deployments can read, and files written at runtime in a separate
+	// data directory.
+	metaDirName = ".meta"
+	dataDirName = ".data"
+)
+
+var (
+	errDeploymentNotFound = errors.New("deployment not found")
+	errUpdateRolledBack   = errors.New("update failed and was rolled back")
+)
+
+type DeploymentStatus struct {
+	ID           string     `json:"id"`
+	Status       string     `json:"status"`
+	Port         int        `json:"port"`
+	RestartCount int        `json:"restartCount"`
+	EntryPoint   string     `json:"entryPoint"`
+	TenantID     string     `json:"tenantId,omitempty"`
+	OwnerID      string     `json:"ownerId,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	StartedAt    *time.Time `json:"startedAt,omitempty"`
+	ReadyAt      *time.Time `json:"readyAt,omitempty"`
+	ColdStartMs  *int64     `json:"coldStartMs,omitempty"`
+	LastActivity *time.Time `json:"lastActivity,omitempty"`
+	Error        string     `json:"error,omitempty"`
+
+	Limits         DeploymentLimits `json:"limits"`
+	DiskUsageBytes int64            `json:"diskUsageBytes,omitempty"`
+	Violations     []LimitViolation `json:"violations,omitempty"`
+
+	RequireProxyToken bool `json:"requireProxyToken,omitempty"`
+
+	Source *DeploymentSourceStatus `json:"source,omitempty"`
+}
+
+// deploymentLocks locks deployments one by one, so a slow start or update
+// of one deployment doesn't hold up the others.
+type deploymentLocks struct {
+	mu    sync.Mutex
+	locks map[string]*deploymentLock
+}
+
+type deploymentLock struct {
+	// ch holds a value while the deployment is locked, so waiting for it
+	// can be given up.
+	ch   chan struct{}
+	refs int
+}
+
+func newDeploymentLocks() *deploymentLocks {
+	return &deploymentLocks{
+		locks: make(map[string]*deploymentLock),
+	}
+}
+
+func (l *deploymentLocks) acquireRef(deploymentID string) *deploymentLock {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lock, exists := l.locks[deploymentID]
+	if !exists {
+		lock = &deploymentLock{ch: make(chan struct{}, 1)}
+		l.locks[deploymentID] = lock
+	}
+	lock.refs++
+
+	return lock
+}
+
+func (l *deploymentLocks) releaseRef(deploymentID string, lock *deploymentLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lock.refs--
+	if lock.refs == 0 {
+		delete(l.locks, deploymentID)
+	}
+}
+
+// Lock locks a deployment. It gives up with errDeploymentStarting once
+// timeout fires, which may be nil to wait until ctx is done.
+func (l *deploymentLocks) Lock(ctx context.Context, deploymentID string, timeout <-chan time.Time) error {
+	lock := l.acquireRef(deploymentID)
+
+	select {
+	case lock.ch <- struct{}{}:
+		return nil
+	case <-timeout:
+		l.releaseRef(deploymentID, lock)
+		return errDeploymentStarting
+	case <-ctx.Done():
+		l.releaseRef(deploymentID, lock)
+		return ctx.Err()
+	}
+}
+
+func (l *deploymentLocks) Unlock(deploymentID string) {
+	l.mu.Lock()
+	lock, exists := l.locks[deploymentID]
+	l.mu.Unlock()
+
+	if !exists {
+		panic("unlock of unlocked deployment")
+	}
+
+	<-lock.ch
+	l.releaseRef(deploymentID, lock)
+}
+
+func isValidDeploymentID(deploymentID string) bool {
+	_, err := uuid.Parse(deploymentID)
+	return err == nil
+}
+
+func (dm *DeployManager) metaPath(name string) string {
+	return filepath.Join(dm.baseDir, metaDirName, name+".json")
+}
+
+func (dm *DeployManager) dataDir(deploymentID string) string {
+	return filepath.Join(dm.baseDir, dataDirName, deploymentID)
+}
+
+func (dm *DeployManager) deploymentExists(deploymentID string) bool {
+	if !isValidDeploymentID(deploymentID) {
+		return false
+	}
+
+	_, err := os.Stat(dm.metaPath(deploymentID))
+	return err == nil
+}
+
+func (dm *DeployManager) readMeta(deploymentID string) (*DeploymentMeta, error) {
+	if !isValidDeploymentID(deploymentID) {
+		return nil, errDeploymentNotFound
+	}
+
+	data, err := os.ReadFile(dm.metaPath(deploymentID))
+	if os.IsNotExist(err) {
+		return nil, errDeploymentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deployment metadata: %w", err)
+	}
+
+	var meta DeploymentMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("invalid deployment metadata")
+	}
+
+	return &meta, nil
+}
+
+// writeMeta stores the metadata of a deployment, replacing the previous
+// version atomically.
+func (dm *DeployManager) writeMeta(meta *DeploymentMeta) error {
+	return dm.writeMetaFile(meta.ID, meta)
+}
+
+func (dm *DeployManager) writeMetaFile(name string, meta *DeploymentMeta) error {
+	if err := os.MkdirAll(filepath.Join(dm.baseDir, metaDirName), 0700); err != nil {
+		return fmt.Errorf("failed to create metadata directory: %w", err)
+	}
+
+	metaData, _ := json.MarshalIndent(meta, "", "  ")
+
+	tmpPath := dm.metaPath(name) + ".tmp"
+	if err := os.WriteFile(tmpPath, metaData, 0600); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+	if err := os.Rename(tmpPath, dm.metaPath(name)); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+
+	return nil
+}
+
+// migrateMeta moves the metadata of a deployment created by an earlier
+// version out of its deployment directory.
+func (dm *DeployManager) migrateMeta(deploymentID string) {
+	if !isValidDeploymentID(deploymentID) {
+		return
+	}
+
+	legacyPath := filepath.Join(dm.baseDir, deploymentID, ".meta.json")
+	if _, err := os.Stat(legacyPath); err != nil {
+		return
+	}
+
+	if !dm.deploymentExists(deploymentID) {
+		if err := os.MkdirAll(filepath.Join(dm.baseDir, metaDirName), 0700); err != nil {
+			log.Printf("Failed to migrate metadata of deployment %s: %v", deploymentID, err)
+			return
+		}
+		if err := os.Rename(legacyPath, dm.metaPath(deploymentID)); err != nil {
+			log.Printf("Failed to migrate metadata of deployment %s: %v", deploymentID, err)
+			return
+		}
+
+		log.Printf("Deployment %s: moved metadata out of the deployment directory", deploymentID)
+		return
+	}
+
+	os.Remove(legacyPath)
+}
+
+func (dm *DeployManager) GetDeploymentStatus(deploymentID string) (*DeploymentStatus, error) {
+	meta, err := dm.readMeta(deploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dm.statusFromMeta(meta), nil
+}
+
+func (dm *DeployManager) statusFromMeta(meta *DeploymentMeta) *DeploymentStatus {
+	status := &DeploymentStatus{
+		ID:         meta.ID,
+		Status:     "stopped",
+		EntryPoint: meta.EntryPoint,
+		CreatedAt:  meta.CreatedAt,
+		UpdatedAt:  meta.UpdatedAt,
+		Limits:     dm.effectiveLimits(meta.Limits),
+		TenantID:   meta.TenantID,
+		OwnerID:    meta.OwnerID,
+
+		RequireProxyToken: meta.RequireProxyToken,
+	}
+
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	status.DiskUsageBytes = dm.diskUsage[meta.ID]
+	status.Violations = append([]LimitViolation(nil), dm.violations[meta.ID]...)
+	status.Source = dm.sourceStatus(meta)
+
+	if proc, ok := dm.runningProcs[meta.ID]; ok {
+		status.Status = "starting"
+		status.Port = proc.port
+		status.RestartCount = proc.restartCount
+
+		startedAt := proc.startedAt
+		status.StartedAt = &startedAt
+
+		if !proc.readyAt.IsZero() {
+			status.Status = "running"
+
+			readyAt := proc.readyAt
+			coldStartMs := proc.coldStart.Milliseconds()
+			status.ReadyAt = &readyAt
+			status.ColdStartMs = &coldStartMs
+		}
+
+		if !proc.lastActivity.IsZero() {
+			lastActivity := proc.lastActivity
+			status.LastActivity = &lastActivity
+		}
+	} else if reason, ok := dm.failed[meta.ID]; ok {
+		status.Status = "failed"
+		status.Error = reason
+	}
+
+	return status
+}
+
+// ListDeployments returns the deployments the claims grant access to.
+func (dm *DeployManager) ListDeployments(claims *Claims) ([]*DeploymentStatus, error) {
+	entries, err := os.ReadDir(dm.baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deployments directory: %w", err)
+	}
+
+	res := make([]*DeploymentStatus, 0)
+	for _, entry := range entries {
+		if !entry.IsDir() || !isValidDeploymentID(entry.Name()) {
+			continue
+		}
+
+		meta, err := dm.readMeta(entry.Name())
+		if err != nil || !claims.canAccess(meta) {
+			continue
+		}
+
+		res = append(res, dm.statusFromMeta(meta))
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].CreatedAt.Before(res[j].CreatedAt)
+	})
+
+	return res, nil
+}
+
+// stopProcess kills the process of a deployment without triggering a
+// restart and waits for it to exit. It reports whether it was running.
+func (dm *DeployManager) stopProcess(deploymentID string) bool {
+	dm.mu.Lock()
+	proc, exists := dm.runningProcs[deploymentID]
+	if !exists {
+		dm.mu.Unlock()
+		return false
+	}
+
+	delete(dm.runningProcs, deploymentID)
+
+	if proc.stopTimer != nil {
+		proc.stopTimer.Stop()
+	}
+	if proc.cancel != nil {
+		proc.cancel()
+	}
+	if proc.cmd != nil && proc.cmd.Process != nil {
+		proc.cmd.Process.Kill()
+	}
+	exited := proc.exited
+	dm.mu.Unlock()
+
+	if exited != nil {
+		select {
+		case <-exited:
+		case <-time.After(5 * time.Second):
+			log.Printf("Deployment %s: process did not exit in time", deploymentID)
+		}
+	}
+
+	return true
+}
+
+func (dm *DeployManager) DeleteDeployment(deploymentID string) error {
+	if err := dm.locks.Lock(context.Background(), deploymentID, nil); err != nil {
+		return err
+	}
+	defer dm.locks.Unlock(deploymentID)
+
+	if !dm.deploymentExists(deploymentID) {
+		return errDeploymentNotFound
+	}
+
+	dm.stopProcess(deploymentID)
+
+	if err := os.Remove(dm.metaPath(deploymentID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove deployment metadata: %w", err)
+	}
+
+	if err := os.RemoveAll(filepath.Join(dm.baseDir, deploymentID)); err != nil {
+		return fmt.Errorf("failed to remove deployment directory: %w", err)
+	}
+	if err := os.RemoveAll(dm.dataDir(deploymentID)); err != nil {
+		return fmt.Errorf("failed to remove deployment data directory: %w", err)
+	}
+
+	dm.mu.Lock()
+	delete(dm.failed, deploymentID)
+	delete(dm.violations, deploymentID)
+	delete(dm.diskUsage, deploymentID)
+	delete(dm.throttled, deploymentID)
+	dm.mu.Unlock()
+
+	if dm.cgroups != nil {
+		dm.cgroups.Remove(deploymentID)
+	}
+
+	dm.removeLogStore(deploymentID)
+
+	log.Printf("Deployment %s: deleted", deploymentID)
+
+	return nil
+}
+
+// UpdateDeployment replaces the assets and configuration of a deployment.
+func (dm *DeployManager) UpdateDeployment(ctx context.Context, deploymentID string, req DeploymentRequest) (*DeploymentStatus, error) {
+	if req.Source != nil {
+		if err := dm.validateSource(req.Source); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := dm.locks.Lock(ctx, deploymentID, nil); err != nil {
+		return nil, err
+	}
+	defer dm.locks.Unlock(deploymentID)
+
+	previousMeta, err := dm.readMeta(deploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := *previousMeta
+	meta.EntryPoint = req.EntryPointURL
+	meta.EnvVars = req.EnvVars
+	meta.Permissions = req.Permissions
+	meta.HealthPath = req.HealthPath
+	meta.Limits = req.Limits
+	meta.RequireProxyToken = req.RequireProxyToken
+	meta.Source = req.Source
+	meta.SourceSnapshot = nil
+
+	assets, err := dm.resolveAssets(ctx, &meta, req.Assets)
+	if err != nil {
+		return nil, err
+	}
+
+	return dm.replaceDeploymentLocked(deploymentID, previousMeta, meta, assets)
+}
+
+// replaceDeploymentLocked swaps in a new version of a deployment, whose
+// lock the caller holds. The new version is written next to the current
+// one and swapped in by renaming the directories. If the new version fails
+// to start, the previous directory and metadata are restored.
+func (dm *DeployManager) replaceDeploymentLocked(deploymentID string, previousMeta *DeploymentMeta, meta DeploymentMeta, assets map[string]DeploymentFile) (*DeploymentStatus, error) {
+
+	deployDir := filepath.Join(dm.baseDir, deploymentID)
+	stagingDir := filepath.Join(dm.baseDir, stagingDirPrefix+deploymentID)
+	previousDir := filepath.Join(dm.baseDir, previousDirPrefix+deploymentID)
+
+	meta.UpdatedAt = time.Now()
+
+	os.RemoveAll(stagingDir)
+	if err := writeDeploymentDir(stagingDir, assets); err != nil {
+		os.RemoveAll(stagingDir)
+		return nil, err
+	}
+
+	// The previous metadata is kept until the update is done, so an
+	// interrupted update can be rolled back on startup
+	previousMetaName := previousDirPrefix + deploymentID
+	if err := dm.writeMetaFile(previousMetaName, previousMeta); err != nil {
+		os.RemoveAll(stagingDir)
+		return nil, err
+	}
+	discardPreviousMeta := func() {
+		os.Remove(dm.metaPath(previousMetaName))
+	}
+
+	logs := dm.getLogStore(deploymentID)
+	logs.Append(LogStreamSystem, "updating deployment")
+
+	wasRunning := dm.stopProcess(deploymentID)
+
+	restorePrevious := func() {
+		if wasRunning {
+			go dm.startDeployment(deploymentID, previousMeta, deployDir)
+		}
+	}
+
+	os.RemoveAll(previousDir)
+	if err := os.Rename(deployDir, previousDir); err != nil {
+		os.RemoveAll(stagingDir)
+		discardPreviousMeta()
+		restorePrevious()
+		return nil, fmt.Errorf("failed to swap deployment directory: %w", err)
+	}
+
+	if err := os.Rename(stagingDir, deployDir); err != nil {
+		os.Rename(previousDir, deployDir)
+		os.RemoveAll(stagingDir)
+		discardPreviousMeta()
+		restorePrevious()
+		return nil, fmt.Errorf("failed to swap deployment directory: %w", err)
+	}
+
+	rollBack := func() error {
+		os.RemoveAll(deployDir)
+		if err := os.Rename(previousDir, deployDir); err != nil {
+			return fmt.Errorf("failed to restore previous deployment: %w", err)
+		}
+		if err := dm.writeMeta(previousMeta); err != nil {
+			return fmt.Errorf("failed to restore previous deployment: %w", err)
+		}
+
+		discardPreviousMeta()
+		restorePrevious()
+
+		return nil
+	}
+
+	if err := dm.writeMeta(&meta); err != nil {
+		if rollBackErr := rollBack(); rollBackErr != nil {
+			return nil, rollBackErr
+		}
+		return nil, err
+	}
+
+	dm.mu.Lock()
+	delete(dm.failed, deploymentID)
+	dm.mu.Unlock()
+
+	if err := dm.verifyStart(deploymentID, &meta, deployDir); err != nil {
+		log.Printf("Deployment %s: update failed, rolling back: %v", deploymentID, err)
+		logs.Append(LogStreamSystem, fmt.Sprintf("update failed, rolling back: %v", err))
+
+		dm.stopProcess(deploymentID)
+
+		if rollBackErr := rollBack(); rollBackErr != nil {
+			return nil, rollBackErr
+		}
+
+		return nil, fmt.Errorf("%w: %v", errUpdateRolledBack, err)
+	}
+
+	discardPreviousMeta()
+	os.RemoveAll(previousDir)
+
+	log.Printf("Deployment %s: updated", deploymentID)
+	logs.Append(LogStreamSystem, "deployment updated")
+
+	return dm.statusFromMeta(&meta), nil
+}
+
+// verifyStart starts a deployment and waits for it to become ready, which
+// the readiness probe bounds by the readiness timeout. Only the lock of
+// this deployment is held meanwhile.
+func (dm *DeployManager) verifyStart(deploymentID string, meta *DeploymentMeta, deployDir string) error {
+	if err := dm.startDeployment(deploymentID, meta, deployDir); err != nil {
+		return err
+	}
+
+	dm.mu.RLock()
+	proc, ok := dm.runningProcs[deploymentID]
+	var ready, exited chan struct{}
+	if ok {
+		ready, exited = proc.ready, proc.exited
+	}
+	dm.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("process is not running")
+	}
+
+	select {
+	case <-ready:
+		return nil
+	case <-exited:
+		return fmt.Errorf("process exited during startup")
+	}
+}
+
+// cleanupSwapDir removes directories left behind by an update that was
+// interrupted, restoring the previous version if the update did not finish.
+// Updates only discard the previous metadata once they are done.
+func (dm *DeployManager) cleanupSwapDir(name string) {
+	dir := filepath.Join(dm.baseDir, name)
+
+	switch {
+	case strings.HasPrefix(name, stagingDirPrefix):
+		os.RemoveAll(dir)
+
+	case strings.HasPrefix(name, previousDirPrefix):
+		deployDir := filepath.Join(dm.baseDir, strings.TrimPrefix(name, previousDirPrefix))
+
+		_, deployErr := os.Stat(deployDir)
+		_, metaErr := os.Stat(dm.metaPath(name))
+
+		if os.IsNotExist(deployErr) || metaErr == nil {
+			log.Printf("Restoring interrupted update of deployment %s", filepath.Base(deployDir))
+			os.RemoveAll(deployDir)
+			os.Rename(dir, deployDir)
+		} else {
+			os.RemoveAll(dir)
+		}
+	}
+}
+
+// restoreInterruptedUpdates puts back the metadata of updates that were
+// interrupted, after cleanupSwapDir restored their directories.
+func (dm *DeployManager) restoreInterruptedUpdates() {
+	paths, err := filepath.Glob(filepath.Join(dm.baseDir, metaDirName, previousDirPrefix+"*.json"))
+	if err != nil {
+		return
+	}
+
+	for _, path := range paths {
+		deploymentID := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), previousDirPrefix), ".json")
+		if !isValidDeploymentID(deploymentID) {
+			continue
+		}
+
+		if err := os.Rename(path, dm.metaPath(deploymentID)); err != nil {
+			log.Printf("Failed to restore metadata of deployment %s: %v", deploymentID, err)
+		}
+	}
+}
+
+func writeDeploymentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errDeploymentNotFound):
+		http.Error(w, "Deployment not found", http.StatusNotFound)
+	case errors.Is(err, errDiskQuotaExceeded):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, errInvalidSource), errors.Is(err, errNoSource):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, errSourceNotConfigured):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	case errors.Is(err, errSourceFetch):
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	case errors.Is(err, errUpdateRolledBack):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// newTestDeployManager returns a manager without the background routines
+// NewDeployManager starts.
+func newTestDeployManager(t *testing.T, opts DeployManagerOptions) *DeployManager {
+	t.Helper()
+
+	if opts.LogCapacity == 0 {
+		opts.LogCapacity = 100
+	}
+
+	return &DeployManager{
+		baseDir:      t.TempDir(),
+		runningProcs: make(map[string]*runningProcess),
+		portCounter:  8000,
+		locks:        newDeploymentLocks(),
+		failed:       make(map[string]string),
+		queued:       make(map[string]int),
+		violations:   make(map[string][]LimitViolation),
+		diskUsage:    make(map[string]int64),
+		throttled:    make(map[string]int),
+		sourceErrors: make(map[string]string),
+		logs:         make(map[string]*logStore),
+		opts:         opts,
+	}
+}
+
+func TestDeploymentLocks_Serializes(t *testing.T) {
+	locks := newDeploymentLocks()
+	ctx := context.Background()
+
+	if err := locks.Lock(ctx, "a", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Other deployments aren't held up
+	if err := locks.Lock(ctx, "b", nil); err != nil {
+		t.Fatal(err)
+	}
+	locks.Unlock("b")
+
+	acquired := make(chan struct{})
+	go func() {
+		locks.Lock(ctx, "a", nil)
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("expected the second lock to wait")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	locks.Unlock("a")
+	<-acquired
+	locks.Unlock("a")
+
+	if len(locks.locks) != 0 {
+		t.Errorf("expected unused locks to be removed, have %d", len(locks.locks))
+	}
+}
+
+func TestDeploymentLocks_GiveUp(t *testing.T) {
+	locks := newDeploymentLocks()
+
+	if err := locks.Lock(context.Background(), "a", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	timeout := make(chan time.Time, 1)
+	timeout <- time.Now()
+	if err := locks.Lock(context.Background(), "a", timeout); !errors.Is(err, errDeploymentStarting) {
+		t.Errorf("Lock() after timeout = %v, want errDeploymentStarting", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := locks.Lock(ctx, "a", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Lock() with canceled context = %v, want context.Canceled", err)
+	}
+
+	if refs := locks.locks["a"].refs; refs != 1 {
+		t.Errorf("expected waiters that gave up to release their ref, have %d refs", refs)
+	}
+
+	locks.Unlock("a")
+	if len(locks.locks) != 0 {
+		t.Errorf("expected unused locks to be removed, have %d", len(locks.locks))
+	}
+}
+
+func TestDeploymentMeta_ReadWrite(t *testing.T) {
+	dm := newTestDeployManager(t, DeployManagerOptions{})
+	deploymentID := uuid.New().String()
+
+	if dm.deploymentExists(deploymentID) {
+		t.Fatal("expected deployment not to exist")
+	}
+	if _, err := dm.readMeta(deploymentID); !errors.Is(err, errDeploymentNotFound) {
+		t.Fatalf("readMeta() = %v, want errDeploymentNotFound", err)
+	}
+
+	meta := &DeploymentMeta{ID: deploymentID, EntryPoint: "main.ts", Port: 8001, TenantID: "tenant"}
+	if err := dm.writeMeta(meta); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := dm.readMeta(deploymentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.EntryPoint != "main.ts" || read.Port != 8001 || read.TenantID != "tenant" {
+		t.Errorf("readMeta() = %+v, want the written metadata", read)
+	}
+	if !dm.deploymentExists(deploymentID) {
+		t.Errorf("expected deployment to exist")
+	}
+
+	// Metadata is kept out of the deployment directory
+	if dir := filepath.Dir(dm.metaPath(deploymentID)); dir == filepath.Join(dm.baseDir, deploymentID) {
+		t.Errorf("metadata is stored in the deployment directory")
+	}
+	if _, err := os.Stat(dm.metaPath(deploymentID) + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("expected the temporary metadata file to be renamed")
+	}
+}
+
+func TestDeploymentMeta_InvalidID(t *testing.T) {
+	dm := newTestDeployManager(t, DeployManagerOptions{})
+
+	for _, deploymentID := range []string{"", "..", "../.meta/x", ".meta", "not-a-uuid"} {
+		if dm.deploymentExists(deploymentID) {
+			t.Errorf("deploymentExists(%q) = true", deploymentID)
+		}
+		if _, err := dm.readMeta(deploymentID); !errors.Is(err, errDeploymentNotFound) {
+			t.Errorf("readMeta(%q) = %v, want errDeploymentNotFound", deploymentID, err)
+		}
+	}
+}
+
+func TestDeploymentMeta_Migrate(t *testing.T) {
+	dm := newTestDeployManager(t, DeployManagerOptions{})
+	deploymentID := uuid.New().String()
+
+	legacyPath := filepath.Join(dm.baseDir, deploymentID, ".meta.json")
+	os.MkdirAll(filepath.Dir(legacyPath), 0755)
+	if err := os.WriteFile(legacyPath, []byte(`{"id":"`+deploymentID+`","port":8005}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dm.migrateMeta(deploymentID)
+
+	meta, err := dm.readMeta(deploymentID)
+	if err != nil || meta.Port != 8005 {
+		t.Fatalf("readMeta() after migration = %+v, %v", meta, err)
+	}
+	if _, err := os.Stat(legacyPath); !os.IsNotExist(err) {
+		t.Errorf("expected the legacy metadata to be moved")
+	}
+
+	// Legacy files next to migrated metadata are removed
+	os.WriteFile(legacyPath, []byte(`{"id":"`+deploymentID+`","port":9000}`), 0644)
+	dm.migrateMeta(deploymentID)
+
+	if meta, _ := dm.readMeta(deploymentID); meta.Port != 8005 {
+		t.Errorf("expected migrated metadata to be kept, got port %d", meta.Port)
+	}
+	if _, err := os.Stat(legacyPath); !os.IsNotExist(err) {
+		t.Errorf("expected the legacy metadata to be removed")
+	}
+}
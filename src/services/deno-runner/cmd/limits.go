@@ -0,0 +1,230 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const maxViolationsPerDeployment = 20
+
+const (
+	ViolationHeap   = "heap"
+	ViolationMemory = "memory"
+	ViolationDisk   = "disk"
+	ViolationCPU    = "cpu"
+)
+
+var errDiskQuotaExceeded = errors.New("deployment directory exceeds its size limit")
+
+// DeploymentLimits are the resource limits of a deployment. Zero values
+// fall back to the service defaults, and requested values can't exceed
+// them.
+type DeploymentLimits struct {
+	MemoryMB   int `json:"memoryMb,omitempty"`
+	CPUPercent int `json:"cpuPercent,omitempty"`
+	MaxDirMB   int `json:"maxDirMb,omitempty"`
+}
+
+type LimitViolation struct {
+	Kind    string    `json:"kind"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+func (dm *DeployManager) effectiveLimits(requested *DeploymentLimits) DeploymentLimits {
+	limits := dm.opts.Limits
+	if requested == nil {
+		return limits
+	}
+
+	limit := func(requested, max int) int {
+		if requested <= 0 || (max > 0 && requested > max) {
+			return max
+		}
+		return requested
+	}
+
+	return DeploymentLimits{
+		MemoryMB:   limit(requested.MemoryMB, limits.MemoryMB),
+		CPUPercent: limit(requested.CPUPercent, limits.CPUPercent),
+		MaxDirMB:   limit(requested.MaxDirMB, limits.MaxDirMB),
+	}
+}
+
+// checkAssetsSize rejects deployments whose assets alone exceed the
+// directory limit.
+func (dm *DeployManager) checkAssetsSize(assets map[string]DeploymentFile, requested *DeploymentLimits) error {
+	limits := dm.effectiveLimits(requested)
+	if limits.MaxDirMB <= 0 {
+		return nil
+	}
+
+	var size int64
+	for _, file := range assets {
+		size += int64(len(file.Content))
+	}
+
+	if size > int64(limits.MaxDirMB)*1024*1024 {
+		return fmt.Errorf("%w: assets are %d bytes, limit is %d MB", errDiskQuotaExceeded, size, limits.MaxDirMB)
+	}
+
+	return nil
+}
+
+// v8HeapFlag returns the deno flag limiting the V8 heap. The heap gets
+// three quarters of the memory limit, leaving room for native memory.
+func v8HeapFlag(limits DeploymentLimits) (string, bool) {
+	if limits.MemoryMB <= 0 {
+		return "", false
+	}
+
+	return fmt.Sprintf("--v8-flags=--max-old-space-size=%d", max(limits.MemoryMB*3/4, 16)), true
+}
+
+// isHeapLimitMessage reports whether a line of deno output says the V8
+// heap limit was hit.
+func isHeapLimitMessage(line string) bool {
+	return strings.Contains(line, "JavaScript heap out of memory") ||
+		strings.Contains(line, "Reached heap limit")
+}
+
+// deploymentEnv builds the environment of a deployment process. The
+// service's own environment is not inherited except for a few variables
+// deno needs and those listed in the passthrough option.
+func (dm *DeployManager) deploymentEnv(meta *DeploymentMeta, dataDir string, port int) []string {
+	env := []string{
+		"HOME=" + dataDir,
+		"TMPDIR=" + dataDir,
+	}
+
+	for _, key := range append([]string{"PATH", "LANG", "TZ", "DENO_DIR"}, dm.opts.EnvPassthrough...) {
+		if value, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+value)
+		}
+	}
+
+	env = append(env, fmt.Sprintf("PORT=%d", port))
+	for key, value := range meta.EnvVars {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	return env
+}
+
+func (dm *DeployManager) recordViolation(deploymentID, kind, message string) {
+	log.Printf("Deployment %s: %s limit violated: %s", deploymentID, kind, message)
+	dm.getLogStore(deploymentID).Append(LogStreamSystem, fmt.Sprintf("%s limit violated: %s", kind, message))
+
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	violations := append(dm.violations[deploymentID], LimitViolation{
+		Kind:    kind,
+		Message: message,
+		Time:    time.Now(),
+	})
+	if len(violations) > maxViolationsPerDeployment {
+		violations = violations[len(violations)-maxViolationsPerDeployment:]
+	}
+
+	dm.violations[deploymentID] = violations
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+
+	return size, err
+}
+
+// checkDiskUsage measures the deployment and data directories and
+// records whether they exceed the limit.
+func (dm *DeployManager) checkDiskUsage(deploymentID string, limits DeploymentLimits) error {
+	size, err := dirSize(filepath.Join(dm.baseDir, deploymentID))
+	if err != nil {
+		return nil
+	}
+
+	if dataSize, err := dirSize(dm.dataDir(deploymentID)); err == nil {
+		size += dataSize
+	}
+
+	dm.mu.Lock()
+	dm.diskUsage[deploymentID] = size
+	dm.mu.Unlock()
+
+	if limits.MaxDirMB > 0 && size > int64(limits.MaxDirMB)*1024*1024 {
+		return fmt.Errorf("%w: %d bytes used, limit is %d MB", errDiskQuotaExceeded, size, limits.MaxDirMB)
+	}
+
+	return nil
+}
+
+// checkThrottling records a CPU violation if the deployment's group was
+// throttled since the last check. The first check only remembers the
+// count.
+func (dm *DeployManager) checkThrottling(deploymentID string, periods int) {
+	dm.mu.Lock()
+	previous, checked := dm.throttled[deploymentID]
+	dm.throttled[deploymentID] = periods
+	dm.mu.Unlock()
+
+	if checked && periods > previous {
+		dm.recordViolation(deploymentID, ViolationCPU, fmt.Sprintf("throttled in %d CPU periods", periods-previous))
+	}
+}
+
+// monitorLimits periodically checks running deployments. It records CPU
+// throttling, and checks their directories, e.g. for node_modules or files
+// written at runtime, stopping deployments that exceed their limit.
+func (dm *DeployManager) monitorLimits() {
+	ticker := time.NewTicker(dm.opts.DiskCheckInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		dm.mu.RLock()
+		deploymentIDs := make([]string, 0, len(dm.runningProcs))
+		for deploymentID := range dm.runningProcs {
+			deploymentIDs = append(deploymentIDs, deploymentID)
+		}
+		dm.mu.RUnlock()
+
+		for _, deploymentID := range deploymentIDs {
+			meta, err := dm.readMeta(deploymentID)
+			if err != nil {
+				continue
+			}
+
+			if dm.cgroups != nil {
+				dm.checkThrottling(deploymentID, dm.cgroups.ThrottledPeriods(deploymentID))
+			}
+
+			if err := dm.checkDiskUsage(deploymentID, dm.effectiveLimits(meta.Limits)); err != nil {
+				dm.recordViolation(deploymentID, ViolationDisk, err.Error())
+				dm.stopProcess(deploymentID)
+
+				dm.mu.Lock()
+				dm.failed[deploymentID] = err.Error()
+				dm.mu.Unlock()
+			}
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEffectiveLimits(t *testing.T) {
+	dm := newTestDeployManager(t, DeployManagerOptions{
+		Limits: DeploymentLimits{MemoryMB: 512, CPUPercent: 100, MaxDirMB: 0},
+	})
+
+	tests := []struct {
+		name      string
+		requested *DeploymentLimits
+		want      DeploymentLimits
+	}{
+		{"defaults", nil, DeploymentLimits{MemoryMB: 512, CPUPercent: 100}},
+		{"zero values", &DeploymentLimits{}, DeploymentLimits{MemoryMB: 512, CPUPercent: 100}},
+		{"lower", &DeploymentLimits{MemoryMB: 128, CPUPercent: 50}, DeploymentLimits{MemoryMB: 128, CPUPercent: 50}},
+		{"above maximum", &DeploymentLimits{MemoryMB: 4096, CPUPercent: 400}, DeploymentLimits{MemoryMB: 512, CPUPercent: 100}},
+		{"negative", &DeploymentLimits{MemoryMB: -1}, DeploymentLimits{MemoryMB: 512, CPUPercent: 100}},
+		{"unlimited maximum", &DeploymentLimits{MaxDirMB: 2048}, DeploymentLimits{MemoryMB: 512, CPUPercent: 100, MaxDirMB: 2048}},
+	}
+	for _, tt := range tests {
+		if got := dm.effectiveLimits(tt.requested); got != tt.want {
+			t.Errorf("%s: effectiveLimits() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckAssetsSize(t *testing.T) {
+	dm := newTestDeployManager(t, DeployManagerOptions{Limits: DeploymentLimits{MaxDirMB: 1}})
+
+	small := map[string]DeploymentFile{"a.ts": {Content: strings.Repeat("a", 512*1024)}}
+	large := map[string]DeploymentFile{
+		"a.ts": {Content: strings.Repeat("a", 512*1024)},
+		"b.ts": {Content: strings.Repeat("b", 512*1024+1)},
+	}
+
+	if err := dm.checkAssetsSize(small, nil); err != nil {
+		t.Errorf("checkAssetsSize() of small assets = %v", err)
+	}
+	if err := dm.checkAssetsSize(large, nil); !errors.Is(err, errDiskQuotaExceeded) {
+		t.Errorf("checkAssetsSize() of large assets = %v, want errDiskQuotaExceeded", err)
+	}
+
+	unlimited := newTestDeployManager(t, DeployManagerOptions{})
+	if err := unlimited.checkAssetsSize(large, nil); err != nil {
+		t.Errorf("checkAssetsSize() without limit = %v", err)
+	}
+}
+
+func TestV8HeapFlag(t *testing.T) {
+	tests := []struct {
+		memoryMB int
+		want     string
+		ok       bool
+	}{
+		{0, "", false},
+		{512, "--v8-flags=--max-old-space-size=384", true},
+		{8, "--v8-flags=--max-old-space-size=16", true},
+	}
+	for _, tt := range tests {
+		got, ok := v8HeapFlag(DeploymentLimits{MemoryMB: tt.memoryMB})
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("v8HeapFlag(%d) = %q, %v, want %q, %v", tt.memoryMB, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIsHeapLimitMessage(t *testing.T) {
+	tests := map[string]bool{
+		"FATAL ERROR: Reached heap limit Allocation failed - JavaScript heap out of memory": true,
+		"<--- Last few GCs ---> Reached heap limit":                                         true,
+		"error: Uncaught Error: out of memory":                                              false,
+		"listening on :8000":                                                                false,
+	}
+	for line, want := range tests {
+		if got := isHeapLimitMessage(line); got != want {
+			t.Errorf("isHeapLimitMessage(%q) = %v, want %v", line, got, want)
+		}
+	}
+}
+
+func TestDeploymentEnv(t *testing.T) {
+	t.Setenv("PATH", "/usr/bin")
+	t.Setenv("DENO_RUNNER_SECRET", "secret")
+	t.Setenv("SHARED_VALUE", "shared")
+
+	dm := newTestDeployManager(t, DeployManagerOptions{EnvPassthrough: []string{"SHARED_VALUE"}})
+	env := dm.deploymentEnv(&DeploymentMeta{EnvVars: map[string]string{"API_URL": "https://x.dev"}}, "/data/a", 8001)
+
+	values := make(map[string]string)
+	for _, entry := range env {
+		key, value, _ := strings.Cut(entry, "=")
+		values[key] = value
+	}
+
+	want := map[string]string{
+		"HOME":         "/data/a",
+		"TMPDIR":       "/data/a",
+		"PATH":         "/usr/bin",
+		"SHARED_VALUE": "shared",
+		"PORT":         "8001",
+		"API_URL":      "https://x.dev",
+	}
+	for key, value := range want {
+		if values[key] != value {
+			t.Errorf("%s = %q, want %q", key, values[key], value)
+		}
+	}
+	if _, ok := values["DENO_RUNNER_SECRET"]; ok {
+		t.Errorf("expected the service environment not to be inherited")
+	}
+}
+
+func TestRecordViolation(t *testing.T) {
+	dm := newTestDeployManager(t, DeployManagerOptions{})
+
+	for i := 0; i < maxViolationsPerDeployment+5; i++ {
+		dm.recordViolation("a", ViolationHeap, "heap limit")
+	}
+
+	if got := len(dm.violations["a"]); got != maxViolationsPerDeployment {
+		t.Errorf("kept %d violations, want %d", got, maxViolationsPerDeployment)
+	}
+
+	logs := dm.getLogStore("a").Since(0, 0)
+	if len(logs) == 0 || logs[0].Stream != LogStreamSystem || !strings.Contains(logs[0].Line, "heap limit violated") {
+		t.Errorf("expected violations to be logged, got %+v", logs)
+	}
+}
+
+func TestCheckThrottling(t *testing.T) {
+	dm := newTestDeployManager(t, DeployManagerOptions{})
+
+	// Periods throttled before the first check aren't counted
+	for _, periods := range []int{5, 5, 8, 8, 10} {
+		dm.checkThrottling("a", periods)
+	}
+
+	violations := dm.violations["a"]
+	if len(violations) != 2 {
+		t.Fatalf("recorded %d violations, want 2: %+v", len(violations), violations)
+	}
+	if violations[0].Kind != ViolationCPU || !strings.Contains(violations[0].Message, "3 CPU periods") {
+		t.Errorf("first violation = %+v, want 3 throttled periods", violations[0])
+	}
+	if !strings.Contains(violations[1].Message, "2 CPU periods") {
+		t.Errorf("second violation = %+v, want 2 throttled periods", violations[1])
+	}
+
+	// A new group starts counting from zero again
+	dm.checkThrottling("a", 1)
+	if len(dm.violations["a"]) != 2 {
+		t.Errorf("expected a reset counter not to be recorded")
+	}
+}
+
+func TestCgroupStats(t *testing.T) {
+	cgroups := &cgroupManager{root: t.TempDir()}
+	os.MkdirAll(cgroups.path("a"), 0755)
+	os.WriteFile(filepath.Join(cgroups.path("a"), "cpu.stat"), []byte("usage_usec 1200\nnr_periods 40\nnr_throttled 7\nthrottled_usec 900\n"), 0644)
+	os.WriteFile(filepath.Join(cgroups.path("a"), "memory.events"), []byte("low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n"), 0644)
+
+	if got := cgroups.ThrottledPeriods("a"); got != 7 {
+		t.Errorf("ThrottledPeriods() = %d, want 7", got)
+	}
+	if got := cgroups.OOMKills("a"); got != 1 {
+		t.Errorf("OOMKills() = %d, want 1", got)
+	}
+	if got := cgroups.ThrottledPeriods("missing"); got != 0 {
+		t.Errorf("ThrottledPeriods() of a missing group = %d, want 0", got)
+	}
+}
+
+func TestCheckDiskUsage(t *testing.T) {
+	dm := newTestDeployManager(t, DeployManagerOptions{})
+
+	deployDir := filepath.Join(dm.baseDir, "a")
+	os.MkdirAll(filepath.Join(deployDir, "node_modules"), 0755)
+	os.WriteFile(filepath.Join(deployDir, "main.ts"), make([]byte, 600*1024), 0644)
+	os.WriteFile(filepath.Join(deployDir, "node_modules", "dep.js"), make([]byte, 200*1024), 0644)
+	os.MkdirAll(dm.dataDir("a"), 0755)
+	os.WriteFile(filepath.Join(dm.dataDir("a"), "cache"), make([]byte, 300*1024), 0644)
+
+	if err := dm.checkDiskUsage("a", DeploymentLimits{MaxDirMB: 2}); err != nil {
+		t.Errorf("checkDiskUsage() below limit = %v", err)
+	}
+	if got := dm.diskUsage["a"]; got != 1100*1024 {
+		t.Errorf("disk usage = %d, want %d", got, 1100*1024)
+	}
+
+	if err := dm.checkDiskUsage("a", DeploymentLimits{MaxDirMB: 1}); !errors.Is(err, errDiskQuotaExceeded) {
+		t.Errorf("checkDiskUsage() above limit = %v, want errDiskQuotaExceeded", err)
+	}
+}
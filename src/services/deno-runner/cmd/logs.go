@@ -0,0 +1,354 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	LogStreamStdout = "stdout"
+	LogStreamStderr = "stderr"
+	LogStreamSystem = "system"
+)
+
+type LogEntry struct {
+	Seq    int64     `json:"seq"`
+	Time   time.Time `json:"time"`
+	Stream string    `json:"stream"`
+	Line   string    `json:"line"`
+}
+
+// logStore keeps the most recent output lines of a deployment in a ring
+// buffer. It outlives the deno process, so logs of crashed or idle-stopped
+// processes stay available.
+type logStore struct {
+	entries []LogEntry
+	start   int
+	size    int
+	nextSeq int64
+
+	subscribers map[chan LogEntry]struct{}
+	closed      bool
+	mu          sync.Mutex
+}
+
+func newLogStore(capacity int) *logStore {
+	return &logStore{
+		entries:     make([]LogEntry, capacity),
+		nextSeq:     1,
+		subscribers: make(map[chan LogEntry]struct{}),
+	}
+}
+
+func (ls *logStore) Append(stream, line string) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if ls.closed {
+		return
+	}
+
+	entry := LogEntry{
+		Seq:    ls.nextSeq,
+		Time:   time.Now(),
+		Stream: stream,
+		Line:   line,
+	}
+	ls.nextSeq++
+
+	if ls.size < len(ls.entries) {
+		ls.entries[(ls.start+ls.size)%len(ls.entries)] = entry
+		ls.size++
+	} else {
+		ls.entries[ls.start] = entry
+		ls.start = (ls.start + 1) % len(ls.entries)
+	}
+
+	for ch := range ls.subscribers {
+		select {
+		case ch <- entry:
+		default:
+			// Followers that can't keep up are disconnected. They can
+			// reconnect with the last seq they saw and catch up from the
+			// buffer.
+			delete(ls.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Since returns the buffered entries with a seq greater than afterSeq,
+// limited to the last `tail` entries if tail is positive.
+func (ls *logStore) Since(afterSeq int64, tail int) []LogEntry {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	return ls.since(afterSeq, tail)
+}
+
+func (ls *logStore) since(afterSeq int64, tail int) []LogEntry {
+	res := make([]LogEntry, 0)
+	for i := 0; i < ls.size; i++ {
+		entry := ls.entries[(ls.start+i)%len(ls.entries)]
+		if entry.Seq > afterSeq {
+			res = append(res, entry)
+		}
+	}
+
+	if tail > 0 && len(res) > tail {
+		res = res[len(res)-tail:]
+	}
+
+	return res
+}
+
+// Subscribe returns the buffered entries after afterSeq together with a
+// channel receiving all entries appended from now on. The channel is
+// closed when the store is closed or the subscriber falls behind.
+func (ls *logStore) Subscribe(afterSeq int64, tail int) ([]LogEntry, chan LogEntry, func()) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	backlog := ls.since(afterSeq, tail)
+
+	ch := make(chan LogEntry, 256)
+	if ls.closed {
+		close(ch)
+		return backlog, ch, func() {}
+	}
+
+	ls.subscribers[ch] = struct{}{}
+
+	unsubscribe := func() {
+		ls.mu.Lock()
+		defer ls.mu.Unlock()
+
+		if _, ok := ls.subscribers[ch]; ok {
+			delete(ls.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return backlog, ch, unsubscribe
+}
+
+// Close disconnects all followers. Appends after Close are ignored.
+func (ls *logStore) Close() {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	ls.closed = true
+	for ch := range ls.subscribers {
+		close(ch)
+	}
+	ls.subscribers = make(map[chan LogEntry]struct{})
+}
+
+func (dm *DeployManager) getLogStore(deploymentID string) *logStore {
+	dm.logsMu.Lock()
+	defer dm.logsMu.Unlock()
+
+	store, ok := dm.logs[deploymentID]
+	if !ok {
+		store = newLogStore(dm.opts.LogCapacity)
+		dm.logs[deploymentID] = store
+	}
+
+	return store
+}
+
+func (dm *DeployManager) removeLogStore(deploymentID string) {
+	dm.logsMu.Lock()
+	store, ok := dm.logs[deploymentID]
+	delete(dm.logs, deploymentID)
+	dm.logsMu.Unlock()
+
+	if ok {
+		store.Close()
+	}
+}
+
+// captureOutput copies the lines of a process output stream into the
+// deployment's log store and the service log.
+func (dm *DeployManager) captureOutput(deploymentID, stream string, r io.Reader) {
+	store := dm.getLogStore(deploymentID)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		store.Append(stream, line)
+
+		if stream == LogStreamStderr && isHeapLimitMessage(line) {
+			dm.recordViolation(deploymentID, ViolationHeap, line)
+		}
+		log.Printf("[%s %s] %s", deploymentID[:8], stream, line)
+	}
+}
+
+// logsHandler serves the buffered logs of a deployment. With follow=true
+// new lines are streamed using server-sent events, or over a WebSocket if
+// the request is an upgrade.
+func (dm *DeployManager) logsHandler(w http.ResponseWriter, r *http.Request) {
+	deploymentID := mux.Vars(r)["deploymentId"]
+
+	if !dm.deploymentExists(deploymentID) {
+		http.Error(w, "Deployment not found", http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+
+	var afterSeq int64
+	if since := query.Get("since"); since != "" {
+		parsed, err := strconv.ParseInt(since, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid since parameter", http.StatusBadRequest)
+			return
+		}
+		afterSeq = parsed
+	}
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		if parsed, err := strconv.ParseInt(lastEventID, 10, 64); err == nil {
+			afterSeq = parsed
+		}
+	}
+
+	var tail int
+	if tailParam := query.Get("tail"); tailParam != "" {
+		parsed, err := strconv.Atoi(tailParam)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid tail parameter", http.StatusBadRequest)
+			return
+		}
+		tail = parsed
+	}
+
+	store := dm.getLogStore(deploymentID)
+
+	if websocket.IsWebSocketUpgrade(r) {
+		dm.followLogsWebSocket(w, r, store, afterSeq, tail)
+		return
+	}
+
+	if query.Get("follow") == "true" || query.Get("follow") == "1" {
+		dm.followLogsSSE(w, r, store, afterSeq, tail)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":   deploymentID,
+		"logs": store.Since(afterSeq, tail),
+	})
+}
+
+func (dm *DeployManager) followLogsSSE(w http.ResponseWriter, r *http.Request, store *logStore, afterSeq int64, tail int) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	backlog, ch, unsubscribe := store.Subscribe(afterSeq, tail)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	writeEntry := func(entry LogEntry) error {
+		data, _ := json.Marshal(entry)
+		_, err := fmt.Fprintf(w, "id: %d\nevent: log\ndata: %s\n\n", entry.Seq, data)
+		return err
+	}
+
+	for _, entry := range backlog {
+		if err := writeEntry(entry); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(15 * time.Second)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case entry, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := writeEntry(entry); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (dm *DeployManager) followLogsWebSocket(w http.ResponseWriter, r *http.Request, store *logStore, afterSeq int64, tail int) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	backlog, ch, unsubscribe := store.Subscribe(afterSeq, tail)
+	defer unsubscribe()
+
+	// Reading is required to process control frames and notice when the
+	// client goes away.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for _, entry := range backlog {
+		if err := conn.WriteJSON(entry); err != nil {
+			return
+		}
+	}
+
+	for {
+		select {
+		case <-closed:
+			return
+
+		case entry, ok := <-ch:
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			}
+			if err := conn.WriteJSON(entry); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"testing"
+)
+
+func logLines(entries []LogEntry) []string {
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = entry.Line
+	}
+	return lines
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLogStore_RingBuffer(t *testing.T) {
+	store := newLogStore(3)
+	for _, line := range []string{"a", "b", "c", "d", "e"} {
+		store.Append(LogStreamStdout, line)
+	}
+
+	tests := []struct {
+		name     string
+		afterSeq int64
+		tail     int
+		want     []string
+	}{
+		{"all buffered", 0, 0, []string{"c", "d", "e"}},
+		{"after seq", 3, 0, []string{"d", "e"}},
+		{"after evicted seq", 1, 0, []string{"c", "d", "e"}},
+		{"after last seq", 5, 0, []string{}},
+		{"tail", 0, 2, []string{"d", "e"}},
+		{"tail larger than buffer", 0, 10, []string{"c", "d", "e"}},
+		{"after seq and tail", 3, 1, []string{"e"}},
+	}
+	for _, tt := range tests {
+		if got := logLines(store.Since(tt.afterSeq, tt.tail)); !equalLines(got, tt.want) {
+			t.Errorf("%s: Since(%d, %d) = %v, want %v", tt.name, tt.afterSeq, tt.tail, got, tt.want)
+		}
+	}
+
+	entries := store.Since(0, 0)
+	for i, entry := range entries {
+		if entry.Seq != int64(i+3) {
+			t.Errorf("entry %d has seq %d, want %d", i, entry.Seq, i+3)
+		}
+	}
+}
+
+func TestLogStore_Subscribe(t *testing.T) {
+	store := newLogStore(10)
+	store.Append(LogStreamStdout, "a")
+	store.Append(LogStreamStderr, "b")
+
+	backlog, ch, unsubscribe := store.Subscribe(1, 0)
+	defer unsubscribe()
+
+	if got := logLines(backlog); !equalLines(got, []string{"b"}) {
+		t.Fatalf("backlog = %v, want [b]", got)
+	}
+
+	store.Append(LogStreamSystem, "c")
+
+	entry := <-ch
+	if entry.Line != "c" || entry.Stream != LogStreamSystem || entry.Seq != 3 {
+		t.Errorf("received %+v, want seq 3 system line c", entry)
+	}
+}
+
+func TestLogStore_SlowSubscriberIsDisconnected(t *testing.T) {
+	store := newLogStore(10)
+
+	_, ch, unsubscribe := store.Subscribe(0, 0)
+	defer unsubscribe()
+
+	for i := 0; i < cap(ch)+1; i++ {
+		store.Append(LogStreamStdout, "line")
+	}
+
+	received := 0
+	for range ch {
+		received++
+	}
+
+	if received != cap(ch) {
+		t.Errorf("received %d entries before the channel was closed, want %d", received, cap(ch))
+	}
+	if len(store.subscribers) != 0 {
+		t.Errorf("expected the subscriber to be removed")
+	}
+}
+
+func TestLogStore_Close(t *testing.T) {
+	store := newLogStore(10)
+	store.Append(LogStreamStdout, "a")
+
+	_, ch, unsubscribe := store.Subscribe(0, 0)
+	store.Close()
+
+	if _, ok := <-ch; ok {
+		t.Errorf("expected the subscription to be closed")
+	}
+	unsubscribe()
+
+	store.Append(LogStreamStdout, "b")
+	if got := logLines(store.Since(0, 0)); !equalLines(got, []string{"a"}) {
+		t.Errorf("Since() after Close = %v, want [a]", got)
+	}
+
+	backlog, ch, _ := store.Subscribe(0, 0)
+	if _, ok := <-ch; ok || len(backlog) != 1 {
+		t.Errorf("expected subscriptions after Close to get the backlog and a closed channel")
+	}
+}
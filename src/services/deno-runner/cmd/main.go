@@ -11,6 +11,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -31,6 +32,16 @@ type DeploymentRequest struct {
 	EnvVars       map[string]string         `json:"envVars"`
 	Permissions   map[string][]string       `json:"permissions"`
 	Assets        map[string]DeploymentFile `json:"assets"`
+	HealthPath    string                    `json:"healthPath,omitempty"`
+	Limits        *DeploymentLimits         `json:"limits,omitempty"`
+	// Source deploys the files of a code-bucket bucket instead of Assets
+	Source *DeploymentSource `json:"source,omitempty"`
+
+	// RequireProxyToken makes the proxy route require a deployment token
+	RequireProxyToken bool `json:"requireProxyToken,omitempty"`
+	// TenantID is only used for admin tokens, others create deployments
+	// for the tenant of the token.
+	TenantID string `json:"tenantId,omitempty"`
 }
 
 type DeploymentMeta struct {
@@ -39,6 +50,18 @@ type DeploymentMeta struct {
 	EnvVars     map[string]string   `json:"envVars"`
 	Permissions map[string][]string `json:"permissions"`
 	Port        int                 `json:"port"`
+	HealthPath  string              `json:"healthPath,omitempty"`
+	Limits      *DeploymentLimits   `json:"limits,omitempty"`
+	TenantID    string              `json:"tenantId,omitempty"`
+	OwnerID     string              `json:"ownerId,omitempty"`
+
+	RequireProxyToken bool `json:"requireProxyToken,omitempty"`
+
+	Source         *DeploymentSource `json:"source,omitempty"`
+	SourceSnapshot *SourceSnapshot   `json:"sourceSnapshot,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 type DeployManager struct {
@@ -47,6 +70,56 @@ type DeployManager struct {
 	mu           sync.RWMutex
 	portCounter  int
 	portMu       sync.Mutex
+
+	// locks serializes the starts, updates and deletions of each
+	// deployment, so a deployment directory is never read mid-swap.
+	locks      *deploymentLocks
+	failed     map[string]string
+	queued     map[string]int
+	violations map[string][]LimitViolation
+	diskUsage  map[string]int64
+	throttled  map[string]int
+	cgroups    *cgroupManager
+
+	codeBucket   *codeBucketClient
+	sourceErrors map[string]string
+
+	logs   map[string]*logStore
+	logsMu sync.Mutex
+
+	opts DeployManagerOptions
+}
+
+type DeployManagerOptions struct {
+	// LogCapacity is the number of output lines kept per deployment.
+	LogCapacity int
+	// ReadinessTimeout is how long a process may take to accept
+	// connections before it is killed.
+	ReadinessTimeout time.Duration
+	// ColdStartWait is how long a request waits for a deployment to
+	// become ready before it fails with 503.
+	ColdStartWait time.Duration
+	// MaxQueuedRequests limits the requests waiting per deployment.
+	MaxQueuedRequests int
+	// Limits are the default and maximum resource limits.
+	Limits DeploymentLimits
+	// CgroupRoot is the cgroup v2 group deployment groups are created in.
+	CgroupRoot string
+	// EnvPassthrough lists variables of the service environment that
+	// are passed on to deployments.
+	EnvPassthrough []string
+	// DiskCheckInterval is how often deployment directories are measured.
+	DiskCheckInterval time.Duration
+	// CodeBucketURL is the base URL of the code-bucket HTTP API
+	// deployments with a bucket source are fetched from.
+	CodeBucketURL string
+	// SourcePollInterval is how often buckets of deployments with auto
+	// redeploy are checked for changes.
+	SourcePollInterval time.Duration
+	// CodeBucketJWTSecret is the secret code-bucket signs bucket tokens
+	// with. If set, sources are fetched with tokens signed here instead
+	// of stored ones, which may expire.
+	CodeBucketJWTSecret []byte
 }
 
 type runningProcess struct {
@@ -56,7 +129,12 @@ type runningProcess struct {
 	restartCount int
 	lastRestart  time.Time
 	lastActivity time.Time
+	startedAt    time.Time
+	readyAt      time.Time
+	coldStart    time.Duration
 	stopTimer    *time.Timer
+	ready        chan struct{}
+	exited       chan struct{}
 }
 
 var upgrader = websocket.Upgrader{
@@ -65,16 +143,33 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
-func NewDeployManager(baseDir string) *DeployManager {
+func NewDeployManager(baseDir string, opts DeployManagerOptions) *DeployManager {
 	dm := &DeployManager{
 		baseDir:      baseDir,
 		runningProcs: make(map[string]*runningProcess),
 		portCounter:  8000,
+		locks:        newDeploymentLocks(),
+		failed:       make(map[string]string),
+		queued:       make(map[string]int),
+		violations:   make(map[string][]LimitViolation),
+		diskUsage:    make(map[string]int64),
+		throttled:    make(map[string]int),
+		cgroups:      newCgroupManager(opts.CgroupRoot),
+		codeBucket:   newCodeBucketClient(opts.CodeBucketURL),
+		sourceErrors: make(map[string]string),
+		logs:         make(map[string]*logStore),
+		opts:         opts,
 	}
 
 	// Restore existing deployments on startup
 	dm.restoreDeployments()
 
+	go dm.monitorLimits()
+
+	if dm.codeBucket != nil && opts.SourcePollInterval > 0 {
+		go dm.watchSources()
+	}
+
 	return dm
 }
 
@@ -98,58 +193,69 @@ func (dm *DeployManager) restoreDeployments() {
 			continue
 		}
 
+		if strings.HasPrefix(entry.Name(), ".") {
+			dm.cleanupSwapDir(entry.Name())
+			continue
+		}
+
 		deploymentID := entry.Name()
-		metaPath := filepath.Join(dm.baseDir, deploymentID, ".meta.json")
+		dm.migrateMeta(deploymentID)
 
-		if _, err := os.Stat(metaPath); err == nil {
+		if dm.deploymentExists(deploymentID) {
 			deploymentCount++
 		}
 	}
 
+	dm.restoreInterruptedUpdates()
+
 	if deploymentCount > 0 {
 		log.Printf("Found %d deployment(s), will start on demand", deploymentCount)
 	}
 }
 
-func (dm *DeployManager) CreateDeployment(req DeploymentRequest) (string, error) {
+func (dm *DeployManager) CreateDeployment(ctx context.Context, req DeploymentRequest, tenantID, ownerID string) (string, error) {
 	deploymentID := uuid.New().String()
 	deployDir := filepath.Join(dm.baseDir, deploymentID)
 
-	if err := os.MkdirAll(deployDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create deployment directory: %w", err)
-	}
-
-	// Write files
-	for path, file := range req.Assets {
-		if file.Kind != "file" {
-			continue
-		}
-
-		fullPath := filepath.Join(deployDir, path)
-		dir := filepath.Dir(fullPath)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return "", fmt.Errorf("failed to create directory: %w", err)
-		}
-		if err := os.WriteFile(fullPath, []byte(file.Content), 0644); err != nil {
-			return "", fmt.Errorf("failed to write file: %w", err)
-		}
-	}
-
 	// Assign port and create metadata
 	port := dm.getNextPort()
+	now := time.Now()
 	meta := DeploymentMeta{
 		ID:          deploymentID,
 		EntryPoint:  req.EntryPointURL,
 		EnvVars:     req.EnvVars,
 		Permissions: req.Permissions,
 		Port:        port,
+		HealthPath:  req.HealthPath,
+		Limits:      req.Limits,
+		TenantID:    tenantID,
+		OwnerID:     ownerID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+
+		RequireProxyToken: req.RequireProxyToken,
+		Source:            req.Source,
+	}
+
+	if req.Source != nil {
+		if err := dm.validateSource(req.Source); err != nil {
+			return "", err
+		}
+	}
+
+	assets, err := dm.resolveAssets(ctx, &meta, req.Assets)
+	if err != nil {
+		return "", err
 	}
 
-	// Save metadata
-	metaData, _ := json.MarshalIndent(meta, "", "  ")
-	metaPath := filepath.Join(deployDir, ".meta.json")
-	if err := os.WriteFile(metaPath, metaData, 0644); err != nil {
-		return "", fmt.Errorf("failed to write metadata: %w", err)
+	if err := writeDeploymentDir(deployDir, assets); err != nil {
+		os.RemoveAll(deployDir)
+		return "", err
+	}
+
+	if err := dm.writeMeta(&meta); err != nil {
+		os.RemoveAll(deployDir)
+		return "", err
 	}
 
 	// Start the deployment
@@ -158,13 +264,48 @@ func (dm *DeployManager) CreateDeployment(req DeploymentRequest) (string, error)
 	return deploymentID, nil
 }
 
-func (dm *DeployManager) startDeployment(deploymentID string, meta *DeploymentMeta, deployDir string) {
+// writeDeploymentDir writes the assets of a deployment into a new
+// directory.
+func writeDeploymentDir(deployDir string, assets map[string]DeploymentFile) error {
+	if err := os.MkdirAll(deployDir, 0755); err != nil {
+		return fmt.Errorf("failed to create deployment directory: %w", err)
+	}
+
+	// Write files
+	for path, file := range assets {
+		if file.Kind != "file" {
+			continue
+		}
+
+		fullPath := filepath.Join(deployDir, path)
+		if !strings.HasPrefix(fullPath, filepath.Clean(deployDir)+string(filepath.Separator)) {
+			return fmt.Errorf("invalid asset path: %s", path)
+		}
+
+		dir := filepath.Dir(fullPath)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(file.Content), 0644); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (dm *DeployManager) startDeployment(deploymentID string, meta *DeploymentMeta, deployDir string) error {
 	const maxRestarts = 5
 	const restartBackoff = 2 * time.Second
 
+	logs := dm.getLogStore(deploymentID)
+
 	// Check if we should restart
 	dm.mu.Lock()
+	var restarting *runningProcess
 	if proc, exists := dm.runningProcs[deploymentID]; exists {
+		restarting = proc
+
 		// Kill the old process first
 		if proc.cancel != nil {
 			proc.cancel()
@@ -176,8 +317,10 @@ func (dm *DeployManager) startDeployment(deploymentID string, meta *DeploymentMe
 		if proc.restartCount >= maxRestarts {
 			log.Printf("Deployment %s exceeded max restarts (%d), marking as failed", deploymentID, maxRestarts)
 			delete(dm.runningProcs, deploymentID)
+			dm.failed[deploymentID] = fmt.Sprintf("exceeded max restarts (%d)", maxRestarts)
 			dm.mu.Unlock()
-			return
+			logs.Append(LogStreamSystem, fmt.Sprintf("exceeded max restarts (%d), giving up", maxRestarts))
+			return fmt.Errorf("deployment exceeded max restarts")
 		}
 
 		// Enforce backoff
@@ -188,12 +331,20 @@ func (dm *DeployManager) startDeployment(deploymentID string, meta *DeploymentMe
 			dm.mu.Unlock()
 			time.Sleep(sleepDuration)
 			dm.mu.Lock()
+
+			// The deployment may have been stopped, updated or deleted
+			// while waiting.
+			if current, ok := dm.runningProcs[deploymentID]; !ok || current != proc {
+				dm.mu.Unlock()
+				return fmt.Errorf("deployment was stopped")
+			}
 		}
 
 		proc.restartCount++
 		proc.lastRestart = time.Now()
 		dm.mu.Unlock()
 	} else {
+		delete(dm.failed, deploymentID)
 		dm.mu.Unlock()
 	}
 
@@ -201,9 +352,16 @@ func (dm *DeployManager) startDeployment(deploymentID string, meta *DeploymentMe
 	port := dm.getNextPort()
 	meta.Port = port
 
+	limits := dm.effectiveLimits(meta.Limits)
+
 	// Build deno command with permissions
 	args := []string{"run", "--node-modules-dir=auto"}
 
+	// Memory limit for the V8 heap
+	if flag, ok := v8HeapFlag(limits); ok {
+		args = append(args, flag)
+	}
+
 	// Network permissions
 	if netPerms, ok := meta.Permissions["net"]; ok {
 		if len(netPerms) == 1 && netPerms[0] == "*" {
@@ -213,11 +371,17 @@ func (dm *DeployManager) startDeployment(deploymentID string, meta *DeploymentMe
 		}
 	}
 
-	// Read permissions - only the deployment directory
-	args = append(args, fmt.Sprintf("--allow-read=%s", deployDir))
+	// Files written at runtime go to the data directory, the deployment
+	// directory is only readable. Deno writes node_modules itself, which
+	// doesn't need a permission.
+	dataDir := dm.dataDir(deploymentID)
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		log.Printf("Failed to create data directory for deployment %s: %v", deploymentID, err)
+		return err
+	}
 
-	// Write permissions - allow writing to deployment directory for node_modules
-	args = append(args, fmt.Sprintf("--allow-write=%s", deployDir))
+	args = append(args, fmt.Sprintf("--allow-read=%s,%s", deployDir, dataDir))
+	args = append(args, fmt.Sprintf("--allow-write=%s", dataDir))
 
 	// Environment variable permissions
 	args = append(args, "--allow-env")
@@ -228,30 +392,41 @@ func (dm *DeployManager) startDeployment(deploymentID string, meta *DeploymentMe
 	// Strip any leading slash to ensure it's relative
 	entryPoint = strings.TrimPrefix(entryPoint, "/")
 
-	log.Printf("DEBUG deployment %s: entryPoint: %s (relative to deployDir: %s)", deploymentID, entryPoint, deployDir)
-
 	args = append(args, entryPoint)
 	args = append(args, entryPoint)
 
+	// CPU and memory limits for the whole process, where available. The
+	// process is started inside its group, so it never runs without them.
+	var cgroup *os.File
+	var oomKills int
+	if dm.cgroups != nil {
+		var err error
+		cgroup, oomKills, err = dm.cgroups.Prepare(deploymentID, limits)
+		if err != nil {
+			log.Printf("Failed to apply cgroup limits to deployment %s: %v", deploymentID, err)
+			logs.Append(LogStreamSystem, fmt.Sprintf("running without cgroup limits: %v", err))
+		} else {
+			defer cgroup.Close()
+		}
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	cmd := exec.CommandContext(ctx, "deno", args...)
 	cmd.Dir = deployDir
 
-	// Set environment variables
-	cmd.Env = os.Environ()
-	cmd.Env = append(cmd.Env, fmt.Sprintf("PORT=%d", port))
-	for key, value := range meta.EnvVars {
-		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
-	}
+	// Set environment variables, without inheriting the service's
+	cmd.Env = dm.deploymentEnv(meta, dataDir, port)
 
-	log.Printf("DEBUG deployment %s: Setting PORT env var to %d", deploymentID, port)
+	if cgroup != nil {
+		cmd.SysProcAttr = cgroupProcAttr(cgroup)
+	}
 
 	// Capture stderr to see why it's crashing
 	stderr, err := cmd.StderrPipe()
 	if err != nil {
 		log.Printf("Failed to get stderr for deployment %s: %v", deploymentID, err)
 		cancel()
-		return
+		return err
 	}
 
 	// Capture stdout
@@ -259,16 +434,19 @@ func (dm *DeployManager) startDeployment(deploymentID string, meta *DeploymentMe
 	if err != nil {
 		log.Printf("Failed to get stdout for deployment %s: %v", deploymentID, err)
 		cancel()
-		return
+		return err
 	}
 
 	// Start the process
 	if err := cmd.Start(); err != nil {
 		log.Printf("Failed to start deployment %s: %v", deploymentID, err)
+		logs.Append(LogStreamSystem, fmt.Sprintf("failed to start: %v", err))
 		cancel()
-		return
+		return err
 	}
 
+	logs.Append(LogStreamSystem, fmt.Sprintf("process started on port %d", meta.Port))
+
 	log.Printf("Started deployment %s on port %d (restart count: %d)", deploymentID, meta.Port, func() int {
 		dm.mu.RLock()
 		defer dm.mu.RUnlock()
@@ -278,56 +456,71 @@ func (dm *DeployManager) startDeployment(deploymentID string, meta *DeploymentMe
 		return 0
 	}())
 
+	startedAt := time.Now()
+	ready := make(chan struct{})
+	exited := make(chan struct{})
+
 	// Track running process
 	dm.mu.Lock()
-	if _, exists := dm.runningProcs[deploymentID]; !exists {
+	current, exists := dm.runningProcs[deploymentID]
+	if restarting != nil && current != restarting {
+		// Stopped while the new process was starting
+		dm.mu.Unlock()
+		cancel()
+		cmd.Process.Kill()
+		cmd.Wait()
+		return fmt.Errorf("deployment was stopped")
+	}
+	if !exists {
 		dm.runningProcs[deploymentID] = &runningProcess{
 			cmd:          cmd,
 			cancel:       cancel,
 			port:         meta.Port,
 			restartCount: 0,
 			lastRestart:  time.Now(),
+			startedAt:    startedAt,
+			ready:        ready,
+			exited:       exited,
 		}
 	} else {
-		dm.runningProcs[deploymentID].cmd = cmd
-		dm.runningProcs[deploymentID].cancel = cancel
+		current.cmd = cmd
+		current.cancel = cancel
+		current.port = meta.Port
+		current.startedAt = startedAt
+		current.readyAt = time.Time{}
+		current.coldStart = 0
+		current.ready = ready
+		current.exited = exited
 	}
 	dm.mu.Unlock()
 
-	// Monitor output in goroutines - with limited buffering to see crash reasons
-	go func() {
-		buf := make([]byte, 4096)
-		for {
-			n, err := stderr.Read(buf)
-			if err != nil {
-				break
-			}
-			if n > 0 {
-				log.Printf("[%s stderr] %s", deploymentID[:8], string(buf[:n]))
-			}
-		}
-	}()
+	go dm.probeReadiness(deploymentID, meta, meta.Port, startedAt, ready, exited)
 
-	go func() {
-		buf := make([]byte, 4096)
-		for {
-			n, err := stdout.Read(buf)
-			if err != nil {
-				break
-			}
-			if n > 0 {
-				log.Printf("[%s stdout] %s", deploymentID[:8], string(buf[:n]))
-			}
-		}
-	}()
+	// Monitor output in goroutines
+	go dm.captureOutput(deploymentID, LogStreamStderr, stderr)
+	go dm.captureOutput(deploymentID, LogStreamStdout, stdout)
 
 	// Wait for process and restart if it crashes
 	go func() {
 		err := cmd.Wait()
+		close(exited)
+
+		if err != nil {
+			logs.Append(LogStreamSystem, fmt.Sprintf("process exited: %v", err))
+		} else {
+			logs.Append(LogStreamSystem, "process exited")
+		}
+
+		if dm.cgroups != nil {
+			if kills := dm.cgroups.OOMKills(deploymentID); kills > oomKills {
+				dm.recordViolation(deploymentID, ViolationMemory, fmt.Sprintf("killed for exceeding %d MB of memory", limits.MemoryMB))
+			}
+		}
 
 		dm.mu.Lock()
 		proc, exists := dm.runningProcs[deploymentID]
-		if !exists {
+		if !exists || proc.cmd != cmd {
+			// Stopped on purpose or replaced by an update
 			dm.mu.Unlock()
 			return
 		}
@@ -342,7 +535,7 @@ func (dm *DeployManager) startDeployment(deploymentID string, meta *DeploymentMe
 		}
 
 		// Check if deployment still exists, if so, restart it
-		if _, err := os.Stat(filepath.Join(deployDir, ".meta.json")); err == nil {
+		if dm.deploymentExists(deploymentID) {
 			if restartCount < maxRestarts {
 				log.Printf("Deployment %s crashed, restarting...", deploymentID)
 				dm.startDeployment(deploymentID, meta, deployDir)
@@ -350,10 +543,13 @@ func (dm *DeployManager) startDeployment(deploymentID string, meta *DeploymentMe
 				log.Printf("Deployment %s exceeded max restarts, giving up", deploymentID)
 				dm.mu.Lock()
 				delete(dm.runningProcs, deploymentID)
+				dm.failed[deploymentID] = fmt.Sprintf("exceeded max restarts (%d)", maxRestarts)
 				dm.mu.Unlock()
 			}
 		}
 	}()
+
+	return nil
 }
 
 func (dm *DeployManager) GetDeploymentPort(deploymentID string) (int, bool) {
@@ -415,71 +611,68 @@ func (dm *DeployManager) stopDeployment(deploymentID string) {
 	dm.mu.Unlock()
 }
 
-func (dm *DeployManager) ensureDeploymentRunning(deploymentID string) error {
+func (dm *DeployManager) ensureDeploymentRunning(ctx context.Context, deploymentID string, timeout <-chan time.Time) error {
+	// Updates hold the deployment's lock until the new version is ready,
+	// so waiting for it is bounded like the rest of the cold start.
+	// Concurrent requests for a stopped deployment must also start it once.
+	if err := dm.locks.Lock(ctx, deploymentID, timeout); err != nil {
+		return err
+	}
+	defer dm.locks.Unlock(deploymentID)
+
 	dm.mu.RLock()
 	_, exists := dm.runningProcs[deploymentID]
 	dm.mu.RUnlock()
 
 	if exists {
-		// Already running, just update activity
-		dm.GetDeploymentPort(deploymentID)
 		return nil
 	}
 
 	// Need to start it
 	deployDir := filepath.Join(dm.baseDir, deploymentID)
-	metaPath := filepath.Join(deployDir, ".meta.json")
 
-	data, err := os.ReadFile(metaPath)
+	meta, err := dm.readMeta(deploymentID)
 	if err != nil {
-		return fmt.Errorf("deployment not found")
+		return err
 	}
 
-	var meta DeploymentMeta
-	if err := json.Unmarshal(data, &meta); err != nil {
-		return fmt.Errorf("invalid deployment metadata")
+	if err := dm.checkDiskUsage(deploymentID, dm.effectiveLimits(meta.Limits)); err != nil {
+		return err
 	}
 
 	log.Printf("Deployment %s: starting on demand", deploymentID)
 
-	// Start the deployment
-	go dm.startDeployment(deploymentID, &meta, deployDir)
-
-	// Wait a bit for it to start
-	time.Sleep(500 * time.Millisecond)
+	return dm.startDeployment(deploymentID, meta, deployDir)
+}
 
-	return nil
+func (dm *DeployManager) proxyHandler(auth *authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		dm.proxy(w, r, auth)
+	}
 }
 
-func (dm *DeployManager) proxyHandler(w http.ResponseWriter, r *http.Request) {
+func (dm *DeployManager) proxy(w http.ResponseWriter, r *http.Request, auth *authenticator) {
 	vars := mux.Vars(r)
 	deploymentID := vars["deploymentId"]
 	path := vars["path"]
 
-	// Ensure deployment is running (start if needed)
-	if err := dm.ensureDeploymentRunning(deploymentID); err != nil {
-		http.Error(w, "Deployment not found", http.StatusNotFound)
+	meta, err := dm.readMeta(deploymentID)
+	if err != nil {
+		writeNotReadyError(w, err)
 		return
 	}
 
-	// port, ok := dm.GetDeploymentPort(deploymentID)
-	// if !ok {
-	// 	http.Error(w, "Deployment not ready", http.StatusServiceUnavailable)
-	// 	return
-	// }
-
-	// Wait for deployment to be ready
-	var port int
-	for i := 0; i < 1000; i++ {
-		var ok bool
-		port, ok = dm.GetDeploymentPort(deploymentID)
-		if ok {
-			break
+	if meta.RequireProxyToken {
+		if err := auth.authenticateProxy(r, deploymentID); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
 		}
-		time.Sleep(200 * time.Millisecond)
 	}
-	if port == 0 {
-		http.Error(w, "Deployment not ready", http.StatusServiceUnavailable)
+
+	// Start the deployment if needed and wait until it accepts requests
+	port, err := dm.waitForReady(r.Context(), deploymentID)
+	if err != nil {
+		writeNotReadyError(w, err)
 		return
 	}
 
@@ -585,64 +778,174 @@ func main() {
 		log.Fatalf("Failed to create base directory: %v", err)
 	}
 
-	dm := NewDeployManager(baseDir)
+	dm := NewDeployManager(baseDir, DeployManagerOptions{
+		LogCapacity:       envInt("DEPLOYMENT_LOG_LINES", 1000),
+		ReadinessTimeout:  envDuration("DEPLOYMENT_READINESS_TIMEOUT", 60*time.Second),
+		ColdStartWait:     envDuration("DEPLOYMENT_COLD_START_WAIT", 30*time.Second),
+		MaxQueuedRequests: envInt("DEPLOYMENT_MAX_QUEUED_REQUESTS", 100),
+		Limits: DeploymentLimits{
+			MemoryMB:   envInt("DEPLOYMENT_MEMORY_MB", 512),
+			CPUPercent: envInt("DEPLOYMENT_CPU_PERCENT", 100),
+			MaxDirMB:   envInt("DEPLOYMENT_MAX_DIR_MB", 512),
+		},
+		CgroupRoot:        envString("DEPLOYMENT_CGROUP_ROOT", "/sys/fs/cgroup/deno-runner"),
+		EnvPassthrough:    envList("DEPLOYMENT_ENV_PASSTHROUGH"),
+		DiskCheckInterval: envDuration("DEPLOYMENT_DISK_CHECK_INTERVAL", 30*time.Second),
+
+		CodeBucketURL:       os.Getenv("CODE_BUCKET_URL"),
+		SourcePollInterval:  envDuration("DEPLOYMENT_SOURCE_POLL_INTERVAL", 30*time.Second),
+		CodeBucketJWTSecret: []byte(os.Getenv("CODE_BUCKET_JWT_SECRET")),
+	})
+
+	auth := &authenticator{
+		jwtSecret: []byte(os.Getenv("DENO_RUNNER_JWT_SECRET")),
+		disabled:  os.Getenv("DENO_RUNNER_AUTH_DISABLED") == "true",
+	}
+	if auth.disabled {
+		log.Printf("WARNING: authentication is disabled, anyone who can reach this service can run code")
+	} else if len(auth.jwtSecret) == 0 {
+		log.Fatalf("DENO_RUNNER_JWT_SECRET is required, set DENO_RUNNER_AUTH_DISABLED=true to run without authentication")
+	}
 
 	r := mux.NewRouter()
 
 	// Create deployment
-	r.HandleFunc("/deployments", func(w http.ResponseWriter, r *http.Request) {
+	r.HandleFunc("/deployments", auth.management(func(w http.ResponseWriter, r *http.Request, claims *Claims) {
 		var req DeploymentRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		deploymentID, err := dm.CreateDeployment(req)
+		tenantID := claims.TenantID
+		if claims.IsAdmin && req.TenantID != "" {
+			tenantID = req.TenantID
+		}
+
+		deploymentID, err := dm.CreateDeployment(r.Context(), req, tenantID, claims.Subject)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeDeploymentError(w, err)
 			return
 		}
 
 		json.NewEncoder(w).Encode(map[string]string{
 			"id": deploymentID,
 		})
-	}).Methods("POST")
+	})).Methods("POST")
+
+	// List deployments
+	r.HandleFunc("/deployments", auth.management(func(w http.ResponseWriter, r *http.Request, claims *Claims) {
+		deployments, err := dm.ListDeployments(claims)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"deployments": deployments,
+		})
+	})).Methods("GET")
 
 	// Get deployment status
-	r.HandleFunc("/deployments/{deploymentId}", func(w http.ResponseWriter, r *http.Request) {
-		vars := mux.Vars(r)
-		deploymentID := vars["deploymentId"]
+	r.HandleFunc("/deployments/{deploymentId}", auth.management(func(w http.ResponseWriter, r *http.Request, claims *Claims) {
+		deploymentID := mux.Vars(r)["deploymentId"]
+		if err := dm.authorize(claims, deploymentID); err != nil {
+			writeDeploymentError(w, err)
+			return
+		}
 
-		port, ok := dm.GetDeploymentPort(deploymentID)
+		status, err := dm.GetDeploymentStatus(deploymentID)
+		if err != nil {
+			writeDeploymentError(w, err)
+			return
+		}
 
-		var status string
-		var restartCount int
+		json.NewEncoder(w).Encode(status)
+	})).Methods("GET")
 
-		if ok {
-			status = "running"
-			dm.mu.RLock()
-			if proc, exists := dm.runningProcs[deploymentID]; exists {
-				restartCount = proc.restartCount
-			}
-			dm.mu.RUnlock()
-		} else {
-			// Check if deployment exists but is not running
-			deployDir := filepath.Join(dm.baseDir, deploymentID)
-			if _, err := os.Stat(filepath.Join(deployDir, ".meta.json")); err == nil {
-				status = "failed"
-			} else {
-				http.Error(w, "Deployment not found", http.StatusNotFound)
+	// Update deployment
+	r.HandleFunc("/deployments/{deploymentId}", auth.management(func(w http.ResponseWriter, r *http.Request, claims *Claims) {
+		deploymentID := mux.Vars(r)["deploymentId"]
+		if err := dm.authorize(claims, deploymentID); err != nil {
+			writeDeploymentError(w, err)
+			return
+		}
+
+		var req DeploymentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		status, err := dm.UpdateDeployment(r.Context(), deploymentID, req)
+		if err != nil {
+			writeDeploymentError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(status)
+	})).Methods("PUT")
+
+	// Delete deployment
+	r.HandleFunc("/deployments/{deploymentId}", auth.management(func(w http.ResponseWriter, r *http.Request, claims *Claims) {
+		deploymentID := mux.Vars(r)["deploymentId"]
+		if err := dm.authorize(claims, deploymentID); err != nil {
+			writeDeploymentError(w, err)
+			return
+		}
+
+		if err := dm.DeleteDeployment(deploymentID); err != nil {
+			writeDeploymentError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})).Methods("DELETE")
+
+	// Deployment logs
+	r.HandleFunc("/deployments/{deploymentId}/logs", auth.management(func(w http.ResponseWriter, r *http.Request, claims *Claims) {
+		if err := dm.authorize(claims, mux.Vars(r)["deploymentId"]); err != nil {
+			writeDeploymentError(w, err)
+			return
+		}
+
+		dm.logsHandler(w, r)
+	})).Methods("GET")
+
+	// Fetch the bucket of a deployment again and redeploy it if it changed
+	r.HandleFunc("/deployments/{deploymentId}/redeploy", auth.management(func(w http.ResponseWriter, r *http.Request, claims *Claims) {
+		deploymentID := mux.Vars(r)["deploymentId"]
+		if err := dm.authorize(claims, deploymentID); err != nil {
+			writeDeploymentError(w, err)
+			return
+		}
+
+		var req struct {
+			Force bool `json:"force"`
+			// Token replaces the stored bucket token
+			Token string `json:"token"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
 			}
 		}
 
+		updated, status, err := dm.RedeployFromSource(r.Context(), deploymentID, req.Force, req.Token)
+		if err != nil {
+			writeDeploymentError(w, err)
+			return
+		}
+
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"id":           deploymentID,
-			"port":         port,
-			"status":       status,
-			"restartCount": restartCount,
+			"updated":    updated,
+			"deployment": status,
 		})
-	}).Methods("GET")
+	})).Methods("POST")
+
+	// Create an access token for the proxy route of a deployment
+	r.HandleFunc("/deployments/{deploymentId}/proxy-tokens", dm.proxyTokenHandler(auth)).Methods("POST")
 
 	// Health check
 	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -651,7 +954,7 @@ func main() {
 	}).Methods("GET")
 
 	// Proxy routes - must be last
-	r.HandleFunc("/{deploymentId}/{path:.*}", dm.proxyHandler)
+	r.HandleFunc("/{deploymentId}/{path:.*}", dm.proxyHandler(auth))
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -662,3 +965,50 @@ func main() {
 	log.Printf("Deployments directory: %s", baseDir)
 	log.Fatal(http.ListenAndServe(":"+port, r))
 }
+
+func envInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		log.Fatalf("Invalid %s: %s", key, value)
+	}
+
+	return parsed
+}
+
+func envDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		log.Fatalf("Invalid %s: %s", key, value)
+	}
+
+	return parsed
+}
+
+func envString(key string, defaultValue string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+
+	return defaultValue
+}
+
+func envList(key string) []string {
+	res := make([]string, 0)
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			res = append(res, value)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const readinessProbeInterval = 50 * time.Millisecond
+
+var (
+	errDeploymentStarting = errors.New("deployment is still starting")
+	errTooManyQueued      = errors.New("too many requests waiting for the deployment to start")
+	errDeploymentFailed   = errors.New("deployment failed to start")
+)
+
+// probeReadiness waits until the deployment accepts connections, or
+// answers on its health path if one is configured, and marks it ready.
+// Processes that don't become ready within the readiness timeout are
+// killed, which counts as a crash.
+func (dm *DeployManager) probeReadiness(deploymentID string, meta *DeploymentMeta, port int, startedAt time.Time, ready, exited chan struct{}) {
+	logs := dm.getLogStore(deploymentID)
+
+	client := &http.Client{Timeout: time.Second}
+	deadline := time.NewTimer(dm.opts.ReadinessTimeout)
+	defer deadline.Stop()
+
+	ticker := time.NewTicker(readinessProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		if probeDeployment(client, port, meta.HealthPath) {
+			coldStart := time.Since(startedAt)
+
+			dm.mu.Lock()
+			if proc, ok := dm.runningProcs[deploymentID]; ok && proc.ready == ready {
+				proc.readyAt = time.Now()
+				proc.coldStart = coldStart
+			}
+			dm.mu.Unlock()
+
+			close(ready)
+
+			log.Printf("Deployment %s: ready on port %d after %v", deploymentID, port, coldStart)
+			logs.Append(LogStreamSystem, fmt.Sprintf("ready after %dms", coldStart.Milliseconds()))
+			return
+		}
+
+		select {
+		case <-exited:
+			return
+
+		case <-deadline.C:
+			log.Printf("Deployment %s: not ready after %v, killing process", deploymentID, dm.opts.ReadinessTimeout)
+			logs.Append(LogStreamSystem, fmt.Sprintf("not ready after %v", dm.opts.ReadinessTimeout))
+
+			dm.mu.RLock()
+			if proc, ok := dm.runningProcs[deploymentID]; ok && proc.ready == ready && proc.cmd.Process != nil {
+				proc.cmd.Process.Kill()
+			}
+			dm.mu.RUnlock()
+			return
+
+		case <-ticker.C:
+		}
+	}
+}
+
+func probeDeployment(client *http.Client, port int, healthPath string) bool {
+	address := net.JoinHostPort("localhost", strconv.Itoa(port))
+
+	if healthPath == "" {
+		conn, err := net.DialTimeout("tcp", address, time.Second)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}
+
+	res, err := client.Get(fmt.Sprintf("http://%s/%s", address, strings.TrimPrefix(healthPath, "/")))
+	if err != nil {
+		return false
+	}
+	res.Body.Close()
+
+	return res.StatusCode >= 200 && res.StatusCode < 400
+}
+
+// waitForReady starts the deployment if needed and waits for it to
+// become ready. At most MaxQueuedRequests requests wait per deployment
+// and none longer than ColdStartWait.
+func (dm *DeployManager) waitForReady(ctx context.Context, deploymentID string) (int, error) {
+	dm.mu.Lock()
+	if proc, ok := dm.runningProcs[deploymentID]; ok {
+		select {
+		case <-proc.ready:
+			dm.mu.Unlock()
+			return dm.touchDeployment(deploymentID)
+		default:
+		}
+	}
+
+	if dm.queued[deploymentID] >= dm.opts.MaxQueuedRequests {
+		dm.mu.Unlock()
+		return 0, errTooManyQueued
+	}
+	dm.queued[deploymentID]++
+	dm.mu.Unlock()
+
+	defer func() {
+		dm.mu.Lock()
+		dm.queued[deploymentID]--
+		if dm.queued[deploymentID] <= 0 {
+			delete(dm.queued, deploymentID)
+		}
+		dm.mu.Unlock()
+	}()
+
+	timeout := time.NewTimer(dm.opts.ColdStartWait)
+	defer timeout.Stop()
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			dm.mu.RLock()
+			_, failed := dm.failed[deploymentID]
+			dm.mu.RUnlock()
+
+			// Don't restart a deployment that gave up while we waited
+			if failed {
+				return 0, errDeploymentFailed
+			}
+		}
+
+		if err := dm.ensureDeploymentRunning(ctx, deploymentID, timeout.C); err != nil {
+			return 0, err
+		}
+
+		dm.mu.RLock()
+		proc, ok := dm.runningProcs[deploymentID]
+		var ready, exited chan struct{}
+		if ok {
+			ready, exited = proc.ready, proc.exited
+		}
+		dm.mu.RUnlock()
+
+		if !ok {
+			continue
+		}
+
+		select {
+		case <-ready:
+			return dm.touchDeployment(deploymentID)
+
+		case <-exited:
+			// The process crashed during startup. Give the restart a
+			// moment to register before checking again.
+			select {
+			case <-time.After(readinessProbeInterval):
+			case <-timeout.C:
+				return 0, errDeploymentStarting
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+
+		case <-timeout.C:
+			return 0, errDeploymentStarting
+
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
+
+// touchDeployment returns the port of a running deployment and records
+// activity for the idle timer.
+func (dm *DeployManager) touchDeployment(deploymentID string) (int, error) {
+	port, ok := dm.GetDeploymentPort(deploymentID)
+	if !ok {
+		return 0, errDeploymentStarting
+	}
+
+	return port, nil
+}
+
+func writeNotReadyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errDeploymentNotFound):
+		http.Error(w, "Deployment not found", http.StatusNotFound)
+
+	case errors.Is(err, errDeploymentStarting), errors.Is(err, errTooManyQueued):
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+
+	case errors.Is(err, errDiskQuotaExceeded):
+		http.Error(w, err.Error(), http.StatusInsufficientStorage)
+
+	case errors.Is(err, errDeploymentFailed):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		// Client went away while waiting
+
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
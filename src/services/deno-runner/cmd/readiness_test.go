@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// addTestProcess registers a running process without starting one.
+func addTestProcess(dm *DeployManager, deploymentID string, port int) *runningProcess {
+	proc := &runningProcess{
+		port:   port,
+		ready:  make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+
+	dm.mu.Lock()
+	dm.runningProcs[deploymentID] = proc
+	dm.mu.Unlock()
+
+	return proc
+}
+
+func stopTestProcess(dm *DeployManager, deploymentID string) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	if proc, ok := dm.runningProcs[deploymentID]; ok && proc.stopTimer != nil {
+		proc.stopTimer.Stop()
+	}
+}
+
+func TestWaitForReady_Ready(t *testing.T) {
+	dm := newTestDeployManager(t, DeployManagerOptions{ColdStartWait: time.Second, MaxQueuedRequests: 2})
+	proc := addTestProcess(dm, "a", 8123)
+	defer stopTestProcess(dm, "a")
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(proc.ready)
+	}()
+
+	port, err := dm.waitForReady(context.Background(), "a")
+	if err != nil || port != 8123 {
+		t.Fatalf("waitForReady() = %d, %v, want 8123", port, err)
+	}
+	if len(dm.queued) != 0 {
+		t.Errorf("expected the request to leave the queue, have %v", dm.queued)
+	}
+
+	// Ready deployments don't count against the queue
+	dm.queued["a"] = 2
+	if port, err := dm.waitForReady(context.Background(), "a"); err != nil || port != 8123 {
+		t.Errorf("waitForReady() on a ready deployment = %d, %v", port, err)
+	}
+}
+
+func TestWaitForReady_QueueFull(t *testing.T) {
+	dm := newTestDeployManager(t, DeployManagerOptions{ColdStartWait: time.Second, MaxQueuedRequests: 2})
+	addTestProcess(dm, "a", 8123)
+
+	dm.queued["a"] = 2
+	if _, err := dm.waitForReady(context.Background(), "a"); !errors.Is(err, errTooManyQueued) {
+		t.Errorf("waitForReady() = %v, want errTooManyQueued", err)
+	}
+	if dm.queued["a"] != 2 {
+		t.Errorf("expected rejected requests not to be queued, have %d", dm.queued["a"])
+	}
+}
+
+func TestWaitForReady_GiveUp(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(dm *DeployManager, proc *runningProcess) context.Context
+		want    error
+	}{
+		{"cold start timeout", func(dm *DeployManager, proc *runningProcess) context.Context {
+			return context.Background()
+		}, errDeploymentStarting},
+		{"client gone", func(dm *DeployManager, proc *runningProcess) context.Context {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(20*time.Millisecond, cancel)
+			return ctx
+		}, context.Canceled},
+		{"failed while waiting", func(dm *DeployManager, proc *runningProcess) context.Context {
+			dm.failed["a"] = "crashed"
+			close(proc.exited)
+			return context.Background()
+		}, errDeploymentFailed},
+	}
+	for _, tt := range tests {
+		dm := newTestDeployManager(t, DeployManagerOptions{ColdStartWait: 100 * time.Millisecond, MaxQueuedRequests: 2})
+		proc := addTestProcess(dm, "a", 8123)
+
+		if _, err := dm.waitForReady(tt.prepare(dm, proc), "a"); !errors.Is(err, tt.want) {
+			t.Errorf("%s: waitForReady() = %v, want %v", tt.name, err, tt.want)
+		}
+		if len(dm.queued) != 0 {
+			t.Errorf("%s: expected the request to leave the queue, have %v", tt.name, dm.queued)
+		}
+	}
+}
+
+func TestProbeDeployment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			w.WriteHeader(http.StatusNoContent)
+		case "/moved":
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	_, portString, _ := net.SplitHostPort(server.Listener.Addr().String())
+	port, _ := strconv.Atoi(portString)
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedPort := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	client := &http.Client{Timeout: time.Second}
+
+	tests := []struct {
+		port       int
+		healthPath string
+		want       bool
+	}{
+		{port, "", true},
+		{port, "/healthz", true},
+		{port, "healthz", true},
+		{port, "/moved", true},
+		{port, "/starting", false},
+		{closedPort, "", false},
+		{closedPort, "/healthz", false},
+	}
+	for _, tt := range tests {
+		if got := probeDeployment(client, tt.port, tt.healthPath); got != tt.want {
+			t.Errorf("probeDeployment(%d, %q) = %v, want %v", tt.port, tt.healthPath, got, tt.want)
+		}
+	}
+}
+
+func TestWriteNotReadyError(t *testing.T) {
+	tests := []struct {
+		err        error
+		status     int
+		retryAfter bool
+	}{
+		{errDeploymentNotFound, http.StatusNotFound, false},
+		{errDeploymentStarting, http.StatusServiceUnavailable, true},
+		{errTooManyQueued, http.StatusServiceUnavailable, true},
+		{fmt.Errorf("%w: 1 MB", errDiskQuotaExceeded), http.StatusInsufficientStorage, false},
+		{errDeploymentFailed, http.StatusServiceUnavailable, false},
+		{errors.New("other"), http.StatusInternalServerError, false},
+	}
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		writeNotReadyError(recorder, tt.err)
+
+		if recorder.Code != tt.status {
+			t.Errorf("writeNotReadyError(%v) status = %d, want %d", tt.err, recorder.Code, tt.status)
+		}
+		if got := recorder.Header().Get("Retry-After") != ""; got != tt.retryAfter {
+			t.Errorf("writeNotReadyError(%v) Retry-After set = %v, want %v", tt.err, got, tt.retryAfter)
+		}
+	}
+}
@@ -0,0 +1,469 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	errSourceNotConfigured = errors.New("deploying from code-bucket is not configured")
+	errNoSource            = errors.New("deployment has no bucket source")
+	errInvalidSource       = errors.New("invalid bucket source")
+	errSourceFetch         = errors.New("failed to fetch bucket source")
+)
+
+// sourceTokenExpiry is how long the tokens deno-runner signs to fetch
+// sources are valid.
+const sourceTokenExpiry = 10 * time.Minute
+
+// DeploymentSource references the code of a deployment in a code-bucket
+// bucket instead of inline assets. The token is a bucket token issued by
+// code-bucket; a read-only token is sufficient, and one is required to
+// create or update a deployment. If deno-runner has the code-bucket
+// secret, the token is only used to create or update the deployment and
+// redeploys sign their own short-lived tokens. Otherwise it is stored for
+// redeploys, which fail once it expires unless a new token is passed.
+type DeploymentSource struct {
+	BucketID string `json:"bucketId"`
+	Token    string `json:"token"`
+	// Prefix limits the deployment to the files below a directory of
+	// the bucket. Paths are relative to the prefix.
+	Prefix string `json:"prefix,omitempty"`
+	// AutoRedeploy redeploys the deployment when the bucket changes.
+	AutoRedeploy bool `json:"autoRedeploy,omitempty"`
+}
+
+// SourceSnapshot pins the bucket contents a deployment runs.
+type SourceSnapshot struct {
+	// Revision is a hash of the paths and contents of the deployed files.
+	Revision string `json:"revision"`
+	// ListingHash is a hash of the file listing, used to detect changes
+	// without downloading every file.
+	ListingHash string    `json:"listingHash"`
+	FetchedAt   time.Time `json:"fetchedAt"`
+}
+
+type DeploymentSourceStatus struct {
+	BucketID     string     `json:"bucketId"`
+	Prefix       string     `json:"prefix,omitempty"`
+	AutoRedeploy bool       `json:"autoRedeploy,omitempty"`
+	Revision     string     `json:"revision,omitempty"`
+	FetchedAt    *time.Time `json:"fetchedAt,omitempty"`
+	LastError    string     `json:"lastError,omitempty"`
+}
+
+type bucketFile struct {
+	Path       string    `json:"path"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// codeBucketClient reads bucket files through the code-bucket HTTP API.
+type codeBucketClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func newCodeBucketClient(baseURL string) *codeBucketClient {
+	if baseURL == "" {
+		return nil
+	}
+
+	return &codeBucketClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *codeBucketClient) get(ctx context.Context, token, path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errSourceFetch, err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		res.Body.Close()
+		return nil, fmt.Errorf("%w: code-bucket returned %d for %s: %s", errSourceFetch, res.StatusCode, path, strings.TrimSpace(string(body)))
+	}
+
+	return res, nil
+}
+
+func (c *codeBucketClient) listFiles(ctx context.Context, token string, source *DeploymentSource) ([]bucketFile, error) {
+	res, err := c.get(ctx, token, "/files")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var files []bucketFile
+	if err := json.NewDecoder(res.Body).Decode(&files); err != nil {
+		return nil, fmt.Errorf("%w: invalid file listing: %v", errSourceFetch, err)
+	}
+
+	filtered := make([]bucketFile, 0, len(files))
+	for _, file := range files {
+		if source.Prefix != "" && !strings.HasPrefix(file.Path, strings.TrimSuffix(source.Prefix, "/")+"/") {
+			continue
+		}
+		filtered = append(filtered, file)
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].Path < filtered[j].Path
+	})
+
+	return filtered, nil
+}
+
+func (c *codeBucketClient) getFile(ctx context.Context, token, path string, maxBytes int64) ([]byte, error) {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	res, err := c.get(ctx, token, "/files/"+strings.Join(segments, "/"))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(res.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read %s: %v", errSourceFetch, path, err)
+	}
+	if int64(len(content)) > maxBytes {
+		return nil, fmt.Errorf("%w: %s is too large", errDiskQuotaExceeded, path)
+	}
+
+	return content, nil
+}
+
+func listingHash(files []bucketFile) string {
+	hash := sha256.New()
+	for _, file := range files {
+		fmt.Fprintf(hash, "%s\x00%d\x00%d\n", file.Path, file.Size, file.ModifiedAt.UnixNano())
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// validateSource checks that the token belongs to the referenced bucket.
+// Without the code-bucket secret the signature is checked by code-bucket
+// when the files are fetched.
+func (dm *DeployManager) validateSource(source *DeploymentSource) error {
+	if source.BucketID == "" || source.Token == "" {
+		return fmt.Errorf("%w: bucketId and token are required", errInvalidSource)
+	}
+
+	claims := jwt.MapClaims{}
+	if secret := dm.opts.CodeBucketJWTSecret; len(secret) > 0 {
+		_, err := jwt.ParseWithClaims(source.Token, claims, func(token *jwt.Token) (any, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return secret, nil
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %v", errInvalidSource, err)
+		}
+	} else {
+		if _, _, err := jwt.NewParser().ParseUnverified(source.Token, claims); err != nil {
+			return fmt.Errorf("%w: malformed token", errInvalidSource)
+		}
+
+		if expiresAt, err := claims.GetExpirationTime(); err == nil && expiresAt != nil && expiresAt.Before(time.Now()) {
+			return fmt.Errorf("%w: token expired, pass a new one", errInvalidSource)
+		}
+	}
+
+	if bucketID, _ := claims["bucket_id"].(string); bucketID != source.BucketID {
+		return fmt.Errorf("%w: token is not valid for bucket %s", errInvalidSource, source.BucketID)
+	}
+
+	return nil
+}
+
+// sourceToken returns the token to fetch a source with. Sources without a
+// token get a short-lived read-only token signed with the code-bucket
+// secret. Only sources stored in the metadata of a deployment may lack a
+// token: requested sources are checked with validateSource first, so a
+// caller can't get a token for a bucket it has no token for.
+func (dm *DeployManager) sourceToken(source *DeploymentSource) (string, error) {
+	if source.Token != "" {
+		if err := dm.validateSource(source); err != nil {
+			return "", err
+		}
+
+		return source.Token, nil
+	}
+
+	if len(dm.opts.CodeBucketJWTSecret) == 0 {
+		return "", fmt.Errorf("%w: bucketId and token are required", errInvalidSource)
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"bucket_id":    source.BucketID,
+		"is_read_only": true,
+		"iat":          now.Unix(),
+		"exp":          now.Add(sourceTokenExpiry).Unix(),
+	})
+
+	return token.SignedString(dm.opts.CodeBucketJWTSecret)
+}
+
+// resolveAssets returns the assets of a deployment, which are fetched
+// from its bucket if it has a source.
+func (dm *DeployManager) resolveAssets(ctx context.Context, meta *DeploymentMeta, assets map[string]DeploymentFile) (map[string]DeploymentFile, error) {
+	if meta.Source != nil {
+		fetched, err := dm.fetchSource(ctx, meta)
+		if err != nil {
+			return nil, err
+		}
+		assets = fetched
+	}
+
+	if err := dm.checkAssetsSize(assets, meta.Limits); err != nil {
+		return nil, err
+	}
+
+	return assets, nil
+}
+
+// fetchSource downloads the files of a bucket source as deployment
+// assets and records the snapshot in meta.
+func (dm *DeployManager) fetchSource(ctx context.Context, meta *DeploymentMeta) (map[string]DeploymentFile, error) {
+	source := meta.Source
+
+	if dm.codeBucket == nil {
+		return nil, errSourceNotConfigured
+	}
+
+	token, err := dm.sourceToken(source)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := dm.codeBucket.listFiles(ctx, token, source)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBytes := int64(1) << 62
+	if limits := dm.effectiveLimits(meta.Limits); limits.MaxDirMB > 0 {
+		maxBytes = int64(limits.MaxDirMB) * 1024 * 1024
+	}
+
+	assets := make(map[string]DeploymentFile, len(files))
+	revision := sha256.New()
+
+	var total int64
+	for _, file := range files {
+		content, err := dm.codeBucket.getFile(ctx, token, file.Path, maxBytes-total)
+		if err != nil {
+			return nil, err
+		}
+		total += int64(len(content))
+
+		path := file.Path
+		if source.Prefix != "" {
+			path = strings.TrimPrefix(path, strings.TrimSuffix(source.Prefix, "/")+"/")
+		}
+
+		assets[path] = DeploymentFile{
+			Kind:    "file",
+			Content: string(content),
+		}
+
+		contentHash := sha256.Sum256(content)
+		fmt.Fprintf(revision, "%s\x00%x\n", path, contentHash)
+	}
+
+	meta.SourceSnapshot = &SourceSnapshot{
+		Revision:    hex.EncodeToString(revision.Sum(nil)),
+		ListingHash: listingHash(files),
+		FetchedAt:   time.Now(),
+	}
+
+	// Redeploys sign their own tokens, so the token isn't stored
+	if len(dm.opts.CodeBucketJWTSecret) > 0 {
+		storedSource := *source
+		storedSource.Token = ""
+		meta.Source = &storedSource
+	}
+
+	return assets, nil
+}
+
+// RedeployFromSource fetches the bucket of a deployment again and
+// updates the deployment if the contents changed, or always if force is
+// set. A token passed here replaces the stored one. It reports whether the
+// deployment was updated.
+func (dm *DeployManager) RedeployFromSource(ctx context.Context, deploymentID string, force bool, token string) (bool, *DeploymentStatus, error) {
+	if err := dm.locks.Lock(ctx, deploymentID, nil); err != nil {
+		return false, nil, err
+	}
+	defer dm.locks.Unlock(deploymentID)
+
+	previousMeta, err := dm.readMeta(deploymentID)
+	if err != nil {
+		return false, nil, err
+	}
+	if previousMeta.Source == nil {
+		return false, nil, errNoSource
+	}
+
+	meta := *previousMeta
+	source := *previousMeta.Source
+	if token != "" {
+		source.Token = token
+	}
+	meta.Source = &source
+
+	assets, err := dm.resolveAssets(ctx, &meta, nil)
+	if err != nil {
+		dm.setSourceError(deploymentID, err)
+		return false, nil, err
+	}
+	dm.setSourceError(deploymentID, nil)
+
+	if !force && previousMeta.SourceSnapshot != nil && previousMeta.SourceSnapshot.Revision == meta.SourceSnapshot.Revision {
+		// Only the listing or the token changed, e.g. a file was
+		// rewritten with the same content. Remember it so the next poll
+		// doesn't refetch.
+		if previousMeta.SourceSnapshot.ListingHash != meta.SourceSnapshot.ListingHash || previousMeta.Source.Token != meta.Source.Token {
+			stored := *previousMeta
+			stored.Source = meta.Source
+			stored.SourceSnapshot = meta.SourceSnapshot
+			if err := dm.writeMeta(&stored); err != nil {
+				return false, nil, err
+			}
+			previousMeta = &stored
+		}
+
+		return false, dm.statusFromMeta(previousMeta), nil
+	}
+
+	dm.getLogStore(deploymentID).Append(LogStreamSystem, fmt.Sprintf("redeploying bucket %s at revision %s", meta.Source.BucketID, meta.SourceSnapshot.Revision[:12]))
+
+	status, err := dm.replaceDeploymentLocked(deploymentID, previousMeta, meta, assets)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return true, status, nil
+}
+
+func (dm *DeployManager) setSourceError(deploymentID string, err error) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	if err == nil {
+		delete(dm.sourceErrors, deploymentID)
+	} else {
+		dm.sourceErrors[deploymentID] = err.Error()
+	}
+}
+
+// watchSources polls the buckets of deployments with auto redeploy
+// enabled and redeploys them when their file listing changes.
+func (dm *DeployManager) watchSources() {
+	ticker := time.NewTicker(dm.opts.SourcePollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		entries, err := os.ReadDir(dm.baseDir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() || !isValidDeploymentID(entry.Name()) {
+				continue
+			}
+
+			meta, err := dm.readMeta(entry.Name())
+			if err != nil || meta.Source == nil || !meta.Source.AutoRedeploy {
+				continue
+			}
+
+			dm.checkSource(meta)
+		}
+	}
+}
+
+func (dm *DeployManager) checkSource(meta *DeploymentMeta) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	token, err := dm.sourceToken(meta.Source)
+	if err != nil {
+		dm.setSourceError(meta.ID, err)
+		return
+	}
+
+	files, err := dm.codeBucket.listFiles(ctx, token, meta.Source)
+	if err != nil {
+		log.Printf("Deployment %s: failed to check bucket %s: %v", meta.ID, meta.Source.BucketID, err)
+		dm.setSourceError(meta.ID, err)
+		return
+	}
+
+	if meta.SourceSnapshot != nil && meta.SourceSnapshot.ListingHash == listingHash(files) {
+		return
+	}
+
+	updated, _, err := dm.RedeployFromSource(ctx, meta.ID, false, "")
+	if err != nil {
+		log.Printf("Deployment %s: failed to redeploy from bucket %s: %v", meta.ID, meta.Source.BucketID, err)
+		dm.setSourceError(meta.ID, err)
+		return
+	}
+
+	if updated {
+		log.Printf("Deployment %s: redeployed from bucket %s", meta.ID, meta.Source.BucketID)
+	}
+}
+
+func (dm *DeployManager) sourceStatus(meta *DeploymentMeta) *DeploymentSourceStatus {
+	if meta.Source == nil {
+		return nil
+	}
+
+	status := &DeploymentSourceStatus{
+		BucketID:     meta.Source.BucketID,
+		Prefix:       meta.Source.Prefix,
+		AutoRedeploy: meta.Source.AutoRedeploy,
+		LastError:    dm.sourceErrors[meta.ID],
+	}
+
+	if meta.SourceSnapshot != nil {
+		fetchedAt := meta.SourceSnapshot.FetchedAt
+		status.Revision = meta.SourceSnapshot.Revision
+		status.FetchedAt = &fetchedAt
+	}
+
+	return status
+}
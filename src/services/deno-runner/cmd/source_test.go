@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testBucketSecret = []byte("bucket-secret")
+
+func signBucketToken(t *testing.T, secret []byte, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+// newTestCodeBucket serves the files of a bucket like the code-bucket
+// HTTP API, for requests with the given token.
+func newTestCodeBucket(t *testing.T, token string, files map[string]string) *httptest.Server {
+	t.Helper()
+
+	modifiedAt := time.Unix(1700000000, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		if r.URL.Path == "/files" {
+			listing := make([]bucketFile, 0, len(files))
+			for path, content := range files {
+				listing = append(listing, bucketFile{Path: path, Size: int64(len(content)), ModifiedAt: modifiedAt})
+			}
+			json.NewEncoder(w).Encode(listing)
+			return
+		}
+
+		content, ok := files[strings.TrimPrefix(r.URL.Path, "/files/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestValidateSource(t *testing.T) {
+	expires := time.Now().Add(time.Hour).Unix()
+	valid := signBucketToken(t, testBucketSecret, jwt.MapClaims{"bucket_id": "a", "exp": expires})
+	otherBucket := signBucketToken(t, testBucketSecret, jwt.MapClaims{"bucket_id": "b", "exp": expires})
+	otherSecret := signBucketToken(t, []byte("other"), jwt.MapClaims{"bucket_id": "a", "exp": expires})
+	expired := signBucketToken(t, []byte("other"), jwt.MapClaims{"bucket_id": "a", "exp": time.Now().Add(-time.Hour).Unix()})
+
+	tests := []struct {
+		name   string
+		secret []byte
+		source DeploymentSource
+		valid  bool
+	}{
+		{"valid", testBucketSecret, DeploymentSource{BucketID: "a", Token: valid}, true},
+		{"missing token", testBucketSecret, DeploymentSource{BucketID: "a"}, false},
+		{"missing bucket", testBucketSecret, DeploymentSource{Token: valid}, false},
+		{"bucket mismatch", testBucketSecret, DeploymentSource{BucketID: "a", Token: otherBucket}, false},
+		{"wrong signature", testBucketSecret, DeploymentSource{BucketID: "a", Token: otherSecret}, false},
+		{"malformed", testBucketSecret, DeploymentSource{BucketID: "a", Token: "not-a-token"}, false},
+		{"unverified", nil, DeploymentSource{BucketID: "a", Token: otherSecret}, true},
+		{"unverified missing token", nil, DeploymentSource{BucketID: "a"}, false},
+		{"unverified bucket mismatch", nil, DeploymentSource{BucketID: "a", Token: otherBucket}, false},
+		{"unverified expired", nil, DeploymentSource{BucketID: "a", Token: expired}, false},
+		{"unverified malformed", nil, DeploymentSource{BucketID: "a", Token: "not-a-token"}, false},
+	}
+	for _, tt := range tests {
+		dm := newTestDeployManager(t, DeployManagerOptions{CodeBucketJWTSecret: tt.secret})
+
+		err := dm.validateSource(&tt.source)
+		if tt.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !tt.valid && !errors.Is(err, errInvalidSource) {
+			t.Errorf("%s: expected errInvalidSource, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestSourceToken(t *testing.T) {
+	valid := signBucketToken(t, testBucketSecret, jwt.MapClaims{"bucket_id": "a"})
+	otherBucket := signBucketToken(t, testBucketSecret, jwt.MapClaims{"bucket_id": "b"})
+
+	tests := []struct {
+		name   string
+		secret []byte
+		source DeploymentSource
+		want   string
+		err    error
+	}{
+		{"passed token", testBucketSecret, DeploymentSource{BucketID: "a", Token: valid}, valid, nil},
+		{"passed token without secret", nil, DeploymentSource{BucketID: "a", Token: valid}, valid, nil},
+		{"bucket mismatch", testBucketSecret, DeploymentSource{BucketID: "a", Token: otherBucket}, "", errInvalidSource},
+		{"missing token without secret", nil, DeploymentSource{BucketID: "a"}, "", errInvalidSource},
+	}
+	for _, tt := range tests {
+		dm := newTestDeployManager(t, DeployManagerOptions{CodeBucketJWTSecret: tt.secret})
+
+		got, err := dm.sourceToken(&tt.source)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("%s: sourceToken() = %q, %v, want %q, %v", tt.name, got, err, tt.want, tt.err)
+		}
+	}
+
+	// Stored sources get a read-only token for their bucket
+	dm := newTestDeployManager(t, DeployManagerOptions{CodeBucketJWTSecret: testBucketSecret})
+	signed, err := dm.sourceToken(&DeploymentSource{BucketID: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(signed, claims, func(*jwt.Token) (any, error) { return testBucketSecret, nil }); err != nil {
+		t.Fatalf("signed token is invalid: %v", err)
+	}
+	if claims["bucket_id"] != "a" || claims["is_read_only"] != true {
+		t.Errorf("signed token claims = %v, want a read-only token for bucket a", claims)
+	}
+	if expiresAt, _ := claims.GetExpirationTime(); expiresAt == nil || expiresAt.After(time.Now().Add(sourceTokenExpiry)) {
+		t.Errorf("signed token expires at %v, want within %v", expiresAt, sourceTokenExpiry)
+	}
+}
+
+func TestFetchSource(t *testing.T) {
+	token := signBucketToken(t, testBucketSecret, jwt.MapClaims{"bucket_id": "a"})
+	server := newTestCodeBucket(t, token, map[string]string{
+		"app/main.ts":    "serve()",
+		"app/lib/a.ts":   "export {}",
+		"other/skip.ts":  "skipped",
+		"application.ts": "skipped",
+	})
+
+	tests := []struct {
+		name   string
+		secret []byte
+		prefix string
+		want   map[string]string
+	}{
+		{"whole bucket", nil, "", map[string]string{
+			"app/main.ts": "serve()", "app/lib/a.ts": "export {}", "other/skip.ts": "skipped", "application.ts": "skipped",
+		}},
+		{"prefix", nil, "app", map[string]string{"main.ts": "serve()", "lib/a.ts": "export {}"}},
+		{"prefix with slash", testBucketSecret, "app/", map[string]string{"main.ts": "serve()", "lib/a.ts": "export {}"}},
+	}
+	for _, tt := range tests {
+		dm := newTestDeployManager(t, DeployManagerOptions{CodeBucketURL: server.URL, CodeBucketJWTSecret: tt.secret})
+		dm.codeBucket = newCodeBucketClient(server.URL)
+
+		meta := &DeploymentMeta{Source: &DeploymentSource{BucketID: "a", Token: token, Prefix: tt.prefix}}
+		assets, err := dm.fetchSource(context.Background(), meta)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+
+		if len(assets) != len(tt.want) {
+			t.Errorf("%s: fetched %v, want %v", tt.name, assets, tt.want)
+		}
+		for path, content := range tt.want {
+			if assets[path].Content != content || assets[path].Kind != "file" {
+				t.Errorf("%s: %s = %+v, want %q", tt.name, path, assets[path], content)
+			}
+		}
+
+		if meta.SourceSnapshot == nil || meta.SourceSnapshot.Revision == "" || meta.SourceSnapshot.ListingHash == "" {
+			t.Errorf("%s: expected the snapshot to be recorded, got %+v", tt.name, meta.SourceSnapshot)
+		}
+
+		// The token is only stored if redeploys can't sign their own
+		if storesToken := meta.Source.Token != ""; storesToken != (tt.secret == nil) {
+			t.Errorf("%s: stored token = %v, want %v", tt.name, storesToken, tt.secret == nil)
+		}
+	}
+}
+
+func TestFetchSource_Revision(t *testing.T) {
+	token := signBucketToken(t, testBucketSecret, jwt.MapClaims{"bucket_id": "a"})
+
+	revision := func(files map[string]string) string {
+		server := newTestCodeBucket(t, token, files)
+		dm := newTestDeployManager(t, DeployManagerOptions{})
+		dm.codeBucket = newCodeBucketClient(server.URL)
+
+		meta := &DeploymentMeta{Source: &DeploymentSource{BucketID: "a", Token: token}}
+		if _, err := dm.fetchSource(context.Background(), meta); err != nil {
+			t.Fatal(err)
+		}
+		return meta.SourceSnapshot.Revision
+	}
+
+	first := revision(map[string]string{"a.ts": "a", "b.ts": "b"})
+	if revision(map[string]string{"b.ts": "b", "a.ts": "a"}) != first {
+		t.Errorf("expected the revision to be stable")
+	}
+	if revision(map[string]string{"a.ts": "a", "b.ts": "c"}) == first {
+		t.Errorf("expected the revision to change with the contents")
+	}
+	if revision(map[string]string{"a.ts": "a", "c.ts": "b"}) == first {
+		t.Errorf("expected the revision to change with the paths")
+	}
+}
+
+func TestFetchSource_Errors(t *testing.T) {
+	token := signBucketToken(t, testBucketSecret, jwt.MapClaims{"bucket_id": "a"})
+	otherBucket := signBucketToken(t, testBucketSecret, jwt.MapClaims{"bucket_id": "b"})
+	server := newTestCodeBucket(t, token, map[string]string{
+		"a.ts": strings.Repeat("a", 600*1024),
+		"b.ts": strings.Repeat("b", 600*1024),
+	})
+
+	tests := []struct {
+		name   string
+		url    string
+		opts   DeployManagerOptions
+		source DeploymentSource
+		want   error
+	}{
+		{"not configured", "", DeployManagerOptions{}, DeploymentSource{BucketID: "a", Token: token}, errSourceNotConfigured},
+		{"missing token", server.URL, DeployManagerOptions{}, DeploymentSource{BucketID: "a"}, errInvalidSource},
+		{"bucket mismatch", server.URL, DeployManagerOptions{CodeBucketJWTSecret: testBucketSecret}, DeploymentSource{BucketID: "a", Token: otherBucket}, errInvalidSource},
+		{"rejected token", server.URL, DeployManagerOptions{}, DeploymentSource{BucketID: "b", Token: otherBucket}, errSourceFetch},
+		{"too large", server.URL, DeployManagerOptions{Limits: DeploymentLimits{MaxDirMB: 1}}, DeploymentSource{BucketID: "a", Token: token}, errDiskQuotaExceeded},
+	}
+	for _, tt := range tests {
+		dm := newTestDeployManager(t, tt.opts)
+		dm.codeBucket = newCodeBucketClient(tt.url)
+
+		meta := &DeploymentMeta{Source: &tt.source}
+		if _, err := dm.fetchSource(context.Background(), meta); !errors.Is(err, tt.want) {
+			t.Errorf("%s: fetchSource() = %v, want %v", tt.name, err, tt.want)
+		}
+		if meta.SourceSnapshot != nil {
+			t.Errorf("%s: expected no snapshot to be recorded", tt.name)
+		}
+	}
+}
+
+func TestRequestedSource_RequiresToken(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	dm := newTestDeployManager(t, DeployManagerOptions{CodeBucketJWTSecret: testBucketSecret})
+	dm.codeBucket = newCodeBucketClient(server.URL)
+
+	otherBucket := signBucketToken(t, testBucketSecret, jwt.MapClaims{"bucket_id": "b"})
+
+	for _, source := range []DeploymentSource{{BucketID: "a"}, {BucketID: "a", Token: otherBucket}} {
+		req := DeploymentRequest{EntryPointURL: "main.ts", Source: &source}
+
+		if _, err := dm.CreateDeployment(context.Background(), req, "tenant", "owner"); !errors.Is(err, errInvalidSource) {
+			t.Errorf("CreateDeployment() with token %q = %v, want errInvalidSource", source.Token, err)
+		}
+		if _, err := dm.UpdateDeployment(context.Background(), "a", req); !errors.Is(err, errInvalidSource) {
+			t.Errorf("UpdateDeployment() with token %q = %v, want errInvalidSource", source.Token, err)
+		}
+	}
+
+	if requests != 0 {
+		t.Errorf("expected requested sources to be rejected before fetching, got %d requests", requests)
+	}
+}
@@ -3,7 +3,10 @@ module github.com/metorial/metorial/services/deno-runner
 go 1.21
 
 require (
+	github.com/golang-jwt/jwt/v5 v5.2.3
 	github.com/google/uuid v1.5.0
 	github.com/gorilla/mux v1.8.1
 	github.com/gorilla/websocket v1.5.1
 )
+
+require golang.org/x/net v0.17.0 // indirect
@@ -33,6 +33,8 @@ func main() {
 	awsBucket := mustGetEnv("LOG_AWS_S3_BUCKET")
 	awsEndpoint := os.Getenv("LOG_AWS_ENDPOINT")
 
+	queueDir := os.Getenv("LOG_QUEUE_DIR")
+
 	service := service.NewService(
 		context.Background(),
 		entries.DefaultEntryTypeRegistry,
@@ -40,6 +42,7 @@ func main() {
 		service.WithMongoURI(mongoURI),
 		service.WithMongoDatabase(mongoDb),
 		service.WithGRPCAddress(rpcAddress),
+		service.WithQueueDir(queueDir),
 
 		service.WithAwsAccessKey(awsAccessKey),
 		service.WithAwsSecretKey(awsSecretKey),
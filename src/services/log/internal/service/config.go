@@ -13,6 +13,10 @@ type Config struct {
 	MongoDatabase string
 
 	GRPCAddress string
+
+	// QueueDir enables the durable ingest queue, which keeps logs on disk
+	// until they are stored.
+	QueueDir string
 }
 
 type ConfigOptions func(*Config)
@@ -69,6 +73,12 @@ func WithGRPCAddress(address string) ConfigOptions {
 	}
 }
 
+func WithQueueDir(dir string) ConfigOptions {
+	return func(c *Config) {
+		c.QueueDir = dir
+	}
+}
+
 func applyConfigOptions(opts ...ConfigOptions) *Config {
 	config := &Config{
 		AwsAccessKey:  "",
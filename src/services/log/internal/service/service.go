@@ -41,7 +41,10 @@ func NewService(ctx context.Context, entryRegistry *entries.EntryTypeRegistry, o
 		log.Fatalf("Failed to initialize S3 storage: %v", err)
 	}
 
-	storeRegistry := store.NewStoreTypeRegistry(entryRegistry, storage, db)
+	storeRegistry, err := store.NewStoreTypeRegistry(entryRegistry, storage, db, config.QueueDir)
+	if err != nil {
+		log.Fatalf("Failed to initialize log stores: %v", err)
+	}
 
 	return &LogService{
 		entryRegistry: entryRegistry,
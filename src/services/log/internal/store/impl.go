@@ -52,33 +52,63 @@ func (s *LogStore) IngestLog(
 		return status.Errorf(codes.Internal, "failed to extract fields: %v", err)
 	}
 
-	s.queue.Add(func() error {
-		payloadKey := fmt.Sprintf("%s/%s", s.entryType.GetTypeName(), entryId)
-
-		if err := s.storageBackend.Store(payloadKey, []byte(payloadJson)); err != nil {
-			return err
-		}
-
-		doc := &LogDocument{
-			EntityID:   entryId,
-			EntityType: s.entryType.GetTypeName(),
-			InstanceID: instanceId,
-			Timestamp:  timestamp,
-			PayloadKey: payloadKey,
-			Fields:     fields,
-		}
+	job := ingestJob{
+		InstanceID:  instanceId,
+		EntryID:     entryId,
+		PayloadJson: payloadJson,
+		Timestamp:   timestamp,
+		Fields:      fields,
+	}
 
-		if _, err := s.collection.InsertOne(context.Background(), doc); err != nil {
-			fmt.Printf("Failed to insert log document: %v\n", err)
-			return err
+	if s.durableQueue != nil {
+		if err := s.durableQueue.Add(job, 10); err != nil {
+			return status.Errorf(codes.Unavailable, "failed to queue log: %v", err)
 		}
 
 		return nil
+	}
+
+	s.queue.Add(func() error {
+		return s.ingest(job)
 	}, 10)
 
 	return nil
 }
 
+// ingestJob is a log waiting to be stored. It is written to the queue log
+// when the durable queue is used, so it only holds plain data.
+type ingestJob struct {
+	InstanceID  string         `json:"instance_id"`
+	EntryID     string         `json:"entry_id"`
+	PayloadJson string         `json:"payload_json"`
+	Timestamp   uint64         `json:"timestamp"`
+	Fields      map[string]any `json:"fields"`
+}
+
+func (s *LogStore) ingest(job ingestJob) error {
+	payloadKey := fmt.Sprintf("%s/%s", s.entryType.GetTypeName(), job.EntryID)
+
+	if err := s.storageBackend.Store(payloadKey, []byte(job.PayloadJson)); err != nil {
+		return err
+	}
+
+	doc := &LogDocument{
+		EntityID:   job.EntryID,
+		EntityType: s.entryType.GetTypeName(),
+		InstanceID: job.InstanceID,
+		Timestamp:  job.Timestamp,
+		PayloadKey: payloadKey,
+		Fields:     job.Fields,
+	}
+
+	if _, err := s.collection.InsertOne(context.Background(), doc); err != nil {
+		fmt.Printf("Failed to insert log document: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
 func (s *LogStore) ListLogs(ctx context.Context, req *LogFilter) ([]*LogDocument, error) {
 	// Build query filters
 	filters := bson.M{}
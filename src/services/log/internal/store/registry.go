@@ -12,11 +12,14 @@ type StoreTypeRegistry struct {
 	db             *mongo.Database
 }
 
-func NewStoreTypeRegistry(entryTypeRegistry *entries.EntryTypeRegistry, storageBackend StorageBackend, db *mongo.Database) *StoreTypeRegistry {
+func NewStoreTypeRegistry(entryTypeRegistry *entries.EntryTypeRegistry, storageBackend StorageBackend, db *mongo.Database, queueDir string) (*StoreTypeRegistry, error) {
 	types := make(map[string]*LogStore)
 
 	for _, entryType := range entryTypeRegistry.GetAll() {
-		store := NewLogStore(entryType, storageBackend, db)
+		store, err := NewLogStore(entryType, storageBackend, db, queueDir)
+		if err != nil {
+			return nil, err
+		}
 		types[entryType.GetTypeName()] = store
 	}
 
@@ -24,7 +27,7 @@ func NewStoreTypeRegistry(entryTypeRegistry *entries.EntryTypeRegistry, storageB
 		storageBackend: storageBackend,
 		db:             db,
 		types:          types,
-	}
+	}, nil
 }
 
 func (r *StoreTypeRegistry) Get(typeName string) (*LogStore, bool) {
@@ -34,6 +37,6 @@ func (r *StoreTypeRegistry) Get(typeName string) (*LogStore, bool) {
 
 func (r *StoreTypeRegistry) Stop() {
 	for _, store := range r.types {
-		store.queue.Stop()
+		store.stopQueue()
 	}
 }
@@ -3,6 +3,8 @@ package store
 import (
 	"context"
 	"fmt"
+	"log"
+	"path/filepath"
 	"time"
 
 	memoryQueue "github.com/metorial/metorial/modules/memory-queue"
@@ -17,9 +19,13 @@ type LogStore struct {
 	storageBackend StorageBackend
 
 	queue *memoryQueue.JobQueue
+
+	// durableQueue is used instead of queue when a queue directory is
+	// configured, so logs aren't lost on restarts.
+	durableQueue *memoryQueue.DurableJobQueue[ingestJob]
 }
 
-func NewLogStore(entryType entries.EntryType, storageBackend StorageBackend, db *mongo.Database) *LogStore {
+func NewLogStore(entryType entries.EntryType, storageBackend StorageBackend, db *mongo.Database, queueDir string) (*LogStore, error) {
 	collection := db.Collection(fmt.Sprintf("logs_%s", entryType.GetTypeName()))
 
 	indexes := []mongo.IndexModel{
@@ -65,11 +71,35 @@ func NewLogStore(entryType entries.EntryType, storageBackend StorageBackend, db
 		collection:     collection,
 		entryType:      entryType,
 		storageBackend: storageBackend,
+	}
+
+	if queueDir != "" {
+		queuePath := filepath.Join(queueDir, fmt.Sprintf("%s.log", entryType.GetTypeName()))
 
-		queue: memoryQueue.NewJobQueue(50),
+		durableQueue, err := memoryQueue.NewDurableJobQueue(queuePath, res.ingest, memoryQueue.DefaultDurableOptions())
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log queue: %w", err)
+		}
+
+		if deadLetters, err := durableQueue.DeadLetters(); err == nil && len(deadLetters) > 0 {
+			log.Printf("Log queue %s has %d dead letters", queuePath, len(deadLetters))
+		}
+
+		res.durableQueue = durableQueue
+	} else {
+		res.queue = memoryQueue.NewJobQueue(50)
 	}
 
 	res.startCleanupRoutine()
 
-	return res
+	return res, nil
+}
+
+func (s *LogStore) stopQueue() {
+	if s.durableQueue != nil {
+		s.durableQueue.Stop()
+		return
+	}
+
+	s.queue.Stop()
 }
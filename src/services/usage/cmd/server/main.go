@@ -6,6 +6,8 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+	_ "time/tzdata" // Timeline time zones don't depend on the system's zoneinfo
 
 	"github.com/joho/godotenv"
 	sentryUtil "github.com/metorial/metorial/modules/sentry-util"
@@ -31,12 +33,31 @@ func main() {
 	mongoDb := getEnvOrDefault("USAGE_MONGO_DB", "usage")
 	mongoCollection := getEnvOrDefault("USAGE_MONGO_COLLECTION", "usage_records")
 
-	store, err := mongoStore.NewMongoStore(context.Background(), mongoURI, mongoDb, mongoCollection)
+	rawRetention := getDurationEnvOrDefault("USAGE_RAW_RETENTION", 30*24*time.Hour)
+
+	store, err := mongoStore.NewMongoStore(context.Background(), mongoURI, mongoDb, mongoCollection, rawRetention)
 	if err != nil {
 		log.Fatalf("Failed to create MongoDB store: %v", err)
 	}
 
-	repo := repository.NewRepository(store)
+	if getEnvOrDefault("USAGE_ROLLUPS", "true") != "false" {
+		rollupOptions := mongoStore.DefaultRollupOptions()
+		rollupOptions.MinuteRetention = getDurationEnvOrDefault("USAGE_MINUTE_RETENTION", rollupOptions.MinuteRetention)
+		rollupOptions.HourRetention = getDurationEnvOrDefault("USAGE_HOUR_RETENTION", rollupOptions.HourRetention)
+		rollupOptions.DayRetention = getDurationEnvOrDefault("USAGE_DAY_RETENTION", rollupOptions.DayRetention)
+
+		if err := store.StartRollups(context.Background(), rollupOptions); err != nil {
+			log.Fatalf("Failed to start usage rollups: %v", err)
+		}
+	}
+
+	repo, err := repository.NewRepository(store, repository.RepositoryOptions{
+		QueueDir:   os.Getenv("USAGE_QUEUE_DIR"),
+		JournalDir: os.Getenv("USAGE_JOURNAL_DIR"),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create usage repository: %v", err)
+	}
 
 	service := service.NewService(repo)
 
@@ -73,3 +94,18 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return value
 }
+
+// getDurationEnvOrDefault parses durations like "720h", "0" keeps data
+// forever where that is supported.
+func getDurationEnvOrDefault(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Environment variable %s must be a duration like 720h: %v", key, err)
+	}
+	return duration
+}
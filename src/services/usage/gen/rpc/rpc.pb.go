@@ -77,6 +77,8 @@ const (
 	IntervalUnit_interval_unit_minute      IntervalUnit = 1
 	IntervalUnit_interval_unit_hour        IntervalUnit = 2
 	IntervalUnit_interval_unit_day         IntervalUnit = 3
+	IntervalUnit_interval_unit_week        IntervalUnit = 4
+	IntervalUnit_interval_unit_month       IntervalUnit = 5
 )
 
 // Enum value maps for IntervalUnit.
@@ -86,12 +88,16 @@ var (
 		1: "interval_unit_minute",
 		2: "interval_unit_hour",
 		3: "interval_unit_day",
+		4: "interval_unit_week",
+		5: "interval_unit_month",
 	}
 	IntervalUnit_value = map[string]int32{
 		"interval_unit_unspecified": 0,
 		"interval_unit_minute":      1,
 		"interval_unit_hour":        2,
 		"interval_unit_day":         3,
+		"interval_unit_week":        4,
+		"interval_unit_month":       5,
 	}
 )
 
@@ -435,14 +441,17 @@ func (*IngestUsageRecordResponse) Descriptor() ([]byte, []int) {
 }
 
 type GetUsageTimelineRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Owners        []*Owner               `protobuf:"bytes,1,rep,name=owners,proto3" json:"owners,omitempty"`
-	EntityIds     []string               `protobuf:"bytes,2,rep,name=entity_ids,json=entityIds,proto3" json:"entity_ids,omitempty"`
-	EntityTypes   []string               `protobuf:"bytes,3,rep,name=entity_types,json=entityTypes,proto3" json:"entity_types,omitempty"`
-	EventTypes    []string               `protobuf:"bytes,7,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
-	From          int64                  `protobuf:"varint,4,opt,name=from,proto3" json:"from,omitempty"`
-	To            int64                  `protobuf:"varint,5,opt,name=to,proto3" json:"to,omitempty"`
-	Interval      *Interval              `protobuf:"bytes,6,opt,name=interval,proto3" json:"interval,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Owners      []*Owner               `protobuf:"bytes,1,rep,name=owners,proto3" json:"owners,omitempty"`
+	EntityIds   []string               `protobuf:"bytes,2,rep,name=entity_ids,json=entityIds,proto3" json:"entity_ids,omitempty"`
+	EntityTypes []string               `protobuf:"bytes,3,rep,name=entity_types,json=entityTypes,proto3" json:"entity_types,omitempty"`
+	EventTypes  []string               `protobuf:"bytes,7,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	From        int64                  `protobuf:"varint,4,opt,name=from,proto3" json:"from,omitempty"`
+	To          int64                  `protobuf:"varint,5,opt,name=to,proto3" json:"to,omitempty"`
+	Interval    *Interval              `protobuf:"bytes,6,opt,name=interval,proto3" json:"interval,omitempty"`
+	// IANA time zone, e.g. "Europe/Berlin", that days, weeks and months are
+	// aligned in. Defaults to UTC.
+	Timezone      string `protobuf:"bytes,8,opt,name=timezone,proto3" json:"timezone,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -526,6 +535,13 @@ func (x *GetUsageTimelineRequest) GetInterval() *Interval {
 	return nil
 }
 
+func (x *GetUsageTimelineRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
 type TimelineEntry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ts            int64                  `protobuf:"varint,1,opt,name=ts,proto3" json:"ts,omitempty"`
@@ -765,7 +781,7 @@ const file_rpc_proto_rawDesc = "" +
 	"\x18IngestUsageRecordRequest\x124\n" +
 	"\arecords\x18\x01 \x03(\v2\x1a.rpc.rpc.IngestUsageRecordR\arecords\x12\x0e\n" +
 	"\x02ts\x18\x02 \x01(\x03R\x02ts\"\x1b\n" +
-	"\x19IngestUsageRecordResponse\"\xf7\x01\n" +
+	"\x19IngestUsageRecordResponse\"\x93\x02\n" +
 	"\x17GetUsageTimelineRequest\x12&\n" +
 	"\x06owners\x18\x01 \x03(\v2\x0e.rpc.rpc.OwnerR\x06owners\x12\x1d\n" +
 	"\n" +
@@ -775,7 +791,8 @@ const file_rpc_proto_rawDesc = "" +
 	"eventTypes\x12\x12\n" +
 	"\x04from\x18\x04 \x01(\x03R\x04from\x12\x0e\n" +
 	"\x02to\x18\x05 \x01(\x03R\x02to\x12-\n" +
-	"\binterval\x18\x06 \x01(\v2\x11.rpc.rpc.IntervalR\binterval\"5\n" +
+	"\binterval\x18\x06 \x01(\v2\x11.rpc.rpc.IntervalR\binterval\x12\x1a\n" +
+	"\btimezone\x18\b \x01(\tR\btimezone\"5\n" +
 	"\rTimelineEntry\x12\x0e\n" +
 	"\x02ts\x18\x01 \x01(\x03R\x02ts\x12\x14\n" +
 	"\x05count\x18\x02 \x01(\x03R\x05count\"\x9b\x01\n" +
@@ -794,12 +811,14 @@ const file_rpc_proto_rawDesc = "" +
 	"\tOwnerType\x12\x1a\n" +
 	"\x16owner_type_unspecified\x10\x00\x12\x17\n" +
 	"\x13owner_type_instance\x10\x01\x12\x1b\n" +
-	"\x17owner_type_organization\x10\x02*v\n" +
+	"\x17owner_type_organization\x10\x02*\xa7\x01\n" +
 	"\fIntervalUnit\x12\x1d\n" +
 	"\x19interval_unit_unspecified\x10\x00\x12\x18\n" +
 	"\x14interval_unit_minute\x10\x01\x12\x16\n" +
 	"\x12interval_unit_hour\x10\x02\x12\x15\n" +
-	"\x11interval_unit_day\x10\x032\xc3\x01\n" +
+	"\x11interval_unit_day\x10\x03\x12\x16\n" +
+	"\x12interval_unit_week\x10\x04\x12\x17\n" +
+	"\x13interval_unit_month\x10\x052\xc3\x01\n" +
 	"\fUsageService\x12Z\n" +
 	"\x11IngestUsageRecord\x12!.rpc.rpc.IngestUsageRecordRequest\x1a\".rpc.rpc.IngestUsageRecordResponse\x12W\n" +
 	"\x10GetUsageTimeline\x12 .rpc.rpc.GetUsageTimelineRequest\x1a!.rpc.rpc.GetUsageTimelineResponseB7Z5github.com/metorial/metorial/services/rpc/gen/rpc;rpcb\x06proto3"
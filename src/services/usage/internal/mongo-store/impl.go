@@ -2,15 +2,19 @@ package mongoStore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/metorial/metorial/modules/util"
 	"github.com/metorial/metorial/services/usage/internal/repository"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const duplicateKeyCode = 11000
+
 type AggregationResult struct {
 	ID struct {
 		OwnerID    string    `bson:"ownerId"`
@@ -23,20 +27,72 @@ type AggregationResult struct {
 }
 
 type UsageRecord struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	OwnerID    string             `bson:"ownerId" json:"ownerId"`
-	EntityID   string             `bson:"entityId" json:"entityId"`
-	EntityType string             `bson:"entityType" json:"entityType"`
-	Count      int64              `bson:"count" json:"count"`
-	Type       string             `bson:"type" json:"type"`
-	Timestamp  time.Time          `bson:"ts" json:"ts"`
+	ID         string    `bson:"_id,omitempty" json:"id"`
+	OwnerID    string    `bson:"ownerId" json:"ownerId"`
+	EntityID   string    `bson:"entityId" json:"entityId"`
+	EntityType string    `bson:"entityType" json:"entityType"`
+	Count      int64     `bson:"count" json:"count"`
+	Type       string    `bson:"type" json:"type"`
+	Timestamp  time.Time `bson:"ts" json:"ts"`
+
+	// InsertedAt is when the record was stored, which rollups pick up
+	// records by
+	InsertedAt time.Time `bson:"insertedAt" json:"insertedAt"`
 }
 
 func (m *MongoStore) GetUsageTimeline(ctx context.Context, opts repository.TimelineOptions) ([]repository.AggregationResult, error) {
 	// Adjust time boundaries based on interval
-	from, to := repository.AdjustTimeBoundaries(opts.From, opts.To, opts.Interval.Unit)
-	intervalMs := repository.CalculateIntervalMs(opts.Interval)
+	from, to := repository.AdjustTimeBoundaries(opts.From, opts.To, opts.Interval, opts.Location)
+
+	tier := m.selectRollupTier(opts, from, to)
+	if tier == nil {
+		results, err := aggregateTimeline(ctx, m.collection, opts, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		return aggregationResultsToAggregationResults(results), nil
+	}
+
+	// Rolled up buckets come from the tier, the ones after it from the
+	// raw records
+	split, err := m.rollupWatermark(ctx, tier)
+	if err != nil {
+		return nil, err
+	}
+	if split.Before(from) {
+		split = from
+	}
+	if split.After(to) {
+		split = to
+	}
+
+	var results []AggregationResult
 
+	if split.After(from) {
+		tierResults, err := aggregateTimeline(ctx, m.rollupCollection(tier), opts, from, split)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, tierResults...)
+	}
+
+	if to.After(split) {
+		rawResults, err := aggregateTimeline(ctx, m.collection, opts, split, to)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, rawResults...)
+	}
+
+	return aggregationResultsToAggregationResults(mergeAggregationResults(results)), nil
+}
+
+// aggregateTimeline sums the counts of usage records, raw or rolled up, in
+// [from, to) per interval.
+func aggregateTimeline(ctx context.Context, collection *mongo.Collection, opts repository.TimelineOptions, from, to time.Time) ([]AggregationResult, error) {
 	// Build match stage
 	matchStage := bson.M{
 		"ts": bson.M{
@@ -63,26 +119,7 @@ func (m *MongoStore) GetUsageTimeline(ctx context.Context, opts repository.Timel
 			"entityId":   "$entityId",
 			"entityType": "$entityType",
 			"type":       "$type",
-			"ts": bson.M{
-				"$add": []interface{}{
-					bson.M{
-						"$subtract": []interface{}{
-							bson.M{
-								"$subtract": []interface{}{"$ts", primitive.NewDateTimeFromTime(time.Unix(0, 0))},
-							},
-							bson.M{
-								"$mod": []interface{}{
-									bson.M{
-										"$subtract": []interface{}{"$ts", primitive.NewDateTimeFromTime(time.Unix(0, 0))},
-									},
-									intervalMs,
-								},
-							},
-						},
-					},
-					primitive.NewDateTimeFromTime(time.Unix(0, 0)),
-				},
-			},
+			"ts":         dateTrunc(opts.Interval, opts.Location),
 		},
 		"count": bson.M{"$sum": "$count"},
 	}
@@ -93,7 +130,7 @@ func (m *MongoStore) GetUsageTimeline(ctx context.Context, opts repository.Timel
 		{"$group": groupStage},
 	}
 
-	cursor, err := m.collection.Aggregate(ctx, pipeline)
+	cursor, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute aggregation: %w", err)
 	}
@@ -104,36 +141,106 @@ func (m *MongoStore) GetUsageTimeline(ctx context.Context, opts repository.Timel
 		return nil, fmt.Errorf("failed to decode aggregation results: %w", err)
 	}
 
-	return aggregationResultsToAggregationResults(results), nil
+	return results, nil
+}
+
+// mergeAggregationResults sums results of the same interval, which are
+// split when an interval is partly rolled up.
+func mergeAggregationResults(results []AggregationResult) []AggregationResult {
+	merged := make([]AggregationResult, 0, len(results))
+	indexes := make(map[string]int, len(results))
+
+	for _, result := range results {
+		key := fmt.Sprintf("%s:%s:%s:%s:%d", result.ID.OwnerID, result.ID.EntityType, result.ID.EntityID, result.ID.Type, result.ID.Timestamp.UnixMilli())
+
+		if i, ok := indexes[key]; ok {
+			merged[i].Count += result.Count
+			continue
+		}
+
+		indexes[key] = len(merged)
+		merged = append(merged, result)
+	}
+
+	return merged
 }
 
-func (m *MongoStore) IngestUsage(ctx context.Context, records []*repository.UsageRecord) error {
-	if len(records) == 0 {
+// IngestUsage inserts the records of a batch under IDs derived from the
+// batch, so records of a batch that is stored again are skipped.
+func (m *MongoStore) IngestUsage(ctx context.Context, batch repository.UsageBatch) error {
+	if len(batch.Records) == 0 {
 		return nil
 	}
 
-	mongoRecords := make([]interface{}, 0, len(records))
-	for _, record := range records {
+	insertedAt := time.Now()
+
+	mongoRecords := make([]interface{}, 0, len(batch.Records))
+	for _, record := range batch.Records {
 		mongoRecords = append(mongoRecords, UsageRecord{
+			ID:         batch.RecordID(record),
 			OwnerID:    record.OwnerID,
 			EntityID:   record.EntityID,
 			EntityType: record.EntityType,
 			Count:      record.Count,
 			Type:       record.EventType,
 			Timestamp:  record.Timestamp,
+			InsertedAt: insertedAt,
 		})
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	if _, err := m.collection.InsertMany(ctx, mongoRecords); err != nil {
+	// Unordered, so records after one that was stored before are inserted
+	_, err := m.collection.InsertMany(ctx, mongoRecords, options.InsertMany().SetOrdered(false))
+	if err != nil && !onlyDuplicateKeyErrors(err) {
 		return fmt.Errorf("failed to insert usage records: %w", err)
 	}
 
 	return nil
 }
 
+// onlyDuplicateKeyErrors reports whether all records an insert failed on
+// were stored before.
+func onlyDuplicateKeyErrors(err error) bool {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil || len(bulkErr.WriteErrors) == 0 {
+		return false
+	}
+
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Code != duplicateKeyCode {
+			return false
+		}
+	}
+
+	return true
+}
+
+// dateTrunc groups timestamps into intervals aligned the same way as
+// repository.TruncateTime. It needs MongoDB 5.0 or later.
+func dateTrunc(interval repository.IntervalConfig, loc *time.Location) bson.M {
+	timezone := "UTC"
+	if loc != nil {
+		timezone = loc.String()
+	}
+
+	binSize := interval.Count
+	if binSize < 1 {
+		binSize = 1
+	}
+
+	return bson.M{
+		"$dateTrunc": bson.M{
+			"date":        "$ts",
+			"unit":        string(interval.Unit),
+			"binSize":     binSize,
+			"timezone":    timezone,
+			"startOfWeek": "monday",
+		},
+	}
+}
+
 func aggregationResultsToAggregationResults(results []AggregationResult) []repository.AggregationResult {
 	return util.Map(results, func(r AggregationResult) repository.AggregationResult {
 		return repository.AggregationResult{
@@ -0,0 +1,32 @@
+package mongoStore
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestOnlyDuplicateKeyErrors(t *testing.T) {
+	duplicate := mongo.BulkWriteError{WriteError: mongo.WriteError{Code: duplicateKeyCode}}
+	other := mongo.BulkWriteError{WriteError: mongo.WriteError{Code: 121}}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"duplicates", mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{duplicate, duplicate}}, true},
+		{"wrapped duplicates", errors.Join(errors.New("insert"), mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{duplicate}}), true},
+		{"other write error", mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{duplicate, other}}, false},
+		{"write concern", mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{duplicate}, WriteConcernError: &mongo.WriteConcernError{}}, false},
+		{"no write errors", mongo.BulkWriteException{}, false},
+		{"network", errors.New("connection reset"), false},
+	}
+
+	for _, test := range tests {
+		if got := onlyDuplicateKeyErrors(test.err); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
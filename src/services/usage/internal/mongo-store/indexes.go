@@ -2,32 +2,104 @@ package mongoStore
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func createIndexes(ctx context.Context, collection *mongo.Collection) error {
+const (
+	indexOptionsConflictCode  = 85
+	indexKeySpecsConflictCode = 86
+)
+
+func createIndexes(ctx context.Context, collection *mongo.Collection, retention time.Duration) error {
 	indexes := []mongo.IndexModel{
 		{
 			Keys: bson.D{{Key: "entityId", Value: 1}},
 		},
 		{
-			Keys:    bson.D{{Key: "ts", Value: 1}},
-			Options: options.Index().SetExpireAfterSeconds(60 * 60 * 24 * 30), // 30 days TTL
+			Keys: bson.D{
+				{Key: "ownerId", Value: 1},
+				{Key: "entityId", Value: 1},
+				{Key: "entityType", Value: 1},
+				{Key: "type", Value: 1},
+			},
 		},
 		{
+			// Rollups pick up records by when they were stored
+			Keys: bson.D{{Key: rawChangeField, Value: 1}},
+		},
+	}
+
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		return err
+	}
+
+	return ensureTTLIndex(ctx, collection, retention)
+}
+
+func createRollupIndexes(ctx context.Context, collection *mongo.Collection, retention time.Duration) error {
+	indexes := []mongo.IndexModel{
+		{
+			// Buckets are upserted on these fields
 			Keys: bson.D{
 				{Key: "ownerId", Value: 1},
 				{Key: "entityId", Value: 1},
 				{Key: "entityType", Value: 1},
 				{Key: "type", Value: 1},
+				{Key: "ts", Value: 1},
 			},
+			Options: options.Index().SetUnique(true),
 		},
+		{
+			Keys: bson.D{
+				{Key: "type", Value: 1},
+				{Key: "ts", Value: 1},
+			},
+		},
+		{
+			// The next tier picks up buckets by when they were recomputed
+			Keys: bson.D{{Key: bucketChangeField, Value: 1}},
+		},
+	}
+
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		return err
 	}
 
-	_, err := collection.Indexes().CreateMany(ctx, indexes)
+	if retention <= 0 {
+		return nil
+	}
+
+	return ensureTTLIndex(ctx, collection, retention)
+}
+
+// ensureTTLIndex expires documents after retention, changing the
+// retention of an existing TTL index if needed.
+func ensureTTLIndex(ctx context.Context, collection *mongo.Collection, retention time.Duration) error {
+	seconds := int32(retention / time.Second)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "ts", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(seconds),
+	})
+	if err == nil {
+		return nil
+	}
+
+	var cmdErr mongo.CommandError
+	if !errors.As(err, &cmdErr) || (cmdErr.Code != indexOptionsConflictCode && cmdErr.Code != indexKeySpecsConflictCode) {
+		return err
+	}
 
-	return err
+	return collection.Database().RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: collection.Name()},
+		{Key: "index", Value: bson.D{
+			{Key: "keyPattern", Value: bson.D{{Key: "ts", Value: 1}}},
+			{Key: "expireAfterSeconds", Value: seconds},
+		}},
+	}).Err()
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync/atomic"
+	"time"
 
 	"github.com/metorial/metorial/services/usage/internal/repository"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -16,9 +18,25 @@ type MongoStore struct {
 	client     *mongo.Client
 	database   *mongo.Database
 	collection *mongo.Collection
+
+	// rawRetention is how long raw usage records are kept
+	rawRetention time.Duration
+
+	rollupOptions  RollupOptions
+	rollupsEnabled atomic.Bool
+	stopRollups    chan struct{}
+	rollupsDone    chan struct{}
 }
 
-func NewMongoStore(ctx context.Context, mongoURL, dbName, collectionName string) (*MongoStore, error) {
+const defaultRawRetention = 30 * 24 * time.Hour
+
+// NewMongoStore connects to the store. Raw usage records are kept for
+// rawRetention, or 30 days if it is zero.
+func NewMongoStore(ctx context.Context, mongoURL, dbName, collectionName string, rawRetention time.Duration) (*MongoStore, error) {
+	if rawRetention <= 0 {
+		rawRetention = defaultRawRetention
+	}
+
 	clientOptions := options.Client().ApplyURI(mongoURL)
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
@@ -33,7 +51,7 @@ func NewMongoStore(ctx context.Context, mongoURL, dbName, collectionName string)
 	database := client.Database(dbName)
 	collection := database.Collection(collectionName)
 
-	err = createIndexes(ctx, collection)
+	err = createIndexes(ctx, collection, rawRetention)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create indexes: %w", err)
 	}
@@ -44,10 +62,14 @@ func NewMongoStore(ctx context.Context, mongoURL, dbName, collectionName string)
 		client:     client,
 		database:   database,
 		collection: collection,
+
+		rawRetention: rawRetention,
 	}, nil
 }
 
 func (m *MongoStore) Close(ctx context.Context) error {
+	m.stopRollupRoutine()
+
 	if m.client != nil {
 		return m.client.Disconnect(ctx)
 	}
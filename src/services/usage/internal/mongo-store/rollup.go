@@ -0,0 +1,494 @@
+package mongoStore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/metorial/metorial/services/usage/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RollupOptions struct {
+	// Interval is how often rollups run.
+	Interval time.Duration
+
+	// Delay is how long after its end a bucket is read from its tier
+	// rather than from its source, so records that are stored late are
+	// rolled up before it is. Records stored later than that are rolled
+	// up into the bucket with the next rollup.
+	Delay time.Duration
+
+	// Retention of the tiers, zero keeps a tier forever. Raw records are
+	// expired after the retention passed to NewMongoStore.
+	MinuteRetention time.Duration
+	HourRetention   time.Duration
+	DayRetention    time.Duration
+}
+
+func DefaultRollupOptions() RollupOptions {
+	return RollupOptions{
+		Interval:        time.Minute,
+		Delay:           5 * time.Minute,
+		MinuteRetention: 7 * 24 * time.Hour,
+		HourRetention:   90 * 24 * time.Hour,
+	}
+}
+
+// rollupTier is a collection of usage counts summed per bucket of unit.
+// Tiers are rolled up from the tier before them, the first one from the
+// raw records. Buckets are aligned in UTC. Every rollup recomputes the
+// buckets of the source documents that changed since the last one, which
+// raw records do when they are inserted and buckets when they are
+// recomputed.
+type rollupTier struct {
+	name   string
+	unit   repository.IntervalConfigUnit
+	length time.Duration
+	source *rollupTier
+
+	// maxWindow limits how much is rolled up in a single aggregation
+	maxWindow time.Duration
+}
+
+var (
+	minuteTier = &rollupTier{name: "minute", unit: repository.IntervalUnitMinute, length: time.Minute, maxWindow: 6 * time.Hour}
+	hourTier   = &rollupTier{name: "hour", unit: repository.IntervalUnitHour, length: time.Hour, source: minuteTier, maxWindow: 7 * 24 * time.Hour}
+	dayTier    = &rollupTier{name: "day", unit: repository.IntervalUnitDay, length: 24 * time.Hour, source: hourTier, maxWindow: 90 * 24 * time.Hour}
+
+	rollupTiers = []*rollupTier{minuteTier, hourTier, dayTier}
+)
+
+var intervalUnitRanks = map[repository.IntervalConfigUnit]int{
+	repository.IntervalUnitMinute: 0,
+	repository.IntervalUnitHour:   1,
+	repository.IntervalUnitDay:    2,
+	repository.IntervalUnitWeek:   3,
+	repository.IntervalUnitMonth:  4,
+}
+
+type rollupState struct {
+	Tier string `bson:"_id"`
+
+	// Until is the end of the buckets that are complete in the tier
+	Until time.Time `bson:"until"`
+
+	// ChangedUntil is the time up to which changes of the source are
+	// rolled up
+	ChangedUntil time.Time `bson:"changedUntil"`
+}
+
+const (
+	rawChangeField    = "insertedAt"
+	bucketChangeField = "updatedAt"
+)
+
+func (o RollupOptions) retention(tier *rollupTier) time.Duration {
+	switch tier {
+	case minuteTier:
+		return o.MinuteRetention
+	case hourTier:
+		return o.HourRetention
+	default:
+		return o.DayRetention
+	}
+}
+
+// validate checks that records are kept long enough to be rolled up into
+// the next tier.
+func (o RollupOptions) validate(rawRetention time.Duration) error {
+	if rawRetention <= o.Delay+minuteTier.length {
+		return fmt.Errorf("raw retention must be longer than the rollup delay and a minute")
+	}
+
+	for _, tier := range rollupTiers {
+		if tier.source == nil {
+			continue
+		}
+
+		retention := o.retention(tier.source)
+		if retention > 0 && retention <= o.Delay+tier.length {
+			return fmt.Errorf("%s retention must be longer than the rollup delay and a %s", tier.source.name, tier.name)
+		}
+	}
+
+	return nil
+}
+
+func (m *MongoStore) rollupCollection(tier *rollupTier) *mongo.Collection {
+	return m.database.Collection(fmt.Sprintf("%s_%s", m.collection.Name(), tier.name))
+}
+
+func (m *MongoStore) rollupStateCollection() *mongo.Collection {
+	return m.database.Collection(fmt.Sprintf("%s_rollups", m.collection.Name()))
+}
+
+func (m *MongoStore) sourceCollection(tier *rollupTier) *mongo.Collection {
+	if tier.source == nil {
+		return m.collection
+	}
+
+	return m.rollupCollection(tier.source)
+}
+
+// sourceChangeField is the field holding the time a source document of
+// the tier changed.
+func sourceChangeField(tier *rollupTier) string {
+	if tier.source == nil {
+		return rawChangeField
+	}
+
+	return bucketChangeField
+}
+
+func (m *MongoStore) sourceRetention(tier *rollupTier) time.Duration {
+	if tier.source == nil {
+		return m.rawRetention
+	}
+
+	return m.rollupOptions.retention(tier.source)
+}
+
+// StartRollups creates the rollup tiers and starts rolling up usage records
+// in the background. Timelines are only read from the tiers once rollups
+// are started.
+func (m *MongoStore) StartRollups(ctx context.Context, opts RollupOptions) error {
+	defaults := DefaultRollupOptions()
+	if opts.Interval <= 0 {
+		opts.Interval = defaults.Interval
+	}
+
+	if err := opts.validate(m.rawRetention); err != nil {
+		return err
+	}
+
+	for _, tier := range rollupTiers {
+		if err := createRollupIndexes(ctx, m.rollupCollection(tier), opts.retention(tier)); err != nil {
+			return fmt.Errorf("failed to create %s rollup indexes: %w", tier.name, err)
+		}
+	}
+
+	m.rollupOptions = opts
+	m.stopRollups = make(chan struct{})
+	m.rollupsDone = make(chan struct{})
+	m.rollupsEnabled.Store(true)
+
+	go m.rollupRoutine()
+
+	return nil
+}
+
+func (m *MongoStore) rollupRoutine() {
+	defer close(m.rollupsDone)
+
+	ticker := time.NewTicker(m.rollupOptions.Interval)
+	defer ticker.Stop()
+
+	for {
+		m.runRollups()
+
+		select {
+		case <-ticker.C:
+		case <-m.stopRollups:
+			return
+		}
+	}
+}
+
+func (m *MongoStore) runRollups() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-m.stopRollups:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// Buckets are stamped with the time of the run, which is a whole
+	// number of milliseconds like all times stored in MongoDB
+	now := time.Now().Truncate(time.Millisecond)
+
+	for _, tier := range rollupTiers {
+		if err := m.rollUp(ctx, tier, now); err != nil {
+			// Later tiers are rolled up from this one, so they wait for it
+			log.Printf("Failed to roll up %s usage: %v", tier.name, err)
+			return
+		}
+	}
+}
+
+func truncateToTier(t time.Time, tier *rollupTier) time.Time {
+	return repository.TruncateTime(t, repository.IntervalConfig{Unit: tier.unit, Count: 1}, time.UTC)
+}
+
+// rollUp recomputes the buckets of a tier whose source documents changed
+// since the last rollup, and marks the buckets that are complete in its
+// source as readable from the tier.
+func (m *MongoStore) rollUp(ctx context.Context, tier *rollupTier, now time.Time) error {
+	end := truncateToTier(now.Add(-m.rollupOptions.Delay), tier)
+
+	// Raw records are inserted with the time of the service storing them,
+	// which gets the delay to reach the database. Source buckets changed
+	// in this run at the latest, so all of them are rolled up.
+	changedEnd := now.Add(-m.rollupOptions.Delay)
+
+	if tier.source != nil {
+		sourceState, err := m.rollupState(ctx, tier.source)
+		if err != nil {
+			return err
+		}
+
+		if sourceEnd := truncateToTier(sourceState.Until, tier); sourceEnd.Before(end) {
+			end = sourceEnd
+		}
+
+		changedEnd = now.Add(time.Millisecond)
+	}
+
+	state, err := m.rollupState(ctx, tier)
+	if err != nil {
+		return err
+	}
+
+	start := state.ChangedUntil
+	if start.IsZero() {
+		// Before changes were tracked, tiers were rolled up by bucket up to
+		// Until
+		start = state.Until
+	}
+
+	if start.IsZero() {
+		earliest, err := m.earliestRecord(ctx, m.sourceCollection(tier))
+		if err != nil || earliest.IsZero() {
+			return err
+		}
+
+		start = truncateToTier(earliest, tier)
+	}
+
+	for start.Before(changedEnd) {
+		windowEnd := start.Add(tier.maxWindow)
+		if windowEnd.After(changedEnd) {
+			windowEnd = changedEnd
+		}
+
+		if err := m.rollUpWindow(ctx, tier, start, windowEnd, now); err != nil {
+			return err
+		}
+
+		update := bson.M{"changedUntil": windowEnd}
+		if windowEnd.Equal(changedEnd) && end.After(state.Until) {
+			update["until"] = end
+		}
+
+		if err := m.setRollupState(ctx, tier, update); err != nil {
+			return err
+		}
+
+		start = windowEnd
+	}
+
+	return nil
+}
+
+// rollUpWindow recomputes the buckets of a tier that source documents
+// changed in [from, to) fall into.
+func (m *MongoStore) rollUpWindow(ctx context.Context, tier *rollupTier, from, to, now time.Time) error {
+	pipeline := rollupPipeline(tier, m.sourceCollection(tier).Name(), m.rollupCollection(tier).Name(), from, to, now, m.sourceRetention(tier))
+
+	cursor, err := m.sourceCollection(tier).Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("failed to aggregate %s rollup: %w", tier.name, err)
+	}
+	cursor.Close(ctx)
+
+	return nil
+}
+
+// rollupPipeline finds the buckets that source documents changed in
+// [from, to) fall into and replaces them with the sum of all their
+// source documents. Buckets whose source documents may have expired
+// already are skipped, they would be replaced with a partial sum.
+func rollupPipeline(tier *rollupTier, source, into string, from, to, now time.Time, sourceRetention time.Duration) []bson.M {
+	changeField := sourceChangeField(tier)
+
+	match := bson.M{
+		"$or": []bson.M{
+			{changeField: bson.M{"$gte": from, "$lt": to}},
+			// Documents stored before changes were tracked
+			{changeField: bson.M{"$exists": false}, "ts": bson.M{"$gte": from, "$lt": to}},
+		},
+	}
+	if sourceRetention > 0 {
+		match["ts"] = bson.M{"$gte": truncateToTier(now.Add(-sourceRetention), tier).Add(tier.length)}
+	}
+
+	bucket := repository.IntervalConfig{Unit: tier.unit, Count: 1}
+
+	return []bson.M{
+		{"$match": match},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"ownerId":    "$ownerId",
+				"entityId":   "$entityId",
+				"entityType": "$entityType",
+				"type":       "$type",
+				"ts":         dateTrunc(bucket, time.UTC),
+			},
+		}},
+		{"$lookup": bson.M{
+			"from": source,
+			"let": bson.M{
+				"ownerId":    "$_id.ownerId",
+				"entityId":   "$_id.entityId",
+				"entityType": "$_id.entityType",
+				"type":       "$_id.type",
+				"start":      "$_id.ts",
+			},
+			"pipeline": []bson.M{
+				{"$match": bson.M{"$expr": bson.M{"$and": []bson.M{
+					{"$eq": []string{"$ownerId", "$$ownerId"}},
+					{"$eq": []string{"$entityId", "$$entityId"}},
+					{"$eq": []string{"$entityType", "$$entityType"}},
+					{"$eq": []string{"$type", "$$type"}},
+					{"$gte": []string{"$ts", "$$start"}},
+					{"$lt": []interface{}{"$ts", bson.M{"$dateAdd": bson.M{"startDate": "$$start", "unit": string(tier.unit), "amount": 1}}}},
+				}}}},
+				{"$group": bson.M{"_id": nil, "count": bson.M{"$sum": "$count"}}},
+			},
+			"as": "total",
+		}},
+		{"$project": bson.M{
+			"_id":        0,
+			"ownerId":    "$_id.ownerId",
+			"entityId":   "$_id.entityId",
+			"entityType": "$_id.entityType",
+			"type":       "$_id.type",
+			"ts":         "$_id.ts",
+			"count":      bson.M{"$ifNull": []interface{}{bson.M{"$first": "$total.count"}, 0}},
+			"updatedAt":  now,
+		}},
+		{"$merge": bson.M{
+			"into":           into,
+			"on":             []string{"ownerId", "entityId", "entityType", "type", "ts"},
+			"whenMatched":    "replace",
+			"whenNotMatched": "insert",
+		}},
+	}
+}
+
+func (m *MongoStore) setRollupState(ctx context.Context, tier *rollupTier, update bson.M) error {
+	_, err := m.rollupStateCollection().UpdateOne(
+		ctx,
+		bson.M{"_id": tier.name},
+		bson.M{"$set": update},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store %s rollup state: %w", tier.name, err)
+	}
+
+	return nil
+}
+
+// rollupWatermark returns the time up to which the buckets of a tier are
+// complete, or zero if there are none yet.
+func (m *MongoStore) rollupWatermark(ctx context.Context, tier *rollupTier) (time.Time, error) {
+	state, err := m.rollupState(ctx, tier)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return state.Until, nil
+}
+
+func (m *MongoStore) rollupState(ctx context.Context, tier *rollupTier) (rollupState, error) {
+	var state rollupState
+
+	err := m.rollupStateCollection().FindOne(ctx, bson.M{"_id": tier.name}).Decode(&state)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return rollupState{Tier: tier.name}, nil
+	}
+	if err != nil {
+		return rollupState{}, fmt.Errorf("failed to get %s rollup state: %w", tier.name, err)
+	}
+
+	return state, nil
+}
+
+func (m *MongoStore) earliestRecord(ctx context.Context, collection *mongo.Collection) (time.Time, error) {
+	var record struct {
+		Timestamp time.Time `bson:"ts"`
+	}
+
+	err := collection.FindOne(
+		ctx,
+		bson.M{},
+		options.FindOne().SetSort(bson.D{{Key: "ts", Value: 1}}).SetProjection(bson.M{"ts": 1}),
+	).Decode(&record)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get earliest usage record: %w", err)
+	}
+
+	return record.Timestamp, nil
+}
+
+// selectRollupTier returns the coarsest tier whose buckets the requested
+// intervals are made of, or nil if the raw records have to be used.
+func (m *MongoStore) selectRollupTier(opts repository.TimelineOptions, from, to time.Time) *rollupTier {
+	if !m.rollupsEnabled.Load() {
+		return nil
+	}
+
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	rank, ok := intervalUnitRanks[opts.Interval.Unit]
+	if !ok {
+		return nil
+	}
+
+	for i := len(rollupTiers) - 1; i >= 0; i-- {
+		tier := rollupTiers[i]
+		if intervalUnitRanks[tier.unit] > rank {
+			continue
+		}
+
+		// Tier buckets are aligned in UTC, so the time zone's offset has
+		// to be a whole number of them at both ends of the range
+		aligned := true
+		for _, ts := range []time.Time{from, to} {
+			_, offset := ts.In(loc).Zone()
+			if time.Duration(offset)*time.Second%tier.length != 0 {
+				aligned = false
+			}
+		}
+
+		if aligned {
+			return tier
+		}
+	}
+
+	return nil
+}
+
+func (m *MongoStore) stopRollupRoutine() {
+	if !m.rollupsEnabled.CompareAndSwap(true, false) {
+		return
+	}
+
+	close(m.stopRollups)
+	<-m.rollupsDone
+}
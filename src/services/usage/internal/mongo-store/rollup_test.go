@@ -0,0 +1,153 @@
+package mongoStore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metorial/metorial/services/usage/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSelectRollupTier(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	store := &MongoStore{}
+	store.rollupsEnabled.Store(true)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		unit repository.IntervalConfigUnit
+		loc  *time.Location
+		want *rollupTier
+	}{
+		{"minute", repository.IntervalUnitMinute, time.UTC, minuteTier},
+		{"hour", repository.IntervalUnitHour, time.UTC, hourTier},
+		{"day", repository.IntervalUnitDay, nil, dayTier},
+		{"month", repository.IntervalUnitMonth, time.UTC, dayTier},
+		{"day in whole hour offset", repository.IntervalUnitDay, berlin, hourTier},
+		{"day in half hour offset", repository.IntervalUnitDay, kolkata, minuteTier},
+	}
+
+	for _, test := range tests {
+		opts := repository.TimelineOptions{
+			Interval: repository.IntervalConfig{Unit: test.unit, Count: 1},
+			Location: test.loc,
+		}
+
+		if got := store.selectRollupTier(opts, from, to); got != test.want {
+			t.Errorf("%s: got tier %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	disabled := &MongoStore{}
+	opts := repository.TimelineOptions{Interval: repository.IntervalConfig{Unit: repository.IntervalUnitDay, Count: 1}}
+	if got := disabled.selectRollupTier(opts, from, to); got != nil {
+		t.Errorf("expected raw records without rollups, got %v", got)
+	}
+}
+
+func TestRollupOptionsValidate(t *testing.T) {
+	options := DefaultRollupOptions()
+	if err := options.validate(30 * 24 * time.Hour); err != nil {
+		t.Errorf("default options should be valid: %v", err)
+	}
+
+	if err := options.validate(time.Minute); err == nil {
+		t.Error("raw records expiring before they are rolled up should be invalid")
+	}
+
+	options.MinuteRetention = 30 * time.Minute
+	if err := options.validate(30 * 24 * time.Hour); err == nil {
+		t.Error("minute buckets expiring before they are rolled up should be invalid")
+	}
+
+	options.MinuteRetention = 0
+	if err := options.validate(30 * 24 * time.Hour); err != nil {
+		t.Errorf("tiers kept forever should be valid: %v", err)
+	}
+}
+
+func TestMergeAggregationResults(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	result := func(entityType string, count int64) AggregationResult {
+		var res AggregationResult
+		res.ID.OwnerID = "owner"
+		res.ID.EntityID = "entity"
+		res.ID.EntityType = entityType
+		res.ID.Type = "call"
+		res.ID.Timestamp = ts
+		res.Count = count
+		return res
+	}
+
+	merged := mergeAggregationResults([]AggregationResult{
+		result("server", 5),
+		result("session", 1),
+		result("server", 2),
+	})
+
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(merged))
+	}
+	if merged[0].Count != 7 || merged[1].Count != 1 {
+		t.Errorf("unexpected counts: %d, %d", merged[0].Count, merged[1].Count)
+	}
+}
+
+func TestRollupPipeline(t *testing.T) {
+	from := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	to := from.Add(time.Minute)
+	now := to.Add(5 * time.Minute)
+
+	tests := []struct {
+		name        string
+		tier        *rollupTier
+		retention   time.Duration
+		changeField string
+		minTs       time.Time
+	}{
+		{"raw records", minuteTier, 30 * 24 * time.Hour, "insertedAt", time.Date(2024, 1, 31, 12, 7, 0, 0, time.UTC)},
+		{"minute buckets", hourTier, 7 * 24 * time.Hour, "updatedAt", time.Date(2024, 2, 23, 13, 0, 0, 0, time.UTC)},
+		{"kept forever", dayTier, 0, "updatedAt", time.Time{}},
+	}
+
+	for _, test := range tests {
+		pipeline := rollupPipeline(test.tier, "source", "into", from, to, now, test.retention)
+
+		match := pipeline[0]["$match"].(bson.M)
+		changed := match["$or"].([]bson.M)[0][test.changeField].(bson.M)
+		if changed["$gte"] != from || changed["$lt"] != to {
+			t.Errorf("%s: expected documents changed in the window to be matched, got %v", test.name, match)
+		}
+
+		ts, guarded := match["ts"].(bson.M)
+		if test.minTs.IsZero() {
+			if guarded {
+				t.Errorf("%s: expected no retention guard, got %v", test.name, ts)
+			}
+		} else if !guarded || ts["$gte"] != test.minTs {
+			t.Errorf("%s: expected buckets from %v on, got %v", test.name, test.minTs, ts)
+		}
+
+		lookup := pipeline[2]["$lookup"].(bson.M)
+		if lookup["from"] != "source" {
+			t.Errorf("%s: expected buckets to be recomputed from the source, got %v", test.name, lookup["from"])
+		}
+
+		project := pipeline[3]["$project"].(bson.M)
+		if project["updatedAt"] != now {
+			t.Errorf("%s: expected buckets to be stamped with the run, got %v", test.name, project["updatedAt"])
+		}
+	}
+}
@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"time"
@@ -16,53 +19,170 @@ type UsageRecord struct {
 	Timestamp  time.Time
 }
 
+func (r UsageRecord) cacheKey() string {
+	return fmt.Sprintf("%s:%s:%s:%s", r.OwnerID, r.EntityType, r.EntityID, r.EventType)
+}
+
+// UsageBatch is a set of increments that is stored at once. A batch keeps
+// its ID when it is retried, so stores can tell records they already
+// stored from new ones.
+type UsageBatch struct {
+	ID      string
+	Records []*UsageRecord
+
+	// JournalPath is the journal file holding the increments, removed
+	// once the batch is stored.
+	JournalPath string
+}
+
+func newBatchID() string {
+	random := make([]byte, 8)
+	rand.Read(random)
+
+	return fmt.Sprintf("%016x-%s", time.Now().UnixNano(), hex.EncodeToString(random))
+}
+
+// RecordID identifies a record of the batch. Records of a batch have
+// distinct keys, so a record gets the same ID every time the batch is
+// stored.
+func (b UsageBatch) RecordID(record *UsageRecord) string {
+	sum := sha256.Sum256([]byte(b.ID + "\x00" + record.cacheKey()))
+	return hex.EncodeToString(sum[:])
+}
+
 func (r *Repository) IngestUsage(record UsageRecord) {
-	hash := fmt.Sprintf("%s:%s:%s", record.OwnerID, record.EntityID, record.EventType)
+	record.Timestamp = time.Now()
 
 	r.cacheMutex.Lock()
 	defer r.cacheMutex.Unlock()
 
-	if existing, ok := r.usageCache[hash]; ok {
+	if r.journal != nil {
+		if err := r.journal.append(record); err != nil {
+			log.Printf("Failed to journal usage record: %v", err)
+		}
+	}
+
+	mergeRecord(r.usageCache, record)
+}
+
+// mergeRecord adds a record to the increments of its key.
+func mergeRecord(records map[string]*UsageRecord, record UsageRecord) {
+	hash := record.cacheKey()
+
+	if existing, ok := records[hash]; ok {
 		existing.Count += record.Count
+		if record.Timestamp.Before(existing.Timestamp) {
+			existing.Timestamp = record.Timestamp
+		}
 	} else {
-		record.Timestamp = time.Now()
-		r.usageCache[hash] = &record
+		records[hash] = &record
 	}
 }
 
 func (r *Repository) startBatchProcessor() {
-	batchTicker := time.NewTicker(5 * time.Second)
+	batchTicker := time.NewTicker(r.options.BatchInterval)
 	go func() {
-		for range batchTicker.C {
-			r.processBatch()
+		defer close(r.batchesDone)
+		defer batchTicker.Stop()
+
+		for {
+			select {
+			case <-batchTicker.C:
+				r.processBatch()
+			case <-r.stopBatches:
+				return
+			}
 		}
 	}()
 }
 
 func (r *Repository) processBatch() {
 	r.cacheMutex.Lock()
-	if len(r.usageCache) == 0 {
+	if batch, ok := r.takeBatch(); ok {
+		r.pending = append(r.pending, batch)
+	}
+
+	batches := r.pending
+	r.pending = nil
+	r.cacheMutex.Unlock()
+
+	// Failed batches are retried as they are rather than merged back into
+	// the buffer, as a failed insert may have stored some of their records
+	var failed []UsageBatch
+	for _, batch := range batches {
+		fmt.Printf("Ingesting %d usage records\n", len(batch.Records))
+
+		// A queued batch releases its journal file once it is stored
+		var err error
+		if r.queue != nil {
+			err = r.queue.Add(batch, 10)
+		} else {
+			err = r.storeBatch(batch)
+		}
+
+		if err != nil {
+			log.Printf("Failed to store usage batch %s, retrying with the next batch: %v", batch.ID, err)
+			failed = append(failed, batch)
+		}
+	}
+
+	if len(failed) > 0 {
+		r.cacheMutex.Lock()
+		r.pending = append(failed, r.pending...)
 		r.cacheMutex.Unlock()
-		return
+	}
+}
+
+// takeBatch takes the buffered increments as a batch. With a journal, the
+// batch is named after the journal file holding them, so replaying the
+// file after a crash stores them under the same ID. The caller holds
+// cacheMutex.
+func (r *Repository) takeBatch() (UsageBatch, bool) {
+	if len(r.usageCache) == 0 {
+		return UsageBatch{}, false
+	}
+
+	batch := UsageBatch{ID: newBatchID()}
+
+	if r.journal != nil {
+		path, err := r.journal.rotate()
+		if err != nil {
+			log.Printf("Failed to rotate usage journal: %v", err)
+		}
+
+		if path == "" && err != nil {
+			// The increments are still in the journal, so they stay in the
+			// buffer and are taken with the next rotation
+			return UsageBatch{}, false
+		}
+
+		if path != "" {
+			batch.ID = journalBatchID(path)
+			batch.JournalPath = path
+		}
 	}
 
 	// Copy records and clear cache
-	records := make([]*UsageRecord, 0, len(r.usageCache))
+	batch.Records = make([]*UsageRecord, 0, len(r.usageCache))
 	for _, record := range r.usageCache {
-		records = append(records, record)
+		batch.Records = append(batch.Records, record)
 	}
 	clear(r.usageCache)
-	r.cacheMutex.Unlock()
 
-	fmt.Printf("Ingesting %d usage records\n", len(records))
+	return batch, true
+}
 
+func (r *Repository) storeBatch(batch UsageBatch) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	if len(records) > 0 {
-		err := r.store.IngestUsage(ctx, records)
-		if err != nil {
-			log.Printf("Failed to insert usage records: %v", err)
-		}
+	if err := r.store.IngestUsage(ctx, batch); err != nil {
+		return err
 	}
+
+	if batch.JournalPath != "" {
+		releaseJournalFile(batch.JournalPath)
+	}
+
+	return nil
 }
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeStore struct {
+	mu      sync.Mutex
+	fail    bool
+	batches []UsageBatch
+}
+
+func (s *fakeStore) IngestUsage(ctx context.Context, batch UsageBatch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.batches = append(s.batches, batch)
+	if s.fail {
+		return errors.New("store unavailable")
+	}
+
+	return nil
+}
+
+func (s *fakeStore) GetUsageTimeline(ctx context.Context, opts TimelineOptions) ([]AggregationResult, error) {
+	return nil, nil
+}
+
+func newTestRepository(t *testing.T, store UsageStore, options RepositoryOptions) *Repository {
+	t.Helper()
+
+	options.BatchInterval = time.Hour
+	repo, err := NewRepository(store, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return repo
+}
+
+func TestProcessBatch_RetriesFailedBatchUnchanged(t *testing.T) {
+	store := &fakeStore{fail: true}
+	repo := newTestRepository(t, store, RepositoryOptions{})
+	defer repo.Stop()
+
+	repo.IngestUsage(UsageRecord{EventType: "call", OwnerID: "o", EntityID: "e", EntityType: "server", Count: 1})
+	repo.processBatch()
+
+	repo.IngestUsage(UsageRecord{EventType: "call", OwnerID: "o", EntityID: "e", EntityType: "server", Count: 2})
+	store.fail = false
+	repo.processBatch()
+
+	if len(store.batches) != 3 {
+		t.Fatalf("expected the failed batch to be retried before the new one, got %d attempts", len(store.batches))
+	}
+
+	failed, retried, next := store.batches[0], store.batches[1], store.batches[2]
+	if retried.ID != failed.ID || retried.Records[0].Count != 1 {
+		t.Errorf("expected the failed batch to be retried as it was, got %s with count %d", retried.ID, retried.Records[0].Count)
+	}
+	if next.ID == failed.ID || next.Records[0].Count != 2 {
+		t.Errorf("expected new increments in a new batch, got %s with count %d", next.ID, next.Records[0].Count)
+	}
+}
+
+func TestUsageBatch_RecordID(t *testing.T) {
+	a := &UsageRecord{EventType: "call", OwnerID: "o", EntityID: "a", EntityType: "server"}
+	b := &UsageRecord{EventType: "call", OwnerID: "o", EntityID: "b", EntityType: "server"}
+
+	batch := UsageBatch{ID: "batch-1"}
+	other := UsageBatch{ID: "batch-2"}
+
+	if batch.RecordID(a) != batch.RecordID(&UsageRecord{EventType: "call", OwnerID: "o", EntityID: "a", EntityType: "server", Count: 5}) {
+		t.Error("expected the ID to depend only on the batch and key")
+	}
+	if batch.RecordID(a) == batch.RecordID(b) {
+		t.Error("expected records with different keys to get different IDs")
+	}
+	if batch.RecordID(a) == other.RecordID(a) {
+		t.Error("expected records of different batches to get different IDs")
+	}
+}
+
+func TestJournal_ReplaysBatchUnderSameID(t *testing.T) {
+	dir := t.TempDir()
+	store := &fakeStore{}
+
+	repo := newTestRepository(t, store, RepositoryOptions{JournalDir: dir})
+	repo.IngestUsage(UsageRecord{EventType: "call", OwnerID: "o", EntityID: "a", EntityType: "server", Count: 1})
+	repo.IngestUsage(UsageRecord{EventType: "call", OwnerID: "o", EntityID: "a", EntityType: "server", Count: 2})
+	repo.IngestUsage(UsageRecord{EventType: "call", OwnerID: "o", EntityID: "b", EntityType: "server", Count: 4})
+
+	// The batch is handed off, but the process crashes before its journal
+	// file is released
+	repo.cacheMutex.Lock()
+	handedOff, ok := repo.takeBatch()
+	repo.journal.close()
+	repo.cacheMutex.Unlock()
+	if !ok || handedOff.JournalPath == "" {
+		t.Fatal("expected a batch backed by a journal file")
+	}
+
+	recovered := newTestRepository(t, store, RepositoryOptions{JournalDir: dir})
+	if len(recovered.pending) != 1 {
+		t.Fatalf("expected one recovered batch, got %d", len(recovered.pending))
+	}
+
+	replayed := recovered.pending[0]
+	if replayed.ID != handedOff.ID {
+		t.Fatalf("expected the batch to be replayed as %s, got %s", handedOff.ID, replayed.ID)
+	}
+
+	ids := func(batch UsageBatch) map[string]int64 {
+		result := make(map[string]int64)
+		for _, record := range batch.Records {
+			result[batch.RecordID(record)] = record.Count
+		}
+		return result
+	}
+
+	want, got := ids(handedOff), ids(replayed)
+	if len(want) != 2 || len(got) != len(want) {
+		t.Fatalf("expected the same records, got %v and %v", want, got)
+	}
+	for id, count := range want {
+		if got[id] != count {
+			t.Errorf("record %s: replayed count %d, want %d", id, got[id], count)
+		}
+	}
+
+	// Stored batches release their journal file
+	recovered.Stop()
+	if len(store.batches) != 1 {
+		t.Fatalf("expected the recovered batch to be stored, got %d batches", len(store.batches))
+	}
+
+	again := newTestRepository(t, store, RepositoryOptions{JournalDir: dir})
+	defer again.Stop()
+	if len(again.pending) != 0 {
+		t.Errorf("expected no batches after they were stored, got %d", len(again.pending))
+	}
+}
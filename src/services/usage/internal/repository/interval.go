@@ -8,6 +8,8 @@ const (
 	IntervalUnitHour   IntervalConfigUnit = "hour"
 	IntervalUnitMinute IntervalConfigUnit = "minute"
 	IntervalUnitDay    IntervalConfigUnit = "day"
+	IntervalUnitWeek   IntervalConfigUnit = "week"
+	IntervalUnitMonth  IntervalConfigUnit = "month"
 )
 
 type IntervalConfig struct {
@@ -15,26 +17,139 @@ type IntervalConfig struct {
 	Count int32
 }
 
-func AdjustTimeBoundaries(from, to time.Time, unit IntervalConfigUnit) (time.Time, time.Time) {
-	switch unit {
-	case IntervalUnitDay:
-		from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
-		to = time.Date(to.Year(), to.Month(), to.Day(), 23, 59, 59, 999999999, to.Location())
-	case IntervalUnitHour:
-		from = time.Date(from.Year(), from.Month(), from.Day(), from.Hour(), 0, 0, 0, from.Location())
-		to = time.Date(to.Year(), to.Month(), to.Day(), to.Hour(), 59, 59, 999999999, to.Location())
+func (i IntervalConfig) binSize() int {
+	if i.Count < 1 {
+		return 1
+	}
+
+	return int(i.Count)
+}
+
+// Intervals are counted from this date in the requested time zone, the
+// same as MongoDB's $dateTrunc does. Weeks start on Monday.
+var (
+	intervalReference     = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	intervalWeekReference = time.Date(2000, 1, 3, 0, 0, 0, 0, time.UTC)
+)
+
+// TruncateTime returns the start of the interval t is in, aligned to the
+// calendar in loc.
+func TruncateTime(t time.Time, interval IntervalConfig, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	binSize := interval.binSize()
+
+	// Wall clock time in loc, expressed in UTC to count units without
+	// offsets getting in the way
+	local := t.In(loc)
+	wall := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), 0, 0, time.UTC)
+	date := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	var start time.Time
+
+	switch interval.Unit {
+	case IntervalUnitMinute, IntervalUnitHour:
+		unit := time.Hour
+		if interval.Unit == IntervalUnitMinute {
+			unit = time.Minute
+		}
+
+		units := floorToBin(int(wall.Sub(intervalReference)/unit), binSize)
+		start = intervalReference.Add(time.Duration(units) * unit)
+
+	case IntervalUnitWeek:
+		days := int(date.Sub(intervalWeekReference) / (24 * time.Hour))
+		weeks := floorToBin(floorDiv(days, 7), binSize)
+		start = intervalWeekReference.AddDate(0, 0, weeks*7)
+
+	case IntervalUnitMonth:
+		months := floorToBin((local.Year()-2000)*12+int(local.Month())-1, binSize)
+		start = intervalReference.AddDate(0, months, 0)
+
+	default:
+		days := int(date.Sub(intervalReference) / (24 * time.Hour))
+		start = intervalReference.AddDate(0, 0, floorToBin(days, binSize))
+	}
+
+	return time.Date(start.Year(), start.Month(), start.Day(), start.Hour(), start.Minute(), 0, 0, loc)
+}
+
+// AddInterval returns the start of the interval after the one starting at
+// t. Days, weeks and months follow the calendar, so they can be shorter or
+// longer than their nominal length.
+func AddInterval(t time.Time, interval IntervalConfig) time.Time {
+	binSize := interval.binSize()
+
+	switch interval.Unit {
 	case IntervalUnitMinute:
-		from = time.Date(from.Year(), from.Month(), from.Day(), from.Hour(), from.Minute(), 0, 0, from.Location())
-		to = time.Date(to.Year(), to.Month(), to.Day(), to.Hour(), to.Minute(), 59, 999999999, to.Location())
+		return t.Add(time.Duration(binSize) * time.Minute)
+	case IntervalUnitHour:
+		return t.Add(time.Duration(binSize) * time.Hour)
+	case IntervalUnitWeek:
+		return t.AddDate(0, 0, 7*binSize)
+	case IntervalUnitMonth:
+		return t.AddDate(0, binSize, 0)
+	default:
+		return t.AddDate(0, 0, binSize)
+	}
+}
+
+// IntervalStarts returns the starts of all intervals that overlap
+// [from, to).
+func IntervalStarts(from, to time.Time, interval IntervalConfig, loc *time.Location) []time.Time {
+	var starts []time.Time
+
+	for ts := TruncateTime(from, interval, loc); ts.Before(to); ts = AddInterval(ts, interval) {
+		starts = append(starts, ts)
+	}
+
+	return starts
+}
+
+// AdjustTimeBoundaries widens [from, to) to whole intervals.
+func AdjustTimeBoundaries(from, to time.Time, interval IntervalConfig, loc *time.Location) (time.Time, time.Time) {
+	from = TruncateTime(from, interval, loc)
+
+	end := TruncateTime(to, interval, loc)
+	if end.Before(to) {
+		end = AddInterval(end, interval)
 	}
 
-	return from, to
+	return from, end
 }
 
+// CalculateIntervalMs returns the nominal length of an interval. Months
+// count as 30 days.
 func CalculateIntervalMs(interval IntervalConfig) int64 {
-	baseMs := int64(60 * 60 * 1000) // 1 hour in ms
-	if interval.Unit == "day" {
+	var baseMs int64
+
+	switch interval.Unit {
+	case IntervalUnitMinute:
+		baseMs = 60 * 1000 // 1 minute in ms
+	case IntervalUnitDay:
 		baseMs = 24 * 60 * 60 * 1000 // 1 day in ms
+	case IntervalUnitWeek:
+		baseMs = 7 * 24 * 60 * 60 * 1000 // 1 week in ms
+	case IntervalUnitMonth:
+		baseMs = 30 * 24 * 60 * 60 * 1000 // 30 days in ms
+	default:
+		baseMs = 60 * 60 * 1000 // 1 hour in ms
 	}
+
 	return baseMs * int64(interval.Count)
 }
+
+func floorDiv(a, b int) int {
+	res := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		res--
+	}
+
+	return res
+}
+
+func floorToBin(units, binSize int) int {
+	return floorDiv(units, binSize) * binSize
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateIntervalMs(t *testing.T) {
+	tests := []struct {
+		interval IntervalConfig
+		want     int64
+	}{
+		{IntervalConfig{Unit: IntervalUnitMinute, Count: 5}, 5 * 60 * 1000},
+		{IntervalConfig{Unit: IntervalUnitHour, Count: 2}, 2 * 60 * 60 * 1000},
+		{IntervalConfig{Unit: IntervalUnitDay, Count: 1}, 24 * 60 * 60 * 1000},
+		{IntervalConfig{Unit: IntervalUnitWeek, Count: 1}, 7 * 24 * 60 * 60 * 1000},
+	}
+
+	for _, test := range tests {
+		if got := CalculateIntervalMs(test.interval); got != test.want {
+			t.Errorf("CalculateIntervalMs(%+v) = %d, want %d", test.interval, got, test.want)
+		}
+	}
+}
+
+func TestTruncateTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	// A Wednesday, 00:30 in Berlin
+	ts := time.Date(2024, 3, 13, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		interval IntervalConfig
+		loc      *time.Location
+		want     time.Time
+	}{
+		{"minute", IntervalConfig{Unit: IntervalUnitMinute, Count: 15}, time.UTC, time.Date(2024, 3, 13, 23, 30, 0, 0, time.UTC)},
+		{"hour", IntervalConfig{Unit: IntervalUnitHour, Count: 1}, time.UTC, time.Date(2024, 3, 13, 23, 0, 0, 0, time.UTC)},
+		{"day utc", IntervalConfig{Unit: IntervalUnitDay, Count: 1}, time.UTC, time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{"day berlin", IntervalConfig{Unit: IntervalUnitDay, Count: 1}, berlin, time.Date(2024, 3, 14, 0, 0, 0, 0, berlin)},
+		{"week", IntervalConfig{Unit: IntervalUnitWeek, Count: 1}, time.UTC, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)},
+		{"month", IntervalConfig{Unit: IntervalUnitMonth, Count: 1}, time.UTC, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"quarter", IntervalConfig{Unit: IntervalUnitMonth, Count: 3}, time.UTC, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"nil location", IntervalConfig{Unit: IntervalUnitDay, Count: 1}, nil, time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		if got := TruncateTime(ts, test.interval, test.loc); !got.Equal(test.want) {
+			t.Errorf("%s: TruncateTime = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestIntervalStarts_FollowCalendar(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	// Spans the switch to daylight saving time on March 31st
+	from := time.Date(2024, 3, 30, 12, 0, 0, 0, berlin)
+	to := time.Date(2024, 4, 2, 0, 0, 0, 0, berlin)
+
+	starts := IntervalStarts(from, to, IntervalConfig{Unit: IntervalUnitDay, Count: 1}, berlin)
+	if len(starts) != 3 {
+		t.Fatalf("expected 3 days, got %v", starts)
+	}
+
+	for _, start := range starts {
+		local := start.In(berlin)
+		if local.Hour() != 0 || local.Minute() != 0 {
+			t.Errorf("day doesn't start at midnight: %v", local)
+		}
+	}
+
+	months := IntervalStarts(
+		time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		IntervalConfig{Unit: IntervalUnitMonth, Count: 1},
+		time.UTC,
+	)
+
+	want := []time.Time{
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if len(months) != len(want) {
+		t.Fatalf("expected %d months, got %v", len(want), months)
+	}
+	for i := range want {
+		if !months[i].Equal(want[i]) {
+			t.Errorf("month %d = %v, want %v", i, months[i], want[i])
+		}
+	}
+}
+
+func TestAdjustTimeBoundaries(t *testing.T) {
+	from := time.Date(2024, 3, 13, 10, 20, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 13, 12, 0, 0, 0, time.UTC)
+
+	gotFrom, gotTo := AdjustTimeBoundaries(from, to, IntervalConfig{Unit: IntervalUnitHour, Count: 1}, time.UTC)
+
+	if !gotFrom.Equal(time.Date(2024, 3, 13, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("from = %v", gotFrom)
+	}
+	if !gotTo.Equal(to) {
+		t.Errorf("to = %v, expected aligned end to stay", gotTo)
+	}
+}
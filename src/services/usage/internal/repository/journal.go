@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const journalFileName = "pending.log"
+
+// journal keeps the increments that are buffered in memory on disk, so
+// they survive a crash of the process. When a batch is taken from the
+// buffer the journal is rotated into a batch file, named after the batch,
+// which is removed once the batch is stored. A crash before that replays
+// the file as a batch of the same ID, which the store skips the records
+// of that it stored already, so increments are neither lost nor counted
+// twice.
+type journal struct {
+	dir  string
+	file *os.File
+}
+
+// openJournal opens the journal in dir and returns the batches that
+// weren't stored before the last shutdown.
+func openJournal(dir string) (*journal, []UsageBatch, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	j := &journal{dir: dir}
+
+	// The last journal becomes a batch file like the ones that weren't
+	// stored
+	if _, err := j.rotate(); err != nil {
+		return nil, nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "batch-*.log"))
+	if err != nil {
+		j.close()
+		return nil, nil, fmt.Errorf("failed to list journal files: %w", err)
+	}
+	sort.Strings(files)
+
+	var batches []UsageBatch
+	for _, path := range files {
+		records, err := readJournalFile(path)
+		if err != nil {
+			j.close()
+			return nil, nil, err
+		}
+
+		if len(records) == 0 {
+			releaseJournalFile(path)
+			continue
+		}
+
+		// Merged like the buffer, so records get the same IDs as when the
+		// batch was taken from it
+		merged := make(map[string]*UsageRecord, len(records))
+		for _, record := range records {
+			mergeRecord(merged, record)
+		}
+
+		batch := UsageBatch{
+			ID:          journalBatchID(path),
+			Records:     make([]*UsageRecord, 0, len(merged)),
+			JournalPath: path,
+		}
+		for _, record := range merged {
+			batch.Records = append(batch.Records, record)
+		}
+
+		batches = append(batches, batch)
+	}
+
+	return j, batches, nil
+}
+
+func journalBatchID(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), ".log")
+}
+
+func readJournalFile(path string) ([]UsageRecord, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	var records []UsageRecord
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(strings.TrimSpace(string(line))) > 0 {
+			var record UsageRecord
+			if jsonErr := json.Unmarshal(line, &record); jsonErr != nil {
+				// Only the last record can be torn by a crash
+				log.Printf("Ignoring invalid usage journal record in %s: %v", path, jsonErr)
+			} else {
+				records = append(records, record)
+			}
+		}
+
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read journal: %w", err)
+		}
+	}
+}
+
+func (j *journal) path() string {
+	return filepath.Join(j.dir, journalFileName)
+}
+
+func (j *journal) open() error {
+	file, err := os.OpenFile(j.path(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+
+	j.file = file
+	return nil
+}
+
+// append writes an increment to the journal. It isn't synced, so it
+// survives crashes of the process but not of the machine.
+func (j *journal) append(records ...UsageRecord) error {
+	if j.file == nil {
+		return fmt.Errorf("journal is closed")
+	}
+
+	var data []byte
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode journal record: %w", err)
+		}
+
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+
+	if _, err := j.file.Write(data); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+
+	return nil
+}
+
+// rotate moves the journal into a batch file and starts a new one. It
+// returns the file holding the increments since the last rotation, which
+// is removed with releaseJournalFile once they are stored, or an empty
+// path if there is no journal to rotate.
+func (j *journal) rotate() (string, error) {
+	if j.file != nil {
+		j.file.Close()
+		j.file = nil
+	}
+
+	random := make([]byte, 8)
+	rand.Read(random)
+
+	batchPath := filepath.Join(j.dir, fmt.Sprintf("batch-%020d-%s.log", time.Now().UnixNano(), hex.EncodeToString(random)))
+	err := os.Rename(j.path(), batchPath)
+	if errors.Is(err, os.ErrNotExist) {
+		batchPath = ""
+	} else if err != nil {
+		j.open()
+		return "", fmt.Errorf("failed to rotate journal: %w", err)
+	}
+
+	return batchPath, j.open()
+}
+
+func releaseJournalFile(path string) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Failed to remove usage journal batch %s: %v", path, err)
+	}
+}
+
+func (j *journal) close() error {
+	if j.file == nil {
+		return nil
+	}
+
+	err := j.file.Sync()
+	if closeErr := j.file.Close(); err == nil {
+		err = closeErr
+	}
+	j.file = nil
+
+	return err
+}
@@ -1,21 +1,115 @@
 package repository
 
-import "sync"
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+
+	memoryQueue "github.com/metorial/metorial/modules/memory-queue"
+)
+
+type RepositoryOptions struct {
+	// QueueDir keeps batches on disk until they are stored, retrying
+	// failed inserts. Otherwise batches are stored directly.
+	QueueDir string
+
+	// JournalDir keeps the increments buffered in memory on disk, so they
+	// are stored after a crash.
+	JournalDir string
+
+	// BatchInterval is how often buffered increments are stored.
+	BatchInterval time.Duration
+}
 
 type Repository struct {
 	usageCache map[string]*UsageRecord
 	cacheMutex sync.RWMutex
 
+	// batches that couldn't be stored or queued, retried with the next
+	// batch, guarded by cacheMutex
+	pending []UsageBatch
+
 	store UsageStore
+
+	queue   *memoryQueue.DurableJobQueue[UsageBatch]
+	journal *journal
+
+	options RepositoryOptions
+
+	stopBatches chan struct{}
+	batchesDone chan struct{}
+	stopOnce    sync.Once
 }
 
-func NewRepository(store UsageStore) *Repository {
+func NewRepository(store UsageStore, options RepositoryOptions) (*Repository, error) {
+	if options.BatchInterval <= 0 {
+		options.BatchInterval = 5 * time.Second
+	}
+
 	res := &Repository{
-		usageCache: make(map[string]*UsageRecord),
-		store:      store,
+		usageCache:  make(map[string]*UsageRecord),
+		store:       store,
+		options:     options,
+		stopBatches: make(chan struct{}),
+		batchesDone: make(chan struct{}),
+	}
+
+	if options.JournalDir != "" {
+		journal, batches, err := openJournal(options.JournalDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open usage journal: %w", err)
+		}
+
+		// Batches that were queued before the crash may be stored twice,
+		// the store skips the records it has already
+		res.pending = batches
+
+		if len(batches) > 0 {
+			log.Printf("Recovered %d usage batches from journal", len(batches))
+		}
+
+		res.journal = journal
+	}
+
+	if options.QueueDir != "" {
+		queue, err := memoryQueue.NewDurableJobQueue(filepath.Join(options.QueueDir, "usage.log"), res.storeBatch, memoryQueue.DefaultDurableOptions())
+		if err != nil {
+			if res.journal != nil {
+				res.journal.close()
+			}
+
+			return nil, fmt.Errorf("failed to open usage queue: %w", err)
+		}
+
+		res.queue = queue
 	}
 
 	res.startBatchProcessor()
 
-	return res
+	return res, nil
+}
+
+// Stop stores the buffered increments and stops the repository. Batches
+// that are queued but not stored yet stay in the queue for the next start.
+func (r *Repository) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopBatches)
+		<-r.batchesDone
+
+		r.processBatch()
+
+		if r.queue != nil {
+			r.queue.Stop()
+		}
+
+		if r.journal != nil {
+			r.cacheMutex.Lock()
+			if err := r.journal.close(); err != nil {
+				log.Printf("Failed to close usage journal: %v", err)
+			}
+			r.cacheMutex.Unlock()
+		}
+	})
 }
@@ -3,6 +3,8 @@ package repository
 import "context"
 
 type UsageStore interface {
-	IngestUsage(ctx context.Context, records []*UsageRecord) error
+	// IngestUsage stores the records of a batch. Storing a batch that was
+	// stored before, in full or in part, must not count its records twice.
+	IngestUsage(ctx context.Context, batch UsageBatch) error
 	GetUsageTimeline(ctx context.Context, opts TimelineOptions) ([]AggregationResult, error)
 }
@@ -22,6 +22,11 @@ type TimelineOptions struct {
 	From        time.Time
 	To          time.Time
 	Interval    IntervalConfig
+
+	// Location is the time zone intervals are aligned in, UTC if nil. It
+	// must be loaded by name, e.g. with time.LoadLocation, so the store
+	// can align in the same zone.
+	Location *time.Location
 }
 
 type TimelineEntry struct {
@@ -42,14 +47,14 @@ func (r *Repository) GetUsageTimeline(ctx context.Context, opts TimelineOptions)
 		return nil, fmt.Errorf("failed to get usage timeline: %w", err)
 	}
 
-	from, to := AdjustTimeBoundaries(opts.From, opts.To, opts.Interval.Unit)
-	intervalMs := CalculateIntervalMs(opts.Interval)
-	timeline := buildTimeline(result, opts, from, to, intervalMs)
+	from, to := AdjustTimeBoundaries(opts.From, opts.To, opts.Interval, opts.Location)
+	intervalStarts := IntervalStarts(from, to, opts.Interval, opts.Location)
+	timeline := buildTimeline(result, opts, intervalStarts)
 
 	return timeline, nil
 }
 
-func buildTimeline(results []AggregationResult, opts TimelineOptions, from, to time.Time, intervalMs int64) []TimelineSeries {
+func buildTimeline(results []AggregationResult, opts TimelineOptions, intervalStarts []time.Time) []TimelineSeries {
 	timelineMap := make(map[string]*TimelineSeries)
 
 	// Initialize with empty series for requested entity IDs if no results
@@ -91,7 +96,7 @@ func buildTimeline(results []AggregationResult, opts TimelineOptions, from, to t
 		})
 	}
 
-	fillMissingIntervals(timelineMap, from, to, intervalMs)
+	fillMissingIntervals(timelineMap, intervalStarts)
 
 	// Convert map to slice and sort entries
 	timeline := make([]TimelineSeries, 0, len(timelineMap))
@@ -125,16 +130,7 @@ func createEmptyTimeline(opts TimelineOptions) []TimelineSeries {
 	return timeline
 }
 
-func fillMissingIntervals(timelineMap map[string]*TimelineSeries, from, to time.Time, intervalMs int64) {
-	var timestamps []time.Time
-	currentTs := from.UnixMilli()
-	toMs := to.UnixMilli()
-
-	for currentTs < toMs {
-		timestamps = append(timestamps, time.UnixMilli(currentTs))
-		currentTs += intervalMs
-	}
-
+func fillMissingIntervals(timelineMap map[string]*TimelineSeries, timestamps []time.Time) {
 	// Fill missing intervals for each series
 	for _, series := range timelineMap {
 		existingTimes := make(map[int64]bool)
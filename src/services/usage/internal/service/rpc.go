@@ -56,6 +56,15 @@ func (s *UsageService) GetUsageTimeline(ctx context.Context, req *rpc.GetUsageTi
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	location := time.UTC
+	if req.Timezone != "" {
+		var err error
+		location, err = time.LoadLocation(req.Timezone)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown timezone %q", req.Timezone)
+		}
+	}
+
 	events := make([]*rpc.TimelineEvent, 0, len(req.EventTypes))
 
 	for _, eventType := range req.EventTypes {
@@ -70,6 +79,7 @@ func (s *UsageService) GetUsageTimeline(ctx context.Context, req *rpc.GetUsageTi
 				Unit:  convertIntervalUnit(req.Interval.Unit),
 				Count: req.Interval.Count,
 			},
+			Location: location,
 		}
 
 		for _, owner := range req.Owners {
@@ -185,6 +195,10 @@ func convertIntervalUnit(unit rpc.IntervalUnit) repository.IntervalConfigUnit {
 		return repository.IntervalUnitMinute
 	case rpc.IntervalUnit_interval_unit_day:
 		return repository.IntervalUnitDay
+	case rpc.IntervalUnit_interval_unit_week:
+		return repository.IntervalUnitWeek
+	case rpc.IntervalUnit_interval_unit_month:
+		return repository.IntervalUnitMonth
 	default:
 		return repository.IntervalUnitHour // Default to hour if unspecified
 	}
@@ -8,11 +8,13 @@ import (
 	grpcUtil "github.com/metorial/metorial/mcp-engine/pkg/grpcUtil"
 	"github.com/metorial/metorial/services/usage/gen/rpc"
 	"github.com/metorial/metorial/services/usage/internal/repository"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
 type Service struct {
 	repository *repository.Repository
+	grpcServer *grpc.Server
 }
 
 func NewService(repo *repository.Repository) *Service {
@@ -26,6 +28,7 @@ func (s *Service) Start(rpcAddress string) error {
 
 	// gRPC Server
 	grpcServer := grpcUtil.NewGrpcServer("usage")
+	s.grpcServer = grpcServer
 	rpc.RegisterUsageServiceServer(grpcServer, rpcService)
 
 	reflection.Register(grpcServer)
@@ -47,5 +50,12 @@ func (s *Service) Start(rpcAddress string) error {
 }
 
 func (s *Service) Stop() error {
+	// Stop taking usage before the buffered usage is flushed
+	if s.grpcServer != nil {
+		s.grpcServer.GracefulStop()
+	}
+
+	s.repository.Stop()
+
 	return nil
 }